@@ -17,37 +17,206 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"go.uber.org/zap/zapcore"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"github.com/vmware-tanzu/cartographer/pkg/gitops"
+	"github.com/vmware-tanzu/cartographer/pkg/logging"
+	"github.com/vmware-tanzu/cartographer/pkg/registrar"
 	"github.com/vmware-tanzu/cartographer/pkg/root"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
 
 var devMode bool
+var logSettings logging.Settings
 var port int
 var certDir string
+var interceptorURL string
+var watchedKinds string
+var coalesceWindow time.Duration
+var resyncIntervals registrar.ResyncIntervals
+var maxConcurrentReconciles registrar.MaxConcurrentReconciles
+var clusterContext templates.ClusterContext
+var supportBundleAddress string
+var metricsAddress string
+var healthProbeAddress string
+var profilingAddress string
+var tracesEndpoint string
+var auditLog string
+var fieldManager string
+var forceConflicts bool
+var serverSideApply bool
+var dryRunFirst bool
+var validateSchemas bool
+var reviewAccessFirst bool
+var templateSigningKeys string
+var rejectMissingTemplates bool
+var workloadServiceAccountName string
+var workloadLabels string
+var impersonateWorkloadServiceAccounts bool
+var configFile string
+var kubeAPIQPS float64
+var kubeAPIBurst int
+var requestTimeout time.Duration
+var leaderElection root.LeaderElectionSettings
 
 func init() {
 	flag.IntVar(&port, "Port", 9443, "Webhook server Port")
 	flag.StringVar(&certDir, "cert-dir", "", "Webhook server tls dir")
 	flag.BoolVar(&devMode, "dev", false, "Human readable logs")
+	flag.Var(&logSettings.Levels, "log-levels", "Comma separated subsystem=level of the verbosity of the logs of the realizer, repository, webhook and selectors subsystems, e.g. realizer=2,repository=1 (default: that of the other logs)")
+	flag.BoolVar(&logSettings.StampedObjects, "debug-stamped-objects", false, "Log the content of every object stamped, with the data of secrets redacted")
+	flag.StringVar(&interceptorURL, "interceptor-url", "", "URL of a webhook invoked before and after submitting stamped objects")
+	flag.StringVar(&watchedKinds, "watched-kinds", "", "Comma separated Kind.group of the stamped objects this instance watches, e.g. TaskRun.tekton.dev,Deployment.apps (default: every kind)")
+	flag.DurationVar(&coalesceWindow, "coalesce-window", 0, "Delay after an update to a stamped object during which further updates cause no extra reconcile of its owner, e.g. 2s (default: no delay)")
+	flag.DurationVar(&resyncIntervals.Workload, "workload-resync-interval", 0, "How often a workload is reconciled again when nothing about it changed, unless it sets the carto.run/resync-interval annotation (default: 5s)")
+	flag.DurationVar(&resyncIntervals.SupplyChain, "supply-chain-resync-interval", 0, "How often a supply chain is reconciled again when nothing about it changed (default: 5s)")
+	flag.DurationVar(&resyncIntervals.Pipeline, "pipeline-resync-interval", 0, "How often a pipeline is reconciled again when nothing about it changed (default: only on changes)")
+	flag.IntVar(&maxConcurrentReconciles.Workload, "workload-max-concurrent-reconciles", 1, "How many workloads are reconciled at once")
+	flag.IntVar(&maxConcurrentReconciles.SupplyChain, "supply-chain-max-concurrent-reconciles", 1, "How many supply chains are reconciled at once")
+	flag.IntVar(&maxConcurrentReconciles.Pipeline, "pipeline-max-concurrent-reconciles", 1, "How many pipelines are reconciled at once")
+	flag.StringVar(&clusterContext.Name, "cluster-name", "", "Name of the cluster, available to templates as $(clusterContext.name)$")
+	flag.StringVar(&clusterContext.Region, "cluster-region", "", "Region of the cluster, available to templates as $(clusterContext.region)$")
+	flag.StringVar(&clusterContext.IngressDomain, "cluster-ingress-domain", "", "Ingress domain of the cluster, available to templates as $(clusterContext.ingressDomain)$")
+	flag.StringVar(&clusterContext.Registry, "cluster-registry", "", "Image registry of the cluster, available to templates as $(clusterContext.registry)$")
+	flag.StringVar(&supportBundleAddress, "support-bundle-address", "", "Address of the endpoint serving support bundles of workloads, e.g. 127.0.0.1:8082 (default: disabled)")
+	flag.StringVar(&metricsAddress, "metrics-address", "", "Address of the endpoint serving Prometheus metrics, e.g. :8080 (default: disabled)")
+	flag.StringVar(&healthProbeAddress, "health-probe-address", "", "Address of the endpoint serving the /healthz and /readyz probes, e.g. :8081 (default: disabled)")
+	flag.StringVar(&profilingAddress, "pprof-address", "", "Address of the endpoint serving pprof profiles under /debug/pprof/, e.g. 127.0.0.1:6060 (default: disabled)")
+	flag.StringVar(&auditLog, "audit-log", "", "File every create and update of a stamped object is appended to as a line of JSON, or - for stdout (default: disabled)")
+	flag.StringVar(&tracesEndpoint, "otlp-traces-endpoint", "", "OTLP/HTTP endpoint of the OpenTelemetry collector traces of reconciles are exported to, e.g. http://otel-collector:4318 (default: disabled)")
+	flag.StringVar(&fieldManager, "field-manager", "cartographer", "Field manager stamped objects are applied under with server-side apply")
+	flag.BoolVar(&forceConflicts, "force-conflicts", true, "Take over the fields of stamped objects that another field manager owns, rather than failing to apply them")
+	flag.BoolVar(&serverSideApply, "server-side-apply", true, "Update stamped objects with server-side apply, or else with a three-way merge of their last applied configuration")
+	flag.BoolVar(&dryRunFirst, "dry-run-first", false, "Submit each stamped object with a dry run before writing it, so that an object the API server rejects is not written")
+	flag.BoolVar(&validateSchemas, "validate-schemas", false, "Validate each stamped object against the OpenAPI schema of its kind before submitting it")
+	flag.BoolVar(&reviewAccessFirst, "review-access-first", false, "Ask the API server whether each stamped object may be created or patched before writing it, reporting a missing permission as PermissionDenied")
+	flag.StringVar(&templateSigningKeys, "template-signing-keys", "", "Comma separated files of the PEM encoded public keys, e.g. of cosign, that templates must be signed with in the carto.run/signature annotation before they are stamped (default: templates are not verified)")
+	flag.BoolVar(&rejectMissingTemplates, "reject-missing-templates", false, "Reject supply chains and pipelines referring to templates that do not exist, rather than admitting them with a warning")
+	flag.StringVar(&workloadServiceAccountName, "workload-service-account-name", "", "Service account name of the workloads that do not set one (default: none)")
+	flag.StringVar(&workloadLabels, "workload-labels", "", "Comma separated key=value labels added to the workloads that do not set them, e.g. team=platform (default: none)")
+	flag.BoolVar(&impersonateWorkloadServiceAccounts, "impersonate-workload-service-accounts", false, "Write the objects stamped for a workload as its service account, or the default service account of its namespace, rather than as the controller")
+	flag.StringVar(&configFile, "config", "", "File of client settings, with the qps, burst and requestTimeout fields, which the flags below override")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", 0, "Requests per second to the API server (default: the client's default)")
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", 0, "Requests to the API server allowed above --kube-api-qps for a moment (default: the client's default)")
+	flag.DurationVar(&requestTimeout, "request-timeout", 0, "Time after which a request to the API server is given up, e.g. 30s (default: no timeout)")
+	flag.BoolVar(&leaderElection.Enabled, "leader-elect", true, "Elect a leader among the replicas of the controller, so that only one of them reconciles at a time")
+	flag.StringVar(&leaderElection.Namespace, "leader-elect-namespace", "", "Namespace of the lease the leader is elected with (default: the namespace the controller runs in)")
+	flag.DurationVar(&leaderElection.LeaseDuration, "leader-elect-lease-duration", 0, "How long the other replicas wait before taking over from a leader that stopped renewing its lease (default: 15s)")
+	flag.DurationVar(&leaderElection.RenewDeadline, "leader-elect-renew-deadline", 0, "How long the leader tries to renew its lease before giving up leadership, shorter than the lease duration (default: 10s)")
 	flag.Parse()
 }
 
 func main() {
+	// git runs the controller to answer its prompts for the credentials of
+	// the repositories stamped objects are committed to.
+	if gitops.AskPass(os.Args, os.Stdout) {
+		return
+	}
+
+	if err := leaderElection.Validate(); err != nil {
+		fmt.Fprintln(flag.CommandLine.Output(), err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	defer cancel()
 
+	clientSettings, err := readClientSettings()
+	if err != nil {
+		panic(err)
+	}
+
+	if devMode {
+		logSettings.Level = 1
+	}
+
+	defaultLabels, err := labels.ConvertSelectorToLabelsMap(workloadLabels)
+	if err != nil {
+		panic(fmt.Errorf("workload labels: %w", err))
+	}
+
 	cmd := root.Command{
-		Port:    port,
-		CertDir: certDir,
-		Context: ctx,
-		Logger:  zap.New(zap.UseDevMode(devMode)),
+		Port:           port,
+		CertDir:        certDir,
+		Context:        ctx,
+		Logger:         zap.New(zap.UseDevMode(devMode), zap.Level(zapcore.Level(-logSettings.MaxLevel()))),
+		Logging:        logSettings,
+		InterceptorURL: interceptorURL,
+		WatchedKinds:   splitList(watchedKinds),
+		CoalesceWindow: coalesceWindow,
+		ClusterContext: clusterContext,
+
+		ResyncIntervals:         resyncIntervals,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		SupportBundleAddress:    supportBundleAddress,
+		MetricsAddress:          metricsAddress,
+		HealthProbeAddress:      healthProbeAddress,
+		ProfilingAddress:        profilingAddress,
+		TracesEndpoint:          tracesEndpoint,
+		AuditLog:                auditLog,
+		FieldManager:            fieldManager,
+		LeaveConflicts:          !forceConflicts,
+		ThreeWayMerge:           !serverSideApply,
+		DryRunFirst:             dryRunFirst,
+		ValidateSchemas:         validateSchemas,
+		ReviewAccessFirst:       reviewAccessFirst,
+		TemplateSigningKeys:     splitList(templateSigningKeys),
+		RejectMissingTemplates:  rejectMissingTemplates,
+		Client:                  clientSettings,
+		LeaderElection:          leaderElection,
+		WorkloadDefaults: root.WorkloadDefaults{
+			ServiceAccountName: workloadServiceAccountName,
+			Labels:             defaultLabels,
+		},
+		ImpersonateWorkloadServiceAccounts: impersonateWorkloadServiceAccounts,
 	}
 
 	if err := cmd.Execute(); err != nil {
 		panic(err)
 	}
 }
+
+func splitList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// readClientSettings reads the --config file, if any, and overrides its
+// settings with the flags that were set.
+func readClientSettings() (root.ClientSettings, error) {
+	var settings root.ClientSettings
+	if configFile != "" {
+		var err error
+		if settings, err = root.ReadClientSettings(configFile); err != nil {
+			return settings, fmt.Errorf("read client settings: %w", err)
+		}
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "kube-api-qps":
+			settings.QPS = float32(kubeAPIQPS)
+		case "kube-api-burst":
+			settings.Burst = kubeAPIBurst
+		case "request-timeout":
+			settings.RequestTimeout = metav1.Duration{Duration: requestTimeout}
+		}
+	})
+	return settings, nil
+}
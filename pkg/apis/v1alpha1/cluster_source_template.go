@@ -19,6 +19,8 @@
 package v1alpha1
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -35,10 +37,39 @@ type ClusterSourceTemplate struct {
 	Status            SourceTemplateStatus `json:"status,omitempty"`
 }
 
+const (
+	MavenArtifactSourceType = "maven"
+	NpmArtifactSourceType   = "npm"
+)
+
 type SourceTemplateSpec struct {
 	TemplateSpec `json:",inline"`
-	URLPath      string `json:"urlPath"`
-	RevisionPath string `json:"revisionPath"`
+	URLPath      string `json:"urlPath,omitempty"`
+	RevisionPath string `json:"revisionPath,omitempty"`
+	// Artifact provides the source from a package registry rather than from
+	// a stamped object. It is an alternative to template, ytt, urlPath and
+	// revisionPath.
+	Artifact *ArtifactSource `json:"artifact,omitempty"`
+}
+
+// ArtifactSource polls a Maven or npm registry for a published artifact,
+// emitting the url of the artifact and its version as the revision.
+type ArtifactSource struct {
+	// +kubebuilder:validation:Enum=maven;npm
+	Type string `json:"type"`
+	// Registry is the base url of the registry, for instance
+	// https://repo1.maven.org/maven2 or https://registry.npmjs.org
+	Registry string `json:"registry"`
+	// Name is the groupId:artifactId of a Maven artifact, or the name of an
+	// npm package. Like a template, it may refer to the workload and params.
+	Name string `json:"name"`
+	// Version pins the artifact to a version. When omitted, the latest
+	// release is used. Like a template, it may refer to the workload and
+	// params.
+	Version string `json:"version,omitempty"`
+	// PollInterval is how long a resolved version is reused before the
+	// registry is polled again. Defaults to one minute.
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
 }
 
 type SourceTemplateStatus struct {
@@ -47,17 +78,34 @@ type SourceTemplateStatus struct {
 var _ webhook.Validator = &ClusterSourceTemplate{}
 
 func (c *ClusterSourceTemplate) ValidateCreate() error {
-	return c.Spec.TemplateSpec.validate()
+	return c.Spec.validate()
 }
 
 func (c *ClusterSourceTemplate) ValidateUpdate(_ runtime.Object) error {
-	return c.Spec.TemplateSpec.validate()
+	return c.Spec.validate()
 }
 
 func (c *ClusterSourceTemplate) ValidateDelete() error {
 	return nil
 }
 
+func (s *SourceTemplateSpec) validate() error {
+	if s.Artifact == nil {
+		if err := s.TemplateSpec.validate(); err != nil {
+			return err
+		}
+		if s.URLPath == "" || s.RevisionPath == "" {
+			return fmt.Errorf("invalid template: must specify urlPath and revisionPath")
+		}
+		return nil
+	}
+
+	if s.Template != nil || s.Ytt != "" || s.URLPath != "" || s.RevisionPath != "" || s.HealthRule != nil {
+		return fmt.Errorf("invalid template: artifact may not be combined with template, ytt, urlPath, revisionPath or healthRule")
+	}
+	return nil
+}
+
 // +kubebuilder:object:root=true
 
 type ClusterSourceTemplateList struct {
@@ -37,6 +37,10 @@ var _ = Describe("ClusterSourceTemplate", func() {
 					Name:      "some-template",
 					Namespace: "default",
 				},
+				Spec: v1alpha1.SourceTemplateSpec{
+					URLPath:      "status.url",
+					RevisionPath: "status.revision",
+				},
 			}
 		})
 
@@ -140,6 +144,41 @@ var _ = Describe("ClusterSourceTemplate", func() {
 			})
 		})
 
+		Describe("artifact mode", func() {
+			BeforeEach(func() {
+				template.Spec = v1alpha1.SourceTemplateSpec{
+					Artifact: &v1alpha1.ArtifactSource{
+						Type:     "maven",
+						Registry: "https://repo1.maven.org/maven2",
+						Name:     "org.springframework:spring-petclinic",
+					},
+				}
+			})
+
+			It("succeeds without a template or paths", func() {
+				Expect(template.ValidateCreate()).To(Succeed())
+			})
+
+			It("returns an error when combined with a template", func() {
+				template.Spec.Ytt = "some: ytt"
+
+				Expect(template.ValidateUpdate(nil)).
+					To(MatchError("invalid template: artifact may not be combined with template, ytt, urlPath, revisionPath or healthRule"))
+			})
+		})
+
+		Context("template does not set the output paths", func() {
+			BeforeEach(func() {
+				template.Spec.Ytt = "some: ytt"
+				template.Spec.URLPath = ""
+			})
+
+			It("returns an error", func() {
+				Expect(template.ValidateCreate()).
+					To(MatchError("invalid template: must specify urlPath and revisionPath"))
+			})
+		})
+
 		Context("#Delete", func() {
 			Context("Any template", func() {
 				var anyTemplate *v1alpha1.ClusterSourceTemplate
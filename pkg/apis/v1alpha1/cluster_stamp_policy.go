@@ -0,0 +1,126 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +versionName=v1alpha1
+// +groupName=carto.run
+// +kubebuilder:object:generate=true
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// AnyKind allows every kind of a group.
+const AnyKind = "*"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// ClusterStampPolicy lists the kinds templates may stamp, in every
+// namespace or in the namespaces it names. While no policy applies to a
+// namespace, any kind may be stamped in it; once one does, only the kinds
+// one of the policies that apply allows may be.
+type ClusterStampPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              StampPolicySpec `json:"spec"`
+}
+
+type StampPolicySpec struct {
+	// Namespaces the policy applies to, those of the stamped objects or,
+	// for a cluster-scoped object, of its owner. When empty, the policy
+	// applies to every namespace.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// AllowedKinds are the kinds templates may stamp.
+	// +kubebuilder:validation:MinItems=1
+	AllowedKinds []AllowedKind `json:"allowedKinds"`
+}
+
+type AllowedKind struct {
+	// Group of the kind, empty for the core group.
+	Group string `json:"group,omitempty"`
+	// Kind, or * for every kind of the group.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+}
+
+// AppliesTo reports whether the policy applies to the namespace. Every
+// policy applies to the empty namespace, which stands for any namespace.
+func (p *ClusterStampPolicy) AppliesTo(namespace string) bool {
+	if len(p.Spec.Namespaces) == 0 || namespace == "" {
+		return true
+	}
+	for _, candidate := range p.Spec.Namespaces {
+		if candidate == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the policy allows the kind.
+func (p *ClusterStampPolicy) Allows(kind schema.GroupKind) bool {
+	for _, allowed := range p.Spec.AllowedKinds {
+		if allowed.Group == kind.Group && (allowed.Kind == AnyKind || allowed.Kind == kind.Kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckStampPolicies fails when the policies that apply to the namespace do
+// not allow the kind to be stamped in it, naming them. An empty namespace
+// stands for any namespace, as for a cluster template, which may be stamped
+// for workloads of every namespace.
+func CheckStampPolicies(policies []ClusterStampPolicy, namespace string, kind schema.GroupKind) error {
+	var applied []string
+	for i := range policies {
+		if !policies[i].AppliesTo(namespace) {
+			continue
+		}
+		if policies[i].Allows(kind) {
+			return nil
+		}
+		applied = append(applied, fmt.Sprintf("'%s'", policies[i].Name))
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	where := "any namespace"
+	if namespace != "" {
+		where = fmt.Sprintf("namespace '%s'", namespace)
+	}
+	return fmt.Errorf("kind %s may not be stamped in %s: not allowed by ClusterStampPolicy %s", kind, where, strings.Join(applied, ", "))
+}
+
+// +kubebuilder:object:root=true
+
+type ClusterStampPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterStampPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&ClusterStampPolicy{},
+		&ClusterStampPolicyList{},
+	)
+}
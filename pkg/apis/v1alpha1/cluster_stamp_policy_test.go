@@ -0,0 +1,71 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+var _ = Describe("CheckStampPolicies", func() {
+	var policies []v1alpha1.ClusterStampPolicy
+
+	deployment := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	clusterRoleBinding := schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}
+
+	BeforeEach(func() {
+		policies = []v1alpha1.ClusterStampPolicy{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "everywhere"},
+				Spec: v1alpha1.StampPolicySpec{
+					AllowedKinds: []v1alpha1.AllowedKind{{Group: "apps", Kind: v1alpha1.AnyKind}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+				Spec: v1alpha1.StampPolicySpec{
+					Namespaces:   []string{"platform"},
+					AllowedKinds: []v1alpha1.AllowedKind{{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}},
+				},
+			},
+		}
+	})
+
+	It("allows every kind while no policy applies", func() {
+		Expect(v1alpha1.CheckStampPolicies(nil, "dev", clusterRoleBinding)).To(Succeed())
+	})
+
+	It("allows the kinds of a group a policy allows with a wildcard", func() {
+		Expect(v1alpha1.CheckStampPolicies(policies, "dev", deployment)).To(Succeed())
+	})
+
+	It("allows the kinds a policy allows only in its namespaces", func() {
+		Expect(v1alpha1.CheckStampPolicies(policies, "platform", clusterRoleBinding)).To(Succeed())
+		Expect(v1alpha1.CheckStampPolicies(policies, "dev", clusterRoleBinding)).To(MatchError(
+			"kind ClusterRoleBinding.rbac.authorization.k8s.io may not be stamped in namespace 'dev': not allowed by ClusterStampPolicy 'everywhere'",
+		))
+	})
+
+	It("allows in any namespace the kinds some policy allows", func() {
+		Expect(v1alpha1.CheckStampPolicies(policies, "", clusterRoleBinding)).To(Succeed())
+		Expect(v1alpha1.CheckStampPolicies(policies, "", schema.GroupKind{Kind: "Secret"})).To(MatchError(
+			"kind Secret may not be stamped in any namespace: not allowed by ClusterStampPolicy 'everywhere', 'platform'",
+		))
+	})
+})
@@ -19,22 +19,30 @@
 package v1alpha1
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
-	SupplyChainReady          = "Ready"
-	SupplyChainTemplatesReady = "TemplatesReady"
+	SupplyChainReady             = "Ready"
+	SupplyChainTemplatesReady    = "TemplatesReady"
+	SupplyChainExtensionResolved = "ExtensionResolved"
 )
 
 const (
-	ReadyTemplatesReadyReason    = "Ready"
-	NotFoundTemplatesReadyReason = "TemplatesNotFound"
+	ReadyTemplatesReadyReason       = "Ready"
+	NotFoundTemplatesReadyReason    = "TemplatesNotFound"
+	ResolvedExtensionResolvedReason = "ExtensionResolved"
+	InvalidExtensionResolvedReason  = "InvalidExtension"
 )
 
 // +kubebuilder:object:root=true
@@ -49,6 +57,13 @@ type ClusterSupplyChain struct {
 }
 
 func (c *ClusterSupplyChain) validateNewState() error {
+	if _, err := c.Spec.LabelSelector(); err != nil {
+		return fmt.Errorf("invalid selector for clustersupplychain '%s': %w", c.Name, err)
+	}
+	if err := validateFieldRequirements(c.Spec.SelectorMatchFields); err != nil {
+		return fmt.Errorf("invalid selector for clustersupplychain '%s': %w", c.Name, err)
+	}
+
 	names := make(map[string]bool)
 
 	for _, component := range c.Spec.Components {
@@ -62,6 +77,83 @@ func (c *ClusterSupplyChain) validateNewState() error {
 		names[component.Name] = true
 	}
 
+	for _, component := range c.Spec.Components {
+		if err := component.TemplateRef.Validate(); err != nil {
+			return fmt.Errorf(
+				"invalid template reference for component '%s': %w",
+				component.Name,
+				err,
+			)
+		}
+		if err := component.Hooks.validate(); err != nil {
+			return fmt.Errorf(
+				"invalid hooks for component '%s': %w",
+				component.Name,
+				err,
+			)
+		}
+		if component.GitOps != nil && component.OCIArtifact != nil {
+			return fmt.Errorf(
+				"component '%s' may write its object to git or publish it as an OCI artifact, not both",
+				component.Name,
+			)
+		}
+		if c.Namespace != "" && component.Namespace != "" && component.Namespace != c.Namespace {
+			return fmt.Errorf(
+				"component '%s' may not stamp its object outside the namespace of the supply chain, '%s'",
+				component.Name,
+				c.Namespace,
+			)
+		}
+		if c.Namespace == "" && component.Namespace != "" && !strings.Contains(component.Namespace, "$(") && !c.Spec.AllowsTargetNamespace(component.Namespace) {
+			return fmt.Errorf(
+				"component '%s' stamps its object into namespace '%s', which is not one of the allowedTargetNamespaces",
+				component.Name,
+				component.Namespace,
+			)
+		}
+		if component.writesElsewhere() && (component.Adopt || component.ReadinessGate) {
+			return fmt.Errorf(
+				"component '%s' writes its object outside the cluster, so it may not adopt it or gate on its health",
+				component.Name,
+			)
+		}
+	}
+
+	if ref := c.Spec.ServiceAccountRef; c.Namespace != "" && ref != nil && ref.Namespace != "" && ref.Namespace != c.Namespace {
+		return fmt.Errorf(
+			"service account '%s/%s' is not in the namespace of the supply chain, '%s'",
+			ref.Namespace,
+			ref.Name,
+			c.Namespace,
+		)
+	}
+
+	for _, declaration := range c.Spec.WorkloadParams {
+		if declaration.Default != nil && declaration.Type != "" && !hasJSONType(*declaration.Default, declaration.Type) {
+			return fmt.Errorf(
+				"default of workload param '%s' must be of type %s in clustersupplychain '%s'",
+				declaration.Name,
+				declaration.Type,
+				c.Name,
+			)
+		}
+	}
+
+	// The components of an extending chain may consume and depend on the
+	// components of its base, so they are checked once the chain is
+	// resolved.
+	if c.Spec.Extends != nil {
+		if c.Spec.Extends.Name == "" {
+			return fmt.Errorf("clustersupplychain '%s' must name the supply chain it extends", c.Name)
+		}
+		return nil
+	}
+
+	return c.validateComponents()
+}
+
+func (c *ClusterSupplyChain) validateComponents() error {
 	for _, component := range c.Spec.Components {
 		if err := c.validateComponentRefs(component.Sources, "ClusterSourceTemplate"); err != nil {
 			return fmt.Errorf(
@@ -86,13 +178,33 @@ func (c *ClusterSupplyChain) validateNewState() error {
 				err,
 			)
 		}
+
+		for _, dependency := range component.DependsOn {
+			if c.getComponentByName(dependency) == nil {
+				return fmt.Errorf(
+					"component '%s' depends on unknown component '%s'",
+					component.Name,
+					dependency,
+				)
+			}
+		}
+	}
+
+	if cycle := c.Spec.dependencyCycle(); cycle != nil {
+		return fmt.Errorf("invalid clustersupplychain '%s': dependencies form a cycle %s", c.Name, strings.Join(cycle, " -> "))
 	}
 
 	return nil
 }
 
 func (c *ClusterSupplyChain) validateComponentRefs(references []ComponentReference, targetKind string) error {
+	names := make(map[string]bool)
 	for _, ref := range references {
+		if names[ref.Name] {
+			return fmt.Errorf("duplicate input name '%s'", ref.Name)
+		}
+		names[ref.Name] = true
+
 		referencedComponent := c.getComponentByName(ref.Component)
 		if referencedComponent == nil {
 			return fmt.Errorf(
@@ -101,12 +213,13 @@ func (c *ClusterSupplyChain) validateComponentRefs(references []ComponentReferen
 				ref.Component,
 			)
 		}
-		if referencedComponent.TemplateRef.Kind != targetKind {
+		if referencedComponent.TemplateRef.Kind != targetKind && !(targetKind == "ClusterSourceTemplate" && referencedComponent.OCIArtifact != nil) {
 			return fmt.Errorf(
-				"component '%s' providing '%s' must reference a %s",
+				"component '%s' providing '%s' must reference a %s, not a %s",
 				referencedComponent.Name,
 				ref.Name,
 				targetKind,
+				referencedComponent.TemplateRef.Kind,
 			)
 		}
 	}
@@ -150,13 +263,463 @@ func GetSelectorsFromObject(o client.Object) []string {
 }
 
 type SupplyChainSpec struct {
+	// Extends makes this supply chain a variant of another, whose
+	// components it inherits.
+	Extends    *SupplyChainExtension  `json:"extends,omitempty"`
 	Components []SupplyChainComponent `json:"components"`
 	Selector   map[string]string      `json:"selector"`
+	// SelectorMatchExpressions further restricts the workloads selected by
+	// Selector to those whose labels meet every requirement.
+	SelectorMatchExpressions []metav1.LabelSelectorRequirement `json:"selectorMatchExpressions,omitempty"`
+	// SelectorMatchFields further restricts the workloads selected to
+	// those whose fields meet every requirement, selecting workloads by
+	// their shape rather than by labels alone.
+	SelectorMatchFields []FieldSelectorRequirement `json:"selectorMatchFields,omitempty"`
+	// Priority chooses among the supply chains that select a workload: the
+	// chain of highest priority is used. Among chains of equal priority,
+	// the one with the most specific selector is used.
+	Priority int32 `json:"priority,omitempty"`
+	// Default makes this supply chain realize the workloads that no supply
+	// chain selects, including those without labels. A default SupplyChain
+	// does so for the workloads of its namespace, in place of the default
+	// ClusterSupplyChains. A default supply chain with an empty selector
+	// selects no workload otherwise.
+	Default bool `json:"default,omitempty"`
+	// WorkloadParams declares the params a workload may provide. When
+	// omitted, the workload's params are not validated. A variant that
+	// declares none inherits those of the supply chain it extends.
+	WorkloadParams []WorkloadParamDeclaration `json:"workloadParams,omitempty"`
+	// OwnerReferences chooses how the objects stamped for a workload refer
+	// to it. Controller objects are controlled by the workload; NonController
+	// objects are owned by it without being controlled, so that another
+	// controller may adopt them; both are garbage collected with the
+	// workload. None objects are only tracked by their labels and outlive
+	// the workload. Defaults to Controller. A variant that does not choose
+	// inherits the choice of the supply chain it extends.
+	// +kubebuilder:validation:Enum=Controller;NonController;None
+	OwnerReferences OwnerReferencePolicy `json:"ownerReferences,omitempty"`
+	// ServerSideApply chooses how the objects stamped for a workload are
+	// applied, in place of the settings of the Cartographer installation. A
+	// variant that does not choose inherits the choice of the supply chain
+	// it extends.
+	ServerSideApply *ServerSideApplySettings `json:"serverSideApply,omitempty"`
+	// ServiceAccountRef is the service account the objects stamped for a
+	// workload are written as, by impersonation, so that a tenant's objects
+	// are written with its permissions rather than those of the controller.
+	// When omitted, they are written as the controller. A variant that does
+	// not choose inherits the choice of the supply chain it extends.
+	ServiceAccountRef *ServiceAccountRef `json:"serviceAccountRef,omitempty"`
+	// AllowedTargetNamespaces are the namespaces other than the workload's
+	// that the components may stamp objects into, as a component's
+	// namespace or its template's chooses. As a workload's params may
+	// choose the namespace, an object stamped into a namespace not listed
+	// is not submitted. A variant that lists none inherits those of the
+	// supply chain it extends.
+	AllowedTargetNamespaces []string `json:"allowedTargetNamespaces,omitempty"`
+}
+
+// AllowsTargetNamespace tells whether the components may stamp objects into
+// the namespace, other than the workload's.
+func (s *SupplyChainSpec) AllowsTargetNamespace(namespace string) bool {
+	for _, allowed := range s.AllowedTargetNamespaces {
+		if allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceAccountRef names a service account.
+type ServiceAccountRef struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Namespace is ignored: the service account is always that of the
+	// workload's namespace, so that a tenant cannot write as the service
+	// account of another. A SupplyChain may only name its own namespace.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type ServerSideApplySettings struct {
+	// FieldManager is the field manager the stamped objects are applied
+	// under, so that the fields Cartographer sets can be told apart from
+	// those other controllers set.
+	FieldManager string `json:"fieldManager,omitempty"`
+	// ForceConflicts chooses whether to take over the fields of stamped
+	// objects that another field manager owns. When false, applying an
+	// object that sets such a field fails with a conflict.
+	ForceConflicts *bool `json:"forceConflicts,omitempty"`
+}
+
+type OwnerReferencePolicy string
+
+const (
+	ControllerOwnerReferencePolicy    OwnerReferencePolicy = "Controller"
+	NonControllerOwnerReferencePolicy OwnerReferencePolicy = "NonController"
+	NoneOwnerReferencePolicy          OwnerReferencePolicy = "None"
+)
+
+type WorkloadParamDeclaration struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Type is the JSON type the param's value must have. When omitted, any
+	// value is accepted.
+	// +kubebuilder:validation:Enum=string;number;integer;boolean;object;array
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	// Default is the value the param is given when a workload does not
+	// provide it, set on the workload when it is admitted.
+	Default *apiextensionsv1.JSON `json:"default,omitempty"`
+}
+
+// DefaultWorkloadParams returns the params of a workload with those it does
+// not provide set to the defaults declared by the supply chain.
+func (s *SupplyChainSpec) DefaultWorkloadParams(params []WorkloadParam) []WorkloadParam {
+	provided := map[string]bool{}
+	for _, param := range params {
+		provided[param.Name] = true
+	}
+
+	for _, declaration := range s.WorkloadParams {
+		if declaration.Default == nil || provided[declaration.Name] {
+			continue
+		}
+		params = append(params, WorkloadParam{Name: declaration.Name, Value: *declaration.Default.DeepCopy()})
+	}
+	return params
+}
+
+// ValidateWorkloadParams checks the params of a workload against those
+// declared by the supply chain, naming every missing, undeclared and
+// mistyped param.
+func (s *SupplyChainSpec) ValidateWorkloadParams(params []WorkloadParam) error {
+	if len(s.WorkloadParams) == 0 {
+		return nil
+	}
+
+	provided := map[string]WorkloadParam{}
+	for _, param := range params {
+		provided[param.Name] = param
+	}
+
+	var problems []string
+	declared := map[string]WorkloadParamDeclaration{}
+	for _, declaration := range s.WorkloadParams {
+		declared[declaration.Name] = declaration
+		if _, ok := provided[declaration.Name]; declaration.Required && !ok {
+			problems = append(problems, fmt.Sprintf("param '%s' is required", declaration.Name))
+		}
+	}
+
+	for _, param := range params {
+		declaration, ok := declared[param.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("param '%s' is not declared by the supply chain", param.Name))
+			continue
+		}
+
+		value := param.Value
+		if param.ValueFrom != nil {
+			// the value of a secret is a string.
+			value = apiextensionsv1.JSON{Raw: []byte(`""`)}
+		}
+		if declaration.Type != "" && !hasJSONType(value, declaration.Type) {
+			problems = append(problems, fmt.Sprintf("param '%s' must be of type %s", param.Name, declaration.Type))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// SelectorSpecificity is the number of requirements a workload must meet
+// to be selected by the supply chain.
+func (s *SupplyChainSpec) SelectorSpecificity() int {
+	return len(s.Selector) + len(s.SelectorMatchExpressions) + len(s.SelectorMatchFields)
+}
+
+// LabelSelector returns the selector matching the labels of the workloads
+// that go through the supply chain.
+func (s *SupplyChainSpec) LabelSelector() (labels.Selector, error) {
+	return metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels:      s.Selector,
+		MatchExpressions: s.SelectorMatchExpressions,
+	})
+}
+
+// SupplyChainExtension names the base supply chain that a supply chain
+// extends. The extending chain's components replace the base components of
+// the same name, in place, and its other components are inserted after the
+// base components. As components are realized after those they depend on,
+// an inserted component's place in the chain is given by its sources,
+// images, configs and dependsOn.
+type SupplyChainExtension struct {
+	// Name of the base ClusterSupplyChain, which may itself extend another.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Remove names the base components that are left out of the chain.
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Resolve returns a copy of the supply chain with the components of the
+// chains it extends, getting each base chain with get. The resolved chain
+// is validated as a whole. A supply chain that extends nothing is returned
+// as a copy.
+func (c *ClusterSupplyChain) Resolve(get func(name string) (*ClusterSupplyChain, error)) (*ClusterSupplyChain, error) {
+	if c.Spec.Extends == nil {
+		return c.DeepCopy(), nil
+	}
+
+	resolved, err := c.resolve(get, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolved.validateComponents(); err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+func (c *ClusterSupplyChain) resolve(get func(name string) (*ClusterSupplyChain, error), seen map[string]bool) (*ClusterSupplyChain, error) {
+	resolved := c.DeepCopy()
+	extension := resolved.Spec.Extends
+	if extension == nil {
+		return resolved, nil
+	}
+
+	// A SupplyChain may extend the ClusterSupplyChain of the same name, so
+	// chains are told apart by namespace too. Base chains are cluster-scoped.
+	seen[c.Namespace+"/"+c.Name] = true
+	if seen["/"+extension.Name] {
+		return nil, fmt.Errorf("clustersupplychain '%s' extends '%s', which extends it in turn", c.Name, extension.Name)
+	}
+
+	base, err := get(extension.Name)
+	if err != nil {
+		return nil, fmt.Errorf("get base clustersupplychain '%s': %w", extension.Name, err)
+	}
+	if base == nil {
+		return nil, fmt.Errorf("base clustersupplychain '%s' not found", extension.Name)
+	}
+
+	resolvedBase, err := base.resolve(get, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := extension.apply(resolvedBase.Spec.Components, resolved.Spec.Components)
+	if err != nil {
+		return nil, fmt.Errorf("extend clustersupplychain '%s': %w", extension.Name, err)
+	}
+
+	resolved.Spec.Components = components
+	if len(resolved.Spec.WorkloadParams) == 0 {
+		resolved.Spec.WorkloadParams = resolvedBase.Spec.WorkloadParams
+	}
+	if resolved.Spec.OwnerReferences == "" {
+		resolved.Spec.OwnerReferences = resolvedBase.Spec.OwnerReferences
+	}
+	if resolved.Spec.ServerSideApply == nil {
+		resolved.Spec.ServerSideApply = resolvedBase.Spec.ServerSideApply
+	}
+	if resolved.Spec.ServiceAccountRef == nil {
+		resolved.Spec.ServiceAccountRef = resolvedBase.Spec.ServiceAccountRef
+	}
+	if len(resolved.Spec.AllowedTargetNamespaces) == 0 {
+		resolved.Spec.AllowedTargetNamespaces = resolvedBase.Spec.AllowedTargetNamespaces
+	}
+	resolved.Spec.Extends = nil
+	return resolved, nil
+}
+
+func (e *SupplyChainExtension) apply(base, components []SupplyChainComponent) ([]SupplyChainComponent, error) {
+	removed := make(map[string]bool)
+	for _, name := range e.Remove {
+		removed[name] = true
+	}
+
+	overrides := make(map[string]SupplyChainComponent)
+	for _, component := range components {
+		if removed[component.Name] {
+			return nil, fmt.Errorf("component '%s' is both removed and replaced", component.Name)
+		}
+		overrides[component.Name] = component
+	}
+
+	var result []SupplyChainComponent
+	for _, component := range base {
+		if removed[component.Name] {
+			delete(removed, component.Name)
+			continue
+		}
+		if override, ok := overrides[component.Name]; ok {
+			component = override
+			delete(overrides, component.Name)
+		}
+		result = append(result, component)
+	}
+
+	for _, name := range e.Remove {
+		if removed[name] {
+			return nil, fmt.Errorf("cannot remove unknown component '%s'", name)
+		}
+	}
+
+	for _, component := range components {
+		if _, ok := overrides[component.Name]; ok {
+			result = append(result, component)
+		}
+	}
+
+	return result, nil
+}
+
+// RealizationOrder returns the indexes of the components in the order they
+// are realized: after every component they depend on, whether through
+// dependsOn or by consuming its sources, images or configs. Components
+// otherwise keep their order in the spec. An error is returned when the
+// dependencies form a cycle.
+func (s *SupplyChainSpec) RealizationOrder() ([]int, error) {
+	dependencies := s.dependencyIndexes()
+
+	var order []int
+	realized := make([]bool, len(s.Components))
+	for len(order) < len(s.Components) {
+		next := -1
+		for i := range s.Components {
+			if !realized[i] && allRealized(dependencies[i], realized) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			var unordered []string
+			for i, component := range s.Components {
+				if !realized[i] {
+					unordered = append(unordered, fmt.Sprintf("'%s'", component.Name))
+				}
+			}
+			return nil, fmt.Errorf("cannot order components %s: dependencies form a cycle", strings.Join(unordered, ", "))
+		}
+		realized[next] = true
+		order = append(order, next)
+	}
+
+	return order, nil
+}
+
+// DependencyIndexes returns, for each component, the indexes of the
+// components that must be realized before it.
+func (s *SupplyChainSpec) DependencyIndexes() [][]int {
+	result := make([][]int, len(s.Components))
+	for i, dependencies := range s.dependencyIndexes() {
+		result[i] = []int{}
+		for dependency := range dependencies {
+			result[i] = append(result[i], dependency)
+		}
+		sort.Ints(result[i])
+	}
+	return result
+}
+
+func (s *SupplyChainSpec) dependencyIndexes() []map[int]bool {
+	indexes := make(map[string]int, len(s.Components))
+	for i, component := range s.Components {
+		indexes[component.Name] = i
+	}
+
+	dependencies := make([]map[int]bool, len(s.Components))
+	for i, component := range s.Components {
+		dependencies[i] = map[int]bool{}
+		for _, name := range component.dependencyNames() {
+			if dependency, ok := indexes[name]; ok {
+				dependencies[i][dependency] = true
+			}
+		}
+	}
+	return dependencies
+}
+
+// dependencyCycle returns the quoted names of the components along a cycle
+// of dependencies, starting and ending with the same component, or nil when
+// the dependencies form no cycle.
+func (s *SupplyChainSpec) dependencyCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	dependencies := s.DependencyIndexes()
+	state := make([]int, len(s.Components))
+	var path []int
+
+	var visit func(i int) []int
+	visit = func(i int) []int {
+		state[i] = visiting
+		path = append(path, i)
+		for _, dependency := range dependencies[i] {
+			switch state[dependency] {
+			case visiting:
+				for j := range path {
+					if path[j] == dependency {
+						return append(append([]int{}, path[j:]...), dependency)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+
+	for i := range s.Components {
+		if state[i] != unvisited {
+			continue
+		}
+		if cycle := visit(i); cycle != nil {
+			names := make([]string, len(cycle))
+			for j, index := range cycle {
+				names[j] = fmt.Sprintf("'%s'", s.Components[index].Name)
+			}
+			return names
+		}
+	}
+	return nil
+}
+
+func allRealized(dependencies map[int]bool, realized []bool) bool {
+	for dependency := range dependencies {
+		if !realized[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *SupplyChainComponent) dependencyNames() []string {
+	names := append([]string{}, c.DependsOn...)
+	for _, refs := range [][]ComponentReference{c.Sources, c.Images, c.Configs} {
+		for _, ref := range refs {
+			names = append(names, ref.Component)
+		}
+	}
+	return names
 }
 
 type SupplyChainParam struct {
 	Name  string               `json:"name"`
 	Value apiextensionsv1.JSON `json:"value"`
+	// Immutable forbids workloads from overriding Value with a param of
+	// the same name.
+	Immutable bool `json:"immutable,omitempty"`
 }
 
 type SupplyChainComponent struct {
@@ -166,13 +729,256 @@ type SupplyChainComponent struct {
 	Sources     []ComponentReference     `json:"sources,omitempty"`
 	Images      []ComponentReference     `json:"images,omitempty"`
 	Configs     []ComponentReference     `json:"configs,omitempty"`
+	// DependsOn names the components that must be realized before this
+	// one, in addition to those it consumes sources, images or configs
+	// from.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Hooks run pipelines before and after the component's object is
+	// submitted.
+	Hooks *ComponentHooks `json:"hooks,omitempty"`
+	// ReadinessGate holds back the outputs of the component, and so the
+	// components that depend on it, until its object is healthy by the
+	// health rule of its template.
+	ReadinessGate bool `json:"readinessGate,omitempty"`
+	// MinHealthySeconds is how long the object of the component must have
+	// been healthy before the component is deemed healthy, so that an
+	// object whose health flaps opens no readiness gate and does not make
+	// the workload healthy. Until then, its health is Unknown.
+	// +kubebuilder:validation:Minimum=0
+	MinHealthySeconds int64 `json:"minHealthySeconds,omitempty"`
+	// Adopt takes over an existing object of the same name and kind as the
+	// one stamped, instead of failing to create it, so that objects created
+	// by hand can be migrated into the supply chain. The adopted object is
+	// given the labels and owner reference of a stamped object and updated
+	// from the template. Objects controlled by another owner, or stamped for
+	// another owner, are never adopted.
+	Adopt bool `json:"adopt,omitempty"`
+	// Namespace stamps the component's object into another namespace than
+	// the workload's, such as a namespace shared by builds. Like a
+	// template, it may refer to the workload and to the params of the
+	// template, as $(params.builds-namespace)$. As
+	// owner references cannot cross namespaces, an object stamped into
+	// another namespace is only tracked by its labels. A namespaced
+	// SupplyChain may not stamp objects outside its own namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// GitOps writes the component's stamped object to a Git repository, for
+	// Flux or Argo CD to apply, instead of applying it to the cluster. The
+	// outputs of the component are read from the object as stamped.
+	GitOps *GitOpsTarget `json:"gitOps,omitempty"`
+	// OCIArtifact publishes the component's stamped object as an OCI
+	// artifact, for the delivery side to pull, instead of applying it to
+	// the cluster. The artifact is the component's source output, which
+	// other components may consume as a source: its url is the image by
+	// digest and its revision the digest.
+	OCIArtifact *OCIArtifactTarget `json:"ociArtifact,omitempty"`
 }
 
+// writesElsewhere is whether the component's object is written somewhere
+// other than the cluster.
+func (c *SupplyChainComponent) writesElsewhere() bool {
+	return c.GitOps != nil || c.OCIArtifact != nil
+}
+
+// GitOpsTarget is the file of a Git repository a component's stamped object
+// is committed to, as a YAML manifest.
+type GitOpsTarget struct {
+	// URL of the repository, as git fetches it, such as
+	// https://github.com/acme/config.git.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+	// Branch the manifest is committed to, which is created when missing.
+	// Like a template, it may refer to the workload and to the params of
+	// the template, as $(params.environment)$.
+	// +optional
+	// +kubebuilder:default=main
+	Branch string `json:"branch,omitempty"`
+	// Path of the manifest in the repository. Like Branch, it may refer to
+	// the workload and to params. Defaults to
+	// <workload namespace>/<workload name>/<component name>.yaml.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// SecretRef names a Secret of type kubernetes.io/basic-auth in the
+	// workload's namespace, whose username and password the repository is
+	// written with. Without it, the repository is written with the
+	// credentials git finds on its own.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// OCIArtifactTarget is the image a component's stamped object is published
+// as: an artifact of a single layer holding its manifest.
+type OCIArtifactTarget struct {
+	// Image the artifact is pushed as, such as
+	// registry.example.com/team/app-config:latest. Like a template, it may
+	// refer to the workload and to the params of the template, as
+	// $(params.config-registry)$/$(workload.metadata.name)$-config.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// SecretRef names a Secret of type kubernetes.io/dockerconfigjson in
+	// the workload's namespace, with the credentials of the registry.
+	// Without it, the artifact is pushed anonymously.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// DefaultGitOpsBranch is the branch of a GitOpsTarget that names none.
+const DefaultGitOpsBranch = "main"
+
+// ComponentHooks run Pipelines around a component, for notifications,
+// cache warming or migrations that do not belong in the supply chain's
+// graph. Each hook is stamped as a Pipeline owned by the workload.
+type ComponentHooks struct {
+	// Pre hooks are stamped before the component's object. The object is
+	// only submitted once the latest run of every pre hook has succeeded.
+	Pre []ComponentHook `json:"pre,omitempty"`
+	// Post hooks are stamped once the component's object is submitted, and
+	// may refer to its outputs as $(output.source.url)$, $(output.image)$
+	// or $(output.config)$.
+	Post []ComponentHook `json:"post,omitempty"`
+}
+
+type ComponentHook struct {
+	// Name tells the hook apart from the other hooks of the component.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	RunTemplateRef TemplateReference `json:"runTemplateRef"`
+	// Inputs are passed to the RunTemplate. Like a template, they may refer
+	// to the workload, params, and the inputs of the component.
+	Inputs map[string]apiextensionsv1.JSON `json:"inputs,omitempty"`
+}
+
+func (h *ComponentHooks) validate() error {
+	if h == nil {
+		return nil
+	}
+
+	names := make(map[string]bool)
+	for _, hook := range append(append([]ComponentHook{}, h.Pre...), h.Post...) {
+		if hook.Name == "" {
+			return fmt.Errorf("hooks must be named")
+		}
+		if names[hook.Name] {
+			return fmt.Errorf("duplicate hook name '%s'", hook.Name)
+		}
+		names[hook.Name] = true
+
+		if err := hook.RunTemplateRef.Validate(); err != nil {
+			return fmt.Errorf("invalid run template reference for hook '%s': %w", hook.Name, err)
+		}
+	}
+	return nil
+}
+
+// ClusterTemplateReference names a template, or lists candidate templates
+// of the same kind to choose between for each workload. Exactly one of
+// Name or Options must be set.
 type ClusterTemplateReference struct {
 	// +kubebuilder:validation:Enum=ClusterSourceTemplate;ClusterImageTemplate;ClusterTemplate;ClusterConfigTemplate
 	Kind string `json:"kind"`
 	// +kubebuilder:validation:MinLength=1
-	Name string `json:"name"`
+	Name string `json:"name,omitempty"`
+	// Options are the candidate templates. The template of the one option
+	// whose selector matches the workload is used; a workload matching no
+	// option, or more than one, is not realized.
+	Options []TemplateOption `json:"options,omitempty"`
+}
+
+type TemplateOption struct {
+	// +kubebuilder:validation:MinLength=1
+	Name     string         `json:"name"`
+	Selector OptionSelector `json:"selector"`
+}
+
+// OptionSelector matches a workload by its labels and by the values of its
+// fields. All of the requirements must be satisfied.
+type OptionSelector struct {
+	metav1.LabelSelector `json:",inline"`
+	MatchFields          []FieldSelectorRequirement `json:"matchFields,omitempty"`
+}
+
+type FieldSelectorOperator string
+
+const (
+	FieldSelectorOpIn           FieldSelectorOperator = "In"
+	FieldSelectorOpNotIn        FieldSelectorOperator = "NotIn"
+	FieldSelectorOpExists       FieldSelectorOperator = "Exists"
+	FieldSelectorOpDoesNotExist FieldSelectorOperator = "DoesNotExist"
+)
+
+type FieldSelectorRequirement struct {
+	// Key is a jsonpath into the workload, e.g.
+	// `spec.params[?(@.name=="dockerfile")].value`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+	// +kubebuilder:validation:Enum=In;NotIn;Exists;DoesNotExist
+	Operator FieldSelectorOperator `json:"operator"`
+	// Values must be set for In and NotIn, and empty for Exists and
+	// DoesNotExist.
+	Values []string `json:"values,omitempty"`
+}
+
+// Validate checks that the reference names a template or lists options,
+// but not both, and that the options' selectors are well formed.
+func (r *ClusterTemplateReference) Validate() error {
+	if (r.Name == "") == (len(r.Options) == 0) {
+		return fmt.Errorf("exactly one of name or options must be set")
+	}
+
+	names := make(map[string]bool)
+	for _, option := range r.Options {
+		if names[option.Name] {
+			return fmt.Errorf("duplicate option '%s'", option.Name)
+		}
+		names[option.Name] = true
+
+		if err := option.Selector.validate(); err != nil {
+			return fmt.Errorf("invalid selector for option '%s': %w", option.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Candidates returns a reference to each template the reference may
+// resolve to.
+func (r *ClusterTemplateReference) Candidates() []ClusterTemplateReference {
+	if len(r.Options) == 0 {
+		return []ClusterTemplateReference{{Kind: r.Kind, Name: r.Name}}
+	}
+
+	var candidates []ClusterTemplateReference
+	for _, option := range r.Options {
+		candidates = append(candidates, ClusterTemplateReference{Kind: r.Kind, Name: option.Name})
+	}
+	return candidates
+}
+
+func (s *OptionSelector) validate() error {
+	if _, err := metav1.LabelSelectorAsSelector(&s.LabelSelector); err != nil {
+		return err
+	}
+
+	return validateFieldRequirements(s.MatchFields)
+}
+
+func validateFieldRequirements(requirements []FieldSelectorRequirement) error {
+	for _, requirement := range requirements {
+		switch requirement.Operator {
+		case FieldSelectorOpIn, FieldSelectorOpNotIn:
+			if len(requirement.Values) == 0 {
+				return fmt.Errorf("field '%s' must have values for operator %s", requirement.Key, requirement.Operator)
+			}
+		case FieldSelectorOpExists, FieldSelectorOpDoesNotExist:
+			if len(requirement.Values) != 0 {
+				return fmt.Errorf("field '%s' may not have values for operator %s", requirement.Key, requirement.Operator)
+			}
+		default:
+			return fmt.Errorf("field '%s' has unknown operator '%s'", requirement.Key, requirement.Operator)
+		}
+	}
+
+	return nil
 }
 
 type ComponentReference struct {
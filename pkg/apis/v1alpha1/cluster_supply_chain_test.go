@@ -22,6 +22,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
@@ -112,6 +114,457 @@ var _ = Describe("ClusterSupplyChain", func() {
 			Expect(jsonValue).To(ContainSubstring("configs"))
 			Expect(jsonValue).To(ContainSubstring("omitempty"))
 		})
+
+		It("does not require dependsOn", func() {
+			dependsOnField, found := supplyChainComponentType.FieldByName("DependsOn")
+			Expect(found).To(BeTrue())
+			jsonValue := dependsOnField.Tag.Get("json")
+			Expect(jsonValue).To(ContainSubstring("dependsOn"))
+			Expect(jsonValue).To(ContainSubstring("omitempty"))
+		})
+	})
+
+	Describe("RealizationOrder", func() {
+		var spec v1alpha1.SupplyChainSpec
+
+		BeforeEach(func() {
+			spec = v1alpha1.SupplyChainSpec{
+				Components: []v1alpha1.SupplyChainComponent{
+					{Name: "pipeline"},
+					{Name: "source-provider"},
+					{Name: "rbac"},
+				},
+			}
+		})
+
+		It("keeps the spec order of independent components", func() {
+			Expect(spec.RealizationOrder()).To(Equal([]int{0, 1, 2}))
+		})
+
+		It("orders components after those they depend on", func() {
+			spec.Components[0].DependsOn = []string{"rbac"}
+
+			Expect(spec.RealizationOrder()).To(Equal([]int{1, 2, 0}))
+		})
+
+		It("orders components after those they consume outputs from", func() {
+			spec.Components[0].Sources = []v1alpha1.ComponentReference{
+				{Name: "source", Component: "source-provider"},
+			}
+
+			Expect(spec.RealizationOrder()).To(Equal([]int{1, 0, 2}))
+		})
+
+		It("lists the dependencies of each component", func() {
+			spec.Components[0].DependsOn = []string{"rbac"}
+			spec.Components[0].Sources = []v1alpha1.ComponentReference{
+				{Name: "source", Component: "source-provider"},
+			}
+
+			Expect(spec.DependencyIndexes()).To(Equal([][]int{{1, 2}, {}, {}}))
+		})
+
+		It("returns an error when the dependencies form a cycle", func() {
+			spec.Components[0].DependsOn = []string{"rbac"}
+			spec.Components[2].DependsOn = []string{"pipeline"}
+
+			_, err := spec.RealizationOrder()
+			Expect(err).To(MatchError("cannot order components 'pipeline', 'rbac': dependencies form a cycle"))
+		})
+	})
+
+	Describe("ClusterTemplateReference", func() {
+		var ref v1alpha1.ClusterTemplateReference
+
+		BeforeEach(func() {
+			ref = v1alpha1.ClusterTemplateReference{
+				Kind: "ClusterImageTemplate",
+				Options: []v1alpha1.TemplateOption{
+					{
+						Name: "kpack-template",
+						Selector: v1alpha1.OptionSelector{
+							MatchFields: []v1alpha1.FieldSelectorRequirement{
+								{Key: `spec.params[?(@.name=="dockerfile")].value`, Operator: v1alpha1.FieldSelectorOpDoesNotExist},
+							},
+						},
+					},
+					{
+						Name: "kaniko-template",
+						Selector: v1alpha1.OptionSelector{
+							MatchFields: []v1alpha1.FieldSelectorRequirement{
+								{Key: `spec.params[?(@.name=="dockerfile")].value`, Operator: v1alpha1.FieldSelectorOpExists},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("accepts options", func() {
+			Expect(ref.Validate()).To(Succeed())
+		})
+
+		It("accepts a name", func() {
+			ref.Options = nil
+			ref.Name = "kpack-template"
+			Expect(ref.Validate()).To(Succeed())
+		})
+
+		It("rejects both a name and options", func() {
+			ref.Name = "kpack-template"
+			Expect(ref.Validate()).To(MatchError("exactly one of name or options must be set"))
+		})
+
+		It("rejects neither a name nor options", func() {
+			ref.Options = nil
+			Expect(ref.Validate()).To(MatchError("exactly one of name or options must be set"))
+		})
+
+		It("rejects duplicate options", func() {
+			ref.Options[1].Name = "kpack-template"
+			Expect(ref.Validate()).To(MatchError("duplicate option 'kpack-template'"))
+		})
+
+		It("rejects values for an Exists requirement", func() {
+			ref.Options[1].Selector.MatchFields[0].Values = []string{"Dockerfile"}
+			Expect(ref.Validate()).To(MatchError(`invalid selector for option 'kaniko-template': field 'spec.params[?(@.name=="dockerfile")].value' may not have values for operator Exists`))
+		})
+
+		It("rejects an In requirement without values", func() {
+			ref.Options[1].Selector.MatchFields[0].Operator = v1alpha1.FieldSelectorOpIn
+			Expect(ref.Validate()).To(MatchError(`invalid selector for option 'kaniko-template': field 'spec.params[?(@.name=="dockerfile")].value' must have values for operator In`))
+		})
+
+		It("rejects a malformed label selector", func() {
+			ref.Options[0].Selector.MatchExpressions = []metav1.LabelSelectorRequirement{
+				{Key: "language", Operator: "Resembles"},
+			}
+			Expect(ref.Validate()).To(MatchError(ContainSubstring("invalid selector for option 'kpack-template'")))
+		})
+
+		It("lists each option as a candidate", func() {
+			Expect(ref.Candidates()).To(Equal([]v1alpha1.ClusterTemplateReference{
+				{Kind: "ClusterImageTemplate", Name: "kpack-template"},
+				{Kind: "ClusterImageTemplate", Name: "kaniko-template"},
+			}))
+		})
+
+		It("lists a named template as the only candidate", func() {
+			ref.Options = nil
+			ref.Name = "kpack-template"
+			Expect(ref.Candidates()).To(Equal([]v1alpha1.ClusterTemplateReference{
+				{Kind: "ClusterImageTemplate", Name: "kpack-template"},
+			}))
+		})
+	})
+
+	Describe("Resolve", func() {
+		var (
+			chains map[string]*v1alpha1.ClusterSupplyChain
+			get    func(name string) (*v1alpha1.ClusterSupplyChain, error)
+		)
+
+		component := func(name, kind string, sources ...string) v1alpha1.SupplyChainComponent {
+			result := v1alpha1.SupplyChainComponent{
+				Name:        name,
+				TemplateRef: v1alpha1.ClusterTemplateReference{Kind: kind, Name: name + "-template"},
+			}
+			for _, source := range sources {
+				result.Sources = append(result.Sources, v1alpha1.ComponentReference{Name: source, Component: source})
+			}
+			return result
+		}
+
+		names := func(chain *v1alpha1.ClusterSupplyChain) []string {
+			var result []string
+			for _, component := range chain.Spec.Components {
+				result = append(result, component.Name)
+			}
+			return result
+		}
+
+		BeforeEach(func() {
+			chains = map[string]*v1alpha1.ClusterSupplyChain{
+				"golden": {
+					ObjectMeta: metav1.ObjectMeta{Name: "golden"},
+					Spec: v1alpha1.SupplyChainSpec{
+						Components: []v1alpha1.SupplyChainComponent{
+							component("source", "ClusterSourceTemplate"),
+							component("tests", "ClusterSourceTemplate", "source"),
+							component("image", "ClusterImageTemplate", "tests"),
+						},
+					},
+				},
+				"team": {
+					ObjectMeta: metav1.ObjectMeta{Name: "team"},
+					Spec: v1alpha1.SupplyChainSpec{
+						Extends: &v1alpha1.SupplyChainExtension{Name: "golden"},
+					},
+				},
+			}
+			get = func(name string) (*v1alpha1.ClusterSupplyChain, error) {
+				if chain, ok := chains[name]; ok {
+					return chain, nil
+				}
+				return nil, fmt.Errorf("'%s' not found", name)
+			}
+		})
+
+		It("inherits the components of the base chain", func() {
+			resolved, err := chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Name).To(Equal("team"))
+			Expect(resolved.Spec.Extends).To(BeNil())
+			Expect(names(resolved)).To(Equal([]string{"source", "tests", "image"}))
+		})
+
+		It("replaces base components in place", func() {
+			replacement := component("tests", "ClusterSourceTemplate", "source")
+			replacement.TemplateRef.Name = "team-tests-template"
+			chains["team"].Spec.Components = []v1alpha1.SupplyChainComponent{replacement}
+
+			resolved, err := chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(resolved)).To(Equal([]string{"source", "tests", "image"}))
+			Expect(resolved.Spec.Components[1].TemplateRef.Name).To(Equal("team-tests-template"))
+		})
+
+		It("inserts new components after the base components", func() {
+			chains["team"].Spec.Components = []v1alpha1.SupplyChainComponent{component("scan", "ClusterSourceTemplate", "source")}
+
+			resolved, err := chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(resolved)).To(Equal([]string{"source", "tests", "image", "scan"}))
+		})
+
+		It("removes base components", func() {
+			chains["team"].Spec.Extends.Remove = []string{"tests"}
+			chains["team"].Spec.Components = []v1alpha1.SupplyChainComponent{component("image", "ClusterImageTemplate", "source")}
+
+			resolved, err := chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(resolved)).To(Equal([]string{"source", "image"}))
+		})
+
+		It("resolves a base chain that extends another", func() {
+			chains["team"].Spec.Components = []v1alpha1.SupplyChainComponent{component("scan", "ClusterSourceTemplate", "source")}
+			chains["squad"] = &v1alpha1.ClusterSupplyChain{
+				ObjectMeta: metav1.ObjectMeta{Name: "squad"},
+				Spec: v1alpha1.SupplyChainSpec{
+					Extends: &v1alpha1.SupplyChainExtension{Name: "team", Remove: []string{"scan"}},
+				},
+			}
+
+			resolved, err := chains["squad"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(resolved)).To(Equal([]string{"source", "tests", "image"}))
+		})
+
+		It("returns a chain that extends nothing as it is", func() {
+			resolved, err := chains["golden"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved).To(Equal(chains["golden"]))
+		})
+
+		It("rejects removing a component that the base chain does not have", func() {
+			chains["team"].Spec.Extends.Remove = []string{"deploy"}
+
+			_, err := chains["team"].Resolve(get)
+			Expect(err).To(MatchError("extend clustersupplychain 'golden': cannot remove unknown component 'deploy'"))
+		})
+
+		It("rejects removing and replacing the same component", func() {
+			chains["team"].Spec.Extends.Remove = []string{"image"}
+			chains["team"].Spec.Components = []v1alpha1.SupplyChainComponent{component("image", "ClusterImageTemplate", "tests")}
+
+			_, err := chains["team"].Resolve(get)
+			Expect(err).To(MatchError("extend clustersupplychain 'golden': component 'image' is both removed and replaced"))
+		})
+
+		It("rejects a resolved chain whose components consume a removed component", func() {
+			chains["team"].Spec.Extends.Remove = []string{"tests"}
+
+			_, err := chains["team"].Resolve(get)
+			Expect(err).To(MatchError("invalid sources for component 'image': 'tests' is provided by unknown component 'tests'"))
+		})
+
+		It("rejects chains that extend each other", func() {
+			chains["golden"].Spec.Extends = &v1alpha1.SupplyChainExtension{Name: "team"}
+
+			_, err := chains["team"].Resolve(get)
+			Expect(err).To(MatchError("clustersupplychain 'golden' extends 'team', which extends it in turn"))
+		})
+
+		It("lets a namespaced chain extend the cluster chain of the same name", func() {
+			namespaced := &v1alpha1.SupplyChain{
+				ObjectMeta: metav1.ObjectMeta{Name: "golden", Namespace: "team-ns"},
+				Spec: v1alpha1.SupplyChainSpec{
+					Extends: &v1alpha1.SupplyChainExtension{Name: "golden"},
+				},
+			}
+
+			resolved, err := namespaced.AsClusterSupplyChain().Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Namespace).To(Equal("team-ns"))
+			Expect(names(resolved)).To(Equal([]string{"source", "tests", "image"}))
+		})
+
+		It("returns an error when the base chain cannot be got", func() {
+			chains["team"].Spec.Extends.Name = "platinum"
+
+			_, err := chains["team"].Resolve(get)
+			Expect(err).To(MatchError("get base clustersupplychain 'platinum': 'platinum' not found"))
+		})
+
+		It("returns an error when the base chain does not exist", func() {
+			chains["team"].Spec.Extends.Name = "platinum"
+
+			_, err := chains["team"].Resolve(func(string) (*v1alpha1.ClusterSupplyChain, error) {
+				return nil, nil
+			})
+			Expect(err).To(MatchError("base clustersupplychain 'platinum' not found"))
+		})
+
+		It("inherits the workload params declared by the base chain unless it declares its own", func() {
+			chains["golden"].Spec.WorkloadParams = []v1alpha1.WorkloadParamDeclaration{{Name: "port"}}
+
+			resolved, err := chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.WorkloadParams).To(Equal([]v1alpha1.WorkloadParamDeclaration{{Name: "port"}}))
+
+			chains["team"].Spec.WorkloadParams = []v1alpha1.WorkloadParamDeclaration{{Name: "replicas"}}
+
+			resolved, err = chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.WorkloadParams).To(Equal([]v1alpha1.WorkloadParamDeclaration{{Name: "replicas"}}))
+		})
+
+		It("inherits the owner references policy of the base chain unless it chooses its own", func() {
+			chains["golden"].Spec.OwnerReferences = v1alpha1.NoneOwnerReferencePolicy
+
+			resolved, err := chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.OwnerReferences).To(Equal(v1alpha1.NoneOwnerReferencePolicy))
+
+			chains["team"].Spec.OwnerReferences = v1alpha1.ControllerOwnerReferencePolicy
+
+			resolved, err = chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.OwnerReferences).To(Equal(v1alpha1.ControllerOwnerReferencePolicy))
+		})
+
+		It("inherits the server-side apply settings of the base chain unless it chooses its own", func() {
+			chains["golden"].Spec.ServerSideApply = &v1alpha1.ServerSideApplySettings{FieldManager: "golden"}
+
+			resolved, err := chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.ServerSideApply).To(Equal(&v1alpha1.ServerSideApplySettings{FieldManager: "golden"}))
+
+			chains["team"].Spec.ServerSideApply = &v1alpha1.ServerSideApplySettings{FieldManager: "team"}
+
+			resolved, err = chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.ServerSideApply).To(Equal(&v1alpha1.ServerSideApplySettings{FieldManager: "team"}))
+		})
+
+		It("inherits the service account of the base chain unless it chooses its own", func() {
+			chains["golden"].Spec.ServiceAccountRef = &v1alpha1.ServiceAccountRef{Name: "golden"}
+
+			resolved, err := chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.ServiceAccountRef).To(Equal(&v1alpha1.ServiceAccountRef{Name: "golden"}))
+
+			chains["team"].Spec.ServiceAccountRef = &v1alpha1.ServiceAccountRef{Name: "team", Namespace: "tenants"}
+
+			resolved, err = chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.ServiceAccountRef).To(Equal(&v1alpha1.ServiceAccountRef{Name: "team", Namespace: "tenants"}))
+		})
+
+		It("inherits the allowed target namespaces of the base chain unless it lists its own", func() {
+			chains["golden"].Spec.AllowedTargetNamespaces = []string{"builds"}
+
+			resolved, err := chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.AllowedTargetNamespaces).To(Equal([]string{"builds"}))
+
+			chains["team"].Spec.AllowedTargetNamespaces = []string{"team-builds"}
+
+			resolved, err = chains["team"].Resolve(get)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Spec.AllowedTargetNamespaces).To(Equal([]string{"team-builds"}))
+		})
+	})
+
+	Describe("ValidateWorkloadParams", func() {
+		var spec v1alpha1.SupplyChainSpec
+
+		BeforeEach(func() {
+			spec = v1alpha1.SupplyChainSpec{
+				WorkloadParams: []v1alpha1.WorkloadParamDeclaration{
+					{Name: "port", Type: "integer", Required: true},
+					{Name: "debug", Type: "boolean"},
+					{Name: "extra"},
+				},
+			}
+		})
+
+		param := func(name, value string) v1alpha1.WorkloadParam {
+			return v1alpha1.WorkloadParam{Name: name, Value: apiextensionsv1.JSON{Raw: []byte(value)}}
+		}
+
+		It("accepts any params when none are declared", func() {
+			spec.WorkloadParams = nil
+			Expect(spec.ValidateWorkloadParams([]v1alpha1.WorkloadParam{param("anything", `1`)})).To(Succeed())
+		})
+
+		It("accepts params matching the declaration", func() {
+			Expect(spec.ValidateWorkloadParams([]v1alpha1.WorkloadParam{
+				param("port", `8080`),
+				param("debug", `true`),
+				param("extra", `{"any": ["thing"]}`),
+			})).To(Succeed())
+		})
+
+		It("names every missing, undeclared and mistyped param", func() {
+			Expect(spec.ValidateWorkloadParams([]v1alpha1.WorkloadParam{
+				param("debug", `"yes"`),
+				param("prot", `8080`),
+			})).To(MatchError("param 'port' is required, param 'debug' must be of type boolean, param 'prot' is not declared by the supply chain"))
+		})
+
+		It("takes the value of a param from a secret to be a string", func() {
+			spec.WorkloadParams = append(spec.WorkloadParams, v1alpha1.WorkloadParamDeclaration{Name: "token", Type: "string"})
+			fromSecret := func(name string) v1alpha1.WorkloadParam {
+				return v1alpha1.WorkloadParam{Name: name, ValueFrom: &v1alpha1.WorkloadParamSource{
+					SecretKeyRef: &corev1.SecretKeySelector{Key: "token"},
+				}}
+			}
+
+			Expect(spec.ValidateWorkloadParams([]v1alpha1.WorkloadParam{param("port", `8080`), fromSecret("token")})).To(Succeed())
+			Expect(spec.ValidateWorkloadParams([]v1alpha1.WorkloadParam{fromSecret("port")})).To(MatchError("param 'port' must be of type integer"))
+		})
+	})
+
+	Describe("DefaultWorkloadParams", func() {
+		param := func(name, value string) v1alpha1.WorkloadParam {
+			return v1alpha1.WorkloadParam{Name: name, Value: apiextensionsv1.JSON{Raw: []byte(value)}}
+		}
+
+		It("adds the declared defaults of the params the workload does not provide", func() {
+			spec := v1alpha1.SupplyChainSpec{
+				WorkloadParams: []v1alpha1.WorkloadParamDeclaration{
+					{Name: "port", Type: "integer", Default: &apiextensionsv1.JSON{Raw: []byte(`8080`)}},
+					{Name: "debug", Type: "boolean", Default: &apiextensionsv1.JSON{Raw: []byte(`false`)}},
+					{Name: "extra"},
+				},
+			}
+
+			Expect(spec.DefaultWorkloadParams([]v1alpha1.WorkloadParam{param("debug", `true`)})).To(Equal([]v1alpha1.WorkloadParam{
+				param("debug", `true`),
+				param("port", `8080`),
+			}))
+		})
 	})
 
 	Describe("Webhook Validation", func() {
@@ -150,6 +603,23 @@ var _ = Describe("ClusterSupplyChain", func() {
 				})
 			})
 
+			Context("Supply chain with a workload param default of the wrong type", func() {
+				It("returns an error", func() {
+					supplyChain := &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							WorkloadParams: []v1alpha1.WorkloadParamDeclaration{
+								{Name: "port", Type: "integer", Default: &apiextensionsv1.JSON{Raw: []byte(`"8080"`)}},
+							},
+						},
+					}
+
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"default of workload param 'port' must be of type integer in clustersupplychain 'responsible-ops'",
+					))
+				})
+			})
+
 			Context("Supply chain with a component reference that does not exist", func() {
 				var supplyChain *v1alpha1.ClusterSupplyChain
 
@@ -193,6 +663,417 @@ var _ = Describe("ClusterSupplyChain", func() {
 				})
 			})
 
+			Context("Supply chain with a component consuming two inputs of the same name", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "app-config",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterConfigTemplate",
+										Name: "app-config-template",
+									},
+								},
+								{
+									Name: "db-config",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterConfigTemplate",
+										Name: "db-config-template",
+									},
+								},
+								{
+									Name: "merged-config",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterTemplate",
+										Name: "merge-template",
+									},
+									Configs: []v1alpha1.ComponentReference{
+										{Name: "config", Component: "app-config"},
+										{Name: "config", Component: "db-config"},
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("returns an error", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"invalid configs for component 'merged-config': duplicate input name 'config'",
+					))
+				})
+
+				It("accepts distinct names", func() {
+					supplyChain.Spec.Components[2].Configs[1].Name = "db"
+					Expect(supplyChain.ValidateCreate()).To(Succeed())
+				})
+			})
+
+			Context("Supply chain with a dependsOn that does not exist", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "some-component",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterTemplate",
+										Name: "some-template",
+									},
+									DependsOn: []string{"some-nonexistent-component"},
+								},
+							},
+						},
+					}
+				})
+
+				It("returns an error", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"component 'some-component' depends on unknown component 'some-nonexistent-component'",
+					))
+				})
+			})
+
+			Context("Supply chain with a component that names a template and lists options", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "some-component",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind:    "ClusterTemplate",
+										Name:    "some-template",
+										Options: []v1alpha1.TemplateOption{{Name: "some-other-template"}},
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("returns an error", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"invalid template reference for component 'some-component': exactly one of name or options must be set",
+					))
+				})
+			})
+
+			Context("Supply chain with a component that has hooks", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "some-component",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterTemplate",
+										Name: "some-template",
+									},
+									Hooks: &v1alpha1.ComponentHooks{
+										Pre:  []v1alpha1.ComponentHook{{Name: "migrate", RunTemplateRef: v1alpha1.TemplateReference{Name: "migration"}}},
+										Post: []v1alpha1.ComponentHook{{Name: "notify", RunTemplateRef: v1alpha1.TemplateReference{Name: "notification"}}},
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("accepts well formed hooks", func() {
+					Expect(supplyChain.ValidateCreate()).To(Succeed())
+				})
+
+				It("rejects hooks of the same name", func() {
+					supplyChain.Spec.Components[0].Hooks.Post[0].Name = "migrate"
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"invalid hooks for component 'some-component': duplicate hook name 'migrate'",
+					))
+				})
+
+				It("rejects a hook that neither names nor selects a run template", func() {
+					supplyChain.Spec.Components[0].Hooks.Pre[0].RunTemplateRef.Name = ""
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"invalid hooks for component 'some-component': invalid run template reference for hook 'migrate': exactly one of name or selector must be set",
+					))
+				})
+			})
+
+			Context("Supply chain with a component written to git", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "config",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterConfigTemplate",
+										Name: "app-config",
+									},
+									GitOps: &v1alpha1.GitOpsTarget{URL: "https://github.com/acme/config.git"},
+								},
+							},
+						},
+					}
+				})
+
+				It("accepts it", func() {
+					Expect(supplyChain.ValidateCreate()).To(Succeed())
+				})
+
+				It("rejects it when it also gates on the health of its object", func() {
+					supplyChain.Spec.Components[0].ReadinessGate = true
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"component 'config' writes its object outside the cluster, so it may not adopt it or gate on its health",
+					))
+				})
+			})
+
+			Context("Supply chain with a component stamped into another namespace", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "image",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterImageTemplate",
+										Name: "kpack-image",
+									},
+									Namespace: "builds",
+								},
+							},
+							AllowedTargetNamespaces: []string{"builds"},
+						},
+					}
+				})
+
+				It("accepts it when the namespace is allowed", func() {
+					Expect(supplyChain.ValidateCreate()).To(Succeed())
+				})
+
+				It("accepts a namespace chosen by the workload, which is checked as it is stamped", func() {
+					supplyChain.Spec.Components[0].Namespace = "$(params.builds-namespace)$"
+					supplyChain.Spec.AllowedTargetNamespaces = nil
+					Expect(supplyChain.ValidateCreate()).To(Succeed())
+				})
+
+				It("rejects it when the namespace is not allowed", func() {
+					supplyChain.Spec.AllowedTargetNamespaces = nil
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"component 'image' stamps its object into namespace 'builds', which is not one of the allowedTargetNamespaces",
+					))
+				})
+			})
+
+			Context("Supply chain with a component published as an OCI artifact", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "config",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterConfigTemplate",
+										Name: "app-config",
+									},
+									OCIArtifact: &v1alpha1.OCIArtifactTarget{Image: "registry.example.com/team/app-config"},
+								},
+								{
+									Name: "deliverer",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterTemplate",
+										Name: "deliverable",
+									},
+									Sources: []v1alpha1.ComponentReference{{Name: "config", Component: "config"}},
+								},
+							},
+						},
+					}
+				})
+
+				It("accepts a component consuming the artifact as a source", func() {
+					Expect(supplyChain.ValidateCreate()).To(Succeed())
+				})
+
+				It("rejects a component that also writes to git", func() {
+					supplyChain.Spec.Components[0].GitOps = &v1alpha1.GitOpsTarget{URL: "https://github.com/acme/config.git"}
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"component 'config' may write its object to git or publish it as an OCI artifact, not both",
+					))
+				})
+			})
+
+			Context("Supply chain that extends another", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "team-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Extends: &v1alpha1.SupplyChainExtension{Name: "responsible-ops"},
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "some-component",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterTemplate",
+										Name: "some-template",
+									},
+									DependsOn: []string{"some-base-component"},
+								},
+							},
+						},
+					}
+				})
+
+				It("accepts references to components of the base chain", func() {
+					Expect(supplyChain.ValidateCreate()).To(Succeed())
+				})
+
+				It("requires the name of the base chain", func() {
+					supplyChain.Spec.Extends.Name = ""
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"clustersupplychain 'team-ops' must name the supply chain it extends",
+					))
+				})
+			})
+
+			Context("Supply chain with selector match expressions", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Selector: map[string]string{"app.tanzu.vmware.com/workload-type": "web"},
+							SelectorMatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"legacy"}},
+								{Key: "team", Operator: metav1.LabelSelectorOpExists},
+							},
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "some-component",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterTemplate",
+										Name: "some-template",
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("accepts well formed expressions", func() {
+					Expect(supplyChain.ValidateCreate()).To(Succeed())
+				})
+
+				It("rejects an expression without the values its operator needs", func() {
+					supplyChain.Spec.SelectorMatchExpressions[0].Values = nil
+					Expect(supplyChain.ValidateCreate()).To(MatchError(ContainSubstring(
+						"invalid selector for clustersupplychain 'responsible-ops': ",
+					)))
+				})
+
+				It("rejects an unknown operator", func() {
+					supplyChain.Spec.SelectorMatchExpressions[1].Operator = "Matches"
+					Expect(supplyChain.ValidateUpdate(nil)).To(MatchError(ContainSubstring(
+						`"Matches" is not a valid pod selector operator`,
+					)))
+				})
+
+				It("rejects a field requirement without the values its operator needs", func() {
+					supplyChain.Spec.SelectorMatchFields = []v1alpha1.FieldSelectorRequirement{
+						{Key: "spec.source.git.url", Operator: v1alpha1.FieldSelectorOpIn},
+					}
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"invalid selector for clustersupplychain 'responsible-ops': field 'spec.source.git.url' must have values for operator In",
+					))
+				})
+			})
+
+			Context("Supply chain whose dependencies form a cycle", func() {
+				var supplyChain *v1alpha1.ClusterSupplyChain
+
+				BeforeEach(func() {
+					supplyChain = &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "responsible-ops"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{
+									Name: "some-component",
+									TemplateRef: v1alpha1.ClusterTemplateReference{
+										Kind: "ClusterTemplate",
+										Name: "some-template",
+									},
+									DependsOn: []string{"some-component"},
+								},
+							},
+						},
+					}
+				})
+
+				It("returns an error", func() {
+					Expect(supplyChain.ValidateCreate()).To(MatchError(
+						"invalid clustersupplychain 'responsible-ops': dependencies form a cycle 'some-component' -> 'some-component'",
+					))
+				})
+
+				Context("through other components", func() {
+					BeforeEach(func() {
+						supplyChain.Spec.Components = []v1alpha1.SupplyChainComponent{
+							{
+								Name:        "source-provider",
+								TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterSourceTemplate", Name: "source"},
+								DependsOn:   []string{"deployer"},
+							},
+							{
+								Name:        "image-builder",
+								TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterImageTemplate", Name: "image"},
+								Sources:     []v1alpha1.ComponentReference{{Name: "source", Component: "source-provider"}},
+							},
+							{
+								Name:        "deployer",
+								TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterTemplate", Name: "deploy"},
+								Images:      []v1alpha1.ComponentReference{{Name: "image", Component: "image-builder"}},
+							},
+							{
+								Name:        "notifier",
+								TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterTemplate", Name: "notify"},
+								DependsOn:   []string{"deployer"},
+							},
+						}
+					})
+
+					It("names only the components along the cycle", func() {
+						Expect(supplyChain.ValidateCreate()).To(MatchError(
+							"invalid clustersupplychain 'responsible-ops': dependencies form a cycle 'source-provider' -> 'deployer' -> 'image-builder' -> 'source-provider'",
+						))
+					})
+				})
+			})
+
 			Context("Two components with the same name", func() {
 				var supplyChainWithDuplicateComponentNames *v1alpha1.ClusterSupplyChain
 				BeforeEach(func() {
@@ -288,9 +1169,10 @@ var _ = Describe("ClusterSupplyChain", func() {
 						} else {
 							Expect(err).To(HaveOccurred())
 							Expect(err).To(MatchError(fmt.Sprintf(
-								"invalid %ss for component 'input-consumer': component 'input-provider' providing 'input-name' must reference a %s",
+								"invalid %ss for component 'input-consumer': component 'input-provider' providing 'input-name' must reference a %s, not a %s",
 								strings.ToLower(inputReferenceType),
-								consumerToProviderMapping[inputReferenceType]),
+								consumerToProviderMapping[inputReferenceType],
+								firstComponentKind),
 							))
 						}
 
@@ -422,7 +1304,7 @@ var _ = Describe("ClusterSupplyChain", func() {
 				It("validates on update as well", func() {
 					err := invalidSupplyChain.ValidateUpdate(&v1alpha1.ClusterSupplyChain{})
 					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(Equal("invalid sources for component 'input-consumer': component 'image-provider' providing 'source-name' must reference a ClusterSourceTemplate"))
+					Expect(err.Error()).To(Equal("invalid sources for component 'input-consumer': component 'image-provider' providing 'source-name' must reference a ClusterSourceTemplate, not a ClusterImageTemplate"))
 				})
 			})
 		})
@@ -21,6 +21,7 @@ package v1alpha1
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -44,6 +45,58 @@ type TemplateSpec struct {
 	Template *runtime.RawExtension `json:"template,omitempty"`
 	Ytt      string                `json:"ytt,omitempty"`
 	Params   DefaultParams         `json:"params,omitempty"`
+	// Metadata tells app teams whom to contact when the template fails to
+	// realize their workloads.
+	Metadata *TemplateMetadata `json:"metadata,omitempty"`
+	// HealthRule tells how healthy the objects stamped from the template
+	// are.
+	HealthRule *HealthRule `json:"healthRule,omitempty"`
+	// Sample is what the template is stamped for when it is created or
+	// updated. A template that fails to stamp for its sample is rejected.
+	Sample *TemplateSample `json:"sample,omitempty"`
+}
+
+// TemplateSample is a sample of what a template is stamped for, catching
+// the templates that would fail to stamp before they break the workloads
+// going through them.
+type TemplateSample struct {
+	// Workload is a sample of the workloads the template is stamped for.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Workload runtime.RawExtension `json:"workload"`
+	// Context is the rest of what the template may refer to, as the context
+	// of a TemplatePlayground: params, sources, images, configs, source,
+	// image and config. When it has no params, those of the workload
+	// override the defaults of the template's params.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Context *runtime.RawExtension `json:"context,omitempty"`
+}
+
+type TemplateMetadata struct {
+	// Maintainers are the people or teams who own the template, e.g.
+	// "build-team <builds@example.com>".
+	Maintainers []string `json:"maintainers,omitempty"`
+	// DocsURL links to the template's documentation.
+	DocsURL string `json:"docsURL,omitempty"`
+}
+
+// Contact describes how to reach the template's maintainers, or returns an
+// empty string when the metadata names neither maintainers nor docs.
+func (m *TemplateMetadata) Contact() string {
+	if m == nil {
+		return ""
+	}
+
+	var parts []string
+	if len(m.Maintainers) > 0 {
+		parts = append(parts, fmt.Sprintf("maintained by %s", strings.Join(m.Maintainers, ", ")))
+	}
+	if m.DocsURL != "" {
+		parts = append(parts, fmt.Sprintf("docs at %s", m.DocsURL))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("template %s", strings.Join(parts, ", "))
 }
 
 type TemplateStatus struct {
@@ -71,6 +124,9 @@ func (t *TemplateSpec) validate() error {
 		return fmt.Errorf("invalid template: must specify one of template or ytt, found both")
 	}
 	if t.Template != nil {
+		if err := validateTemplateSyntax(t.Template.Raw); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
 		obj := metav1.PartialObjectMetadata{}
 		if err := json.Unmarshal(t.Template.Raw, &obj); err != nil {
 			return fmt.Errorf("invalid template: failed to parse object metadata: %w", err)
@@ -79,6 +135,14 @@ func (t *TemplateSpec) validate() error {
 			return errors.New("invalid template: template should not set metadata.namespace on the child object")
 		}
 	}
+	if t.Ytt != "" {
+		if err := validateYttSyntax(t.Ytt); err != nil {
+			return fmt.Errorf("invalid ytt: %w", err)
+		}
+	}
+	if err := t.HealthRule.validate(); err != nil {
+		return fmt.Errorf("invalid health rule: %w", err)
+	}
 	return nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -204,6 +205,101 @@ var _ = Describe("ClusterTemplate", func() {
 			})
 		})
 
+		Describe("health rule", func() {
+			BeforeEach(func() {
+				template.Spec.Ytt = "some-ytt"
+			})
+
+			It("accepts a template with a single condition type", func() {
+				template.Spec.HealthRule = &v1alpha1.HealthRule{SingleConditionType: "Ready"}
+				Expect(template.ValidateCreate()).To(Succeed())
+			})
+
+			It("rejects a rule setting more than one way to judge health", func() {
+				template.Spec.HealthRule = &v1alpha1.HealthRule{
+					SingleConditionType: "Ready",
+					AlwaysHealthy:       &runtime.RawExtension{Raw: []byte(`{}`)},
+				}
+				Expect(template.ValidateCreate()).To(MatchError(
+					"invalid health rule: exactly one of alwaysHealthy, singleConditionType, multiMatch or tekton must be set",
+				))
+			})
+
+			It("rejects an empty rule", func() {
+				template.Spec.HealthRule = &v1alpha1.HealthRule{}
+				Expect(template.ValidateUpdate(nil)).To(MatchError(
+					"invalid health rule: exactly one of alwaysHealthy, singleConditionType, multiMatch or tekton must be set",
+				))
+			})
+
+			Context("that multi matches", func() {
+				BeforeEach(func() {
+					template.Spec.HealthRule = &v1alpha1.HealthRule{
+						MultiMatch: &v1alpha1.MultiMatchHealthRule{
+							Healthy: v1alpha1.HealthMatchRule{
+								MatchConditions: []v1alpha1.ConditionRequirement{{Type: "Ready", Status: "True"}},
+							},
+							Unhealthy: v1alpha1.HealthMatchRule{
+								MatchFields: []v1alpha1.FieldSelectorRequirement{{Key: "status.phase", Operator: "In", Values: []string{"Failed"}}},
+							},
+						},
+					}
+				})
+
+				It("accepts healthy and unhealthy requirements", func() {
+					Expect(template.ValidateCreate()).To(Succeed())
+				})
+
+				It("rejects a rule without unhealthy requirements", func() {
+					template.Spec.HealthRule.MultiMatch.Unhealthy = v1alpha1.HealthMatchRule{}
+					Expect(template.ValidateCreate()).To(MatchError(
+						"invalid health rule: multiMatch must have both healthy and unhealthy requirements",
+					))
+				})
+
+				It("rejects malformed field requirements", func() {
+					template.Spec.HealthRule.MultiMatch.Unhealthy.MatchFields[0].Values = nil
+					Expect(template.ValidateCreate()).To(MatchError(
+						"invalid health rule: invalid unhealthy requirements: field 'status.phase' must have values for operator In",
+					))
+				})
+			})
+		})
+
+		Describe("syntax", func() {
+			DescribeTable("templates",
+				func(raw string, expectedError string) {
+					template.Spec.Template = &runtime.RawExtension{Raw: []byte(raw)}
+					err := template.ValidateCreate()
+					if expectedError == "" {
+						Expect(err).NotTo(HaveOccurred())
+					} else {
+						Expect(err).To(MatchError(HavePrefix(expectedError)))
+					}
+				},
+				Entry("with expressions", `{"metadata": {"name": "$(workload.metadata.name)$-$(params.suffix)$"}, "data": {"n": "$(params.replicas)$"}}`, ""),
+				Entry("with a $( that is not an expression", `{"data": {"script": "echo $(date)"}}`, ""),
+				Entry("that is empty", ``, "invalid template: template is empty"),
+				Entry("that does not parse", "{\n  \"data\": {\n    \"a\": \"b\",,\n  }\n}", "invalid template: line 3, column 14: invalid character ','"),
+				Entry("with an expression that does not parse", `{"data": {"url": "https://$(workload.spec.params[0)$"}}`, "invalid template: template.data.url: expression 'workload.spec.params[0' at column 9 does not parse"),
+				Entry("with an empty expression", `{"spec": {"args": ["--port", "$( )$"]}}`, "invalid template: template.spec.args[1]: expression at column 1 is empty"),
+			)
+
+			DescribeTable("ytt",
+				func(ytt string, expectedError string) {
+					template.Spec.Ytt = ytt
+					err := template.ValidateCreate()
+					if expectedError == "" {
+						Expect(err).NotTo(HaveOccurred())
+					} else {
+						Expect(err).To(MatchError(expectedError))
+					}
+				},
+				Entry("with annotations and several documents", "#@ load(\"@ytt:data\", \"data\")\n---\nkind: ConfigMap\nmetadata:\n  name: #@ data.values.workload.metadata.name\n---\nkind: Secret\n", ""),
+				Entry("that does not parse", "kind: ConfigMap\nmetadata:\n  name: a\n   labels: {}\n", "invalid ytt: yaml: line 4: mapping values are not allowed in this context"),
+			)
+		})
+
 		Context("#Delete", func() {
 			Context("Any template", func() {
 				var anyTemplate *v1alpha1.ClusterTemplate
@@ -214,3 +310,24 @@ var _ = Describe("ClusterTemplate", func() {
 		})
 	})
 })
+
+var _ = Describe("TemplateMetadata", func() {
+	It("names the maintainers and docs", func() {
+		metadata := &v1alpha1.TemplateMetadata{
+			Maintainers: []string{"build-team", "oncall <oncall@example.com>"},
+			DocsURL:     "https://example.com/templates",
+		}
+		Expect(metadata.Contact()).To(Equal("template maintained by build-team, oncall <oncall@example.com>, docs at https://example.com/templates"))
+	})
+
+	It("names only the docs when there are no maintainers", func() {
+		metadata := &v1alpha1.TemplateMetadata{DocsURL: "https://example.com/templates"}
+		Expect(metadata.Contact()).To(Equal("template docs at https://example.com/templates"))
+	})
+
+	It("is empty without metadata", func() {
+		var metadata *v1alpha1.TemplateMetadata
+		Expect(metadata.Contact()).To(BeEmpty())
+		Expect((&v1alpha1.TemplateMetadata{}).Contact()).To(BeEmpty())
+	})
+})
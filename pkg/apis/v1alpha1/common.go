@@ -45,3 +45,23 @@ func GetAPITemplate(templateKind string) (client.Object, error) {
 	}
 	return template, nil
 }
+
+// GetNamespacedAPITemplate returns the namespace-scoped counterpart of a
+// cluster-scoped template kind, which a SupplyChain resolves first.
+func GetNamespacedAPITemplate(clusterTemplateKind string) (client.Object, error) {
+	var template client.Object
+
+	switch clusterTemplateKind {
+	case "ClusterSourceTemplate":
+		template = &SourceTemplate{}
+	case "ClusterImageTemplate":
+		template = &ImageTemplate{}
+	case "ClusterConfigTemplate":
+		template = &ConfigTemplate{}
+	case "ClusterTemplate":
+		template = &Template{}
+	default:
+		return nil, fmt.Errorf("component does not have valid kind: %s", clusterTemplateKind)
+	}
+	return template, nil
+}
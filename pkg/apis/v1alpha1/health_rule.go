@@ -0,0 +1,139 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ComponentHealthy is the type of the condition reporting the health of the
+// object stamped for a component, as judged by the health rule of its
+// template.
+const ComponentHealthy = "Healthy"
+
+// ComponentHealthFlapping is the type of the condition reported while the
+// object stamped for a component keeps changing between healthy and
+// unhealthy. It is only reported while the object flaps.
+const ComponentHealthFlapping = "HealthFlapping"
+
+const (
+	FrequentChangesHealthFlappingReason = "FrequentChanges"
+)
+
+const (
+	AlwaysHealthyHealthyReason       = "AlwaysHealthy"
+	SingleConditionTypeHealthyReason = "SingleConditionType"
+	MatchedConditionHealthyReason    = "MatchedCondition"
+	MatchedFieldHealthyReason        = "MatchedField"
+	NoMatchesFulfilledHealthyReason  = "NoMatchesFulfilled"
+	OutputsAvailableHealthyReason    = "OutputsAvailable"
+	OutputsNotAvailableHealthyReason = "OutputsNotAvailable"
+	NoStampedObjectHealthyReason     = "NoStampedObject"
+	TektonRunHealthyReason           = "TektonRun"
+	KappAppHealthyReason             = "KappApp"
+	KnativeServiceHealthyReason      = "KnativeService"
+	FluxKustomizationHealthyReason   = "FluxKustomization"
+	FluxGitRepositoryHealthyReason   = "FluxGitRepository"
+	StabilizingHealthyReason         = "Stabilizing"
+)
+
+// HealthRule tells how healthy the object stamped from a template is.
+// Exactly one of its fields must be set. Without a health rule, a
+// kapp-controller App, a Knative Service or a Flux Kustomization or
+// GitRepository is judged by its status, and any other object is healthy
+// once its outputs are available.
+type HealthRule struct {
+	// AlwaysHealthy deems the object healthy as soon as it is submitted.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	AlwaysHealthy *runtime.RawExtension `json:"alwaysHealthy,omitempty"`
+	// SingleConditionType names the condition of the object whose status is
+	// its health, e.g. Ready or Succeeded.
+	SingleConditionType string `json:"singleConditionType,omitempty"`
+	// MultiMatch judges the health of the object by its conditions and
+	// fields.
+	MultiMatch *MultiMatchHealthRule `json:"multiMatch,omitempty"`
+	// Tekton judges a Tekton PipelineRun or TaskRun by its Succeeded
+	// condition: healthy once it succeeded, unhealthy once it failed, timed
+	// out or was cancelled, and of unknown health while it is pending or
+	// running.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Tekton *runtime.RawExtension `json:"tekton,omitempty"`
+}
+
+// MultiMatchHealthRule deems an object unhealthy when any of the unhealthy
+// requirements is met, healthy when all of the healthy requirements are met,
+// and of unknown health otherwise.
+type MultiMatchHealthRule struct {
+	Healthy   HealthMatchRule `json:"healthy"`
+	Unhealthy HealthMatchRule `json:"unhealthy"`
+}
+
+type HealthMatchRule struct {
+	MatchConditions []ConditionRequirement     `json:"matchConditions,omitempty"`
+	MatchFields     []FieldSelectorRequirement `json:"matchFields,omitempty"`
+}
+
+// ConditionRequirement is met by an object with a condition of the type and
+// status.
+type ConditionRequirement struct {
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status metav1.ConditionStatus `json:"status"`
+}
+
+func (r *HealthRule) validate() error {
+	if r == nil {
+		return nil
+	}
+
+	set := 0
+	if r.AlwaysHealthy != nil {
+		set++
+	}
+	if r.SingleConditionType != "" {
+		set++
+	}
+	if r.MultiMatch != nil {
+		set++
+	}
+	if r.Tekton != nil {
+		set++
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of alwaysHealthy, singleConditionType, multiMatch or tekton must be set")
+	}
+
+	if r.MultiMatch != nil {
+		if r.MultiMatch.Healthy.empty() || r.MultiMatch.Unhealthy.empty() {
+			return fmt.Errorf("multiMatch must have both healthy and unhealthy requirements")
+		}
+		if err := validateFieldRequirements(r.MultiMatch.Healthy.MatchFields); err != nil {
+			return fmt.Errorf("invalid healthy requirements: %w", err)
+		}
+		if err := validateFieldRequirements(r.MultiMatch.Unhealthy.MatchFields); err != nil {
+			return fmt.Errorf("invalid unhealthy requirements: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *HealthMatchRule) empty() bool {
+	return len(r.MatchConditions) == 0 && len(r.MatchFields) == 0
+}
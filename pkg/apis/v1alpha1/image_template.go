@@ -0,0 +1,67 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +versionName=v1alpha1
+// +groupName=carto.run
+// +kubebuilder:object:generate=true
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// ImageTemplate is the namespace-scoped counterpart of ClusterImageTemplate. The components
+// of a SupplyChain in the same namespace that refer to a ClusterImageTemplate use the
+// ImageTemplate of the same name, when there is one.
+type ImageTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              ImageTemplateSpec   `json:"spec"`
+	Status            ImageTemplateStatus `json:"status,omitempty"`
+}
+
+var _ webhook.Validator = &ImageTemplate{}
+
+func (t *ImageTemplate) ValidateCreate() error {
+	return t.Spec.TemplateSpec.validate()
+}
+
+func (t *ImageTemplate) ValidateUpdate(_ runtime.Object) error {
+	return t.Spec.TemplateSpec.validate()
+}
+
+func (t *ImageTemplate) ValidateDelete() error {
+	return nil
+}
+
+// +kubebuilder:object:root=true
+
+type ImageTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&ImageTemplate{},
+		&ImageTemplateList{},
+	)
+}
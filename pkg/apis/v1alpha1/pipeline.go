@@ -19,6 +19,8 @@
 package v1alpha1
 
 import (
+	"fmt"
+
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -28,17 +30,40 @@ const (
 	RunTemplateReady = "RunTemplateReady"
 )
 
+// RetriggerAnnotation forces a pipeline to stamp a fresh run, even when its
+// inputs are unchanged, whenever the annotation's value changes. A
+// timestamp is a convenient value.
+const RetriggerAnnotation = "carto.run/retrigger"
+
+const (
+	SucceededRunResult = "Succeeded"
+	FailedRunResult    = "Failed"
+	RunningRunResult   = "Running"
+)
+
 const (
 	ReadyRunTemplateReason                            = "Ready"
 	NotFoundRunTemplateReason                         = "RunTemplateNotFound"
 	StampedObjectRejectedByAPIServerRunTemplateReason = "StampedObjectRejectedByAPIServer"
+	StampedObjectRejectedByDryRunRunTemplateReason    = "StampedObjectRejectedByDryRun"
+	InvalidStampedObjectRunTemplateReason             = "InvalidStampedObject"
 	OutputPathNotSatisfiedRunTemplateReason           = "OutputPathNotSatisfied"
 	TemplateStampFailureRunTemplateReason             = "TemplateStampFailure"
 	FailedToListCreatedObjectsReason                  = "FailedToListCreatedObjects"
+	RunTimedOutRunTemplateReason                      = "RunTimedOut"
+	RetryBackoffRunTemplateReason                     = "RetryBackoff"
+	InterceptorFailureRunTemplateReason               = "InterceptorFailure"
+	InvalidInputsRunTemplateReason                    = "InvalidInputs"
+	CannotListCreatedObjectsRunTemplateReason         = "CannotListCreatedObjects"
+	CannotCreateObjectRunTemplateReason               = "CannotCreateObject"
+	PermissionDeniedRunTemplateReason                 = "PermissionDenied"
+	KindNotAllowedRunTemplateReason                   = "KindNotAllowed"
+	MissingAPIDependencyRunTemplateReason             = "MissingAPIDependency"
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 
 type Pipeline struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -51,19 +76,93 @@ type PipelineStatus struct {
 	ObservedGeneration int64                           `json:"observedGeneration,omitempty"`
 	Conditions         []metav1.Condition              `json:"conditions,omitempty"`
 	Outputs            map[string]apiextensionsv1.JSON `json:"outputs,omitempty"`
+	// RunHistory lists the most recently stamped runs, newest first.
+	RunHistory []RunRecord `json:"runHistory,omitempty"`
+}
+
+type RunRecord struct {
+	Name           string       `json:"name"`
+	StartTime      metav1.Time  `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Result is one of Succeeded, Failed or Running.
+	Result string `json:"result"`
+	// OutputsDigest is the sha256 digest of the outputs read from the run,
+	// empty when none could be read.
+	OutputsDigest string `json:"outputsDigest,omitempty"`
+	// APIVersion and Kind are those of the run, so that the runs of a
+	// deleted pipeline can be found.
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
 }
 
 type PipelineSpec struct {
 	// +kubebuilder:validation:Required
 	RunTemplateRef TemplateReference               `json:"runTemplateRef"`
 	Inputs         map[string]apiextensionsv1.JSON `json:"inputs,omitempty"`
+	// Timeout is how long the latest run may take to report success or
+	// failure before the pipeline stops waiting on it for outputs.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// RetryPolicy stamps a new run after the latest run fails. When
+	// omitted, a failed run is only replaced once the pipeline changes.
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// RetentionPolicy controls the pruning of previously stamped runs.
+	// When omitted, no runs are pruned.
+	RetentionPolicy *RetentionPolicy `json:"retentionPolicy,omitempty"`
+	// TTLSecondsAfterFinished deletes a run this many seconds after it
+	// succeeds or fails, independently of the RetentionPolicy. The latest
+	// run is kept, as the pipeline's outputs are read from it. When
+	// omitted, finished runs are not deleted on a timer.
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// TeardownPolicy is Delete, the default, to delete the runs with the
+	// pipeline, or Orphan to keep them.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	TeardownPolicy TeardownPolicy `json:"teardownPolicy,omitempty"`
 }
 
+type RetentionPolicy struct {
+	// MaxFailedRuns is the number of failed runs to keep. Older failed
+	// runs are deleted, other than the latest run.
+	// +kubebuilder:validation:Minimum=0
+	MaxFailedRuns int64 `json:"maxFailedRuns"`
+	// PruneDependents deletes the Pods and PersistentVolumeClaims owned by
+	// a failed run, directly or through the objects it creates, before
+	// deleting the run itself, rather than waiting on the run's own
+	// controller or the garbage collector to reclaim them. Only those that
+	// carry the run's identity labels, as Tekton propagates them, are
+	// pruned.
+	PruneDependents bool `json:"pruneDependents,omitempty"`
+}
+
+type RetryPolicy struct {
+	// Limit is the number of times a failed run is retried for the same
+	// generation of the pipeline.
+	// +kubebuilder:validation:Minimum=0
+	Limit int64 `json:"limit"`
+	// Backoff is how long to wait after a run fails before stamping the
+	// next attempt.
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+}
+
+// TemplateReference names a RunTemplate, or selects one by its labels.
+// Exactly one of Name or Selector must be set.
 type TemplateReference struct {
 	Kind string `json:"kind,omitempty"`
 	// +kubebuilder:validation:MinLength=1
-	Name      string `json:"name"`
+	Name      string `json:"name,omitempty"`
 	Namespace string `json:"namespace,omitempty"`
+	// Selector chooses the RunTemplate by its labels. When several
+	// RunTemplates match, the one with the highest version in its
+	// carto.run/template-version annotation is used.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// Validate checks that the reference names or selects a RunTemplate, but not both.
+func (r *TemplateReference) Validate() error {
+	if (r.Name == "") == (r.Selector == nil) {
+		return fmt.Errorf("exactly one of name or selector must be set")
+	}
+	return nil
 }
 
 // +kubebuilder:object:root=true
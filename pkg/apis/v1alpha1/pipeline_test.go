@@ -41,6 +41,38 @@ var _ = Describe("Pipeline", func() {
 			Expect(jsonValue).To(ContainSubstring("runTemplate"))
 			Expect(jsonValue).NotTo(ContainSubstring("omitempty"))
 		})
+
+		It("does not require a timeout", func() {
+			timeoutField, found := pipelineSpecType.FieldByName("Timeout")
+			Expect(found).To(BeTrue())
+			jsonValue := timeoutField.Tag.Get("json")
+			Expect(jsonValue).To(ContainSubstring("timeout"))
+			Expect(jsonValue).To(ContainSubstring("omitempty"))
+		})
+
+		It("does not require a ttlSecondsAfterFinished", func() {
+			ttlField, found := pipelineSpecType.FieldByName("TTLSecondsAfterFinished")
+			Expect(found).To(BeTrue())
+			jsonValue := ttlField.Tag.Get("json")
+			Expect(jsonValue).To(ContainSubstring("ttlSecondsAfterFinished"))
+			Expect(jsonValue).To(ContainSubstring("omitempty"))
+		})
+
+		It("does not require a retryPolicy", func() {
+			retryPolicyField, found := pipelineSpecType.FieldByName("RetryPolicy")
+			Expect(found).To(BeTrue())
+			jsonValue := retryPolicyField.Tag.Get("json")
+			Expect(jsonValue).To(ContainSubstring("retryPolicy"))
+			Expect(jsonValue).To(ContainSubstring("omitempty"))
+		})
+
+		It("does not require a retentionPolicy", func() {
+			retentionPolicyField, found := pipelineSpecType.FieldByName("RetentionPolicy")
+			Expect(found).To(BeTrue())
+			jsonValue := retentionPolicyField.Tag.Get("json")
+			Expect(jsonValue).To(ContainSubstring("retentionPolicy"))
+			Expect(jsonValue).To(ContainSubstring("omitempty"))
+		})
 	})
 
 	Describe("TemplateReference", func() {
@@ -53,12 +85,20 @@ var _ = Describe("Pipeline", func() {
 			templateReferenceType = reflect.TypeOf(templateReference)
 		})
 
-		It("requires a name", func() {
+		It("does not require a name, which may be replaced by a selector", func() {
 			nameField, found := templateReferenceType.FieldByName("Name")
 			Expect(found).To(BeTrue())
 			jsonValue := nameField.Tag.Get("json")
 			Expect(jsonValue).To(ContainSubstring("name"))
-			Expect(jsonValue).NotTo(ContainSubstring("omitempty"))
+			Expect(jsonValue).To(ContainSubstring("omitempty"))
+		})
+
+		It("does not require a selector", func() {
+			selectorField, found := templateReferenceType.FieldByName("Selector")
+			Expect(found).To(BeTrue())
+			jsonValue := selectorField.Tag.Get("json")
+			Expect(jsonValue).To(ContainSubstring("selector"))
+			Expect(jsonValue).To(ContainSubstring("omitempty"))
 		})
 
 		It("requires a kind", func() {
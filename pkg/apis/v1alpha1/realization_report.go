@@ -0,0 +1,130 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +versionName=v1alpha1
+// +groupName=carto.run
+// +kubebuilder:object:generate=true
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	DefaultRealizationReportInterval = 10 * time.Minute
+	DefaultRealizationReportLimit    = 5
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// RealizationReport summarizes the realization of every workload in the
+// cluster in its status, which is regenerated on a schedule.
+type RealizationReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              RealizationReportSpec   `json:"spec,omitempty"`
+	Status            RealizationReportStatus `json:"status,omitempty"`
+}
+
+type RealizationReportSpec struct {
+	// Interval is how often the report is generated. Defaults to 10m.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Limit is the most entries listed in each ranking of the report.
+	// Defaults to 5.
+	// +kubebuilder:validation:Minimum=0
+	Limit int `json:"limit,omitempty"`
+}
+
+// ReportInterval is how often the report is generated.
+func (r *RealizationReport) ReportInterval() time.Duration {
+	if r.Spec.Interval == nil || r.Spec.Interval.Duration <= 0 {
+		return DefaultRealizationReportInterval
+	}
+	return r.Spec.Interval.Duration
+}
+
+// ReportLimit is the most entries listed in each ranking of the report.
+func (r *RealizationReport) ReportLimit() int {
+	if r.Spec.Limit <= 0 {
+		return DefaultRealizationReportLimit
+	}
+	return r.Spec.Limit
+}
+
+type RealizationReportStatus struct {
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ReportTime is when the report was last generated.
+	ReportTime *metav1.Time `json:"reportTime,omitempty"`
+	// Workloads is the number of workloads in the cluster.
+	Workloads int `json:"workloads,omitempty"`
+	// WorkloadsByCondition counts the workloads by the status and reason
+	// of their Ready condition, most common first.
+	WorkloadsByCondition []ConditionCount `json:"workloadsByCondition,omitempty"`
+	// FailingTemplates ranks the components whose templates fail for the
+	// most workloads.
+	FailingTemplates []ComponentCount `json:"failingTemplates,omitempty"`
+	// SlowestComponents ranks the components the most workloads are
+	// waiting on.
+	SlowestComponents []ComponentCount `json:"slowestComponents,omitempty"`
+	// StuckWorkloads lists the workloads that have gone unrealized the
+	// longest, oldest first.
+	StuckWorkloads []StuckWorkload `json:"stuckWorkloads,omitempty"`
+}
+
+type ConditionCount struct {
+	Status    metav1.ConditionStatus `json:"status"`
+	Reason    string                 `json:"reason,omitempty"`
+	Workloads int                    `json:"workloads"`
+}
+
+type ComponentCount struct {
+	SupplyChain  string `json:"supplyChain"`
+	Component    string `json:"component"`
+	TemplateKind string `json:"templateKind,omitempty"`
+	// TemplateName is empty for a component that chooses among options.
+	TemplateName string `json:"templateName,omitempty"`
+	Workloads    int    `json:"workloads"`
+}
+
+type StuckWorkload struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	SupplyChain string `json:"supplyChain,omitempty"`
+	// Component is the first component the workload is waiting on, or
+	// that failed.
+	Component string `json:"component,omitempty"`
+	// Since is when the current generation of the workload was first
+	// observed.
+	Since metav1.Time `json:"since"`
+}
+
+// +kubebuilder:object:root=true
+
+type RealizationReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RealizationReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&RealizationReport{},
+		&RealizationReportList{},
+	)
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// UnknownConditionReason is set on a top level condition before any of the
+// conditions it summarizes have been reported.
+const UnknownConditionReason = "Unknown"
+
+// Reasons is the catalog of every reason cartographer sets on a condition.
+// Alerting rules are built on these values, so a released reason may be
+// added to but never renamed or removed. The catalog is checked against the
+// reason constants of this package, and against the released reasons in
+// testdata/reasons.txt, by the package tests.
+var Reasons = []string{
+	UnknownConditionReason,
+	ReadyTemplatesReadyReason,
+	NotFoundTemplatesReadyReason,
+	ResolvedExtensionResolvedReason,
+	InvalidExtensionResolvedReason,
+	NotFoundRunTemplateReason,
+	StampedObjectRejectedByAPIServerRunTemplateReason,
+	StampedObjectRejectedByDryRunRunTemplateReason,
+	OutputPathNotSatisfiedRunTemplateReason,
+	TemplateStampFailureRunTemplateReason,
+	FailedToListCreatedObjectsReason,
+	RunTimedOutRunTemplateReason,
+	RetryBackoffRunTemplateReason,
+	InterceptorFailureRunTemplateReason,
+	InvalidInputsRunTemplateReason,
+	WorkloadLabelsMissingSupplyChainReason,
+	NotFoundSupplyChainReadyReason,
+	MultipleMatchesSupplyChainReadyReason,
+	DefaultUsedSupplyChainReadyReason,
+	NotReadySupplyChainReason,
+	InvalidExtensionSupplyChainReason,
+	CompleteComponentsSubmittedReason,
+	TemplateObjectRetrievalFailureComponentsSubmittedReason,
+	MissingValueAtPathComponentsSubmittedReason,
+	TemplateRejectedByAPIServerComponentsSubmittedReason,
+	TemplateRejectedByDryRunComponentsSubmittedReason,
+	InvalidStampedObjectComponentsSubmittedReason,
+	UnknownErrorComponentsSubmittedReason,
+	ArtifactResolutionFailureComponentsSubmittedReason,
+	NoMatchingTemplateOptionComponentsSubmittedReason,
+	AmbiguousTemplateOptionsComponentsSubmittedReason,
+	CannotListCreatedObjectsComponentsSubmittedReason,
+	CannotCreateObjectComponentsSubmittedReason,
+	CannotPatchObjectComponentsSubmittedReason,
+	PermissionDeniedComponentsSubmittedReason,
+	KindNotAllowedComponentsSubmittedReason,
+	GitWriteFailureComponentsSubmittedReason,
+	ArtifactPublishFailureComponentsSubmittedReason,
+	ImmutableParamOverriddenComponentsSubmittedReason,
+	HookFailureComponentsSubmittedReason,
+	PreHookPendingComponentsSubmittedReason,
+	ReadinessGatePendingComponentsSubmittedReason,
+	InvalidWorkloadParamsComponentsSubmittedReason,
+	SecretParamUnavailableComponentsSubmittedReason,
+	MissingAPIDependencyComponentsSubmittedReason,
+	WithinDeadlineRealizationDeadlineReason,
+	CreatedWorkloadCreatedReason,
+	WorkloadNotFoundWorkloadCreatedReason,
+	NoGitSourceWorkloadCreatedReason,
+	WorkloadRejectedByAPIServerWorkloadCreatedReason,
+	ExceededRealizationDeadlineReason,
+	RequestedPausedReason,
+	AlwaysHealthyHealthyReason,
+	SingleConditionTypeHealthyReason,
+	MatchedConditionHealthyReason,
+	MatchedFieldHealthyReason,
+	NoMatchesFulfilledHealthyReason,
+	OutputsAvailableHealthyReason,
+	OutputsNotAvailableHealthyReason,
+	NoStampedObjectHealthyReason,
+	TektonRunHealthyReason,
+	KappAppHealthyReason,
+	KnativeServiceHealthyReason,
+	FluxKustomizationHealthyReason,
+	FluxGitRepositoryHealthyReason,
+	StabilizingHealthyReason,
+	FrequentChangesHealthFlappingReason,
+	AllHealthyResourcesHealthyReason,
+	UnhealthyResourceResourcesHealthyReason,
+	HealthUnknownResourceResourcesHealthyReason,
+	UnhealthyComponentReadyReason,
+	HealthUnknownComponentReadyReason,
+	FailedComponentReadyReason,
+	WaitingComponentReadyReason,
+	BlockedComponentReadyReason,
+	RenderedTemplateRenderedReason,
+	FailedTemplateRenderedReason,
+	InvalidContextRenderedReason,
+}
+
+// IsCataloguedReason reports whether reason is in the Reasons catalog.
+func IsCataloguedReason(reason string) bool {
+	for _, catalogued := range Reasons {
+		if catalogued == reason {
+			return true
+		}
+	}
+	return false
+}
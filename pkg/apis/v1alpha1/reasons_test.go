@@ -0,0 +1,82 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+var _ = Describe("Reasons", func() {
+	It("catalogues every reason constant", func() {
+		packages, err := parser.ParseDir(token.NewFileSet(), ".", nil, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		var declared []string
+		ast.Inspect(packages["v1alpha1"], func(node ast.Node) bool {
+			spec, ok := node.(*ast.ValueSpec)
+			if !ok {
+				return true
+			}
+			for i, name := range spec.Names {
+				if !strings.HasSuffix(name.Name, "Reason") || i >= len(spec.Values) {
+					continue
+				}
+				literal, ok := spec.Values[i].(*ast.BasicLit)
+				if !ok || literal.Kind != token.STRING {
+					continue
+				}
+				value, err := strconv.Unquote(literal.Value)
+				Expect(err).NotTo(HaveOccurred())
+				declared = append(declared, value)
+			}
+			return true
+		})
+
+		Expect(declared).NotTo(BeEmpty())
+		for _, reason := range declared {
+			Expect(v1alpha1.IsCataloguedReason(reason)).To(BeTrue(), "reason %q is not in v1alpha1.Reasons", reason)
+		}
+	})
+
+	It("keeps every released reason", func() {
+		contents, err := ioutil.ReadFile("testdata/reasons.txt")
+		Expect(err).NotTo(HaveOccurred())
+		released := strings.Fields(string(contents))
+
+		catalogued := map[string]bool{}
+		for _, reason := range v1alpha1.Reasons {
+			catalogued[reason] = true
+		}
+		var sortedCatalogue []string
+		for reason := range catalogued {
+			sortedCatalogue = append(sortedCatalogue, reason)
+		}
+		sort.Strings(sortedCatalogue)
+
+		Expect(sortedCatalogue).To(Equal(released),
+			"a released reason was removed, or a new reason is missing from testdata/reasons.txt")
+	})
+})
@@ -19,10 +19,23 @@
 package v1alpha1
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+// TemplateVersionAnnotation holds the version of a RunTemplate, used to
+// choose between RunTemplates that match the same selector.
+const TemplateVersionAnnotation = "carto.run/template-version"
+
 // +kubebuilder:object:root=true
 
 type RunTemplate struct {
@@ -34,7 +47,175 @@ type RunTemplate struct {
 type RunTemplateSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Template runtime.RawExtension `json:"template"`
-	Outputs  map[string]string    `json:"outputs,omitempty"`
+	// Outputs are jsonpaths into the stamped object, e.g.
+	// status.results[0].value, by the name of the output they provide.
+	Outputs map[string]string `json:"outputs,omitempty"`
+	// TektonResults are outputs read from the results of a Tekton
+	// PipelineRun or TaskRun, by the name of the output they provide, e.g.
+	// url: image-url. A result is found by name in status.results, or in
+	// status.pipelineResults and status.taskResults of v1beta1 runs.
+	TektonResults map[string]string `json:"tektonResults,omitempty"`
+	// Inputs declares the inputs a pipeline may provide. When omitted, the
+	// pipeline's inputs are not validated.
+	Inputs []RunTemplateInput `json:"inputs,omitempty"`
+	// Sample is what the template is stamped for when it is created or
+	// updated. A template that fails to stamp for its sample is rejected.
+	Sample *RunTemplateSample `json:"sample,omitempty"`
+}
+
+// RunTemplateSample is a sample of what a RunTemplate is stamped for.
+type RunTemplateSample struct {
+	// Pipeline is a sample of the pipelines the template is stamped for.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Pipeline runtime.RawExtension `json:"pipeline"`
+}
+
+type RunTemplateInput struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Type is the JSON type the input's value must have. When omitted, any
+	// value is accepted.
+	// +kubebuilder:validation:Enum=string;number;integer;boolean;object;array
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+var _ webhook.Validator = &RunTemplate{}
+
+func (t *RunTemplate) ValidateCreate() error {
+	return t.Spec.validate()
+}
+
+func (t *RunTemplate) ValidateUpdate(_ runtime.Object) error {
+	return t.Spec.validate()
+}
+
+func (t *RunTemplate) ValidateDelete() error {
+	return nil
+}
+
+func (s *RunTemplateSpec) validate() error {
+	if err := validateTemplateSyntax(s.Template.Raw); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return s.validateOutputs()
+}
+
+// templatingContextRoots are the fields of the context a run template is
+// stamped with. An output path starting with one of them is a mistake: it is
+// evaluated against the stamped object.
+var templatingContextRoots = []string{"pipeline", "clusterContext"}
+
+// validateOutputs checks that the path of each output parses as a jsonpath
+// into the stamped object, naming the first offending output.
+func (s *RunTemplateSpec) validateOutputs() error {
+	var names []string
+	for name := range s.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := validateOutputPath(s.Outputs[name]); err != nil {
+			return fmt.Errorf("invalid path of output '%s': %w", name, err)
+		}
+	}
+
+	names = nil
+	for name := range s.TektonResults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if strings.TrimSpace(s.TektonResults[name]) == "" {
+			return fmt.Errorf("invalid Tekton result of output '%s': result name is empty", name)
+		}
+		if _, ok := s.Outputs[name]; ok {
+			return fmt.Errorf("output '%s' is both a path and a Tekton result", name)
+		}
+	}
+	return nil
+}
+
+func validateOutputPath(path string) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return errors.New("path is empty")
+	}
+
+	expression := wrapJSONPath(path)
+	if _, err := jsonpath.Parse("output", expression); err != nil {
+		return fmt.Errorf("parse '%s': %w", path, err)
+	}
+
+	root := strings.TrimPrefix(expression, "{.")
+	if end := strings.IndexAny(root, ".[}"); end >= 0 {
+		root = root[:end]
+	}
+	for _, contextRoot := range templatingContextRoots {
+		if root == contextRoot {
+			return fmt.Errorf("'%s' is not a field of the stamped object: output paths start at its root, e.g. status.results", root)
+		}
+	}
+	return nil
+}
+
+// ValidateInputs checks the inputs of a pipeline against those declared by
+// the run template, naming the first offending key.
+func (s *RunTemplateSpec) ValidateInputs(inputs map[string]apiextensionsv1.JSON) error {
+	if len(s.Inputs) == 0 {
+		return nil
+	}
+
+	declared := map[string]RunTemplateInput{}
+	for _, input := range s.Inputs {
+		declared[input.Name] = input
+		if _, ok := inputs[input.Name]; input.Required && !ok {
+			return fmt.Errorf("input '%s' is required", input.Name)
+		}
+	}
+
+	var keys []string
+	for key := range inputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		input, ok := declared[key]
+		if !ok {
+			return fmt.Errorf("input '%s' is not declared by the run template", key)
+		}
+
+		if input.Type != "" && !hasJSONType(inputs[key], input.Type) {
+			return fmt.Errorf("input '%s' must be of type %s", key, input.Type)
+		}
+	}
+
+	return nil
+}
+
+func hasJSONType(value apiextensionsv1.JSON, jsonType string) bool {
+	var decoded interface{}
+	if err := json.Unmarshal(value.Raw, &decoded); err != nil {
+		return false
+	}
+
+	switch typedValue := decoded.(type) {
+	case string:
+		return jsonType == "string"
+	case float64:
+		return jsonType == "number" || (jsonType == "integer" && typedValue == float64(int64(typedValue)))
+	case bool:
+		return jsonType == "boolean"
+	case map[string]interface{}:
+		return jsonType == "object"
+	case []interface{}:
+		return jsonType == "array"
+	default:
+		return false
+	}
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,155 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+var _ = Describe("RunTemplate", func() {
+	Describe("ValidateInputs", func() {
+		var spec v1alpha1.RunTemplateSpec
+
+		BeforeEach(func() {
+			spec = v1alpha1.RunTemplateSpec{
+				Inputs: []v1alpha1.RunTemplateInput{
+					{Name: "url", Type: "string", Required: true},
+					{Name: "replicas", Type: "integer"},
+					{Name: "extra"},
+				},
+			}
+		})
+
+		raw := func(value string) apiextensionsv1.JSON {
+			return apiextensionsv1.JSON{Raw: []byte(value)}
+		}
+
+		It("accepts any inputs when none are declared", func() {
+			spec.Inputs = nil
+			Expect(spec.ValidateInputs(map[string]apiextensionsv1.JSON{"anything": raw(`1`)})).To(Succeed())
+		})
+
+		It("accepts inputs matching the declaration", func() {
+			Expect(spec.ValidateInputs(map[string]apiextensionsv1.JSON{
+				"url":      raw(`"https://example.com"`),
+				"replicas": raw(`3`),
+				"extra":    raw(`{"any": ["thing"]}`),
+			})).To(Succeed())
+		})
+
+		It("rejects a missing required input", func() {
+			Expect(spec.ValidateInputs(map[string]apiextensionsv1.JSON{
+				"replicas": raw(`3`),
+			})).To(MatchError("input 'url' is required"))
+		})
+
+		It("rejects an undeclared input", func() {
+			Expect(spec.ValidateInputs(map[string]apiextensionsv1.JSON{
+				"url":      raw(`"https://example.com"`),
+				"replicaz": raw(`3`),
+			})).To(MatchError("input 'replicaz' is not declared by the run template"))
+		})
+
+		DescribeTable("type checking",
+			func(jsonType string, value string, valid bool) {
+				spec.Inputs = []v1alpha1.RunTemplateInput{{Name: "in", Type: jsonType}}
+				err := spec.ValidateInputs(map[string]apiextensionsv1.JSON{"in": raw(value)})
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(MatchError("input 'in' must be of type " + jsonType))
+				}
+			},
+			Entry("string", "string", `"a"`, true),
+			Entry("not a string", "string", `1`, false),
+			Entry("number", "number", `1.5`, true),
+			Entry("integer", "integer", `2`, true),
+			Entry("not an integer", "integer", `2.5`, false),
+			Entry("boolean", "boolean", `true`, true),
+			Entry("object", "object", `{}`, true),
+			Entry("array", "array", `[]`, true),
+			Entry("not an array", "array", `null`, false),
+		)
+	})
+
+	Describe("ValidateCreate", func() {
+		DescribeTable("output paths",
+			func(path string, expectedError string) {
+				template := &v1alpha1.RunTemplate{Spec: v1alpha1.RunTemplateSpec{
+					Template: runtime.RawExtension{Raw: []byte(`{}`)},
+					Outputs:  map[string]string{"url": "status.url", "result": path},
+				}}
+				err := template.ValidateCreate()
+				if expectedError == "" {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(MatchError(ContainSubstring("invalid path of output 'result': " + expectedError)))
+				}
+			},
+			Entry("a field", "status.results[0].value", ""),
+			Entry("a field starting with a dot", ".status.outputs.revision", ""),
+			Entry("a wrapped path", "{.status.conditions[?(@.type==\"Succeeded\")].status}", ""),
+			Entry("an empty path", "  ", "path is empty"),
+			Entry("an unclosed index", "status.results[0", "parse 'status.results[0'"),
+			Entry("a path into the pipeline", "pipeline.spec.inputs.url", "'pipeline' is not a field of the stamped object"),
+			Entry("a path into the cluster context", "clusterContext.registry", "'clusterContext' is not a field of the stamped object"),
+		)
+
+		It("validates the output paths on update too", func() {
+			template := &v1alpha1.RunTemplate{Spec: v1alpha1.RunTemplateSpec{
+				Template: runtime.RawExtension{Raw: []byte(`{}`)},
+				Outputs:  map[string]string{"revision": ""},
+			}}
+			Expect(template.ValidateUpdate(nil)).To(MatchError("invalid path of output 'revision': path is empty"))
+		})
+
+		It("rejects a Tekton result without a name", func() {
+			template := &v1alpha1.RunTemplate{Spec: v1alpha1.RunTemplateSpec{
+				Template:      runtime.RawExtension{Raw: []byte(`{}`)},
+				TektonResults: map[string]string{"url": " "},
+			}}
+			Expect(template.ValidateCreate()).To(MatchError("invalid Tekton result of output 'url': result name is empty"))
+		})
+
+		It("rejects an output that is both a path and a Tekton result", func() {
+			template := &v1alpha1.RunTemplate{Spec: v1alpha1.RunTemplateSpec{
+				Template:      runtime.RawExtension{Raw: []byte(`{}`)},
+				Outputs:       map[string]string{"url": "status.results[0].value"},
+				TektonResults: map[string]string{"url": "image-url"},
+			}}
+			Expect(template.ValidateCreate()).To(MatchError("output 'url' is both a path and a Tekton result"))
+		})
+
+		It("rejects an empty template", func() {
+			template := &v1alpha1.RunTemplate{}
+			Expect(template.ValidateCreate()).To(MatchError("invalid template: template is empty"))
+		})
+
+		It("rejects expressions in the template that do not parse", func() {
+			template := &v1alpha1.RunTemplate{Spec: v1alpha1.RunTemplateSpec{
+				Template: runtime.RawExtension{Raw: []byte(`{"spec": {"params": [{"name": "url", "value": "url=$(pipeline.spec.inputs[url)$"}]}}`)},
+			}}
+			Expect(template.ValidateCreate()).To(MatchError(HavePrefix(
+				"invalid template: template.spec.params[0].value: expression 'pipeline.spec.inputs[url' at column 5 does not parse",
+			)))
+		})
+	})
+})
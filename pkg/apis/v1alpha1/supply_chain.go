@@ -0,0 +1,83 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +versionName=v1alpha1
+// +groupName=carto.run
+// +kubebuilder:object:generate=true
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// SupplyChain is a namespace-scoped ClusterSupplyChain, which application
+// teams may define without cluster-wide permissions. It selects workloads in
+// its own namespace only, and the templates it refers to are looked up in
+// its namespace before falling back to the cluster-scoped templates.
+type SupplyChain struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              SupplyChainSpec   `json:"spec"`
+	Status            SupplyChainStatus `json:"status,omitempty"`
+}
+
+// AsClusterSupplyChain returns the supply chain in the form the controllers
+// work with. The namespace is kept, which is how a namespaced supply chain
+// is told apart from a ClusterSupplyChain.
+func (s *SupplyChain) AsClusterSupplyChain() *ClusterSupplyChain {
+	return &ClusterSupplyChain{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "SupplyChain",
+		},
+		ObjectMeta: *s.ObjectMeta.DeepCopy(),
+		Spec:       *s.Spec.DeepCopy(),
+		Status:     *s.Status.DeepCopy(),
+	}
+}
+
+var _ webhook.Validator = &SupplyChain{}
+
+func (s *SupplyChain) ValidateCreate() error {
+	return s.AsClusterSupplyChain().validateNewState()
+}
+
+func (s *SupplyChain) ValidateUpdate(_ runtime.Object) error {
+	return s.AsClusterSupplyChain().validateNewState()
+}
+
+func (s *SupplyChain) ValidateDelete() error {
+	return nil
+}
+
+// +kubebuilder:object:root=true
+
+type SupplyChainList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SupplyChain `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&SupplyChain{},
+		&SupplyChainList{},
+	)
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+var _ = Describe("SupplyChain", func() {
+	var supplyChain *v1alpha1.SupplyChain
+
+	BeforeEach(func() {
+		supplyChain = &v1alpha1.SupplyChain{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "team-supply-chain",
+				Namespace: "team-ns",
+			},
+			Spec: v1alpha1.SupplyChainSpec{
+				Selector: map[string]string{"app.tanzu.vmware.com/workload-type": "web"},
+				Components: []v1alpha1.SupplyChainComponent{
+					{
+						Name: "some-component",
+						TemplateRef: v1alpha1.ClusterTemplateReference{
+							Kind: "ClusterTemplate",
+							Name: "some-template",
+						},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("AsClusterSupplyChain", func() {
+		It("keeps the namespace, name and spec of the supply chain", func() {
+			clusterSupplyChain := supplyChain.AsClusterSupplyChain()
+			Expect(clusterSupplyChain.Kind).To(Equal("SupplyChain"))
+			Expect(clusterSupplyChain.Namespace).To(Equal("team-ns"))
+			Expect(clusterSupplyChain.Name).To(Equal("team-supply-chain"))
+			Expect(clusterSupplyChain.Spec).To(Equal(supplyChain.Spec))
+		})
+	})
+
+	Describe("Webhook Validation", func() {
+		It("accepts a well formed supply chain", func() {
+			Expect(supplyChain.ValidateCreate()).To(Succeed())
+			Expect(supplyChain.ValidateUpdate(nil)).To(Succeed())
+		})
+
+		It("validates the supply chain as a cluster supply chain does", func() {
+			supplyChain.Spec.Components = append(supplyChain.Spec.Components, supplyChain.Spec.Components[0])
+			Expect(supplyChain.ValidateCreate()).To(MatchError(
+				"duplicate component name 'some-component' found in clustersupplychain 'team-supply-chain'",
+			))
+		})
+
+		It("accepts a component stamping its object into the namespace of the supply chain", func() {
+			supplyChain.Spec.Components[0].Namespace = "team-ns"
+			Expect(supplyChain.ValidateCreate()).To(Succeed())
+		})
+
+		It("rejects a component stamping its object into another namespace", func() {
+			supplyChain.Spec.Components[0].Namespace = "$(params.namespace)$"
+			Expect(supplyChain.ValidateCreate()).To(MatchError(
+				"component 'some-component' may not stamp its object outside the namespace of the supply chain, 'team-ns'",
+			))
+		})
+
+		It("rejects a service account of another namespace", func() {
+			supplyChain.Spec.ServiceAccountRef = &v1alpha1.ServiceAccountRef{Name: "stamper", Namespace: "cartographer-system"}
+			Expect(supplyChain.ValidateCreate()).To(MatchError(
+				"service account 'cartographer-system/stamper' is not in the namespace of the supply chain, 'team-ns'",
+			))
+		})
+
+		It("always allows deletion", func() {
+			Expect(supplyChain.ValidateDelete()).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,85 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +versionName=v1alpha1
+// +groupName=carto.run
+// +kubebuilder:object:generate=true
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	TemplatePlaygroundReady    = "Ready"
+	TemplatePlaygroundRendered = "Rendered"
+)
+
+const (
+	RenderedTemplateRenderedReason = "TemplateRendered"
+	FailedTemplateRenderedReason   = "TemplateRenderFailed"
+	InvalidContextRenderedReason   = "InvalidContext"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TemplatePlayground renders a template against a context on every edit,
+// writing the stamped object or the error to its status. It lets template
+// authors try a template in the cluster without local tooling, and stamps
+// nothing.
+type TemplatePlayground struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              TemplatePlaygroundSpec   `json:"spec"`
+	Status            TemplatePlaygroundStatus `json:"status,omitempty"`
+}
+
+type TemplatePlaygroundSpec struct {
+	// The template, ytt and params are those of a ClusterTemplate. Its
+	// sample is not stamped: the template is rendered against Context.
+	TemplateSpec `json:",inline"`
+	// Context is what the template may refer to, as it would be for a
+	// component of a supply chain: workload, params, sources, images,
+	// configs, source, image and config. When it has no params, the
+	// defaults of the template's params are used.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Context *runtime.RawExtension `json:"context,omitempty"`
+}
+
+type TemplatePlaygroundStatus struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	// Result is the object the template stamped for the latest generation,
+	// without the owner references a supply chain would add.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Result *runtime.RawExtension `json:"result,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type TemplatePlaygroundList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TemplatePlayground `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&TemplatePlayground{},
+		&TemplatePlaygroundList{},
+	)
+}
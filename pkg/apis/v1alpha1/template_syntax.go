@@ -0,0 +1,149 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// validateTemplateSyntax checks that a template parses, and so does every
+// $(...)$ expression in its strings, telling where they do not.
+func validateTemplateSyntax(raw []byte) error {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return errors.New("template is empty")
+	}
+
+	var template interface{}
+	if err := json.Unmarshal(raw, &template); err != nil {
+		var syntaxError *json.SyntaxError
+		if errors.As(err, &syntaxError) {
+			line, column := position(raw, syntaxError.Offset)
+			return fmt.Errorf("line %d, column %d: %w", line, column, err)
+		}
+		return err
+	}
+
+	return validateExpressions(template, "template")
+}
+
+// position returns the line and column, from 1, of the byte the JSON
+// decoder stopped at after reading offset bytes.
+func position(raw []byte, offset int64) (int, int) {
+	index := int(offset) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index > len(raw) {
+		index = len(raw)
+	}
+
+	before := raw[:index]
+	return bytes.Count(before, []byte("\n")) + 1, index - bytes.LastIndexByte(before, '\n')
+}
+
+func validateExpressions(value interface{}, path string) error {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		var keys []string
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if err := validateExpressions(typed[key], path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, element := range typed {
+			if err := validateExpressions(element, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case string:
+		if err := validateExpressionsIn(typed); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// validateExpressionsIn parses the $(...)$ expressions of a string as the
+// stamper finds them: a "$(" without a closing ")$" is left as it is.
+func validateExpressionsIn(value string) error {
+	offset := 0
+	for {
+		start := strings.Index(value[offset:], "$(")
+		if start < 0 {
+			return nil
+		}
+		start += offset
+
+		end := strings.Index(value[start+2:], ")$")
+		if end < 0 {
+			return nil
+		}
+		end += start + 2
+
+		expression := value[start+2 : end]
+		if strings.TrimSpace(expression) == "" {
+			return fmt.Errorf("expression at column %d is empty", start+1)
+		}
+		if _, err := jsonpath.Parse("expression", wrapJSONPath(expression)); err != nil {
+			return fmt.Errorf("expression '%s' at column %d does not parse: %w", expression, start+1, err)
+		}
+
+		offset = end + 2
+	}
+}
+
+// wrapJSONPath wraps a path into a jsonpath expression, as the path of an
+// expression or output is when it is evaluated.
+func wrapJSONPath(path string) string {
+	expression := path
+	if !strings.HasPrefix(expression, "{.") {
+		expression = "{." + strings.TrimPrefix(expression, ".")
+	}
+	if !strings.HasSuffix(expression, "}") {
+		expression += "}"
+	}
+	return expression
+}
+
+// validateYttSyntax checks that each document of a ytt template parses as
+// YAML, which ytt templates are, their annotations being comments.
+func validateYttSyntax(ytt string) error {
+	decoder := yaml.NewDecoder(strings.NewReader(ytt))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -28,6 +28,36 @@ const (
 	WorkloadReady               = "Ready"
 	WorkloadSupplyChainReady    = "SupplyChainReady"
 	WorkloadComponentsSubmitted = "ComponentsSubmitted"
+	// WorkloadRealizationDeadlineExceeded is only reported for workloads
+	// with a maxDuration.
+	WorkloadRealizationDeadlineExceeded = "RealizationDeadlineExceeded"
+	// WorkloadPaused is only reported while the workload is paused.
+	WorkloadPaused = "Paused"
+	// WorkloadResourcesHealthy rolls up the Healthy conditions of the
+	// components.
+	WorkloadResourcesHealthy = "ResourcesHealthy"
+)
+
+const (
+	RealizedComponentState = "Realized"
+	WaitingComponentState  = "Waiting"
+	FailedComponentState   = "Failed"
+	BlockedComponentState  = "Blocked"
+)
+
+// ComponentReady is the type of the condition reporting whether a component
+// is realized and its stamped object healthy. When the component failed the
+// realization of the workload, its reason is that of the ComponentsSubmitted
+// condition, which names the step that failed.
+const ComponentReady = "Ready"
+
+const (
+	ReadyComponentReadyReason         = "Ready"
+	UnhealthyComponentReadyReason     = "Unhealthy"
+	HealthUnknownComponentReadyReason = "HealthUnknown"
+	FailedComponentReadyReason        = "Failed"
+	WaitingComponentReadyReason       = "Waiting"
+	BlockedComponentReadyReason       = "Blocked"
 )
 
 const (
@@ -35,7 +65,9 @@ const (
 	WorkloadLabelsMissingSupplyChainReason = "WorkloadLabelsMissing"
 	NotFoundSupplyChainReadyReason         = "SupplyChainNotFound"
 	MultipleMatchesSupplyChainReadyReason  = "MultipleSupplyChainMatches"
+	DefaultUsedSupplyChainReadyReason      = "DefaultSupplyChainUsed"
 	NotReadySupplyChainReason              = "SupplyChainNotReady"
+	InvalidExtensionSupplyChainReason      = "SupplyChainExtensionInvalid"
 )
 
 const (
@@ -44,11 +76,74 @@ const (
 	MissingValueAtPathComponentsSubmittedReason             = "MissingValueAtPath"
 	TemplateStampFailureComponentsSubmittedReason           = "TemplateStampFailure"
 	TemplateRejectedByAPIServerComponentsSubmittedReason    = "TemplateRejectedByAPIServer"
+	TemplateRejectedByDryRunComponentsSubmittedReason       = "TemplateRejectedByDryRun"
+	InvalidStampedObjectComponentsSubmittedReason           = "InvalidStampedObject"
 	UnknownErrorComponentsSubmittedReason                   = "UnknownError"
+	InterceptorFailureComponentsSubmittedReason             = "InterceptorFailure"
+	ArtifactResolutionFailureComponentsSubmittedReason      = "ArtifactResolutionFailure"
+	NoMatchingTemplateOptionComponentsSubmittedReason       = "NoMatchingTemplateOption"
+	AmbiguousTemplateOptionsComponentsSubmittedReason       = "AmbiguousTemplateOptions"
+	CannotListCreatedObjectsComponentsSubmittedReason       = "CannotListCreatedObjects"
+	CannotCreateObjectComponentsSubmittedReason             = "CannotCreateObject"
+	CannotPatchObjectComponentsSubmittedReason              = "CannotPatchObject"
+	PermissionDeniedComponentsSubmittedReason               = "PermissionDenied"
+	KindNotAllowedComponentsSubmittedReason                 = "KindNotAllowed"
+	GitWriteFailureComponentsSubmittedReason                = "GitWriteFailure"
+	ArtifactPublishFailureComponentsSubmittedReason         = "ArtifactPublishFailure"
+	ImmutableParamOverriddenComponentsSubmittedReason       = "ImmutableParamOverridden"
+	HookFailureComponentsSubmittedReason                    = "HookFailure"
+	PreHookPendingComponentsSubmittedReason                 = "PreHookPending"
+	ReadinessGatePendingComponentsSubmittedReason           = "ReadinessGatePending"
+	InvalidWorkloadParamsComponentsSubmittedReason          = "InvalidWorkloadParams"
+	SecretParamUnavailableComponentsSubmittedReason         = "SecretParamUnavailable"
+	MissingAPIDependencyComponentsSubmittedReason           = "MissingAPIDependency"
+)
+
+const (
+	WithinDeadlineRealizationDeadlineReason = "WithinDeadline"
+	ExceededRealizationDeadlineReason       = "DeadlineExceeded"
+)
+
+const (
+	RequestedPausedReason = "PauseRequested"
+)
+
+const (
+	AllHealthyResourcesHealthyReason            = "AllHealthy"
+	UnhealthyResourceResourcesHealthyReason     = "ResourceUnhealthy"
+	HealthUnknownResourceResourcesHealthyReason = "ResourceHealthUnknown"
+)
+
+// OrderedTeardownFinalizer holds the deletion of a workload with an ordered
+// teardown until its stamped objects have been deleted in order.
+const OrderedTeardownFinalizer = "carto.run/ordered-teardown"
+
+// TeardownFinalizer holds the deletion of a workload without an ordered
+// teardown, or of a pipeline, until its stamped objects have been deleted or
+// orphaned, as its teardown policy chooses.
+const TeardownFinalizer = "carto.run/teardown"
+
+// TeardownPolicy chooses what becomes of the objects stamped for a workload
+// or a pipeline when it is deleted.
+type TeardownPolicy string
+
+const (
+	// DeleteTeardownPolicy deletes the stamped objects the owner owns,
+	// including those stamped into another namespace or cluster scoped,
+	// which the garbage collector does not delete with it.
+	DeleteTeardownPolicy TeardownPolicy = "Delete"
+	// OrphanTeardownPolicy keeps the stamped objects, removing the owner's
+	// references from them so that the garbage collector keeps them too.
+	OrphanTeardownPolicy TeardownPolicy = "Orphan"
 )
 
+// ResyncIntervalAnnotation overrides how often a workload is reconciled
+// again when nothing about it changed, as a duration such as 1m.
+const ResyncIntervalAnnotation = "carto.run/resync-interval"
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 
 type Workload struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -77,6 +172,40 @@ type WorkloadSpec struct {
 	ServiceClaims []WorkloadServiceClaim       `json:"serviceClaims,omitempty"`
 	Env           []corev1.EnvVar              `json:"env,omitempty"`
 	Resources     *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// MaxDuration is how long the supply chain may take to realize the
+	// workload after a change to its spec. A workload that is not realized
+	// in time reports the RealizationDeadlineExceeded condition.
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+	// Paused suspends the delivery of the workload until it is unset, e.g.
+	// during a maintenance window or an incident. The objects stamped for
+	// the workload are kept as they are: none is stamped, updated or
+	// deleted, and no output is passed on to the components consuming it.
+	// Deleting a paused workload still deletes its objects.
+	Paused bool `json:"paused,omitempty"`
+	// Teardown configures how the objects stamped for the workload are
+	// deleted with it.
+	Teardown *WorkloadTeardown `json:"teardown,omitempty"`
+	// ServiceAccountName is the service account the objects stamped for the
+	// workload run as, for templates to refer to as
+	// $(workload.spec.serviceAccountName)$. It is set to the default of the
+	// Cartographer installation when omitted.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// WorkloadTeardown configures the deletion of the objects stamped for a
+// workload. Unless it is ordered, they are all deleted at once.
+type WorkloadTeardown struct {
+	// Ordered deletes the stamped objects in the reverse of the order the
+	// supply chain realizes them in: the objects of a component are only
+	// deleted once those of every component depending on it are gone.
+	Ordered bool `json:"ordered,omitempty"`
+	// Timeout bounds how long an ordered teardown may take, after which the
+	// remaining objects are left to the garbage collector. Defaults to 5m.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// Policy is Delete, the default, to delete the stamped objects with the
+	// workload, or Orphan to keep them.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	Policy TeardownPolicy `json:"policy,omitempty"`
 }
 
 type WorkloadSource struct {
@@ -98,8 +227,24 @@ type WorkloadGitRef struct {
 }
 
 type WorkloadParam struct {
-	Name  string               `json:"name"`
-	Value apiextensionsv1.JSON `json:"value"`
+	Name string `json:"name"`
+	// Value of the param, unless it comes from ValueFrom.
+	Value apiextensionsv1.JSON `json:"value,omitempty"`
+	// ValueFrom sources the value of the param from a Secret, so that
+	// credentials need not be written into the workload.
+	ValueFrom *WorkloadParamSource `json:"valueFrom,omitempty"`
+}
+
+// WorkloadParamSource is where the value of a param comes from. The value
+// is read when the workload is realized, as the workload's service account,
+// or the default service account of its namespace, and is only available to
+// templates as the param: it is neither written into the workload nor
+// available as $(workload.spec.params)$.
+type WorkloadParamSource struct {
+	// SecretKeyRef selects a key of a Secret in the namespace of the
+	// workload, whose value becomes that of the param, as a string. An
+	// optional key that is missing leaves the param unset.
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 type WorkloadSupplyChainReference struct {
@@ -113,6 +258,130 @@ type WorkloadStatus struct {
 	ObservedGeneration int64                        `json:"observedGeneration,omitempty"`
 	Conditions         []metav1.Condition           `json:"conditions,omitempty"`
 	SupplyChainRef     WorkloadSupplyChainReference `json:"supplyChainRef,omitempty"`
+	// SupplyChainSelection explains why the supply chain was chosen when
+	// several supply chains select the workload.
+	SupplyChainSelection string `json:"supplyChainSelection,omitempty"`
+	// Components reports the progress of each component of the supply
+	// chain, in supply chain order.
+	Components []ComponentStatus `json:"components,omitempty"`
+	// Progress is the percentage of the supply chain's components that have
+	// been realized.
+	Progress int32 `json:"progress,omitempty"`
+	// RealizationStartTime is when the current generation of the workload
+	// was first observed.
+	RealizationStartTime *metav1.Time `json:"realizationStartTime,omitempty"`
+	// RealizationCompletionTime is when every component of the supply chain
+	// was first realized for the current generation of the workload.
+	RealizationCompletionTime *metav1.Time `json:"realizationCompletionTime,omitempty"`
+	// Artifacts are the latest source, image and config provided by the
+	// components of the supply chain.
+	Artifacts *WorkloadArtifacts `json:"artifacts,omitempty"`
+	// Retry reports a realization that keeps failing the same way, and when
+	// it is next retried.
+	Retry *RealizationRetry `json:"retry,omitempty"`
+}
+
+// RealizationRetry counts the consecutive realizations that failed with the
+// same reason and message. From the second one on, the realization is
+// retried after a delay that doubles with each failure, up to a cap.
+type RealizationRetry struct {
+	Failures int32  `json:"failures"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message,omitempty"`
+	// NextRetryTime is when the realization is next retried, once it is
+	// backed off.
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+}
+
+// WorkloadArtifacts are what the supply chain last built for the workload.
+// Each is provided by the last component in realization order to provide
+// one, and is kept while that component is not realized.
+type WorkloadArtifacts struct {
+	Source *SourceArtifact `json:"source,omitempty"`
+	Image  *ImageArtifact  `json:"image,omitempty"`
+	Config *ConfigArtifact `json:"config,omitempty"`
+	// Service is where the app is served, by the Knative Service of the
+	// last component to stamp one.
+	Service *ServiceArtifact `json:"service,omitempty"`
+}
+
+type ServiceArtifact struct {
+	// Component is the name of the component that stamped the service.
+	Component           string `json:"component"`
+	URL                 string `json:"url,omitempty"`
+	LatestReadyRevision string `json:"latestReadyRevision,omitempty"`
+}
+
+type SourceArtifact struct {
+	// Component is the name of the component that provided the source.
+	Component string `json:"component"`
+	URL       string `json:"url,omitempty"`
+	Revision  string `json:"revision,omitempty"`
+}
+
+type ImageArtifact struct {
+	// Component is the name of the component that provided the image.
+	Component string `json:"component"`
+	Image     string `json:"image"`
+}
+
+type ConfigArtifact struct {
+	// Component is the name of the component that provided the config.
+	Component string `json:"component"`
+	// StampedRef refers to the object the config was read from.
+	StampedRef *corev1.ObjectReference `json:"stampedRef,omitempty"`
+	// Digest is the sha256 of the config as JSON.
+	Digest string `json:"digest"`
+}
+
+type ComponentStatus struct {
+	Name string `json:"name"`
+	// State is one of Realized, Waiting, Failed or Blocked. A component is
+	// Blocked while an earlier component is Waiting or Failed.
+	State   string `json:"state"`
+	Message string `json:"message,omitempty"`
+	// StampedRef refers to the object stamped for the component, once it
+	// has been submitted.
+	StampedRef *corev1.ObjectReference `json:"stampedRef,omitempty"`
+	// TemplateRef refers to the template the component was stamped from.
+	TemplateRef *corev1.ObjectReference `json:"templateRef,omitempty"`
+	// Conditions report whether the component is Ready, and the health of
+	// the stamped object.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Outputs are the values the component last provided to the components
+	// that consume it.
+	Outputs []ComponentOutput `json:"outputs,omitempty"`
+	// Service reports where the app is served, when the stamped object is
+	// a Knative Service.
+	Service *ServiceStatus `json:"service,omitempty"`
+	// HealthTransitions are the recent times the stamped object became
+	// healthy or unhealthy, oldest first, from which flapping is detected.
+	HealthTransitions []HealthTransition `json:"healthTransitions,omitempty"`
+}
+
+// HealthTransition is a time the stamped object of a component became
+// healthy or unhealthy.
+type HealthTransition struct {
+	Status metav1.ConditionStatus `json:"status"`
+	Time   metav1.Time            `json:"time"`
+}
+
+// ServiceStatus is where a Knative Service serves the app, as reported in
+// its status.
+type ServiceStatus struct {
+	URL                 string `json:"url,omitempty"`
+	LatestReadyRevision string `json:"latestReadyRevision,omitempty"`
+}
+
+// ComponentOutput is a value a component provides, one of url, revision,
+// image or config.
+type ComponentOutput struct {
+	Name string `json:"name"`
+	// Preview is the value as JSON, truncated when it is long.
+	Preview string `json:"preview"`
+	// Digest is the sha256 of the whole value as JSON, so that a change
+	// beyond the preview can be noticed.
+	Digest string `json:"digest"`
 }
 
 // +kubebuilder:object:root=true
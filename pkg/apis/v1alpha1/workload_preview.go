@@ -0,0 +1,110 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +versionName=v1alpha1
+// +groupName=carto.run
+// +kubebuilder:object:generate=true
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	WorkloadPreviewReady           = "Ready"
+	WorkloadPreviewWorkloadCreated = "WorkloadCreated"
+)
+
+const (
+	CreatedWorkloadCreatedReason                     = "PreviewWorkloadCreated"
+	WorkloadNotFoundWorkloadCreatedReason            = "WorkloadNotFound"
+	NoGitSourceWorkloadCreatedReason                 = "WorkloadHasNoGitSource"
+	WorkloadRejectedByAPIServerWorkloadCreatedReason = "PreviewWorkloadRejectedByAPIServer"
+)
+
+// WorkloadPreviewLabel is set on the workload of a preview to the name of
+// the preview.
+const WorkloadPreviewLabel = "carto.run/workload-preview-name"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// WorkloadPreview realizes a copy of a workload that builds a pull request's
+// branch, through the same supply chain. The copy, and the objects stamped
+// for it, are deleted along with the preview.
+type WorkloadPreview struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              WorkloadPreviewSpec   `json:"spec"`
+	Status            WorkloadPreviewStatus `json:"status,omitempty"`
+}
+
+type WorkloadPreviewSpec struct {
+	// Workload names the workload to preview, in the namespace of the
+	// preview. It must take its source from git.
+	// +kubebuilder:validation:MinLength=1
+	Workload string `json:"workload"`
+	// PullRequest is the number of the pull request, which names the
+	// preview's workload, and so the objects stamped for it.
+	// +kubebuilder:validation:Minimum=1
+	PullRequest int64 `json:"pullRequest"`
+	// Branch is the branch of the pull request, built in place of the
+	// workload's git ref.
+	// +kubebuilder:validation:MinLength=1
+	Branch string `json:"branch"`
+	// TTL is how long after its creation the preview is deleted. When
+	// omitted, the preview is kept until it is deleted.
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+type WorkloadPreviewStatus struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	// WorkloadName is the name of the preview's workload.
+	WorkloadName string `json:"workloadName,omitempty"`
+	// ExpirationTime is when the preview is deleted.
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// WorkloadName is the name of the workload realized for the preview.
+func (p *WorkloadPreview) WorkloadName() string {
+	return fmt.Sprintf("%s-pr-%d", p.Spec.Workload, p.Spec.PullRequest)
+}
+
+// ExpirationTime is when the preview expires, or nil when it has no TTL.
+func (p *WorkloadPreview) ExpirationTime() *metav1.Time {
+	if p.Spec.TTL == nil {
+		return nil
+	}
+	expiration := metav1.NewTime(p.CreationTimestamp.Add(p.Spec.TTL.Duration))
+	return &expiration
+}
+
+// +kubebuilder:object:root=true
+
+type WorkloadPreviewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkloadPreview `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&WorkloadPreview{},
+		&WorkloadPreviewList{},
+	)
+}
@@ -190,12 +190,16 @@ var _ = Describe("Workload", func() {
 			Expect(jsonValue).NotTo(ContainSubstring("omitempty"))
 		})
 
-		It("requires value", func() {
+		It("does not require value, which may come from valueFrom", func() {
 			valueField, found := workloadParamType.FieldByName("Value")
 			Expect(found).To(BeTrue())
 			jsonValue := valueField.Tag.Get("json")
 			Expect(jsonValue).To(ContainSubstring("value"))
-			Expect(jsonValue).NotTo(ContainSubstring("omitempty"))
+			Expect(jsonValue).To(ContainSubstring("omitempty"))
+
+			valueFromField, found := workloadParamType.FieldByName("ValueFrom")
+			Expect(found).To(BeTrue())
+			Expect(valueFromField.Tag.Get("json")).To(Equal("valueFrom,omitempty"))
 		})
 	})
 })
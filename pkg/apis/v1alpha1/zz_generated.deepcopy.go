@@ -12,6 +12,41 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedKind) DeepCopyInto(out *AllowedKind) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedKind.
+func (in *AllowedKind) DeepCopy() *AllowedKind {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactSource) DeepCopyInto(out *ArtifactSource) {
+	*out = *in
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactSource.
+func (in *ArtifactSource) DeepCopy() *ArtifactSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterConfigTemplate) DeepCopyInto(out *ClusterConfigTemplate) {
 	*out = *in
@@ -189,6 +224,64 @@ func (in *ClusterSourceTemplateList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStampPolicy) DeepCopyInto(out *ClusterStampPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStampPolicy.
+func (in *ClusterStampPolicy) DeepCopy() *ClusterStampPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStampPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterStampPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStampPolicyList) DeepCopyInto(out *ClusterStampPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterStampPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStampPolicyList.
+func (in *ClusterStampPolicyList) DeepCopy() *ClusterStampPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStampPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterStampPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSupplyChain) DeepCopyInto(out *ClusterSupplyChain) {
 	*out = *in
@@ -310,6 +403,13 @@ func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterTemplateReference) DeepCopyInto(out *ClusterTemplateReference) {
 	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]TemplateOption, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateReference.
@@ -323,140 +423,222 @@ func (in *ClusterTemplateReference) DeepCopy() *ClusterTemplateReference {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentReference) DeepCopyInto(out *ComponentReference) {
+func (in *ComponentCount) DeepCopyInto(out *ComponentCount) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentReference.
-func (in *ComponentReference) DeepCopy() *ComponentReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentCount.
+func (in *ComponentCount) DeepCopy() *ComponentCount {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentReference)
+	out := new(ComponentCount)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigTemplateSpec) DeepCopyInto(out *ConfigTemplateSpec) {
+func (in *ComponentHook) DeepCopyInto(out *ComponentHook) {
 	*out = *in
-	in.TemplateSpec.DeepCopyInto(&out.TemplateSpec)
+	in.RunTemplateRef.DeepCopyInto(&out.RunTemplateRef)
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTemplateSpec.
-func (in *ConfigTemplateSpec) DeepCopy() *ConfigTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentHook.
+func (in *ComponentHook) DeepCopy() *ComponentHook {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigTemplateSpec)
+	out := new(ComponentHook)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigTemplateStatus) DeepCopyInto(out *ConfigTemplateStatus) {
+func (in *ComponentHooks) DeepCopyInto(out *ComponentHooks) {
+	*out = *in
+	if in.Pre != nil {
+		in, out := &in.Pre, &out.Pre
+		*out = make([]ComponentHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Post != nil {
+		in, out := &in.Post, &out.Post
+		*out = make([]ComponentHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentHooks.
+func (in *ComponentHooks) DeepCopy() *ComponentHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentOutput) DeepCopyInto(out *ComponentOutput) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTemplateStatus.
-func (in *ConfigTemplateStatus) DeepCopy() *ConfigTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentOutput.
+func (in *ComponentOutput) DeepCopy() *ComponentOutput {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigTemplateStatus)
+	out := new(ComponentOutput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DefaultParam) DeepCopyInto(out *DefaultParam) {
+func (in *ComponentReference) DeepCopyInto(out *ComponentReference) {
 	*out = *in
-	in.DefaultValue.DeepCopyInto(&out.DefaultValue)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultParam.
-func (in *DefaultParam) DeepCopy() *DefaultParam {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentReference.
+func (in *ComponentReference) DeepCopy() *ComponentReference {
 	if in == nil {
 		return nil
 	}
-	out := new(DefaultParam)
+	out := new(ComponentReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in DefaultParams) DeepCopyInto(out *DefaultParams) {
-	{
-		in := &in
-		*out = make(DefaultParams, len(*in))
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	if in.StampedRef != nil {
+		in, out := &in.StampedRef, &out.StampedRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]ComponentOutput, len(*in))
+		copy(*out, *in)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceStatus)
+		**out = **in
+	}
+	if in.HealthTransitions != nil {
+		in, out := &in.HealthTransitions, &out.HealthTransitions
+		*out = make([]HealthTransition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultParams.
-func (in DefaultParams) DeepCopy() DefaultParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DefaultParams)
+	out := new(ComponentStatus)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageTemplateSpec) DeepCopyInto(out *ImageTemplateSpec) {
+func (in *ConditionCount) DeepCopyInto(out *ConditionCount) {
 	*out = *in
-	in.TemplateSpec.DeepCopyInto(&out.TemplateSpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageTemplateSpec.
-func (in *ImageTemplateSpec) DeepCopy() *ImageTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionCount.
+func (in *ConditionCount) DeepCopy() *ConditionCount {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageTemplateSpec)
+	out := new(ConditionCount)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageTemplateStatus) DeepCopyInto(out *ImageTemplateStatus) {
+func (in *ConditionRequirement) DeepCopyInto(out *ConditionRequirement) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageTemplateStatus.
-func (in *ImageTemplateStatus) DeepCopy() *ImageTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionRequirement.
+func (in *ConditionRequirement) DeepCopy() *ConditionRequirement {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageTemplateStatus)
+	out := new(ConditionRequirement)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Pipeline) DeepCopyInto(out *Pipeline) {
+func (in *ConfigArtifact) DeepCopyInto(out *ConfigArtifact) {
+	*out = *in
+	if in.StampedRef != nil {
+		in, out := &in.StampedRef, &out.StampedRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigArtifact.
+func (in *ConfigArtifact) DeepCopy() *ConfigArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigTemplate) DeepCopyInto(out *ConfigTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pipeline.
-func (in *Pipeline) DeepCopy() *Pipeline {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTemplate.
+func (in *ConfigTemplate) DeepCopy() *ConfigTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(Pipeline)
+	out := new(ConfigTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Pipeline) DeepCopyObject() runtime.Object {
+func (in *ConfigTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -464,31 +646,31 @@ func (in *Pipeline) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineList) DeepCopyInto(out *PipelineList) {
+func (in *ConfigTemplateList) DeepCopyInto(out *ConfigTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Pipeline, len(*in))
+		*out = make([]ConfigTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineList.
-func (in *PipelineList) DeepCopy() *PipelineList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTemplateList.
+func (in *ConfigTemplateList) DeepCopy() *ConfigTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineList)
+	out := new(ConfigTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PipelineList) DeepCopyObject() runtime.Object {
+func (in *ConfigTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -496,173 +678,1059 @@ func (in *PipelineList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
+func (in *ConfigTemplateSpec) DeepCopyInto(out *ConfigTemplateSpec) {
 	*out = *in
-	out.RunTemplateRef = in.RunTemplateRef
-	if in.Inputs != nil {
-		in, out := &in.Inputs, &out.Inputs
-		*out = make(map[string]apiextensionsv1.JSON, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
+	in.TemplateSpec.DeepCopyInto(&out.TemplateSpec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
-func (in *PipelineSpec) DeepCopy() *PipelineSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTemplateSpec.
+func (in *ConfigTemplateSpec) DeepCopy() *ConfigTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineSpec)
+	out := new(ConfigTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
+func (in *ConfigTemplateStatus) DeepCopyInto(out *ConfigTemplateStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Outputs != nil {
-		in, out := &in.Outputs, &out.Outputs
-		*out = make(map[string]apiextensionsv1.JSON, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStatus.
-func (in *PipelineStatus) DeepCopy() *PipelineStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTemplateStatus.
+func (in *ConfigTemplateStatus) DeepCopy() *ConfigTemplateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PipelineStatus)
+	out := new(ConfigTemplateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RunTemplate) DeepCopyInto(out *RunTemplate) {
+func (in *DefaultParam) DeepCopyInto(out *DefaultParam) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	in.DefaultValue.DeepCopyInto(&out.DefaultValue)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplate.
-func (in *RunTemplate) DeepCopy() *RunTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultParam.
+func (in *DefaultParam) DeepCopy() *DefaultParam {
 	if in == nil {
 		return nil
 	}
-	out := new(RunTemplate)
+	out := new(DefaultParam)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RunTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RunTemplateList) DeepCopyInto(out *RunTemplateList) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]RunTemplate, len(*in))
+func (in DefaultParams) DeepCopyInto(out *DefaultParams) {
+	{
+		in := &in
+		*out = make(DefaultParams, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateList.
-func (in *RunTemplateList) DeepCopy() *RunTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultParams.
+func (in DefaultParams) DeepCopy() DefaultParams {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultParams)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldSelectorRequirement) DeepCopyInto(out *FieldSelectorRequirement) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldSelectorRequirement.
+func (in *FieldSelectorRequirement) DeepCopy() *FieldSelectorRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldSelectorRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsTarget) DeepCopyInto(out *GitOpsTarget) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsTarget.
+func (in *GitOpsTarget) DeepCopy() *GitOpsTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthMatchRule) DeepCopyInto(out *HealthMatchRule) {
+	*out = *in
+	if in.MatchConditions != nil {
+		in, out := &in.MatchConditions, &out.MatchConditions
+		*out = make([]ConditionRequirement, len(*in))
+		copy(*out, *in)
+	}
+	if in.MatchFields != nil {
+		in, out := &in.MatchFields, &out.MatchFields
+		*out = make([]FieldSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthMatchRule.
+func (in *HealthMatchRule) DeepCopy() *HealthMatchRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthMatchRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthRule) DeepCopyInto(out *HealthRule) {
+	*out = *in
+	if in.AlwaysHealthy != nil {
+		in, out := &in.AlwaysHealthy, &out.AlwaysHealthy
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MultiMatch != nil {
+		in, out := &in.MultiMatch, &out.MultiMatch
+		*out = new(MultiMatchHealthRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tekton != nil {
+		in, out := &in.Tekton, &out.Tekton
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthRule.
+func (in *HealthRule) DeepCopy() *HealthRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthTransition) DeepCopyInto(out *HealthTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthTransition.
+func (in *HealthTransition) DeepCopy() *HealthTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageArtifact) DeepCopyInto(out *ImageArtifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageArtifact.
+func (in *ImageArtifact) DeepCopy() *ImageArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageTemplate) DeepCopyInto(out *ImageTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageTemplate.
+func (in *ImageTemplate) DeepCopy() *ImageTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageTemplateList) DeepCopyInto(out *ImageTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageTemplateList.
+func (in *ImageTemplateList) DeepCopy() *ImageTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageTemplateSpec) DeepCopyInto(out *ImageTemplateSpec) {
+	*out = *in
+	in.TemplateSpec.DeepCopyInto(&out.TemplateSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageTemplateSpec.
+func (in *ImageTemplateSpec) DeepCopy() *ImageTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageTemplateStatus) DeepCopyInto(out *ImageTemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageTemplateStatus.
+func (in *ImageTemplateStatus) DeepCopy() *ImageTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiMatchHealthRule) DeepCopyInto(out *MultiMatchHealthRule) {
+	*out = *in
+	in.Healthy.DeepCopyInto(&out.Healthy)
+	in.Unhealthy.DeepCopyInto(&out.Unhealthy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiMatchHealthRule.
+func (in *MultiMatchHealthRule) DeepCopy() *MultiMatchHealthRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiMatchHealthRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIArtifactTarget) DeepCopyInto(out *OCIArtifactTarget) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIArtifactTarget.
+func (in *OCIArtifactTarget) DeepCopy() *OCIArtifactTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIArtifactTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OptionSelector) DeepCopyInto(out *OptionSelector) {
+	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.MatchFields != nil {
+		in, out := &in.MatchFields, &out.MatchFields
+		*out = make([]FieldSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OptionSelector.
+func (in *OptionSelector) DeepCopy() *OptionSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(OptionSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pipeline) DeepCopyInto(out *Pipeline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pipeline.
+func (in *Pipeline) DeepCopy() *Pipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(Pipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Pipeline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineList) DeepCopyInto(out *PipelineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Pipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineList.
+func (in *PipelineList) DeepCopy() *PipelineList {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PipelineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
+	*out = *in
+	in.RunTemplateRef.DeepCopyInto(&out.RunTemplateRef)
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetentionPolicy != nil {
+		in, out := &in.RetentionPolicy, &out.RetentionPolicy
+		*out = new(RetentionPolicy)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
+func (in *PipelineSpec) DeepCopy() *PipelineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.RunHistory != nil {
+		in, out := &in.RunHistory, &out.RunHistory
+		*out = make([]RunRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStatus.
+func (in *PipelineStatus) DeepCopy() *PipelineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RealizationReport) DeepCopyInto(out *RealizationReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealizationReport.
+func (in *RealizationReport) DeepCopy() *RealizationReport {
+	if in == nil {
+		return nil
+	}
+	out := new(RealizationReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RealizationReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RealizationReportList) DeepCopyInto(out *RealizationReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RealizationReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealizationReportList.
+func (in *RealizationReportList) DeepCopy() *RealizationReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(RealizationReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RealizationReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RealizationReportSpec) DeepCopyInto(out *RealizationReportSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealizationReportSpec.
+func (in *RealizationReportSpec) DeepCopy() *RealizationReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RealizationReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RealizationReportStatus) DeepCopyInto(out *RealizationReportStatus) {
+	*out = *in
+	if in.ReportTime != nil {
+		in, out := &in.ReportTime, &out.ReportTime
+		*out = (*in).DeepCopy()
+	}
+	if in.WorkloadsByCondition != nil {
+		in, out := &in.WorkloadsByCondition, &out.WorkloadsByCondition
+		*out = make([]ConditionCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailingTemplates != nil {
+		in, out := &in.FailingTemplates, &out.FailingTemplates
+		*out = make([]ComponentCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.SlowestComponents != nil {
+		in, out := &in.SlowestComponents, &out.SlowestComponents
+		*out = make([]ComponentCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.StuckWorkloads != nil {
+		in, out := &in.StuckWorkloads, &out.StuckWorkloads
+		*out = make([]StuckWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealizationReportStatus.
+func (in *RealizationReportStatus) DeepCopy() *RealizationReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RealizationReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RealizationRetry) DeepCopyInto(out *RealizationRetry) {
+	*out = *in
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RealizationRetry.
+func (in *RealizationRetry) DeepCopy() *RealizationRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(RealizationRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionPolicy) DeepCopyInto(out *RetentionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPolicy.
+func (in *RetentionPolicy) DeepCopy() *RetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunRecord) DeepCopyInto(out *RunRecord) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunRecord.
+func (in *RunRecord) DeepCopy() *RunRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(RunRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplate) DeepCopyInto(out *RunTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplate.
+func (in *RunTemplate) DeepCopy() *RunTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplateInput) DeepCopyInto(out *RunTemplateInput) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateInput.
+func (in *RunTemplateInput) DeepCopy() *RunTemplateInput {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplateInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplateList) DeepCopyInto(out *RunTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RunTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateList.
+func (in *RunTemplateList) DeepCopy() *RunTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplateSample) DeepCopyInto(out *RunTemplateSample) {
+	*out = *in
+	in.Pipeline.DeepCopyInto(&out.Pipeline)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateSample.
+func (in *RunTemplateSample) DeepCopy() *RunTemplateSample {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplateSample)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplateSpec) DeepCopyInto(out *RunTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TektonResults != nil {
+		in, out := &in.TektonResults, &out.TektonResults
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make([]RunTemplateInput, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sample != nil {
+		in, out := &in.Sample, &out.Sample
+		*out = new(RunTemplateSample)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateSpec.
+func (in *RunTemplateSpec) DeepCopy() *RunTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSideApplySettings) DeepCopyInto(out *ServerSideApplySettings) {
+	*out = *in
+	if in.ForceConflicts != nil {
+		in, out := &in.ForceConflicts, &out.ForceConflicts
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSideApplySettings.
+func (in *ServerSideApplySettings) DeepCopy() *ServerSideApplySettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSideApplySettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountRef) DeepCopyInto(out *ServiceAccountRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountRef.
+func (in *ServiceAccountRef) DeepCopy() *ServiceAccountRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceArtifact) DeepCopyInto(out *ServiceArtifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceArtifact.
+func (in *ServiceArtifact) DeepCopy() *ServiceArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceStatus) DeepCopyInto(out *ServiceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceStatus.
+func (in *ServiceStatus) DeepCopy() *ServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceArtifact) DeepCopyInto(out *SourceArtifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceArtifact.
+func (in *SourceArtifact) DeepCopy() *SourceArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceTemplate) DeepCopyInto(out *SourceTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceTemplate.
+func (in *SourceTemplate) DeepCopy() *SourceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceTemplateList) DeepCopyInto(out *SourceTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SourceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceTemplateList.
+func (in *SourceTemplateList) DeepCopy() *SourceTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceTemplateSpec) DeepCopyInto(out *SourceTemplateSpec) {
+	*out = *in
+	in.TemplateSpec.DeepCopyInto(&out.TemplateSpec)
+	if in.Artifact != nil {
+		in, out := &in.Artifact, &out.Artifact
+		*out = new(ArtifactSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceTemplateSpec.
+func (in *SourceTemplateSpec) DeepCopy() *SourceTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RunTemplateList)
+	out := new(SourceTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RunTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceTemplateStatus) DeepCopyInto(out *SourceTemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceTemplateStatus.
+func (in *SourceTemplateStatus) DeepCopy() *SourceTemplateStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(SourceTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RunTemplateSpec) DeepCopyInto(out *RunTemplateSpec) {
+func (in *StampPolicySpec) DeepCopyInto(out *StampPolicySpec) {
 	*out = *in
-	in.Template.DeepCopyInto(&out.Template)
-	if in.Outputs != nil {
-		in, out := &in.Outputs, &out.Outputs
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedKinds != nil {
+		in, out := &in.AllowedKinds, &out.AllowedKinds
+		*out = make([]AllowedKind, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateSpec.
-func (in *RunTemplateSpec) DeepCopy() *RunTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StampPolicySpec.
+func (in *StampPolicySpec) DeepCopy() *StampPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RunTemplateSpec)
+	out := new(StampPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SourceTemplateSpec) DeepCopyInto(out *SourceTemplateSpec) {
+func (in *StuckWorkload) DeepCopyInto(out *StuckWorkload) {
 	*out = *in
-	in.TemplateSpec.DeepCopyInto(&out.TemplateSpec)
+	in.Since.DeepCopyInto(&out.Since)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceTemplateSpec.
-func (in *SourceTemplateSpec) DeepCopy() *SourceTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StuckWorkload.
+func (in *StuckWorkload) DeepCopy() *StuckWorkload {
 	if in == nil {
 		return nil
 	}
-	out := new(SourceTemplateSpec)
+	out := new(StuckWorkload)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SourceTemplateStatus) DeepCopyInto(out *SourceTemplateStatus) {
+func (in *SupplyChain) DeepCopyInto(out *SupplyChain) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceTemplateStatus.
-func (in *SourceTemplateStatus) DeepCopy() *SourceTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChain.
+func (in *SupplyChain) DeepCopy() *SupplyChain {
 	if in == nil {
 		return nil
 	}
-	out := new(SourceTemplateStatus)
+	out := new(SupplyChain)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SupplyChain) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SupplyChainComponent) DeepCopyInto(out *SupplyChainComponent) {
 	*out = *in
-	out.TemplateRef = in.TemplateRef
+	in.TemplateRef.DeepCopyInto(&out.TemplateRef)
 	if in.Params != nil {
 		in, out := &in.Params, &out.Params
 		*out = make([]SupplyChainParam, len(*in))
@@ -685,65 +1753,375 @@ func (in *SupplyChainComponent) DeepCopyInto(out *SupplyChainComponent) {
 		*out = make([]ComponentReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(ComponentHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitOps != nil {
+		in, out := &in.GitOps, &out.GitOps
+		*out = new(GitOpsTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OCIArtifact != nil {
+		in, out := &in.OCIArtifact, &out.OCIArtifact
+		*out = new(OCIArtifactTarget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainComponent.
+func (in *SupplyChainComponent) DeepCopy() *SupplyChainComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(SupplyChainComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupplyChainExtension) DeepCopyInto(out *SupplyChainExtension) {
+	*out = *in
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainExtension.
+func (in *SupplyChainExtension) DeepCopy() *SupplyChainExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(SupplyChainExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupplyChainList) DeepCopyInto(out *SupplyChainList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SupplyChain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainList.
+func (in *SupplyChainList) DeepCopy() *SupplyChainList {
+	if in == nil {
+		return nil
+	}
+	out := new(SupplyChainList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SupplyChainList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupplyChainParam) DeepCopyInto(out *SupplyChainParam) {
+	*out = *in
+	in.Value.DeepCopyInto(&out.Value)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainParam.
+func (in *SupplyChainParam) DeepCopy() *SupplyChainParam {
+	if in == nil {
+		return nil
+	}
+	out := new(SupplyChainParam)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupplyChainSpec) DeepCopyInto(out *SupplyChainSpec) {
+	*out = *in
+	if in.Extends != nil {
+		in, out := &in.Extends, &out.Extends
+		*out = new(SupplyChainExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]SupplyChainComponent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SelectorMatchExpressions != nil {
+		in, out := &in.SelectorMatchExpressions, &out.SelectorMatchExpressions
+		*out = make([]v1.LabelSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SelectorMatchFields != nil {
+		in, out := &in.SelectorMatchFields, &out.SelectorMatchFields
+		*out = make([]FieldSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WorkloadParams != nil {
+		in, out := &in.WorkloadParams, &out.WorkloadParams
+		*out = make([]WorkloadParamDeclaration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServerSideApply != nil {
+		in, out := &in.ServerSideApply, &out.ServerSideApply
+		*out = new(ServerSideApplySettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccountRef != nil {
+		in, out := &in.ServiceAccountRef, &out.ServiceAccountRef
+		*out = new(ServiceAccountRef)
+		**out = **in
+	}
+	if in.AllowedTargetNamespaces != nil {
+		in, out := &in.AllowedTargetNamespaces, &out.AllowedTargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainSpec.
+func (in *SupplyChainSpec) DeepCopy() *SupplyChainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SupplyChainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupplyChainStatus) DeepCopyInto(out *SupplyChainStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainStatus.
+func (in *SupplyChainStatus) DeepCopy() *SupplyChainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SupplyChainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Template) DeepCopyInto(out *Template) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Template.
+func (in *Template) DeepCopy() *Template {
+	if in == nil {
+		return nil
+	}
+	out := new(Template)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Template) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateList) DeepCopyInto(out *TemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Template, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateList.
+func (in *TemplateList) DeepCopy() *TemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateMetadata) DeepCopyInto(out *TemplateMetadata) {
+	*out = *in
+	if in.Maintainers != nil {
+		in, out := &in.Maintainers, &out.Maintainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateMetadata.
+func (in *TemplateMetadata) DeepCopy() *TemplateMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateMetadata)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainComponent.
-func (in *SupplyChainComponent) DeepCopy() *SupplyChainComponent {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateOption) DeepCopyInto(out *TemplateOption) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateOption.
+func (in *TemplateOption) DeepCopy() *TemplateOption {
 	if in == nil {
 		return nil
 	}
-	out := new(SupplyChainComponent)
+	out := new(TemplateOption)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SupplyChainParam) DeepCopyInto(out *SupplyChainParam) {
+func (in *TemplatePlayground) DeepCopyInto(out *TemplatePlayground) {
 	*out = *in
-	in.Value.DeepCopyInto(&out.Value)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainParam.
-func (in *SupplyChainParam) DeepCopy() *SupplyChainParam {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplatePlayground.
+func (in *TemplatePlayground) DeepCopy() *TemplatePlayground {
 	if in == nil {
 		return nil
 	}
-	out := new(SupplyChainParam)
+	out := new(TemplatePlayground)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TemplatePlayground) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SupplyChainSpec) DeepCopyInto(out *SupplyChainSpec) {
+func (in *TemplatePlaygroundList) DeepCopyInto(out *TemplatePlaygroundList) {
 	*out = *in
-	if in.Components != nil {
-		in, out := &in.Components, &out.Components
-		*out = make([]SupplyChainComponent, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TemplatePlayground, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplatePlaygroundList.
+func (in *TemplatePlaygroundList) DeepCopy() *TemplatePlaygroundList {
+	if in == nil {
+		return nil
 	}
+	out := new(TemplatePlaygroundList)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainSpec.
-func (in *SupplyChainSpec) DeepCopy() *SupplyChainSpec {
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TemplatePlaygroundList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplatePlaygroundSpec) DeepCopyInto(out *TemplatePlaygroundSpec) {
+	*out = *in
+	in.TemplateSpec.DeepCopyInto(&out.TemplateSpec)
+	if in.Context != nil {
+		in, out := &in.Context, &out.Context
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplatePlaygroundSpec.
+func (in *TemplatePlaygroundSpec) DeepCopy() *TemplatePlaygroundSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SupplyChainSpec)
+	out := new(TemplatePlaygroundSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SupplyChainStatus) DeepCopyInto(out *SupplyChainStatus) {
+func (in *TemplatePlaygroundStatus) DeepCopyInto(out *TemplatePlaygroundStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -752,14 +2130,19 @@ func (in *SupplyChainStatus) DeepCopyInto(out *SupplyChainStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Result != nil {
+		in, out := &in.Result, &out.Result
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupplyChainStatus.
-func (in *SupplyChainStatus) DeepCopy() *SupplyChainStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplatePlaygroundStatus.
+func (in *TemplatePlaygroundStatus) DeepCopy() *TemplatePlaygroundStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SupplyChainStatus)
+	out := new(TemplatePlaygroundStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -767,6 +2150,11 @@ func (in *SupplyChainStatus) DeepCopy() *SupplyChainStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateReference) DeepCopyInto(out *TemplateReference) {
 	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateReference.
@@ -779,6 +2167,27 @@ func (in *TemplateReference) DeepCopy() *TemplateReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateSample) DeepCopyInto(out *TemplateSample) {
+	*out = *in
+	in.Workload.DeepCopyInto(&out.Workload)
+	if in.Context != nil {
+		in, out := &in.Context, &out.Context
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSample.
+func (in *TemplateSample) DeepCopy() *TemplateSample {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateSample)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateSpec) DeepCopyInto(out *TemplateSpec) {
 	*out = *in
@@ -794,6 +2203,21 @@ func (in *TemplateSpec) DeepCopyInto(out *TemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(TemplateMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthRule != nil {
+		in, out := &in.HealthRule, &out.HealthRule
+		*out = new(HealthRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sample != nil {
+		in, out := &in.Sample, &out.Sample
+		*out = new(TemplateSample)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSpec.
@@ -848,6 +2272,41 @@ func (in *Workload) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadArtifacts) DeepCopyInto(out *WorkloadArtifacts) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(SourceArtifact)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(ImageArtifact)
+		**out = **in
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(ConfigArtifact)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceArtifact)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadArtifacts.
+func (in *WorkloadArtifacts) DeepCopy() *WorkloadArtifacts {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadArtifacts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadGit) DeepCopyInto(out *WorkloadGit) {
 	*out = *in
@@ -939,6 +2398,11 @@ func (in *WorkloadList) DeepCopyObject() runtime.Object {
 func (in *WorkloadParam) DeepCopyInto(out *WorkloadParam) {
 	*out = *in
 	in.Value.DeepCopyInto(&out.Value)
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(WorkloadParamSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadParam.
@@ -951,6 +2415,151 @@ func (in *WorkloadParam) DeepCopy() *WorkloadParam {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadParamDeclaration) DeepCopyInto(out *WorkloadParamDeclaration) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadParamDeclaration.
+func (in *WorkloadParamDeclaration) DeepCopy() *WorkloadParamDeclaration {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadParamDeclaration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadParamSource) DeepCopyInto(out *WorkloadParamSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadParamSource.
+func (in *WorkloadParamSource) DeepCopy() *WorkloadParamSource {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadParamSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPreview) DeepCopyInto(out *WorkloadPreview) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPreview.
+func (in *WorkloadPreview) DeepCopy() *WorkloadPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkloadPreview) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPreviewList) DeepCopyInto(out *WorkloadPreviewList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkloadPreview, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPreviewList.
+func (in *WorkloadPreviewList) DeepCopy() *WorkloadPreviewList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPreviewList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkloadPreviewList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPreviewSpec) DeepCopyInto(out *WorkloadPreviewSpec) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPreviewSpec.
+func (in *WorkloadPreviewSpec) DeepCopy() *WorkloadPreviewSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPreviewSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadPreviewStatus) DeepCopyInto(out *WorkloadPreviewStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadPreviewStatus.
+func (in *WorkloadPreviewStatus) DeepCopy() *WorkloadPreviewStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadPreviewStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadServiceClaim) DeepCopyInto(out *WorkloadServiceClaim) {
 	*out = *in
@@ -1055,6 +2664,16 @@ func (in *WorkloadSpec) DeepCopyInto(out *WorkloadSpec) {
 		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaxDuration != nil {
+		in, out := &in.MaxDuration, &out.MaxDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Teardown != nil {
+		in, out := &in.Teardown, &out.Teardown
+		*out = new(WorkloadTeardown)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpec.
@@ -1078,6 +2697,31 @@ func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
 		}
 	}
 	out.SupplyChainRef = in.SupplyChainRef
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RealizationStartTime != nil {
+		in, out := &in.RealizationStartTime, &out.RealizationStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RealizationCompletionTime != nil {
+		in, out := &in.RealizationCompletionTime, &out.RealizationCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = new(WorkloadArtifacts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RealizationRetry)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStatus.
@@ -1104,3 +2748,23 @@ func (in *WorkloadSupplyChainReference) DeepCopy() *WorkloadSupplyChainReference
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadTeardown) DeepCopyInto(out *WorkloadTeardown) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadTeardown.
+func (in *WorkloadTeardown) DeepCopy() *WorkloadTeardown {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadTeardown)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	"sort"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+func (src *Workload) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Workload)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.WorkloadSpec{
+		Source:             src.Spec.Source,
+		Image:              src.Spec.Image,
+		ServiceClaims:      src.Spec.ServiceClaims,
+		Env:                src.Spec.Env,
+		Resources:          src.Spec.Resources,
+		MaxDuration:        src.Spec.MaxDuration,
+		Paused:             src.Spec.Paused,
+		Teardown:           src.Spec.Teardown,
+		ServiceAccountName: src.Spec.ServiceAccountName,
+	}
+	for _, param := range src.Spec.Params {
+		dst.Spec.Params = append(dst.Spec.Params, v1alpha1.WorkloadParam{Name: param.Name, Value: param.Value, ValueFrom: param.ValueFrom})
+	}
+	dst.Status = src.Status
+
+	return nil
+}
+
+func (dst *Workload) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Workload)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = WorkloadSpec{
+		Source:             src.Spec.Source,
+		Image:              src.Spec.Image,
+		ServiceClaims:      src.Spec.ServiceClaims,
+		Env:                src.Spec.Env,
+		Resources:          src.Spec.Resources,
+		MaxDuration:        src.Spec.MaxDuration,
+		Paused:             src.Spec.Paused,
+		Teardown:           src.Spec.Teardown,
+		ServiceAccountName: src.Spec.ServiceAccountName,
+	}
+	for _, param := range src.Spec.Params {
+		dst.Spec.Params = append(dst.Spec.Params, WorkloadParam{Name: param.Name, Value: param.Value, ValueFrom: param.ValueFrom})
+	}
+	dst.Status = src.Status
+
+	return nil
+}
+
+func (src *Pipeline) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Pipeline)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.PipelineSpec{
+		RunTemplateRef:          src.Spec.TemplateRef,
+		Timeout:                 src.Spec.Timeout,
+		RetryPolicy:             src.Spec.RetryPolicy,
+		RetentionPolicy:         src.Spec.RetentionPolicy,
+		TTLSecondsAfterFinished: src.Spec.TTLSecondsAfterFinished,
+		TeardownPolicy:          src.Spec.TeardownPolicy,
+	}
+	if len(src.Spec.Params) > 0 {
+		dst.Spec.Inputs = make(map[string]apiextensionsv1.JSON, len(src.Spec.Params))
+		for _, param := range src.Spec.Params {
+			dst.Spec.Inputs[param.Name] = param.Value
+		}
+	}
+	dst.Status = src.Status
+
+	return nil
+}
+
+// ConvertFrom lists the inputs of a v1alpha1 pipeline as params sorted by
+// name, the order of a map being lost.
+func (dst *Pipeline) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Pipeline)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = PipelineSpec{
+		TemplateRef:             src.Spec.RunTemplateRef,
+		Timeout:                 src.Spec.Timeout,
+		RetryPolicy:             src.Spec.RetryPolicy,
+		RetentionPolicy:         src.Spec.RetentionPolicy,
+		TTLSecondsAfterFinished: src.Spec.TTLSecondsAfterFinished,
+		TeardownPolicy:          src.Spec.TeardownPolicy,
+	}
+	for name, value := range src.Spec.Inputs {
+		dst.Spec.Params = append(dst.Spec.Params, Param{Name: name, Value: value})
+	}
+	sort.Slice(dst.Spec.Params, func(i, j int) bool {
+		return dst.Spec.Params[i].Name < dst.Spec.Params[j].Name
+	})
+	dst.Status = src.Status
+
+	return nil
+}
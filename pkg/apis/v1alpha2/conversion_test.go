@@ -0,0 +1,170 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha2"
+)
+
+var _ = Describe("Conversion", func() {
+	It("converts workloads and pipelines between versions", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha2.AddToScheme(scheme)).To(Succeed())
+
+		for _, obj := range []runtime.Object{&v1alpha1.Workload{}, &v1alpha1.Pipeline{}} {
+			convertible, err := conversion.IsConvertible(scheme, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(convertible).To(BeTrue())
+		}
+	})
+
+	Describe("Workload", func() {
+		var hub *v1alpha1.Workload
+
+		BeforeEach(func() {
+			image := "registry.example.com/app:latest"
+			hub = &v1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "dev", Labels: map[string]string{"app": "web"}},
+				Spec: v1alpha1.WorkloadSpec{
+					Params: []v1alpha1.WorkloadParam{
+						{Name: "port", Value: apiextensionsv1.JSON{Raw: []byte(`8080`)}},
+						{Name: "debug", Value: apiextensionsv1.JSON{Raw: []byte(`true`)}},
+						{Name: "token", ValueFrom: &v1alpha1.WorkloadParamSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+							Key:                  "token",
+						}}},
+					},
+					Image:              &image,
+					Env:                []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}},
+					MaxDuration:        &metav1.Duration{Duration: time.Minute},
+					Paused:             true,
+					Teardown:           &v1alpha1.WorkloadTeardown{Ordered: true, Policy: v1alpha1.OrphanTeardownPolicy},
+					ServiceAccountName: "builder",
+				},
+				Status: v1alpha1.WorkloadStatus{
+					SupplyChainRef: v1alpha1.WorkloadSupplyChainReference{Kind: "ClusterSupplyChain", Name: "web"},
+				},
+			}
+		})
+
+		It("round trips a v1alpha1 workload", func() {
+			spoke := &v1alpha2.Workload{}
+			Expect(spoke.ConvertFrom(hub.DeepCopy())).To(Succeed())
+
+			Expect(spoke.Spec.Params).To(Equal([]v1alpha2.WorkloadParam{
+				{Name: "port", Value: apiextensionsv1.JSON{Raw: []byte(`8080`)}},
+				{Name: "debug", Value: apiextensionsv1.JSON{Raw: []byte(`true`)}},
+				{Name: "token", ValueFrom: hub.Spec.Params[2].ValueFrom},
+			}))
+
+			converted := &v1alpha1.Workload{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		})
+
+		It("round trips a v1alpha2 workload", func() {
+			spoke := &v1alpha2.Workload{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+			converted := &v1alpha1.Workload{}
+			Expect(spoke.DeepCopy().ConvertTo(converted)).To(Succeed())
+			roundTripped := &v1alpha2.Workload{}
+			Expect(roundTripped.ConvertFrom(converted)).To(Succeed())
+			Expect(roundTripped).To(Equal(spoke))
+		})
+	})
+
+	Describe("Pipeline", func() {
+		var hub *v1alpha1.Pipeline
+
+		BeforeEach(func() {
+			ttl := int32(60)
+			hub = &v1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: "tests", Namespace: "dev"},
+				Spec: v1alpha1.PipelineSpec{
+					RunTemplateRef: v1alpha1.TemplateReference{Kind: "RunTemplate", Name: "tekton"},
+					Inputs: map[string]apiextensionsv1.JSON{
+						"url":      {Raw: []byte(`"https://example.com/app.git"`)},
+						"revision": {Raw: []byte(`"main"`)},
+					},
+					Timeout:                 &metav1.Duration{Duration: time.Hour},
+					RetryPolicy:             &v1alpha1.RetryPolicy{Limit: 2},
+					RetentionPolicy:         &v1alpha1.RetentionPolicy{MaxFailedRuns: 3},
+					TTLSecondsAfterFinished: &ttl,
+					TeardownPolicy:          v1alpha1.DeleteTeardownPolicy,
+				},
+				Status: v1alpha1.PipelineStatus{
+					ObservedGeneration: 2,
+					RunHistory:         []v1alpha1.RunRecord{{Name: "tests-abc", Result: "Succeeded"}},
+				},
+			}
+		})
+
+		It("names the fields of a v1alpha1 pipeline after those of workloads and supply chains", func() {
+			spoke := &v1alpha2.Pipeline{}
+			Expect(spoke.ConvertFrom(hub.DeepCopy())).To(Succeed())
+
+			Expect(spoke.Spec.TemplateRef).To(Equal(hub.Spec.RunTemplateRef))
+			Expect(spoke.Spec.Params).To(Equal([]v1alpha2.Param{
+				{Name: "revision", Value: apiextensionsv1.JSON{Raw: []byte(`"main"`)}},
+				{Name: "url", Value: apiextensionsv1.JSON{Raw: []byte(`"https://example.com/app.git"`)}},
+			}))
+		})
+
+		It("round trips a v1alpha1 pipeline", func() {
+			spoke := &v1alpha2.Pipeline{}
+			Expect(spoke.ConvertFrom(hub.DeepCopy())).To(Succeed())
+
+			converted := &v1alpha1.Pipeline{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		})
+
+		It("round trips a v1alpha2 pipeline", func() {
+			spoke := &v1alpha2.Pipeline{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+			converted := &v1alpha1.Pipeline{}
+			Expect(spoke.DeepCopy().ConvertTo(converted)).To(Succeed())
+			roundTripped := &v1alpha2.Pipeline{}
+			Expect(roundTripped.ConvertFrom(converted)).To(Succeed())
+			Expect(roundTripped).To(Equal(spoke))
+		})
+
+		It("round trips a pipeline without params", func() {
+			hub.Spec.Inputs = nil
+
+			spoke := &v1alpha2.Pipeline{}
+			Expect(spoke.ConvertFrom(hub.DeepCopy())).To(Succeed())
+			Expect(spoke.Spec.Params).To(BeNil())
+
+			converted := &v1alpha1.Pipeline{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+			Expect(converted).To(Equal(hub))
+		})
+	})
+})
@@ -0,0 +1,37 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// Param is a named value, the shape of the params of pipelines.
+type Param struct {
+	Name  string               `json:"name"`
+	Value apiextensionsv1.JSON `json:"value"`
+}
+
+// WorkloadParam is a Param whose value may instead come from a Secret.
+type WorkloadParam struct {
+	Name string `json:"name"`
+	// Value of the param, unless it comes from ValueFrom.
+	Value apiextensionsv1.JSON `json:"value,omitempty"`
+	// ValueFrom sources the value of the param from a Secret, so that
+	// credentials need not be written into the workload.
+	ValueFrom *v1alpha1.WorkloadParamSource `json:"valueFrom,omitempty"`
+}
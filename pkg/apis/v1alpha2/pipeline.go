@@ -0,0 +1,77 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+type Pipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              PipelineSpec            `json:"spec"`
+	Status            v1alpha1.PipelineStatus `json:"status,omitempty"`
+}
+
+// PipelineSpec is that of v1alpha1 with its fields named after those of
+// workloads and supply chains: runTemplateRef is templateRef, and the
+// inputs map is the params list.
+type PipelineSpec struct {
+	// TemplateRef names the RunTemplate to stamp runs from, or selects it by
+	// its labels.
+	// +kubebuilder:validation:Required
+	TemplateRef v1alpha1.TemplateReference `json:"templateRef"`
+	// Params are the values the RunTemplate stamps runs with. In templates
+	// they are still found under $(pipeline.spec.inputs)$, as pipelines
+	// are stamped as v1alpha1.
+	// +listType=map
+	// +listMapKey=name
+	Params []Param `json:"params,omitempty"`
+	// Timeout is how long the latest run may take to report success or
+	// failure before the pipeline stops waiting on it for outputs.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// RetryPolicy stamps a new run after the latest run fails.
+	RetryPolicy *v1alpha1.RetryPolicy `json:"retryPolicy,omitempty"`
+	// RetentionPolicy controls the pruning of previously stamped runs.
+	RetentionPolicy *v1alpha1.RetentionPolicy `json:"retentionPolicy,omitempty"`
+	// TTLSecondsAfterFinished deletes a run this many seconds after it
+	// succeeds or fails.
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// TeardownPolicy is Delete, the default, to delete the runs with the
+	// pipeline, or Orphan to keep them.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	TeardownPolicy v1alpha1.TeardownPolicy `json:"teardownPolicy,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type PipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Pipeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&Pipeline{},
+		&PipelineList{},
+	)
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+type Workload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              WorkloadSpec            `json:"spec"`
+	Status            v1alpha1.WorkloadStatus `json:"status,omitempty"`
+}
+
+// WorkloadSpec is that of v1alpha1, but for its params, whose names must
+// be unique.
+type WorkloadSpec struct {
+	// +listType=map
+	// +listMapKey=name
+	Params []WorkloadParam          `json:"params,omitempty"`
+	Source *v1alpha1.WorkloadSource `json:"source,omitempty"`
+	// Image is a pre-built image in a registry. It is an alternative to defining source
+	// code.
+	Image         *string                         `json:"image,omitempty"`
+	ServiceClaims []v1alpha1.WorkloadServiceClaim `json:"serviceClaims,omitempty"`
+	Env           []corev1.EnvVar                 `json:"env,omitempty"`
+	Resources     *corev1.ResourceRequirements    `json:"resources,omitempty"`
+	// MaxDuration is how long the supply chain may take to realize the
+	// workload after a change to its spec.
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+	// Paused suspends the delivery of the workload until it is unset.
+	Paused bool `json:"paused,omitempty"`
+	// Teardown configures how the objects stamped for the workload are
+	// deleted with it.
+	Teardown *v1alpha1.WorkloadTeardown `json:"teardown,omitempty"`
+	// ServiceAccountName is the service account the objects stamped for the
+	// workload run as.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type WorkloadList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Workload `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&Workload{},
+		&WorkloadList{},
+	)
+}
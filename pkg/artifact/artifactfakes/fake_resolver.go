@@ -0,0 +1,122 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package artifactfakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vmware-tanzu/cartographer/pkg/artifact"
+)
+
+type FakeResolver struct {
+	ResolveStub        func(context.Context, artifact.Coordinate, time.Duration) (*artifact.Artifact, error)
+	resolveMutex       sync.RWMutex
+	resolveArgsForCall []struct {
+		arg1 context.Context
+		arg2 artifact.Coordinate
+		arg3 time.Duration
+	}
+	resolveReturns struct {
+		result1 *artifact.Artifact
+		result2 error
+	}
+	resolveReturnsOnCall map[int]struct {
+		result1 *artifact.Artifact
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeResolver) Resolve(arg1 context.Context, arg2 artifact.Coordinate, arg3 time.Duration) (*artifact.Artifact, error) {
+	fake.resolveMutex.Lock()
+	ret, specificReturn := fake.resolveReturnsOnCall[len(fake.resolveArgsForCall)]
+	fake.resolveArgsForCall = append(fake.resolveArgsForCall, struct {
+		arg1 context.Context
+		arg2 artifact.Coordinate
+		arg3 time.Duration
+	}{arg1, arg2, arg3})
+	stub := fake.ResolveStub
+	fakeReturns := fake.resolveReturns
+	fake.recordInvocation("Resolve", []interface{}{arg1, arg2, arg3})
+	fake.resolveMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeResolver) ResolveCallCount() int {
+	fake.resolveMutex.RLock()
+	defer fake.resolveMutex.RUnlock()
+	return len(fake.resolveArgsForCall)
+}
+
+func (fake *FakeResolver) ResolveCalls(stub func(context.Context, artifact.Coordinate, time.Duration) (*artifact.Artifact, error)) {
+	fake.resolveMutex.Lock()
+	defer fake.resolveMutex.Unlock()
+	fake.ResolveStub = stub
+}
+
+func (fake *FakeResolver) ResolveArgsForCall(i int) (context.Context, artifact.Coordinate, time.Duration) {
+	fake.resolveMutex.RLock()
+	defer fake.resolveMutex.RUnlock()
+	argsForCall := fake.resolveArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeResolver) ResolveReturns(result1 *artifact.Artifact, result2 error) {
+	fake.resolveMutex.Lock()
+	defer fake.resolveMutex.Unlock()
+	fake.ResolveStub = nil
+	fake.resolveReturns = struct {
+		result1 *artifact.Artifact
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeResolver) ResolveReturnsOnCall(i int, result1 *artifact.Artifact, result2 error) {
+	fake.resolveMutex.Lock()
+	defer fake.resolveMutex.Unlock()
+	fake.ResolveStub = nil
+	if fake.resolveReturnsOnCall == nil {
+		fake.resolveReturnsOnCall = make(map[int]struct {
+			result1 *artifact.Artifact
+			result2 error
+		})
+	}
+	fake.resolveReturnsOnCall[i] = struct {
+		result1 *artifact.Artifact
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeResolver) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.resolveMutex.RLock()
+	defer fake.resolveMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeResolver) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ artifact.Resolver = new(FakeResolver)
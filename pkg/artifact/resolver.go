@@ -0,0 +1,208 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// Coordinate identifies an artifact in a registry. An empty Version
+// resolves to the latest release.
+type Coordinate struct {
+	Type     string
+	Registry string
+	Name     string
+	Version  string
+}
+
+type Artifact struct {
+	URL     string
+	Version string
+}
+
+//counterfeiter:generate . Resolver
+type Resolver interface {
+	// Resolve returns the artifact at the coordinate, polling the registry
+	// at most once per pollInterval for each coordinate.
+	Resolve(ctx context.Context, coordinate Coordinate, pollInterval time.Duration) (*Artifact, error)
+}
+
+type resolved struct {
+	artifact *Artifact
+	at       time.Time
+}
+
+type resolver struct {
+	client *http.Client
+	now    func() time.Time
+
+	mu       sync.Mutex
+	resolved map[Coordinate]resolved
+}
+
+func NewResolver(client *http.Client) Resolver {
+	return &resolver{
+		client:   client,
+		now:      time.Now,
+		resolved: map[Coordinate]resolved{},
+	}
+}
+
+func (r *resolver) Resolve(ctx context.Context, coordinate Coordinate, pollInterval time.Duration) (*Artifact, error) {
+	r.mu.Lock()
+	previous, ok := r.resolved[coordinate]
+	r.mu.Unlock()
+	if ok && r.now().Before(previous.at.Add(pollInterval)) {
+		return previous.artifact, nil
+	}
+
+	var (
+		artifact *Artifact
+		err      error
+	)
+	switch coordinate.Type {
+	case v1alpha1.MavenArtifactSourceType:
+		artifact, err = r.resolveMaven(ctx, coordinate)
+	case v1alpha1.NpmArtifactSourceType:
+		artifact, err = r.resolveNpm(ctx, coordinate)
+	default:
+		err = fmt.Errorf("unknown artifact type '%s'", coordinate.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.resolved[coordinate] = resolved{artifact: artifact, at: r.now()}
+	r.mu.Unlock()
+
+	return artifact, nil
+}
+
+type mavenMetadata struct {
+	Versioning struct {
+		Latest  string `xml:"latest"`
+		Release string `xml:"release"`
+	} `xml:"versioning"`
+}
+
+func (r *resolver) resolveMaven(ctx context.Context, coordinate Coordinate) (*Artifact, error) {
+	parts := strings.Split(coordinate.Name, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("maven artifact name '%s' is not groupId:artifactId", coordinate.Name)
+	}
+	groupID, artifactID := parts[0], parts[1]
+	base := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(coordinate.Registry, "/"), strings.ReplaceAll(groupID, ".", "/"), artifactID)
+
+	version := coordinate.Version
+	if version == "" {
+		body, err := r.get(ctx, base+"/maven-metadata.xml")
+		if err != nil {
+			return nil, err
+		}
+
+		metadata := mavenMetadata{}
+		if err := xml.Unmarshal(body, &metadata); err != nil {
+			return nil, fmt.Errorf("unmarshal maven metadata: %w", err)
+		}
+
+		version = metadata.Versioning.Release
+		if version == "" {
+			version = metadata.Versioning.Latest
+		}
+		if version == "" {
+			return nil, fmt.Errorf("maven metadata for '%s' lists no release", coordinate.Name)
+		}
+	}
+
+	return &Artifact{
+		URL:     fmt.Sprintf("%s/%s/%s-%s.jar", base, version, artifactID, version),
+		Version: version,
+	}, nil
+}
+
+type npmPackument struct {
+	DistTags map[string]string `json:"dist-tags"`
+	Versions map[string]struct {
+		Dist struct {
+			Tarball string `json:"tarball"`
+		} `json:"dist"`
+	} `json:"versions"`
+}
+
+func (r *resolver) resolveNpm(ctx context.Context, coordinate Coordinate) (*Artifact, error) {
+	// the slash of a scoped package, @scope/name, is escaped
+	body, err := r.get(ctx, fmt.Sprintf("%s/%s", strings.TrimSuffix(coordinate.Registry, "/"), url.PathEscape(coordinate.Name)))
+	if err != nil {
+		return nil, err
+	}
+
+	packument := npmPackument{}
+	if err := json.Unmarshal(body, &packument); err != nil {
+		return nil, fmt.Errorf("unmarshal npm package: %w", err)
+	}
+
+	version := coordinate.Version
+	if version == "" {
+		version = packument.DistTags["latest"]
+	}
+
+	release, ok := packument.Versions[version]
+	if !ok || release.Dist.Tarball == "" {
+		return nil, fmt.Errorf("npm package '%s' has no version '%s'", coordinate.Name, version)
+	}
+
+	return &Artifact{
+		URL:     release.Dist.Tarball,
+		Version: version,
+	}, nil
+}
+
+func (r *resolver) get(ctx context.Context, location string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("get: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry responded %d to '%s'", response.StatusCode, location)
+	}
+
+	return body, nil
+}
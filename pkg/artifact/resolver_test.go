@@ -0,0 +1,184 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/cartographer/pkg/artifact"
+)
+
+var _ = Describe("Resolver", func() {
+	var (
+		server   *httptest.Server
+		requests []string
+		bodies   map[string]string
+		resolver artifact.Resolver
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = map[string]string{}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.EscapedPath())
+			body, ok := bodies[r.URL.EscapedPath()]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}))
+
+		resolver = artifact.NewResolver(server.Client())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("maven", func() {
+		BeforeEach(func() {
+			bodies["/maven2/org/springframework/spring-petclinic/maven-metadata.xml"] = `
+				<metadata>
+					<groupId>org.springframework</groupId>
+					<artifactId>spring-petclinic</artifactId>
+					<versioning>
+						<latest>2.6.0-SNAPSHOT</latest>
+						<release>2.5.0</release>
+					</versioning>
+				</metadata>`
+		})
+
+		It("resolves the latest release", func() {
+			resolved, err := resolver.Resolve(context.TODO(), artifact.Coordinate{
+				Type:     "maven",
+				Registry: server.URL + "/maven2/",
+				Name:     "org.springframework:spring-petclinic",
+			}, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resolved).To(Equal(&artifact.Artifact{
+				URL:     server.URL + "/maven2/org/springframework/spring-petclinic/2.5.0/spring-petclinic-2.5.0.jar",
+				Version: "2.5.0",
+			}))
+		})
+
+		It("does not poll the registry for a pinned version", func() {
+			resolved, err := resolver.Resolve(context.TODO(), artifact.Coordinate{
+				Type:     "maven",
+				Registry: server.URL + "/maven2",
+				Name:     "org.springframework:spring-petclinic",
+				Version:  "2.4.5",
+			}, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resolved.URL).To(Equal(server.URL + "/maven2/org/springframework/spring-petclinic/2.4.5/spring-petclinic-2.4.5.jar"))
+			Expect(requests).To(BeEmpty())
+		})
+
+		It("rejects names that are not groupId:artifactId", func() {
+			_, err := resolver.Resolve(context.TODO(), artifact.Coordinate{
+				Type:     "maven",
+				Registry: server.URL + "/maven2",
+				Name:     "spring-petclinic",
+			}, time.Minute)
+			Expect(err).To(MatchError("maven artifact name 'spring-petclinic' is not groupId:artifactId"))
+		})
+	})
+
+	Describe("npm", func() {
+		BeforeEach(func() {
+			bodies["/@example%2Fwidget"] = fmt.Sprintf(`{
+				"dist-tags": {"latest": "1.2.0"},
+				"versions": {
+					"1.1.0": {"dist": {"tarball": "%[1]s/@example/widget/-/widget-1.1.0.tgz"}},
+					"1.2.0": {"dist": {"tarball": "%[1]s/@example/widget/-/widget-1.2.0.tgz"}}
+				}
+			}`, server.URL)
+		})
+
+		It("resolves the latest dist-tag of a scoped package", func() {
+			resolved, err := resolver.Resolve(context.TODO(), artifact.Coordinate{
+				Type:     "npm",
+				Registry: server.URL,
+				Name:     "@example/widget",
+			}, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resolved).To(Equal(&artifact.Artifact{
+				URL:     server.URL + "/@example/widget/-/widget-1.2.0.tgz",
+				Version: "1.2.0",
+			}))
+		})
+
+		It("resolves a pinned version", func() {
+			resolved, err := resolver.Resolve(context.TODO(), artifact.Coordinate{
+				Type:     "npm",
+				Registry: server.URL,
+				Name:     "@example/widget",
+				Version:  "1.1.0",
+			}, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved.Version).To(Equal("1.1.0"))
+		})
+
+		It("returns an error for an unknown version", func() {
+			_, err := resolver.Resolve(context.TODO(), artifact.Coordinate{
+				Type:     "npm",
+				Registry: server.URL,
+				Name:     "@example/widget",
+				Version:  "9.9.9",
+			}, time.Minute)
+			Expect(err).To(MatchError("npm package '@example/widget' has no version '9.9.9'"))
+		})
+	})
+
+	It("polls the registry at most once per poll interval", func() {
+		bodies["/left-pad"] = `{"dist-tags": {"latest": "1.3.0"}, "versions": {"1.3.0": {"dist": {"tarball": "https://example.com/left-pad-1.3.0.tgz"}}}}`
+		coordinate := artifact.Coordinate{Type: "npm", Registry: server.URL, Name: "left-pad"}
+
+		_, err := resolver.Resolve(context.TODO(), coordinate, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = resolver.Resolve(context.TODO(), coordinate, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+
+		_, err = resolver.Resolve(context.TODO(), coordinate, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(HaveLen(2))
+	})
+
+	It("returns an error when the registry does not respond with the artifact", func() {
+		_, err := resolver.Resolve(context.TODO(), artifact.Coordinate{
+			Type:     "npm",
+			Registry: server.URL,
+			Name:     "missing",
+		}, time.Minute)
+		Expect(err).To(MatchError(fmt.Sprintf("registry responded 404 to '%s/missing'", server.URL)))
+	})
+
+	It("returns an error for unknown types", func() {
+		_, err := resolver.Resolve(context.TODO(), artifact.Coordinate{Type: "pypi"}, time.Minute)
+		Expect(err).To(MatchError("unknown artifact type 'pypi'"))
+	})
+})
@@ -0,0 +1,128 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records every create and update of a stamped object, with
+// the fields an update changed, so that what Cartographer changed, and when,
+// can be answered without the audit log of the API server.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/identity"
+)
+
+const (
+	CreateAction = "create"
+	UpdateAction = "update"
+)
+
+// Auditor is told of every stamped object that was written: before is the
+// object as it was on the cluster, or nil when it was created, and after is
+// the object as it was written.
+type Auditor interface {
+	Audit(before, after *unstructured.Unstructured)
+}
+
+// Record is a line of the audit log.
+type Record struct {
+	Time       time.Time `json:"time"`
+	Action     string    `json:"action"`
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Name       string    `json:"name"`
+	Owner      Owner     `json:"owner"`
+	// Changes are the fields an update changed, and are empty for a create.
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Owner is the Workload or Pipeline the object was stamped for, and the
+// resource of its blueprint it was stamped from.
+type Owner struct {
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Resource  string `json:"resource,omitempty"`
+}
+
+// Change is a field that an update changed, added or removed. Old is absent
+// for a field that was added, and New for a field that was removed.
+type Change struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Log writes a Record as a line of JSON for every create, and for every
+// update that changed a field.
+type Log struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	now     func() time.Time
+}
+
+func NewLog(w io.Writer) *Log {
+	return &Log{encoder: json.NewEncoder(w), now: time.Now}
+}
+
+// Open appends the log to the file at path, or writes it to stdout when path
+// is "-".
+func Open(path string) (*Log, error) {
+	if path == "-" {
+		return NewLog(os.Stdout), nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	return NewLog(file), nil
+}
+
+func (l *Log) Audit(before, after *unstructured.Unstructured) {
+	labels := after.GetLabels()
+	record := Record{
+		Action:     CreateAction,
+		APIVersion: after.GetAPIVersion(),
+		Kind:       after.GetKind(),
+		Namespace:  after.GetNamespace(),
+		Name:       after.GetName(),
+		Owner: Owner{
+			Kind:      labels[identity.OwnerKindLabel],
+			Namespace: labels[identity.OwnerNamespaceLabel],
+			Name:      labels[identity.OwnerNameLabel],
+			Resource:  labels[identity.ResourceNameLabel],
+		},
+	}
+	if before != nil {
+		record.Action = UpdateAction
+		record.Changes = Diff(before, after)
+		if len(record.Changes) == 0 {
+			return
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	record.Time = l.now().UTC()
+	_ = l.encoder.Encode(record)
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/audit"
+)
+
+var _ = Describe("Log", func() {
+	var (
+		buffer *bytes.Buffer
+		log    *audit.Log
+		before *unstructured.Unstructured
+		after  *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		log = audit.NewLog(buffer)
+
+		before = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":            "petclinic",
+				"namespace":       "dev",
+				"resourceVersion": "1",
+				"labels": map[string]interface{}{
+					"carto.run/owner-kind":      "Workload",
+					"carto.run/owner-name":      "petclinic",
+					"carto.run/owner-namespace": "dev",
+					"carto.run/resource-name":   "deployer",
+				},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+				"paused":   false,
+			},
+			"status": map[string]interface{}{"readyReplicas": int64(1)},
+		}}
+		after = before.DeepCopy()
+	})
+
+	It("records a create without changes", func() {
+		log.Audit(nil, after)
+
+		Expect(buffer.String()).To(MatchRegexp(`^\{"time":"[^"]+",`))
+		Expect(buffer.String()).To(ContainSubstring(`"action":"create","apiVersion":"apps/v1","kind":"Deployment","namespace":"dev","name":"petclinic","owner":{"kind":"Workload","namespace":"dev","name":"petclinic","resource":"deployer"}}`))
+	})
+
+	It("records the fields an update changed, added and removed", func() {
+		Expect(unstructured.SetNestedField(after.Object, int64(3), "spec", "replicas")).To(Succeed())
+		Expect(unstructured.SetNestedField(after.Object, "v2", "metadata", "annotations", "carto.run/revision")).To(Succeed())
+		unstructured.RemoveNestedField(after.Object, "spec", "paused")
+
+		log.Audit(before, after)
+
+		Expect(buffer.String()).To(ContainSubstring(`"action":"update"`))
+		Expect(buffer.String()).To(ContainSubstring(`"changes":[` +
+			`{"path":"metadata.annotations[\"carto.run/revision\"]","new":"v2"},` +
+			`{"path":"spec.paused","old":false},` +
+			`{"path":"spec.replicas","old":1,"new":3}]`))
+	})
+
+	It("does not record an update that changed only what the API server maintains", func() {
+		after.SetResourceVersion("2")
+		Expect(unstructured.SetNestedField(after.Object, int64(3), "status", "readyReplicas")).To(Succeed())
+
+		log.Audit(before, after)
+
+		Expect(buffer.String()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Diff", func() {
+	It("redacts the values of a secret", func() {
+		before := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"data":       map[string]interface{}{"password": "aHVudGVyMg=="},
+		}}
+		after := before.DeepCopy()
+		after.Object["data"] = map[string]interface{}{"password": "c3dvcmRmaXNo"}
+
+		Expect(audit.Diff(before, after)).To(Equal([]audit.Change{
+			{Path: "data.password", Old: "<redacted>", New: "<redacted>"},
+		}))
+	})
+})
+
+var _ = Describe("Drift", func() {
+	It("lists the fields the desired object sets that differ on the live one", func() {
+		live := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "petclinic", "resourceVersion": "42"},
+			"spec": map[string]interface{}{
+				"replicas":             int64(1),
+				"revisionHistoryLimit": int64(10),
+				"strategy":             map[string]interface{}{"type": "RollingUpdate"},
+			},
+		}}
+		desired := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "petclinic", "labels": map[string]interface{}{"app": "petclinic"}},
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+				"strategy": map[string]interface{}{"type": "Recreate"},
+			},
+		}}
+
+		Expect(audit.Drift(live, desired)).To(Equal([]audit.Change{
+			{Path: "metadata.labels.app", New: "petclinic"},
+			{Path: "spec.strategy.type", Old: "RollingUpdate", New: "Recreate"},
+		}))
+	})
+})
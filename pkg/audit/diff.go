@@ -0,0 +1,126 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const redacted = "<redacted>"
+
+// ignoredPaths are the fields the API server maintains, which change with
+// every write whether or not the stamped object did.
+var ignoredPaths = map[string]bool{
+	"metadata.resourceVersion":   true,
+	"metadata.generation":        true,
+	"metadata.managedFields":     true,
+	"metadata.uid":               true,
+	"metadata.creationTimestamp": true,
+	"metadata.selfLink":          true,
+	"status":                     true,
+}
+
+// Diff lists the fields that differ between before and after, ordered by
+// path. Lists are compared as a whole. The fields the API server maintains,
+// and the last applied configuration annotations, are left out, and the
+// values of a secret are redacted.
+func Diff(before, after *unstructured.Unstructured) []Change {
+	oldFields := flatten(before.Object)
+	newFields := flatten(after.Object)
+
+	var changes []Change
+	for path, oldValue := range oldFields {
+		newValue, ok := newFields[path]
+		if !ok {
+			changes = append(changes, Change{Path: path, Old: oldValue})
+		} else if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, Change{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	for path, newValue := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			changes = append(changes, Change{Path: path, New: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	if after.GetAPIVersion() == "v1" && after.GetKind() == "Secret" {
+		for i, change := range changes {
+			if strings.HasPrefix(change.Path, "data.") || strings.HasPrefix(change.Path, "stringData.") {
+				if change.Old != nil {
+					changes[i].Old = redacted
+				}
+				if change.New != nil {
+					changes[i].New = redacted
+				}
+			}
+		}
+	}
+
+	return changes
+}
+
+// Drift lists the fields desired sets that differ on live, ordered by path,
+// such as a field the API server defaults to another value than the one
+// desired. The fields that only live has are left out.
+func Drift(live, desired *unstructured.Unstructured) []Change {
+	var drift []Change
+	for _, change := range Diff(live, desired) {
+		if change.New != nil {
+			drift = append(drift, change)
+		}
+	}
+	return drift
+}
+
+// flatten maps the path of every field that is not a map to its value.
+func flatten(object map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	var walk func(prefix string, value map[string]interface{})
+	walk = func(prefix string, value map[string]interface{}) {
+		for key, field := range value {
+			path := join(prefix, key)
+			if ignoredPaths[path] || strings.HasSuffix(key, "last-applied-configuration") {
+				continue
+			}
+			if nested, ok := field.(map[string]interface{}); ok && len(nested) > 0 {
+				walk(path, nested)
+				continue
+			}
+			fields[path] = field
+		}
+	}
+	walk("", object)
+	return fields
+}
+
+// join appends a key to a path, quoting keys that hold dots or slashes, as
+// the keys of labels and annotations do.
+func join(prefix, key string) string {
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf("%s[%q]", prefix, key)
+	}
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -20,6 +20,8 @@ import (
 	"reflect"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 )
 
 // To learn more about condition conventions:
@@ -108,15 +110,20 @@ func (c *conditionManager) Add(condition metav1.Condition, polarity Polarity) {
 			isNewCondition = false
 			lastTransitionTime := condition.LastTransitionTime
 			condition.LastTransitionTime = previousCondition.LastTransitionTime
+			// observing the condition on a later generation does not
+			// change it by itself
+			previousCondition.ObservedGeneration = condition.ObservedGeneration
 			if !reflect.DeepEqual(previousCondition, condition) {
 				condition.LastTransitionTime = lastTransitionTime
 				c.changed = true
+				ReasonsTotal.WithLabelValues(condition.Reason).Inc()
 			}
 		}
 	}
 
 	if isNewCondition {
 		c.changed = true
+		ReasonsTotal.WithLabelValues(condition.Reason).Inc()
 	}
 
 	c.conditions = append(c.conditions, condition)
@@ -137,10 +144,11 @@ func (c *conditionManager) AddNegative(condition metav1.Condition) {
 func (c *conditionManager) Finalize() ([]metav1.Condition, bool) {
 	if len(c.conditions) == 0 {
 		c.status = metav1.ConditionFalse
+		ReasonsTotal.WithLabelValues(v1alpha1.UnknownConditionReason).Inc()
 		return []metav1.Condition{{
 			Type:               c.topLevelType,
 			Status:             "Unknown",
-			Reason:             "Unknown",
+			Reason:             v1alpha1.UnknownConditionReason,
 			LastTransitionTime: metav1.Now(),
 		}}, true
 	}
@@ -479,5 +479,37 @@ var _ = Describe("conditionManager", func() {
 			})
 		})
 
+		Context("when our conditions were observed on an earlier generation", func() {
+			BeforeEach(func() {
+				conditions.ObserveGeneration(firstConditions, 1)
+				manager = conditions.NewConditionManager("HappyParent", firstConditions)
+				manager.AddPositive(goodnessCondition)
+			})
+
+			It("does not consider them changed, and keeps the time of their last transition", func() {
+				newConditions, changed := manager.Finalize()
+				Expect(changed).To(BeFalse())
+
+				conditions.ObserveGeneration(newConditions, 2)
+				for i := range newConditions {
+					Expect(newConditions[i].ObservedGeneration).To(Equal(int64(2)))
+					Expect(newConditions[i].LastTransitionTime).To(Equal(firstConditions[i].LastTransitionTime))
+				}
+			})
+		})
+	})
+})
+
+var _ = Describe("ObserveGeneration", func() {
+	It("records the generation on the conditions that do not record one", func() {
+		observed := []metav1.Condition{
+			{Type: "Ready", Status: metav1.ConditionFalse},
+			{Type: "Healthy", Status: metav1.ConditionTrue, ObservedGeneration: 3},
+		}
+
+		conditions.ObserveGeneration(observed, 4)
+
+		Expect(observed[0].ObservedGeneration).To(Equal(int64(4)))
+		Expect(observed[1].ObservedGeneration).To(Equal(int64(3)))
 	})
 })
@@ -0,0 +1,82 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditions
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// ConditionChangedEventReason is the reason of the event recorded when the
+// status of a condition changes.
+const ConditionChangedEventReason = "ConditionChanged"
+
+// The annotations of a ConditionChanged event, for alerting on the
+// transitions of a condition without parsing the message.
+const (
+	ConditionTypeAnnotation           = "carto.run/condition-type"
+	ConditionStatusAnnotation         = "carto.run/condition-status"
+	PreviousConditionStatusAnnotation = "carto.run/previous-condition-status"
+	ConditionReasonAnnotation         = "carto.run/condition-reason"
+	ComponentAnnotation               = "carto.run/component"
+)
+
+// RecordTransitions records a ConditionChanged event on the object for each
+// condition whose status differs from that of the previous condition of its
+// type. A condition without a previous one has not changed. The event is a
+// warning when the condition became unsuccessful: False, or True for one of
+// the negative types. component names the component the conditions are of,
+// or is empty for the conditions of the object itself.
+func RecordTransitions(recorder record.EventRecorder, object runtime.Object, component string, previous, current []metav1.Condition, negativeTypes ...string) {
+	for _, condition := range current {
+		was := meta.FindStatusCondition(previous, condition.Type)
+		if was == nil || was.Status == condition.Status {
+			continue
+		}
+
+		unsuccessful := metav1.ConditionFalse
+		for _, negativeType := range negativeTypes {
+			if condition.Type == negativeType {
+				unsuccessful = metav1.ConditionTrue
+			}
+		}
+		eventType := corev1.EventTypeNormal
+		if condition.Status == unsuccessful {
+			eventType = corev1.EventTypeWarning
+		}
+
+		annotations := map[string]string{
+			ConditionTypeAnnotation:           condition.Type,
+			ConditionStatusAnnotation:         string(condition.Status),
+			PreviousConditionStatusAnnotation: string(was.Status),
+			ConditionReasonAnnotation:         condition.Reason,
+		}
+		message := fmt.Sprintf("%s changed from %s to %s (%s)", condition.Type, was.Status, condition.Status, condition.Reason)
+		if condition.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, condition.Message)
+		}
+		if component != "" {
+			annotations[ComponentAnnotation] = component
+			message = fmt.Sprintf("component '%s': %s", component, message)
+		}
+
+		recorder.AnnotatedEventf(object, annotations, eventType, ConditionChangedEventReason, "%s", message)
+	}
+}
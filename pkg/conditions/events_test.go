@@ -0,0 +1,117 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditions_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+)
+
+type annotatedEvent struct {
+	eventType, reason, message string
+	annotations                map[string]string
+}
+
+type annotatingRecorder struct {
+	events []annotatedEvent
+}
+
+func (r *annotatingRecorder) Event(object runtime.Object, eventType, reason, message string) {
+	r.AnnotatedEventf(object, nil, eventType, reason, "%s", message)
+}
+
+func (r *annotatingRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedEventf(object, nil, eventType, reason, messageFmt, args...)
+}
+
+func (r *annotatingRecorder) AnnotatedEventf(_ runtime.Object, annotations map[string]string, eventType, reason, messageFmt string, args ...interface{}) {
+	r.events = append(r.events, annotatedEvent{eventType: eventType, reason: reason, message: fmt.Sprintf(messageFmt, args...), annotations: annotations})
+}
+
+var _ = Describe("RecordTransitions", func() {
+	var (
+		recorder *annotatingRecorder
+		pipeline *v1alpha1.Pipeline
+		previous []metav1.Condition
+	)
+
+	BeforeEach(func() {
+		recorder = &annotatingRecorder{}
+		pipeline = &v1alpha1.Pipeline{}
+		previous = []metav1.Condition{
+			{Type: "RunTemplateReady", Status: metav1.ConditionTrue, Reason: "Ready"},
+			{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready"},
+		}
+	})
+
+	It("records a warning when a condition becomes unsuccessful, with its reason", func() {
+		conditions.RecordTransitions(recorder, pipeline, "", previous, []metav1.Condition{
+			{Type: "RunTemplateReady", Status: metav1.ConditionFalse, Reason: "TemplateStampFailure", Message: "could not stamp template"},
+			{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready"},
+		})
+
+		Expect(recorder.events).To(Equal([]annotatedEvent{{
+			eventType: "Warning",
+			reason:    "ConditionChanged",
+			message:   "RunTemplateReady changed from True to False (TemplateStampFailure): could not stamp template",
+			annotations: map[string]string{
+				"carto.run/condition-type":            "RunTemplateReady",
+				"carto.run/condition-status":          "False",
+				"carto.run/previous-condition-status": "True",
+				"carto.run/condition-reason":          "TemplateStampFailure",
+			},
+		}}))
+	})
+
+	It("records a normal event when a condition recovers, naming its component", func() {
+		previous[0].Status = metav1.ConditionUnknown
+
+		conditions.RecordTransitions(recorder, pipeline, "image", previous, []metav1.Condition{
+			{Type: "RunTemplateReady", Status: metav1.ConditionTrue, Reason: "Ready"},
+		})
+
+		Expect(recorder.events).To(HaveLen(1))
+		Expect(recorder.events[0].eventType).To(Equal("Normal"))
+		Expect(recorder.events[0].message).To(Equal("component 'image': RunTemplateReady changed from Unknown to True (Ready)"))
+		Expect(recorder.events[0].annotations).To(HaveKeyWithValue("carto.run/component", "image"))
+	})
+
+	It("records a warning when a negative condition becomes True", func() {
+		previous = []metav1.Condition{{Type: "RealizationDeadlineExceeded", Status: metav1.ConditionFalse, Reason: "WithinDeadline"}}
+
+		conditions.RecordTransitions(recorder, pipeline, "", previous, []metav1.Condition{
+			{Type: "RealizationDeadlineExceeded", Status: metav1.ConditionTrue, Reason: "DeadlineExceeded"},
+		}, "RealizationDeadlineExceeded")
+
+		Expect(recorder.events).To(HaveLen(1))
+		Expect(recorder.events[0].eventType).To(Equal("Warning"))
+	})
+
+	It("records nothing for a condition that is new or whose status did not change", func() {
+		conditions.RecordTransitions(recorder, pipeline, "", previous, []metav1.Condition{
+			{Type: "RunTemplateReady", Status: metav1.ConditionTrue, Reason: "Ready", Message: "a new message"},
+			{Type: "Paused", Status: metav1.ConditionTrue, Reason: "PauseRequested"},
+		})
+
+		Expect(recorder.events).To(BeEmpty())
+	})
+})
@@ -0,0 +1,31 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditions
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ObserveGeneration records on the conditions that do not record one yet
+// the generation of the object they were observed on, so that a client can
+// tell a condition about the current spec from a stale one. Conditions kept
+// from an earlier reconcile keep the generation they were observed on.
+func ObserveGeneration(conditions []metav1.Condition, generation int64) {
+	for i := range conditions {
+		if conditions[i].ObservedGeneration == 0 {
+			conditions[i].ObservedGeneration = generation
+		}
+	}
+}
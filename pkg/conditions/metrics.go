@@ -0,0 +1,40 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditions
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// ReasonsTotal counts the conditions that changed to each reason. Every
+// catalogued reason is exported from start up, so that alerts on a reason
+// see a zero rather than a missing series.
+var ReasonsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cartographer_condition_reasons_total",
+		Help: "Number of conditions that changed to each reason",
+	},
+	[]string{"reason"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(ReasonsTotal)
+	for _, reason := range v1alpha1.Reasons {
+		ReasonsTotal.WithLabelValues(reason)
+	}
+}
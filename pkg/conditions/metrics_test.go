@@ -0,0 +1,71 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditions_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+)
+
+var _ = Describe("ReasonsTotal", func() {
+	count := func(reason string) float64 {
+		return testutil.ToFloat64(conditions.ReasonsTotal.WithLabelValues(reason))
+	}
+
+	It("exports a series for every catalogued reason", func() {
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		var exported []string
+		for _, family := range families {
+			if family.GetName() != "cartographer_condition_reasons_total" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					exported = append(exported, label.GetValue())
+				}
+			}
+		}
+
+		Expect(exported).To(ContainElements(v1alpha1.Reasons))
+	})
+
+	It("counts conditions that change to a reason", func() {
+		before := count("SomeReason")
+
+		previous := []metav1.Condition{{Type: "Goodness", Status: metav1.ConditionTrue, Reason: "OtherReason"}}
+		manager := conditions.NewConditionManager("HappyParent", previous)
+		manager.AddPositive(metav1.Condition{Type: "Goodness", Status: metav1.ConditionFalse, Reason: "SomeReason"})
+
+		Expect(count("SomeReason")).To(Equal(before + 1))
+	})
+
+	It("does not count conditions that are unchanged", func() {
+		condition := metav1.Condition{Type: "Goodness", Status: metav1.ConditionTrue, Reason: "SteadyReason"}
+		manager := conditions.NewConditionManager("HappyParent", []metav1.Condition{condition})
+		before := count("SteadyReason")
+
+		manager.AddPositive(condition)
+
+		Expect(count("SteadyReason")).To(Equal(before))
+	})
+})
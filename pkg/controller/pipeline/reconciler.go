@@ -17,12 +17,21 @@ package pipeline
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
@@ -30,24 +39,38 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/conditions"
 	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/pipeline"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/tracing"
 )
 
 type Reconciler interface {
 	Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error)
 	AddTracking(dynamicTracker DynamicTracker)
+	// SetResyncInterval sets how often a pipeline is reconciled again when
+	// nothing about it changed. By default, and when zero, it is only
+	// reconciled on changes.
+	SetResyncInterval(interval time.Duration)
 }
 
-func NewReconciler(repository repository.Repository, realizer realizer.Realizer) Reconciler {
+// OutputsUpdatedEventReason is the reason of the event recorded on a
+// pipeline when the outputs of its run change. A failure is recorded with
+// the reason of the condition that reports it.
+const OutputsUpdatedEventReason = "OutputsUpdated"
+
+func NewReconciler(repository repository.Repository, realizer realizer.Realizer, recorder record.EventRecorder) Reconciler {
 	return &reconciler{
 		repository: repository,
 		realizer:   realizer,
+		recorder:   recorder,
 	}
 }
 
 type reconciler struct {
 	repository     repository.Repository
 	realizer       realizer.Realizer
+	recorder       record.EventRecorder
 	dynamicTracker DynamicTracker
+	resyncInterval time.Duration
 }
 
 //counterfeiter:generate . DynamicTracker
@@ -55,11 +78,18 @@ type DynamicTracker interface {
 	Watch(log logr.Logger, obj runtime.Object, handler handler.EventHandler) error
 }
 
+func (r *reconciler) SetResyncInterval(interval time.Duration) {
+	r.resyncInterval = interval
+}
+
 func (r *reconciler) AddTracking(dynamicTracker DynamicTracker) {
 	r.dynamicTracker = dynamicTracker
 }
 
-func (r *reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+func (r *reconciler) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "Reconcile Pipeline", tracing.String("namespace", request.Namespace), tracing.String("name", request.Name))
+	defer func() { span.End(err) }()
+
 	logger := logr.FromContext(ctx).
 		WithValues("name", request.Name, "namespace", request.Namespace)
 	logger.Info("started")
@@ -76,6 +106,14 @@ func (r *reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 		return ctrl.Result{}, err
 	}
 
+	if pipeline.DeletionTimestamp != nil {
+		return r.teardown(logger, pipeline)
+	}
+
+	if err := r.ensureTeardownFinalizer(pipeline); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	condition, outputs, stampedObject := r.realizer.Realize(ctx, pipeline, logger, r.repository)
 	if stampedObject != nil {
 		err = r.dynamicTracker.Watch(logger, stampedObject, &handler.EnqueueRequestForOwner{OwnerType: &v1alpha1.Pipeline{}})
@@ -84,10 +122,20 @@ func (r *reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 		}
 	}
 
+	if newFailure(pipeline.Status.Conditions, condition) {
+		r.recorder.Event(pipeline, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+	if changed := changedOutputs(pipeline.Status.Outputs, outputs); len(changed) > 0 {
+		r.recorder.Eventf(pipeline, corev1.EventTypeNormal, OutputsUpdatedEventReason, "run provides new outputs: %s", strings.Join(changed, ", "))
+	}
+
+	previous := pipeline.Status.Conditions
 	conditionManager := conditions.NewConditionManager(v1alpha1.PipelineReady, pipeline.Status.Conditions)
 	conditionManager.AddPositive(*condition)
 	//TODO: deal with changed (story #84)
 	pipeline.Status.Conditions, _ = conditionManager.Finalize()
+	conditions.ObserveGeneration(pipeline.Status.Conditions, pipeline.Generation)
+	pipeline.Status.ObservedGeneration = pipeline.Generation
 	pipeline.Status.Outputs = outputs
 
 	statusUpdateError := r.repository.StatusUpdate(pipeline)
@@ -95,6 +143,69 @@ func (r *reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 		logger.Info("finished")
 		return ctrl.Result{}, fmt.Errorf("update pipeline status: %w", statusUpdateError)
 	}
+	conditions.RecordTransitions(r.recorder, pipeline, "", previous, pipeline.Status.Conditions)
+
+	// A kind that is not installed yet, e.g. before Tekton is, is retried
+	// with the controller's backoff until it is.
+	if condition.Reason == v1alpha1.MissingAPIDependencyRunTemplateReason {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if condition.Reason == v1alpha1.RetryBackoffRunTemplateReason {
+		if retryAt, ok := realizer.RetryAt(pipeline); ok {
+			if wait := time.Until(retryAt); wait > 0 {
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
+	requeueAfter := r.resyncInterval
+	if ttl := pipeline.Spec.TTLSecondsAfterFinished; ttl != nil && *ttl > 0 {
+		requeueAfter = time.Duration(*ttl) * time.Second
+	}
+
+	// A run still in progress is checked again when it times out, in case
+	// nothing else about it changes before then.
+	if timeoutAt, ok := realizer.TimeoutAt(pipeline); ok {
+		wait := time.Until(timeoutAt)
+		if wait <= 0 {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		if requeueAfter == 0 || wait < requeueAfter {
+			requeueAfter = wait
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// newFailure is whether the condition reports a failure that no event was
+// recorded for yet: the pipeline failed before it had the condition, or
+// failed again with another reason or message. A condition that turns False
+// is recorded by its transition instead.
+func newFailure(previous []metav1.Condition, condition *metav1.Condition) bool {
+	if condition.Status != metav1.ConditionFalse {
+		return false
+	}
+
+	was := meta.FindStatusCondition(previous, condition.Type)
+	if was == nil {
+		return true
+	}
+	return was.Status == metav1.ConditionFalse &&
+		(was.Reason != condition.Reason || was.Message != condition.Message)
+}
 
-	return ctrl.Result{}, nil
+// changedOutputs names the outputs whose values differ from the previous
+// ones, in alphabetical order.
+func changedOutputs(previous map[string]apiextensionsv1.JSON, current templates.Outputs) []string {
+	var changed []string
+	for name, value := range current {
+		if !bytes.Equal(previous[name].Raw, value.Raw) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
 }
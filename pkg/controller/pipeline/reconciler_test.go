@@ -17,6 +17,7 @@ package pipeline_test
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
@@ -29,6 +30,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -38,6 +41,7 @@ import (
 	pipelinefakes2 "github.com/vmware-tanzu/cartographer/pkg/controller/pipeline/pipelinefakes"
 	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/pipeline"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/pipeline/pipelinefakes"
+	repo "github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
@@ -51,6 +55,7 @@ var _ = Describe("Reconcile", func() {
 		repository     *repositoryfakes.FakeRepository
 		rlzr           *pipelinefakes.FakeRealizer
 		dynamicTracker *pipelinefakes2.FakeDynamicTracker
+		recorder       *record.FakeRecorder
 	)
 
 	BeforeEach(func() {
@@ -61,7 +66,9 @@ var _ = Describe("Reconcile", func() {
 		rlzr = &pipelinefakes.FakeRealizer{}
 		dynamicTracker = &pipelinefakes2.FakeDynamicTracker{}
 
-		reconciler = pipeline.NewReconciler(repository, rlzr)
+		recorder = record.NewFakeRecorder(10)
+
+		reconciler = pipeline.NewReconciler(repository, rlzr, recorder)
 		reconciler.AddTracking(dynamicTracker)
 
 		request = controllerruntime.Request{
@@ -80,8 +87,9 @@ var _ = Describe("Reconcile", func() {
 					APIVersion: "carto.run/v1alpha1",
 				},
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      "my-pipeline",
-					Namespace: "my-namespace",
+					Name:       "my-pipeline",
+					Namespace:  "my-namespace",
+					Generation: 2,
 				},
 				Spec: v1alpha1.PipelineSpec{
 					RunTemplateRef: v1alpha1.TemplateReference{
@@ -174,6 +182,17 @@ var _ = Describe("Reconcile", func() {
 				))
 
 			})
+
+			It("records the generation the status was observed on", func() {
+				_, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+
+				statusObject := repository.StatusUpdateArgsForCall(0).(*v1alpha1.Pipeline)
+				Expect(statusObject.Status.ObservedGeneration).To(BeEquivalentTo(2))
+				for _, condition := range statusObject.Status.Conditions {
+					Expect(condition.ObservedGeneration).To(BeEquivalentTo(2))
+				}
+			})
 		})
 
 		Context("outputs are returned from the realizer", func() {
@@ -199,6 +218,69 @@ var _ = Describe("Reconcile", func() {
 				Expect(statusObject.Status.Outputs["an-output"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"the value"`)}))
 
 			})
+
+			It("records an event naming the outputs that changed", func() {
+				_, _ = reconciler.Reconcile(ctx, request)
+				Expect(recorder.Events).To(Receive(Equal("Normal OutputsUpdated run provides new outputs: an-output")))
+			})
+
+			It("records no event when the outputs are unchanged", func() {
+				repository.GetPipelineReturns(&v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "my-namespace"},
+					Status: v1alpha1.PipelineStatus{
+						Outputs: map[string]apiextensionsv1.JSON{"an-output": {Raw: []byte(`"the value"`)}},
+					},
+				}, nil)
+
+				_, _ = reconciler.Reconcile(ctx, request)
+				Expect(recorder.Events).To(BeEmpty())
+			})
+		})
+
+		Context("the realizer reports a failure", func() {
+			BeforeEach(func() {
+				rlzr.RealizeReturns(realizer.TemplateStampFailureCondition(errors.New("bad template")), nil, nil)
+			})
+
+			It("records a warning event", func() {
+				_, _ = reconciler.Reconcile(ctx, request)
+				Expect(recorder.Events).To(Receive(Equal("Warning TemplateStampFailure bad template")))
+			})
+
+			It("records no warning event again while the pipeline keeps failing the same way", func() {
+				failed := realizer.TemplateStampFailureCondition(errors.New("bad template"))
+				repository.GetPipelineReturns(&v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "my-namespace"},
+					Status:     v1alpha1.PipelineStatus{Conditions: []metav1.Condition{*failed}},
+				}, nil)
+
+				_, _ = reconciler.Reconcile(ctx, request)
+				Expect(recorder.Events).To(BeEmpty())
+			})
+
+			It("records a warning event when the pipeline fails another way", func() {
+				repository.GetPipelineReturns(&v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "my-namespace"},
+					Status: v1alpha1.PipelineStatus{Conditions: []metav1.Condition{
+						*realizer.TemplateStampFailureCondition(errors.New("another bad template")),
+					}},
+				}, nil)
+
+				_, _ = reconciler.Reconcile(ctx, request)
+				Expect(recorder.Events).To(Receive(Equal("Warning TemplateStampFailure bad template")))
+				Expect(recorder.Events).To(BeEmpty())
+			})
+
+			It("records only the transition when the pipeline starts failing", func() {
+				repository.GetPipelineReturns(&v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "my-namespace"},
+					Status:     v1alpha1.PipelineStatus{Conditions: []metav1.Condition{*realizer.RunTemplateReadyCondition()}},
+				}, nil)
+
+				_, _ = reconciler.Reconcile(ctx, request)
+				Expect(recorder.Events).To(Receive(HavePrefix("Warning ConditionChanged RunTemplateReady changed from True to False (TemplateStampFailure): bad template")))
+				Expect(recorder.Events).To(BeEmpty())
+			})
 		})
 
 		Context("updating the status fails", func() {
@@ -221,6 +303,126 @@ var _ = Describe("Reconcile", func() {
 			})
 		})
 
+		Context("the realizer is backing off before retrying a failed run", func() {
+			BeforeEach(func() {
+				repository.GetPipelineReturns(&v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-pipeline",
+						Namespace: "my-namespace",
+					},
+					Spec: v1alpha1.PipelineSpec{
+						RetryPolicy: &v1alpha1.RetryPolicy{
+							Limit:   1,
+							Backoff: &metav1.Duration{Duration: time.Minute},
+						},
+					},
+				}, nil)
+				rlzr.RealizeStub = func(ctx context.Context, pipeline *v1alpha1.Pipeline, logger logr.Logger, _ repo.Repository) (*metav1.Condition, templates.Outputs, *unstructured.Unstructured) {
+					failed := metav1.NewTime(time.Now().Add(-20 * time.Second))
+					pipeline.Status.RunHistory = []v1alpha1.RunRecord{{Name: "run-1", Result: v1alpha1.FailedRunResult, CompletionTime: &failed}}
+					return realizer.RetryBackoffCondition(errors.New("retrying")), nil, nil
+				}
+			})
+
+			It("requeues when the backoff since the run failed is over", func() {
+				result, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(BeNumerically("~", 40*time.Second, 2*time.Second))
+			})
+		})
+
+		Context("the kind of the stamped object is not installed", func() {
+			BeforeEach(func() {
+				rlzr.RealizeReturns(realizer.MissingAPIDependencyCondition(errors.New("no matches for kind")), nil, nil)
+			})
+
+			It("requeues with the backoff of the controller", func() {
+				result, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(controllerruntime.Result{Requeue: true}))
+			})
+		})
+
+		Context("the pipeline expires finished runs", func() {
+			BeforeEach(func() {
+				repository.GetPipelineReturns(&v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-pipeline",
+						Namespace: "my-namespace",
+					},
+					Spec: v1alpha1.PipelineSpec{
+						TTLSecondsAfterFinished: pointer.Int32Ptr(300),
+					},
+				}, nil)
+				rlzr.RealizeReturns(realizer.RunTemplateReadyCondition(), nil, nil)
+			})
+
+			It("requeues after the ttl", func() {
+				result, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(controllerruntime.Result{RequeueAfter: 5 * time.Minute}))
+			})
+		})
+
+		Context("the latest run is still in progress", func() {
+			var started time.Duration
+
+			BeforeEach(func() {
+				reconciler.SetResyncInterval(10 * time.Minute)
+				repository.GetPipelineReturns(&v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-pipeline",
+						Namespace: "my-namespace",
+					},
+					Spec: v1alpha1.PipelineSpec{
+						Timeout: &metav1.Duration{Duration: time.Minute},
+					},
+				}, nil)
+				rlzr.RealizeStub = func(ctx context.Context, pipeline *v1alpha1.Pipeline, logger logr.Logger, _ repo.Repository) (*metav1.Condition, templates.Outputs, *unstructured.Unstructured) {
+					start := metav1.NewTime(time.Now().Add(-started))
+					pipeline.Status.RunHistory = []v1alpha1.RunRecord{{Name: "run-1", Result: v1alpha1.RunningRunResult, StartTime: start}}
+					return realizer.RunTemplateReadyCondition(), nil, nil
+				}
+			})
+
+			Context("before the timeout", func() {
+				BeforeEach(func() {
+					started = 20 * time.Second
+				})
+
+				It("requeues when the run times out", func() {
+					result, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically("~", 40*time.Second, 2*time.Second))
+				})
+			})
+
+			Context("past the timeout", func() {
+				BeforeEach(func() {
+					started = 2 * time.Minute
+				})
+
+				It("requeues right away", func() {
+					result, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(Equal(controllerruntime.Result{Requeue: true}))
+				})
+			})
+		})
+
+		Context("the controller resyncs pipelines", func() {
+			BeforeEach(func() {
+				reconciler.SetResyncInterval(time.Minute)
+				rlzr.RealizeReturns(realizer.RunTemplateReadyCondition(), nil, nil)
+			})
+
+			It("requeues after the resync interval", func() {
+				result, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(controllerruntime.Result{RequeueAfter: time.Minute}))
+			})
+		})
+
 		Context("realizer could not stamp the object", func() {
 			BeforeEach(func() {
 				rlzr.RealizeReturns(realizer.RunTemplateReadyCondition(), nil, nil)
@@ -243,6 +445,122 @@ var _ = Describe("Reconcile", func() {
 		})
 	})
 
+	Context("teardown", func() {
+		var pl *v1alpha1.Pipeline
+
+		BeforeEach(func() {
+			pl = &v1alpha1.Pipeline{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-pipeline",
+					Namespace: "my-namespace",
+					UID:       "pipeline-uid",
+				},
+				Status: v1alpha1.PipelineStatus{
+					RunHistory: []v1alpha1.RunRecord{
+						{Name: "run-2", APIVersion: "tekton.dev/v1beta1", Kind: "TaskRun"},
+						{Name: "run-1", APIVersion: "tekton.dev/v1beta1", Kind: "TaskRun"},
+					},
+				},
+			}
+			repository.GetPipelineReturns(pl, nil)
+			rlzr.RealizeReturns(realizer.RunTemplateReadyCondition(), nil, nil)
+
+			repository.ListStampedObjectsStub = func(schema.GroupVersionKind, string, types.UID, string) ([]*unstructured.Unstructured, error) {
+				var runs []*unstructured.Unstructured
+				for _, name := range []string{"run-1", "run-2"} {
+					run := &unstructured.Unstructured{}
+					run.SetName(name)
+					run.SetNamespace("my-namespace")
+					run.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Pipeline", Name: "my-pipeline", UID: "pipeline-uid"}})
+					runs = append(runs, run)
+				}
+				return runs, nil
+			}
+		})
+
+		It("holds the deletion of the pipeline with a finalizer", func() {
+			_, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repository.UpdateCallCount()).To(Equal(1))
+			Expect(repository.UpdateArgsForCall(0).GetFinalizers()).To(ConsistOf("carto.run/teardown"))
+			Expect(rlzr.RealizeCallCount()).To(Equal(1))
+		})
+
+		It("returns an error when the finalizer cannot be added", func() {
+			repository.UpdateReturns(errors.New("some error"))
+			_, err := reconciler.Reconcile(ctx, request)
+
+			Expect(err).To(MatchError("update pipeline finalizers: some error"))
+			Expect(rlzr.RealizeCallCount()).To(Equal(0))
+		})
+
+		Context("when the pipeline is being deleted", func() {
+			BeforeEach(func() {
+				now := metav1.Now()
+				pl.DeletionTimestamp = &now
+				pl.Finalizers = []string{"carto.run/teardown"}
+			})
+
+			It("deletes the runs of every kind in the run history, in any namespace, and removes the finalizer", func() {
+				result, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(controllerruntime.Result{}))
+
+				Expect(repository.ListStampedObjectsCallCount()).To(Equal(1))
+				gvk, namespace, owner, resourceName := repository.ListStampedObjectsArgsForCall(0)
+				Expect(gvk).To(Equal(schema.GroupVersionKind{Group: "tekton.dev", Version: "v1beta1", Kind: "TaskRun"}))
+				Expect(namespace).To(BeEmpty())
+				Expect(owner).To(BeEquivalentTo("pipeline-uid"))
+				Expect(resourceName).To(Equal("run"))
+
+				Expect(repository.DeleteCallCount()).To(Equal(2))
+				Expect(repository.UpdateCallCount()).To(Equal(1))
+				Expect(repository.UpdateArgsForCall(0).GetFinalizers()).To(BeEmpty())
+				Expect(rlzr.RealizeCallCount()).To(Equal(0))
+			})
+
+			It("keeps the finalizer when a run cannot be deleted", func() {
+				repository.DeleteReturns(errors.New("some error"))
+				_, err := reconciler.Reconcile(ctx, request)
+
+				Expect(err).To(MatchError("teardown run 'run-1': some error"))
+				Expect(repository.UpdateCallCount()).To(Equal(0))
+			})
+
+			Context("with the Orphan policy", func() {
+				BeforeEach(func() {
+					pl.Spec.TeardownPolicy = v1alpha1.OrphanTeardownPolicy
+				})
+
+				It("removes the pipeline's references from the runs instead of deleting them", func() {
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(repository.DeleteCallCount()).To(Equal(0))
+					Expect(repository.UpdateCallCount()).To(Equal(3))
+					Expect(repository.UpdateArgsForCall(0).GetOwnerReferences()).To(BeEmpty())
+					Expect(repository.UpdateArgsForCall(1).GetOwnerReferences()).To(BeEmpty())
+					Expect(repository.UpdateArgsForCall(2).GetFinalizers()).To(BeEmpty())
+				})
+			})
+
+			Context("without the finalizer", func() {
+				BeforeEach(func() {
+					pl.Finalizers = nil
+				})
+
+				It("leaves the runs to the garbage collector", func() {
+					_, err := reconciler.Reconcile(ctx, request)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(repository.DeleteCallCount()).To(Equal(0))
+					Expect(repository.UpdateCallCount()).To(Equal(0))
+				})
+			})
+		})
+	})
+
 	Context("the pipeline goes away", func() {
 		BeforeEach(func() {
 			repository.GetPipelineReturns(nil, kerrors.NewNotFound(
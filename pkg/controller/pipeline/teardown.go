@@ -0,0 +1,111 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/identity"
+)
+
+// ensureTeardownFinalizer holds the deletion of a pipeline until its runs
+// have been deleted or orphaned.
+func (r *reconciler) ensureTeardownFinalizer(pipeline *v1alpha1.Pipeline) error {
+	if controllerutil.ContainsFinalizer(pipeline, v1alpha1.TeardownFinalizer) {
+		return nil
+	}
+
+	controllerutil.AddFinalizer(pipeline, v1alpha1.TeardownFinalizer)
+	if err := r.repository.Update(pipeline); err != nil {
+		return fmt.Errorf("update pipeline finalizers: %w", err)
+	}
+	return nil
+}
+
+// teardown deletes the runs of a deleted pipeline, including those stamped
+// into another namespace, or with the Orphan policy removes the pipeline's
+// references from them, before releasing the pipeline. The runs are found
+// by the kinds recorded in the run history.
+func (r *reconciler) teardown(logger logr.Logger, pipeline *v1alpha1.Pipeline) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(pipeline, v1alpha1.TeardownFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	runs, err := r.stampedRuns(pipeline)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("teardown: %w", err)
+	}
+
+	for _, run := range runs {
+		if pipeline.Spec.TeardownPolicy == v1alpha1.OrphanTeardownPolicy {
+			err = r.orphan(pipeline, run)
+		} else if run.GetDeletionTimestamp() == nil {
+			err = r.repository.Delete(run)
+		}
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("teardown run '%s': %w", run.GetName(), err)
+		}
+	}
+	logger.Info("tore down runs", "count", len(runs), "policy", pipeline.Spec.TeardownPolicy)
+
+	controllerutil.RemoveFinalizer(pipeline, v1alpha1.TeardownFinalizer)
+	if err := r.repository.Update(pipeline); err != nil {
+		return ctrl.Result{}, fmt.Errorf("update pipeline finalizers: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *reconciler) stampedRuns(pipeline *v1alpha1.Pipeline) ([]*unstructured.Unstructured, error) {
+	seen := map[schema.GroupVersionKind]bool{}
+	var runs []*unstructured.Unstructured
+	for _, record := range pipeline.Status.RunHistory {
+		gvk := schema.FromAPIVersionAndKind(record.APIVersion, record.Kind)
+		if record.Kind == "" || seen[gvk] {
+			continue
+		}
+		seen[gvk] = true
+
+		stamped, err := r.repository.ListStampedObjects(gvk, "", pipeline.UID, identity.RunResourceName)
+		if err != nil {
+			return nil, fmt.Errorf("list runs of kind '%s': %w", gvk.Kind, err)
+		}
+		runs = append(runs, stamped...)
+	}
+	return runs, nil
+}
+
+func (r *reconciler) orphan(pipeline *v1alpha1.Pipeline, run *unstructured.Unstructured) error {
+	refs := run.GetOwnerReferences()
+	var kept []metav1.OwnerReference
+	for _, ref := range refs {
+		if ref.UID != pipeline.UID {
+			kept = append(kept, ref)
+		}
+	}
+	if len(kept) == len(refs) {
+		return nil
+	}
+
+	run.SetOwnerReferences(kept)
+	return r.repository.Update(run)
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// KindFilteredTracker only watches stamped objects of the given kinds, so
+// that several instances of the controller can divide the informers for
+// stamped kinds between them. With no kinds, every stamped object is
+// watched.
+type KindFilteredTracker struct {
+	Tracker DynamicTracker
+	Kinds   []schema.GroupKind
+}
+
+func (t *KindFilteredTracker) Watch(log logr.Logger, obj runtime.Object, handler handler.EventHandler) error {
+	if !t.handles(obj.GetObjectKind().GroupVersionKind().GroupKind()) {
+		log.V(1).Info("not watching stamped object of a kind handled by another instance", "kind", obj.GetObjectKind().GroupVersionKind().String())
+		return nil
+	}
+
+	return t.Tracker.Watch(log, obj, handler)
+}
+
+func (t *KindFilteredTracker) handles(kind schema.GroupKind) bool {
+	if len(t.Kinds) == 0 {
+		return true
+	}
+
+	for _, handled := range t.Kinds {
+		if handled == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// CoalescingTracker delays the reconciles caused by updates to stamped
+// objects by Window, so that a stamped object whose status churns many
+// times a second enqueues its owner once per window rather than once per
+// update. Creates and deletes are not delayed. A zero Window coalesces
+// nothing.
+type CoalescingTracker struct {
+	Tracker DynamicTracker
+	Window  time.Duration
+}
+
+func (t *CoalescingTracker) Watch(log logr.Logger, obj runtime.Object, eventHandler handler.EventHandler) error {
+	if t.Window <= 0 {
+		return t.Tracker.Watch(log, obj, eventHandler)
+	}
+
+	return t.Tracker.Watch(log, obj, &coalescingHandler{EventHandler: eventHandler, window: t.Window})
+}
+
+type coalescingHandler struct {
+	handler.EventHandler
+	window time.Duration
+}
+
+func (h *coalescingHandler) Update(evt event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+	h.EventHandler.Update(evt, &delayedQueue{RateLimitingInterface: queue, delay: h.window})
+}
+
+// InjectFunc passes the fields the controller injects on to the wrapped
+// handler, which needs them to map stamped objects to their owners.
+func (h *coalescingHandler) InjectFunc(f inject.Func) error {
+	return f(h.EventHandler)
+}
+
+// delayedQueue adds items after a delay. The workqueue keeps a single
+// delayed entry per item, so the items added again while they wait are
+// dropped, which is what coalesces the events of an owner.
+type delayedQueue struct {
+	workqueue.RateLimitingInterface
+	delay time.Duration
+}
+
+func (q *delayedQueue) Add(item interface{}) {
+	q.AddAfter(item, q.delay)
+}
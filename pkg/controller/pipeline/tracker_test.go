@@ -0,0 +1,158 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+
+	"github.com/vmware-tanzu/cartographer/pkg/controller/pipeline"
+	"github.com/vmware-tanzu/cartographer/pkg/controller/pipeline/pipelinefakes"
+)
+
+var _ = Describe("KindFilteredTracker", func() {
+	var (
+		fakeTracker *pipelinefakes.FakeDynamicTracker
+		tracker     *pipeline.KindFilteredTracker
+		taskRun     *unstructured.Unstructured
+		deployment  *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		fakeTracker = &pipelinefakes.FakeDynamicTracker{}
+		tracker = &pipeline.KindFilteredTracker{Tracker: fakeTracker}
+
+		taskRun = &unstructured.Unstructured{}
+		taskRun.SetAPIVersion("tekton.dev/v1beta1")
+		taskRun.SetKind("TaskRun")
+
+		deployment = &unstructured.Unstructured{}
+		deployment.SetAPIVersion("apps/v1")
+		deployment.SetKind("Deployment")
+	})
+
+	It("watches every kind when no kinds are given", func() {
+		Expect(tracker.Watch(zap.New(), taskRun, &handler.EnqueueRequestForObject{})).To(Succeed())
+		Expect(tracker.Watch(zap.New(), deployment, &handler.EnqueueRequestForObject{})).To(Succeed())
+		Expect(fakeTracker.WatchCallCount()).To(Equal(2))
+	})
+
+	It("only watches the given kinds", func() {
+		tracker.Kinds = []schema.GroupKind{{Group: "tekton.dev", Kind: "TaskRun"}}
+
+		Expect(tracker.Watch(zap.New(), taskRun, &handler.EnqueueRequestForObject{})).To(Succeed())
+		Expect(tracker.Watch(zap.New(), deployment, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+		Expect(fakeTracker.WatchCallCount()).To(Equal(1))
+		_, obj, _ := fakeTracker.WatchArgsForCall(0)
+		Expect(obj).To(Equal(taskRun))
+	})
+})
+
+var _ = Describe("CoalescingTracker", func() {
+	var (
+		fakeTracker *pipelinefakes.FakeDynamicTracker
+		tracker     *pipeline.CoalescingTracker
+		taskRun     *unstructured.Unstructured
+		queue       workqueue.RateLimitingInterface
+		owner       reconcile.Request
+		ownerFuncs  handler.Funcs
+	)
+
+	BeforeEach(func() {
+		fakeTracker = &pipelinefakes.FakeDynamicTracker{}
+		tracker = &pipeline.CoalescingTracker{Tracker: fakeTracker, Window: 200 * time.Millisecond}
+
+		taskRun = &unstructured.Unstructured{}
+		taskRun.SetAPIVersion("tekton.dev/v1beta1")
+		taskRun.SetKind("TaskRun")
+
+		queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+
+		owner = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "my-pipeline"}}
+		enqueueOwner := func(q workqueue.RateLimitingInterface) { q.Add(owner) }
+		ownerFuncs = handler.Funcs{
+			CreateFunc: func(_ event.CreateEvent, q workqueue.RateLimitingInterface) { enqueueOwner(q) },
+			UpdateFunc: func(_ event.UpdateEvent, q workqueue.RateLimitingInterface) { enqueueOwner(q) },
+		}
+	})
+
+	AfterEach(func() {
+		queue.ShutDown()
+	})
+
+	watchedHandler := func() handler.EventHandler {
+		Expect(tracker.Watch(zap.New(), taskRun, ownerFuncs)).To(Succeed())
+		Expect(fakeTracker.WatchCallCount()).To(Equal(1))
+		_, _, eventHandler := fakeTracker.WatchArgsForCall(0)
+		return eventHandler
+	}
+
+	It("enqueues the owner once for a burst of updates", func() {
+		eventHandler := watchedHandler()
+
+		for i := 0; i < 10; i++ {
+			eventHandler.Update(event.UpdateEvent{ObjectOld: taskRun, ObjectNew: taskRun}, queue)
+		}
+
+		Expect(queue.Len()).To(Equal(0))
+		Eventually(queue.Len).Should(Equal(1))
+		Consistently(queue.Len, 300*time.Millisecond).Should(Equal(1))
+
+		item, _ := queue.Get()
+		Expect(item).To(Equal(owner))
+	})
+
+	It("passes injected fields on to the wrapped handler", func() {
+		eventHandler := watchedHandler()
+
+		var injected []interface{}
+		_, err := inject.InjectorInto(func(i interface{}) error {
+			injected = append(injected, i)
+			return nil
+		}, eventHandler)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(injected).To(HaveLen(1))
+	})
+
+	It("does not delay creates", func() {
+		eventHandler := watchedHandler()
+
+		eventHandler.Create(event.CreateEvent{Object: taskRun}, queue)
+
+		Expect(queue.Len()).To(Equal(1))
+	})
+
+	It("passes the handler through when the window is zero", func() {
+		tracker.Window = 0
+		eventHandler := watchedHandler()
+
+		eventHandler.Update(event.UpdateEvent{ObjectOld: taskRun, ObjectNew: taskRun}, queue)
+
+		Expect(queue.Len()).To(Equal(1))
+	})
+})
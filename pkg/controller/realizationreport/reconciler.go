@@ -0,0 +1,91 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizationreport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+)
+
+type Timer interface {
+	Now() metav1.Time
+}
+
+type Reconciler struct {
+	repo  repository.Repository
+	timer Timer
+}
+
+func NewReconciler(repo repository.Repository, timer Timer) *Reconciler {
+	return &Reconciler{
+		repo:  repo,
+		timer: timer,
+	}
+}
+
+// Reconcile regenerates the report once its interval has passed since it
+// was last generated, or when its spec changes, and requeues it for when
+// the next report is due.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContext(ctx).
+		WithValues("name", req.Name)
+	logger.Info("started")
+	defer logger.Info("finished")
+
+	report, err := r.repo.GetRealizationReport(req.Name)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("get realization report: %w", err)
+	}
+
+	now := r.timer.Now()
+	interval := report.ReportInterval()
+
+	if report.Status.ReportTime != nil && report.Status.ObservedGeneration == report.Generation {
+		if due := report.Status.ReportTime.Add(interval); now.Time.Before(due) {
+			return ctrl.Result{RequeueAfter: due.Sub(now.Time)}, nil
+		}
+	}
+
+	workloads, err := r.repo.ListWorkloads()
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("list workloads: %w", err)
+	}
+
+	supplyChains, err := r.repo.ListSupplyChains()
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("list supply chains: %w", err)
+	}
+
+	report.Status = Summarize(workloads, supplyChains, report.ReportLimit())
+	report.Status.ObservedGeneration = report.Generation
+	report.Status.ReportTime = &now
+
+	if err := r.repo.StatusUpdate(report); err != nil {
+		return ctrl.Result{}, fmt.Errorf("update realization report status: %w", err)
+	}
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
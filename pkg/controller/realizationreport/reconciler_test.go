@@ -0,0 +1,138 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizationreport_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/controller/realizationreport"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+)
+
+type fixedTimer struct {
+	now metav1.Time
+}
+
+func (t fixedTimer) Now() metav1.Time {
+	return t.now
+}
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx        context.Context
+		req        ctrl.Request
+		repo       *repositoryfakes.FakeRepository
+		now        metav1.Time
+		reconciler *realizationreport.Reconciler
+		report     *v1alpha1.RealizationReport
+	)
+
+	BeforeEach(func() {
+		ctx = logr.NewContext(context.Background(), zap.New())
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Name: "fleet"}}
+		now = metav1.NewTime(time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC))
+
+		repo = &repositoryfakes.FakeRepository{}
+		reconciler = realizationreport.NewReconciler(repo, fixedTimer{now: now})
+
+		report = &v1alpha1.RealizationReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "fleet", Generation: 1},
+		}
+		repo.GetRealizationReportReturns(report, nil)
+		repo.ListWorkloadsReturns([]v1alpha1.Workload{
+			{ObjectMeta: metav1.ObjectMeta{Name: "petclinic", Namespace: "dev"}},
+		}, nil)
+	})
+
+	It("generates the report and requeues when the next is due", func() {
+		result, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{RequeueAfter: 10 * time.Minute}))
+
+		Expect(repo.GetRealizationReportArgsForCall(0)).To(Equal("fleet"))
+		Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+		updated := repo.StatusUpdateArgsForCall(0).(*v1alpha1.RealizationReport)
+		Expect(updated.Status.Workloads).To(Equal(1))
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(*updated.Status.ReportTime).To(Equal(now))
+	})
+
+	It("generates the report on the interval of the spec", func() {
+		report.Spec.Interval = &metav1.Duration{Duration: time.Hour}
+		result, _ := reconciler.Reconcile(ctx, req)
+		Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+	})
+
+	Context("when the report was generated within the interval", func() {
+		BeforeEach(func() {
+			reportTime := metav1.NewTime(now.Add(-4 * time.Minute))
+			report.Status.ReportTime = &reportTime
+			report.Status.ObservedGeneration = 1
+		})
+
+		It("requeues when the next report is due without generating it", func() {
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: 6 * time.Minute}))
+			Expect(repo.ListWorkloadsCallCount()).To(Equal(0))
+			Expect(repo.StatusUpdateCallCount()).To(Equal(0))
+		})
+
+		It("generates the report when the spec has changed", func() {
+			report.Generation = 2
+			_, _ = reconciler.Reconcile(ctx, req)
+			Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+		})
+	})
+
+	It("returns an error when the workloads cannot be listed", func() {
+		repo.ListWorkloadsReturns(nil, errors.New("some error"))
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).To(MatchError("list workloads: some error"))
+		Expect(repo.StatusUpdateCallCount()).To(Equal(0))
+	})
+
+	It("returns an error when the supply chains cannot be listed", func() {
+		repo.ListSupplyChainsReturns(nil, errors.New("some error"))
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).To(MatchError("list supply chains: some error"))
+	})
+
+	It("returns an error when the status cannot be updated", func() {
+		repo.StatusUpdateReturns(errors.New("some error"))
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).To(MatchError("update realization report status: some error"))
+	})
+
+	It("does nothing when the report has been deleted", func() {
+		repo.GetRealizationReportReturns(nil, kerrors.NewNotFound(schema.GroupResource{}, "fleet"))
+		result, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ctrl.Result{}))
+	})
+})
@@ -0,0 +1,172 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizationreport
+
+import (
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+type componentKey struct {
+	supplyChain string
+	component   string
+}
+
+// Summarize reports on the realization of the workloads by the supply
+// chains, listing at most limit entries in each ranking.
+func Summarize(workloads []v1alpha1.Workload, supplyChains []v1alpha1.ClusterSupplyChain, limit int) v1alpha1.RealizationReportStatus {
+	templates := componentTemplates(supplyChains)
+
+	conditionCounts := map[v1alpha1.ConditionCount]int{}
+	failing := map[componentKey]int{}
+	waiting := map[componentKey]int{}
+	var stuck []v1alpha1.StuckWorkload
+
+	for _, workload := range workloads {
+		status, reason := readiness(workload)
+		conditionCounts[v1alpha1.ConditionCount{Status: status, Reason: reason}]++
+
+		supplyChain := workload.Status.SupplyChainRef.Name
+		var pending string
+		for _, component := range workload.Status.Components {
+			key := componentKey{supplyChain: supplyChain, component: component.Name}
+			switch component.State {
+			case v1alpha1.FailedComponentState:
+				failing[key]++
+			case v1alpha1.WaitingComponentState:
+				waiting[key]++
+			default:
+				continue
+			}
+			if pending == "" {
+				pending = component.Name
+			}
+		}
+
+		if workload.Status.RealizationStartTime != nil && workload.Status.RealizationCompletionTime == nil {
+			stuck = append(stuck, v1alpha1.StuckWorkload{
+				Name:        workload.Name,
+				Namespace:   workload.Namespace,
+				SupplyChain: supplyChain,
+				Component:   pending,
+				Since:       *workload.Status.RealizationStartTime,
+			})
+		}
+	}
+
+	sort.SliceStable(stuck, func(i, j int) bool {
+		if !stuck[i].Since.Equal(&stuck[j].Since) {
+			return stuck[i].Since.Before(&stuck[j].Since)
+		}
+		if stuck[i].Namespace != stuck[j].Namespace {
+			return stuck[i].Namespace < stuck[j].Namespace
+		}
+		return stuck[i].Name < stuck[j].Name
+	})
+	if len(stuck) > limit {
+		stuck = stuck[:limit]
+	}
+
+	return v1alpha1.RealizationReportStatus{
+		Workloads:            len(workloads),
+		WorkloadsByCondition: rankConditions(conditionCounts),
+		FailingTemplates:     rankComponents(failing, templates, limit),
+		SlowestComponents:    rankComponents(waiting, templates, limit),
+		StuckWorkloads:       stuck,
+	}
+}
+
+func readiness(workload v1alpha1.Workload) (metav1.ConditionStatus, string) {
+	for _, condition := range workload.Status.Conditions {
+		if condition.Type == v1alpha1.WorkloadReady {
+			return condition.Status, condition.Reason
+		}
+	}
+	return metav1.ConditionUnknown, ""
+}
+
+// componentTemplates maps the components of the supply chains, including
+// those inherited from the chains they extend, to their template references.
+func componentTemplates(supplyChains []v1alpha1.ClusterSupplyChain) map[componentKey]v1alpha1.ClusterTemplateReference {
+	byName := map[string]*v1alpha1.ClusterSupplyChain{}
+	for i := range supplyChains {
+		byName[supplyChains[i].Name] = &supplyChains[i]
+	}
+	get := func(name string) (*v1alpha1.ClusterSupplyChain, error) {
+		return byName[name], nil
+	}
+
+	templates := map[componentKey]v1alpha1.ClusterTemplateReference{}
+	for _, supplyChain := range supplyChains {
+		resolved, err := supplyChain.Resolve(get)
+		if err != nil {
+			resolved = &supplyChain
+		}
+		for _, component := range resolved.Spec.Components {
+			templates[componentKey{supplyChain: supplyChain.Name, component: component.Name}] = component.TemplateRef
+		}
+	}
+	return templates
+}
+
+func rankConditions(counts map[v1alpha1.ConditionCount]int) []v1alpha1.ConditionCount {
+	var ranking []v1alpha1.ConditionCount
+	for condition, count := range counts {
+		condition.Workloads = count
+		ranking = append(ranking, condition)
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Workloads != ranking[j].Workloads {
+			return ranking[i].Workloads > ranking[j].Workloads
+		}
+		if ranking[i].Status != ranking[j].Status {
+			return ranking[i].Status < ranking[j].Status
+		}
+		return ranking[i].Reason < ranking[j].Reason
+	})
+	return ranking
+}
+
+func rankComponents(counts map[componentKey]int, templates map[componentKey]v1alpha1.ClusterTemplateReference, limit int) []v1alpha1.ComponentCount {
+	var ranking []v1alpha1.ComponentCount
+	for key, count := range counts {
+		template := templates[key]
+		ranking = append(ranking, v1alpha1.ComponentCount{
+			SupplyChain:  key.supplyChain,
+			Component:    key.component,
+			TemplateKind: template.Kind,
+			TemplateName: template.Name,
+			Workloads:    count,
+		})
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Workloads != ranking[j].Workloads {
+			return ranking[i].Workloads > ranking[j].Workloads
+		}
+		if ranking[i].SupplyChain != ranking[j].SupplyChain {
+			return ranking[i].SupplyChain < ranking[j].SupplyChain
+		}
+		return ranking[i].Component < ranking[j].Component
+	})
+	if len(ranking) > limit {
+		ranking = ranking[:limit]
+	}
+	return ranking
+}
@@ -0,0 +1,158 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package realizationreport_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/controller/realizationreport"
+)
+
+var _ = Describe("Summarize", func() {
+	var (
+		workloads    []v1alpha1.Workload
+		supplyChains []v1alpha1.ClusterSupplyChain
+		started      metav1.Time
+	)
+
+	workload := func(name string, ready metav1.ConditionStatus, reason string, components ...v1alpha1.ComponentStatus) v1alpha1.Workload {
+		return v1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "dev"},
+			Status: v1alpha1.WorkloadStatus{
+				Conditions:     []metav1.Condition{{Type: "Ready", Status: ready, Reason: reason}},
+				SupplyChainRef: v1alpha1.WorkloadSupplyChainReference{Kind: "ClusterSupplyChain", Name: "web"},
+				Components:     components,
+			},
+		}
+	}
+
+	component := func(name, state string) v1alpha1.ComponentStatus {
+		return v1alpha1.ComponentStatus{Name: name, State: state}
+	}
+
+	BeforeEach(func() {
+		started = metav1.NewTime(time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC))
+
+		supplyChains = []v1alpha1.ClusterSupplyChain{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec: v1alpha1.SupplyChainSpec{
+					Components: []v1alpha1.SupplyChainComponent{
+						{Name: "source", TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterSourceTemplate", Name: "git"}},
+						{Name: "image", TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterImageTemplate", Name: "kpack"}},
+					},
+				},
+			},
+		}
+
+		workloads = []v1alpha1.Workload{
+			workload("realized", "True", "Ready",
+				component("source", v1alpha1.RealizedComponentState),
+				component("image", v1alpha1.RealizedComponentState)),
+			workload("failing", "False", "TemplateStampFailure",
+				component("source", v1alpha1.RealizedComponentState),
+				component("image", v1alpha1.FailedComponentState)),
+			workload("waiting", "Unknown", "MissingValueAtPath",
+				component("source", v1alpha1.WaitingComponentState),
+				component("image", v1alpha1.BlockedComponentState)),
+			workload("also-failing", "False", "TemplateStampFailure",
+				component("source", v1alpha1.RealizedComponentState),
+				component("image", v1alpha1.FailedComponentState)),
+		}
+
+		for i := range workloads[1:] {
+			since := metav1.NewTime(started.Add(time.Duration(i) * time.Minute))
+			workloads[i+1].Status.RealizationStartTime = &since
+		}
+		workloads[0].Status.RealizationStartTime = &started
+		workloads[0].Status.RealizationCompletionTime = &started
+	})
+
+	It("counts the workloads", func() {
+		report := realizationreport.Summarize(workloads, supplyChains, 5)
+		Expect(report.Workloads).To(Equal(4))
+	})
+
+	It("counts the workloads by readiness, most common first", func() {
+		report := realizationreport.Summarize(workloads, supplyChains, 5)
+		Expect(report.WorkloadsByCondition).To(Equal([]v1alpha1.ConditionCount{
+			{Status: "False", Reason: "TemplateStampFailure", Workloads: 2},
+			{Status: "True", Reason: "Ready", Workloads: 1},
+			{Status: "Unknown", Reason: "MissingValueAtPath", Workloads: 1},
+		}))
+	})
+
+	It("counts workloads without a Ready condition as unknown", func() {
+		workloads[0].Status.Conditions = nil
+		report := realizationreport.Summarize(workloads, supplyChains, 5)
+		Expect(report.WorkloadsByCondition).To(ContainElement(v1alpha1.ConditionCount{Status: "Unknown", Workloads: 1}))
+	})
+
+	It("ranks the failing templates", func() {
+		report := realizationreport.Summarize(workloads, supplyChains, 5)
+		Expect(report.FailingTemplates).To(Equal([]v1alpha1.ComponentCount{
+			{SupplyChain: "web", Component: "image", TemplateKind: "ClusterImageTemplate", TemplateName: "kpack", Workloads: 2},
+		}))
+	})
+
+	It("ranks the components workloads are waiting on", func() {
+		report := realizationreport.Summarize(workloads, supplyChains, 5)
+		Expect(report.SlowestComponents).To(Equal([]v1alpha1.ComponentCount{
+			{SupplyChain: "web", Component: "source", TemplateKind: "ClusterSourceTemplate", TemplateName: "git", Workloads: 1},
+		}))
+	})
+
+	It("lists the unrealized workloads, oldest first", func() {
+		report := realizationreport.Summarize(workloads, supplyChains, 5)
+		Expect(report.StuckWorkloads).To(Equal([]v1alpha1.StuckWorkload{
+			{Name: "failing", Namespace: "dev", SupplyChain: "web", Component: "image", Since: started},
+			{Name: "waiting", Namespace: "dev", SupplyChain: "web", Component: "source", Since: metav1.NewTime(started.Add(time.Minute))},
+			{Name: "also-failing", Namespace: "dev", SupplyChain: "web", Component: "image", Since: metav1.NewTime(started.Add(2 * time.Minute))},
+		}))
+	})
+
+	It("lists at most limit entries in each ranking", func() {
+		report := realizationreport.Summarize(workloads, supplyChains, 1)
+		Expect(report.StuckWorkloads).To(HaveLen(1))
+		Expect(report.StuckWorkloads[0].Name).To(Equal("failing"))
+		Expect(report.WorkloadsByCondition).To(HaveLen(3))
+	})
+
+	Context("when the supply chain extends another", func() {
+		BeforeEach(func() {
+			base := supplyChains[0]
+			base.Name = "golden"
+			supplyChains = []v1alpha1.ClusterSupplyChain{
+				base,
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "web"},
+					Spec: v1alpha1.SupplyChainSpec{
+						Extends: &v1alpha1.SupplyChainExtension{Name: "golden"},
+					},
+				},
+			}
+		})
+
+		It("names the templates the chain inherits", func() {
+			report := realizationreport.Summarize(workloads, supplyChains, 5)
+			Expect(report.FailingTemplates[0].TemplateName).To(Equal("kpack"))
+		})
+	})
+})
@@ -44,3 +44,20 @@ func TemplatesFoundCondition() metav1.Condition {
 		Reason: v1alpha1.ReadyTemplatesReadyReason,
 	}
 }
+
+func ExtensionResolvedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:   v1alpha1.SupplyChainExtensionResolved,
+		Status: metav1.ConditionTrue,
+		Reason: v1alpha1.ResolvedExtensionResolvedReason,
+	}
+}
+
+func InvalidExtensionCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.SupplyChainExtensionResolved,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InvalidExtensionResolvedReason,
+		Message: err.Error(),
+	}
+}
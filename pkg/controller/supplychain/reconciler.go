@@ -23,13 +23,16 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/cartographer/pkg/conditions"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
 )
 
-const reconcileInterval = 5 * time.Second
+// DefaultResyncInterval is how often a supply chain is reconciled again
+// when nothing about it changed, unless the controller is set otherwise.
+const DefaultResyncInterval = 5 * time.Second
 
 type Timer interface {
 	Now() metav1.Time
@@ -37,17 +40,24 @@ type Timer interface {
 
 type Reconciler struct {
 	repo                    repository.Repository
-	conditionManager        conditions.ConditionManager
 	conditionManagerBuilder conditions.ConditionManagerBuilder
+	resyncInterval          time.Duration
 }
 
 func NewReconciler(repo repository.Repository, conditionManagerBuilder conditions.ConditionManagerBuilder) *Reconciler {
 	return &Reconciler{
 		repo:                    repo,
 		conditionManagerBuilder: conditionManagerBuilder,
+		resyncInterval:          DefaultResyncInterval,
 	}
 }
 
+// SetResyncInterval sets how often a supply chain is reconciled again when
+// nothing about it changed.
+func (r *Reconciler) SetResyncInterval(interval time.Duration) {
+	r.resyncInterval = interval
+}
+
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := logr.FromContext(ctx).
 		WithValues("name", req.Name, "namespace", req.Namespace)
@@ -55,34 +65,65 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	reconcileCtx := logr.NewContext(ctx, logger)
 
-	sc, err := r.repo.GetSupplyChain(req.Name)
-	if err != nil || sc == nil {
+	sc, err := r.getSupplyChain(req)
+	if err != nil {
 		if kerrors.IsNotFound(err) {
 			return ctrl.Result{}, nil
 		}
 
 		return ctrl.Result{}, fmt.Errorf("get supplyChain: %w", err)
 	}
+	if sc == nil {
+		return ctrl.Result{}, nil
+	}
 
 	supplyChain := sc.DeepCopy()
 
-	r.conditionManager = r.conditionManagerBuilder(v1alpha1.SupplyChainReady, supplyChain.Status.Conditions)
+	conditionManager := r.conditionManagerBuilder(v1alpha1.SupplyChainReady, supplyChain.Status.Conditions)
 
-	err = r.reconcileSupplyChain(supplyChain)
+	err = r.reconcileSupplyChain(supplyChain, conditionManager)
 
-	return r.completeReconciliation(reconcileCtx, supplyChain, err)
+	return r.completeReconciliation(reconcileCtx, supplyChain, conditionManager, err)
+}
+
+// getSupplyChain gets the ClusterSupplyChain of the request, or the
+// SupplyChain in the cluster form the reconciler works with when the request
+// is namespaced.
+func (r *Reconciler) getSupplyChain(req ctrl.Request) (*v1alpha1.ClusterSupplyChain, error) {
+	if req.Namespace == "" {
+		return r.repo.GetSupplyChain(req.Name)
+	}
+
+	sc, err := r.repo.GetNamespacedSupplyChain(req.Name, req.Namespace)
+	if err != nil || sc == nil {
+		return nil, err
+	}
+	return sc.AsClusterSupplyChain(), nil
 }
 
-func (r *Reconciler) completeReconciliation(ctx context.Context, supplyChain *v1alpha1.ClusterSupplyChain, err error) (ctrl.Result, error) {
+// statusObject is the object whose status is updated for the supply chain.
+func statusObject(supplyChain *v1alpha1.ClusterSupplyChain) client.Object {
+	if supplyChain.Namespace == "" {
+		return supplyChain
+	}
+	return &v1alpha1.SupplyChain{
+		ObjectMeta: supplyChain.ObjectMeta,
+		Spec:       supplyChain.Spec,
+		Status:     supplyChain.Status,
+	}
+}
+
+func (r *Reconciler) completeReconciliation(ctx context.Context, supplyChain *v1alpha1.ClusterSupplyChain, conditionManager conditions.ConditionManager, err error) (ctrl.Result, error) {
 	logger := logr.FromContext(ctx)
 
 	var changed bool
-	supplyChain.Status.Conditions, changed = r.conditionManager.Finalize()
+	supplyChain.Status.Conditions, changed = conditionManager.Finalize()
+	conditions.ObserveGeneration(supplyChain.Status.Conditions, supplyChain.Generation)
 
 	var updateErr error
 	if changed || (supplyChain.Status.ObservedGeneration != supplyChain.Generation) {
 		supplyChain.Status.ObservedGeneration = supplyChain.Generation
-		updateErr = r.repo.StatusUpdate(supplyChain)
+		updateErr = r.repo.StatusUpdate(statusObject(supplyChain))
 		if updateErr != nil {
 			logger.Error(updateErr, "update error")
 			if err == nil {
@@ -97,29 +138,46 @@ func (r *Reconciler) completeReconciliation(ctx context.Context, supplyChain *v1
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+	return ctrl.Result{RequeueAfter: r.resyncInterval}, nil
 }
 
-func (r *Reconciler) reconcileSupplyChain(chain *v1alpha1.ClusterSupplyChain) error {
+func (r *Reconciler) reconcileSupplyChain(chain *v1alpha1.ClusterSupplyChain, conditionManager conditions.ConditionManager) error {
 	var (
 		componentHandlingError, err error
 		componentsNotFound          []string
 	)
 
-	for _, component := range chain.Spec.Components {
-		_, err = r.repo.GetClusterTemplate(component.TemplateRef)
+	if chain.Spec.Extends != nil {
+		resolved, err := chain.Resolve(r.repo.GetSupplyChain)
 		if err != nil {
-			componentsNotFound = append(componentsNotFound, component.Name)
-			if componentHandlingError == nil {
-				componentHandlingError = fmt.Errorf("handle component: %w", err)
+			conditionManager.AddPositive(InvalidExtensionCondition(err))
+			return fmt.Errorf("resolve supply chain: %w", err)
+		}
+		conditionManager.AddPositive(ExtensionResolvedCondition())
+		chain = resolved
+	}
+
+	for _, component := range chain.Spec.Components {
+		for _, candidate := range component.TemplateRef.Candidates() {
+			if chain.Namespace == "" {
+				_, err = r.repo.GetClusterTemplate(candidate)
+			} else {
+				_, err = r.repo.GetTemplate(candidate, chain.Namespace)
+			}
+			if err != nil {
+				componentsNotFound = append(componentsNotFound, component.Name)
+				if componentHandlingError == nil {
+					componentHandlingError = fmt.Errorf("handle component: %w", err)
+				}
+				break
 			}
 		}
 	}
 
 	if componentHandlingError != nil {
-		r.conditionManager.AddPositive(TemplatesNotFoundCondition(componentsNotFound))
+		conditionManager.AddPositive(TemplatesNotFoundCondition(componentsNotFound))
 	} else {
-		r.conditionManager.AddPositive(TemplatesFoundCondition())
+		conditionManager.AddPositive(TemplatesFoundCondition())
 	}
 
 	return componentHandlingError
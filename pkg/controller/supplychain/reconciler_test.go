@@ -17,6 +17,7 @@ package supplychain_test
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -38,6 +39,7 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/controller/supplychain"
 	"github.com/vmware-tanzu/cartographer/pkg/registrar"
 	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
 
 var _ = Describe("Reconciler", func() {
@@ -113,7 +115,7 @@ var _ = Describe("Reconciler", func() {
 			reconciler = supplychain.NewReconciler(repo, fakeConditionManagerBuilder)
 
 			req = ctrl.Request{
-				NamespacedName: types.NamespacedName{Name: "my-supply-chain", Namespace: "my-namespace"},
+				NamespacedName: types.NamespacedName{Name: "my-supply-chain"},
 			}
 		})
 
@@ -122,7 +124,7 @@ var _ = Describe("Reconciler", func() {
 
 			Expect(out).To(Say(`"msg":"started"`))
 			Expect(out).To(Say(`"name":"my-supply-chain"`))
-			Expect(out).To(Say(`"namespace":"my-namespace"`))
+			Expect(out).To(Say(`"namespace":""`))
 		})
 
 		It("logs that it's finished", func() {
@@ -130,7 +132,7 @@ var _ = Describe("Reconciler", func() {
 
 			Expect(out).To(Say(`"msg":"finished"`))
 			Expect(out).To(Say(`"name":"my-supply-chain"`))
-			Expect(out).To(Say(`"namespace":"my-namespace"`))
+			Expect(out).To(Say(`"namespace":""`))
 		})
 
 		It("updates the status of the workload", func() {
@@ -169,12 +171,70 @@ var _ = Describe("Reconciler", func() {
 			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(supplychain.TemplatesFoundCondition()))
 		})
 
+		It("keeps the state of concurrent reconciles apart", func() {
+			var (
+				mu       sync.Mutex
+				managers []*conditionsfakes.FakeConditionManager
+			)
+			conditionManagerBuilder := func(string, []metav1.Condition) conditions.ConditionManager {
+				mu.Lock()
+				defer mu.Unlock()
+				manager := &conditionsfakes.FakeConditionManager{}
+				managers = append(managers, manager)
+				return manager
+			}
+			reconciler = supplychain.NewReconciler(repo, conditionManagerBuilder)
+
+			looking := make(chan struct{})
+			release := make(chan struct{})
+			var once sync.Once
+			repo.GetClusterTemplateStub = func(v1alpha1.ClusterTemplateReference) (templates.Template, error) {
+				first := false
+				once.Do(func() { first = true })
+				if first {
+					close(looking)
+					<-release
+				}
+				return nil, nil
+			}
+
+			done := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+			}()
+			<-looking
+
+			other := ctrl.Request{NamespacedName: types.NamespacedName{Name: "other-supply-chain"}}
+			_, err := reconciler.Reconcile(ctx, other)
+			Expect(err).NotTo(HaveOccurred())
+
+			close(release)
+			<-done
+
+			Expect(managers).To(HaveLen(2))
+			for _, manager := range managers {
+				Expect(manager.AddPositiveCallCount()).To(Equal(1))
+				Expect(manager.AddPositiveArgsForCall(0)).To(Equal(supplychain.TemplatesFoundCondition()))
+				Expect(manager.FinalizeCallCount()).To(Equal(1))
+			}
+		})
+
 		It("reschedules for 5 seconds", func() {
 			result, _ := reconciler.Reconcile(ctx, req)
 
 			Expect(result).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
 		})
 
+		It("reschedules after the resync interval it is set to", func() {
+			reconciler.SetResyncInterval(time.Minute)
+			result, _ := reconciler.Reconcile(ctx, req)
+
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		})
+
 		It("does not return an error", func() {
 			_, err := reconciler.Reconcile(ctx, req)
 
@@ -229,6 +289,166 @@ var _ = Describe("Reconciler", func() {
 			})
 		})
 
+		Context("when a component lists template options", func() {
+			BeforeEach(func() {
+				sc.Spec.Components[1].TemplateRef = v1alpha1.ClusterTemplateReference{
+					Kind: "another-kind",
+					Options: []v1alpha1.TemplateOption{
+						{Name: "some-option"},
+						{Name: "another-option"},
+					},
+				}
+			})
+
+			It("retrieves the template of every option", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+				Expect(repo.GetClusterTemplateCallCount()).To(Equal(3))
+				Expect(repo.GetClusterTemplateArgsForCall(1)).To(Equal(v1alpha1.ClusterTemplateReference{Kind: "another-kind", Name: "some-option"}))
+				Expect(repo.GetClusterTemplateArgsForCall(2)).To(Equal(v1alpha1.ClusterTemplateReference{Kind: "another-kind", Name: "another-option"}))
+			})
+
+			Context("when an option's template cannot be retrieved", func() {
+				BeforeEach(func() {
+					repo.GetClusterTemplateReturnsOnCall(2, nil, errors.New("getting templates is hard"))
+				})
+
+				It("adds a positive templates NOT found condition listing the component once", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(supplychain.TemplatesNotFoundCondition([]string{"second name"})))
+				})
+			})
+		})
+
+		Context("when the supply chain extends another", func() {
+			var base *v1alpha1.ClusterSupplyChain
+
+			BeforeEach(func() {
+				base = &v1alpha1.ClusterSupplyChain{
+					ObjectMeta: metav1.ObjectMeta{Name: "base"},
+					Spec: v1alpha1.SupplyChainSpec{
+						Components: []v1alpha1.SupplyChainComponent{
+							{
+								Name: "base component",
+								TemplateRef: v1alpha1.ClusterTemplateReference{
+									Kind: "base-kind",
+									Name: "base-name",
+								},
+							},
+							{
+								Name: "first name",
+								TemplateRef: v1alpha1.ClusterTemplateReference{
+									Kind: "replaced-kind",
+									Name: "replaced-name",
+								},
+							},
+						},
+					},
+				}
+
+				sc.Name = "my-supply-chain"
+				sc.Spec.Extends = &v1alpha1.SupplyChainExtension{Name: "base"}
+
+				repo.GetSupplyChainStub = func(name string) (*v1alpha1.ClusterSupplyChain, error) {
+					if name == "base" {
+						return base, nil
+					}
+					return sc, nil
+				}
+			})
+
+			It("adds a positive extension resolved condition", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+				Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(supplychain.ExtensionResolvedCondition()))
+				Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(supplychain.TemplatesFoundCondition()))
+			})
+
+			It("retrieves the templates of the resolved components", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+				Expect(repo.GetClusterTemplateCallCount()).To(Equal(3))
+				Expect(repo.GetClusterTemplateArgsForCall(0)).To(Equal(v1alpha1.ClusterTemplateReference{Kind: "base-kind", Name: "base-name"}))
+				Expect(repo.GetClusterTemplateArgsForCall(1)).To(Equal(v1alpha1.ClusterTemplateReference{Kind: "some-kind", Name: "some-name"}))
+				Expect(repo.GetClusterTemplateArgsForCall(2)).To(Equal(v1alpha1.ClusterTemplateReference{Kind: "another-kind", Name: "another-name"}))
+			})
+
+			Context("when the extension cannot be resolved", func() {
+				BeforeEach(func() {
+					sc.Spec.Extends.Remove = []string{"missing component"}
+				})
+
+				It("adds a positive invalid extension condition", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(conditionManager.AddPositiveCallCount()).To(Equal(1))
+					condition := conditionManager.AddPositiveArgsForCall(0)
+					Expect(condition.Reason).To(Equal(v1alpha1.InvalidExtensionResolvedReason))
+					Expect(condition.Message).To(ContainSubstring("cannot remove unknown component 'missing component'"))
+				})
+
+				It("returns an error", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).To(MatchError(ContainSubstring("resolve supply chain")))
+				})
+			})
+		})
+
+		Context("when the supply chain is namespaced", func() {
+			BeforeEach(func() {
+				req.Namespace = "my-namespace"
+				repo.GetNamespacedSupplyChainReturns(&v1alpha1.SupplyChain{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "my-supply-chain",
+						Namespace:  "my-namespace",
+						Generation: 1,
+					},
+					Spec: sc.Spec,
+				}, nil)
+			})
+
+			It("gets the supply chain from the namespace", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(repo.GetSupplyChainCallCount()).To(Equal(0))
+				Expect(repo.GetNamespacedSupplyChainCallCount()).To(Equal(1))
+				name, namespace := repo.GetNamespacedSupplyChainArgsForCall(0)
+				Expect(name).To(Equal("my-supply-chain"))
+				Expect(namespace).To(Equal("my-namespace"))
+			})
+
+			It("retrieves the templates from the namespace first", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(repo.GetClusterTemplateCallCount()).To(Equal(0))
+				Expect(repo.GetTemplateCallCount()).To(Equal(2))
+				ref, namespace := repo.GetTemplateArgsForCall(0)
+				Expect(ref).To(Equal(sc.Spec.Components[0].TemplateRef))
+				Expect(namespace).To(Equal("my-namespace"))
+			})
+
+			It("updates the status of the namespaced supply chain", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+				updatedSupplyChain, ok := repo.StatusUpdateArgsForCall(0).(*v1alpha1.SupplyChain)
+				Expect(ok).To(BeTrue())
+				Expect(updatedSupplyChain.Namespace).To(Equal("my-namespace"))
+				Expect(updatedSupplyChain.Status.ObservedGeneration).To(BeEquivalentTo(1))
+				Expect(updatedSupplyChain.Status.Conditions).To(Equal(expectedConditions))
+			})
+
+			Context("when the supply chain has been deleted from the apiServer", func() {
+				BeforeEach(func() {
+					repo.GetNamespacedSupplyChainReturns(nil, nil)
+				})
+
+				It("does not return an error or update a status", func() {
+					result, err := reconciler.Reconcile(ctx, req)
+
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(Equal(ctrl.Result{Requeue: false}))
+					Expect(repo.StatusUpdateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("when the update fails", func() {
 			BeforeEach(func() {
 				repo.StatusUpdateReturns(errors.New("updating is hard"))
@@ -0,0 +1,116 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templateplayground
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+type Reconciler struct {
+	repo                    repository.Repository
+	conditionManagerBuilder conditions.ConditionManagerBuilder
+	clusterContext          templates.ClusterContext
+}
+
+func NewReconciler(repo repository.Repository, conditionManagerBuilder conditions.ConditionManagerBuilder, clusterContext templates.ClusterContext) *Reconciler {
+	return &Reconciler{
+		repo:                    repo,
+		conditionManagerBuilder: conditionManagerBuilder,
+		clusterContext:          clusterContext,
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContext(ctx).
+		WithValues("name", req.Name, "namespace", req.Namespace)
+	logger.Info("started")
+	defer logger.Info("finished")
+
+	playground, err := r.repo.GetTemplatePlayground(req.Name, req.Namespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("get template playground: %w", err)
+	}
+
+	conditionManager := r.conditionManagerBuilder(v1alpha1.TemplatePlaygroundReady, playground.Status.Conditions)
+	playground.Status.Result = r.render(ctx, playground, conditionManager)
+
+	playground.Status.Conditions, _ = conditionManager.Finalize()
+	conditions.ObserveGeneration(playground.Status.Conditions, playground.Generation)
+	playground.Status.ObservedGeneration = playground.Generation
+	if err := r.repo.StatusUpdate(playground); err != nil {
+		return ctrl.Result{}, fmt.Errorf("update template playground status: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// render stamps the playground's template against its context. The errors
+// of the template are the author's to fix, so they are reported in a
+// condition rather than retried.
+func (r *Reconciler) render(ctx context.Context, playground *v1alpha1.TemplatePlayground, conditionManager conditions.ConditionManager) *runtime.RawExtension {
+	templatingContext := map[string]interface{}{}
+	if playground.Spec.Context != nil && len(playground.Spec.Context.Raw) > 0 {
+		if err := json.Unmarshal(playground.Spec.Context.Raw, &templatingContext); err != nil {
+			conditionManager.AddPositive(InvalidContextCondition(err))
+			return nil
+		}
+	}
+
+	if _, ok := templatingContext["params"]; !ok {
+		params, err := templates.ParamsBuilder(playground.Spec.Params, nil, nil)
+		if err != nil {
+			conditionManager.AddPositive(TemplateRenderFailedCondition(fmt.Errorf("params: %w", err)))
+			return nil
+		}
+		templatingContext["params"] = params
+	}
+
+	if _, ok := templatingContext["clusterContext"]; !ok {
+		templatingContext["clusterContext"] = r.clusterContext
+	}
+
+	stampContext := templates.StamperBuilder(playground, templatingContext, nil)
+	stampedObject, err := stampContext.Stamp(ctx, playground.Spec.TemplateSpec)
+	if err != nil {
+		conditionManager.AddPositive(TemplateRenderFailedCondition(err))
+		return nil
+	}
+	stampedObject.SetOwnerReferences(nil)
+
+	result, err := stampedObject.MarshalJSON()
+	if err != nil {
+		conditionManager.AddPositive(TemplateRenderFailedCondition(fmt.Errorf("marshal result: %w", err)))
+		return nil
+	}
+
+	conditionManager.AddPositive(TemplateRenderedCondition())
+	return &runtime.RawExtension{Raw: result}
+}
@@ -0,0 +1,237 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templateplayground_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions/conditionsfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/controller/templateplayground"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx              context.Context
+		req              ctrl.Request
+		repo             *repositoryfakes.FakeRepository
+		conditionManager *conditionsfakes.FakeConditionManager
+		reconciler       *templateplayground.Reconciler
+		playground       *v1alpha1.TemplatePlayground
+	)
+
+	BeforeEach(func() {
+		ctx = logr.NewContext(context.Background(), zap.New())
+
+		conditionManager = &conditionsfakes.FakeConditionManager{}
+		repo = &repositoryfakes.FakeRepository{}
+		reconciler = templateplayground.NewReconciler(repo, func(string, []metav1.Condition) conditions.ConditionManager {
+			return conditionManager
+		}, templates.ClusterContext{Registry: "harbor.prod.example.com"})
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Name: "try-kpack", Namespace: "dev"}}
+
+		playground = &v1alpha1.TemplatePlayground{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "try-kpack",
+				Namespace:  "dev",
+				UID:        "playground-uid",
+				Generation: 3,
+			},
+			Spec: v1alpha1.TemplatePlaygroundSpec{
+				TemplateSpec: v1alpha1.TemplateSpec{
+					Template: &runtime.RawExtension{Raw: []byte(`{
+						"apiVersion": "kpack.io/v1alpha1",
+						"kind": "Image",
+						"metadata": {"name": "$(workload.metadata.name)$"},
+						"spec": {"tag": "$(params.registry)$/$(workload.metadata.name)$"}
+					}`)},
+					Params: v1alpha1.DefaultParams{
+						{Name: "registry", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"registry.example.com"`)}},
+					},
+				},
+				Context: &runtime.RawExtension{Raw: []byte(`{"workload": {"metadata": {"name": "petclinic"}}}`)},
+			},
+		}
+		repo.GetTemplatePlaygroundReturns(playground, nil)
+	})
+
+	It("renders the template against the context into the status", func() {
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		name, namespace := repo.GetTemplatePlaygroundArgsForCall(0)
+		Expect(name).To(Equal("try-kpack"))
+		Expect(namespace).To(Equal("dev"))
+
+		Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(templateplayground.TemplateRenderedCondition()))
+		Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+		updated := repo.StatusUpdateArgsForCall(0).(*v1alpha1.TemplatePlayground)
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(3)))
+		Expect(updated.Status.Result).NotTo(BeNil())
+
+		result := map[string]interface{}{}
+		Expect(json.Unmarshal(updated.Status.Result.Raw, &result)).To(Succeed())
+		Expect(result["kind"]).To(Equal("Image"))
+		Expect(result["spec"]).To(Equal(map[string]interface{}{"tag": "registry.example.com/petclinic"}))
+		Expect(result["metadata"]).To(HaveKeyWithValue("name", "petclinic"))
+		Expect(result["metadata"]).To(HaveKeyWithValue("namespace", "dev"))
+		Expect(result["metadata"]).NotTo(HaveKey("ownerReferences"))
+	})
+
+	It("does not create the rendered object", func() {
+		_, _ = reconciler.Reconcile(ctx, req)
+		Expect(repo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+	})
+
+	Context("when the context supplies params", func() {
+		BeforeEach(func() {
+			playground.Spec.Context = &runtime.RawExtension{Raw: []byte(`{
+				"workload": {"metadata": {"name": "petclinic"}},
+				"params": {"registry": "gcr.io/team"}
+			}`)}
+		})
+
+		It("uses them instead of the template's defaults", func() {
+			_, _ = reconciler.Reconcile(ctx, req)
+
+			updated := repo.StatusUpdateArgsForCall(0).(*v1alpha1.TemplatePlayground)
+			result := map[string]interface{}{}
+			Expect(json.Unmarshal(updated.Status.Result.Raw, &result)).To(Succeed())
+			Expect(result["spec"]).To(Equal(map[string]interface{}{"tag": "gcr.io/team/petclinic"}))
+		})
+	})
+
+	Context("when the template refers to the cluster context", func() {
+		BeforeEach(func() {
+			playground.Spec.Template = &runtime.RawExtension{Raw: []byte(`{
+				"apiVersion": "kpack.io/v1alpha1",
+				"kind": "Image",
+				"metadata": {"name": "$(workload.metadata.name)$"},
+				"spec": {"tag": "$(clusterContext.registry)$/$(workload.metadata.name)$"}
+			}`)}
+		})
+
+		It("renders the cluster context of the controller", func() {
+			_, _ = reconciler.Reconcile(ctx, req)
+
+			updated := repo.StatusUpdateArgsForCall(0).(*v1alpha1.TemplatePlayground)
+			result := map[string]interface{}{}
+			Expect(json.Unmarshal(updated.Status.Result.Raw, &result)).To(Succeed())
+			Expect(result["spec"]).To(Equal(map[string]interface{}{"tag": "harbor.prod.example.com/petclinic"}))
+		})
+
+		It("renders the cluster context supplied by the context instead", func() {
+			playground.Spec.Context = &runtime.RawExtension{Raw: []byte(`{
+				"workload": {"metadata": {"name": "petclinic"}},
+				"clusterContext": {"registry": "localhost:5000"}
+			}`)}
+
+			_, _ = reconciler.Reconcile(ctx, req)
+
+			updated := repo.StatusUpdateArgsForCall(0).(*v1alpha1.TemplatePlayground)
+			result := map[string]interface{}{}
+			Expect(json.Unmarshal(updated.Status.Result.Raw, &result)).To(Succeed())
+			Expect(result["spec"]).To(Equal(map[string]interface{}{"tag": "localhost:5000/petclinic"}))
+		})
+	})
+
+	Context("when the template cannot be rendered", func() {
+		BeforeEach(func() {
+			playground.Status.Result = &runtime.RawExtension{Raw: []byte(`{"stale": true}`)}
+			playground.Spec.Context = &runtime.RawExtension{Raw: []byte(`{}`)}
+		})
+
+		It("reports the failure and clears the previous result", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			condition := conditionManager.AddPositiveArgsForCall(0)
+			Expect(condition.Type).To(Equal(v1alpha1.TemplatePlaygroundRendered))
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(v1alpha1.FailedTemplateRenderedReason))
+			Expect(condition.Message).To(ContainSubstring("workload.metadata.name"))
+
+			updated := repo.StatusUpdateArgsForCall(0).(*v1alpha1.TemplatePlayground)
+			Expect(updated.Status.Result).To(BeNil())
+		})
+	})
+
+	Context("when the context is not an object", func() {
+		BeforeEach(func() {
+			playground.Spec.Context = &runtime.RawExtension{Raw: []byte(`["petclinic"]`)}
+		})
+
+		It("reports the context is invalid", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			condition := conditionManager.AddPositiveArgsForCall(0)
+			Expect(condition.Reason).To(Equal(v1alpha1.InvalidContextRenderedReason))
+			Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the playground no longer exists", func() {
+		BeforeEach(func() {
+			repo.GetTemplatePlaygroundReturns(nil, kerrors.NewNotFound(schema.GroupResource{Group: "carto.run", Resource: "templateplaygrounds"}, "try-kpack"))
+		})
+
+		It("does nothing", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.StatusUpdateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the playground cannot be fetched", func() {
+		BeforeEach(func() {
+			repo.GetTemplatePlaygroundReturns(nil, errors.New("some error"))
+		})
+
+		It("returns an error", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).To(MatchError("get template playground: some error"))
+		})
+	})
+
+	Context("when the status cannot be updated", func() {
+		BeforeEach(func() {
+			repo.StatusUpdateReturns(errors.New("some error"))
+		})
+
+		It("returns an error", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).To(MatchError("update template playground status: some error"))
+		})
+	})
+})
@@ -0,0 +1,128 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+type templateKey struct {
+	kind string
+	name string
+}
+
+type supplyChainKey struct {
+	namespace string
+	name      string
+}
+
+type usage struct {
+	supplyChain supplyChainKey
+	templates   []templateKey
+}
+
+// adoption keeps gauges of how many workloads use each supply chain and
+// each template, from the supply chain and templates each workload was last
+// reconciled with.
+type adoption struct {
+	mu             sync.Mutex
+	supplyChains   *prometheus.GaugeVec
+	templates      *prometheus.GaugeVec
+	usages         map[types.NamespacedName]usage
+	supplyChainUse map[supplyChainKey]int
+	templateUse    map[templateKey]int
+}
+
+func newAdoption(supplyChains, templates *prometheus.GaugeVec) *adoption {
+	return &adoption{
+		supplyChains:   supplyChains,
+		templates:      templates,
+		usages:         map[types.NamespacedName]usage{},
+		supplyChainUse: map[supplyChainKey]int{},
+		templateUse:    map[templateKey]int{},
+	}
+}
+
+// use records that the workload uses the supply chain and the templates.
+func (a *adoption) use(workload types.NamespacedName, supplyChain string, templates []v1alpha1.ClusterTemplateReference) {
+	current := usage{supplyChain: supplyChainKey{namespace: workload.Namespace, name: supplyChain}}
+	seen := map[templateKey]bool{}
+	for _, template := range templates {
+		key := templateKey{kind: template.Kind, name: template.Name}
+		if !seen[key] {
+			seen[key] = true
+			current.templates = append(current.templates, key)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.release(workload)
+	a.usages[workload] = current
+	a.countSupplyChain(current.supplyChain, 1)
+	for _, key := range current.templates {
+		a.countTemplate(key, 1)
+	}
+}
+
+// forget records that the workload uses no supply chain.
+func (a *adoption) forget(workload types.NamespacedName) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.release(workload)
+}
+
+func (a *adoption) release(workload types.NamespacedName) {
+	previous, ok := a.usages[workload]
+	if !ok {
+		return
+	}
+
+	delete(a.usages, workload)
+	a.countSupplyChain(previous.supplyChain, -1)
+	for _, key := range previous.templates {
+		a.countTemplate(key, -1)
+	}
+}
+
+// countSupplyChain adjusts the count of workloads of a namespace using the
+// supply chain, dropping the gauge once no workload of the namespace uses
+// it any longer.
+func (a *adoption) countSupplyChain(key supplyChainKey, delta int) {
+	a.supplyChainUse[key] += delta
+	if count := a.supplyChainUse[key]; count > 0 {
+		a.supplyChains.WithLabelValues(key.namespace, key.name).Set(float64(count))
+		return
+	}
+	delete(a.supplyChainUse, key)
+	a.supplyChains.DeleteLabelValues(key.namespace, key.name)
+}
+
+func (a *adoption) countTemplate(key templateKey, delta int) {
+	a.templateUse[key] += delta
+	if count := a.templateUse[key]; count > 0 {
+		a.templates.WithLabelValues(key.kind, key.name).Set(float64(count))
+		return
+	}
+	delete(a.templateUse, key)
+	a.templates.DeleteLabelValues(key.kind, key.name)
+}
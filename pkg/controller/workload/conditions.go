@@ -16,7 +16,10 @@ package workload
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
@@ -32,6 +35,15 @@ func SupplyChainReadyCondition() metav1.Condition {
 	}
 }
 
+func DefaultSupplyChainUsedCondition(name string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadSupplyChainReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.DefaultUsedSupplyChainReadyReason,
+		Message: fmt.Sprintf("no supply chain selects the workload, so the default supply chain '%s' is used", name),
+	}
+}
+
 func WorkloadMissingLabelsCondition() metav1.Condition {
 	return metav1.Condition{
 		Type:    v1alpha1.WorkloadSupplyChainReady,
@@ -55,7 +67,7 @@ func TooManySupplyChainMatchesCondition() metav1.Condition {
 		Type:    v1alpha1.WorkloadSupplyChainReady,
 		Status:  metav1.ConditionFalse,
 		Reason:  v1alpha1.MultipleMatchesSupplyChainReadyReason,
-		Message: "workload may only match a single supply chain's selector",
+		Message: "workload matches several supply chains of equal priority and selector specificity",
 	}
 }
 
@@ -68,6 +80,15 @@ func MissingReadyInSupplyChainCondition(supplyChainReadyCondition metav1.Conditi
 	}
 }
 
+func SupplyChainExtensionInvalidCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadSupplyChainReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InvalidExtensionSupplyChainReason,
+		Message: err.Error(),
+	}
+}
+
 // -- Component conditions
 
 func ComponentsSubmittedCondition() metav1.Condition {
@@ -105,6 +126,42 @@ func TemplateStampFailureCondition(err error) metav1.Condition {
 	}
 }
 
+func ImmutableParamOverriddenCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.ImmutableParamOverriddenComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func InvalidWorkloadParamsCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InvalidWorkloadParamsComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func SecretParamUnavailableCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.SecretParamUnavailableComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func MissingAPIDependencyCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.MissingAPIDependencyComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
 func TemplateRejectedByAPIServerCondition(err error) metav1.Condition {
 	return metav1.Condition{
 		Type:    v1alpha1.WorkloadComponentsSubmitted,
@@ -114,6 +171,150 @@ func TemplateRejectedByAPIServerCondition(err error) metav1.Condition {
 	}
 }
 
+func TemplateRejectedByDryRunCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.TemplateRejectedByDryRunComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func InvalidStampedObjectCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InvalidStampedObjectComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func CannotListCreatedObjectsCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.CannotListCreatedObjectsComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func CannotCreateObjectCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.CannotCreateObjectComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func CannotPatchObjectCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.CannotPatchObjectComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func GitWriteFailureCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.GitWriteFailureComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func ArtifactPublishFailureCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.ArtifactPublishFailureComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func KindNotAllowedCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.KindNotAllowedComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func PermissionDeniedCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.PermissionDeniedComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func InterceptorFailureCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InterceptorFailureComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func ArtifactResolutionFailureCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.ArtifactResolutionFailureComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func NoMatchingTemplateOptionCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.NoMatchingTemplateOptionComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func AmbiguousTemplateOptionsCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.AmbiguousTemplateOptionsComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func HookFailureCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.HookFailureComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func PreHookPendingCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionUnknown,
+		Reason:  v1alpha1.PreHookPendingComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
+func ReadinessGatePendingCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadComponentsSubmitted,
+		Status:  metav1.ConditionUnknown,
+		Reason:  v1alpha1.ReadinessGatePendingComponentsSubmittedReason,
+		Message: err.Error(),
+	}
+}
+
 func UnknownComponentErrorCondition(err error) metav1.Condition {
 	return metav1.Condition{
 		Type:    v1alpha1.WorkloadComponentsSubmitted,
@@ -122,3 +323,132 @@ func UnknownComponentErrorCondition(err error) metav1.Condition {
 		Message: err.Error(),
 	}
 }
+
+// -- Resources healthy conditions
+
+// ResourcesHealthyCondition rolls the Healthy conditions of the components
+// up into one for the workload: any unhealthy component makes it False, and
+// any component of unknown health makes it Unknown.
+func ResourcesHealthyCondition(statuses []v1alpha1.ComponentStatus) metav1.Condition {
+	var unknown []string
+	for _, status := range statuses {
+		health := meta.FindStatusCondition(status.Conditions, v1alpha1.ComponentHealthy)
+		switch {
+		case health == nil || health.Status == metav1.ConditionUnknown:
+			unknown = append(unknown, fmt.Sprintf("'%s'", status.Name))
+		case health.Status == metav1.ConditionFalse:
+			message := fmt.Sprintf("component '%s' is unhealthy", status.Name)
+			if health.Message != "" {
+				message = fmt.Sprintf("%s: %s", message, health.Message)
+			}
+			return metav1.Condition{
+				Type:    v1alpha1.WorkloadResourcesHealthy,
+				Status:  metav1.ConditionFalse,
+				Reason:  v1alpha1.UnhealthyResourceResourcesHealthyReason,
+				Message: message,
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		return metav1.Condition{
+			Type:    v1alpha1.WorkloadResourcesHealthy,
+			Status:  metav1.ConditionUnknown,
+			Reason:  v1alpha1.HealthUnknownResourceResourcesHealthyReason,
+			Message: fmt.Sprintf("health of components %s is unknown", strings.Join(unknown, ", ")),
+		}
+	}
+
+	return metav1.Condition{
+		Type:   v1alpha1.WorkloadResourcesHealthy,
+		Status: metav1.ConditionTrue,
+		Reason: v1alpha1.AllHealthyResourcesHealthyReason,
+	}
+}
+
+// -- Component conditions
+
+// ComponentReadyCondition tells whether the component is realized and its
+// stamped object healthy. cause is the ComponentsSubmitted condition caused
+// by the component, when it failed the realization of the workload, and
+// names the step that failed.
+func ComponentReadyCondition(status v1alpha1.ComponentStatus, cause *metav1.Condition) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    v1alpha1.ComponentReady,
+		Message: status.Message,
+	}
+
+	switch status.State {
+	case v1alpha1.RealizedComponentState:
+		health := meta.FindStatusCondition(status.Conditions, v1alpha1.ComponentHealthy)
+		switch {
+		case health == nil || health.Status == metav1.ConditionTrue:
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = v1alpha1.ReadyComponentReadyReason
+		case health.Status == metav1.ConditionFalse:
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = v1alpha1.UnhealthyComponentReadyReason
+			condition.Message = health.Message
+		default:
+			condition.Status = metav1.ConditionUnknown
+			condition.Reason = v1alpha1.HealthUnknownComponentReadyReason
+			condition.Message = health.Message
+		}
+		return condition
+	case v1alpha1.FailedComponentState:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1alpha1.FailedComponentReadyReason
+	case v1alpha1.WaitingComponentState:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = v1alpha1.WaitingComponentReadyReason
+	default:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = v1alpha1.BlockedComponentReadyReason
+		return condition
+	}
+
+	if cause != nil {
+		condition.Reason = cause.Reason
+		condition.Message = cause.Message
+	}
+	return condition
+}
+
+// -- Realization deadline conditions
+
+func RealizationWithinDeadlineCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:   v1alpha1.WorkloadRealizationDeadlineExceeded,
+		Status: metav1.ConditionFalse,
+		Reason: v1alpha1.WithinDeadlineRealizationDeadlineReason,
+	}
+}
+
+func RealizationDeadlineExceededCondition(maxDuration time.Duration) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadRealizationDeadlineExceeded,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.ExceededRealizationDeadlineReason,
+		Message: fmt.Sprintf("workload was not realized within %s of its last change", maxDuration),
+	}
+}
+
+func RealizedAfterDeadlineCondition(maxDuration time.Duration) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadRealizationDeadlineExceeded,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.ExceededRealizationDeadlineReason,
+		Message: fmt.Sprintf("workload was realized, but not within %s of its last change", maxDuration),
+	}
+}
+
+// -- Paused conditions
+
+func WorkloadPausedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadPaused,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.RequestedPausedReason,
+		Message: "workload is paused, its objects are neither stamped nor updated until spec.paused is unset",
+	}
+}
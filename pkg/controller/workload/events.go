@@ -0,0 +1,123 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+)
+
+// The reasons of the events recorded on a workload as its components are
+// realized. A failure is recorded with the reason of the condition that
+// reports it.
+const (
+	TemplateResolvedEventReason = "TemplateResolved"
+	ObjectStampedEventReason    = "ObjectStamped"
+	OutputsUpdatedEventReason   = "OutputsUpdated"
+	HealthFlappingEventReason   = "HealthFlapping"
+
+	OrphanDeletedEventReason        = "OrphanDeleted"
+	OrphanKeptEventReason           = "OrphanKept"
+	OrphanDeletionFailedEventReason = "OrphanDeletionFailed"
+)
+
+// recordConditionEvents records the transitions of the conditions of the
+// workload since the previous ones.
+func recordConditionEvents(recorder record.EventRecorder, workload *v1alpha1.Workload, previous []metav1.Condition) {
+	conditions.RecordTransitions(recorder, workload, "", previous, workload.Status.Conditions,
+		v1alpha1.WorkloadPaused, v1alpha1.WorkloadRealizationDeadlineExceeded)
+}
+
+// recordComponentEvents records what changed for each component since the
+// previous realization: the template it resolved to, the object stamped for
+// it, the outputs it provides, whether its object started flapping, and the
+// transitions of its conditions.
+func recordComponentEvents(recorder record.EventRecorder, workload *v1alpha1.Workload, previous, current []v1alpha1.ComponentStatus) {
+	prior := map[string]*v1alpha1.ComponentStatus{}
+	for i := range previous {
+		prior[previous[i].Name] = &previous[i]
+	}
+
+	for i := range current {
+		status := &current[i]
+		was, ok := prior[status.Name]
+		if !ok {
+			was = &v1alpha1.ComponentStatus{}
+		}
+
+		if ref := status.TemplateRef; ref != nil && !sameRef(ref, was.TemplateRef) {
+			recorder.Eventf(workload, corev1.EventTypeNormal, TemplateResolvedEventReason,
+				"component '%s' uses %s '%s'", status.Name, ref.Kind, ref.Name)
+		}
+
+		if ref := status.StampedRef; ref != nil && !sameRef(ref, was.StampedRef) {
+			recorder.Eventf(workload, corev1.EventTypeNormal, ObjectStampedEventReason,
+				"stamped %s '%s' for component '%s'", ref.Kind, qualifiedName(ref), status.Name)
+		}
+
+		if changed := changedOutputs(was.Outputs, status.Outputs); len(changed) > 0 {
+			recorder.Eventf(workload, corev1.EventTypeNormal, OutputsUpdatedEventReason,
+				"component '%s' provides new outputs: %s", status.Name, strings.Join(changed, ", "))
+		}
+
+		if flapping := meta.FindStatusCondition(status.Conditions, v1alpha1.ComponentHealthFlapping); flapping != nil &&
+			meta.FindStatusCondition(was.Conditions, v1alpha1.ComponentHealthFlapping) == nil {
+			recorder.Eventf(workload, corev1.EventTypeWarning, HealthFlappingEventReason,
+				"the object of component '%s' is flapping: %s", status.Name, flapping.Message)
+		}
+
+		conditions.RecordTransitions(recorder, workload, status.Name, was.Conditions, status.Conditions)
+	}
+}
+
+func sameRef(ref, other *corev1.ObjectReference) bool {
+	return other != nil &&
+		ref.APIVersion == other.APIVersion &&
+		ref.Kind == other.Kind &&
+		ref.Namespace == other.Namespace &&
+		ref.Name == other.Name
+}
+
+func qualifiedName(ref *corev1.ObjectReference) string {
+	if ref.Namespace == "" {
+		return ref.Name
+	}
+	return fmt.Sprintf("%s/%s", ref.Namespace, ref.Name)
+}
+
+// changedOutputs names the outputs whose values differ from the previous
+// ones, in the order they are provided.
+func changedOutputs(previous, current []v1alpha1.ComponentOutput) []string {
+	digests := map[string]string{}
+	for _, output := range previous {
+		digests[output.Name] = output.Digest
+	}
+
+	var changed []string
+	for _, output := range current {
+		if digests[output.Name] != output.Digest {
+			changed = append(changed, output.Name)
+		}
+	}
+	return changed
+}
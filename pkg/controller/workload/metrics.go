@@ -0,0 +1,76 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// RealizationDurationSeconds observes how long the supply chain took to
+// realize each generation of a workload, from the change to its spec until
+// every component was realized.
+var RealizationDurationSeconds = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Name:    "cartographer_workload_realization_duration_seconds",
+		Help:    "Time from a change to a workload until its supply chain was realized",
+		Buckets: []float64{10, 30, 60, 120, 300, 600, 1200, 1800, 3600, 7200},
+	},
+)
+
+// SupplyChainWorkloads gauges how many workloads of each namespace use each
+// supply chain.
+var SupplyChainWorkloads = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cartographer_supply_chain_workloads",
+		Help: "Number of workloads of the namespace using the supply chain",
+	},
+	[]string{"namespace", "supply_chain"},
+)
+
+// TemplateWorkloads gauges how many workloads use each template, through
+// any supply chain.
+var TemplateWorkloads = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "cartographer_template_workloads",
+		Help: "Number of workloads using the template",
+	},
+	[]string{"kind", "name"},
+)
+
+// RealizationFailuresTotal counts the realizations of workloads that failed,
+// by the reason of the condition reporting the failure, so that alerts can
+// tell a broken template from a missing permission.
+var RealizationFailuresTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cartographer_workload_realization_failures_total",
+		Help: "Number of failed realizations of workloads by reason",
+	},
+	[]string{"namespace", "supply_chain", "reason"},
+)
+
+// OutputExtractionFailuresTotal counts the components whose stamped object
+// did not have a value at the path of an output of their template.
+var OutputExtractionFailuresTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cartographer_workload_output_extraction_failures_total",
+		Help: "Number of outputs that could not be read from stamped objects",
+	},
+	[]string{"namespace", "supply_chain", "component"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(RealizationDurationSeconds, SupplyChainWorkloads, TemplateWorkloads, RealizationFailuresTotal, OutputExtractionFailuresTotal)
+}
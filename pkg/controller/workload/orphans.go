@@ -0,0 +1,109 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// deleteOrphans deletes the objects stamped by the previous realization
+// that the supply chain no longer describes: those of components that were
+// removed or renamed, and those left behind when a component's template
+// stamps another kind, or into another namespace or name. Orphans the
+// workload does not own are left in place and reported. The components
+// whose orphans could not all be deleted are returned, so that they stay in
+// the workload's status and are deleted on a later reconciliation, or torn
+// down with the workload.
+func (r *Reconciler) deleteOrphans(workload *v1alpha1.Workload, supplyChain *v1alpha1.ClusterSupplyChain, previous, current []v1alpha1.ComponentStatus) []v1alpha1.ComponentStatus {
+	described := map[string]bool{}
+	for _, component := range supplyChain.Spec.Components {
+		described[component.Name] = true
+	}
+	refs := map[string]*corev1.ObjectReference{}
+	for i := range current {
+		refs[current[i].Name] = current[i].StampedRef
+	}
+
+	var remaining []v1alpha1.ComponentStatus
+	for _, component := range previous {
+		if component.StampedRef == nil {
+			continue
+		}
+		ok := described[component.Name]
+		ref := refs[component.Name]
+		if ok && (ref == nil || sameRef(component.StampedRef, ref)) {
+			continue
+		}
+
+		if err := r.deleteOrphansOf(workload, component, ref); err != nil {
+			r.recorder.Eventf(workload, corev1.EventTypeWarning, OrphanDeletionFailedEventReason,
+				"delete objects no longer stamped for component '%s': %s", component.Name, err.Error())
+			if !ok {
+				remaining = append(remaining, component)
+			}
+		}
+	}
+	return remaining
+}
+
+// deleteOrphansOf deletes the objects stamped for a component of the
+// previous realization, except the one now stamped for it, if any.
+func (r *Reconciler) deleteOrphansOf(workload *v1alpha1.Workload, component v1alpha1.ComponentStatus, stamped *corev1.ObjectReference) error {
+	objects, err := r.stampedObjects(workload, component)
+	if err != nil {
+		return fmt.Errorf("list objects: %w", err)
+	}
+
+	for _, obj := range objects {
+		if stamped != nil && isStamped(obj, stamped) {
+			continue
+		}
+		if obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if !ownedBy(obj, workload) {
+			r.recorder.Eventf(workload, corev1.EventTypeWarning, OrphanKeptEventReason,
+				"kept %s '%s' no longer stamped for component '%s', as the workload does not own it",
+				obj.GetKind(), qualifiedName(objectRef(obj)), component.Name)
+			continue
+		}
+		if err := r.repo.Delete(obj); err != nil {
+			return fmt.Errorf("delete %s '%s': %w", obj.GetKind(), qualifiedName(objectRef(obj)), err)
+		}
+		r.recorder.Eventf(workload, corev1.EventTypeNormal, OrphanDeletedEventReason,
+			"deleted %s '%s' no longer stamped for component '%s'",
+			obj.GetKind(), qualifiedName(objectRef(obj)), component.Name)
+	}
+	return nil
+}
+
+// isStamped tells whether the object is the one the reference refers to.
+// The API version is not compared, as the same object is served at every
+// version of its group.
+func isStamped(obj *unstructured.Unstructured, ref *corev1.ObjectReference) bool {
+	return obj.GroupVersionKind().GroupKind() == schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind() &&
+		obj.GetNamespace() == ref.Namespace &&
+		obj.GetName() == ref.Name
+}
+
+func objectRef(obj *unstructured.Unstructured) *corev1.ObjectReference {
+	return &corev1.ObjectReference{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
@@ -16,39 +16,128 @@ package workload
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/artifact"
 	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+	"github.com/vmware-tanzu/cartographer/pkg/gitops"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/oci"
 	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/workload"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/tracing"
 	"github.com/vmware-tanzu/cartographer/pkg/utils"
 )
 
 const reconcileInterval = 5 * time.Second
 
+// DefaultResyncInterval is how often a workload is reconciled again when
+// nothing about it changed, unless the controller or the workload is set
+// otherwise.
+const DefaultResyncInterval = reconcileInterval
+
+// DefaultServiceAccountName is the service account of its namespace a
+// workload is realized as when workloads are impersonated and neither the
+// workload nor its supply chain names one.
+const DefaultServiceAccountName = "default"
+
 type Reconciler struct {
 	repo                    repository.Repository
-	conditionManager        conditions.ConditionManager
 	conditionManagerBuilder conditions.ConditionManagerBuilder
 	realizer                realizer.Realizer
+	interceptor             interceptor.Interceptor
+	resolver                artifact.Resolver
+	recorder                record.EventRecorder
+	clusterContext          templates.ClusterContext
+	impersonator            repository.Impersonator
+	accessMapper            meta.RESTMapper
+	gitWriter               gitops.Writer
+	publisher               oci.Publisher
+	impersonateWorkloads    bool
+	adoption                *adoption
+	resyncInterval          time.Duration
+	dynamicTracker          DynamicTracker
 }
 
-func NewReconciler(repo repository.Repository, conditionManagerBuilder conditions.ConditionManagerBuilder, realizer realizer.Realizer) *Reconciler {
+// reconciliation is the state of a single reconcile of a workload. It is not
+// kept on the Reconciler, which reconciles workloads concurrently.
+type reconciliation struct {
+	conditionManager conditions.ConditionManager
+	statusChanged    bool
+	retryAfter       time.Duration
+}
+
+func NewReconciler(repo repository.Repository, conditionManagerBuilder conditions.ConditionManagerBuilder, realizer realizer.Realizer, interceptor interceptor.Interceptor, resolver artifact.Resolver, recorder record.EventRecorder, clusterContext templates.ClusterContext) *Reconciler {
 	return &Reconciler{
 		repo:                    repo,
 		conditionManagerBuilder: conditionManagerBuilder,
 		realizer:                realizer,
+		interceptor:             interceptor,
+		resolver:                resolver,
+		recorder:                recorder,
+		clusterContext:          clusterContext,
+		adoption:                newAdoption(SupplyChainWorkloads, TemplateWorkloads),
+		resyncInterval:          DefaultResyncInterval,
 	}
 }
 
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+// SetResyncInterval sets how often a workload is reconciled again when
+// nothing about it changed, for the workloads that do not set their own
+// with the ResyncIntervalAnnotation.
+func (r *Reconciler) SetResyncInterval(interval time.Duration) {
+	r.resyncInterval = interval
+}
+
+// SetImpersonator sets what builds the clients that write the objects
+// stamped for the workloads of a supply chain with a serviceAccountRef.
+func (r *Reconciler) SetImpersonator(impersonator repository.Impersonator) {
+	r.impersonator = impersonator
+}
+
+// SetAccessMapper sets what resolves the resources of the objects stamped
+// into another namespace than the workload's, whose access is reviewed
+// before they are written.
+func (r *Reconciler) SetAccessMapper(mapper meta.RESTMapper) {
+	r.accessMapper = mapper
+}
+
+// SetImpersonateWorkloadServiceAccounts chooses whether the objects stamped
+// for every workload are written as the workload's service account, so that
+// RBAC in the workload's namespace bounds what its supply chain may do.
+func (r *Reconciler) SetImpersonateWorkloadServiceAccounts(impersonate bool) {
+	r.impersonateWorkloads = impersonate
+}
+
+// SetGitWriter sets what commits the objects of the components that write to
+// git rather than to the cluster.
+func (r *Reconciler) SetGitWriter(writer gitops.Writer) {
+	r.gitWriter = writer
+}
+
+// SetPublisher sets what pushes the objects of the components published as
+// OCI artifacts.
+func (r *Reconciler) SetPublisher(publisher oci.Publisher) {
+	r.publisher = publisher
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracing.Start(ctx, "Reconcile Workload", tracing.String("namespace", req.Namespace), tracing.String("name", req.Name))
+	defer func() { span.End(err) }()
+
 	logger := logr.FromContext(ctx).
 		WithValues("name", req.Name, "namespace", req.Namespace)
 	ctx = logr.NewContext(ctx, logger)
@@ -59,66 +148,312 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	workload, err := r.repo.GetWorkload(req.Name, req.Namespace)
 	if err != nil || workload == nil {
 		if kerrors.IsNotFound(err) {
+			r.adoption.forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 
 		return ctrl.Result{}, fmt.Errorf("get workload: %w", err)
 	}
 
-	r.conditionManager = r.conditionManagerBuilder(v1alpha1.WorkloadReady, workload.Status.Conditions)
+	if workload.DeletionTimestamp != nil {
+		return r.teardown(reconcileCtx, workload)
+	}
 
-	supplyChain, err := r.getSupplyChainsForWorkload(workload)
+	if err := r.ensureTeardownFinalizer(workload); err != nil {
+		logger.Info("finished")
+		return ctrl.Result{}, err
+	}
+
+	rec := &reconciliation{
+		conditionManager: r.conditionManagerBuilder(v1alpha1.WorkloadReady, workload.Status.Conditions),
+	}
+
+	if workload.Spec.Paused {
+		return r.completePausedReconciliation(reconcileCtx, workload, rec.conditionManager)
+	}
+
+	if workload.Status.ObservedGeneration != workload.Generation {
+		now := metav1.Now()
+		workload.Status.RealizationStartTime = &now
+		workload.Status.RealizationCompletionTime = nil
+	}
+
+	supplyChain, usedDefault, err := r.getSupplyChainsForWorkload(workload, rec)
 	if err != nil {
-		return r.completeReconciliation(reconcileCtx, workload, err)
+		r.adoption.forget(req.NamespacedName)
+		return r.completeReconciliation(reconcileCtx, workload, rec, err)
 	}
 
 	supplyChainGVK, err := utils.GetObjectGVK(supplyChain, r.repo.GetScheme())
 	if err != nil {
-		return r.completeReconciliation(reconcileCtx, workload, fmt.Errorf("get object gvk: %w", err))
+		return r.completeReconciliation(reconcileCtx, workload, rec, fmt.Errorf("get object gvk: %w", err))
 	}
 
 	workload.Status.SupplyChainRef.Kind = supplyChainGVK.Kind
+	if supplyChain.Namespace != "" {
+		workload.Status.SupplyChainRef.Kind = "SupplyChain"
+	}
+	workload.Status.SupplyChainRef.Namespace = supplyChain.Namespace
 	workload.Status.SupplyChainRef.Name = supplyChain.Name
 
 	err = r.checkSupplyChainReadiness(supplyChain)
 	if err != nil {
-		r.conditionManager.AddPositive(MissingReadyInSupplyChainCondition(getSupplyChainReadyCondition(supplyChain)))
-		return r.completeReconciliation(reconcileCtx, workload, err)
+		rec.conditionManager.AddPositive(MissingReadyInSupplyChainCondition(getSupplyChainReadyCondition(supplyChain)))
+		return r.completeReconciliation(reconcileCtx, workload, rec, err)
 	}
-	r.conditionManager.AddPositive(SupplyChainReadyCondition())
 
-	err = r.realizer.Realize(ctx, realizer.NewComponentRealizer(workload, r.repo), supplyChain)
+	supplyChain, err = supplyChain.Resolve(r.repo.GetSupplyChain)
 	if err != nil {
-		switch typedErr := err.(type) {
-		case realizer.GetClusterTemplateError:
-			r.conditionManager.AddPositive(TemplateObjectRetrievalFailureCondition(typedErr))
-		case realizer.StampError:
-			r.conditionManager.AddPositive(TemplateStampFailureCondition(typedErr))
-		case realizer.ApplyStampedObjectError:
-			r.conditionManager.AddPositive(TemplateRejectedByAPIServerCondition(typedErr))
-		case realizer.RetrieveOutputError:
-			r.conditionManager.AddPositive(MissingValueAtPathCondition(typedErr.ComponentName(), typedErr.JsonPathExpression()))
-			err = nil
-		default:
-			r.conditionManager.AddPositive(UnknownComponentErrorCondition(typedErr))
+		rec.conditionManager.AddPositive(SupplyChainExtensionInvalidCondition(err))
+		return r.completeReconciliation(reconcileCtx, workload, rec, fmt.Errorf("resolve supply chain: %w", err))
+	}
+	if usedDefault {
+		rec.conditionManager.AddPositive(DefaultSupplyChainUsedCondition(supplyChain.Name))
+	} else {
+		rec.conditionManager.AddPositive(SupplyChainReadyCondition())
+	}
+	r.adoption.use(req.NamespacedName, supplyChain.Name, templateRefs(workload, supplyChain))
+
+	if err := supplyChain.Spec.ValidateWorkloadParams(workload.Spec.Params); err != nil {
+		rec.conditionManager.AddPositive(InvalidWorkloadParamsCondition(err))
+		return r.completeReconciliation(reconcileCtx, workload, rec, fmt.Errorf("invalid params for supply chain '%s': %w", supplyChain.Name, err))
+	}
+
+	secretParams, err := r.resolveSecretParams(ctx, workload)
+	if err != nil {
+		rec.conditionManager.AddPositive(SecretParamUnavailableCondition(err))
+		return r.completeReconciliation(reconcileCtx, workload, rec, fmt.Errorf("resolve secret params: %w", err))
+	}
+
+	applyOptions, err := r.serviceAccountApplyOptions(workload, supplyChain)
+	if err != nil {
+		return r.completeReconciliation(reconcileCtx, workload, rec, err)
+	}
+	_, _, impersonated := r.serviceAccount(workload, supplyChain)
+
+	componentRealizer := realizer.NewComponentRealizer(workload, r.repo, realizer.ComponentRealizerOptions{
+		SecretParams:            secretParams,
+		Interceptor:             r.interceptor,
+		Resolver:                r.resolver,
+		GitWriter:               r.gitWriter,
+		Publisher:               r.publisher,
+		TemplateNamespace:       supplyChain.Namespace,
+		OwnerReferences:         supplyChain.Spec.OwnerReferences,
+		ServerSideApply:         supplyChain.Spec.ServerSideApply,
+		ClusterContext:          r.clusterContext,
+		ApplyOptions:            applyOptions,
+		AllowedTargetNamespaces: supplyChain.Spec.AllowedTargetNamespaces,
+		Impersonated:            impersonated,
+		AccessMapper:            r.accessMapper,
+	})
+	componentStatuses, realizeErr := r.realizer.Realize(ctx, componentRealizer, supplyChain, workload.Status.Components)
+	submitted, failed, err := componentsSubmittedCondition(workload, supplyChain, realizeErr)
+	componentStatuses = keepStampedRefs(workload.Status.Components, componentStatuses)
+	addReadyConditions(workload.Status.Components, componentStatuses, realizeErr, submitted)
+	for i := range componentStatuses {
+		conditions.ObserveGeneration(componentStatuses[i].Conditions, workload.Generation)
+	}
+	recordComponentEvents(r.recorder, workload, workload.Status.Components, componentStatuses)
+	r.trackStampedKinds(logger, componentStatuses)
+	components := componentStatuses
+	if orphans := r.deleteOrphans(workload, supplyChain, workload.Status.Components, componentStatuses); len(orphans) > 0 {
+		components = append(append([]v1alpha1.ComponentStatus{}, componentStatuses...), orphans...)
+	}
+	rec.statusChanged = rec.statusChanged || !reflect.DeepEqual(workload.Status.Components, components)
+	workload.Status.Components = components
+	workload.Status.Progress = realizer.Progress(componentStatuses)
+	if artifacts := realizer.Artifacts(supplyChain, componentStatuses, workload.Status.Artifacts); !reflect.DeepEqual(workload.Status.Artifacts, artifacts) {
+		workload.Status.Artifacts = artifacts
+		rec.statusChanged = true
+	}
+	if realizeErr != nil {
+		rec.conditionManager.AddPositive(submitted)
+		rec.conditionManager.AddPositive(ResourcesHealthyCondition(componentStatuses))
+
+		if failed && err != nil {
+			rec.retryAfter = rec.trackRetry(workload, submitted)
+		} else {
+			rec.clearRetry(workload)
+		}
+
+		// The event reaches app teams watching the workload's events, and
+		// names whom to contact when the template's maintainers are known.
+		if failed {
+			r.recorder.Event(workload, corev1.EventTypeWarning, submitted.Reason, submitted.Message)
+			RealizationFailuresTotal.WithLabelValues(workload.Namespace, supplyChain.Name, submitted.Reason).Inc()
+		}
+
+		return r.completeReconciliation(reconcileCtx, workload, rec, err)
+	}
+
+	rec.conditionManager.AddPositive(submitted)
+	rec.conditionManager.AddPositive(ResourcesHealthyCondition(componentStatuses))
+	rec.clearRetry(workload)
+
+	return r.completeReconciliation(reconcileCtx, workload, rec, nil)
+}
+
+// componentsSubmittedCondition reports the error realizing the supply chain
+// failed with, if any. A component that is waiting, for a pre hook, a
+// readiness gate or an output, has not failed, and is not retried as an
+// error: the error returned is nil.
+func componentsSubmittedCondition(workload *v1alpha1.Workload, supplyChain *v1alpha1.ClusterSupplyChain, err error) (metav1.Condition, bool, error) {
+	switch typedErr := err.(type) {
+	case nil:
+		return ComponentsSubmittedCondition(), false, nil
+	case realizer.GetClusterTemplateError:
+		if repository.IsMissingAPI(typedErr.Err) {
+			return MissingAPIDependencyCondition(typedErr), true, err
+		}
+		return TemplateObjectRetrievalFailureCondition(typedErr), true, err
+	case realizer.StampError:
+		return TemplateStampFailureCondition(typedErr), true, err
+	case realizer.KindNotAllowedError:
+		return KindNotAllowedCondition(typedErr), true, err
+	case realizer.ApplyStampedObjectError:
+		return applyStampedObjectCondition(typedErr), true, err
+	case realizer.ParamsError:
+		return ImmutableParamOverriddenCondition(typedErr), true, err
+	case realizer.InterceptError:
+		return InterceptorFailureCondition(typedErr), true, err
+	case realizer.ResolveArtifactError:
+		return ArtifactResolutionFailureCondition(typedErr), true, err
+	case realizer.GitWriteError:
+		return GitWriteFailureCondition(typedErr), true, err
+	case realizer.PublishArtifactError:
+		return ArtifactPublishFailureCondition(typedErr), true, err
+	case realizer.TemplateOptionError:
+		if typedErr.Ambiguous() {
+			return AmbiguousTemplateOptionsCondition(typedErr), true, err
 		}
+		return NoMatchingTemplateOptionCondition(typedErr), true, err
+	case realizer.HookError:
+		return HookFailureCondition(typedErr), true, err
+	case realizer.PendingHookError:
+		return PreHookPendingCondition(typedErr), false, nil
+	case realizer.ReadinessGateError:
+		return ReadinessGatePendingCondition(typedErr), false, nil
+	case realizer.RetrieveOutputError:
+		OutputExtractionFailuresTotal.WithLabelValues(workload.Namespace, supplyChain.Name, typedErr.ComponentName()).Inc()
+		return MissingValueAtPathCondition(typedErr.ComponentName(), typedErr.JsonPathExpression()), true, nil
+	default:
+		return UnknownComponentErrorCondition(typedErr), true, err
+	}
+}
 
-		return r.completeReconciliation(reconcileCtx, workload, err)
+// addReadyConditions adds the Ready condition to each component, keeping
+// the time of its last transition from the previous statuses. The component
+// whose error failed the realization reports the ComponentsSubmitted
+// condition it caused.
+func addReadyConditions(previous, current []v1alpha1.ComponentStatus, realizeErr error, submitted metav1.Condition) {
+	ready := map[string]metav1.Condition{}
+	for _, status := range previous {
+		if condition := meta.FindStatusCondition(status.Conditions, v1alpha1.ComponentReady); condition != nil {
+			ready[status.Name] = *condition
+		}
 	}
 
-	r.conditionManager.AddPositive(ComponentsSubmittedCondition())
+	for i := range current {
+		var cause *metav1.Condition
+		if realizeErr != nil && current[i].Message == realizeErr.Error() {
+			cause = &submitted
+		}
 
-	return r.completeReconciliation(reconcileCtx, workload, nil)
+		var conditions []metav1.Condition
+		if condition, ok := ready[current[i].Name]; ok {
+			conditions = append(conditions, condition)
+		}
+		meta.SetStatusCondition(&conditions, ComponentReadyCondition(current[i], cause))
+		current[i].Conditions = append(conditions, current[i].Conditions...)
+	}
 }
 
-func (r *Reconciler) completeReconciliation(ctx context.Context, workload *v1alpha1.Workload, err error) (ctrl.Result, error) {
+// templateRefs lists the templates the supply chain stamps for the
+// workload, leaving out components whose template options do not single
+// one out.
+func templateRefs(workload *v1alpha1.Workload, supplyChain *v1alpha1.ClusterSupplyChain) []v1alpha1.ClusterTemplateReference {
+	var refs []v1alpha1.ClusterTemplateReference
+	for i := range supplyChain.Spec.Components {
+		ref, err := realizer.SelectTemplateRef(workload, &supplyChain.Spec.Components[i])
+		if err != nil {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// serviceAccountApplyOptions writes the stamped objects as the service
+// account the workload is realized as, if any.
+func (r *Reconciler) serviceAccountApplyOptions(workload *v1alpha1.Workload, supplyChain *v1alpha1.ClusterSupplyChain) ([]repository.ApplyOption, error) {
+	namespace, name, ok := r.serviceAccount(workload, supplyChain)
+	if !ok {
+		return nil, nil
+	}
+	if r.impersonator == nil {
+		return nil, fmt.Errorf("workload is realized as service account '%s/%s', but the controller cannot impersonate it", namespace, name)
+	}
+
+	writer, err := r.impersonator.ServiceAccountClient(namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("impersonate service account '%s/%s': %w", namespace, name, err)
+	}
+	return []repository.ApplyOption{repository.WithWriter(writer)}, nil
+}
+
+// serviceAccount is the service account the workload is realized as. When
+// workloads are impersonated, it is the workload's own, then that of the
+// supply chain, then the default service account of the workload's
+// namespace. Otherwise it is that of the supply chain, if any. It is always
+// one of the workload's namespace, so that a tenant cannot write as the
+// service account of another namespace.
+// A namespaced supply chain is always realized as a service account of its
+// own namespace, as those who write it may not write as the controller.
+func (r *Reconciler) serviceAccount(workload *v1alpha1.Workload, supplyChain *v1alpha1.ClusterSupplyChain) (namespace, name string, ok bool) {
+	if supplyChain.Namespace != "" {
+		return supplyChain.Namespace, namespacedServiceAccountName(workload, supplyChain, r.impersonateWorkloads), true
+	}
+	if r.impersonateWorkloads && workload.Spec.ServiceAccountName != "" {
+		return workload.Namespace, workload.Spec.ServiceAccountName, true
+	}
+	if ref := supplyChain.Spec.ServiceAccountRef; ref != nil {
+		return workload.Namespace, ref.Name, true
+	}
+	if r.impersonateWorkloads {
+		return workload.Namespace, DefaultServiceAccountName, true
+	}
+	return "", "", false
+}
+
+// namespacedServiceAccountName chooses among the service accounts of a
+// namespaced supply chain's namespace as serviceAccount does, falling back
+// to the workload's own, then to the default service account.
+func namespacedServiceAccountName(workload *v1alpha1.Workload, supplyChain *v1alpha1.ClusterSupplyChain, impersonateWorkloads bool) string {
+	ref := supplyChain.Spec.ServiceAccountRef
+	switch {
+	case impersonateWorkloads && workload.Spec.ServiceAccountName != "":
+		return workload.Spec.ServiceAccountName
+	case ref != nil:
+		return ref.Name
+	case workload.Spec.ServiceAccountName != "":
+		return workload.Spec.ServiceAccountName
+	default:
+		return DefaultServiceAccountName
+	}
+}
+
+func (r *Reconciler) completeReconciliation(ctx context.Context, workload *v1alpha1.Workload, rec *reconciliation, err error) (ctrl.Result, error) {
 	logger := logr.FromContext(ctx)
 
+	rec.trackRealizationDeadline(workload, err)
+
+	previous := workload.Status.Conditions
 	var changed bool
-	workload.Status.Conditions, changed = r.conditionManager.Finalize()
+	workload.Status.Conditions, changed = rec.conditionManager.Finalize()
+	conditions.ObserveGeneration(workload.Status.Conditions, workload.Generation)
 
 	var updateErr error
-	if changed || (workload.Status.ObservedGeneration != workload.Generation) {
+	if changed || rec.statusChanged || (workload.Status.ObservedGeneration != workload.Generation) {
 		workload.Status.ObservedGeneration = workload.Generation
 		updateErr = r.repo.StatusUpdate(workload)
 		if updateErr != nil {
@@ -129,18 +464,148 @@ func (r *Reconciler) completeReconciliation(ctx context.Context, workload *v1alp
 			}
 		}
 	}
+	if updateErr == nil {
+		recordConditionEvents(r.recorder, workload, previous)
+	}
 
 	logger.Info("finished")
 
 	if err != nil {
+		if rec.retryAfter > 0 {
+			logger.Error(err, "realization keeps failing, backing off", "retryAfter", rec.retryAfter.String())
+			return ctrl.Result{RequeueAfter: rec.retryAfter}, nil
+		}
 		return ctrl.Result{}, err
 	}
 
-	if !r.conditionManager.IsSuccessful() { // TODO: Discuss rename to IsReady
+	if !rec.conditionManager.IsSuccessful() { // TODO: Discuss rename to IsReady
 		return ctrl.Result{}, fmt.Errorf("workload not ready")
 	}
 
-	return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+	return ctrl.Result{RequeueAfter: r.resyncIntervalOf(ctx, workload)}, nil
+}
+
+// resyncIntervalOf returns the interval the workload's annotation asks for,
+// or else the controller's. An annotation that is not a positive duration is
+// ignored.
+func (r *Reconciler) resyncIntervalOf(ctx context.Context, workload *v1alpha1.Workload) time.Duration {
+	value, ok := workload.Annotations[v1alpha1.ResyncIntervalAnnotation]
+	if !ok {
+		return r.resyncInterval
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		logr.FromContext(ctx).Info("ignoring invalid resync interval", "annotation", v1alpha1.ResyncIntervalAnnotation, "value", value)
+		return r.resyncInterval
+	}
+	return interval
+}
+
+// completePausedReconciliation reports that the workload is paused, keeping
+// the conditions and component statuses of its last realization for them to
+// be inspected. Unpausing changes the spec, so nothing is requeued.
+func (r *Reconciler) completePausedReconciliation(ctx context.Context, workload *v1alpha1.Workload, conditionManager conditions.ConditionManager) (ctrl.Result, error) {
+	logger := logr.FromContext(ctx)
+
+	for _, condition := range workload.Status.Conditions {
+		switch condition.Type {
+		case v1alpha1.WorkloadReady, v1alpha1.WorkloadPaused:
+		case v1alpha1.WorkloadRealizationDeadlineExceeded:
+			conditionManager.AddNegative(condition)
+		default:
+			conditionManager.AddPositive(condition)
+		}
+	}
+	conditionManager.AddNegative(WorkloadPausedCondition())
+
+	previous := workload.Status.Conditions
+	var changed bool
+	workload.Status.Conditions, changed = conditionManager.Finalize()
+	conditions.ObserveGeneration(workload.Status.Conditions, workload.Generation)
+
+	if changed || workload.Status.ObservedGeneration != workload.Generation {
+		workload.Status.ObservedGeneration = workload.Generation
+		if err := r.repo.StatusUpdate(workload); err != nil {
+			logger.Info("finished")
+			return ctrl.Result{}, fmt.Errorf("update workload status: %w", err)
+		}
+	}
+	recordConditionEvents(r.recorder, workload, previous)
+
+	logger.Info("finished")
+	return ctrl.Result{}, nil
+}
+
+// trackRealizationDeadline records when the current generation of the
+// workload was first realized, and reports whether that happened within the
+// workload's maxDuration.
+func (rec *reconciliation) trackRealizationDeadline(workload *v1alpha1.Workload, err error) {
+	status := &workload.Status
+	if status.RealizationStartTime == nil {
+		return
+	}
+
+	if status.RealizationCompletionTime == nil && err == nil && rec.conditionManager.IsSuccessful() && status.Progress == 100 {
+		now := metav1.Now()
+		status.RealizationCompletionTime = &now
+		rec.statusChanged = true
+		RealizationDurationSeconds.Observe(now.Sub(status.RealizationStartTime.Time).Seconds())
+	}
+
+	if workload.Spec.MaxDuration == nil {
+		return
+	}
+
+	maxDuration := workload.Spec.MaxDuration.Duration
+	deadline := status.RealizationStartTime.Add(maxDuration)
+	switch {
+	case status.RealizationCompletionTime == nil && time.Now().After(deadline):
+		rec.conditionManager.AddNegative(RealizationDeadlineExceededCondition(maxDuration))
+	case status.RealizationCompletionTime != nil && status.RealizationCompletionTime.After(deadline):
+		rec.conditionManager.AddNegative(RealizedAfterDeadlineCondition(maxDuration))
+	default:
+		rec.conditionManager.AddNegative(RealizationWithinDeadlineCondition())
+	}
+}
+
+// applyStampedObjectCondition distinguishes the verb the API server refused
+// the controller permission for, or an access review denied before the
+// write, from a rejection of the object itself, and
+// an object found invalid before submission, or rejected by a dry run, which
+// wrote nothing, from one rejected by the write. An object of a kind that is
+// not installed is a missing dependency, retried until the kind is.
+func applyStampedObjectCondition(err realizer.ApplyStampedObjectError) metav1.Condition {
+	if repository.IsMissingAPI(err.Err) {
+		return MissingAPIDependencyCondition(err)
+	}
+
+	var objectErr repository.ObjectError
+	if !errors.As(err.Err, &objectErr) {
+		return TemplateRejectedByAPIServerCondition(err)
+	}
+
+	if objectErr.Denied {
+		return PermissionDeniedCondition(err)
+	}
+	if objectErr.Forbidden() {
+		switch objectErr.Verb {
+		case repository.ListVerb:
+			return CannotListCreatedObjectsCondition(err)
+		case repository.CreateVerb:
+			return CannotCreateObjectCondition(err)
+		case repository.PatchVerb:
+			return CannotPatchObjectCondition(err)
+		}
+	}
+
+	if objectErr.Verb == repository.ValidateVerb {
+		return InvalidStampedObjectCondition(err)
+	}
+	if objectErr.DryRun {
+		return TemplateRejectedByDryRunCondition(err)
+	}
+	return TemplateRejectedByAPIServerCondition(err)
 }
 
 func (r *Reconciler) checkSupplyChainReadiness(supplyChain *v1alpha1.ClusterSupplyChain) error {
@@ -160,25 +625,51 @@ func getSupplyChainReadyCondition(supplyChain *v1alpha1.ClusterSupplyChain) meta
 	return metav1.Condition{}
 }
 
-func (r *Reconciler) getSupplyChainsForWorkload(workload *v1alpha1.Workload) (*v1alpha1.ClusterSupplyChain, error) {
-	if len(workload.Labels) == 0 {
-		r.conditionManager.AddPositive(WorkloadMissingLabelsCondition())
-		return nil, fmt.Errorf("workload is missing required labels")
+// getSupplyChainsForWorkload chooses the supply chain of the workload,
+// reporting whether it is a default supply chain.
+func (r *Reconciler) getSupplyChainsForWorkload(workload *v1alpha1.Workload, rec *reconciliation) (*v1alpha1.ClusterSupplyChain, bool, error) {
+	supplyChains, usedDefault, err := r.candidateSupplyChains(workload)
+	if err == nil && len(supplyChains) == 0 && len(workload.Labels) == 0 {
+		rec.conditionManager.AddPositive(WorkloadMissingLabelsCondition())
+		return nil, false, fmt.Errorf("workload is missing required labels")
 	}
 
-	supplyChains, err := r.repo.GetSupplyChainsForWorkload(workload)
 	if err != nil || len(supplyChains) == 0 {
-		r.conditionManager.AddPositive(SupplyChainNotFoundCondition(workload.Labels))
+		rec.conditionManager.AddPositive(SupplyChainNotFoundCondition(workload.Labels))
 
 		if err != nil {
-			return nil, fmt.Errorf("get supply chain by label: %w", err)
+			return nil, false, fmt.Errorf("get supply chain by label: %w", err)
 		} else {
-			return nil, fmt.Errorf("no supply chain found where full selector is satisfied by labels: %v", workload.Labels)
+			return nil, false, fmt.Errorf("no supply chain found where full selector is satisfied by labels: %v", workload.Labels)
 		}
-	} else if len(supplyChains) > 1 {
-		r.conditionManager.AddPositive(TooManySupplyChainMatchesCondition())
-		return nil, fmt.Errorf("too many supply chains match the workload selector")
 	}
 
-	return supplyChains[0].DeepCopy(), nil
+	supplyChain, selection := selector.ChooseSupplyChain(supplyChains)
+	if workload.Status.SupplyChainSelection != selection {
+		workload.Status.SupplyChainSelection = selection
+		rec.statusChanged = true
+	}
+
+	if supplyChain == nil {
+		rec.conditionManager.AddPositive(TooManySupplyChainMatchesCondition())
+		return nil, false, fmt.Errorf("too many supply chains match the workload selector")
+	}
+
+	return supplyChain, usedDefault, nil
+}
+
+// candidateSupplyChains returns the supply chains that select the workload
+// or, when none do, the default supply chains, reporting whether they are
+// the defaults.
+func (r *Reconciler) candidateSupplyChains(workload *v1alpha1.Workload) ([]v1alpha1.ClusterSupplyChain, bool, error) {
+	supplyChains, err := r.repo.GetSupplyChainsForWorkload(workload)
+	if err != nil || len(supplyChains) > 0 {
+		return supplyChains, false, err
+	}
+
+	defaults, err := r.repo.GetDefaultSupplyChainsForWorkload(workload)
+	if err != nil {
+		return nil, false, err
+	}
+	return defaults, len(defaults) > 0, nil
 }
@@ -17,29 +17,42 @@ package workload_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
 	. "github.com/onsi/gomega/gstruct"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/artifact/artifactfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/conditions"
 	"github.com/vmware-tanzu/cartographer/pkg/conditions/conditionsfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/controller/workload"
+	controllerfakes "github.com/vmware-tanzu/cartographer/pkg/controller/workload/workloadfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor/interceptorfakes"
 	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/workload"
 	"github.com/vmware-tanzu/cartographer/pkg/realizer/workload/workloadfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/registrar"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
@@ -54,6 +67,7 @@ var _ = Describe("Reconciler", func() {
 			repo             *repositoryfakes.FakeRepository
 			conditionManager *conditionsfakes.FakeConditionManager
 			rlzr             *workloadfakes.FakeRealizer
+			recorder         *record.FakeRecorder
 			wl               *v1alpha1.Workload
 			workloadLabels   map[string]string
 		)
@@ -72,7 +86,7 @@ var _ = Describe("Reconciler", func() {
 			conditionManager.IsSuccessfulReturns(true)
 
 			rlzr = &workloadfakes.FakeRealizer{}
-			rlzr.RealizeReturns(nil)
+			rlzr.RealizeReturns(nil, nil)
 
 			repo = &repositoryfakes.FakeRepository{}
 			scheme := runtime.NewScheme()
@@ -80,7 +94,8 @@ var _ = Describe("Reconciler", func() {
 			Expect(err).NotTo(HaveOccurred())
 			repo.GetSchemeReturns(scheme)
 
-			reconciler = workload.NewReconciler(repo, fakeConditionManagerBuilder, rlzr)
+			recorder = record.NewFakeRecorder(10)
+			reconciler = workload.NewReconciler(repo, fakeConditionManagerBuilder, rlzr, &interceptorfakes.FakeInterceptor{}, &artifactfakes.FakeResolver{}, recorder, templates.ClusterContext{})
 
 			req = ctrl.Request{
 				NamespacedName: types.NamespacedName{Name: "my-workload-name", Namespace: "my-namespace"},
@@ -162,6 +177,21 @@ var _ = Describe("Reconciler", func() {
 			}))
 		})
 
+		It("records the generation the conditions were observed on", func() {
+			conditionManager.FinalizeReturns([]metav1.Condition{
+				{Type: "Ready", Status: "True", Reason: "Ready"},
+				{Type: "SupplyChainReady", Status: "True", Reason: "Ready"},
+			}, true)
+
+			_, _ = reconciler.Reconcile(ctx, req)
+
+			updatedWorkload := repo.StatusUpdateArgsForCall(0).(*v1alpha1.Workload)
+			Expect(updatedWorkload.Status.Conditions).To(HaveLen(2))
+			for _, condition := range updatedWorkload.Status.Conditions {
+				Expect(condition.ObservedGeneration).To(BeEquivalentTo(1))
+			}
+		})
+
 		It("requests supply chains from the repo", func() {
 			_, _ = reconciler.Reconcile(ctx, req)
 
@@ -201,6 +231,32 @@ var _ = Describe("Reconciler", func() {
 				Expect(result).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
 			})
 
+			It("reschedules after the resync interval the controller is set to", func() {
+				reconciler.SetResyncInterval(time.Minute)
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+			})
+
+			It("reschedules after the resync interval the workload asks for", func() {
+				reconciler.SetResyncInterval(time.Minute)
+				wl.Annotations = map[string]string{"carto.run/resync-interval": "10m"}
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{RequeueAfter: 10 * time.Minute}))
+			})
+
+			It("ignores a resync interval the workload asks for that is not a positive duration", func() {
+				wl.Annotations = map[string]string{"carto.run/resync-interval": "-1m"}
+				result, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
+				Expect(out).To(Say(`"msg":"ignoring invalid resync interval"`))
+			})
+
 			It("sets the SupplyChainRef", func() {
 				_, _ = reconciler.Reconcile(ctx, req)
 
@@ -208,6 +264,150 @@ var _ = Describe("Reconciler", func() {
 				Expect(wl.Status.SupplyChainRef.Name).To(Equal(supplyChainName))
 			})
 
+			It("keeps the state of concurrent reconciles apart", func() {
+				var (
+					mu       sync.Mutex
+					managers []*conditionsfakes.FakeConditionManager
+				)
+				conditionManagerBuilder := func(string, []metav1.Condition) conditions.ConditionManager {
+					mu.Lock()
+					defer mu.Unlock()
+					manager := &conditionsfakes.FakeConditionManager{}
+					manager.IsSuccessfulReturns(true)
+					managers = append(managers, manager)
+					return manager
+				}
+				reconciler = workload.NewReconciler(repo, conditionManagerBuilder, rlzr, &interceptorfakes.FakeInterceptor{}, &artifactfakes.FakeResolver{}, recorder, templates.ClusterContext{})
+
+				repo.GetWorkloadStub = func(name, namespace string) (*v1alpha1.Workload, error) {
+					return &v1alpha1.Workload{
+						ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 1, Labels: workloadLabels},
+					}, nil
+				}
+
+				realizing := make(chan struct{})
+				release := make(chan struct{})
+				var once sync.Once
+				rlzr.RealizeStub = func(context.Context, realizer.ComponentRealizer, *v1alpha1.ClusterSupplyChain, []v1alpha1.ComponentStatus) ([]v1alpha1.ComponentStatus, error) {
+					first := false
+					once.Do(func() { first = true })
+					if first {
+						close(realizing)
+						<-release
+					}
+					return nil, nil
+				}
+
+				done := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					defer close(done)
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+				}()
+				<-realizing
+
+				other := ctrl.Request{NamespacedName: types.NamespacedName{Name: "other-workload", Namespace: "my-namespace"}}
+				_, err := reconciler.Reconcile(ctx, other)
+				Expect(err).NotTo(HaveOccurred())
+
+				close(release)
+				<-done
+
+				Expect(managers).To(HaveLen(2))
+				for _, manager := range managers {
+					Expect(manager.AddPositiveCallCount()).To(Equal(3))
+					Expect(manager.AddPositiveArgsForCall(1)).To(Equal(workload.ComponentsSubmittedCondition()))
+					Expect(manager.FinalizeCallCount()).To(Equal(1))
+				}
+			})
+
+			Context("that is namespaced", func() {
+				BeforeEach(func() {
+					supplyChain.Namespace = "my-namespace"
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+				})
+
+				It("refers to the SupplyChain in the workload namespace", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(wl.Status.SupplyChainRef.Kind).To(Equal("SupplyChain"))
+					Expect(wl.Status.SupplyChainRef.Namespace).To(Equal("my-namespace"))
+					Expect(wl.Status.SupplyChainRef.Name).To(Equal(supplyChainName))
+				})
+			})
+
+			Context("adoption metrics", func() {
+				BeforeEach(func() {
+					workload.SupplyChainWorkloads.Reset()
+					workload.TemplateWorkloads.Reset()
+
+					supplyChain.Spec.Components = []v1alpha1.SupplyChainComponent{
+						{Name: "source", TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterSourceTemplate", Name: "git"}},
+						{Name: "tests", TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterSourceTemplate", Name: "git"}},
+						{Name: "image", TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterImageTemplate", Name: "kpack"}},
+					}
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+				})
+
+				It("counts the workload as using the supply chain and each of its templates once", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(testutil.ToFloat64(workload.SupplyChainWorkloads.WithLabelValues("my-namespace", supplyChainName))).To(Equal(1.0))
+					Expect(testutil.ToFloat64(workload.TemplateWorkloads.WithLabelValues("ClusterSourceTemplate", "git"))).To(Equal(1.0))
+					Expect(testutil.ToFloat64(workload.TemplateWorkloads.WithLabelValues("ClusterImageTemplate", "kpack"))).To(Equal(1.0))
+				})
+
+				It("counts each workload using the supply chain", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					other := ctrl.Request{NamespacedName: types.NamespacedName{Name: "other-workload", Namespace: "my-namespace"}}
+					_, _ = reconciler.Reconcile(ctx, other)
+
+					Expect(testutil.ToFloat64(workload.SupplyChainWorkloads.WithLabelValues("my-namespace", supplyChainName))).To(Equal(2.0))
+				})
+
+				It("counts the workloads of each namespace apart", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					other := ctrl.Request{NamespacedName: types.NamespacedName{Name: "my-workload-name", Namespace: "other-namespace"}}
+					_, _ = reconciler.Reconcile(ctx, other)
+
+					Expect(testutil.ToFloat64(workload.SupplyChainWorkloads.WithLabelValues("my-namespace", supplyChainName))).To(Equal(1.0))
+					Expect(testutil.ToFloat64(workload.SupplyChainWorkloads.WithLabelValues("other-namespace", supplyChainName))).To(Equal(1.0))
+				})
+
+				It("stops counting the workload once it is deleted", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					repo.GetWorkloadReturns(nil, kerrors.NewNotFound(schema.GroupResource{}, "my-workload-name"))
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(testutil.CollectAndCount(workload.SupplyChainWorkloads)).To(Equal(0))
+					Expect(testutil.CollectAndCount(workload.TemplateWorkloads)).To(Equal(0))
+				})
+
+				It("stops counting the workload once no supply chain selects it", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					repo.GetSupplyChainsForWorkloadReturns(nil, nil)
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(testutil.CollectAndCount(workload.SupplyChainWorkloads)).To(Equal(0))
+				})
+			})
+
+			Context("that selects workloads without labels by its match expressions", func() {
+				BeforeEach(func() {
+					wl.Labels = nil
+				})
+
+				It("uses the supply chain", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(wl.Status.SupplyChainRef.Name).To(Equal(supplyChainName))
+					Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workload.SupplyChainReadyCondition()))
+				})
+			})
+
 			It("calls the condition manager to specify the supply chain is ready", func() {
 				_, _ = reconciler.Reconcile(ctx, req)
 				Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workload.SupplyChainReadyCondition()))
@@ -218,88 +418,1231 @@ var _ = Describe("Reconciler", func() {
 				Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.ComponentsSubmittedCondition()))
 			})
 
-			Context("but getting the object GVK fails", func() {
+			Context("and the supply chain declares the params of its workloads", func() {
 				BeforeEach(func() {
-					repo.GetSchemeReturns(runtime.NewScheme())
+					supplyChain.Spec.WorkloadParams = []v1alpha1.WorkloadParamDeclaration{
+						{Name: "port", Type: "integer", Required: true},
+					}
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
 				})
-				It("returns a helpful error", func() {
+
+				It("reports the params that do not satisfy the declaration without realizing the supply chain", func() {
+					wl.Spec.Params = []v1alpha1.WorkloadParam{
+						{Name: "prot", Value: apiextensionsv1.JSON{Raw: []byte(`8080`)}},
+					}
+
 					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).To(MatchError("invalid params for supply chain 'some-supply-chain': param 'port' is required, param 'prot' is not declared by the supply chain"))
+
+					Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(metav1.Condition{
+						Type:    "ComponentsSubmitted",
+						Status:  metav1.ConditionFalse,
+						Reason:  "InvalidWorkloadParams",
+						Message: "param 'port' is required, param 'prot' is not declared by the supply chain",
+					}))
+					Expect(rlzr.RealizeCallCount()).To(Equal(0))
+				})
 
-					Expect(err.Error()).To(ContainSubstring("get object gvk: "))
+				It("realizes the supply chain for params that satisfy the declaration", func() {
+					wl.Spec.Params = []v1alpha1.WorkloadParam{
+						{Name: "port", Value: apiextensionsv1.JSON{Raw: []byte(`8080`)}},
+					}
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rlzr.RealizeCallCount()).To(Equal(1))
 				})
 			})
 
-			Context("but the supply chain is not in a ready state", func() {
+			Context("and the supply chain has a service account", func() {
+				var impersonator *repositoryfakes.FakeImpersonator
+
 				BeforeEach(func() {
-					supplyChain.Status.Conditions = []metav1.Condition{
-						{
-							Type:    "Ready",
-							Status:  "False",
-							Reason:  "SomeReason",
-							Message: "some informative message",
+					wl.Namespace = "my-namespace"
+					supplyChain.Spec.ServiceAccountRef = &v1alpha1.ServiceAccountRef{Name: "stamper"}
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+
+					impersonator = &repositoryfakes.FakeImpersonator{}
+					impersonator.ServiceAccountClientReturns(&repositoryfakes.FakeClient{}, nil)
+					reconciler.SetImpersonator(impersonator)
+				})
+
+				It("realizes the supply chain as the service account of the workload's namespace", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(impersonator.ServiceAccountClientCallCount()).To(Equal(1))
+					namespace, name := impersonator.ServiceAccountClientArgsForCall(0)
+					Expect(namespace).To(Equal("my-namespace"))
+					Expect(name).To(Equal("stamper"))
+					Expect(rlzr.RealizeCallCount()).To(Equal(1))
+				})
+
+				It("impersonates the service account of the workload's namespace, whatever namespace the supply chain names", func() {
+					supplyChain.Spec.ServiceAccountRef.Namespace = "tenants"
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					namespace, _ := impersonator.ServiceAccountClientArgsForCall(0)
+					Expect(namespace).To(Equal("my-namespace"))
+				})
+
+				It("does not realize the supply chain when the service account cannot be impersonated", func() {
+					impersonator.ServiceAccountClientReturns(nil, errors.New("some client error"))
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).To(MatchError("impersonate service account 'my-namespace/stamper': some client error"))
+					Expect(rlzr.RealizeCallCount()).To(Equal(0))
+				})
+
+				It("prefers the service account of the workload when workloads are impersonated", func() {
+					reconciler.SetImpersonateWorkloadServiceAccounts(true)
+					wl.Spec.ServiceAccountName = "my-service-account"
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					namespace, name := impersonator.ServiceAccountClientArgsForCall(0)
+					Expect(namespace).To(Equal("my-namespace"))
+					Expect(name).To(Equal("my-service-account"))
+				})
+			})
+
+			Context("and workloads are impersonated", func() {
+				var impersonator *repositoryfakes.FakeImpersonator
+
+				BeforeEach(func() {
+					wl.Namespace = "my-namespace"
+					impersonator = &repositoryfakes.FakeImpersonator{}
+					impersonator.ServiceAccountClientReturns(&repositoryfakes.FakeClient{}, nil)
+					reconciler.SetImpersonator(impersonator)
+					reconciler.SetImpersonateWorkloadServiceAccounts(true)
+				})
+
+				It("realizes the supply chain as the service account of the workload", func() {
+					wl.Spec.ServiceAccountName = "my-service-account"
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(impersonator.ServiceAccountClientCallCount()).To(Equal(1))
+					namespace, name := impersonator.ServiceAccountClientArgsForCall(0)
+					Expect(namespace).To(Equal("my-namespace"))
+					Expect(name).To(Equal("my-service-account"))
+					Expect(rlzr.RealizeCallCount()).To(Equal(1))
+				})
+
+				It("realizes the supply chain as the default service account of the namespace when the workload names none", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					namespace, name := impersonator.ServiceAccountClientArgsForCall(0)
+					Expect(namespace).To(Equal("my-namespace"))
+					Expect(name).To(Equal("default"))
+				})
+
+				It("does not realize the supply chain when the controller cannot impersonate", func() {
+					reconciler.SetImpersonator(nil)
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).To(MatchError("workload is realized as service account 'my-namespace/default', but the controller cannot impersonate it"))
+					Expect(rlzr.RealizeCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("and the supply chain is namespaced", func() {
+				var impersonator *repositoryfakes.FakeImpersonator
+
+				BeforeEach(func() {
+					wl.Namespace = "my-namespace"
+					supplyChain.Namespace = "my-namespace"
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+
+					impersonator = &repositoryfakes.FakeImpersonator{}
+					impersonator.ServiceAccountClientReturns(&repositoryfakes.FakeClient{}, nil)
+					reconciler.SetImpersonator(impersonator)
+				})
+
+				It("realizes the supply chain as the default service account of its namespace, even when workloads are not impersonated", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(impersonator.ServiceAccountClientCallCount()).To(Equal(1))
+					namespace, name := impersonator.ServiceAccountClientArgsForCall(0)
+					Expect(namespace).To(Equal("my-namespace"))
+					Expect(name).To(Equal("default"))
+					Expect(rlzr.RealizeCallCount()).To(Equal(1))
+				})
+
+				It("realizes the supply chain as the service account of the workload", func() {
+					wl.Spec.ServiceAccountName = "my-service-account"
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					namespace, name := impersonator.ServiceAccountClientArgsForCall(0)
+					Expect(namespace).To(Equal("my-namespace"))
+					Expect(name).To(Equal("my-service-account"))
+				})
+
+				It("realizes the supply chain as its service account, in its own namespace", func() {
+					supplyChain.Spec.ServiceAccountRef = &v1alpha1.ServiceAccountRef{Name: "stamper", Namespace: "cartographer-system"}
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					namespace, name := impersonator.ServiceAccountClientArgsForCall(0)
+					Expect(namespace).To(Equal("my-namespace"))
+					Expect(name).To(Equal("stamper"))
+				})
+
+				It("does not realize the supply chain when the controller cannot impersonate", func() {
+					reconciler.SetImpersonator(nil)
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).To(MatchError("workload is realized as service account 'my-namespace/default', but the controller cannot impersonate it"))
+					Expect(rlzr.RealizeCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("and a param of the workload comes from a secret", func() {
+				var (
+					impersonator *repositoryfakes.FakeImpersonator
+					secretReader *repositoryfakes.FakeClient
+					secretData   map[string]interface{}
+				)
+
+				BeforeEach(func() {
+					wl.Namespace = "my-namespace"
+					wl.Spec.Params = []v1alpha1.WorkloadParam{{
+						Name: "token",
+						ValueFrom: &v1alpha1.WorkloadParamSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+								Key:                  "token",
+							},
 						},
+					}}
+
+					secretData = map[string]interface{}{"token": "czNjcjN0"}
+					secretReader = &repositoryfakes.FakeClient{}
+					secretReader.GetStub = func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						obj.(*unstructured.Unstructured).Object["data"] = secretData
+						return nil
 					}
-					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+					impersonator = &repositoryfakes.FakeImpersonator{}
+					impersonator.ServiceAccountClientReturns(secretReader, nil)
+					reconciler.SetImpersonator(impersonator)
 				})
-				It("returns a helpful error", func() {
+
+				It("reads the secret as the service account of the workload", func() {
+					wl.Spec.ServiceAccountName = "my-service-account"
+
 					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
 
-					Expect(err.Error()).To(ContainSubstring("supply-chain is not in ready condition"))
+					namespace, name := impersonator.ServiceAccountClientArgsForCall(0)
+					Expect(namespace).To(Equal("my-namespace"))
+					Expect(name).To(Equal("my-service-account"))
+
+					Expect(secretReader.GetCallCount()).To(Equal(1))
+					_, key, obj := secretReader.GetArgsForCall(0)
+					Expect(key).To(Equal(client.ObjectKey{Namespace: "my-namespace", Name: "registry"}))
+					Expect(obj.GetObjectKind().GroupVersionKind().Kind).To(Equal("Secret"))
+					Expect(rlzr.RealizeCallCount()).To(Equal(1))
 				})
 
-				It("calls the condition manager to report supply chain not ready", func() {
+				It("does not write the value into the workload", func() {
 					_, _ = reconciler.Reconcile(ctx, req)
-					expectedCondition := metav1.Condition{
-						Type:               v1alpha1.WorkloadSupplyChainReady,
-						Status:             metav1.ConditionFalse,
-						ObservedGeneration: 0,
-						LastTransitionTime: metav1.Time{},
-						Reason:             "SomeReason",
-						Message:            "some informative message",
+
+					Expect(wl.Spec.Params[0].Value.Raw).To(BeNil())
+				})
+
+				It("does not realize the supply chain when the secret has no such key", func() {
+					secretData = map[string]interface{}{}
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).To(MatchError("resolve secret params: param 'token': secret 'registry' has no key 'token'"))
+
+					Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(metav1.Condition{
+						Type:    "ComponentsSubmitted",
+						Status:  metav1.ConditionFalse,
+						Reason:  "SecretParamUnavailable",
+						Message: "param 'token': secret 'registry' has no key 'token'",
+					}))
+					Expect(rlzr.RealizeCallCount()).To(Equal(0))
+				})
+
+				It("realizes the supply chain without the param when an optional key is missing", func() {
+					optional := true
+					wl.Spec.Params[0].ValueFrom.SecretKeyRef.Optional = &optional
+					secretData = map[string]interface{}{}
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rlzr.RealizeCallCount()).To(Equal(1))
+				})
+
+				It("does not realize the supply chain when the controller cannot impersonate", func() {
+					reconciler.SetImpersonator(nil)
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).To(MatchError("resolve secret params: workload has params from secrets, but the controller cannot impersonate service account 'my-namespace/default' to read them"))
+					Expect(rlzr.RealizeCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("and the realizer reports the progress of the components", func() {
+				var statuses []v1alpha1.ComponentStatus
+
+				BeforeEach(func() {
+					statuses = []v1alpha1.ComponentStatus{
+						{Name: "source", State: "Realized"},
+						{Name: "image", State: "Waiting", Message: "waiting on a build"},
+						{Name: "config", State: "Blocked", Message: "blocked by component 'image'"},
 					}
-					Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workload.MissingReadyInSupplyChainCondition(expectedCondition)))
+					rlzr.RealizeReturns(statuses, nil)
+				})
+
+				It("records the component statuses and progress on the workload", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(wl.Status.Components).To(Equal(statuses))
+					Expect(wl.Status.Progress).To(Equal(int32(33)))
+				})
+
+				It("updates the status when only the progress has changed", func() {
+					wl.Status.ObservedGeneration = wl.Generation
+					conditionManager.FinalizeReturns(nil, false)
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+				})
+
+				It("does not update the status when nothing has changed", func() {
+					wl.Status.ObservedGeneration = wl.Generation
+					wl.Status.Components = statuses
+					conditionManager.FinalizeReturns(nil, false)
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(repo.StatusUpdateCallCount()).To(Equal(0))
 				})
 			})
 
-			Context("but the realizer returns an error", func() {
-				Context("of type GetClusterTemplateError", func() {
-					var templateError error
+			Context("and the realizer reports the state of each component", func() {
+				var stampError realizer.StampError
+
+				BeforeEach(func() {
+					stampError = realizer.StampError{
+						Err:       errors.New("some error"),
+						Component: &v1alpha1.SupplyChainComponent{Name: "config"},
+					}
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{
+						{Name: "source", State: "Realized"},
+						{Name: "image", State: "Realized", Conditions: []metav1.Condition{{Type: "Healthy", Status: metav1.ConditionFalse, Reason: "MatchedCondition", Message: "build failed"}}},
+						{Name: "config", State: "Failed", Message: stampError.Error()},
+						{Name: "deployer", State: "Blocked", Message: "blocked by component 'config'"},
+					}, stampError)
+				})
+
+				ready := func(name string) *metav1.Condition {
+					for _, component := range wl.Status.Components {
+						if component.Name == name {
+							return meta.FindStatusCondition(component.Conditions, "Ready")
+						}
+					}
+					return nil
+				}
+
+				It("reports whether each component is ready, naming the step that failed", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(*ready("source")).To(MatchFields(IgnoreExtras, Fields{
+						"Status": Equal(metav1.ConditionTrue),
+						"Reason": Equal("Ready"),
+					}))
+					Expect(*ready("image")).To(MatchFields(IgnoreExtras, Fields{
+						"Status":  Equal(metav1.ConditionFalse),
+						"Reason":  Equal("Unhealthy"),
+						"Message": Equal("build failed"),
+					}))
+					Expect(*ready("config")).To(MatchFields(IgnoreExtras, Fields{
+						"Status":  Equal(metav1.ConditionFalse),
+						"Reason":  Equal("TemplateStampFailure"),
+						"Message": Equal(workload.TemplateStampFailureCondition(stampError).Message),
+					}))
+					Expect(*ready("deployer")).To(MatchFields(IgnoreExtras, Fields{
+						"Status":  Equal(metav1.ConditionUnknown),
+						"Reason":  Equal("Blocked"),
+						"Message": Equal("blocked by component 'config'"),
+					}))
+					Expect(ready("source").LastTransitionTime.IsZero()).To(BeFalse())
+				})
+
+				It("records an event when the Ready condition of a component changes status", func() {
+					wl.Status.Components = []v1alpha1.ComponentStatus{{
+						Name:       "source",
+						State:      "Waiting",
+						Conditions: []metav1.Condition{{Type: "Ready", Status: metav1.ConditionUnknown, Reason: "Waiting"}},
+					}}
+
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(recorder.Events).To(Receive(Equal("Normal ConditionChanged component 'source': Ready changed from Unknown to True (Ready)")))
+				})
+
+				It("keeps the time of the last transition of a component that did not change", func() {
+					lastTransition := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+					wl.Status.Components = []v1alpha1.ComponentStatus{{
+						Name:       "source",
+						State:      "Realized",
+						Conditions: []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready", LastTransitionTime: lastTransition}},
+					}, {
+						Name:       "config",
+						State:      "Realized",
+						Conditions: []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready", LastTransitionTime: lastTransition}},
+					}}
+
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(ready("source").LastTransitionTime).To(Equal(lastTransition))
+					Expect(ready("config").LastTransitionTime).NotTo(Equal(lastTransition))
+				})
+			})
+
+			Context("and the components provide artifacts", func() {
+				var statuses []v1alpha1.ComponentStatus
+
+				BeforeEach(func() {
+					supplyChain.Spec.Components = []v1alpha1.SupplyChainComponent{{Name: "image"}}
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+					statuses = []v1alpha1.ComponentStatus{{
+						Name:    "image",
+						State:   "Realized",
+						Outputs: []v1alpha1.ComponentOutput{{Name: "image", Preview: `"registry.example.com/app"`}},
+					}}
+					rlzr.RealizeReturns(statuses, nil)
+				})
+
+				It("records the artifacts on the workload", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(wl.Status.Artifacts).To(Equal(&v1alpha1.WorkloadArtifacts{
+						Image: &v1alpha1.ImageArtifact{Component: "image", Image: "registry.example.com/app"},
+					}))
+				})
+
+				It("updates the status when only the artifacts have changed", func() {
+					wl.Status.ObservedGeneration = wl.Generation
+					wl.Status.Components = statuses
+					wl.Status.Progress = 100
+					conditionManager.FinalizeReturns(nil, false)
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("and the realizer judges the health of the components", func() {
+				healthy := func(name string, status metav1.ConditionStatus, message string) v1alpha1.ComponentStatus {
+					return v1alpha1.ComponentStatus{
+						Name:  name,
+						State: "Realized",
+						Conditions: []metav1.Condition{
+							{Type: "Healthy", Status: status, Reason: "SingleConditionType", Message: message},
+						},
+					}
+				}
+
+				It("reports the resources healthy when every component is", func() {
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{
+						healthy("source", metav1.ConditionTrue, ""),
+						healthy("image", metav1.ConditionTrue, ""),
+					}, nil)
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(conditionManager.AddPositiveArgsForCall(2)).To(Equal(metav1.Condition{
+						Type:   "ResourcesHealthy",
+						Status: metav1.ConditionTrue,
+						Reason: "AllHealthy",
+					}))
+				})
+
+				It("reports the resources unhealthy when any component is", func() {
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{
+						healthy("source", metav1.ConditionUnknown, ""),
+						healthy("image", metav1.ConditionFalse, "condition status: False"),
+					}, nil)
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(conditionManager.AddPositiveArgsForCall(2)).To(Equal(metav1.Condition{
+						Type:    "ResourcesHealthy",
+						Status:  metav1.ConditionFalse,
+						Reason:  "ResourceUnhealthy",
+						Message: "component 'image' is unhealthy: condition status: False",
+					}))
+				})
+
+				It("reports the health of the resources unknown when any component's is", func() {
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{
+						healthy("source", metav1.ConditionTrue, ""),
+						healthy("image", metav1.ConditionUnknown, ""),
+						{Name: "config", State: "Blocked", Message: "blocked by component 'image'"},
+					}, errors.New("realizing is hard"))
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(conditionManager.AddPositiveArgsForCall(2)).To(Equal(metav1.Condition{
+						Type:    "ResourcesHealthy",
+						Status:  metav1.ConditionUnknown,
+						Reason:  "ResourceHealthUnknown",
+						Message: "health of components 'image', 'config' is unknown",
+					}))
+				})
+			})
+
+			Context("and the realizer reports what it did for the components", func() {
+				var status v1alpha1.ComponentStatus
+
+				BeforeEach(func() {
+					status = v1alpha1.ComponentStatus{
+						Name:        "image",
+						State:       "Realized",
+						TemplateRef: &corev1.ObjectReference{APIVersion: "carto.run/v1alpha1", Kind: "ClusterImageTemplate", Name: "kpack"},
+						StampedRef:  &corev1.ObjectReference{APIVersion: "kpack.io/v1alpha1", Kind: "Image", Namespace: "my-namespace", Name: "petclinic"},
+						Outputs:     []v1alpha1.ComponentOutput{{Name: "image", Preview: `"registry/petclinic@sha256:1"`, Digest: "sha256:1"}},
+					}
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{status}, nil)
+				})
+
+				It("records events for the template resolved, the object stamped and the outputs provided", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(recorder.Events).To(Receive(Equal("Normal TemplateResolved component 'image' uses ClusterImageTemplate 'kpack'")))
+					Expect(recorder.Events).To(Receive(Equal("Normal ObjectStamped stamped Image 'my-namespace/petclinic' for component 'image'")))
+					Expect(recorder.Events).To(Receive(Equal("Normal OutputsUpdated component 'image' provides new outputs: image")))
+				})
+
+				It("watches the kind of the object stamped", func() {
+					tracker := &controllerfakes.FakeDynamicTracker{}
+					reconciler.AddTracking(tracker)
+
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(tracker.WatchCallCount()).To(Equal(1))
+					_, obj, _ := tracker.WatchArgsForCall(0)
+					Expect(obj.GetObjectKind().GroupVersionKind()).To(Equal(schema.GroupVersionKind{Group: "kpack.io", Version: "v1alpha1", Kind: "Image"}))
+				})
+
+				It("records no events when nothing changed since the previous realization", func() {
+					wl.Status.Components = []v1alpha1.ComponentStatus{status}
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(recorder.Events).To(BeEmpty())
+				})
+
+				It("records an event when the outputs change", func() {
+					previous := status
+					previous.Outputs = []v1alpha1.ComponentOutput{{Name: "image", Preview: `"registry/petclinic@sha256:0"`, Digest: "sha256:0"}}
+					wl.Status.Components = []v1alpha1.ComponentStatus{previous}
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(recorder.Events).To(Receive(Equal("Normal OutputsUpdated component 'image' provides new outputs: image")))
+					Expect(recorder.Events).To(BeEmpty())
+				})
+
+				It("realizes the components along with their previous statuses", func() {
+					wl.Status.Components = []v1alpha1.ComponentStatus{status}
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					_, _, _, previous := rlzr.RealizeArgsForCall(0)
+					Expect(previous).To(Equal([]v1alpha1.ComponentStatus{status}))
+				})
+
+				It("records a warning once the object of a component starts flapping", func() {
+					wl.Status.Components = []v1alpha1.ComponentStatus{status}
+					flapping := status
+					flapping.Conditions = []metav1.Condition{{
+						Type:    "HealthFlapping",
+						Status:  metav1.ConditionTrue,
+						Reason:  "FrequentChanges",
+						Message: "health changed 4 times in the last 10m0s",
+					}}
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{flapping}, nil)
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(recorder.Events).To(Receive(Equal("Warning HealthFlapping the object of component 'image' is flapping: health changed 4 times in the last 10m0s")))
+
+					wl.Status.Components = []v1alpha1.ComponentStatus{flapping}
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(recorder.Events).NotTo(Receive(ContainSubstring("HealthFlapping")))
+				})
+			})
+
+			Context("and the supply chain no longer describes objects stamped before", func() {
+				var objects map[string][]*unstructured.Unstructured
+
+				stampedRef := func(kind, name string) *corev1.ObjectReference {
+					return &corev1.ObjectReference{APIVersion: "v1", Kind: kind, Namespace: "my-namespace", Name: name}
+				}
+
+				stampedObject := func(kind, name string, owned bool) *unstructured.Unstructured {
+					obj := &unstructured.Unstructured{}
+					obj.SetAPIVersion("v1")
+					obj.SetKind(kind)
+					obj.SetNamespace("my-namespace")
+					obj.SetName(name)
+					if owned {
+						obj.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Workload", Name: wl.Name, UID: wl.UID}})
+					}
+					return obj
+				}
+
+				deletedNames := func() []string {
+					var names []string
+					for i := 0; i < repo.DeleteCallCount(); i++ {
+						obj := repo.DeleteArgsForCall(i)
+						names = append(names, obj.GetKind()+"/"+obj.GetName())
+					}
+					return names
+				}
+
+				events := func() []string {
+					var events []string
+					for len(recorder.Events) > 0 {
+						events = append(events, <-recorder.Events)
+					}
+					return events
+				}
+
+				BeforeEach(func() {
+					wl.UID = "workload-uid"
+					wl.Namespace = "my-namespace"
+					wl.Status.Components = []v1alpha1.ComponentStatus{
+						{Name: "source", State: "Realized", StampedRef: stampedRef("ConfigMap", "source")},
+						{Name: "config", State: "Realized", StampedRef: stampedRef("ConfigMap", "config")},
+						{Name: "tests", State: "Realized", StampedRef: stampedRef("ConfigMap", "tests")},
+					}
+
+					supplyChain.Spec.Components = []v1alpha1.SupplyChainComponent{{Name: "source"}, {Name: "config"}}
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{
+						{Name: "source", State: "Realized", StampedRef: stampedRef("ConfigMap", "source")},
+						{Name: "config", State: "Realized", StampedRef: stampedRef("Secret", "config")},
+					}, nil)
+
+					objects = map[string][]*unstructured.Unstructured{
+						"ConfigMap/source": {stampedObject("ConfigMap", "source", true)},
+						"ConfigMap/config": {stampedObject("ConfigMap", "config", true)},
+						"Secret/config":    {stampedObject("Secret", "config", true)},
+						"ConfigMap/tests":  {stampedObject("ConfigMap", "tests", true)},
+					}
+					repo.ListStampedObjectsStub = func(gvk schema.GroupVersionKind, _ string, _ types.UID, resourceName string) ([]*unstructured.Unstructured, error) {
+						return objects[gvk.Kind+"/"+resourceName], nil
+					}
+				})
+
+				It("deletes the objects of removed components and those of the kind a template no longer stamps", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(deletedNames()).To(ConsistOf("ConfigMap/config", "ConfigMap/tests"))
+					Expect(events()).To(ContainElements(
+						"Normal OrphanDeleted deleted ConfigMap 'my-namespace/config' no longer stamped for component 'config'",
+						"Normal OrphanDeleted deleted ConfigMap 'my-namespace/tests' no longer stamped for component 'tests'",
+					))
+
+					gvk, namespace, owner, resourceName := repo.ListStampedObjectsArgsForCall(1)
+					Expect(gvk).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}))
+					Expect(namespace).To(Equal("my-namespace"))
+					Expect(owner).To(BeEquivalentTo("workload-uid"))
+					Expect(resourceName).To(Equal("tests"))
+				})
+
+				It("drops the removed components from the status", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(wl.Status.Components).To(HaveLen(2))
+					Expect(wl.Status.Components[1].StampedRef).To(Equal(stampedRef("Secret", "config")))
+				})
+
+				It("deletes the object left behind when a template stamps another name", func() {
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{
+						{Name: "source", State: "Realized", StampedRef: stampedRef("ConfigMap", "source-v2")},
+						{Name: "config", State: "Realized", StampedRef: stampedRef("ConfigMap", "config")},
+					}, nil)
+					objects["ConfigMap/source"] = append(objects["ConfigMap/source"], stampedObject("ConfigMap", "source-v2", true))
+
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(deletedNames()).To(ConsistOf("ConfigMap/source", "ConfigMap/tests"))
+				})
+
+				It("does not look for orphans when nothing changed", func() {
+					wl.Status.Components = wl.Status.Components[:1]
+
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(repo.ListStampedObjectsCallCount()).To(Equal(0))
+					Expect(repo.DeleteCallCount()).To(Equal(0))
+				})
+
+				It("keeps and reports the orphans the workload does not own", func() {
+					objects["ConfigMap/tests"] = []*unstructured.Unstructured{stampedObject("ConfigMap", "tests", false)}
+
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(deletedNames()).To(ConsistOf("ConfigMap/config"))
+					Expect(events()).To(ContainElements(
+						"Normal OrphanDeleted deleted ConfigMap 'my-namespace/config' no longer stamped for component 'config'",
+						"Warning OrphanKept kept ConfigMap 'my-namespace/tests' no longer stamped for component 'tests', as the workload does not own it",
+					))
+				})
+
+				It("keeps a removed component in the status until its objects are deleted", func() {
+					repo.DeleteReturns(errors.New("some error"))
+
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(wl.Status.Components).To(HaveLen(3))
+					Expect(wl.Status.Components[2].Name).To(Equal("tests"))
+					Expect(events()).To(ContainElements(
+						"Warning OrphanDeletionFailed delete objects no longer stamped for component 'config': delete ConfigMap 'my-namespace/config': some error",
+						"Warning OrphanDeletionFailed delete objects no longer stamped for component 'tests': delete ConfigMap 'my-namespace/tests': some error",
+					))
+				})
+			})
+
+			Context("and the supply chain realizes every component", func() {
+				BeforeEach(func() {
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{
+						{Name: "source", State: "Realized"},
+						{Name: "image", State: "Realized"},
+					}, nil)
+				})
+
+				It("records when the generation started and finished realizing", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(wl.Status.RealizationStartTime).NotTo(BeNil())
+					Expect(wl.Status.RealizationCompletionTime).NotTo(BeNil())
+					Expect(wl.Status.RealizationCompletionTime.Time).NotTo(BeTemporally("<", wl.Status.RealizationStartTime.Time))
+				})
+
+				It("keeps the completion time of a generation realized earlier", func() {
+					completed := metav1.NewTime(time.Now().Add(-time.Hour))
+					wl.Status.ObservedGeneration = wl.Generation
+					wl.Status.RealizationStartTime = &completed
+					wl.Status.RealizationCompletionTime = &completed
+
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(wl.Status.RealizationCompletionTime).To(Equal(&completed))
+				})
+
+				It("does not report a deadline without a maxDuration", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(conditionManager.AddNegativeCallCount()).To(Equal(0))
+				})
+
+				Context("within the workload's maxDuration", func() {
+					BeforeEach(func() {
+						wl.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+					})
+
+					It("reports the deadline was met", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddNegativeArgsForCall(0)).To(Equal(workload.RealizationWithinDeadlineCondition()))
+					})
+				})
+
+				Context("after the workload's maxDuration", func() {
+					BeforeEach(func() {
+						started := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+						wl.Status.ObservedGeneration = wl.Generation
+						wl.Status.RealizationStartTime = &started
+						wl.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+					})
+
+					It("reports the workload was realized late", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddNegativeArgsForCall(0)).To(Equal(workload.RealizedAfterDeadlineCondition(time.Hour)))
+					})
+
+					It("updates the status with the completion time", func() {
+						conditionManager.FinalizeReturns(nil, false)
+						wl.Status.Components = []v1alpha1.ComponentStatus{
+							{Name: "source", State: "Realized"},
+							{Name: "image", State: "Realized"},
+						}
+
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+					})
+				})
+			})
+
+			Context("and the supply chain is not realized after the workload's maxDuration", func() {
+				BeforeEach(func() {
+					started := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+					wl.Status.ObservedGeneration = wl.Generation
+					wl.Status.RealizationStartTime = &started
+					wl.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+					rlzr.RealizeReturns([]v1alpha1.ComponentStatus{
+						{Name: "source", State: "Realized"},
+						{Name: "image", State: "Waiting"},
+					}, nil)
+				})
+
+				It("reports the deadline was exceeded", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(conditionManager.AddNegativeArgsForCall(0)).To(Equal(workload.RealizationDeadlineExceededCondition(time.Hour)))
+					Expect(wl.Status.RealizationCompletionTime).To(BeNil())
+				})
+			})
+
+			Context("and the workload is paused", func() {
+				var (
+					components       []v1alpha1.ComponentStatus
+					submitted        metav1.Condition
+					deadlineExceeded metav1.Condition
+				)
+
+				BeforeEach(func() {
+					wl.Spec.Paused = true
+					components = []v1alpha1.ComponentStatus{{Name: "source", State: "Realized"}}
+					submitted = workload.TemplateStampFailureCondition(errors.New("some error"))
+					deadlineExceeded = workload.RealizationDeadlineExceededCondition(time.Hour)
+					wl.Status.Components = components
+					wl.Status.Conditions = []metav1.Condition{
+						{Type: "Ready", Status: "False", Reason: submitted.Reason},
+						submitted,
+						deadlineExceeded,
+					}
+				})
+
+				It("does not realize the supply chain", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(rlzr.RealizeCallCount()).To(Equal(0))
+					Expect(repo.GetSupplyChainsForWorkloadCallCount()).To(Equal(0))
+				})
+
+				It("leaves the stamped objects as they are", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					Expect(repo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+					Expect(repo.DeleteCallCount()).To(Equal(0))
+				})
+
+				It("reports the workload is paused, keeping the conditions of its last realization", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(conditionManager.AddPositiveCallCount()).To(Equal(1))
+					Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(submitted))
+					Expect(conditionManager.AddNegativeCallCount()).To(Equal(2))
+					Expect(conditionManager.AddNegativeArgsForCall(0)).To(Equal(deadlineExceeded))
+					Expect(conditionManager.AddNegativeArgsForCall(1)).To(Equal(workload.WorkloadPausedCondition()))
+				})
+
+				It("keeps the component statuses and observes the generation", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+					updated := repo.StatusUpdateArgsForCall(0).(*v1alpha1.Workload)
+					Expect(updated.Status.Components).To(Equal(components))
+					Expect(updated.Status.ObservedGeneration).To(BeEquivalentTo(1))
+					Expect(updated.Status.RealizationStartTime).To(BeNil())
+				})
+
+				It("does not requeue", func() {
+					result, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(Equal(ctrl.Result{}))
+				})
+
+				Context("but the status cannot be updated", func() {
+					BeforeEach(func() {
+						repo.StatusUpdateReturns(errors.New("some error"))
+					})
+
+					It("returns an error", func() {
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err).To(MatchError("update workload status: some error"))
+					})
+				})
+			})
+
+			Context("but getting the object GVK fails", func() {
+				BeforeEach(func() {
+					repo.GetSchemeReturns(runtime.NewScheme())
+				})
+				It("returns a helpful error", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+
+					Expect(err.Error()).To(ContainSubstring("get object gvk: "))
+				})
+			})
+
+			Context("but the supply chain is not in a ready state", func() {
+				BeforeEach(func() {
+					supplyChain.Status.Conditions = []metav1.Condition{
+						{
+							Type:    "Ready",
+							Status:  "False",
+							Reason:  "SomeReason",
+							Message: "some informative message",
+						},
+					}
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+				})
+				It("returns a helpful error", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+
+					Expect(err.Error()).To(ContainSubstring("supply-chain is not in ready condition"))
+				})
+
+				It("calls the condition manager to report supply chain not ready", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+					expectedCondition := metav1.Condition{
+						Type:               v1alpha1.WorkloadSupplyChainReady,
+						Status:             metav1.ConditionFalse,
+						ObservedGeneration: 0,
+						LastTransitionTime: metav1.Time{},
+						Reason:             "SomeReason",
+						Message:            "some informative message",
+					}
+					Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workload.MissingReadyInSupplyChainCondition(expectedCondition)))
+				})
+			})
+
+			Context("and the supply chain extends another", func() {
+				BeforeEach(func() {
+					supplyChain.Spec.Extends = &v1alpha1.SupplyChainExtension{Name: "base-supply-chain"}
+					supplyChain.Spec.Components = []v1alpha1.SupplyChainComponent{
+						{Name: "image", TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterImageTemplate", Name: "team-image"}},
+					}
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+					repo.GetSupplyChainReturns(&v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{Name: "base-supply-chain"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{Name: "source", TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterSourceTemplate", Name: "golden-source"}},
+								{Name: "image", TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterImageTemplate", Name: "golden-image"}},
+							},
+						},
+					}, nil)
+				})
+
+				It("realizes the components of the resolved supply chain", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(repo.GetSupplyChainArgsForCall(0)).To(Equal("base-supply-chain"))
+					_, _, realizedSupplyChain, _ := rlzr.RealizeArgsForCall(0)
+					Expect(realizedSupplyChain.Name).To(Equal(supplyChainName))
+					Expect(realizedSupplyChain.Spec.Components).To(HaveLen(2))
+					Expect(realizedSupplyChain.Spec.Components[1].TemplateRef.Name).To(Equal("team-image"))
+				})
+
+				Context("but the base supply chain cannot be got", func() {
+					BeforeEach(func() {
+						repo.GetSupplyChainReturns(nil, errors.New("some error"))
+					})
+
+					It("calls the condition manager to report the extension is invalid", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						condition := conditionManager.AddPositiveArgsForCall(0)
+						Expect(condition.Reason).To(Equal(v1alpha1.InvalidExtensionSupplyChainReason))
+						Expect(condition.Message).To(Equal("get base clustersupplychain 'base-supply-chain': some error"))
+					})
+
+					It("does not realize the supply chain", func() {
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err).To(MatchError(ContainSubstring("resolve supply chain")))
+						Expect(rlzr.RealizeCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("but the realizer returns an error", func() {
+				Context("of type GetClusterTemplateError", func() {
+					var templateError error
+					BeforeEach(func() {
+						templateError = realizer.GetClusterTemplateError{
+							Err: errors.New("some error"),
+						}
+						rlzr.RealizeReturns(nil, templateError)
+					})
+
+					It("calls the condition manager to report", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.TemplateObjectRetrievalFailureCondition(templateError)))
+					})
+
+					It("reports a template kind that is not installed", func() {
+						templateError = realizer.GetClusterTemplateError{
+							Err: fmt.Errorf("get: %w", &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "carto.run", Kind: "ClusterTemplate"}}),
+						}
+						rlzr.RealizeReturns(nil, templateError)
+
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.MissingAPIDependencyCondition(templateError)))
+					})
+
+					It("returns the error", func() {
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err.Error()).To(ContainSubstring(templateError.Error()))
+					})
+				})
+
+				Context("of type KindNotAllowedError", func() {
+					It("calls the condition manager to report", func() {
+						notAllowed := realizer.KindNotAllowedError{
+							Err:       errors.New("kind ClusterRoleBinding.rbac.authorization.k8s.io may not be stamped in namespace 'dev'"),
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-name"},
+						}
+						rlzr.RealizeReturns(nil, notAllowed)
+
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.KindNotAllowedCondition(notAllowed)))
+					})
+				})
+
+				Context("of type GitWriteError", func() {
+					It("calls the condition manager to report", func() {
+						writeError := realizer.GitWriteError{
+							Err:       errors.New("git push: rejected"),
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-name"},
+						}
+						rlzr.RealizeReturns(nil, writeError)
+
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err).To(MatchError(ContainSubstring("git push: rejected")))
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.GitWriteFailureCondition(writeError)))
+					})
+				})
+
+				Context("of type PublishArtifactError", func() {
+					It("calls the condition manager to report", func() {
+						publishError := realizer.PublishArtifactError{
+							Err:       errors.New("push manifest: 403 Forbidden"),
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-name"},
+						}
+						rlzr.RealizeReturns(nil, publishError)
+
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err).To(MatchError(ContainSubstring("403 Forbidden")))
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.ArtifactPublishFailureCondition(publishError)))
+					})
+				})
+
+				Context("of type StampError", func() {
+					var stampError realizer.StampError
+					BeforeEach(func() {
+						stampError = realizer.StampError{
+							Err:       errors.New("some error"),
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-name"},
+						}
+						rlzr.RealizeReturns(nil, stampError)
+					})
+
+					It("calls the condition manager to report", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.TemplateStampFailureCondition(stampError)))
+					})
+
+					It("returns the error", func() {
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err.Error()).To(ContainSubstring(stampError.Error()))
+					})
+
+					It("records a warning event", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(recorder.Events).To(Receive(Equal(
+							"Warning TemplateStampFailure unable to stamp object for component 'some-name': some error",
+						)))
+					})
+
+					It("counts the failure by its reason", func() {
+						workload.RealizationFailuresTotal.Reset()
+						_, _ = reconciler.Reconcile(ctx, req)
+
+						Expect(testutil.ToFloat64(workload.RealizationFailuresTotal.WithLabelValues("", supplyChainName, "TemplateStampFailure"))).To(Equal(1.0))
+					})
+
+					Context("and the template names its maintainers", func() {
+						BeforeEach(func() {
+							stampError.TemplateMetadata = &v1alpha1.TemplateMetadata{
+								Maintainers: []string{"build-team <builds@example.com>"},
+								DocsURL:     "https://example.com/templates/kpack",
+							}
+							rlzr.RealizeReturns(nil, stampError)
+						})
+
+						It("names them in the condition", func() {
+							_, _ = reconciler.Reconcile(ctx, req)
+							Expect(conditionManager.AddPositiveArgsForCall(1).Message).To(Equal(
+								"unable to stamp object for component 'some-name': some error (template maintained by build-team <builds@example.com>, docs at https://example.com/templates/kpack)",
+							))
+						})
+
+						It("records a warning event naming them", func() {
+							_, _ = reconciler.Reconcile(ctx, req)
+							Expect(recorder.Events).To(Receive(Equal(
+								"Warning TemplateStampFailure unable to stamp object for component 'some-name': some error (template maintained by build-team <builds@example.com>, docs at https://example.com/templates/kpack)",
+							)))
+						})
+					})
+				})
+
+				Context("that it returned the previous time", func() {
+					var stampError realizer.StampError
+
+					BeforeEach(func() {
+						stampError = realizer.StampError{
+							Err:       errors.New("some error"),
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-name"},
+						}
+						rlzr.RealizeReturns(nil, stampError)
+						wl.Status.Retry = &v1alpha1.RealizationRetry{
+							Failures: 1,
+							Reason:   "TemplateStampFailure",
+							Message:  stampError.Error(),
+						}
+					})
+
+					It("backs off instead of returning the error, reporting when it retries", func() {
+						result, err := reconciler.Reconcile(ctx, req)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(Equal(ctrl.Result{RequeueAfter: 10 * time.Second}))
+
+						Expect(wl.Status.Retry.Failures).To(BeEquivalentTo(2))
+						Expect(wl.Status.Retry.NextRetryTime.Time).To(BeTemporally("~", time.Now().Add(10*time.Second), time.Second))
+						Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+					})
+
+					It("doubles the delay with each failure, up to a cap", func() {
+						wl.Status.Retry.Failures = 3
+						result, _ := reconciler.Reconcile(ctx, req)
+						Expect(result).To(Equal(ctrl.Result{RequeueAfter: 40 * time.Second}))
+
+						wl.Status.Retry.Failures = 20
+						result, _ = reconciler.Reconcile(ctx, req)
+						Expect(result).To(Equal(ctrl.Result{RequeueAfter: workload.MaxRetryBackoff}))
+					})
+
+					It("returns the error when the realization fails another way", func() {
+						wl.Status.Retry.Message = "another error"
+
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err).To(MatchError(stampError))
+						Expect(wl.Status.Retry).To(Equal(&v1alpha1.RealizationRetry{
+							Failures: 1,
+							Reason:   "TemplateStampFailure",
+							Message:  stampError.Error(),
+						}))
+					})
+
+					It("forgets the failures once the realization succeeds", func() {
+						rlzr.RealizeReturns(nil, nil)
+
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(wl.Status.Retry).To(BeNil())
+					})
+				})
+
+				Context("of type ParamsError", func() {
+					var paramsError realizer.ParamsError
+					BeforeEach(func() {
+						paramsError = realizer.ParamsError{
+							Err:       templates.ImmutableParamError{Name: "some-param"},
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-name"},
+						}
+						rlzr.RealizeReturns(nil, paramsError)
+					})
+
+					It("calls the condition manager to report", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.ImmutableParamOverriddenCondition(paramsError)))
+					})
+
+					It("returns the error", func() {
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err.Error()).To(ContainSubstring("param 'some-param' is immutable"))
+					})
+				})
+
+				Context("of type InterceptError", func() {
+					var interceptError realizer.InterceptError
+					BeforeEach(func() {
+						interceptError = realizer.InterceptError{
+							Err:       errors.New("some error"),
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-name"},
+						}
+						rlzr.RealizeReturns(nil, interceptError)
+					})
+
+					It("calls the condition manager to report", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.InterceptorFailureCondition(interceptError)))
+					})
+
+					It("returns the error", func() {
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err.Error()).To(ContainSubstring(interceptError.Error()))
+					})
+				})
+
+				Context("of type ResolveArtifactError", func() {
+					var resolveError realizer.ResolveArtifactError
 					BeforeEach(func() {
-						templateError = realizer.GetClusterTemplateError{
-							Err: errors.New("some error"),
+						resolveError = realizer.ResolveArtifactError{
+							Err:       errors.New("some error"),
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-name"},
 						}
-						rlzr.RealizeReturns(templateError)
+						rlzr.RealizeReturns(nil, resolveError)
 					})
 
 					It("calls the condition manager to report", func() {
 						_, _ = reconciler.Reconcile(ctx, req)
-						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.TemplateObjectRetrievalFailureCondition(templateError)))
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.ArtifactResolutionFailureCondition(resolveError)))
 					})
 
 					It("returns the error", func() {
 						_, err := reconciler.Reconcile(ctx, req)
-						Expect(err.Error()).To(ContainSubstring(templateError.Error()))
+						Expect(err.Error()).To(ContainSubstring(resolveError.Error()))
 					})
 				})
 
-				Context("of type StampError", func() {
-					var stampError realizer.StampError
+				Context("of type TemplateOptionError", func() {
+					var optionError realizer.TemplateOptionError
 					BeforeEach(func() {
-						stampError = realizer.StampError{
-							Err:       errors.New("some error"),
+						optionError = realizer.TemplateOptionError{
 							Component: &v1alpha1.SupplyChainComponent{Name: "some-name"},
 						}
-						rlzr.RealizeReturns(stampError)
+						rlzr.RealizeReturns(nil, optionError)
 					})
 
-					It("calls the condition manager to report", func() {
+					It("reports that no option matches", func() {
 						_, _ = reconciler.Reconcile(ctx, req)
-						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.TemplateStampFailureCondition(stampError)))
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.NoMatchingTemplateOptionCondition(optionError)))
 					})
 
 					It("returns the error", func() {
 						_, err := reconciler.Reconcile(ctx, req)
-						Expect(err.Error()).To(ContainSubstring(stampError.Error()))
+						Expect(err.Error()).To(ContainSubstring(optionError.Error()))
+					})
+
+					Context("when several options match", func() {
+						BeforeEach(func() {
+							optionError.Matched = []string{"some-option", "another-option"}
+							rlzr.RealizeReturns(nil, optionError)
+						})
+
+						It("reports the ambiguity", func() {
+							_, _ = reconciler.Reconcile(ctx, req)
+							Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.AmbiguousTemplateOptionsCondition(optionError)))
+						})
 					})
 				})
 
@@ -310,7 +1653,7 @@ var _ = Describe("Reconciler", func() {
 							Err:           errors.New("some error"),
 							StampedObject: &unstructured.Unstructured{},
 						}
-						rlzr.RealizeReturns(stampedObjectError)
+						rlzr.RealizeReturns(nil, stampedObjectError)
 					})
 
 					It("calls the condition manager to report", func() {
@@ -318,12 +1661,140 @@ var _ = Describe("Reconciler", func() {
 						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.TemplateRejectedByAPIServerCondition(stampedObjectError)))
 					})
 
+					DescribeTable("when the controller is forbidden a verb",
+						func(verb string, expectedCondition func(error) metav1.Condition) {
+							stampedObjectError.Err = repository.ObjectError{
+								Verb: verb,
+								Err:  kerrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "some-name", errors.New("no rbac")),
+							}
+							rlzr.RealizeReturns(nil, stampedObjectError)
+
+							_, _ = reconciler.Reconcile(ctx, req)
+							Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(expectedCondition(stampedObjectError)))
+						},
+						Entry("list", repository.ListVerb, workload.CannotListCreatedObjectsCondition),
+						Entry("create", repository.CreateVerb, workload.CannotCreateObjectCondition),
+						Entry("patch", repository.PatchVerb, workload.CannotPatchObjectCondition),
+					)
+
+					It("reports a verb an access review denied", func() {
+						stampedObjectError.Err = repository.ObjectError{
+							Verb:   repository.CreateVerb,
+							Denied: true,
+							Err:    errors.New("permission denied: cannot create deployments.apps"),
+						}
+						rlzr.RealizeReturns(nil, stampedObjectError)
+
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.PermissionDeniedCondition(stampedObjectError)))
+					})
+
+					It("reports a rejection by a dry run", func() {
+						stampedObjectError.Err = repository.ObjectError{
+							Verb:   repository.PatchVerb,
+							DryRun: true,
+							Err:    kerrors.NewBadRequest("spec.foo: Invalid value"),
+						}
+						rlzr.RealizeReturns(nil, stampedObjectError)
+
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.TemplateRejectedByDryRunCondition(stampedObjectError)))
+					})
+
+					It("reports an object that does not conform to its schema", func() {
+						stampedObjectError.Err = repository.ObjectError{
+							Verb: repository.ValidateVerb,
+							Err:  errors.New(`invalid Job: unknown field "colour"`),
+						}
+						rlzr.RealizeReturns(nil, stampedObjectError)
+
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.InvalidStampedObjectCondition(stampedObjectError)))
+					})
+
+					It("reports a kind that is not installed, e.g. before Tekton is", func() {
+						stampedObjectError.Err = repository.ObjectError{
+							Verb: repository.PatchVerb,
+							Err:  &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "tekton.dev", Kind: "TaskRun"}, SearchedVersions: []string{"v1beta1"}},
+						}
+						rlzr.RealizeReturns(nil, stampedObjectError)
+
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.MissingAPIDependencyCondition(stampedObjectError)))
+					})
+
 					It("returns the error", func() {
 						_, err := reconciler.Reconcile(ctx, req)
 						Expect(err.Error()).To(ContainSubstring(stampedObjectError.Error()))
 					})
 				})
 
+				Context("of type HookError", func() {
+					var hookError realizer.HookError
+					BeforeEach(func() {
+						hookError = realizer.HookError{
+							Err:       errors.New("some error"),
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-component"},
+							Hook:      "migrate",
+						}
+						rlzr.RealizeReturns(nil, hookError)
+					})
+
+					It("calls the condition manager to report", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.HookFailureCondition(hookError)))
+					})
+
+					It("returns the error", func() {
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err).To(MatchError(hookError))
+					})
+				})
+
+				Context("of type PendingHookError", func() {
+					var pendingError realizer.PendingHookError
+					BeforeEach(func() {
+						pendingError = realizer.PendingHookError{
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-component"},
+							Hook:      "migrate",
+						}
+						rlzr.RealizeReturns(nil, pendingError)
+					})
+
+					It("calls the condition manager to report", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.PreHookPendingCondition(pendingError)))
+					})
+
+					It("does not return an error", func() {
+						result, err := reconciler.Reconcile(ctx, req)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
+					})
+				})
+
+				Context("of type ReadinessGateError", func() {
+					var gateError realizer.ReadinessGateError
+					BeforeEach(func() {
+						gateError = realizer.ReadinessGateError{
+							Component: &v1alpha1.SupplyChainComponent{Name: "some-component"},
+							Health:    metav1.Condition{Type: "Healthy", Status: metav1.ConditionFalse, Message: "condition status: False"},
+						}
+						rlzr.RealizeReturns(nil, gateError)
+					})
+
+					It("calls the condition manager to report", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+						Expect(conditionManager.AddPositiveArgsForCall(1)).To(Equal(workload.ReadinessGatePendingCondition(gateError)))
+					})
+
+					It("does not return an error", func() {
+						result, err := reconciler.Reconcile(ctx, req)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
+					})
+				})
+
 				Context("of type RetrieveOutputError", func() {
 					var retrieveError realizer.RetrieveOutputError
 					BeforeEach(func() {
@@ -331,7 +1802,7 @@ var _ = Describe("Reconciler", func() {
 						retrieveError = realizer.NewRetrieveOutputError(
 							&v1alpha1.SupplyChainComponent{Name: "some-component"},
 							&jsonPathError)
-						rlzr.RealizeReturns(retrieveError)
+						rlzr.RealizeReturns(nil, retrieveError)
 					})
 
 					It("calls the condition manager to report", func() {
@@ -344,13 +1815,20 @@ var _ = Describe("Reconciler", func() {
 						Expect(err).NotTo(HaveOccurred())
 						Expect(result).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
 					})
+
+					It("counts the output that could not be read", func() {
+						workload.OutputExtractionFailuresTotal.Reset()
+						_, _ = reconciler.Reconcile(ctx, req)
+
+						Expect(testutil.ToFloat64(workload.OutputExtractionFailuresTotal.WithLabelValues("", supplyChainName, "some-component"))).To(Equal(1.0))
+					})
 				})
 
 				Context("of unknown type", func() {
 					var realizerError error
 					BeforeEach(func() {
 						realizerError = errors.New("some error")
-						rlzr.RealizeReturns(realizerError)
+						rlzr.RealizeReturns(nil, realizerError)
 					})
 
 					It("calls the condition manager to report", func() {
@@ -433,6 +1911,49 @@ var _ = Describe("Reconciler", func() {
 				_, err := reconciler.Reconcile(ctx, req)
 				Expect(err.Error()).To(ContainSubstring("no supply chain found where full selector is satisfied by labels: "))
 			})
+
+			Context("but there is a default supply chain", func() {
+				BeforeEach(func() {
+					ready := []metav1.Condition{{Type: "Ready", Status: "True", Reason: "Ready"}}
+					repo.GetDefaultSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{{
+						ObjectMeta: metav1.ObjectMeta{Name: "fallback"},
+						Spec:       v1alpha1.SupplyChainSpec{Default: true},
+						Status:     v1alpha1.SupplyChainStatus{Conditions: ready},
+					}}, nil)
+				})
+
+				It("uses the default supply chain and says so", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workload.DefaultSupplyChainUsedCondition("fallback")))
+					_, _, supplyChain, _ := rlzr.RealizeArgsForCall(0)
+					Expect(supplyChain.Name).To(Equal("fallback"))
+				})
+
+				Context("and the workload has no labels", func() {
+					BeforeEach(func() {
+						wl.Labels = nil
+					})
+
+					It("uses the default supply chain", func() {
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workload.DefaultSupplyChainUsedCondition("fallback")))
+					})
+				})
+			})
+
+			Context("and the repo fails to get the default supply chains", func() {
+				BeforeEach(func() {
+					repo.GetDefaultSupplyChainsForWorkloadReturns(nil, errors.New("some error"))
+				})
+
+				It("returns a helpful error", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).To(MatchError(ContainSubstring("get supply chain by label: some error")))
+				})
+			})
 		})
 
 		Context("and repo returns an an error when requesting supply chains", func() {
@@ -465,6 +1986,40 @@ var _ = Describe("Reconciler", func() {
 				_, err := reconciler.Reconcile(ctx, req)
 				Expect(err.Error()).To(ContainSubstring("too many supply chains match the workload selector"))
 			})
+
+			Context("of which one has a higher priority", func() {
+				BeforeEach(func() {
+					ready := []metav1.Condition{{Type: "Ready", Status: "True", Reason: "Ready"}}
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "web"},
+							Status:     v1alpha1.SupplyChainStatus{Conditions: ready},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{Name: "golden"},
+							Spec:       v1alpha1.SupplyChainSpec{Priority: 10},
+							Status:     v1alpha1.SupplyChainStatus{Conditions: ready},
+						},
+					}, nil)
+				})
+
+				It("uses the supply chain of higher priority", func() {
+					_, err := reconciler.Reconcile(ctx, req)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workload.SupplyChainReadyCondition()))
+					Expect(rlzr.RealizeCallCount()).To(Equal(1))
+					_, _, supplyChain, _ := rlzr.RealizeArgsForCall(0)
+					Expect(supplyChain.Name).To(Equal("golden"))
+				})
+
+				It("records why the supply chain was chosen", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					updatedWorkload := repo.StatusUpdateArgsForCall(0).(*v1alpha1.Workload)
+					Expect(updatedWorkload.Status.SupplyChainRef.Name).To(Equal("golden"))
+					Expect(updatedWorkload.Status.SupplyChainSelection).To(Equal("supply chain 'golden' chosen over 'web' for its higher priority (10)"))
+				})
+			})
 		})
 
 		Context("but status update fails", func() {
@@ -520,6 +2075,376 @@ var _ = Describe("Reconciler", func() {
 			})
 		})
 
+		Context("without an ordered teardown", func() {
+			It("holds the deletion of the workload with the teardown finalizer", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(repo.UpdateCallCount()).To(Equal(1))
+				Expect(repo.UpdateArgsForCall(0).GetFinalizers()).To(ConsistOf("carto.run/teardown"))
+			})
+
+			It("does not update a workload that already has the finalizer", func() {
+				wl.Finalizers = []string{"carto.run/teardown"}
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(repo.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("with an ordered teardown", func() {
+			BeforeEach(func() {
+				wl.Spec.Teardown = &v1alpha1.WorkloadTeardown{Ordered: true}
+			})
+
+			It("holds the deletion of the workload with a finalizer", func() {
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(repo.UpdateCallCount()).To(Equal(1))
+				Expect(repo.UpdateArgsForCall(0).GetFinalizers()).To(ConsistOf("carto.run/ordered-teardown"))
+			})
+
+			It("does not update a workload that already has the finalizer", func() {
+				wl.Finalizers = []string{"carto.run/ordered-teardown"}
+				_, _ = reconciler.Reconcile(ctx, req)
+
+				Expect(repo.UpdateCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the finalizer cannot be added", func() {
+				repo.UpdateReturns(errors.New("some error"))
+				_, err := reconciler.Reconcile(ctx, req)
+
+				Expect(err).To(MatchError("update workload finalizers: some error"))
+				Expect(rlzr.RealizeCallCount()).To(Equal(0))
+			})
+
+			Context("that is no longer asked for", func() {
+				BeforeEach(func() {
+					wl.Spec.Teardown = nil
+					wl.Finalizers = []string{"carto.run/ordered-teardown", "some-other-finalizer"}
+				})
+
+				It("swaps the finalizer for the teardown finalizer", func() {
+					_, _ = reconciler.Reconcile(ctx, req)
+
+					Expect(repo.UpdateCallCount()).To(Equal(1))
+					Expect(repo.UpdateArgsForCall(0).GetFinalizers()).To(ConsistOf("some-other-finalizer", "carto.run/teardown"))
+				})
+			})
+
+			Context("and a realized supply chain", func() {
+				var remaining map[string]int
+
+				stampedRef := func(name string) *corev1.ObjectReference {
+					return &corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "my-namespace", Name: name}
+				}
+
+				BeforeEach(func() {
+					wl.UID = "workload-uid"
+					wl.Namespace = "my-namespace"
+					wl.Finalizers = []string{"carto.run/ordered-teardown"}
+					wl.Status.Components = []v1alpha1.ComponentStatus{
+						{Name: "source", State: "Realized", StampedRef: stampedRef("source")},
+						{Name: "image", State: "Realized", StampedRef: stampedRef("image")},
+						{Name: "config", State: "Realized", StampedRef: stampedRef("config")},
+						{Name: "tests", State: "Realized", StampedRef: stampedRef("tests")},
+						{Name: "artifact", State: "Realized"},
+					}
+
+					repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{{
+						ObjectMeta: metav1.ObjectMeta{Name: "some-supply-chain"},
+						Spec: v1alpha1.SupplyChainSpec{
+							Components: []v1alpha1.SupplyChainComponent{
+								{Name: "source"},
+								{Name: "image", Sources: []v1alpha1.ComponentReference{{Name: "source", Component: "source"}}},
+								{Name: "config", Images: []v1alpha1.ComponentReference{{Name: "image", Component: "image"}}},
+								{Name: "tests", DependsOn: []string{"source"}},
+								{Name: "artifact"},
+							},
+						},
+					}}, nil)
+
+					remaining = map[string]int{"source": 1, "image": 1, "config": 1, "tests": 1}
+					repo.ListStampedObjectsStub = func(_ schema.GroupVersionKind, namespace string, _ types.UID, component string) ([]*unstructured.Unstructured, error) {
+						var objects []*unstructured.Unstructured
+						for i := 0; i < remaining[component]; i++ {
+							obj := &unstructured.Unstructured{}
+							obj.SetName(component)
+							obj.SetNamespace(namespace)
+							obj.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Workload", Name: wl.Name, UID: wl.UID}})
+							objects = append(objects, obj)
+						}
+						return objects, nil
+					}
+				})
+
+				Context("when the workload is being deleted", func() {
+					BeforeEach(func() {
+						now := metav1.Now()
+						wl.DeletionTimestamp = &now
+					})
+
+					deletedNames := func() []string {
+						var names []string
+						for i := 0; i < repo.DeleteCallCount(); i++ {
+							names = append(names, repo.DeleteArgsForCall(i).GetName())
+						}
+						return names
+					}
+
+					It("deletes the objects of the deepest components first, and waits for them to be gone", func() {
+						result, err := reconciler.Reconcile(ctx, req)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
+
+						Expect(deletedNames()).To(ConsistOf("config"))
+						Expect(repo.UpdateCallCount()).To(Equal(0))
+						Expect(rlzr.RealizeCallCount()).To(Equal(0))
+						Expect(repo.StatusUpdateCallCount()).To(Equal(0))
+
+						gvk, namespace, owner, resourceName := repo.ListStampedObjectsArgsForCall(0)
+						Expect(gvk).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}))
+						Expect(namespace).To(Equal("my-namespace"))
+						Expect(owner).To(BeEquivalentTo("workload-uid"))
+						Expect(resourceName).To(Equal("config"))
+					})
+
+					It("does not delete objects again while they are being deleted", func() {
+						repo.ListStampedObjectsStub = func(schema.GroupVersionKind, string, types.UID, string) ([]*unstructured.Unstructured, error) {
+							obj := &unstructured.Unstructured{}
+							obj.SetDeletionTimestamp(wl.DeletionTimestamp)
+							obj.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Workload", Name: wl.Name, UID: wl.UID}})
+							return []*unstructured.Unstructured{obj}, nil
+						}
+
+						result, _ := reconciler.Reconcile(ctx, req)
+						Expect(result).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
+						Expect(repo.DeleteCallCount()).To(Equal(0))
+					})
+
+					It("tears the objects down even while the workload is paused", func() {
+						wl.Spec.Paused = true
+
+						_, err := reconciler.Reconcile(ctx, req)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(deletedNames()).To(ConsistOf("config"))
+					})
+
+					It("deletes the next level once the deeper one is gone", func() {
+						remaining["config"] = 0
+						_, _ = reconciler.Reconcile(ctx, req)
+
+						Expect(deletedNames()).To(ConsistOf("image", "tests"))
+					})
+
+					It("removes the finalizer once every object is gone", func() {
+						remaining = map[string]int{}
+						result, err := reconciler.Reconcile(ctx, req)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(Equal(ctrl.Result{}))
+
+						Expect(repo.UpdateCallCount()).To(Equal(1))
+						Expect(repo.UpdateArgsForCall(0).GetFinalizers()).To(BeEmpty())
+					})
+
+					It("leaves the objects the workload does not own in place", func() {
+						repo.ListStampedObjectsStub = func(_ schema.GroupVersionKind, namespace string, _ types.UID, component string) ([]*unstructured.Unstructured, error) {
+							obj := &unstructured.Unstructured{}
+							obj.SetName(component)
+							obj.SetNamespace(namespace)
+							return []*unstructured.Unstructured{obj}, nil
+						}
+
+						result, err := reconciler.Reconcile(ctx, req)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(Equal(ctrl.Result{}))
+
+						Expect(repo.DeleteCallCount()).To(Equal(0))
+						Expect(repo.UpdateArgsForCall(0).GetFinalizers()).To(BeEmpty())
+					})
+
+					It("deletes the objects stamped into another namespace by their labels", func() {
+						wl.Status.Components[2].StampedRef.Namespace = "builds"
+						repo.ListStampedObjectsStub = func(_ schema.GroupVersionKind, namespace string, _ types.UID, component string) ([]*unstructured.Unstructured, error) {
+							obj := &unstructured.Unstructured{}
+							obj.SetName(component)
+							obj.SetNamespace(namespace)
+							return []*unstructured.Unstructured{obj}, nil
+						}
+
+						_, _ = reconciler.Reconcile(ctx, req)
+
+						_, namespace, _, _ := repo.ListStampedObjectsArgsForCall(0)
+						Expect(namespace).To(Equal("builds"))
+						Expect(deletedNames()).To(ConsistOf("config"))
+					})
+
+					It("returns an error when an object cannot be deleted", func() {
+						repo.DeleteReturns(errors.New("some error"))
+						_, err := reconciler.Reconcile(ctx, req)
+
+						Expect(err).To(MatchError("teardown: delete object of component 'config': some error"))
+					})
+
+					Context("and the supply chain cannot be found", func() {
+						BeforeEach(func() {
+							repo.GetSupplyChainsForWorkloadReturns(nil, nil)
+						})
+
+						It("deletes every object at once", func() {
+							_, _ = reconciler.Reconcile(ctx, req)
+							Expect(deletedNames()).To(ConsistOf("source", "image", "config", "tests"))
+						})
+					})
+
+					Context("for longer than the teardown timeout", func() {
+						BeforeEach(func() {
+							wl.Spec.Teardown.Timeout = &metav1.Duration{Duration: time.Minute}
+							started := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+							wl.DeletionTimestamp = &started
+						})
+
+						It("leaves the remaining objects to the garbage collector", func() {
+							_, err := reconciler.Reconcile(ctx, req)
+							Expect(err).NotTo(HaveOccurred())
+
+							Expect(repo.DeleteCallCount()).To(Equal(0))
+							Expect(repo.UpdateArgsForCall(0).GetFinalizers()).To(BeEmpty())
+						})
+					})
+
+					Context("without an ordered teardown", func() {
+						BeforeEach(func() {
+							wl.Spec.Teardown = nil
+							wl.Finalizers = []string{"carto.run/teardown"}
+							wl.Status.Components[2].StampedRef.Namespace = "builds"
+						})
+
+						It("deletes every object at once, including those in another namespace", func() {
+							result, err := reconciler.Reconcile(ctx, req)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result).To(Equal(ctrl.Result{}))
+
+							Expect(deletedNames()).To(ConsistOf("source", "image", "config", "tests"))
+							Expect(repo.UpdateCallCount()).To(Equal(1))
+							Expect(repo.UpdateArgsForCall(0).GetFinalizers()).To(BeEmpty())
+						})
+
+						It("keeps the finalizer when an object cannot be deleted", func() {
+							repo.DeleteReturns(errors.New("some error"))
+							_, err := reconciler.Reconcile(ctx, req)
+
+							Expect(err).To(MatchError("teardown: delete object of component 'source': some error"))
+							Expect(repo.UpdateCallCount()).To(Equal(0))
+						})
+					})
+
+					Context("with the Orphan policy", func() {
+						BeforeEach(func() {
+							wl.Spec.Teardown.Policy = v1alpha1.OrphanTeardownPolicy
+						})
+
+						It("removes the workload's references from the objects instead of deleting them", func() {
+							result, err := reconciler.Reconcile(ctx, req)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result).To(Equal(ctrl.Result{}))
+
+							Expect(repo.DeleteCallCount()).To(Equal(0))
+							Expect(repo.UpdateCallCount()).To(Equal(5))
+							for i := 0; i < 4; i++ {
+								Expect(repo.UpdateArgsForCall(i).GetOwnerReferences()).To(BeEmpty())
+							}
+							Expect(repo.UpdateArgsForCall(4).GetFinalizers()).To(BeEmpty())
+						})
+
+						It("returns an error when an object cannot be orphaned", func() {
+							repo.UpdateReturns(errors.New("some error"))
+							_, err := reconciler.Reconcile(ctx, req)
+
+							Expect(err).To(MatchError("teardown: orphan object of component 'source': some error"))
+						})
+					})
+
+					Context("without the finalizer", func() {
+						BeforeEach(func() {
+							wl.Finalizers = nil
+						})
+
+						It("leaves the objects to the garbage collector", func() {
+							result, err := reconciler.Reconcile(ctx, req)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result).To(Equal(ctrl.Result{}))
+							Expect(repo.DeleteCallCount()).To(Equal(0))
+							Expect(repo.UpdateCallCount()).To(Equal(0))
+						})
+					})
+				})
+
+				Context("when a component is not submitted again", func() {
+					BeforeEach(func() {
+						wl.Status.Conditions = nil
+						wl.Status.Components[0].StampedRef = stampedRef("source")
+						rlzr.RealizeReturns([]v1alpha1.ComponentStatus{
+							{Name: "source", State: "Failed", Message: "some error"},
+							{Name: "image", State: "Blocked", Message: "blocked by component 'source'"},
+						}, errors.New("some error"))
+						repo.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{{
+							ObjectMeta: metav1.ObjectMeta{Name: "some-supply-chain"},
+							Status: v1alpha1.SupplyChainStatus{
+								Conditions: []metav1.Condition{{Type: "Ready", Status: "True"}},
+							},
+						}}, nil)
+					})
+
+					It("keeps referring to the object stamped for it before", func() {
+						_, _ = reconciler.Reconcile(ctx, req)
+
+						Expect(wl.Status.Components[0].StampedRef).To(Equal(stampedRef("source")))
+						Expect(wl.Status.Components[1].StampedRef).To(Equal(stampedRef("image")))
+					})
+				})
+			})
+		})
+
 	})
 
+	Describe("StampedObjectToWorkloadRequests", func() {
+		var obj *unstructured.Unstructured
+
+		BeforeEach(func() {
+			obj = &unstructured.Unstructured{}
+			obj.SetNamespace("builds")
+			obj.SetLabels(map[string]string{"carto.run/owner-uid": "workload-uid"})
+		})
+
+		It("maps an object to the workload its identity labels name", func() {
+			obj.SetLabels(map[string]string{
+				"carto.run/owner-uid":       "workload-uid",
+				"carto.run/owner-kind":      "Workload",
+				"carto.run/owner-name":      "petclinic",
+				"carto.run/owner-namespace": "dev",
+			})
+
+			Expect(workload.StampedObjectToWorkloadRequests(obj)).To(ConsistOf(
+				ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "dev", Name: "petclinic"}},
+			))
+		})
+
+		It("maps an object without those labels to the workload that owns it", func() {
+			obj.SetOwnerReferences([]metav1.OwnerReference{
+				{Kind: "Workload", Name: "petclinic", UID: "workload-uid"},
+				{Kind: "Workload", Name: "other", UID: "other-uid"},
+			})
+
+			Expect(workload.StampedObjectToWorkloadRequests(obj)).To(ConsistOf(
+				ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "builds", Name: "petclinic"}},
+			))
+		})
+
+		It("maps an object stamped for a pipeline to nothing", func() {
+			obj.SetLabels(map[string]string{"carto.run/owner-kind": "Pipeline", "carto.run/owner-name": "tests"})
+
+			Expect(workload.StampedObjectToWorkloadRequests(obj)).To(BeEmpty())
+		})
+	})
 })
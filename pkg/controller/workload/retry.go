@@ -0,0 +1,76 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// MaxRetryBackoff caps the delay before retrying a realization that keeps
+// failing the same way. The delay starts at twice the reconcile interval
+// and doubles with each failure.
+const MaxRetryBackoff = 5 * time.Minute
+
+// trackRetry counts a failed realization in the workload's status, and
+// returns how long to wait before retrying it. The first failure with a
+// reason and message is retried as any error is, and so is not delayed.
+func (rec *reconciliation) trackRetry(workload *v1alpha1.Workload, failure metav1.Condition) time.Duration {
+	rec.statusChanged = true
+
+	previous := workload.Status.Retry
+	if previous == nil || previous.Reason != failure.Reason || previous.Message != failure.Message {
+		workload.Status.Retry = &v1alpha1.RealizationRetry{
+			Failures: 1,
+			Reason:   failure.Reason,
+			Message:  failure.Message,
+		}
+		return 0
+	}
+
+	failures := previous.Failures + 1
+	delay := retryBackoff(failures)
+	next := metav1.NewTime(time.Now().Add(delay))
+	workload.Status.Retry = &v1alpha1.RealizationRetry{
+		Failures:      failures,
+		Reason:        failure.Reason,
+		Message:       failure.Message,
+		NextRetryTime: &next,
+	}
+	return delay
+}
+
+// clearRetry forgets the failures of the workload's realization once it no
+// longer fails.
+func (rec *reconciliation) clearRetry(workload *v1alpha1.Workload) {
+	if workload.Status.Retry != nil {
+		workload.Status.Retry = nil
+		rec.statusChanged = true
+	}
+}
+
+func retryBackoff(failures int32) time.Duration {
+	delay := reconcileInterval
+	for i := int32(1); i < failures && delay < MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > MaxRetryBackoff {
+		delay = MaxRetryBackoff
+	}
+	return delay
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// resolveSecretParams reads the values of the workload's params that come
+// from secrets, as the workload's service account or the default service
+// account of its namespace, so that a workload can only hand templates the
+// secrets its owner may read. Params whose optional key is missing have no
+// entry, and are left to the defaults of the supply chain and templates.
+func (r *Reconciler) resolveSecretParams(ctx context.Context, workload *v1alpha1.Workload) (map[string]apiextensionsv1.JSON, error) {
+	var fromSecrets bool
+	for _, param := range workload.Spec.Params {
+		fromSecrets = fromSecrets || param.ValueFrom != nil
+	}
+	if !fromSecrets {
+		return nil, nil
+	}
+
+	serviceAccountName := workload.Spec.ServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = DefaultServiceAccountName
+	}
+	if r.impersonator == nil {
+		return nil, fmt.Errorf("workload has params from secrets, but the controller cannot impersonate service account '%s/%s' to read them", workload.Namespace, serviceAccountName)
+	}
+	reader, err := r.impersonator.ServiceAccountClient(workload.Namespace, serviceAccountName)
+	if err != nil {
+		return nil, fmt.Errorf("impersonate service account '%s/%s': %w", workload.Namespace, serviceAccountName, err)
+	}
+
+	values := map[string]apiextensionsv1.JSON{}
+	for _, param := range workload.Spec.Params {
+		if param.ValueFrom == nil {
+			continue
+		}
+		value, found, err := secretParamValue(ctx, reader, workload.Namespace, param.ValueFrom)
+		if err != nil {
+			return nil, fmt.Errorf("param '%s': %w", param.Name, err)
+		}
+		if found {
+			values[param.Name] = value
+		}
+	}
+	return values, nil
+}
+
+func secretParamValue(ctx context.Context, reader client.Reader, namespace string, source *v1alpha1.WorkloadParamSource) (apiextensionsv1.JSON, bool, error) {
+	ref := source.SecretKeyRef
+	if ref == nil {
+		return apiextensionsv1.JSON{}, false, errors.New("valueFrom must set secretKeyRef")
+	}
+	optional := ref.Optional != nil && *ref.Optional
+
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret)
+	if kerrors.IsNotFound(err) && optional {
+		return apiextensionsv1.JSON{}, false, nil
+	}
+	if err != nil {
+		return apiextensionsv1.JSON{}, false, fmt.Errorf("get secret '%s': %w", ref.Name, err)
+	}
+
+	encoded, found, err := unstructured.NestedString(secret.Object, "data", ref.Key)
+	if err != nil {
+		return apiextensionsv1.JSON{}, false, fmt.Errorf("read key '%s' of secret '%s': %w", ref.Key, ref.Name, err)
+	}
+	if !found {
+		if optional {
+			return apiextensionsv1.JSON{}, false, nil
+		}
+		return apiextensionsv1.JSON{}, false, fmt.Errorf("secret '%s' has no key '%s'", ref.Name, ref.Key)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return apiextensionsv1.JSON{}, false, fmt.Errorf("decode key '%s' of secret '%s': %w", ref.Key, ref.Name, err)
+	}
+	raw, err := json.Marshal(string(decoded))
+	if err != nil {
+		return apiextensionsv1.JSON{}, false, fmt.Errorf("marshal key '%s' of secret '%s': %w", ref.Key, ref.Name, err)
+	}
+	return apiextensionsv1.JSON{Raw: raw}, true, nil
+}
@@ -0,0 +1,332 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
+)
+
+const defaultTeardownTimeout = 5 * time.Minute
+
+// ensureTeardownFinalizer holds the deletion of a workload with the ordered
+// teardown finalizer when it asks for an ordered teardown, and with the
+// teardown finalizer otherwise, swapping one for the other when the
+// workload changes its mind.
+func (r *Reconciler) ensureTeardownFinalizer(workload *v1alpha1.Workload) error {
+	wanted, unwanted := v1alpha1.TeardownFinalizer, v1alpha1.OrderedTeardownFinalizer
+	if workload.Spec.Teardown != nil && workload.Spec.Teardown.Ordered {
+		wanted, unwanted = unwanted, wanted
+	}
+	if controllerutil.ContainsFinalizer(workload, wanted) && !controllerutil.ContainsFinalizer(workload, unwanted) {
+		return nil
+	}
+
+	controllerutil.AddFinalizer(workload, wanted)
+	controllerutil.RemoveFinalizer(workload, unwanted)
+
+	if err := r.repo.Update(workload); err != nil {
+		return fmt.Errorf("update workload finalizers: %w", err)
+	}
+	return nil
+}
+
+// teardown disposes of the objects stamped for a deleted workload as its
+// teardown policy chooses, before releasing the workload. Objects the
+// workload does not own are left in place.
+//
+// With the Orphan policy, the workload's references are removed from the
+// objects, so that the garbage collector keeps them. Otherwise, the objects
+// are deleted, including those stamped into another namespace or cluster
+// scoped, which the garbage collector does not delete with the workload.
+// An ordered teardown deletes them one level of the supply chain at a time,
+// starting with the components nothing depends on, and waits for the
+// objects of a level to be gone before deleting the next. Once every object
+// is gone, or the teardown times out, the finalizer is removed and the
+// remaining objects are left to the garbage collector.
+func (r *Reconciler) teardown(ctx context.Context, workload *v1alpha1.Workload) (ctrl.Result, error) {
+	logger := logr.FromContext(ctx)
+
+	ordered := controllerutil.ContainsFinalizer(workload, v1alpha1.OrderedTeardownFinalizer)
+	if !ordered && !controllerutil.ContainsFinalizer(workload, v1alpha1.TeardownFinalizer) {
+		logger.Info("finished")
+		return ctrl.Result{}, nil
+	}
+
+	timeout := defaultTeardownTimeout
+	if workload.Spec.Teardown != nil && workload.Spec.Teardown.Timeout != nil {
+		timeout = workload.Spec.Teardown.Timeout.Duration
+	}
+
+	switch {
+	case workload.Spec.Teardown != nil && workload.Spec.Teardown.Policy == v1alpha1.OrphanTeardownPolicy:
+		if err := r.orphanAll(workload); err != nil {
+			logger.Info("finished")
+			return ctrl.Result{}, fmt.Errorf("teardown: %w", err)
+		}
+	case !ordered:
+		if err := r.deleteAll(workload); err != nil {
+			logger.Info("finished")
+			return ctrl.Result{}, fmt.Errorf("teardown: %w", err)
+		}
+	case time.Since(workload.DeletionTimestamp.Time) < timeout:
+		remaining, err := r.deleteNextLevel(workload)
+		if err != nil {
+			logger.Info("finished")
+			return ctrl.Result{}, fmt.Errorf("teardown: %w", err)
+		}
+		if remaining > 0 {
+			logger.Info("waiting for stamped objects to be deleted", "remaining", remaining)
+			logger.Info("finished")
+			return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+		}
+	default:
+		logger.Info("teardown timed out, leaving the remaining stamped objects to the garbage collector", "timeout", timeout.String())
+	}
+
+	controllerutil.RemoveFinalizer(workload, v1alpha1.OrderedTeardownFinalizer)
+	controllerutil.RemoveFinalizer(workload, v1alpha1.TeardownFinalizer)
+	if err := r.repo.Update(workload); err != nil {
+		logger.Info("finished")
+		return ctrl.Result{}, fmt.Errorf("update workload finalizers: %w", err)
+	}
+
+	logger.Info("finished")
+	return ctrl.Result{}, nil
+}
+
+// deleteAll deletes the objects of every component at once, without waiting
+// for them to be gone.
+func (r *Reconciler) deleteAll(workload *v1alpha1.Workload) error {
+	for _, component := range workload.Status.Components {
+		if component.StampedRef == nil {
+			continue
+		}
+
+		objects, err := r.stampedObjects(workload, component)
+		if err != nil {
+			return fmt.Errorf("list objects of component '%s': %w", component.Name, err)
+		}
+
+		for _, obj := range objects {
+			if !ownedBy(obj, workload) || obj.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if err := r.repo.Delete(obj); err != nil {
+				return fmt.Errorf("delete object of component '%s': %w", component.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// orphanAll removes the workload's references from the objects of every
+// component, so that the garbage collector keeps them once the workload is
+// gone.
+func (r *Reconciler) orphanAll(workload *v1alpha1.Workload) error {
+	for _, component := range workload.Status.Components {
+		if component.StampedRef == nil {
+			continue
+		}
+
+		objects, err := r.stampedObjects(workload, component)
+		if err != nil {
+			return fmt.Errorf("list objects of component '%s': %w", component.Name, err)
+		}
+
+		for _, obj := range objects {
+			refs := obj.GetOwnerReferences()
+			var kept []metav1.OwnerReference
+			for _, ref := range refs {
+				if ref.UID != workload.UID {
+					kept = append(kept, ref)
+				}
+			}
+			if len(kept) == len(refs) {
+				continue
+			}
+
+			obj.SetOwnerReferences(kept)
+			if err := r.repo.Update(obj); err != nil {
+				return fmt.Errorf("orphan object of component '%s': %w", component.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteNextLevel deletes the objects of the highest level that still has
+// any, and returns how many of them are yet to be gone.
+func (r *Reconciler) deleteNextLevel(workload *v1alpha1.Workload) (int, error) {
+	for _, level := range r.teardownLevels(workload) {
+		remaining := 0
+		for _, component := range level {
+			objects, err := r.stampedObjects(workload, component)
+			if err != nil {
+				return 0, fmt.Errorf("list objects of component '%s': %w", component.Name, err)
+			}
+
+			for _, obj := range objects {
+				if !ownedBy(obj, workload) {
+					continue
+				}
+				remaining++
+				if obj.GetDeletionTimestamp() != nil {
+					continue
+				}
+				if err := r.repo.Delete(obj); err != nil {
+					return 0, fmt.Errorf("delete object of component '%s': %w", component.Name, err)
+				}
+			}
+		}
+
+		if remaining > 0 {
+			return remaining, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (r *Reconciler) stampedObjects(workload *v1alpha1.Workload, component v1alpha1.ComponentStatus) ([]*unstructured.Unstructured, error) {
+	namespace := component.StampedRef.Namespace
+	if namespace == "" {
+		namespace = workload.Namespace
+	}
+	gvk := schema.FromAPIVersionAndKind(component.StampedRef.APIVersion, component.StampedRef.Kind)
+
+	return r.repo.ListStampedObjects(gvk, namespace, workload.UID, component.Name)
+}
+
+// ownedBy tells whether the workload owns the object. Objects stamped
+// without owner references outlive the workload, so they are not torn down,
+// except for those stamped into another namespace, which cannot refer to
+// the workload and are owned by their labels alone.
+func ownedBy(obj *unstructured.Unstructured, workload *v1alpha1.Workload) bool {
+	if obj.GetNamespace() != workload.Namespace {
+		return true
+	}
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == workload.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// teardownLevels groups the components with stamped objects by how deep
+// they are in the supply chain's dependencies, deepest first. Components
+// the supply chain no longer has come first, and when the supply chain
+// cannot be resolved every component is torn down at once.
+func (r *Reconciler) teardownLevels(workload *v1alpha1.Workload) [][]v1alpha1.ComponentStatus {
+	depths := r.componentDepths(workload)
+
+	byDepth := map[int][]v1alpha1.ComponentStatus{}
+	for _, component := range workload.Status.Components {
+		if component.StampedRef == nil {
+			continue
+		}
+
+		depth, ok := depths[component.Name]
+		if !ok {
+			depth = len(depths)
+		}
+		byDepth[depth] = append(byDepth[depth], component)
+	}
+
+	var keys []int
+	for depth := range byDepth {
+		keys = append(keys, depth)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(keys)))
+
+	levels := make([][]v1alpha1.ComponentStatus, len(keys))
+	for i, depth := range keys {
+		levels[i] = byDepth[depth]
+	}
+	return levels
+}
+
+// componentDepths returns the length of the longest chain of dependencies
+// of each component of the workload's supply chain.
+func (r *Reconciler) componentDepths(workload *v1alpha1.Workload) map[string]int {
+	supplyChains, _, err := r.candidateSupplyChains(workload)
+	if err != nil {
+		return nil
+	}
+	supplyChain, _ := selector.ChooseSupplyChain(supplyChains)
+	if supplyChain == nil {
+		return nil
+	}
+	supplyChain, err = supplyChain.Resolve(r.repo.GetSupplyChain)
+	if err != nil {
+		return nil
+	}
+
+	order, err := supplyChain.Spec.RealizationOrder()
+	if err != nil {
+		return nil
+	}
+	dependencies := supplyChain.Spec.DependencyIndexes()
+
+	depths := make([]int, len(supplyChain.Spec.Components))
+	for _, i := range order {
+		for _, dependency := range dependencies[i] {
+			if depths[dependency]+1 > depths[i] {
+				depths[i] = depths[dependency] + 1
+			}
+		}
+	}
+
+	result := make(map[string]int, len(depths))
+	for i, component := range supplyChain.Spec.Components {
+		result[component.Name] = depths[i]
+	}
+	return result
+}
+
+// keepStampedRefs carries the stamped objects of the previous realization
+// over to the components that were not submitted this time, e.g. because
+// they are blocked, so that they are still torn down in order.
+func keepStampedRefs(previous, current []v1alpha1.ComponentStatus) []v1alpha1.ComponentStatus {
+	refs := map[string]*v1alpha1.ComponentStatus{}
+	for i := range previous {
+		refs[previous[i].Name] = &previous[i]
+	}
+
+	for i := range current {
+		if current[i].StampedRef != nil {
+			continue
+		}
+		if prior, ok := refs[current[i].Name]; ok {
+			current[i].StampedRef = prior.StampedRef
+		}
+	}
+	return current
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+import (
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/identity"
+)
+
+//counterfeiter:generate . DynamicTracker
+type DynamicTracker interface {
+	Watch(log logr.Logger, obj runtime.Object, handler handler.EventHandler) error
+}
+
+// AddTracking has the kind of every object stamped for a workload watched,
+// so that a change to a stamped object, such as its status, reconciles the
+// workload right away rather than at the next resync.
+func (r *Reconciler) AddTracking(dynamicTracker DynamicTracker) {
+	r.dynamicTracker = dynamicTracker
+}
+
+// trackStampedKinds watches the kinds of the objects stamped for the
+// components. The tracker watches each kind once, however many workloads
+// stamp it.
+func (r *Reconciler) trackStampedKinds(logger logr.Logger, components []v1alpha1.ComponentStatus) {
+	if r.dynamicTracker == nil {
+		return
+	}
+
+	for _, component := range components {
+		if component.StampedRef == nil {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(component.StampedRef.APIVersion)
+		obj.SetKind(component.StampedRef.Kind)
+		if err := r.dynamicTracker.Watch(logger, obj, handler.EnqueueRequestsFromMapFunc(StampedObjectToWorkloadRequests)); err != nil {
+			logger.Error(err, "dynamic tracker watch", "kind", obj.GroupVersionKind().String())
+		}
+	}
+}
+
+// StampedObjectToWorkloadRequests maps an object to the workload it was
+// stamped for, by its identity labels, which also name a workload in
+// another namespace, or else by its owner references.
+func StampedObjectToWorkloadRequests(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if labels[identity.OwnerKindLabel] == "Workload" && labels[identity.OwnerNameLabel] != "" {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{
+			Namespace: labels[identity.OwnerNamespaceLabel],
+			Name:      labels[identity.OwnerNameLabel],
+		}}}
+	}
+
+	var requests []reconcile.Request
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "Workload" && ref.UID == types.UID(labels[identity.OwnerUIDLabel]) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      ref.Name,
+			}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloadpreview
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+func WorkloadCreatedCondition() metav1.Condition {
+	return metav1.Condition{
+		Type:   v1alpha1.WorkloadPreviewWorkloadCreated,
+		Status: metav1.ConditionTrue,
+		Reason: v1alpha1.CreatedWorkloadCreatedReason,
+	}
+}
+
+func WorkloadNotFoundCondition(workloadName string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadPreviewWorkloadCreated,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.WorkloadNotFoundWorkloadCreatedReason,
+		Message: fmt.Sprintf("workload '%s' not found", workloadName),
+	}
+}
+
+func NoGitSourceCondition(workloadName string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadPreviewWorkloadCreated,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.NoGitSourceWorkloadCreatedReason,
+		Message: fmt.Sprintf("workload '%s' does not take its source from git", workloadName),
+	}
+}
+
+func WorkloadRejectedByAPIServerCondition(err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.WorkloadPreviewWorkloadCreated,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.WorkloadRejectedByAPIServerWorkloadCreatedReason,
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloadpreview
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+)
+
+type Reconciler struct {
+	repo                    repository.Repository
+	conditionManagerBuilder conditions.ConditionManagerBuilder
+}
+
+func NewReconciler(repo repository.Repository, conditionManagerBuilder conditions.ConditionManagerBuilder) *Reconciler {
+	return &Reconciler{
+		repo:                    repo,
+		conditionManagerBuilder: conditionManagerBuilder,
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logr.FromContext(ctx).
+		WithValues("name", req.Name, "namespace", req.Namespace)
+	logger.Info("started")
+	defer logger.Info("finished")
+
+	preview, err := r.repo.GetWorkloadPreview(req.Name, req.Namespace)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("get workload preview: %w", err)
+	}
+
+	expiration := preview.ExpirationTime()
+	if expiration != nil && !time.Now().Before(expiration.Time) {
+		logger.Info("workload preview expired", "expirationTime", expiration)
+		if err := r.deletePreview(preview); err != nil {
+			return ctrl.Result{}, fmt.Errorf("delete expired workload preview: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	conditionManager := r.conditionManagerBuilder(v1alpha1.WorkloadPreviewReady, preview.Status.Conditions)
+	err = r.ensurePreviewWorkload(preview, conditionManager)
+
+	preview.Status.Conditions, _ = conditionManager.Finalize()
+	conditions.ObserveGeneration(preview.Status.Conditions, preview.Generation)
+	preview.Status.ObservedGeneration = preview.Generation
+	preview.Status.WorkloadName = preview.WorkloadName()
+	preview.Status.ExpirationTime = expiration
+	if updateErr := r.repo.StatusUpdate(preview); updateErr != nil {
+		logger.Error(updateErr, "update error")
+		if err == nil {
+			return ctrl.Result{}, fmt.Errorf("update workload preview status: %w", updateErr)
+		}
+	}
+
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if expiration != nil {
+		return ctrl.Result{RequeueAfter: time.Until(expiration.Time)}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) ensurePreviewWorkload(preview *v1alpha1.WorkloadPreview, conditionManager conditions.ConditionManager) error {
+	workload, err := r.repo.GetWorkload(preview.Spec.Workload, preview.Namespace)
+	if err != nil {
+		conditionManager.AddPositive(WorkloadNotFoundCondition(preview.Spec.Workload))
+		return fmt.Errorf("get workload: %w", err)
+	}
+
+	if workload.Spec.Source == nil || workload.Spec.Source.Git == nil {
+		conditionManager.AddPositive(NoGitSourceCondition(workload.Name))
+		return nil
+	}
+
+	previewWorkload, err := newPreviewWorkload(preview, workload)
+	if err != nil {
+		return fmt.Errorf("new preview workload: %w", err)
+	}
+
+	if err := r.repo.EnsureObjectExistsOnCluster(previewWorkload, true); err != nil {
+		conditionManager.AddPositive(WorkloadRejectedByAPIServerCondition(err))
+		return fmt.Errorf("ensure preview workload exists: %w", err)
+	}
+
+	conditionManager.AddPositive(WorkloadCreatedCondition())
+	return nil
+}
+
+// newPreviewWorkload copies the workload, building the preview's branch.
+// The preview owns the copy, so that deleting the preview deletes the copy
+// and, in turn, the objects stamped for it.
+func newPreviewWorkload(preview *v1alpha1.WorkloadPreview, workload *v1alpha1.Workload) (*unstructured.Unstructured, error) {
+	labels := map[string]string{}
+	for key, value := range workload.Labels {
+		labels[key] = value
+	}
+	labels[v1alpha1.WorkloadPreviewLabel] = preview.Name
+
+	spec := workload.Spec.DeepCopy()
+	branch := preview.Spec.Branch
+	spec.Source.Git.Ref = &v1alpha1.WorkloadGitRef{Branch: &branch}
+
+	isController := true
+	previewWorkload := &v1alpha1.Workload{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Workload",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      preview.WorkloadName(),
+			Namespace: preview.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         v1alpha1.SchemeGroupVersion.String(),
+				Kind:               "WorkloadPreview",
+				Name:               preview.Name,
+				UID:                preview.UID,
+				Controller:         &isController,
+				BlockOwnerDeletion: &isController,
+			}},
+		},
+		Spec: *spec,
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(previewWorkload)
+	if err != nil {
+		return nil, err
+	}
+	delete(content, "status")
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+func (r *Reconciler) deletePreview(preview *v1alpha1.WorkloadPreview) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(v1alpha1.SchemeGroupVersion.String())
+	obj.SetKind("WorkloadPreview")
+	obj.SetNamespace(preview.Namespace)
+	obj.SetName(preview.Name)
+	return r.repo.Delete(obj)
+}
@@ -0,0 +1,240 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloadpreview_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions"
+	"github.com/vmware-tanzu/cartographer/pkg/conditions/conditionsfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/controller/workloadpreview"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx              context.Context
+		req              ctrl.Request
+		repo             *repositoryfakes.FakeRepository
+		conditionManager *conditionsfakes.FakeConditionManager
+		reconciler       *workloadpreview.Reconciler
+		preview          *v1alpha1.WorkloadPreview
+		workload         *v1alpha1.Workload
+	)
+
+	BeforeEach(func() {
+		ctx = logr.NewContext(context.Background(), zap.New())
+
+		conditionManager = &conditionsfakes.FakeConditionManager{}
+		repo = &repositoryfakes.FakeRepository{}
+		reconciler = workloadpreview.NewReconciler(repo, func(string, []metav1.Condition) conditions.ConditionManager {
+			return conditionManager
+		})
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Name: "petclinic-pr-42", Namespace: "dev"}}
+
+		preview = &v1alpha1.WorkloadPreview{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "petclinic-pr-42",
+				Namespace:         "dev",
+				UID:               "preview-uid",
+				Generation:        2,
+				CreationTimestamp: metav1.Now(),
+			},
+			Spec: v1alpha1.WorkloadPreviewSpec{
+				Workload:    "petclinic",
+				PullRequest: 42,
+				Branch:      "fix-owners",
+			},
+		}
+		repo.GetWorkloadPreviewReturns(preview, nil)
+
+		url := "https://github.com/spring-projects/spring-petclinic.git"
+		main := "main"
+		workload = &v1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "petclinic",
+				Namespace: "dev",
+				Labels:    map[string]string{"app.tanzu.vmware.com/workload-type": "web"},
+			},
+			Spec: v1alpha1.WorkloadSpec{
+				Source: &v1alpha1.WorkloadSource{
+					Git: &v1alpha1.WorkloadGit{URL: &url, Ref: &v1alpha1.WorkloadGitRef{Branch: &main}},
+				},
+			},
+		}
+		repo.GetWorkloadReturns(workload, nil)
+	})
+
+	It("creates a workload building the pull request's branch", func() {
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		name, namespace := repo.GetWorkloadArgsForCall(0)
+		Expect(name).To(Equal("petclinic"))
+		Expect(namespace).To(Equal("dev"))
+
+		Expect(repo.EnsureObjectExistsOnClusterCallCount()).To(Equal(1))
+		obj, allowUpdate, _ := repo.EnsureObjectExistsOnClusterArgsForCall(0)
+		Expect(allowUpdate).To(BeTrue())
+		Expect(obj.GroupVersionKind()).To(Equal(schema.GroupVersionKind{Group: "carto.run", Version: "v1alpha1", Kind: "Workload"}))
+		Expect(obj.GetName()).To(Equal("petclinic-pr-42"))
+		Expect(obj.GetNamespace()).To(Equal("dev"))
+		Expect(obj.GetLabels()).To(Equal(map[string]string{
+			"app.tanzu.vmware.com/workload-type": "web",
+			"carto.run/workload-preview-name":    "petclinic-pr-42",
+		}))
+
+		owners := obj.GetOwnerReferences()
+		Expect(owners).To(HaveLen(1))
+		Expect(owners[0].Kind).To(Equal("WorkloadPreview"))
+		Expect(owners[0].UID).To(Equal(types.UID("preview-uid")))
+		Expect(*owners[0].Controller).To(BeTrue())
+
+		branch, _, _ := unstructured.NestedString(obj.Object, "spec", "source", "git", "ref", "branch")
+		Expect(branch).To(Equal("fix-owners"))
+		url, _, _ := unstructured.NestedString(obj.Object, "spec", "source", "git", "url")
+		Expect(url).To(Equal("https://github.com/spring-projects/spring-petclinic.git"))
+		Expect(obj.Object).NotTo(HaveKey("status"))
+	})
+
+	It("leaves the previewed workload unchanged", func() {
+		_, _ = reconciler.Reconcile(ctx, req)
+		Expect(*workload.Spec.Source.Git.Ref.Branch).To(Equal("main"))
+	})
+
+	It("reports the workload was created and records it in the status", func() {
+		_, _ = reconciler.Reconcile(ctx, req)
+
+		Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workloadpreview.WorkloadCreatedCondition()))
+		Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+		updated := repo.StatusUpdateArgsForCall(0).(*v1alpha1.WorkloadPreview)
+		Expect(updated.Status.WorkloadName).To(Equal("petclinic-pr-42"))
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(2)))
+		Expect(updated.Status.ExpirationTime).To(BeNil())
+	})
+
+	It("does not requeue without a ttl", func() {
+		result, _ := reconciler.Reconcile(ctx, req)
+		Expect(result).To(Equal(ctrl.Result{}))
+	})
+
+	Context("with a ttl", func() {
+		BeforeEach(func() {
+			preview.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+		})
+
+		It("requeues when the preview expires", func() {
+			result, _ := reconciler.Reconcile(ctx, req)
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+
+			updated := repo.StatusUpdateArgsForCall(0).(*v1alpha1.WorkloadPreview)
+			Expect(updated.Status.ExpirationTime.Time).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+		})
+
+		Context("that has elapsed", func() {
+			BeforeEach(func() {
+				preview.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+			})
+
+			It("deletes the preview, and so its workload", func() {
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(repo.DeleteCallCount()).To(Equal(1))
+				deleted := repo.DeleteArgsForCall(0)
+				Expect(deleted.GetKind()).To(Equal("WorkloadPreview"))
+				Expect(deleted.GetName()).To(Equal("petclinic-pr-42"))
+				Expect(deleted.GetNamespace()).To(Equal("dev"))
+
+				Expect(repo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+				Expect(repo.StatusUpdateCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when the preview cannot be deleted", func() {
+				repo.DeleteReturns(errors.New("some error"))
+				_, err := reconciler.Reconcile(ctx, req)
+				Expect(err).To(MatchError("delete expired workload preview: some error"))
+			})
+		})
+	})
+
+	Context("when the workload does not exist", func() {
+		BeforeEach(func() {
+			repo.GetWorkloadReturns(nil, errors.New("not found"))
+		})
+
+		It("reports the workload was not found", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).To(MatchError("get workload: not found"))
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workloadpreview.WorkloadNotFoundCondition("petclinic")))
+			Expect(repo.StatusUpdateCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the workload does not take its source from git", func() {
+		BeforeEach(func() {
+			image := "some-image"
+			workload.Spec.Source = &v1alpha1.WorkloadSource{Image: &image}
+		})
+
+		It("reports the workload cannot be previewed without requeueing", func() {
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workloadpreview.NoGitSourceCondition("petclinic")))
+			Expect(repo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the API server rejects the workload", func() {
+		BeforeEach(func() {
+			repo.EnsureObjectExistsOnClusterReturns(errors.New("some error"))
+		})
+
+		It("reports the rejection", func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).To(MatchError("ensure preview workload exists: some error"))
+			Expect(conditionManager.AddPositiveArgsForCall(0)).To(Equal(workloadpreview.WorkloadRejectedByAPIServerCondition(errors.New("some error"))))
+		})
+	})
+
+	Context("when the preview has been deleted", func() {
+		BeforeEach(func() {
+			repo.GetWorkloadPreviewReturns(nil, kerrors.NewNotFound(schema.GroupResource{}, "petclinic-pr-42"))
+		})
+
+		It("does nothing", func() {
+			result, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(repo.GetWorkloadCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,226 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitops commits the manifests of stamped objects to Git
+// repositories, for a GitOps tool such as Flux or Argo CD to apply, rather
+// than applying them to the cluster. Repositories are written with the git
+// command, which is given the credentials of a repository by the controller
+// itself as its GIT_ASKPASS program, so that they are never in its arguments.
+package gitops
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	AuthorName  = "Cartographer"
+	AuthorEmail = "cartographer@carto.run"
+)
+
+// The environment of the controller when git runs it as its GIT_ASKPASS
+// program, with the credentials it answers with.
+const (
+	askPassEnv  = "CARTOGRAPHER_GIT_ASKPASS"
+	usernameEnv = "CARTOGRAPHER_GIT_USERNAME"
+	passwordEnv = "CARTOGRAPHER_GIT_PASSWORD"
+)
+
+// Repository is a branch of a Git repository, and the credentials it is
+// written with, if any.
+type Repository struct {
+	URL      string
+	Branch   string
+	Username string
+	Password string
+}
+
+//counterfeiter:generate . Writer
+type Writer interface {
+	// Write commits the content to the file at path in the repository, with
+	// the message, unless the file already has that content.
+	Write(ctx context.Context, repository Repository, path string, content []byte, message string) error
+}
+
+// NewWriter writes repositories with the git command. It remembers what it
+// last wrote to each file, so that unchanged content is not fetched and
+// compared again every time an owner is reconciled. The running executable
+// answers git's prompts for credentials, and must call AskPass first thing.
+func NewWriter() Writer {
+	askPass, _ := os.Executable()
+	return &writer{git: "git", askPass: askPass, written: map[string][sha256.Size]byte{}}
+}
+
+// AskPass answers the prompt of git, the first of args after the program,
+// with the username or password of the repository when the executable is run
+// by git as its GIT_ASKPASS program. It reports whether it was.
+func AskPass(args []string, out io.Writer) bool {
+	if os.Getenv(askPassEnv) == "" {
+		return false
+	}
+
+	answer := os.Getenv(passwordEnv)
+	if len(args) > 1 && strings.HasPrefix(args[1], "Username") {
+		answer = os.Getenv(usernameEnv)
+		if answer == "" {
+			answer = "git"
+		}
+	}
+	fmt.Fprintln(out, answer)
+	return true
+}
+
+type writer struct {
+	git     string
+	askPass string
+
+	mu      sync.Mutex
+	written map[string][sha256.Size]byte
+}
+
+func (w *writer) Write(ctx context.Context, repository Repository, file string, content []byte, message string) error {
+	file, err := cleanPath(file)
+	if err != nil {
+		return err
+	}
+	if err := w.checkBranch(ctx, repository.Branch); err != nil {
+		return err
+	}
+
+	key := strings.Join([]string{repository.URL, repository.Branch, file}, "\x00")
+	digest := sha256.Sum256(content)
+	w.mu.Lock()
+	last, ok := w.written[key]
+	w.mu.Unlock()
+	if ok && last == digest {
+		return nil
+	}
+
+	if err := w.write(ctx, repository, file, content, message); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.written[key] = digest
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *writer) write(ctx context.Context, repository Repository, file string, content []byte, message string) error {
+	dir, err := os.MkdirTemp("", "cartographer-gitops-")
+	if err != nil {
+		return fmt.Errorf("create work tree: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	git := func(args ...string) error {
+		return w.run(ctx, dir, repository, args...)
+	}
+
+	if err := git("init", "-q"); err != nil {
+		return err
+	}
+	err = git("fetch", "-q", "--depth", "1", "--", repository.URL, repository.Branch)
+	switch {
+	case err == nil:
+		err = git("checkout", "-q", "-B", repository.Branch, "FETCH_HEAD")
+	case strings.Contains(err.Error(), "couldn't find remote ref"):
+		err = git("checkout", "-q", "--orphan", repository.Branch)
+	}
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(dir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("create directory of '%s': %w", file, err)
+	}
+	if err := os.WriteFile(target, content, 0o644); err != nil {
+		return fmt.Errorf("write '%s': %w", file, err)
+	}
+
+	if err := git("add", "--", file); err != nil {
+		return err
+	}
+	if err := git("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+	if err := git("-c", "user.name="+AuthorName, "-c", "user.email="+AuthorEmail, "commit", "-q", "-m", message); err != nil {
+		return err
+	}
+	return git("push", "-q", "--", repository.URL, "HEAD:refs/heads/"+repository.Branch)
+}
+
+// checkBranch rejects a branch that is not a valid branch name, or that git
+// would read as an option.
+func (w *writer) checkBranch(ctx context.Context, branch string) error {
+	if branch == "" || strings.HasPrefix(branch, "-") {
+		return fmt.Errorf("branch '%s' is not a valid branch name", branch)
+	}
+
+	out, err := exec.CommandContext(ctx, w.git, "check-ref-format", "--branch", branch).Output()
+	if err != nil || strings.TrimSpace(string(out)) != branch {
+		return fmt.Errorf("branch '%s' is not a valid branch name", branch)
+	}
+	return nil
+}
+
+// run runs git in dir, never prompting on a terminal, and reports what it
+// wrote to stderr without the password of the repository. The credentials
+// of the repository, if any, are answered by the askPass program.
+func (w *writer) run(ctx context.Context, dir string, repository Repository, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, w.git, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if repository.Username != "" || repository.Password != "" {
+		cmd.Env = append(cmd.Env,
+			"GIT_ASKPASS="+w.askPass,
+			askPassEnv+"=true",
+			usernameEnv+"="+repository.Username,
+			passwordEnv+"="+repository.Password,
+		)
+	}
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		if repository.Password != "" {
+			msg = strings.ReplaceAll(msg, repository.Password, "<redacted>")
+		}
+		return fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return nil
+}
+
+func cleanPath(file string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(file, "/"))
+	if file == "" || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") || strings.HasPrefix(cleaned, ".git/") || cleaned == ".git" {
+		return "", fmt.Errorf("path '%s' is not a file in the repository", file)
+	}
+	return cleaned, nil
+}
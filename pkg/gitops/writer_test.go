@@ -0,0 +1,188 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitops_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/cartographer/pkg/gitops"
+)
+
+var _ = Describe("Writer", func() {
+	var (
+		ctx        context.Context
+		remote     string
+		repository gitops.Repository
+		writer     gitops.Writer
+	)
+
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	show := func(branch, file string) string {
+		return git(remote, "show", branch+":"+file)
+	}
+
+	BeforeEach(func() {
+		if _, err := exec.LookPath("git"); err != nil {
+			Skip("git is not installed")
+		}
+
+		ctx = context.Background()
+		var err error
+		remote, err = os.MkdirTemp("", "remote-")
+		Expect(err).NotTo(HaveOccurred())
+		git(remote, "init", "-q", "--bare")
+		repository = gitops.Repository{URL: remote, Branch: "main"}
+		writer = gitops.NewWriter()
+	})
+
+	AfterEach(func() {
+		if remote != "" {
+			Expect(os.RemoveAll(remote)).To(Succeed())
+		}
+	})
+
+	It("commits the file to a new branch", func() {
+		Expect(writer.Write(ctx, repository, "dev/petclinic/config.yaml", []byte("kind: ConfigMap\n"), "Update petclinic")).To(Succeed())
+
+		Expect(show("main", "dev/petclinic/config.yaml")).To(Equal("kind: ConfigMap"))
+		Expect(git(remote, "log", "-1", "--format=%an <%ae> %s", "main")).To(Equal("Cartographer <cartographer@carto.run> Update petclinic"))
+	})
+
+	It("keeps the other files of the branch", func() {
+		Expect(writer.Write(ctx, repository, "dev/a.yaml", []byte("a\n"), "a")).To(Succeed())
+		Expect(writer.Write(ctx, repository, "dev/b.yaml", []byte("b\n"), "b")).To(Succeed())
+
+		Expect(show("main", "dev/a.yaml")).To(Equal("a"))
+		Expect(show("main", "dev/b.yaml")).To(Equal("b"))
+		Expect(git(remote, "rev-list", "--count", "main")).To(Equal("2"))
+	})
+
+	It("does not commit content the file already has", func() {
+		Expect(writer.Write(ctx, repository, "config.yaml", []byte("a\n"), "a")).To(Succeed())
+		Expect(gitops.NewWriter().Write(ctx, repository, "config.yaml", []byte("a\n"), "again")).To(Succeed())
+
+		Expect(git(remote, "rev-list", "--count", "main")).To(Equal("1"))
+	})
+
+	It("commits to the branch of the repository", func() {
+		repository.Branch = "env/prod"
+
+		Expect(writer.Write(ctx, repository, "config.yaml", []byte("a\n"), "a")).To(Succeed())
+
+		Expect(show("env/prod", "config.yaml")).To(Equal("a"))
+	})
+
+	DescribeTable("rejects a branch git would not take as a branch name",
+		func(branch string) {
+			repository.Branch = branch
+			err := writer.Write(ctx, repository, "config.yaml", []byte("a\n"), "a")
+			Expect(err).To(MatchError("branch '" + branch + "' is not a valid branch name"))
+			Expect(git(remote, "for-each-ref")).To(BeEmpty())
+		},
+		Entry("an option", "--upload-pack=touch /tmp/x;git-upload-pack"),
+		Entry("a short option", "-v"),
+		Entry("an invalid ref", "env..prod"),
+		Entry("a previous branch", "@{-1}"),
+		Entry("no branch", ""),
+	)
+
+	Context("when the repository is served over http with credentials", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			out, err := exec.Command("git", "--exec-path").Output()
+			Expect(err).NotTo(HaveOccurred())
+			backend := filepath.Join(strings.TrimSpace(string(out)), "git-http-backend")
+			if _, err := os.Stat(backend); err != nil {
+				Skip("git-http-backend is not installed")
+			}
+			git(remote, "config", "http.receivepack", "true")
+
+			handler := &cgi.Handler{
+				Path: backend,
+				Env:  []string{"GIT_PROJECT_ROOT=" + filepath.Dir(remote), "GIT_HTTP_EXPORT_ALL=true"},
+			}
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if username, password, ok := r.BasicAuth(); !ok || username != "bot" || password != "s3cr3t" {
+					w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				handler.ServeHTTP(w, r)
+			}))
+
+			repository.URL = server.URL + "/" + filepath.Base(remote)
+			repository.Username = "bot"
+			repository.Password = "s3cr3t"
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("answers the prompts of git for the credentials", func() {
+			Expect(writer.Write(ctx, repository, "config.yaml", []byte("a\n"), "a")).To(Succeed())
+
+			Expect(show("main", "config.yaml")).To(Equal("a"))
+		})
+
+		It("fails without the right password", func() {
+			repository.Password = "wrong"
+
+			err := writer.Write(ctx, repository, "config.yaml", []byte("a\n"), "a")
+			Expect(err).To(MatchError(HavePrefix("git fetch: ")))
+		})
+	})
+
+	It("rejects a path outside the repository", func() {
+		err := writer.Write(ctx, repository, "../config.yaml", []byte("a\n"), "a")
+		Expect(err).To(MatchError("path '../config.yaml' is not a file in the repository"))
+	})
+
+	It("reports what git failed with, without the password", func() {
+		repository.URL = "https://example.invalid/config.git"
+		repository.Password = "s3cr3t"
+
+		err := writer.Write(ctx, repository, "config.yaml", []byte("a\n"), "a")
+		Expect(err).To(MatchError(HavePrefix("git fetch: ")))
+		Expect(err.Error()).NotTo(ContainSubstring("s3cr3t"))
+	})
+
+	It("does not leave a work tree behind", func() {
+		before, _ := filepath.Glob(filepath.Join(os.TempDir(), "cartographer-gitops-*"))
+		Expect(writer.Write(ctx, repository, "config.yaml", []byte("a\n"), "a")).To(Succeed())
+
+		after, _ := filepath.Glob(filepath.Join(os.TempDir(), "cartographer-gitops-*"))
+		Expect(after).To(ConsistOf(before))
+	})
+})
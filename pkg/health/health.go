@@ -0,0 +1,50 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health provides the checks behind the health and readiness
+// probes of the controller, and the endpoint it is profiled on.
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// CacheSyncTimeout is how long a readiness probe waits for the informers to
+// sync before failing.
+const CacheSyncTimeout = time.Second
+
+// Syncer is the part of the manager's cache that knows whether its
+// informers have synced.
+type Syncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// CacheSynced is ready once the informers of the cache have synced, so
+// that the controllers reconcile from a complete view of the cluster.
+func CacheSynced(cache Syncer) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), CacheSyncTimeout)
+		defer cancel()
+
+		if !cache.WaitForCacheSync(ctx) {
+			return errors.New("informers have not synced")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/cartographer/pkg/health"
+)
+
+type syncer bool
+
+func (s syncer) WaitForCacheSync(ctx context.Context) bool {
+	if !s {
+		<-ctx.Done()
+	}
+	return bool(s)
+}
+
+var _ = Describe("CacheSynced", func() {
+	It("is ready once the informers have synced", func() {
+		check := health.CacheSynced(syncer(true))
+		Expect(check(httptest.NewRequest(http.MethodGet, "/readyz", nil))).To(Succeed())
+	})
+
+	It("is not ready while the informers are syncing", func() {
+		check := health.CacheSynced(syncer(false))
+		Expect(check(httptest.NewRequest(http.MethodGet, "/readyz", nil))).To(MatchError("informers have not synced"))
+	})
+})
+
+var _ = Describe("NewProfilingHandler", func() {
+	It("serves the index of the profiles", func() {
+		recorder := httptest.NewRecorder()
+		health.NewProfilingHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring("goroutine"))
+	})
+})
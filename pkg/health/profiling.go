@@ -0,0 +1,72 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// ProfilingPath is where the pprof profiles are served.
+const ProfilingPath = "/debug/pprof/"
+
+// NewProfilingHandler serves the pprof profiles of the process.
+func NewProfilingHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ProfilingPath, pprof.Index)
+	mux.HandleFunc(ProfilingPath+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(ProfilingPath+"profile", pprof.Profile)
+	mux.HandleFunc(ProfilingPath+"symbol", pprof.Symbol)
+	mux.HandleFunc(ProfilingPath+"trace", pprof.Trace)
+	return mux
+}
+
+// ProfilingServer serves the pprof profiles on an address for as long as
+// the manager it is added to runs, whether or not it is the leader.
+type ProfilingServer struct {
+	Addr string
+}
+
+func (s *ProfilingServer) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *ProfilingServer) Start(ctx context.Context) error {
+	server := &http.Server{Handler: NewProfilingHandler()}
+
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("serve: %w", err)
+	}
+	<-done
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package identity defines the labels that identify every object stamped by
+// cartographer. Tools that clean up after cartographer, such as a janitor
+// run after an incident, can rely on these labels to find stamped objects
+// and to decide whether the owner that stamped them still exists.
+package identity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+const (
+	// OwnerUIDLabel holds the UID of the Workload or Pipeline that stamped
+	// the object.
+	OwnerUIDLabel = "carto.run/owner-uid"
+	// ResourceNameLabel holds the name of the resource within the owner's
+	// blueprint: the supply chain component for a Workload, or RunResourceName
+	// for a Pipeline.
+	ResourceNameLabel = "carto.run/resource-name"
+	// TemplateHashLabel holds the hash of the template the object was
+	// stamped from.
+	TemplateHashLabel = "carto.run/template-hash"
+
+	// OwnerKindLabel, OwnerNameLabel and OwnerNamespaceLabel hold the kind,
+	// name and namespace of the owner, so that its objects can be selected
+	// without knowing its UID, e.g. from a manifest.
+	OwnerKindLabel      = "carto.run/owner-kind"
+	OwnerNameLabel      = "carto.run/owner-name"
+	OwnerNamespaceLabel = "carto.run/owner-namespace"
+	// SupplyChainLabel holds the name of the supply chain that stamped the
+	// object for a Workload.
+	SupplyChainLabel = "carto.run/supply-chain-name"
+	// TemplateKindLabel and TemplateNameLabel hold the kind and name of the
+	// template the object was stamped from.
+	TemplateKindLabel = "carto.run/template-kind"
+	TemplateNameLabel = "carto.run/template-name"
+)
+
+// RunResourceName is the resource name given to the runs stamped by a Pipeline.
+const RunResourceName = "run"
+
+var templateHashPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+type Identity struct {
+	OwnerUID     types.UID
+	ResourceName string
+	TemplateHash string
+
+	// The rest of the identity is descriptive: it is labelled when known,
+	// and not validated.
+	OwnerKind      string
+	OwnerName      string
+	OwnerNamespace string
+	SupplyChain    string
+	TemplateKind   string
+	TemplateName   string
+}
+
+func (i Identity) Labels() map[string]string {
+	labels := map[string]string{
+		OwnerUIDLabel:     string(i.OwnerUID),
+		ResourceNameLabel: i.ResourceName,
+		TemplateHashLabel: i.TemplateHash,
+	}
+	for key, value := range map[string]string{
+		OwnerKindLabel:      i.OwnerKind,
+		OwnerNameLabel:      i.OwnerName,
+		OwnerNamespaceLabel: i.OwnerNamespace,
+		SupplyChainLabel:    i.SupplyChain,
+		TemplateKindLabel:   i.TemplateKind,
+		TemplateNameLabel:   i.TemplateName,
+	} {
+		if value != "" {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// TemplateHash returns a short, label-safe hash of a template.
+func TemplateHash(template v1alpha1.TemplateSpec) (string, error) {
+	// The sample is only stamped at admission.
+	template.Sample = nil
+	raw, err := json.Marshal(template)
+	if err != nil {
+		return "", fmt.Errorf("marshal template: %w", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(raw))[:16], nil
+}
+
+// Apply labels a stamped object with its identity.
+func Apply(obj metav1.Object, identity Identity) {
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	for key, value := range identity.Labels() {
+		objLabels[key] = value
+	}
+	obj.SetLabels(objLabels)
+}
+
+// changingLabels change when the template an object is stamped from is
+// edited, or when another supply chain starts to stamp it, while the object
+// stays the same.
+var changingLabels = []string{TemplateHashLabel, TemplateNameLabel, SupplyChainLabel}
+
+// LookupLabels returns the labels of a stamped object that find it again
+// after its template is edited: its labels other than those that change
+// with the template.
+func LookupLabels(objLabels map[string]string) map[string]string {
+	changing := false
+	for _, key := range changingLabels {
+		if _, ok := objLabels[key]; ok {
+			changing = true
+		}
+	}
+	if !changing {
+		return objLabels
+	}
+
+	lookup := map[string]string{}
+	for key, value := range objLabels {
+		lookup[key] = value
+	}
+	for _, key := range changingLabels {
+		delete(lookup, key)
+	}
+	return lookup
+}
+
+// Of reads the identity of a stamped object, and validates it against the
+// object's controller owner reference or, for an object stamped without a
+// controller, its other owner references. Objects stamped without owner
+// references are only tracked by their labels and do not validate.
+func Of(obj metav1.Object) (Identity, error) {
+	objLabels := obj.GetLabels()
+	identity := Identity{
+		OwnerUID:     types.UID(objLabels[OwnerUIDLabel]),
+		ResourceName: objLabels[ResourceNameLabel],
+		TemplateHash: objLabels[TemplateHashLabel],
+
+		OwnerKind:      objLabels[OwnerKindLabel],
+		OwnerName:      objLabels[OwnerNameLabel],
+		OwnerNamespace: objLabels[OwnerNamespaceLabel],
+		SupplyChain:    objLabels[SupplyChainLabel],
+		TemplateKind:   objLabels[TemplateKindLabel],
+		TemplateName:   objLabels[TemplateNameLabel],
+	}
+
+	if identity.OwnerUID == "" {
+		return Identity{}, fmt.Errorf("missing label '%s'", OwnerUIDLabel)
+	}
+	if identity.ResourceName == "" {
+		return Identity{}, fmt.Errorf("missing label '%s'", ResourceNameLabel)
+	}
+	if !templateHashPattern.MatchString(identity.TemplateHash) {
+		return Identity{}, fmt.Errorf("label '%s' is not a template hash: '%s'", TemplateHashLabel, identity.TemplateHash)
+	}
+
+	if controller := metav1.GetControllerOf(obj); controller != nil {
+		if controller.UID != identity.OwnerUID {
+			return Identity{}, fmt.Errorf("label '%s' does not match controller owner uid '%s'", OwnerUIDLabel, controller.UID)
+		}
+		return identity, nil
+	}
+
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == identity.OwnerUID {
+			return identity, nil
+		}
+	}
+	return Identity{}, fmt.Errorf("missing owner reference to uid '%s'", identity.OwnerUID)
+}
+
+// Selector selects every object labelled with an identity.
+func Selector() labels.Selector {
+	selector := labels.NewSelector()
+	for _, key := range []string{OwnerUIDLabel, ResourceNameLabel, TemplateHashLabel} {
+		requirement, _ := labels.NewRequirement(key, selection.Exists, nil)
+		selector = selector.Add(*requirement)
+	}
+	return selector
+}
+
+// OwnedBy selects the objects stamped for the owner with the uid, for any of
+// its resources when resourceName is empty.
+func OwnedBy(uid types.UID, resourceName string) map[string]string {
+	selector := map[string]string{OwnerUIDLabel: string(uid)}
+	if resourceName != "" {
+		selector[ResourceNameLabel] = resourceName
+	}
+	return selector
+}
+
+// List enumerates the objects of a kind that carry a valid identity. Objects
+// that are labelled but whose identity does not validate are returned
+// separately, so that they are not mistaken for stamped objects.
+func List(ctx context.Context, reader client.Reader, gvk schema.GroupVersionKind, namespace string) (stamped []unstructured.Unstructured, invalid []unstructured.Unstructured, err error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	err = reader.List(ctx, list,
+		client.InNamespace(namespace),
+		client.MatchingLabelsSelector{Selector: Selector()},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list: %w", err)
+	}
+
+	for _, item := range list.Items {
+		if _, err := Of(&item); err != nil {
+			invalid = append(invalid, item)
+			continue
+		}
+		stamped = append(stamped, item)
+	}
+
+	return stamped, invalid, nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identity_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/identity"
+)
+
+var _ = Describe("Identity", func() {
+	var (
+		stamped *unstructured.Unstructured
+		id      identity.Identity
+	)
+
+	BeforeEach(func() {
+		id = identity.Identity{
+			OwnerUID:     "owner-uid",
+			ResourceName: "source-provider",
+			TemplateHash: "0123456789abcdef",
+		}
+
+		stamped = &unstructured.Unstructured{}
+		stamped.SetAPIVersion("v1")
+		stamped.SetKind("ConfigMap")
+		stamped.SetName("stamped")
+		stamped.SetNamespace("ns")
+		stamped.SetLabels(map[string]string{"carto.run/workload-name": "my-workload"})
+		stamped.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: "carto.run/v1alpha1",
+			Kind:       "Workload",
+			Name:       "my-workload",
+			UID:        "owner-uid",
+			Controller: pointer.BoolPtr(true),
+		}})
+	})
+
+	Describe("TemplateHash", func() {
+		It("is stable and label safe", func() {
+			template := v1alpha1.TemplateSpec{Ytt: "some: template"}
+
+			first, err := identity.TemplateHash(template)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := identity.TemplateHash(template)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(first).To(Equal(second))
+			Expect(first).To(MatchRegexp(`^[0-9a-f]{16}$`))
+		})
+
+		It("changes with the template", func() {
+			first, _ := identity.TemplateHash(v1alpha1.TemplateSpec{Ytt: "some: template"})
+			second, _ := identity.TemplateHash(v1alpha1.TemplateSpec{Ytt: "other: template"})
+
+			Expect(first).NotTo(Equal(second))
+		})
+	})
+
+	Describe("Apply", func() {
+		It("adds the identity labels, keeping existing labels", func() {
+			identity.Apply(stamped, id)
+
+			Expect(stamped.GetLabels()).To(Equal(map[string]string{
+				"carto.run/workload-name": "my-workload",
+				"carto.run/owner-uid":     "owner-uid",
+				"carto.run/resource-name": "source-provider",
+				"carto.run/template-hash": "0123456789abcdef",
+			}))
+		})
+
+		It("adds the labels that describe the owner and the template when they are known", func() {
+			id.OwnerKind = "Workload"
+			id.OwnerName = "my-workload"
+			id.OwnerNamespace = "ns"
+			id.SupplyChain = "my-supply-chain"
+			id.TemplateKind = "ClusterTemplate"
+			id.TemplateName = "my-template"
+			identity.Apply(stamped, id)
+
+			Expect(stamped.GetLabels()).To(Equal(map[string]string{
+				"carto.run/workload-name":     "my-workload",
+				"carto.run/owner-uid":         "owner-uid",
+				"carto.run/resource-name":     "source-provider",
+				"carto.run/template-hash":     "0123456789abcdef",
+				"carto.run/owner-kind":        "Workload",
+				"carto.run/owner-name":        "my-workload",
+				"carto.run/owner-namespace":   "ns",
+				"carto.run/supply-chain-name": "my-supply-chain",
+				"carto.run/template-kind":     "ClusterTemplate",
+				"carto.run/template-name":     "my-template",
+			}))
+		})
+	})
+
+	Describe("LookupLabels", func() {
+		It("leaves out the labels that change with the template", func() {
+			id.SupplyChain = "my-supply-chain"
+			id.TemplateKind = "ClusterTemplate"
+			id.TemplateName = "my-template"
+			identity.Apply(stamped, id)
+
+			Expect(identity.LookupLabels(stamped.GetLabels())).To(Equal(map[string]string{
+				"carto.run/workload-name": "my-workload",
+				"carto.run/owner-uid":     "owner-uid",
+				"carto.run/resource-name": "source-provider",
+				"carto.run/template-kind": "ClusterTemplate",
+			}))
+			Expect(stamped.GetLabels()).To(HaveKey("carto.run/template-hash"))
+		})
+
+		It("keeps the labels of an object without an identity", func() {
+			objLabels := map[string]string{"app": "my-app"}
+			Expect(identity.LookupLabels(objLabels)).To(Equal(objLabels))
+		})
+	})
+
+	Describe("Of", func() {
+		It("reads back an applied identity", func() {
+			id.OwnerKind = "Workload"
+			id.OwnerName = "my-workload"
+			id.SupplyChain = "my-supply-chain"
+			identity.Apply(stamped, id)
+
+			Expect(identity.Of(stamped)).To(Equal(id))
+		})
+
+		It("rejects objects without an identity", func() {
+			_, err := identity.Of(stamped)
+			Expect(err).To(MatchError("missing label 'carto.run/owner-uid'"))
+		})
+
+		It("rejects malformed template hashes", func() {
+			id.TemplateHash = "not-a-hash"
+			identity.Apply(stamped, id)
+
+			_, err := identity.Of(stamped)
+			Expect(err).To(MatchError("label 'carto.run/template-hash' is not a template hash: 'not-a-hash'"))
+		})
+
+		It("rejects objects whose controller is not the labelled owner", func() {
+			id.OwnerUID = "some-other-uid"
+			identity.Apply(stamped, id)
+
+			_, err := identity.Of(stamped)
+			Expect(err).To(MatchError("label 'carto.run/owner-uid' does not match controller owner uid 'owner-uid'"))
+		})
+
+		It("accepts objects owned by the labelled owner without it being their controller", func() {
+			stamped.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Workload", Name: "my-workload", UID: "owner-uid"}})
+			identity.Apply(stamped, id)
+
+			Expect(identity.Of(stamped)).To(Equal(id))
+		})
+
+		It("rejects objects without an owner reference to the labelled owner", func() {
+			stamped.SetOwnerReferences(nil)
+			identity.Apply(stamped, id)
+
+			_, err := identity.Of(stamped)
+			Expect(err).To(MatchError("missing owner reference to uid 'owner-uid'"))
+		})
+	})
+
+	Describe("List", func() {
+		var reader client.Reader
+
+		BeforeEach(func() {
+			identity.Apply(stamped, id)
+
+			mislabelled := stamped.DeepCopy()
+			mislabelled.SetName("mislabelled")
+			mislabelled.SetOwnerReferences(nil)
+
+			unlabelled := stamped.DeepCopy()
+			unlabelled.SetName("unlabelled")
+			unlabelled.SetLabels(nil)
+
+			reader = fake.NewClientBuilder().
+				WithScheme(runtime.NewScheme()).
+				WithObjects(stamped, mislabelled, unlabelled).
+				Build()
+		})
+
+		It("enumerates stamped objects, separating those with an invalid identity", func() {
+			valid, invalid, err := identity.List(context.TODO(), reader, schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, "ns")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(valid).To(HaveLen(1))
+			Expect(valid[0].GetName()).To(Equal("stamped"))
+			Expect(invalid).To(HaveLen(1))
+			Expect(invalid[0].GetName()).To(Equal("mislabelled"))
+		})
+	})
+})
@@ -0,0 +1,64 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Submission is what an Interceptor sees around the submission of a stamped
+// object. Interceptors may mutate Object and, after submission, the value
+// Outputs points to.
+type Submission struct {
+	Owner  client.Object              `json:"owner"`
+	Object *unstructured.Unstructured `json:"object"`
+	// Outputs points to the outputs read from the submitted object. It is
+	// nil before submission.
+	Outputs interface{} `json:"outputs,omitempty"`
+}
+
+//counterfeiter:generate . Interceptor
+type Interceptor interface {
+	BeforeSubmit(ctx context.Context, submission *Submission) error
+	AfterSubmit(ctx context.Context, submission *Submission) error
+}
+
+// Chain invokes each of its interceptors in turn, stopping at the first
+// error. An empty Chain does nothing.
+type Chain []Interceptor
+
+func (c Chain) BeforeSubmit(ctx context.Context, submission *Submission) error {
+	for i, interceptor := range c {
+		if err := interceptor.BeforeSubmit(ctx, submission); err != nil {
+			return fmt.Errorf("interceptor %d before submit: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (c Chain) AfterSubmit(ctx context.Context, submission *Submission) error {
+	for i, interceptor := range c {
+		if err := interceptor.AfterSubmit(ctx, submission); err != nil {
+			return fmt.Errorf("interceptor %d after submit: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor/interceptorfakes"
+)
+
+var _ = Describe("Chain", func() {
+	var (
+		first, second *interceptorfakes.FakeInterceptor
+		chain         interceptor.Chain
+		submission    *interceptor.Submission
+	)
+
+	BeforeEach(func() {
+		first = &interceptorfakes.FakeInterceptor{}
+		second = &interceptorfakes.FakeInterceptor{}
+		chain = interceptor.Chain{first, second}
+		submission = &interceptor.Submission{Object: &unstructured.Unstructured{}}
+	})
+
+	It("invokes every interceptor in order", func() {
+		first.BeforeSubmitStub = func(_ context.Context, submission *interceptor.Submission) error {
+			submission.Object.SetName("first")
+			return nil
+		}
+		second.BeforeSubmitStub = func(_ context.Context, submission *interceptor.Submission) error {
+			submission.Object.SetName(submission.Object.GetName() + "-second")
+			return nil
+		}
+
+		Expect(chain.BeforeSubmit(context.TODO(), submission)).To(Succeed())
+		Expect(submission.Object.GetName()).To(Equal("first-second"))
+
+		Expect(chain.AfterSubmit(context.TODO(), submission)).To(Succeed())
+		Expect(first.AfterSubmitCallCount()).To(Equal(1))
+		Expect(second.AfterSubmitCallCount()).To(Equal(1))
+	})
+
+	It("stops at the first error", func() {
+		first.AfterSubmitReturns(errors.New("denied"))
+
+		err := chain.AfterSubmit(context.TODO(), submission)
+		Expect(err).To(MatchError("interceptor 0 after submit: denied"))
+		Expect(second.AfterSubmitCallCount()).To(Equal(0))
+	})
+
+	It("does nothing when empty", func() {
+		Expect(interceptor.Chain{}.BeforeSubmit(context.TODO(), submission)).To(Succeed())
+		Expect(interceptor.Chain(nil).AfterSubmit(context.TODO(), submission)).To(Succeed())
+	})
+})
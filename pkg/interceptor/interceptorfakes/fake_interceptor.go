@@ -0,0 +1,190 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package interceptorfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+)
+
+type FakeInterceptor struct {
+	AfterSubmitStub        func(context.Context, *interceptor.Submission) error
+	afterSubmitMutex       sync.RWMutex
+	afterSubmitArgsForCall []struct {
+		arg1 context.Context
+		arg2 *interceptor.Submission
+	}
+	afterSubmitReturns struct {
+		result1 error
+	}
+	afterSubmitReturnsOnCall map[int]struct {
+		result1 error
+	}
+	BeforeSubmitStub        func(context.Context, *interceptor.Submission) error
+	beforeSubmitMutex       sync.RWMutex
+	beforeSubmitArgsForCall []struct {
+		arg1 context.Context
+		arg2 *interceptor.Submission
+	}
+	beforeSubmitReturns struct {
+		result1 error
+	}
+	beforeSubmitReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeInterceptor) AfterSubmit(arg1 context.Context, arg2 *interceptor.Submission) error {
+	fake.afterSubmitMutex.Lock()
+	ret, specificReturn := fake.afterSubmitReturnsOnCall[len(fake.afterSubmitArgsForCall)]
+	fake.afterSubmitArgsForCall = append(fake.afterSubmitArgsForCall, struct {
+		arg1 context.Context
+		arg2 *interceptor.Submission
+	}{arg1, arg2})
+	stub := fake.AfterSubmitStub
+	fakeReturns := fake.afterSubmitReturns
+	fake.recordInvocation("AfterSubmit", []interface{}{arg1, arg2})
+	fake.afterSubmitMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeInterceptor) AfterSubmitCallCount() int {
+	fake.afterSubmitMutex.RLock()
+	defer fake.afterSubmitMutex.RUnlock()
+	return len(fake.afterSubmitArgsForCall)
+}
+
+func (fake *FakeInterceptor) AfterSubmitCalls(stub func(context.Context, *interceptor.Submission) error) {
+	fake.afterSubmitMutex.Lock()
+	defer fake.afterSubmitMutex.Unlock()
+	fake.AfterSubmitStub = stub
+}
+
+func (fake *FakeInterceptor) AfterSubmitArgsForCall(i int) (context.Context, *interceptor.Submission) {
+	fake.afterSubmitMutex.RLock()
+	defer fake.afterSubmitMutex.RUnlock()
+	argsForCall := fake.afterSubmitArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInterceptor) AfterSubmitReturns(result1 error) {
+	fake.afterSubmitMutex.Lock()
+	defer fake.afterSubmitMutex.Unlock()
+	fake.AfterSubmitStub = nil
+	fake.afterSubmitReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeInterceptor) AfterSubmitReturnsOnCall(i int, result1 error) {
+	fake.afterSubmitMutex.Lock()
+	defer fake.afterSubmitMutex.Unlock()
+	fake.AfterSubmitStub = nil
+	if fake.afterSubmitReturnsOnCall == nil {
+		fake.afterSubmitReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.afterSubmitReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeInterceptor) BeforeSubmit(arg1 context.Context, arg2 *interceptor.Submission) error {
+	fake.beforeSubmitMutex.Lock()
+	ret, specificReturn := fake.beforeSubmitReturnsOnCall[len(fake.beforeSubmitArgsForCall)]
+	fake.beforeSubmitArgsForCall = append(fake.beforeSubmitArgsForCall, struct {
+		arg1 context.Context
+		arg2 *interceptor.Submission
+	}{arg1, arg2})
+	stub := fake.BeforeSubmitStub
+	fakeReturns := fake.beforeSubmitReturns
+	fake.recordInvocation("BeforeSubmit", []interface{}{arg1, arg2})
+	fake.beforeSubmitMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeInterceptor) BeforeSubmitCallCount() int {
+	fake.beforeSubmitMutex.RLock()
+	defer fake.beforeSubmitMutex.RUnlock()
+	return len(fake.beforeSubmitArgsForCall)
+}
+
+func (fake *FakeInterceptor) BeforeSubmitCalls(stub func(context.Context, *interceptor.Submission) error) {
+	fake.beforeSubmitMutex.Lock()
+	defer fake.beforeSubmitMutex.Unlock()
+	fake.BeforeSubmitStub = stub
+}
+
+func (fake *FakeInterceptor) BeforeSubmitArgsForCall(i int) (context.Context, *interceptor.Submission) {
+	fake.beforeSubmitMutex.RLock()
+	defer fake.beforeSubmitMutex.RUnlock()
+	argsForCall := fake.beforeSubmitArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInterceptor) BeforeSubmitReturns(result1 error) {
+	fake.beforeSubmitMutex.Lock()
+	defer fake.beforeSubmitMutex.Unlock()
+	fake.BeforeSubmitStub = nil
+	fake.beforeSubmitReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeInterceptor) BeforeSubmitReturnsOnCall(i int, result1 error) {
+	fake.beforeSubmitMutex.Lock()
+	defer fake.beforeSubmitMutex.Unlock()
+	fake.BeforeSubmitStub = nil
+	if fake.beforeSubmitReturnsOnCall == nil {
+		fake.beforeSubmitReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.beforeSubmitReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeInterceptor) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.afterSubmitMutex.RLock()
+	defer fake.afterSubmitMutex.RUnlock()
+	fake.beforeSubmitMutex.RLock()
+	defer fake.beforeSubmitMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeInterceptor) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ interceptor.Interceptor = new(FakeInterceptor)
@@ -0,0 +1,114 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	BeforeSubmitPhase = "BeforeSubmit"
+	AfterSubmitPhase  = "AfterSubmit"
+)
+
+type webhookRequest struct {
+	Phase string `json:"phase"`
+	*Submission
+}
+
+// webhookResponse carries the replacements for the submission. Fields the
+// webhook leaves out are not changed.
+type webhookResponse struct {
+	Object  *unstructured.Unstructured `json:"object,omitempty"`
+	Outputs json.RawMessage            `json:"outputs,omitempty"`
+}
+
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook returns an Interceptor that POSTs each submission as JSON to
+// url, and applies the object and outputs returned in the response.
+func NewWebhook(url string, client *http.Client) Interceptor {
+	return &webhook{
+		url:    url,
+		client: client,
+	}
+}
+
+func (w *webhook) BeforeSubmit(ctx context.Context, submission *Submission) error {
+	return w.call(ctx, BeforeSubmitPhase, submission)
+}
+
+func (w *webhook) AfterSubmit(ctx context.Context, submission *Submission) error {
+	return w.call(ctx, AfterSubmitPhase, submission)
+}
+
+func (w *webhook) call(ctx context.Context, phase string, submission *Submission) error {
+	body, err := json.Marshal(webhookRequest{Phase: phase, Submission: submission})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("webhook responded %d: %s", response.StatusCode, responseBody)
+	}
+
+	if len(responseBody) == 0 {
+		return nil
+	}
+
+	result := webhookResponse{}
+	if err := json.Unmarshal(responseBody, &result); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	if result.Object != nil {
+		*submission.Object = *result.Object
+	}
+
+	if len(result.Outputs) > 0 && submission.Outputs != nil {
+		if err := json.Unmarshal(result.Outputs, submission.Outputs); err != nil {
+			return fmt.Errorf("unmarshal outputs: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptor_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+var _ = Describe("Webhook", func() {
+	var (
+		server     *httptest.Server
+		received   map[string]interface{}
+		status     int
+		response   string
+		webhook    interceptor.Interceptor
+		submission *interceptor.Submission
+		stampedObj *unstructured.Unstructured
+		outputs    templates.Outputs
+	)
+
+	BeforeEach(func() {
+		received = nil
+		status = http.StatusOK
+		response = ""
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(body, &received)).To(Succeed())
+
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(response))
+		}))
+
+		webhook = interceptor.NewWebhook(server.URL, server.Client())
+
+		stampedObj = &unstructured.Unstructured{}
+		stampedObj.SetAPIVersion("v1")
+		stampedObj.SetKind("ConfigMap")
+		stampedObj.SetName("my-config")
+
+		submission = &interceptor.Submission{
+			Owner:  &v1alpha1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline"}},
+			Object: stampedObj,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("posts the phase, owner and object", func() {
+		Expect(webhook.BeforeSubmit(context.TODO(), submission)).To(Succeed())
+
+		Expect(received["phase"]).To(Equal("BeforeSubmit"))
+		Expect(received["owner"]).To(HaveKeyWithValue("metadata", HaveKeyWithValue("name", "my-pipeline")))
+		Expect(received["object"]).To(HaveKeyWithValue("kind", "ConfigMap"))
+	})
+
+	It("leaves the submission alone when the response is empty", func() {
+		Expect(webhook.BeforeSubmit(context.TODO(), submission)).To(Succeed())
+		Expect(submission.Object.GetName()).To(Equal("my-config"))
+	})
+
+	It("replaces the object with the one in the response", func() {
+		response = `{"object": {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "renamed"}}}`
+
+		Expect(webhook.BeforeSubmit(context.TODO(), submission)).To(Succeed())
+		Expect(stampedObj.GetName()).To(Equal("renamed"))
+	})
+
+	It("replaces the outputs with the ones in the response", func() {
+		outputs = templates.Outputs{"url": apiextensionsv1.JSON{Raw: []byte(`"before"`)}}
+		submission.Outputs = &outputs
+		response = `{"outputs": {"url": "after"}}`
+
+		Expect(webhook.AfterSubmit(context.TODO(), submission)).To(Succeed())
+
+		Expect(received["phase"]).To(Equal("AfterSubmit"))
+		Expect(received["outputs"]).To(Equal(map[string]interface{}{"url": "before"}))
+		Expect(outputs["url"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"after"`)}))
+	})
+
+	It("returns an error when the webhook does not respond OK", func() {
+		status = http.StatusForbidden
+		response = "not allowed"
+
+		err := webhook.BeforeSubmit(context.TODO(), submission)
+		Expect(err).To(MatchError("webhook responded 403: not allowed"))
+	})
+
+	It("returns an error when the response cannot be read", func() {
+		response = "not json"
+
+		err := webhook.BeforeSubmit(context.TODO(), submission)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unmarshal response"))
+	})
+})
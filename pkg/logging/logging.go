@@ -0,0 +1,175 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging tunes the verbosity of the controller's logs per
+// subsystem, so that one part of the controller can be troubleshot without
+// turning on verbose logs for all of it.
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// The subsystems whose verbosity can be set apart. A subsystem's loggers are
+// named after it.
+const (
+	Realizer   = "realizer"
+	Repository = "repository"
+	Webhook    = "webhook"
+	Selectors  = "selectors"
+)
+
+// Subsystems lists the subsystems whose verbosity can be set apart.
+var Subsystems = []string{Realizer, Repository, Webhook, Selectors}
+
+// StampedObjects names the logger the contents of stamped objects are
+// logged to, which is disabled outside of the debug mode.
+const StampedObjects = "stamped-objects"
+
+// Levels are the verbosity of each subsystem, as parsed from
+// subsystem=level,... e.g. realizer=2,repository=1.
+type Levels map[string]int
+
+func (l Levels) String() string {
+	var pairs []string
+	for subsystem, level := range l {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", subsystem, level))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (l *Levels) Set(value string) error {
+	if *l == nil {
+		*l = Levels{}
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("expected subsystem=level but got '%s'", pair)
+		}
+		subsystem := strings.TrimSpace(parts[0])
+		if !isSubsystem(subsystem) {
+			return fmt.Errorf("unknown subsystem '%s', expected one of %s", subsystem, strings.Join(Subsystems, ", "))
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return fmt.Errorf("level of subsystem '%s': %w", subsystem, err)
+		}
+		(*l)[subsystem] = level
+	}
+	return nil
+}
+
+func isSubsystem(name string) bool {
+	for _, subsystem := range Subsystems {
+		if name == subsystem {
+			return true
+		}
+	}
+	return false
+}
+
+// Settings are the verbosity of the controller's logs.
+type Settings struct {
+	// Level is the verbosity of the loggers outside of the subsystems, and
+	// of the subsystems missing from Levels.
+	Level int
+	// Levels are the verbosity of each subsystem.
+	Levels Levels
+	// StampedObjects logs the contents of every object stamped, with the
+	// data of secrets redacted.
+	StampedObjects bool
+}
+
+// MaxLevel is the highest verbosity of any logger, which the underlying
+// logger must enable.
+func (s Settings) MaxLevel() int {
+	max := s.Level
+	for _, level := range s.Levels {
+		if level > max {
+			max = level
+		}
+	}
+	return max
+}
+
+// NewLogger filters the logs written to delegate by the verbosity of the
+// subsystem of each logger. A logger belongs to the subsystem it, or its
+// closest ancestor, is named after. Errors are always logged.
+func NewLogger(delegate logr.Logger, settings Settings) logr.Logger {
+	return &filteredLogger{delegate: delegate, settings: settings, level: settings.Level}
+}
+
+type filteredLogger struct {
+	delegate logr.Logger
+	settings Settings
+	// level is the highest verbosity logged by the subsystem of the logger,
+	// and verbosity is the verbosity of the logger itself.
+	level     int
+	verbosity int
+}
+
+func (l *filteredLogger) Enabled() bool {
+	return l.verbosity <= l.level && l.delegate.Enabled()
+}
+
+func (l *filteredLogger) Info(msg string, keysAndValues ...interface{}) {
+	if l.verbosity <= l.level {
+		l.delegate.Info(msg, keysAndValues...)
+	}
+}
+
+func (l *filteredLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.delegate.Error(err, msg, keysAndValues...)
+}
+
+func (l *filteredLogger) V(level int) logr.Logger {
+	v := *l
+	v.delegate = l.delegate.V(level)
+	v.verbosity += level
+	return &v
+}
+
+func (l *filteredLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	v := *l
+	v.delegate = l.delegate.WithValues(keysAndValues...)
+	return &v
+}
+
+func (l *filteredLogger) WithName(name string) logr.Logger {
+	v := *l
+	v.delegate = l.delegate.WithName(name)
+	if level, ok := l.settings.Levels[name]; ok {
+		v.level = level
+	} else if isSubsystem(name) {
+		v.level = l.settings.Level
+	} else if name == StampedObjects {
+		v.level = -1
+		if l.settings.StampedObjects {
+			v.level = 0
+		}
+	}
+	return &v
+}
@@ -0,0 +1,165 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/logging"
+)
+
+// recordingLogger records the lines it logs as name: message, with every
+// verbosity enabled.
+type recordingLogger struct {
+	lines  *[]string
+	name   string
+	values []interface{}
+}
+
+func (l *recordingLogger) Enabled() bool { return true }
+
+func (l *recordingLogger) Info(msg string, keysAndValues ...interface{}) {
+	*l.lines = append(*l.lines, fmt.Sprintf("%s: %s %v", l.name, msg, append(l.values, keysAndValues...)))
+}
+
+func (l *recordingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	*l.lines = append(*l.lines, fmt.Sprintf("%s: %s: %s", l.name, msg, err))
+}
+
+func (l *recordingLogger) V(int) logr.Logger { return l }
+
+func (l *recordingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return &recordingLogger{lines: l.lines, name: l.name, values: append(append([]interface{}{}, l.values...), keysAndValues...)}
+}
+
+func (l *recordingLogger) WithName(name string) logr.Logger {
+	return &recordingLogger{lines: l.lines, name: strings.TrimPrefix(l.name+"."+name, "."), values: l.values}
+}
+
+var _ = Describe("Levels", func() {
+	It("parses the level of each subsystem", func() {
+		var levels logging.Levels
+		Expect(levels.Set("realizer=2, repository=1")).To(Succeed())
+		Expect(levels.Set("webhook=3")).To(Succeed())
+
+		Expect(levels).To(Equal(logging.Levels{"realizer": 2, "repository": 1, "webhook": 3}))
+		Expect(levels.String()).To(Equal("realizer=2,repository=1,webhook=3"))
+	})
+
+	It("rejects an unknown subsystem", func() {
+		var levels logging.Levels
+		Expect(levels.Set("reconciler=2")).To(MatchError(ContainSubstring("unknown subsystem 'reconciler'")))
+	})
+
+	It("rejects a level that is not a number", func() {
+		var levels logging.Levels
+		Expect(levels.Set("realizer=high")).To(MatchError(ContainSubstring("level of subsystem 'realizer'")))
+		Expect(levels.Set("realizer")).To(MatchError(ContainSubstring("expected subsystem=level")))
+	})
+})
+
+var _ = Describe("NewLogger", func() {
+	var (
+		lines  []string
+		logger logr.Logger
+	)
+
+	BeforeEach(func() {
+		lines = nil
+		logger = logging.NewLogger(&recordingLogger{lines: &lines}, logging.Settings{
+			Level:  0,
+			Levels: logging.Levels{logging.Realizer: 2, logging.Webhook: -1},
+		})
+	})
+
+	It("logs at the verbosity of the subsystem a logger is named after", func() {
+		realizer := logger.WithName("workload").WithName(logging.Realizer)
+		realizer.V(2).Info("stamping")
+		realizer.V(3).Info("ytt call")
+		realizer.WithName("component").V(1).Info("realized")
+
+		Expect(lines).To(Equal([]string{
+			"workload.realizer: stamping []",
+			"workload.realizer.component: realized []",
+		}))
+		Expect(realizer.V(2).Enabled()).To(BeTrue())
+		Expect(realizer.V(3).Enabled()).To(BeFalse())
+	})
+
+	It("logs at the default verbosity outside of the subsystems", func() {
+		logger.WithName("workload").Info("started")
+		logger.WithName("workload").V(1).Info("details")
+		logger.WithName(logging.Repository).V(1).Info("created object")
+
+		Expect(lines).To(Equal([]string{"workload: started []"}))
+	})
+
+	It("always logs errors", func() {
+		logger.WithName(logging.Webhook).Info("admitted")
+		logger.WithName(logging.Webhook).V(4).Error(errors.New("boom"), "could not validate")
+
+		Expect(lines).To(Equal([]string{"webhook: could not validate: boom"}))
+	})
+
+	It("logs at the verbosity of the innermost subsystem", func() {
+		logger.WithName(logging.Realizer).WithName(logging.Repository).V(1).Info("created object")
+
+		Expect(lines).To(BeEmpty())
+	})
+})
+
+var _ = Describe("LogStampedObject", func() {
+	var (
+		lines []string
+		obj   *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		lines = nil
+		obj = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": "credentials"},
+			"data":       map[string]interface{}{"password": "aHVudGVyMg=="},
+			"stringData": map[string]interface{}{"token": "hunter2"},
+		}}
+	})
+
+	It("logs nothing outside of the debug mode", func() {
+		logger := logging.NewLogger(&recordingLogger{lines: &lines}, logging.Settings{Level: 5})
+		logging.LogStampedObject(logger, obj)
+
+		Expect(lines).To(BeEmpty())
+	})
+
+	It("logs the stamped object with the values of a secret redacted", func() {
+		logger := logging.NewLogger(&recordingLogger{lines: &lines}, logging.Settings{StampedObjects: true})
+		logging.LogStampedObject(logger.WithName(logging.Realizer), obj)
+
+		Expect(lines).To(HaveLen(1))
+		Expect(lines[0]).To(HavePrefix("realizer.stamped-objects: stamped object [kind Secret name credentials"))
+		Expect(lines[0]).To(ContainSubstring("password:<redacted>"))
+		Expect(lines[0]).To(ContainSubstring("token:<redacted>"))
+		Expect(lines[0]).NotTo(ContainSubstring("hunter2"))
+		Expect(obj.Object["stringData"]).To(Equal(map[string]interface{}{"token": "hunter2"}))
+	})
+})
@@ -0,0 +1,46 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const redacted = "<redacted>"
+
+// LogStampedObject logs the content of an object stamped for a component or
+// a run in the debug mode. The values of a secret are redacted.
+func LogStampedObject(logger logr.Logger, obj *unstructured.Unstructured) {
+	logger = logger.WithName(StampedObjects)
+	if !logger.Enabled() {
+		return
+	}
+
+	content := obj.DeepCopy().Object
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			values, ok := content[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for key := range values {
+				values[key] = redacted
+			}
+		}
+	}
+
+	logger.Info("stamped object", "kind", obj.GetKind(), "name", obj.GetName(), "object", content)
+}
@@ -0,0 +1,423 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oci publishes the manifests of stamped objects as OCI artifacts:
+// images of a single layer holding the manifests, which imgpkg, ORAS, Flux
+// and kapp-controller pull. Registries are written with the OCI
+// distribution API.
+package oci
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	ManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ConfigMediaType   = "application/vnd.oci.image.config.v1+json"
+	LayerMediaType    = "application/vnd.oci.image.layer.v1.tar+gzip"
+)
+
+// Credentials are the username and password, or token, a registry is
+// written with.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+//counterfeiter:generate . Publisher
+type Publisher interface {
+	// Publish pushes the files, by their path in the artifact, as the
+	// image, such as registry.example.com/team/app-config:latest, and
+	// returns the digest of the artifact pushed.
+	Publish(ctx context.Context, image string, files map[string][]byte, credentials Credentials) (string, error)
+}
+
+// NewPublisher pushes artifacts with the client. Artifacts are built
+// reproducibly, so the same files have the same digest, and the publisher
+// remembers what it last pushed as each image, so that unchanged files are
+// not pushed again every time an owner is reconciled.
+func NewPublisher(client *http.Client) Publisher {
+	return &publisher{client: client, pushed: map[string]pushed{}}
+}
+
+type pushed struct {
+	layer  [sha256.Size]byte
+	digest string
+}
+
+type publisher struct {
+	client *http.Client
+
+	mu     sync.Mutex
+	pushed map[string]pushed
+}
+
+func (p *publisher) Publish(ctx context.Context, image string, files map[string][]byte, credentials Credentials) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+
+	layer, diffID, err := archive(files)
+	if err != nil {
+		return "", fmt.Errorf("archive files: %w", err)
+	}
+	layerDigest := sha256.Sum256(layer)
+	p.mu.Lock()
+	last, ok := p.pushed[image]
+	p.mu.Unlock()
+	if ok && last.layer == layerDigest {
+		return last.digest, nil
+	}
+
+	config, err := json.Marshal(map[string]interface{}{
+		"architecture": "",
+		"os":           "",
+		"rootfs":       map[string]interface{}{"type": "layers", "diff_ids": []string{diffID}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal config: %w", err)
+	}
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     ManifestMediaType,
+		"config":        descriptor(ConfigMediaType, config),
+		"layers":        []interface{}{descriptor(LayerMediaType, layer)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	s := &session{client: p.client, ref: ref, credentials: credentials}
+	for _, blob := range [][]byte{config, layer} {
+		if err := s.pushBlob(ctx, blob); err != nil {
+			return "", err
+		}
+	}
+	if err := s.pushManifest(ctx, manifest); err != nil {
+		return "", err
+	}
+
+	digest := digestOf(manifest)
+	p.mu.Lock()
+	p.pushed[image] = pushed{layer: layerDigest, digest: digest}
+	p.mu.Unlock()
+	return digest, nil
+}
+
+// Reference is where in a registry an artifact is pushed.
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+}
+
+// Name is the reference without its tag. An artifact is referred to by its
+// digest, as Name()@sha256:...
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// ParseReference parses an image reference as docker does: an image without
+// a registry is on Docker Hub, and one without a tag is tagged latest.
+func ParseReference(image string) (Reference, error) {
+	invalid := fmt.Errorf("invalid image reference '%s'", image)
+	if image == "" || strings.ContainsAny(image, " @") {
+		return Reference{}, invalid
+	}
+
+	ref := Reference{Registry: "docker.io", Repository: image, Tag: "latest"}
+	if i := strings.Index(image, "/"); i >= 0 {
+		if host := image[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Registry, ref.Repository = host, image[i+1:]
+		}
+	}
+	if i := strings.LastIndex(ref.Repository, ":"); i >= 0 {
+		ref.Repository, ref.Tag = ref.Repository[:i], ref.Repository[i+1:]
+	}
+	if ref.Registry == "docker.io" && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Repository == "" || ref.Tag == "" || ref.Repository != strings.ToLower(ref.Repository) {
+		return Reference{}, invalid
+	}
+	return ref, nil
+}
+
+// archive is a reproducible gzipped tar of the files, with the digest of the
+// tar, which is the diff ID of the layer.
+func archive(files map[string][]byte) ([]byte, string, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var tarred bytes.Buffer
+	tw := tar.NewWriter(&tarred)
+	for _, path := range paths {
+		header := &tar.Header{Name: path, Mode: 0o644, Size: int64(len(files[path])), Typeflag: tar.TypeReg, Format: tar.FormatPAX}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, "", err
+		}
+		if _, err := tw.Write(files[path]); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, "", err
+	}
+
+	var zipped bytes.Buffer
+	zw := gzip.NewWriter(&zipped)
+	if _, err := zw.Write(tarred.Bytes()); err != nil {
+		return nil, "", err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", err
+	}
+	return zipped.Bytes(), digestOf(tarred.Bytes()), nil
+}
+
+func descriptor(mediaType string, content []byte) map[string]interface{} {
+	return map[string]interface{}{"mediaType": mediaType, "digest": digestOf(content), "size": len(content)}
+}
+
+func digestOf(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
+
+// session pushes to a repository, authenticating as the registry asks: with
+// the credentials, or with a token its authorization service issues for
+// them.
+type session struct {
+	client      *http.Client
+	ref         Reference
+	credentials Credentials
+	token       string
+}
+
+func (s *session) pushBlob(ctx context.Context, blob []byte) error {
+	digest := digestOf(blob)
+	resp, err := s.do(ctx, http.MethodHead, s.url("/blobs/"+digest), "", nil)
+	if err != nil {
+		return fmt.Errorf("check blob: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = s.do(ctx, http.MethodPost, s.url("/blobs/uploads/"), "", nil)
+	if err != nil {
+		return fmt.Errorf("start upload: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("start upload: %s", status(resp))
+	}
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("start upload: location: %w", err)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	upload, err := s.do(ctx, http.MethodPut, location.String(), "application/octet-stream", blob)
+	if err != nil {
+		return fmt.Errorf("upload blob: %w", err)
+	}
+	defer upload.Body.Close()
+	if upload.StatusCode != http.StatusCreated {
+		return fmt.Errorf("upload blob: %s", status(upload))
+	}
+	return nil
+}
+
+func (s *session) pushManifest(ctx context.Context, manifest []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.url("/manifests/"+s.ref.Tag), ManifestMediaType, manifest)
+	if err != nil {
+		return fmt.Errorf("push manifest: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("push manifest: %s", status(resp))
+	}
+	return nil
+}
+
+func (s *session) url(path string) string {
+	registry := s.ref.Registry
+	if registry == "docker.io" {
+		registry = "registry-1.docker.io"
+	}
+	return "https://" + registry + "/v2/" + s.ref.Repository + path
+}
+
+// do sends the request, and sends it again with a token when the registry
+// challenges it for one.
+func (s *session) do(ctx context.Context, method, target, contentType string, body []byte) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		switch {
+		case s.token != "":
+			req.Header.Set("Authorization", "Bearer "+s.token)
+		case s.credentials != (Credentials{}):
+			req.SetBasicAuth(s.credentials.Username, s.credentials.Password)
+		}
+		return s.client.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || s.token != "" {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, errors.New("unauthorized")
+	}
+	if s.token, err = s.fetchToken(ctx, challenge[len("bearer "):]); err != nil {
+		return nil, err
+	}
+	return send()
+}
+
+func (s *session) fetchToken(ctx context.Context, challenge string) (string, error) {
+	params := parseChallenge(challenge)
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("token realm '%s' is not a URL", params["realm"])
+	}
+	query := realm.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull,push", s.ref.Repository))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if s.credentials != (Credentials{}) {
+		req.SetBasicAuth(s.credentials.Username, s.credentials.Password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch token: %s", status(resp))
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("fetch token: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return token.Token, nil
+}
+
+// parseChallenge parses the key="value" params of a WWW-Authenticate
+// challenge.
+func parseChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+	for _, param := range strings.Split(challenge, ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 {
+			params[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return params
+}
+
+func status(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if msg := strings.TrimSpace(string(body)); msg != "" {
+		return resp.Status + ": " + msg
+	}
+	return resp.Status
+}
+
+// DockerConfigCredentials are the credentials of the registry in the
+// .dockerconfigjson of a Secret of type kubernetes.io/dockerconfigjson, or
+// none when it has none for the registry.
+func DockerConfigCredentials(dockerConfig []byte, registry string) (Credentials, error) {
+	var config struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(dockerConfig, &config); err != nil {
+		return Credentials{}, fmt.Errorf("unmarshal docker config: %w", err)
+	}
+
+	hosts := []string{registry}
+	if registry == "docker.io" {
+		hosts = append(hosts, "index.docker.io", "https://index.docker.io/v1/", "registry-1.docker.io")
+	}
+	for key, auth := range config.Auths {
+		host := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://"), "/")
+		for _, candidate := range hosts {
+			if key != candidate && host != candidate {
+				continue
+			}
+			if auth.Auth == "" {
+				return Credentials{Username: auth.Username, Password: auth.Password}, nil
+			}
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return Credentials{}, fmt.Errorf("decode auth of '%s': %w", key, err)
+			}
+			userPassword := strings.SplitN(string(decoded), ":", 2)
+			if len(userPassword) != 2 {
+				return Credentials{}, fmt.Errorf("auth of '%s' is not username:password", key)
+			}
+			return Credentials{Username: userPassword[0], Password: userPassword[1]}, nil
+		}
+	}
+	return Credentials{}, nil
+}
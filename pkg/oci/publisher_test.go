@@ -0,0 +1,213 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oci_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/cartographer/pkg/oci"
+)
+
+// registry is the part of the OCI distribution API artifacts are pushed
+// with, optionally behind token authentication.
+type registry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	requests  int
+
+	username, password string
+}
+
+func (r *registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests++
+
+	if req.URL.Path == "/token" {
+		if username, password, _ := req.BasicAuth(); username != r.username || password != r.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "good"})
+		return
+	}
+	if r.username != "" && req.Header.Get("Authorization") != "Bearer good" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="registry"`, req.Host))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	path := strings.TrimPrefix(req.URL.Path, "/v2/team/app-config")
+	switch {
+	case req.Method == http.MethodHead && strings.HasPrefix(path, "/blobs/"):
+		if _, ok := r.blobs[strings.TrimPrefix(path, "/blobs/")]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case req.Method == http.MethodPost && path == "/blobs/uploads/":
+		w.Header().Set("Location", "/v2/team/app-config/blobs/uploads/1?state=x")
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == http.MethodPut && strings.HasPrefix(path, "/blobs/uploads/"):
+		digest := req.URL.Query().Get("digest")
+		if digest != fmt.Sprintf("sha256:%x", sha256.Sum256(body)) || req.URL.Query().Get("state") != "x" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.blobs[digest] = body
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPut && strings.HasPrefix(path, "/manifests/"):
+		r.manifests[strings.TrimPrefix(path, "/manifests/")] = body
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+var _ = Describe("Publisher", func() {
+	var (
+		ctx       context.Context
+		reg       *registry
+		server    *httptest.Server
+		image     string
+		publisher oci.Publisher
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reg = &registry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+		server = httptest.NewTLSServer(reg)
+		image = strings.TrimPrefix(server.URL, "https://") + "/team/app-config:v1"
+		publisher = oci.NewPublisher(server.Client())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("pushes the files as an artifact of a single layer, and returns its digest", func() {
+		digest, err := publisher.Publish(ctx, image, map[string][]byte{"config.yaml": []byte("kind: ConfigMap\n")}, oci.Credentials{})
+		Expect(err).NotTo(HaveOccurred())
+
+		manifest := reg.manifests["v1"]
+		Expect(digest).To(Equal(fmt.Sprintf("sha256:%x", sha256.Sum256(manifest))))
+
+		var parsed struct {
+			MediaType string
+			Layers    []struct{ Digest string }
+		}
+		Expect(json.Unmarshal(manifest, &parsed)).To(Succeed())
+		Expect(parsed.MediaType).To(Equal(oci.ManifestMediaType))
+		Expect(parsed.Layers).To(HaveLen(1))
+
+		zr, err := gzip.NewReader(strings.NewReader(string(reg.blobs[parsed.Layers[0].Digest])))
+		Expect(err).NotTo(HaveOccurred())
+		tr := tar.NewReader(zr)
+		header, err := tr.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(header.Name).To(Equal("config.yaml"))
+		content, _ := io.ReadAll(tr)
+		Expect(string(content)).To(Equal("kind: ConfigMap\n"))
+	})
+
+	It("pushes the same files as the same digest", func() {
+		files := map[string][]byte{"a.yaml": []byte("a"), "b.yaml": []byte("b")}
+		first, err := publisher.Publish(ctx, image, files, oci.Credentials{})
+		Expect(err).NotTo(HaveOccurred())
+
+		second, err := oci.NewPublisher(server.Client()).Publish(ctx, image, files, oci.Credentials{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second).To(Equal(first))
+	})
+
+	It("does not push files it pushed last", func() {
+		files := map[string][]byte{"config.yaml": []byte("a")}
+		_, err := publisher.Publish(ctx, image, files, oci.Credentials{})
+		Expect(err).NotTo(HaveOccurred())
+		requests := reg.requests
+
+		_, err = publisher.Publish(ctx, image, files, oci.Credentials{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reg.requests).To(Equal(requests))
+	})
+
+	Context("when the registry asks for a token", func() {
+		BeforeEach(func() {
+			reg.username, reg.password = "bot", "s3cr3t"
+		})
+
+		It("pushes with a token issued for the credentials", func() {
+			_, err := publisher.Publish(ctx, image, map[string][]byte{"config.yaml": []byte("a")}, oci.Credentials{Username: "bot", Password: "s3cr3t"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reg.manifests).To(HaveKey("v1"))
+		})
+
+		It("fails without the credentials", func() {
+			_, err := publisher.Publish(ctx, image, map[string][]byte{"config.yaml": []byte("a")}, oci.Credentials{})
+			Expect(err).To(MatchError(ContainSubstring("fetch token: 401 Unauthorized")))
+		})
+	})
+})
+
+var _ = Describe("ParseReference", func() {
+	DescribeTable("parses references as docker does",
+		func(image string, expected oci.Reference) {
+			Expect(oci.ParseReference(image)).To(Equal(expected))
+		},
+		Entry("registry, repository and tag", "registry.example.com:5000/team/app:v1", oci.Reference{Registry: "registry.example.com:5000", Repository: "team/app", Tag: "v1"}),
+		Entry("no tag", "registry.example.com/team/app", oci.Reference{Registry: "registry.example.com", Repository: "team/app", Tag: "latest"}),
+		Entry("docker hub", "team/app:v1", oci.Reference{Registry: "docker.io", Repository: "team/app", Tag: "v1"}),
+		Entry("docker hub library", "app", oci.Reference{Registry: "docker.io", Repository: "library/app", Tag: "latest"}),
+	)
+
+	It("rejects a reference with a digest", func() {
+		_, err := oci.ParseReference("registry.example.com/app@sha256:abc")
+		Expect(err).To(MatchError("invalid image reference 'registry.example.com/app@sha256:abc'"))
+	})
+})
+
+var _ = Describe("DockerConfigCredentials", func() {
+	It("reads the username and password of the registry", func() {
+		credentials, err := oci.DockerConfigCredentials([]byte(`{"auths":{"https://registry.example.com":{"username":"bot","password":"s3cr3t"}}}`), "registry.example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credentials).To(Equal(oci.Credentials{Username: "bot", Password: "s3cr3t"}))
+	})
+
+	It("reads the auth of Docker Hub", func() {
+		credentials, err := oci.DockerConfigCredentials([]byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"Ym90OnMzY3IzdA=="}}}`), "docker.io")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credentials).To(Equal(oci.Credentials{Username: "bot", Password: "s3cr3t"}))
+	})
+
+	It("has no credentials for other registries", func() {
+		credentials, err := oci.DockerConfigCredentials([]byte(`{"auths":{"registry.example.com":{"username":"bot"}}}`), "ghcr.io")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(credentials).To(Equal(oci.Credentials{}))
+	})
+})
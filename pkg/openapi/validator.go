@@ -0,0 +1,159 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openapi validates stamped objects against the OpenAPI schemas the
+// API server publishes, so that a template that sets a field the kind does
+// not have, or sets it to a value of the wrong type, fails before the object
+// is submitted, with the path of the field at fault.
+package openapi
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/kube-openapi/pkg/util/proto"
+	"k8s.io/kube-openapi/pkg/util/proto/validation"
+)
+
+// DefaultRefreshInterval is how long the schemas are cached before they are
+// fetched again, so that the schemas of kinds installed since are known.
+const DefaultRefreshInterval = 10 * time.Minute
+
+const groupVersionKindExtension = "x-kubernetes-group-version-kind"
+
+//counterfeiter:generate . Validator
+type Validator interface {
+	Validate(obj *unstructured.Unstructured) error
+}
+
+// NewValidator validates objects against the schemas fetched through
+// schemas the first time an object is validated, and again once they are
+// older than refreshInterval. An object of a kind without a published schema
+// is not validated.
+func NewValidator(schemas discovery.OpenAPISchemaInterface, refreshInterval time.Duration) Validator {
+	return &validator{
+		schemas:         schemas,
+		refreshInterval: refreshInterval,
+		now:             time.Now,
+	}
+}
+
+type validator struct {
+	schemas         discovery.OpenAPISchemaInterface
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu         sync.Mutex
+	models     proto.Models
+	modelNames map[schema.GroupVersionKind]string
+	fetched    time.Time
+}
+
+// ValidationError lists every way an object does not conform to the schema
+// of its kind.
+type ValidationError struct {
+	GVK  schema.GroupVersionKind
+	Errs []error
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.GVK.Kind, utilerrors.NewAggregate(e.Errs).Error())
+}
+
+func (v *validator) Validate(obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	model, err := v.lookup(gvk)
+	if err != nil {
+		return fmt.Errorf("get schema of %s: %w", gvk.String(), err)
+	}
+	if model == nil {
+		return nil
+	}
+
+	if errs := validation.ValidateModel(obj.UnstructuredContent(), model, gvk.Kind); len(errs) > 0 {
+		return ValidationError{GVK: gvk, Errs: errs}
+	}
+	return nil
+}
+
+func (v *validator) lookup(gvk schema.GroupVersionKind) (proto.Schema, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.models == nil || v.now().Sub(v.fetched) >= v.refreshInterval {
+		if err := v.fetch(); err != nil {
+			return nil, err
+		}
+	}
+
+	name, ok := v.modelNames[gvk]
+	if !ok {
+		return nil, nil
+	}
+	return v.models.LookupModel(name), nil
+}
+
+func (v *validator) fetch() error {
+	document, err := v.schemas.OpenAPISchema()
+	if err != nil {
+		return fmt.Errorf("openapi schema: %w", err)
+	}
+
+	models, err := proto.NewOpenAPIData(document)
+	if err != nil {
+		return fmt.Errorf("parse openapi schema: %w", err)
+	}
+
+	modelNames := map[schema.GroupVersionKind]string{}
+	for _, name := range models.ListModels() {
+		for _, gvk := range groupVersionKinds(models.LookupModel(name)) {
+			modelNames[gvk] = name
+		}
+	}
+
+	v.models, v.modelNames, v.fetched = models, modelNames, v.now()
+	return nil
+}
+
+// groupVersionKinds reads the kinds a model is the schema of from its
+// x-kubernetes-group-version-kind extension.
+func groupVersionKinds(model proto.Schema) []schema.GroupVersionKind {
+	extension, ok := model.GetExtensions()[groupVersionKindExtension].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var gvks []schema.GroupVersionKind
+	for _, entry := range extension {
+		fields, ok := entry.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		group, _ := fields["group"].(string)
+		version, _ := fields["version"].(string)
+		kind, _ := fields["kind"].(string)
+		if version == "" || kind == "" {
+			continue
+		}
+		gvks = append(gvks, schema.GroupVersionKind{Group: group, Version: version, Kind: kind})
+	}
+	return gvks
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	openapi_v2 "github.com/googleapis/gnostic/openapiv2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/openapi"
+)
+
+type schemas struct {
+	document *openapi_v2.Document
+	err      error
+	fetches  int
+}
+
+func (s *schemas) OpenAPISchema() (*openapi_v2.Document, error) {
+	s.fetches++
+	return s.document, s.err
+}
+
+var _ = Describe("Validator", func() {
+	var (
+		fakeSchemas *schemas
+		validator   openapi.Validator
+		widget      *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		content, err := os.ReadFile("testdata/swagger.json")
+		Expect(err).NotTo(HaveOccurred())
+		document, err := openapi_v2.ParseDocument(content)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeSchemas = &schemas{document: document}
+		validator = openapi.NewValidator(fakeSchemas, time.Hour)
+
+		widget = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.io/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": "my-widget", "namespace": "dev"},
+			"spec":       map[string]interface{}{"size": int64(3), "color": "blue"},
+		}}
+	})
+
+	It("accepts an object that conforms to the schema of its kind", func() {
+		Expect(validator.Validate(widget)).To(Succeed())
+	})
+
+	It("rejects a field the kind does not have, with its path", func() {
+		widget.Object["spec"].(map[string]interface{})["colour"] = "blue"
+
+		err := validator.Validate(widget)
+		Expect(err).To(MatchError(ContainSubstring(`ValidationError(Widget.spec): unknown field "colour"`)))
+
+		var validationErr openapi.ValidationError
+		Expect(errors.As(err, &validationErr)).To(BeTrue())
+		Expect(validationErr.GVK.Kind).To(Equal("Widget"))
+	})
+
+	It("rejects a field of the wrong type", func() {
+		widget.Object["spec"].(map[string]interface{})["size"] = "three"
+
+		Expect(validator.Validate(widget)).To(MatchError(ContainSubstring("ValidationError(Widget.spec.size): invalid type")))
+	})
+
+	It("rejects an object missing a required field", func() {
+		delete(widget.Object["spec"].(map[string]interface{}), "size")
+
+		Expect(validator.Validate(widget)).To(MatchError(ContainSubstring(`missing required field "size"`)))
+	})
+
+	It("does not validate an object of a kind without a schema", func() {
+		widget.SetKind("Gadget")
+		widget.Object["spec"] = "anything"
+
+		Expect(validator.Validate(widget)).To(Succeed())
+	})
+
+	It("fetches the schemas once until they are to be refreshed", func() {
+		Expect(validator.Validate(widget)).To(Succeed())
+		Expect(validator.Validate(widget)).To(Succeed())
+		Expect(fakeSchemas.fetches).To(Equal(1))
+	})
+
+	Context("when the schemas are always to be refreshed", func() {
+		BeforeEach(func() {
+			validator = openapi.NewValidator(fakeSchemas, 0)
+		})
+
+		It("fetches the schemas for each object", func() {
+			Expect(validator.Validate(widget)).To(Succeed())
+			Expect(validator.Validate(widget)).To(Succeed())
+			Expect(fakeSchemas.fetches).To(Equal(2))
+		})
+	})
+
+	Context("when the schemas cannot be fetched", func() {
+		BeforeEach(func() {
+			fakeSchemas.err = errors.New("some-error")
+		})
+
+		It("returns an error", func() {
+			Expect(validator.Validate(widget)).To(MatchError("get schema of example.io/v1, Kind=Widget: openapi schema: some-error"))
+		})
+	})
+})
@@ -0,0 +1,334 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package healthcheck judges the health of stamped objects by the health
+// rules of the templates they were stamped from.
+package healthcheck
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
+)
+
+// DetermineHealthCondition returns the Healthy condition of the object
+// stamped for a component. Without a rule, a kapp-controller App, a
+// Knative Service or a Flux Kustomization or GitRepository is judged by its
+// status, and any other object is healthy once outputsAvailable.
+func DetermineHealthCondition(rule *v1alpha1.HealthRule, stampedObject *unstructured.Unstructured, outputsAvailable bool) metav1.Condition {
+	if rule == nil {
+		if isKappApp(stampedObject) {
+			return kappApp(stampedObject)
+		}
+		if IsKnativeService(stampedObject) {
+			return knativeService(stampedObject)
+		}
+		if isKind(stampedObject, "kustomize.toolkit.fluxcd.io", "Kustomization") {
+			return fluxObject(stampedObject, v1alpha1.FluxKustomizationHealthyReason, kustomizationApplied)
+		}
+		if isKind(stampedObject, "source.toolkit.fluxcd.io", "GitRepository") {
+			return fluxObject(stampedObject, v1alpha1.FluxGitRepositoryHealthyReason, gitRepositoryFetched)
+		}
+		if outputsAvailable {
+			return condition(metav1.ConditionTrue, v1alpha1.OutputsAvailableHealthyReason, "")
+		}
+		return condition(metav1.ConditionUnknown, v1alpha1.OutputsNotAvailableHealthyReason, "")
+	}
+
+	if stampedObject == nil {
+		return condition(metav1.ConditionUnknown, v1alpha1.NoStampedObjectHealthyReason, "")
+	}
+
+	switch {
+	case rule.AlwaysHealthy != nil:
+		return condition(metav1.ConditionTrue, v1alpha1.AlwaysHealthyHealthyReason, "")
+	case rule.SingleConditionType != "":
+		return singleConditionType(rule.SingleConditionType, stampedObject)
+	case rule.Tekton != nil:
+		return tektonRun(stampedObject)
+	default:
+		return multiMatch(rule.MultiMatch, stampedObject)
+	}
+}
+
+func singleConditionType(conditionType string, stampedObject *unstructured.Unstructured) metav1.Condition {
+	found := findCondition(stampedObject, conditionType)
+	if found == nil {
+		return condition(metav1.ConditionUnknown, v1alpha1.SingleConditionTypeHealthyReason,
+			fmt.Sprintf("condition with type [%s] not found on resource status", conditionType))
+	}
+
+	message := fmt.Sprintf("condition status: %s", found.Status)
+	if found.Message != "" {
+		message = fmt.Sprintf("%s, message: %s", message, found.Message)
+	}
+
+	status := metav1.ConditionUnknown
+	switch found.Status {
+	case metav1.ConditionTrue, metav1.ConditionFalse:
+		status = found.Status
+	}
+	return condition(status, v1alpha1.SingleConditionTypeHealthyReason, message)
+}
+
+// tektonRun judges a PipelineRun or TaskRun by its Succeeded condition,
+// whose reason tells how the run is going, e.g. Running, or how it ended,
+// e.g. PipelineRunTimeout.
+func tektonRun(stampedObject *unstructured.Unstructured) metav1.Condition {
+	found := findCondition(stampedObject, "Succeeded")
+	if found == nil {
+		return condition(metav1.ConditionUnknown, v1alpha1.TektonRunHealthyReason, "the run has not started")
+	}
+
+	message := found.Reason
+	if found.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, found.Message)
+	}
+
+	status := metav1.ConditionUnknown
+	switch found.Status {
+	case metav1.ConditionTrue, metav1.ConditionFalse:
+		status = found.Status
+	}
+	return condition(status, v1alpha1.TektonRunHealthyReason, message)
+}
+
+func isKappApp(stampedObject *unstructured.Unstructured) bool {
+	return isKind(stampedObject, "kappctrl.k14s.io", "App")
+}
+
+// kappApp judges a kapp-controller App by its status: unhealthy once its
+// reconcile failed, healthy once the current generation was deployed and
+// inspected, and of unknown health until then.
+func kappApp(stampedObject *unstructured.Unstructured) metav1.Condition {
+	content := stampedObject.UnstructuredContent()
+	observedGeneration, _, _ := unstructured.NestedInt64(content, "status", "observedGeneration")
+	if observedGeneration < stampedObject.GetGeneration() {
+		return condition(metav1.ConditionUnknown, v1alpha1.KappAppHealthyReason,
+			fmt.Sprintf("waiting for kapp-controller to reconcile generation %d", stampedObject.GetGeneration()))
+	}
+
+	for _, conditionType := range []string{"ReconcileFailed", "DeleteFailed"} {
+		found := findCondition(stampedObject, conditionType)
+		if found == nil || found.Status != metav1.ConditionTrue {
+			continue
+		}
+
+		message, _, _ := unstructured.NestedString(content, "status", "usefulErrorMessage")
+		if message == "" {
+			message = found.Message
+		}
+		if message == "" {
+			message, _, _ = unstructured.NestedString(content, "status", "deploy", "stderr")
+		}
+		return condition(metav1.ConditionFalse, v1alpha1.KappAppHealthyReason, strings.TrimSpace(fmt.Sprintf("%s: %s", conditionType, message)))
+	}
+
+	description, _, _ := unstructured.NestedString(content, "status", "friendlyDescription")
+	if found := findCondition(stampedObject, "ReconcileSucceeded"); found != nil && found.Status == metav1.ConditionTrue {
+		if exitCode, _, _ := unstructured.NestedInt64(content, "status", "inspect", "exitCode"); exitCode != 0 {
+			stderr, _, _ := unstructured.NestedString(content, "status", "inspect", "stderr")
+			return condition(metav1.ConditionUnknown, v1alpha1.KappAppHealthyReason,
+				strings.TrimSpace(fmt.Sprintf("deployed, but inspecting the app failed: %s", stderr)))
+		}
+		return condition(metav1.ConditionTrue, v1alpha1.KappAppHealthyReason, description)
+	}
+
+	if description == "" {
+		description = "waiting for kapp-controller to reconcile the app"
+	}
+	return condition(metav1.ConditionUnknown, v1alpha1.KappAppHealthyReason, description)
+}
+
+// IsKnativeService is whether the object is a Knative Service.
+func IsKnativeService(stampedObject *unstructured.Unstructured) bool {
+	return stampedObject != nil &&
+		stampedObject.GetKind() == "Service" &&
+		stampedObject.GroupVersionKind().Group == "serving.knative.dev"
+}
+
+// knativeService judges a Knative Service by its Ready condition, once
+// Knative has observed its current generation.
+func knativeService(stampedObject *unstructured.Unstructured) metav1.Condition {
+	content := stampedObject.UnstructuredContent()
+	observedGeneration, _, _ := unstructured.NestedInt64(content, "status", "observedGeneration")
+	if observedGeneration < stampedObject.GetGeneration() {
+		return condition(metav1.ConditionUnknown, v1alpha1.KnativeServiceHealthyReason,
+			fmt.Sprintf("waiting for Knative to reconcile generation %d", stampedObject.GetGeneration()))
+	}
+
+	found := findCondition(stampedObject, "Ready")
+	if found == nil {
+		return condition(metav1.ConditionUnknown, v1alpha1.KnativeServiceHealthyReason, "condition with type [Ready] not found on resource status")
+	}
+
+	switch found.Status {
+	case metav1.ConditionTrue:
+		revision, _, _ := unstructured.NestedString(content, "status", "latestReadyRevisionName")
+		url, _, _ := unstructured.NestedString(content, "status", "url")
+		return condition(metav1.ConditionTrue, v1alpha1.KnativeServiceHealthyReason,
+			fmt.Sprintf("revision %s is ready at %s", revision, url))
+	case metav1.ConditionFalse:
+		return condition(metav1.ConditionFalse, v1alpha1.KnativeServiceHealthyReason,
+			strings.TrimSpace(fmt.Sprintf("%s: %s", found.Reason, found.Message)))
+	default:
+		message := found.Message
+		if message == "" {
+			message = "waiting for the service to become ready"
+		}
+		return condition(metav1.ConditionUnknown, v1alpha1.KnativeServiceHealthyReason, message)
+	}
+}
+
+func isKind(stampedObject *unstructured.Unstructured, group, kind string) bool {
+	return stampedObject != nil &&
+		stampedObject.GetKind() == kind &&
+		stampedObject.GroupVersionKind().Group == group
+}
+
+// fluxObject judges an object reconciled by a Flux controller by its Ready
+// condition, once the controller has observed its current generation. A
+// Stalled object is unhealthy, whether or not it is Ready, and a Ready one
+// is judged by ready, by the revision it reports.
+func fluxObject(stampedObject *unstructured.Unstructured, reason string, ready func(content map[string]interface{}) (metav1.ConditionStatus, string)) metav1.Condition {
+	content := stampedObject.UnstructuredContent()
+	observedGeneration, _, _ := unstructured.NestedInt64(content, "status", "observedGeneration")
+	if observedGeneration < stampedObject.GetGeneration() {
+		return condition(metav1.ConditionUnknown, reason,
+			fmt.Sprintf("waiting for Flux to reconcile generation %d", stampedObject.GetGeneration()))
+	}
+
+	if stalled := findCondition(stampedObject, "Stalled"); stalled != nil && stalled.Status == metav1.ConditionTrue {
+		return condition(metav1.ConditionFalse, reason, strings.TrimSpace(fmt.Sprintf("%s: %s", stalled.Reason, stalled.Message)))
+	}
+
+	found := findCondition(stampedObject, "Ready")
+	if found == nil {
+		return condition(metav1.ConditionUnknown, reason, "condition with type [Ready] not found on resource status")
+	}
+
+	switch found.Status {
+	case metav1.ConditionTrue:
+		status, message := ready(content)
+		return condition(status, reason, message)
+	case metav1.ConditionFalse:
+		return condition(metav1.ConditionFalse, reason, strings.TrimSpace(fmt.Sprintf("%s: %s", found.Reason, found.Message)))
+	default:
+		message := found.Message
+		if message == "" {
+			message = "waiting for Flux to reconcile the object"
+		}
+		return condition(metav1.ConditionUnknown, reason, message)
+	}
+}
+
+// kustomizationApplied judges a Ready Kustomization by its revisions: it is
+// healthy once the revision it last attempted is the one it applied.
+func kustomizationApplied(content map[string]interface{}) (metav1.ConditionStatus, string) {
+	applied, _, _ := unstructured.NestedString(content, "status", "lastAppliedRevision")
+	attempted, _, _ := unstructured.NestedString(content, "status", "lastAttemptedRevision")
+	if applied == "" {
+		return metav1.ConditionUnknown, "waiting for a revision to be applied"
+	}
+	if attempted != "" && attempted != applied {
+		return metav1.ConditionUnknown, fmt.Sprintf("applying revision %s, revision %s is applied", attempted, applied)
+	}
+	return metav1.ConditionTrue, fmt.Sprintf("applied revision %s", applied)
+}
+
+// gitRepositoryFetched judges a Ready GitRepository by its artifact: it is
+// healthy once it has fetched a revision.
+func gitRepositoryFetched(content map[string]interface{}) (metav1.ConditionStatus, string) {
+	revision, _, _ := unstructured.NestedString(content, "status", "artifact", "revision")
+	if revision == "" {
+		return metav1.ConditionUnknown, "waiting for an artifact to be fetched"
+	}
+	return metav1.ConditionTrue, fmt.Sprintf("fetched revision %s", revision)
+}
+
+func multiMatch(rule *v1alpha1.MultiMatchHealthRule, stampedObject *unstructured.Unstructured) metav1.Condition {
+	for _, requirement := range rule.Unhealthy.MatchConditions {
+		if conditionMatches(stampedObject, requirement) {
+			return condition(metav1.ConditionFalse, v1alpha1.MatchedConditionHealthyReason,
+				fmt.Sprintf("condition with type [%s] has status [%s]", requirement.Type, requirement.Status))
+		}
+	}
+	for _, requirement := range rule.Unhealthy.MatchFields {
+		if matched, _ := selector.MatchFields([]v1alpha1.FieldSelectorRequirement{requirement}, stampedObject.UnstructuredContent()); matched {
+			return condition(metav1.ConditionFalse, v1alpha1.MatchedFieldHealthyReason,
+				fmt.Sprintf("field [%s] is %s %v", requirement.Key, requirement.Operator, requirement.Values))
+		}
+	}
+
+	if healthy(&rule.Healthy, stampedObject) {
+		reason := v1alpha1.MatchedConditionHealthyReason
+		if len(rule.Healthy.MatchConditions) == 0 {
+			reason = v1alpha1.MatchedFieldHealthyReason
+		}
+		return condition(metav1.ConditionTrue, reason, "")
+	}
+
+	return condition(metav1.ConditionUnknown, v1alpha1.NoMatchesFulfilledHealthyReason, "")
+}
+
+func healthy(rule *v1alpha1.HealthMatchRule, stampedObject *unstructured.Unstructured) bool {
+	for _, requirement := range rule.MatchConditions {
+		if !conditionMatches(stampedObject, requirement) {
+			return false
+		}
+	}
+
+	matched, err := selector.MatchFields(rule.MatchFields, stampedObject.UnstructuredContent())
+	return err == nil && matched
+}
+
+func conditionMatches(stampedObject *unstructured.Unstructured, requirement v1alpha1.ConditionRequirement) bool {
+	found := findCondition(stampedObject, requirement.Type)
+	return found != nil && found.Status == requirement.Status
+}
+
+func findCondition(stampedObject *unstructured.Unstructured, conditionType string) *metav1.Condition {
+	conditions, _, _ := unstructured.NestedSlice(stampedObject.UnstructuredContent(), "status", "conditions")
+	for _, c := range conditions {
+		fields, ok := c.(map[string]interface{})
+		if !ok || fields["type"] != conditionType {
+			continue
+		}
+
+		status, _ := fields["status"].(string)
+		reason, _ := fields["reason"].(string)
+		message, _ := fields["message"].(string)
+		return &metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionStatus(status),
+			Reason:  reason,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+func condition(status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    v1alpha1.ComponentHealthy,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
@@ -0,0 +1,444 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
+)
+
+var _ = Describe("DetermineHealthCondition", func() {
+	var stampedObject *unstructured.Unstructured
+
+	BeforeEach(func() {
+		stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "kpack.io/v1alpha1",
+			"kind":       "Image",
+			"status": map[string]interface{}{
+				"phase": "Building",
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "False", "message": "build failed"},
+					map[string]interface{}{"type": "Succeeded", "status": "Unknown"},
+				},
+			},
+		}}
+	})
+
+	Context("without a rule", func() {
+		It("is healthy once the outputs are available", func() {
+			Expect(healthcheck.DetermineHealthCondition(nil, stampedObject, true)).To(Equal(metav1.Condition{
+				Type:   "Healthy",
+				Status: metav1.ConditionTrue,
+				Reason: "OutputsAvailable",
+			}))
+		})
+
+		It("is of unknown health until then", func() {
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, false)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Reason).To(Equal("OutputsNotAvailable"))
+		})
+	})
+
+	Context("for a kapp-controller App without a rule", func() {
+		BeforeEach(func() {
+			stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kappctrl.k14s.io/v1alpha1",
+				"kind":       "App",
+				"metadata":   map[string]interface{}{"generation": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration":  int64(2),
+					"friendlyDescription": "Reconcile succeeded",
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "ReconcileSucceeded", "status": "True"},
+					},
+					"inspect": map[string]interface{}{"exitCode": int64(0)},
+				},
+			}}
+		})
+
+		It("is healthy once the app is deployed, whether or not the outputs are available", func() {
+			Expect(healthcheck.DetermineHealthCondition(nil, stampedObject, false)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionTrue,
+				Reason:  "KappApp",
+				Message: "Reconcile succeeded",
+			}))
+		})
+
+		It("is of unknown health until kapp-controller reconciles the latest generation", func() {
+			stampedObject.SetGeneration(3)
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Message).To(Equal("waiting for kapp-controller to reconcile generation 3"))
+		})
+
+		It("is of unknown health while the app is reconciling", func() {
+			Expect(unstructured.SetNestedField(stampedObject.Object, "Reconciling", "status", "friendlyDescription")).To(Succeed())
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Reconciling", "status": "True"},
+			}, "status", "conditions")).To(Succeed())
+
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Message).To(Equal("Reconciling"))
+		})
+
+		It("is unhealthy once the reconcile failed, with its useful error message", func() {
+			Expect(unstructured.SetNestedField(stampedObject.Object, "Deploying: Error (see .status.usefulErrorMessage for details)", "status", "friendlyDescription")).To(Succeed())
+			Expect(unstructured.SetNestedField(stampedObject.Object, "kapp: Error: waiting on reconcile deployment/petclinic", "status", "usefulErrorMessage")).To(Succeed())
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "ReconcileFailed", "status": "True", "message": "Deploying: Error"},
+			}, "status", "conditions")).To(Succeed())
+
+			Expect(healthcheck.DetermineHealthCondition(nil, stampedObject, true)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionFalse,
+				Reason:  "KappApp",
+				Message: "ReconcileFailed: kapp: Error: waiting on reconcile deployment/petclinic",
+			}))
+		})
+
+		It("is of unknown health when the deployed app cannot be inspected", func() {
+			Expect(unstructured.SetNestedMap(stampedObject.Object, map[string]interface{}{
+				"exitCode": int64(1),
+				"stderr":   "kapp: Error: app not found\n",
+			}, "status", "inspect")).To(Succeed())
+
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Message).To(Equal("deployed, but inspecting the app failed: kapp: Error: app not found"))
+		})
+
+		It("defers to the rule of the template", func() {
+			rule := &v1alpha1.HealthRule{AlwaysHealthy: &runtime.RawExtension{Raw: []byte(`{}`)}}
+			Expect(healthcheck.DetermineHealthCondition(rule, stampedObject, true).Reason).To(Equal("AlwaysHealthy"))
+		})
+	})
+
+	Context("for a Knative Service without a rule", func() {
+		BeforeEach(func() {
+			stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "serving.knative.dev/v1",
+				"kind":       "Service",
+				"metadata":   map[string]interface{}{"generation": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration":      int64(2),
+					"url":                     "https://petclinic.dev.example.com",
+					"latestReadyRevisionName": "petclinic-00002",
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "True"},
+					},
+				},
+			}}
+		})
+
+		It("is healthy once the service is ready, with its latest ready revision and url", func() {
+			Expect(healthcheck.DetermineHealthCondition(nil, stampedObject, false)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionTrue,
+				Reason:  "KnativeService",
+				Message: "revision petclinic-00002 is ready at https://petclinic.dev.example.com",
+			}))
+		})
+
+		It("is of unknown health until Knative reconciles the latest generation", func() {
+			stampedObject.SetGeneration(3)
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Message).To(Equal("waiting for Knative to reconcile generation 3"))
+		})
+
+		It("is unhealthy when the service is not ready", func() {
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "RevisionFailed", "message": "Revision \"petclinic-00003\" failed with message: Back-off pulling image."},
+			}, "status", "conditions")).To(Succeed())
+
+			Expect(healthcheck.DetermineHealthCondition(nil, stampedObject, true)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionFalse,
+				Reason:  "KnativeService",
+				Message: "RevisionFailed: Revision \"petclinic-00003\" failed with message: Back-off pulling image.",
+			}))
+		})
+	})
+
+	Context("for a Flux Kustomization without a rule", func() {
+		BeforeEach(func() {
+			stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kustomize.toolkit.fluxcd.io/v1beta2",
+				"kind":       "Kustomization",
+				"metadata":   map[string]interface{}{"generation": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration":    int64(2),
+					"lastAppliedRevision":   "main/abc123",
+					"lastAttemptedRevision": "main/abc123",
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "True", "reason": "ReconciliationSucceeded"},
+					},
+				},
+			}}
+		})
+
+		It("is healthy once it applied the revision it last attempted", func() {
+			Expect(healthcheck.DetermineHealthCondition(nil, stampedObject, false)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionTrue,
+				Reason:  "FluxKustomization",
+				Message: "applied revision main/abc123",
+			}))
+		})
+
+		It("is of unknown health while a newer revision is being applied", func() {
+			Expect(unstructured.SetNestedField(stampedObject.Object, "main/def456", "status", "lastAttemptedRevision")).To(Succeed())
+
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Message).To(Equal("applying revision main/def456, revision main/abc123 is applied"))
+		})
+
+		It("is of unknown health until Flux reconciles the latest generation", func() {
+			stampedObject.SetGeneration(3)
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Message).To(Equal("waiting for Flux to reconcile generation 3"))
+		})
+
+		It("is of unknown health while it is progressing", func() {
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "Unknown", "reason": "Progressing", "message": "reconciliation in progress"},
+			}, "status", "conditions")).To(Succeed())
+
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Message).To(Equal("reconciliation in progress"))
+		})
+
+		It("is unhealthy when it is not ready", func() {
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "BuildFailed", "message": "kustomize build failed"},
+			}, "status", "conditions")).To(Succeed())
+
+			Expect(healthcheck.DetermineHealthCondition(nil, stampedObject, true)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionFalse,
+				Reason:  "FluxKustomization",
+				Message: "BuildFailed: kustomize build failed",
+			}))
+		})
+
+		It("is unhealthy when it is stalled", func() {
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True", "reason": "ReconciliationSucceeded"},
+				map[string]interface{}{"type": "Stalled", "status": "True", "reason": "ArtifactFailed", "message": "source not found"},
+			}, "status", "conditions")).To(Succeed())
+
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("ArtifactFailed: source not found"))
+		})
+	})
+
+	Context("for a Flux GitRepository without a rule", func() {
+		BeforeEach(func() {
+			stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "source.toolkit.fluxcd.io/v1beta2",
+				"kind":       "GitRepository",
+				"metadata":   map[string]interface{}{"generation": int64(1)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(1),
+					"artifact":           map[string]interface{}{"revision": "main/abc123"},
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "True", "reason": "Succeeded"},
+					},
+				},
+			}}
+		})
+
+		It("is healthy once it fetched a revision", func() {
+			Expect(healthcheck.DetermineHealthCondition(nil, stampedObject, false)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionTrue,
+				Reason:  "FluxGitRepository",
+				Message: "fetched revision main/abc123",
+			}))
+		})
+
+		It("is of unknown health while it has no artifact", func() {
+			unstructured.RemoveNestedField(stampedObject.Object, "status", "artifact")
+
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Message).To(Equal("waiting for an artifact to be fetched"))
+		})
+
+		It("is unhealthy when it cannot be fetched", func() {
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "GitOperationFailed", "message": "authentication required"},
+			}, "status", "conditions")).To(Succeed())
+
+			condition := healthcheck.DetermineHealthCondition(nil, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("GitOperationFailed: authentication required"))
+		})
+	})
+
+	It("is of unknown health when nothing was stamped", func() {
+		rule := &v1alpha1.HealthRule{SingleConditionType: "Ready"}
+		condition := healthcheck.DetermineHealthCondition(rule, nil, false)
+		Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(condition.Reason).To(Equal("NoStampedObject"))
+	})
+
+	It("is always healthy when the rule says so", func() {
+		rule := &v1alpha1.HealthRule{AlwaysHealthy: &runtime.RawExtension{Raw: []byte(`{}`)}}
+		condition := healthcheck.DetermineHealthCondition(rule, stampedObject, false)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("AlwaysHealthy"))
+	})
+
+	Context("with a single condition type", func() {
+		It("takes the status of the condition", func() {
+			rule := &v1alpha1.HealthRule{SingleConditionType: "Ready"}
+			Expect(healthcheck.DetermineHealthCondition(rule, stampedObject, true)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionFalse,
+				Reason:  "SingleConditionType",
+				Message: "condition status: False, message: build failed",
+			}))
+		})
+
+		It("is of unknown health when the object does not have the condition", func() {
+			rule := &v1alpha1.HealthRule{SingleConditionType: "Healthy"}
+			condition := healthcheck.DetermineHealthCondition(rule, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Message).To(Equal("condition with type [Healthy] not found on resource status"))
+		})
+	})
+
+	Context("for a Tekton run", func() {
+		var rule *v1alpha1.HealthRule
+
+		BeforeEach(func() {
+			rule = &v1alpha1.HealthRule{Tekton: &runtime.RawExtension{Raw: []byte(`{}`)}}
+			stampedObject = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "tekton.dev/v1beta1",
+				"kind":       "PipelineRun",
+			}}
+		})
+
+		setSucceeded := func(status, reason, message string) {
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Succeeded", "status": status, "reason": reason, "message": message},
+			}, "status", "conditions")).To(Succeed())
+		}
+
+		It("is of unknown health until the run starts", func() {
+			Expect(healthcheck.DetermineHealthCondition(rule, stampedObject, false)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionUnknown,
+				Reason:  "TektonRun",
+				Message: "the run has not started",
+			}))
+		})
+
+		It("is of unknown health while the run is running", func() {
+			setSucceeded("Unknown", "Running", "Tasks Completed: 1 (Failed: 0, Cancelled 0), Incomplete: 2")
+			Expect(healthcheck.DetermineHealthCondition(rule, stampedObject, false)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionUnknown,
+				Reason:  "TektonRun",
+				Message: "Running: Tasks Completed: 1 (Failed: 0, Cancelled 0), Incomplete: 2",
+			}))
+		})
+
+		It("is healthy once the run succeeded", func() {
+			setSucceeded("True", "Succeeded", "")
+			condition := healthcheck.DetermineHealthCondition(rule, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal("Succeeded"))
+		})
+
+		It("is unhealthy once the run failed", func() {
+			setSucceeded("False", "PipelineRunTimeout", "PipelineRun timed out after 1h0m0s")
+			condition := healthcheck.DetermineHealthCondition(rule, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("PipelineRunTimeout: PipelineRun timed out after 1h0m0s"))
+		})
+	})
+
+	Context("with multiple matches", func() {
+		var rule *v1alpha1.HealthRule
+
+		BeforeEach(func() {
+			rule = &v1alpha1.HealthRule{MultiMatch: &v1alpha1.MultiMatchHealthRule{
+				Healthy: v1alpha1.HealthMatchRule{
+					MatchConditions: []v1alpha1.ConditionRequirement{{Type: "Succeeded", Status: "True"}},
+					MatchFields:     []v1alpha1.FieldSelectorRequirement{{Key: "status.phase", Operator: "In", Values: []string{"Done"}}},
+				},
+				Unhealthy: v1alpha1.HealthMatchRule{
+					MatchConditions: []v1alpha1.ConditionRequirement{{Type: "Succeeded", Status: "False"}},
+					MatchFields:     []v1alpha1.FieldSelectorRequirement{{Key: "status.phase", Operator: "In", Values: []string{"Failed"}}},
+				},
+			}}
+		})
+
+		It("is of unknown health while no requirement is met", func() {
+			condition := healthcheck.DetermineHealthCondition(rule, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Reason).To(Equal("NoMatchesFulfilled"))
+		})
+
+		It("is healthy once every healthy requirement is met", func() {
+			Expect(unstructured.SetNestedField(stampedObject.Object, "Done", "status", "phase")).To(Succeed())
+			Expect(healthcheck.DetermineHealthCondition(rule, stampedObject, true).Status).To(Equal(metav1.ConditionUnknown))
+
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Succeeded", "status": "True"},
+			}, "status", "conditions")).To(Succeed())
+			condition := healthcheck.DetermineHealthCondition(rule, stampedObject, true)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("MatchedCondition"))
+		})
+
+		It("is unhealthy when any unhealthy condition is met", func() {
+			Expect(unstructured.SetNestedSlice(stampedObject.Object, []interface{}{
+				map[string]interface{}{"type": "Succeeded", "status": "False"},
+			}, "status", "conditions")).To(Succeed())
+			Expect(healthcheck.DetermineHealthCondition(rule, stampedObject, true)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionFalse,
+				Reason:  "MatchedCondition",
+				Message: "condition with type [Succeeded] has status [False]",
+			}))
+		})
+
+		It("is unhealthy when any unhealthy field is met", func() {
+			Expect(unstructured.SetNestedField(stampedObject.Object, "Failed", "status", "phase")).To(Succeed())
+			Expect(healthcheck.DetermineHealthCondition(rule, stampedObject, true)).To(Equal(metav1.Condition{
+				Type:    "Healthy",
+				Status:  metav1.ConditionFalse,
+				Reason:  "MatchedField",
+				Message: "field [status.phase] is In [Failed]",
+			}))
+		})
+	})
+})
@@ -15,9 +15,12 @@
 package pipeline
 
 import (
+	"errors"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
 )
 
 // -- RunTemplate conditions
@@ -48,6 +51,24 @@ func StampedObjectRejectedByAPIServerCondition(err error) *metav1.Condition {
 	}
 }
 
+func StampedObjectRejectedByDryRunCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.StampedObjectRejectedByDryRunRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
+func InvalidStampedObjectCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InvalidStampedObjectRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
 func OutputPathNotSatisfiedCondition(err error) *metav1.Condition {
 	return &metav1.Condition{
 		Type:    v1alpha1.RunTemplateReady,
@@ -57,6 +78,51 @@ func OutputPathNotSatisfiedCondition(err error) *metav1.Condition {
 	}
 }
 
+func CannotListCreatedObjectsCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.CannotListCreatedObjectsRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
+func MissingAPIDependencyCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.MissingAPIDependencyRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
+func CannotCreateObjectCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.CannotCreateObjectRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
+func KindNotAllowedCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.KindNotAllowedRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
+func PermissionDeniedCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.PermissionDeniedRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
 func FailedToListCreatedObjectsCondition(err error) *metav1.Condition {
 	return &metav1.Condition{
 		Type:    v1alpha1.RunTemplateReady,
@@ -74,3 +140,80 @@ func TemplateStampFailureCondition(err error) *metav1.Condition {
 		Message: err.Error(),
 	}
 }
+
+func RunTimedOutCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.RunTimedOutRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
+func InvalidInputsCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InvalidInputsRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
+func InterceptorFailureCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.InterceptorFailureRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
+func RetryBackoffCondition(err error) *metav1.Condition {
+	return &metav1.Condition{
+		Type:    v1alpha1.RunTemplateReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1alpha1.RetryBackoffRunTemplateReason,
+		Message: err.Error(),
+	}
+}
+
+// accessCondition returns the condition for a kind the API server does not
+// serve, for the verb an access review denied before the write, or for the
+// verb it refused the controller permission for, or nil when err is none of
+// these.
+func accessCondition(err error) *metav1.Condition {
+	if repository.IsMissingAPI(err) {
+		return MissingAPIDependencyCondition(err)
+	}
+
+	var objectErr repository.ObjectError
+	if !errors.As(err, &objectErr) {
+		return nil
+	}
+	if objectErr.Denied {
+		return PermissionDeniedCondition(err)
+	}
+	if !objectErr.Forbidden() {
+		return nil
+	}
+
+	if objectErr.Verb == repository.ListVerb {
+		return CannotListCreatedObjectsCondition(err)
+	}
+	return CannotCreateObjectCondition(err)
+}
+
+// rejectedCondition tells an object found invalid before submission, or
+// rejected by a dry run, which wrote nothing, from one rejected by the write.
+func rejectedCondition(err error) *metav1.Condition {
+	var objectErr repository.ObjectError
+	if errors.As(err, &objectErr) {
+		switch {
+		case objectErr.Verb == repository.ValidateVerb:
+			return InvalidStampedObjectCondition(err)
+		case objectErr.DryRun:
+			return StampedObjectRejectedByDryRunCondition(err)
+		}
+	}
+	return StampedObjectRejectedByAPIServerCondition(err)
+}
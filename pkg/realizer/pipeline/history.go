@@ -0,0 +1,89 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+const runHistoryLimit = 10
+
+// runHistory records the most recent runs stamped by the pipeline, newest
+// first, so that they can be found without searching for generated names.
+func runHistory(pipeline *v1alpha1.Pipeline, runs []*unstructured.Unstructured, template templates.RunTemplate) []v1alpha1.RunRecord {
+	var stampedRuns []*unstructured.Unstructured
+	for _, run := range runs {
+		if isStampedBy(run, pipeline) {
+			stampedRuns = append(stampedRuns, run)
+		}
+	}
+
+	sort.SliceStable(stampedRuns, func(i, j int) bool {
+		iCreated, jCreated := stampedRuns[i].GetCreationTimestamp(), stampedRuns[j].GetCreationTimestamp()
+		return jCreated.Before(&iCreated)
+	})
+
+	if len(stampedRuns) > runHistoryLimit {
+		stampedRuns = stampedRuns[:runHistoryLimit]
+	}
+
+	var history []v1alpha1.RunRecord
+	for _, run := range stampedRuns {
+		record := v1alpha1.RunRecord{
+			Name:       run.GetName(),
+			StartTime:  run.GetCreationTimestamp(),
+			Result:     v1alpha1.RunningRunResult,
+			APIVersion: run.GetAPIVersion(),
+			Kind:       run.GetKind(),
+		}
+
+		switch succeededStatus(run) {
+		case metav1.ConditionTrue:
+			record.Result = v1alpha1.SucceededRunResult
+		case metav1.ConditionFalse:
+			record.Result = v1alpha1.FailedRunResult
+		}
+
+		if record.Result != v1alpha1.RunningRunResult {
+			completed := metav1.NewTime(completedAt(run))
+			record.CompletionTime = &completed
+		}
+
+		if outputs, err := template.GetOutput([]*unstructured.Unstructured{run}); err == nil && len(outputs) > 0 {
+			record.OutputsDigest = outputsDigest(outputs)
+		}
+
+		history = append(history, record)
+	}
+
+	return history
+}
+
+func outputsDigest(outputs templates.Outputs) string {
+	serialized, err := json.Marshal(outputs)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(serialized))
+}
@@ -19,14 +19,19 @@ package pipeline
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/identity"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/logging"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/tracing"
 )
 
 //counterfeiter:generate . Realizer
@@ -34,30 +39,58 @@ type Realizer interface {
 	Realize(ctx context.Context, pipeline *v1alpha1.Pipeline, logger logr.Logger, repository repository.Repository) (*v1.Condition, templates.Outputs, *unstructured.Unstructured)
 }
 
-func NewRealizer() Realizer {
-	return &pipelineRealizer{}
+func NewRealizer(interceptor interceptor.Interceptor, clusterContext templates.ClusterContext) Realizer {
+	return &pipelineRealizer{
+		interceptor:    interceptor,
+		clusterContext: clusterContext,
+	}
 }
 
-type pipelineRealizer struct{}
+type pipelineRealizer struct {
+	interceptor    interceptor.Interceptor
+	clusterContext templates.ClusterContext
+}
 
 type TemplatingContext struct {
-	Pipeline *v1alpha1.Pipeline `json:"pipeline"`
+	Pipeline       *v1alpha1.Pipeline       `json:"pipeline"`
+	ClusterContext templates.ClusterContext `json:"clusterContext"`
 }
 
 func (p *pipelineRealizer) Realize(ctx context.Context, pipeline *v1alpha1.Pipeline, logger logr.Logger, repository repository.Repository) (*v1.Condition, templates.Outputs, *unstructured.Unstructured) {
+	logger = logger.WithName(logging.Realizer)
+	ctx = logr.NewContext(ctx, logger)
+
 	pipeline.Spec.RunTemplateRef.Kind = "RunTemplate"
 	if pipeline.Spec.RunTemplateRef.Namespace == "" {
 		pipeline.Spec.RunTemplateRef.Namespace = pipeline.Namespace
 	}
+	if err := pipeline.Spec.RunTemplateRef.Validate(); err != nil {
+		errorMessage := "invalid RunTemplate reference"
+		logger.Info(fmt.Sprintf("%s: %s", errorMessage, err.Error()))
+		return RunTemplateMissingCondition(fmt.Errorf("%s: %w", errorMessage, err)), nil, nil
+	}
+
+	_, span := tracing.Start(ctx, "Get template", tracing.String("name", pipeline.Spec.RunTemplateRef.Name))
 	template, err := repository.GetRunTemplate(pipeline.Spec.RunTemplateRef)
+	span.End(err)
 
 	if err != nil {
 		errorMessage := fmt.Sprintf("could not get RunTemplate '%s'", pipeline.Spec.RunTemplateRef.Name)
+		if pipeline.Spec.RunTemplateRef.Selector != nil {
+			errorMessage = fmt.Sprintf("could not get RunTemplate matching %s", v1.FormatLabelSelector(pipeline.Spec.RunTemplateRef.Selector))
+		}
 		logger.Error(err, errorMessage)
 
 		return RunTemplateMissingCondition(fmt.Errorf("%s: %w", errorMessage, err)), nil, nil
 	}
 
+	err = template.ValidateInputs(pipeline.Spec.Inputs)
+	if err != nil {
+		errorMessage := fmt.Sprintf("invalid inputs for RunTemplate '%s'", template.GetName())
+		logger.Info(fmt.Sprintf("%s: %s", errorMessage, err.Error()))
+		return InvalidInputsCondition(fmt.Errorf("%s: %w", errorMessage, err)), nil, nil
+	}
+
 	labels := map[string]string{
 		"carto.run/pipeline-name":          pipeline.Name,
 		"carto.run/pipeline-namespace":     pipeline.Namespace,
@@ -68,23 +101,107 @@ func (p *pipelineRealizer) Realize(ctx context.Context, pipeline *v1alpha1.Pipel
 	stampContext := templates.StamperBuilder(
 		pipeline,
 		TemplatingContext{
-			Pipeline: pipeline,
+			Pipeline:       pipeline,
+			ClusterContext: p.clusterContext,
 		},
 		labels,
 	)
 
-	stampedObject, err := stampContext.Stamp(ctx, template.GetResourceTemplate())
+	stampCtx, span := tracing.Start(ctx, "Stamp", tracing.String("template", template.GetName()))
+	stampedObject, err := stampContext.Stamp(stampCtx, template.GetResourceTemplate())
+	span.End(err)
 	if err != nil {
 		errorMessage := "could not stamp template"
 		logger.Error(err, errorMessage)
 		return TemplateStampFailureCondition(fmt.Errorf("%s: %w", errorMessage, err)), nil, nil
 	}
 
+	policies, err := repository.ListStampPolicies()
+	if err != nil {
+		errorMessage := "could not list stamp policies"
+		logger.Error(err, errorMessage)
+		return TemplateStampFailureCondition(fmt.Errorf("%s: %w", errorMessage, err)), nil, nil
+	}
+	if err := v1alpha1.CheckStampPolicies(policies, policyNamespace(stampedObject, pipeline), stampedObject.GroupVersionKind().GroupKind()); err != nil {
+		logger.Info(err.Error())
+		return KindNotAllowedCondition(err), nil, nil
+	}
+
+	templateHash, err := identity.TemplateHash(template.GetResourceTemplate())
+	if err != nil {
+		errorMessage := "could not hash template"
+		logger.Error(err, errorMessage)
+		return TemplateStampFailureCondition(fmt.Errorf("%s: %w", errorMessage, err)), nil, nil
+	}
+	identity.Apply(stampedObject, identity.Identity{
+		OwnerUID:       pipeline.UID,
+		ResourceName:   identity.RunResourceName,
+		TemplateHash:   templateHash,
+		OwnerKind:      "Pipeline",
+		OwnerName:      pipeline.Name,
+		OwnerNamespace: pipeline.Namespace,
+		TemplateKind:   pipeline.Spec.RunTemplateRef.Kind,
+		TemplateName:   template.GetName(),
+	})
+
+	if retrigger, ok := pipeline.Annotations[v1alpha1.RetriggerAnnotation]; ok {
+		annotations := stampedObject.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[v1alpha1.RetriggerAnnotation] = retrigger
+		stampedObject.SetAnnotations(annotations)
+	}
+
+	if pipeline.Spec.RetryPolicy != nil {
+		objectForListCall := stampedObject.DeepCopy()
+		objectForListCall.SetLabels(labels)
+
+		previousRuns, err := repository.ListUnstructured(objectForListCall)
+		if err != nil {
+			err := fmt.Errorf("could not list pipeline objects: %w", err)
+			logger.Info(err.Error())
+			if condition := accessCondition(err); condition != nil {
+				return condition, nil, nil
+			}
+			return FailedToListCreatedObjectsCondition(err), nil, nil
+		}
+
+		attempt, retryAt := nextAttempt(pipeline, previousRuns)
+		if time.Now().Before(retryAt) {
+			pipeline.Status.RunHistory = runHistory(pipeline, previousRuns, template)
+			err := fmt.Errorf("retrying failed run at %s (attempt %d of %d)", retryAt.UTC().Format(time.RFC3339), attempt, pipeline.Spec.RetryPolicy.Limit)
+			logger.Info(err.Error())
+			return RetryBackoffCondition(err), pipeline.Status.Outputs, nil
+		}
+
+		stampedLabels := stampedObject.GetLabels()
+		for key, value := range attemptLabels(pipeline, attempt) {
+			stampedLabels[key] = value
+		}
+		stampedObject.SetLabels(stampedLabels)
+	}
+
+	logging.LogStampedObject(logger, stampedObject)
+
+	submission := &interceptor.Submission{Owner: pipeline, Object: stampedObject}
+	if err := p.interceptor.BeforeSubmit(ctx, submission); err != nil {
+		errorMessage := "could not intercept stamped object"
+		logger.Error(err, errorMessage)
+		return InterceptorFailureCondition(fmt.Errorf("%s: %w", errorMessage, err)), nil, nil
+	}
+
+	_, span = tracing.Start(ctx, "Apply", tracing.String("kind", stampedObject.GetKind()), tracing.String("name", stampedObject.GetName()))
 	err = repository.EnsureObjectExistsOnCluster(stampedObject.DeepCopy(), false)
+	span.End(err)
 	if err != nil {
 		errorMessage := "could not create object"
 		logger.Error(err, errorMessage)
-		return StampedObjectRejectedByAPIServerCondition(fmt.Errorf("%s: %w", errorMessage, err)), nil, nil
+		err = fmt.Errorf("%s: %w", errorMessage, err)
+		if condition := accessCondition(err); condition != nil {
+			return condition, nil, nil
+		}
+		return rejectedCondition(err), nil, nil
 	}
 
 	objectForListCall := stampedObject.DeepCopy()
@@ -94,10 +211,25 @@ func (p *pipelineRealizer) Realize(ctx context.Context, pipeline *v1alpha1.Pipel
 	if err != nil {
 		err := fmt.Errorf("could not list pipeline objects: %w", err)
 		logger.Info(err.Error())
+		if condition := accessCondition(err); condition != nil {
+			return condition, nil, stampedObject
+		}
 		return FailedToListCreatedObjectsCondition(err), nil, stampedObject
 	}
 
-	outputs, err := template.GetOutput(allPipelineStampedObjects)
+	runs := expireFinishedRuns(pipeline, allPipelineStampedObjects, time.Now(), repository, logger)
+	runs = pruneFailedRuns(pipeline, runs, repository, logger)
+	pipeline.Status.RunHistory = runHistory(pipeline, runs, template)
+
+	if run := timedOutRun(pipeline, runs, time.Now()); run != nil {
+		err := fmt.Errorf("run '%s' did not complete within %s", run.GetName(), pipeline.Spec.Timeout.Duration)
+		logger.Info(err.Error())
+		return RunTimedOutCondition(err), pipeline.Status.Outputs, stampedObject
+	}
+
+	_, span = tracing.Start(ctx, "Read outputs")
+	outputs, err := template.GetOutput(runs)
+	span.End(err)
 	if err != nil {
 		errorMessage := fmt.Sprintf("could not get output: %s", err.Error())
 		logger.Info(errorMessage)
@@ -107,5 +239,21 @@ func (p *pipelineRealizer) Realize(ctx context.Context, pipeline *v1alpha1.Pipel
 		outputs = pipeline.Status.Outputs
 	}
 
+	submission.Outputs = &outputs
+	if err := p.interceptor.AfterSubmit(ctx, submission); err != nil {
+		errorMessage := "could not intercept outputs"
+		logger.Error(err, errorMessage)
+		return InterceptorFailureCondition(fmt.Errorf("%s: %w", errorMessage, err)), nil, stampedObject
+	}
+
 	return RunTemplateReadyCondition(), outputs, stampedObject
 }
+
+// policyNamespace is the namespace whose ClusterStampPolicies apply to the
+// object: its own, or the pipeline's for a cluster-scoped object.
+func policyNamespace(obj *unstructured.Unstructured, pipeline *v1alpha1.Pipeline) string {
+	if obj.GetNamespace() != "" {
+		return obj.GetNamespace()
+	}
+	return pipeline.Namespace
+}
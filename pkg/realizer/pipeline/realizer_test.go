@@ -18,6 +18,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	. "github.com/MakeNowJust/heredoc/dot"
 	"github.com/go-logr/logr"
@@ -26,13 +28,21 @@ import (
 	. "github.com/onsi/gomega/gbytes"
 	. "github.com/onsi/gomega/gstruct"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor/interceptorfakes"
 	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/pipeline"
+	repo "github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 	"github.com/vmware-tanzu/cartographer/tests/resources"
@@ -42,6 +52,7 @@ var _ = Describe("Realizer", func() {
 	var (
 		out                 *Buffer
 		repository          *repositoryfakes.FakeRepository
+		fakeInterceptor     *interceptorfakes.FakeInterceptor
 		logger              logr.Logger
 		rlzr                realizer.Realizer
 		pipeline            *v1alpha1.Pipeline
@@ -52,7 +63,8 @@ var _ = Describe("Realizer", func() {
 		out = NewBuffer()
 		logger = zap.New(zap.WriteTo(out))
 		repository = &repositoryfakes.FakeRepository{}
-		rlzr = realizer.NewRealizer()
+		fakeInterceptor = &interceptorfakes.FakeInterceptor{}
+		rlzr = realizer.NewRealizer(fakeInterceptor, templates.ClusterContext{Name: "prod-eu", Region: "eu-west-1"})
 
 		pipeline = &v1alpha1.Pipeline{
 			Spec: v1alpha1.PipelineSpec{
@@ -106,7 +118,8 @@ var _ = Describe("Realizer", func() {
 
 			createdUnstructured = &unstructured.Unstructured{}
 
-			repository.EnsureObjectExistsOnClusterStub = func(obj *unstructured.Unstructured, allowUpdate bool) error {
+			repository.EnsureObjectExistsOnClusterStub = func(obj *unstructured.Unstructured, allowUpdate bool, _ ...repo.ApplyOption) error {
+				obj.SetCreationTimestamp(metav1.Now())
 				createdUnstructured.Object = obj.Object
 				return nil
 			}
@@ -126,7 +139,7 @@ var _ = Describe("Realizer", func() {
 			))
 
 			Expect(repository.EnsureObjectExistsOnClusterCallCount()).To(Equal(1))
-			stamped, allowUpdate := repository.EnsureObjectExistsOnClusterArgsForCall(0)
+			stamped, allowUpdate, _ := repository.EnsureObjectExistsOnClusterArgsForCall(0)
 			Expect(allowUpdate).To(BeFalse())
 			Expect(stamped.Object).To(
 				MatchKeys(IgnoreExtras, Keys{
@@ -142,6 +155,44 @@ var _ = Describe("Realizer", func() {
 			)
 		})
 
+		It("stamps the resource with the cluster context", func() {
+			testObj := resources.Test{
+				TypeMeta: metav1.TypeMeta{Kind: "Test", APIVersion: "test.run/v1alpha1"},
+				Spec:     resources.TestSpec{Foo: "$(clusterContext.name)$ in $(clusterContext.region)$"},
+			}
+			dbytes, err := json.Marshal(testObj)
+			Expect(err).ToNot(HaveOccurred())
+			repository.GetRunTemplateReturns(templates.NewRunTemplateModel(&v1alpha1.RunTemplate{
+				Spec: v1alpha1.RunTemplateSpec{Template: runtime.RawExtension{Raw: dbytes}},
+			}), nil)
+
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			stamped, _, _ := repository.EnsureObjectExistsOnClusterArgsForCall(0)
+			Expect(stamped.Object["spec"]).To(MatchKeys(IgnoreExtras, Keys{
+				"foo": Equal("prod-eu in eu-west-1"),
+			}))
+		})
+
+		It("labels the stamped resource with its identity", func() {
+			pipeline.UID = "pipeline-uid"
+			pipeline.Name = "my-pipeline"
+			pipeline.Namespace = "my-namespace"
+
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			stamped, _, _ := repository.EnsureObjectExistsOnClusterArgsForCall(0)
+			Expect(stamped.GetLabels()).To(MatchKeys(IgnoreExtras, Keys{
+				"carto.run/owner-uid":       Equal("pipeline-uid"),
+				"carto.run/resource-name":   Equal("run"),
+				"carto.run/template-hash":   MatchRegexp(`^[0-9a-f]{16}$`),
+				"carto.run/owner-kind":      Equal("Pipeline"),
+				"carto.run/owner-name":      Equal("my-pipeline"),
+				"carto.run/owner-namespace": Equal("my-namespace"),
+				"carto.run/template-kind":   Equal("RunTemplate"),
+			}))
+		})
+
 		It("returns a happy condition", func() {
 			condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
 			Expect(*condition).To(
@@ -161,8 +212,7 @@ var _ = Describe("Realizer", func() {
 		It("returns the stampedObject", func() {
 			_, _, stampedObject := rlzr.Realize(context.TODO(), pipeline, logger, repository)
 			Expect(stampedObject.Object["spec"]).To(Equal(map[string]interface{}{
-				"foo":   "is a string",
-				"value": nil,
+				"foo": "is a string",
 			}))
 			Expect(stampedObject.Object["apiVersion"]).To(Equal("test.run/v1alpha1"))
 			Expect(stampedObject.Object["kind"]).To(Equal("Test"))
@@ -192,6 +242,193 @@ var _ = Describe("Realizer", func() {
 					}),
 				)
 			})
+
+			Context("because the controller may not create the object", func() {
+				BeforeEach(func() {
+					repository.EnsureObjectExistsOnClusterReturns(repo.ObjectError{
+						Verb: repo.CreateVerb,
+						Err:  kerrors.NewForbidden(schema.GroupResource{Group: "test.run", Resource: "tests"}, "", errors.New("no rbac")),
+					})
+				})
+
+				It("returns a condition stating that it cannot create the object", func() {
+					condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+					Expect(condition.Reason).To(Equal("CannotCreateObject"))
+				})
+			})
+
+			Context("because an access review denied creating the object", func() {
+				BeforeEach(func() {
+					repository.EnsureObjectExistsOnClusterReturns(repo.ObjectError{
+						Verb:   repo.CreateVerb,
+						Denied: true,
+						Err:    errors.New("permission denied: cannot create tests.test.run"),
+					})
+				})
+
+				It("returns a condition naming the verb and resource", func() {
+					condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+					Expect(condition.Reason).To(Equal("PermissionDenied"))
+					Expect(condition.Message).To(ContainSubstring("cannot create tests.test.run"))
+				})
+			})
+
+			Context("because a dry run of the object was rejected", func() {
+				BeforeEach(func() {
+					repository.EnsureObjectExistsOnClusterReturns(repo.ObjectError{
+						Verb:   repo.CreateVerb,
+						DryRun: true,
+						Err:    kerrors.NewBadRequest("spec.foo: Invalid value"),
+					})
+				})
+
+				It("returns a condition with the message of the API server", func() {
+					condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+					Expect(condition.Reason).To(Equal("StampedObjectRejectedByDryRun"))
+					Expect(condition.Message).To(ContainSubstring("dry-run create"))
+					Expect(condition.Message).To(ContainSubstring("spec.foo: Invalid value"))
+				})
+			})
+
+			Context("because the object does not conform to its schema", func() {
+				BeforeEach(func() {
+					repository.EnsureObjectExistsOnClusterReturns(repo.ObjectError{
+						Verb: repo.ValidateVerb,
+						Err:  errors.New(`invalid Test: unknown field "colour"`),
+					})
+				})
+
+				It("returns a condition with the fields at fault", func() {
+					condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+					Expect(condition.Reason).To(Equal("InvalidStampedObject"))
+					Expect(condition.Message).To(ContainSubstring(`unknown field "colour"`))
+				})
+			})
+
+			Context("because the kind of the object is not installed", func() {
+				BeforeEach(func() {
+					repository.EnsureObjectExistsOnClusterReturns(repo.ObjectError{
+						Verb: repo.CreateVerb,
+						Err:  &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "test.run", Kind: "Test"}, SearchedVersions: []string{"v1alpha1"}},
+					})
+				})
+
+				It("returns a condition naming the missing kind", func() {
+					condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+					Expect(condition.Reason).To(Equal("MissingAPIDependency"))
+					Expect(condition.Message).To(ContainSubstring(`no matches for kind "Test" in version "test.run/v1alpha1"`))
+				})
+			})
+		})
+
+		Context("when the stamp policies do not allow the kind of the object", func() {
+			BeforeEach(func() {
+				pipeline.Namespace = "dev"
+				repository.ListStampPoliciesReturns([]v1alpha1.ClusterStampPolicy{{
+					ObjectMeta: metav1.ObjectMeta{Name: "tenants"},
+					Spec: v1alpha1.StampPolicySpec{
+						AllowedKinds: []v1alpha1.AllowedKind{{Group: "tekton.dev", Kind: v1alpha1.AnyKind}},
+					},
+				}}, nil)
+			})
+
+			It("does not submit the object", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(repository.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+			})
+
+			It("returns a condition naming the kind", func() {
+				condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(condition.Reason).To(Equal("KindNotAllowed"))
+				Expect(condition.Message).To(Equal("kind Test.test.run may not be stamped in namespace 'dev': not allowed by ClusterStampPolicy 'tenants'"))
+			})
+		})
+
+		Context("with a retrigger annotation", func() {
+			BeforeEach(func() {
+				pipeline.Annotations = map[string]string{
+					"carto.run/retrigger": "2021-10-01T12:00:00Z",
+				}
+			})
+
+			It("copies the annotation onto the stamped object", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				stamped, _, _ := repository.EnsureObjectExistsOnClusterArgsForCall(0)
+				Expect(stamped.GetAnnotations()).To(HaveKeyWithValue("carto.run/retrigger", "2021-10-01T12:00:00Z"))
+			})
+		})
+
+		It("does not annotate the stamped object without a retrigger annotation", func() {
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			stamped, _, _ := repository.EnsureObjectExistsOnClusterArgsForCall(0)
+			Expect(stamped.GetAnnotations()).NotTo(HaveKey("carto.run/retrigger"))
+		})
+
+		Context("with an interceptor", func() {
+			It("submits the object as mutated by the interceptor", func() {
+				fakeInterceptor.BeforeSubmitStub = func(_ context.Context, submission *interceptor.Submission) error {
+					Expect(submission.Owner).To(Equal(pipeline))
+					submission.Object.SetAnnotations(map[string]string{"org.example/team": "core"})
+					return nil
+				}
+
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(fakeInterceptor.BeforeSubmitCallCount()).To(Equal(1))
+				stamped, _, _ := repository.EnsureObjectExistsOnClusterArgsForCall(0)
+				Expect(stamped.GetAnnotations()).To(Equal(map[string]string{"org.example/team": "core"}))
+			})
+
+			It("returns the outputs as mutated by the interceptor", func() {
+				fakeInterceptor.AfterSubmitStub = func(_ context.Context, submission *interceptor.Submission) error {
+					outputs, ok := submission.Outputs.(*templates.Outputs)
+					Expect(ok).To(BeTrue())
+					(*outputs)["myout"] = apiextensionsv1.JSON{Raw: []byte(`"intercepted"`)}
+					return nil
+				}
+
+				_, outputs, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(outputs["myout"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"intercepted"`)}))
+			})
+
+			Context("that fails before submission", func() {
+				BeforeEach(func() {
+					fakeInterceptor.BeforeSubmitReturns(errors.New("denied"))
+				})
+
+				It("does not submit the object", func() {
+					_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+					Expect(repository.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+				})
+
+				It("returns a condition stating that the interceptor failed", func() {
+					condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+					Expect(*condition).To(
+						MatchFields(IgnoreExtras, Fields{
+							"Type":    Equal("RunTemplateReady"),
+							"Status":  Equal(metav1.ConditionFalse),
+							"Reason":  Equal("InterceptorFailure"),
+							"Message": Equal("could not intercept stamped object: denied"),
+						}),
+					)
+				})
+			})
+
+			Context("that fails after submission", func() {
+				BeforeEach(func() {
+					fakeInterceptor.AfterSubmitReturns(errors.New("denied"))
+				})
+
+				It("returns a condition stating that the interceptor failed", func() {
+					condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+					Expect(condition.Reason).To(Equal("InterceptorFailure"))
+					Expect(condition.Message).To(Equal("could not intercept outputs: denied"))
+				})
+			})
 		})
 
 		Context("listing previously created objects fails", func() {
@@ -217,6 +454,655 @@ var _ = Describe("Realizer", func() {
 					}),
 				)
 			})
+
+			Context("because the controller may not list the objects", func() {
+				BeforeEach(func() {
+					repository.ListUnstructuredReturns(nil, repo.ObjectError{
+						Verb: repo.ListVerb,
+						Err:  kerrors.NewForbidden(schema.GroupResource{Group: "test.run", Resource: "tests"}, "", errors.New("no rbac")),
+					})
+				})
+
+				It("returns a condition stating that it cannot list created objects", func() {
+					condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+					Expect(condition.Reason).To(Equal("CannotListCreatedObjects"))
+				})
+			})
+		})
+	})
+
+	Context("with inputs that do not satisfy the RunTemplate", func() {
+		BeforeEach(func() {
+			templateAPI := &v1alpha1.RunTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-template"},
+				Spec: v1alpha1.RunTemplateSpec{
+					Inputs: []v1alpha1.RunTemplateInput{
+						{Name: "url", Required: true},
+					},
+					Template: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion": "test.run/v1alpha1", "kind": "Test"}`),
+					},
+				},
+			}
+			repository.GetRunTemplateReturns(templates.NewRunTemplateModel(templateAPI), nil)
+		})
+
+		It("does not stamp a run", func() {
+			_, _, stampedObject := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(stampedObject).To(BeNil())
+			Expect(repository.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+		})
+
+		It("returns a condition naming the invalid input", func() {
+			condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(*condition).To(
+				MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal("RunTemplateReady"),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal("InvalidInputs"),
+					"Message": Equal("invalid inputs for RunTemplate 'my-template': input 'url' is required"),
+				}),
+			)
+		})
+	})
+
+	Context("recording run history", func() {
+		var now time.Time
+
+		run := func(name string, created time.Time, pipelineName string, status string) *unstructured.Unstructured {
+			run := &unstructured.Unstructured{}
+			run.SetAPIVersion("test.run/v1alpha1")
+			run.SetKind("Test")
+			run.SetName(name)
+			run.SetCreationTimestamp(metav1.NewTime(created))
+			run.SetLabels(map[string]string{
+				"carto.run/pipeline-name":      pipelineName,
+				"carto.run/pipeline-namespace": "some-ns",
+			})
+			Expect(unstructured.SetNestedField(run.Object, "is a string", "spec", "foo")).To(Succeed())
+			if status != "" {
+				Expect(unstructured.SetNestedSlice(run.Object, []interface{}{
+					map[string]interface{}{
+						"type":               "Succeeded",
+						"status":             status,
+						"lastTransitionTime": created.Add(time.Minute).UTC().Format(time.RFC3339),
+					},
+				}, "status", "conditions")).To(Succeed())
+			}
+			return run
+		}
+
+		BeforeEach(func() {
+			pipeline.Name = "my-pipeline"
+			pipeline.Namespace = "some-ns"
+
+			templateAPI := &v1alpha1.RunTemplate{
+				Spec: v1alpha1.RunTemplateSpec{
+					Outputs: map[string]string{
+						"myout": "spec.foo",
+					},
+					Template: runtime.RawExtension{
+						Raw: []byte(D(`{
+								"apiVersion": "test.run/v1alpha1",
+								"kind": "Test",
+								"metadata": { "generateName": "my-stamped-resource-" },
+								"spec": { "foo": "is a string" }
+							}`,
+						)),
+					},
+				},
+			}
+			repository.GetRunTemplateReturns(templates.NewRunTemplateModel(templateAPI), nil)
+
+			now = time.Now().Truncate(time.Second)
+			repository.ListUnstructuredReturns([]*unstructured.Unstructured{
+				run("succeeded", now.Add(-3*time.Hour), "my-pipeline", "True"),
+				run("running", now, "my-pipeline", ""),
+				run("failed", now.Add(-2*time.Hour), "my-pipeline", "False"),
+				run("someone-elses", now.Add(-1*time.Hour), "other-pipeline", "True"),
+			}, nil)
+		})
+
+		It("records the runs stamped by the pipeline, newest first", func() {
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			history := pipeline.Status.RunHistory
+			Expect(history).To(HaveLen(3))
+
+			Expect(history[0].Name).To(Equal("running"))
+			Expect(history[0].Result).To(Equal("Running"))
+			Expect(history[0].StartTime.Time).To(BeTemporally("==", now))
+			Expect(history[0].CompletionTime).To(BeNil())
+			Expect(history[0].OutputsDigest).To(BeEmpty())
+			Expect(history[0].APIVersion).To(Equal("test.run/v1alpha1"))
+			Expect(history[0].Kind).To(Equal("Test"))
+
+			Expect(history[1].Name).To(Equal("failed"))
+			Expect(history[1].Result).To(Equal("Failed"))
+			Expect(history[1].CompletionTime.Time).To(BeTemporally("==", now.Add(-2*time.Hour+time.Minute)))
+			Expect(history[1].OutputsDigest).To(BeEmpty())
+
+			Expect(history[2].Name).To(Equal("succeeded"))
+			Expect(history[2].Result).To(Equal("Succeeded"))
+			Expect(history[2].OutputsDigest).To(MatchRegexp(`^sha256:[0-9a-f]{64}$`))
+		})
+
+		It("records at most ten runs", func() {
+			var runs []*unstructured.Unstructured
+			for i := 0; i < 12; i++ {
+				runs = append(runs, run(fmt.Sprintf("run-%d", i), now.Add(time.Duration(i)*time.Minute), "my-pipeline", "True"))
+			}
+			repository.ListUnstructuredReturns(runs, nil)
+
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(pipeline.Status.RunHistory).To(HaveLen(10))
+			Expect(pipeline.Status.RunHistory[0].Name).To(Equal("run-11"))
+			Expect(pipeline.Status.RunHistory[9].Name).To(Equal("run-2"))
+		})
+	})
+
+	Context("with a retention policy", func() {
+		var (
+			failedRuns []*unstructured.Unstructured
+			pod        *unstructured.Unstructured
+		)
+
+		failedRun := func(name string, created time.Time, pipelineName string) *unstructured.Unstructured {
+			run := &unstructured.Unstructured{}
+			run.SetAPIVersion("test.run/v1alpha1")
+			run.SetKind("Test")
+			run.SetName(name)
+			run.SetNamespace("some-ns")
+			run.SetUID(types.UID(name + "-uid"))
+			run.SetCreationTimestamp(metav1.NewTime(created))
+			run.SetLabels(map[string]string{
+				"carto.run/pipeline-name":      pipelineName,
+				"carto.run/pipeline-namespace": "some-ns",
+			})
+			Expect(unstructured.SetNestedSlice(run.Object, []interface{}{
+				map[string]interface{}{"type": "Succeeded", "status": "False"},
+			}, "status", "conditions")).To(Succeed())
+			return run
+		}
+
+		BeforeEach(func() {
+			pipeline.Name = "my-pipeline"
+			pipeline.Namespace = "some-ns"
+			pipeline.Spec.RetentionPolicy = &v1alpha1.RetentionPolicy{MaxFailedRuns: 1}
+
+			templateAPI := &v1alpha1.RunTemplate{
+				Spec: v1alpha1.RunTemplateSpec{
+					Template: runtime.RawExtension{
+						Raw: []byte(D(`{
+								"apiVersion": "test.run/v1alpha1",
+								"kind": "Test",
+								"metadata": { "generateName": "my-stamped-resource-" },
+								"spec": { "foo": "is a string" }
+							}`,
+						)),
+					},
+				},
+			}
+			repository.GetRunTemplateReturns(templates.NewRunTemplateModel(templateAPI), nil)
+
+			now := time.Now()
+			failedRuns = []*unstructured.Unstructured{
+				failedRun("newest", now, "my-pipeline"),
+				failedRun("oldest", now.Add(-2*time.Hour), "my-pipeline"),
+				failedRun("older", now.Add(-1*time.Hour), "my-pipeline"),
+			}
+
+			pod = &unstructured.Unstructured{}
+			pod.SetAPIVersion("v1")
+			pod.SetKind("Pod")
+			pod.SetName("oldest-pod")
+
+			repository.ListUnstructuredReturns(failedRuns, nil)
+			repository.ListDependentsStub = func(owner *unstructured.Unstructured, gvk schema.GroupVersionKind) ([]*unstructured.Unstructured, error) {
+				if owner.GetName() == "oldest" && gvk.Kind == "Pod" {
+					return []*unstructured.Unstructured{pod}, nil
+				}
+				return nil, nil
+			}
+		})
+
+		It("deletes the oldest failed runs beyond the limit", func() {
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(repository.DeleteCallCount()).To(Equal(2))
+			Expect(repository.DeleteArgsForCall(0).GetName()).To(Equal("oldest"))
+			Expect(repository.DeleteArgsForCall(1).GetName()).To(Equal("older"))
+		})
+
+		It("leaves the pruned runs out of the run history", func() {
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(pipeline.Status.RunHistory).To(HaveLen(1))
+			Expect(pipeline.Status.RunHistory[0].Name).To(Equal("newest"))
+		})
+
+		It("keeps a failed run in the run history when it could not be pruned", func() {
+			repository.DeleteStub = func(obj *unstructured.Unstructured) error {
+				if obj.GetName() == "oldest" {
+					return errors.New("some delete error")
+				}
+				return nil
+			}
+
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(pipeline.Status.RunHistory).To(HaveLen(2))
+			Expect(pipeline.Status.RunHistory[1].Name).To(Equal("oldest"))
+		})
+
+		It("does not delete runs that were not stamped by the pipeline", func() {
+			failedRuns = append(failedRuns, failedRun("ancient", time.Now().Add(-3*time.Hour), "another-pipeline"))
+
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(repository.DeleteCallCount()).To(Equal(2))
+			Expect(repository.DeleteArgsForCall(0).GetName()).To(Equal("oldest"))
+		})
+
+		Context("when dependents are pruned", func() {
+			BeforeEach(func() {
+				pipeline.Spec.RetentionPolicy.PruneDependents = true
+			})
+
+			It("deletes the pods owned by a failed run before the run", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(repository.DeleteCallCount()).To(Equal(3))
+				Expect(repository.DeleteArgsForCall(0).GetName()).To(Equal("oldest-pod"))
+				Expect(repository.DeleteArgsForCall(1).GetName()).To(Equal("oldest"))
+				Expect(repository.DeleteArgsForCall(2).GetName()).To(Equal("older"))
+			})
+
+			It("lists the dependents of each pruned run", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(repository.ListDependentsCallCount()).To(Equal(4))
+				owner, gvk := repository.ListDependentsArgsForCall(0)
+				Expect(owner.GetName()).To(Equal("oldest"))
+				Expect(gvk).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}))
+				_, gvk = repository.ListDependentsArgsForCall(1)
+				Expect(gvk).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}))
+			})
+
+			It("keeps the run when its dependents could not be deleted", func() {
+				repository.DeleteStub = func(obj *unstructured.Unstructured) error {
+					if obj.GetKind() == "Pod" {
+						return errors.New("some delete error")
+					}
+					return nil
+				}
+
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(out).To(Say(`"msg":"could not prune dependents of failed run"`))
+				Expect(repository.DeleteCallCount()).To(Equal(2))
+				Expect(repository.DeleteArgsForCall(1).GetName()).To(Equal("older"))
+			})
+		})
+
+		Context("when no failed runs are kept", func() {
+			BeforeEach(func() {
+				pipeline.Spec.RetentionPolicy.MaxFailedRuns = 0
+			})
+
+			It("keeps the latest run", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(repository.DeleteCallCount()).To(Equal(2))
+				Expect(repository.DeleteArgsForCall(0).GetName()).To(Equal("oldest"))
+				Expect(repository.DeleteArgsForCall(1).GetName()).To(Equal("older"))
+			})
+		})
+
+		Context("when the failed runs are within the limit", func() {
+			BeforeEach(func() {
+				pipeline.Spec.RetentionPolicy.MaxFailedRuns = 3
+			})
+
+			It("does not delete anything", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(repository.DeleteCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("with a retry policy", func() {
+		var run *unstructured.Unstructured
+
+		BeforeEach(func() {
+			pipeline.Name = "my-pipeline"
+			pipeline.Namespace = "some-ns"
+			pipeline.Generation = 3
+			pipeline.Spec.RetryPolicy = &v1alpha1.RetryPolicy{
+				Limit:   2,
+				Backoff: &metav1.Duration{Duration: time.Minute},
+			}
+
+			templateAPI := &v1alpha1.RunTemplate{
+				Spec: v1alpha1.RunTemplateSpec{
+					Template: runtime.RawExtension{
+						Raw: []byte(D(`{
+								"apiVersion": "test.run/v1alpha1",
+								"kind": "Test",
+								"metadata": { "generateName": "my-stamped-resource-" },
+								"spec": { "foo": "is a string" }
+							}`,
+						)),
+					},
+				},
+			}
+			repository.GetRunTemplateReturns(templates.NewRunTemplateModel(templateAPI), nil)
+
+			run = &unstructured.Unstructured{}
+			run.SetAPIVersion("test.run/v1alpha1")
+			run.SetKind("Test")
+			run.SetName("my-stamped-resource-abcde")
+			run.SetLabels(map[string]string{
+				"carto.run/pipeline-name":       "my-pipeline",
+				"carto.run/pipeline-namespace":  "some-ns",
+				"carto.run/pipeline-generation": "3",
+				"carto.run/run-attempt":         "0",
+			})
+			repository.ListUnstructuredReturns([]*unstructured.Unstructured{run}, nil)
+		})
+
+		failRun := func(failed time.Time) {
+			Expect(unstructured.SetNestedSlice(run.Object, []interface{}{
+				map[string]interface{}{
+					"type":               "Succeeded",
+					"status":             "False",
+					"lastTransitionTime": failed.UTC().Format(time.RFC3339),
+				},
+			}, "status", "conditions")).To(Succeed())
+		}
+
+		stampedAttempt := func() string {
+			Expect(repository.EnsureObjectExistsOnClusterCallCount()).To(Equal(1))
+			stamped, _, _ := repository.EnsureObjectExistsOnClusterArgsForCall(0)
+			Expect(stamped.GetLabels()).To(HaveKeyWithValue("carto.run/pipeline-generation", "3"))
+			return stamped.GetLabels()["carto.run/run-attempt"]
+		}
+
+		It("lists previous runs using the pipeline labels", func() {
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(repository.ListUnstructuredCallCount()).To(BeNumerically(">", 0))
+			listed := repository.ListUnstructuredArgsForCall(0)
+			Expect(listed.GetLabels()).NotTo(HaveKey("carto.run/run-attempt"))
+			Expect(listed.GetLabels()).To(HaveKeyWithValue("carto.run/pipeline-name", "my-pipeline"))
+		})
+
+		Context("and the latest run has not failed", func() {
+			It("stamps the same attempt", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(stampedAttempt()).To(Equal("0"))
+			})
+		})
+
+		Context("and the latest run failed longer ago than the backoff", func() {
+			BeforeEach(func() {
+				failRun(time.Now().Add(-2 * time.Minute))
+			})
+
+			It("stamps the next attempt", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(stampedAttempt()).To(Equal("1"))
+			})
+		})
+
+		Context("and the latest run failed within the backoff", func() {
+			BeforeEach(func() {
+				failRun(time.Now().Add(-10 * time.Second))
+			})
+
+			It("does not stamp a new run", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(repository.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+			})
+
+			It("records the failed run, so that the retry is requeued for when the backoff is over", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(pipeline.Status.RunHistory).To(HaveLen(1))
+				Expect(pipeline.Status.RunHistory[0].Result).To(Equal(v1alpha1.FailedRunResult))
+				retryAt, ok := realizer.RetryAt(pipeline)
+				Expect(ok).To(BeTrue())
+				Expect(retryAt).To(BeTemporally("~", time.Now().Add(50*time.Second), 2*time.Second))
+			})
+
+			It("returns a condition stating that the run will be retried", func() {
+				condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(*condition).To(
+					MatchFields(IgnoreExtras, Fields{
+						"Type":    Equal("RunTemplateReady"),
+						"Status":  Equal(metav1.ConditionFalse),
+						"Reason":  Equal("RetryBackoff"),
+						"Message": ContainSubstring("(attempt 1 of 2)"),
+					}),
+				)
+			})
+		})
+
+		Context("and the retries are exhausted", func() {
+			BeforeEach(func() {
+				labels := run.GetLabels()
+				labels["carto.run/run-attempt"] = "2"
+				run.SetLabels(labels)
+				failRun(time.Now().Add(-time.Hour))
+			})
+
+			It("stamps the same attempt", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(stampedAttempt()).To(Equal("2"))
+			})
+		})
+
+		Context("and the failed run belongs to an earlier generation", func() {
+			BeforeEach(func() {
+				labels := run.GetLabels()
+				labels["carto.run/pipeline-generation"] = "2"
+				labels["carto.run/run-attempt"] = "2"
+				run.SetLabels(labels)
+				failRun(time.Now().Add(-10 * time.Second))
+			})
+
+			It("starts again from the first attempt", func() {
+				_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(stampedAttempt()).To(Equal("0"))
+			})
+		})
+
+		Context("and listing the previous runs fails", func() {
+			BeforeEach(func() {
+				repository.ListUnstructuredReturns(nil, errors.New("some error"))
+			})
+
+			It("returns a condition stating that it failed to list created objects", func() {
+				condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(condition.Reason).To(Equal("FailedToListCreatedObjects"))
+				Expect(repository.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("with a ttl after finished", func() {
+		var runs []*unstructured.Unstructured
+
+		run := func(name string, created time.Time, status string, completed time.Time) *unstructured.Unstructured {
+			run := &unstructured.Unstructured{}
+			run.SetAPIVersion("test.run/v1alpha1")
+			run.SetKind("Test")
+			run.SetName(name)
+			run.SetNamespace("some-ns")
+			run.SetCreationTimestamp(metav1.NewTime(created))
+			run.SetLabels(map[string]string{
+				"carto.run/pipeline-name":      "my-pipeline",
+				"carto.run/pipeline-namespace": "some-ns",
+			})
+			if status != "" {
+				Expect(unstructured.SetNestedSlice(run.Object, []interface{}{
+					map[string]interface{}{
+						"type":               "Succeeded",
+						"status":             status,
+						"lastTransitionTime": completed.UTC().Format(time.RFC3339),
+					},
+				}, "status", "conditions")).To(Succeed())
+			}
+			return run
+		}
+
+		BeforeEach(func() {
+			pipeline.Name = "my-pipeline"
+			pipeline.Namespace = "some-ns"
+			pipeline.Spec.TTLSecondsAfterFinished = pointer.Int32Ptr(600)
+
+			templateAPI := &v1alpha1.RunTemplate{
+				Spec: v1alpha1.RunTemplateSpec{
+					Template: runtime.RawExtension{
+						Raw: []byte(D(`{
+								"apiVersion": "test.run/v1alpha1",
+								"kind": "Test",
+								"metadata": { "generateName": "my-stamped-resource-" },
+								"spec": { "foo": "is a string" }
+							}`,
+						)),
+					},
+				},
+			}
+			repository.GetRunTemplateReturns(templates.NewRunTemplateModel(templateAPI), nil)
+
+			now := time.Now()
+			runs = []*unstructured.Unstructured{
+				run("latest", now.Add(-1*time.Hour), "True", now.Add(-50*time.Minute)),
+				run("expired-success", now.Add(-3*time.Hour), "True", now.Add(-2*time.Hour)),
+				run("expired-failure", now.Add(-4*time.Hour), "False", now.Add(-3*time.Hour)),
+				run("recently-finished", now.Add(-2*time.Hour), "True", now.Add(-5*time.Minute)),
+				run("still-running", now.Add(-5*time.Hour), "", time.Time{}),
+			}
+			repository.ListUnstructuredStub = func(obj *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+				return runs, nil
+			}
+		})
+
+		It("deletes finished runs whose ttl has elapsed, keeping the latest run", func() {
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(repository.DeleteCallCount()).To(Equal(2))
+			Expect(repository.DeleteArgsForCall(0).GetName()).To(Equal("expired-success"))
+			Expect(repository.DeleteArgsForCall(1).GetName()).To(Equal("expired-failure"))
+		})
+
+		It("leaves the expired runs out of the run history", func() {
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			var names []string
+			for _, record := range pipeline.Status.RunHistory {
+				names = append(names, record.Name)
+			}
+			Expect(names).To(ConsistOf("latest", "recently-finished", "still-running"))
+		})
+
+		It("does not delete expired runs a second time for the retention policy", func() {
+			pipeline.Spec.RetentionPolicy = &v1alpha1.RetentionPolicy{MaxFailedRuns: 0}
+
+			_, _, _ = rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(repository.DeleteCallCount()).To(Equal(2))
+		})
+	})
+
+	Context("with a timeout", func() {
+		var run *unstructured.Unstructured
+
+		BeforeEach(func() {
+			pipeline.Spec.Timeout = &metav1.Duration{Duration: time.Hour}
+			pipeline.Status.Outputs = map[string]apiextensionsv1.JSON{
+				"myout": {Raw: []byte(`"a previous output"`)},
+			}
+
+			templateAPI := &v1alpha1.RunTemplate{
+				Spec: v1alpha1.RunTemplateSpec{
+					Outputs: map[string]string{
+						"myout": "spec.foo",
+					},
+					Template: runtime.RawExtension{
+						Raw: []byte(D(`{
+								"apiVersion": "test.run/v1alpha1",
+								"kind": "Test",
+								"metadata": { "generateName": "my-stamped-resource-" },
+								"spec": { "foo": "is a string" }
+							}`,
+						)),
+					},
+				},
+			}
+			repository.GetRunTemplateReturns(templates.NewRunTemplateModel(templateAPI), nil)
+
+			run = &unstructured.Unstructured{}
+			run.SetAPIVersion("test.run/v1alpha1")
+			run.SetKind("Test")
+			run.SetName("my-stamped-resource-abcde")
+			Expect(unstructured.SetNestedField(run.Object, "is a string", "spec", "foo")).To(Succeed())
+			repository.ListUnstructuredReturns([]*unstructured.Unstructured{run}, nil)
+		})
+
+		Context("and the latest run is still running after the timeout", func() {
+			BeforeEach(func() {
+				run.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-2 * time.Hour)))
+			})
+
+			It("returns a condition stating that the run timed out", func() {
+				condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(*condition).To(
+					MatchFields(IgnoreExtras, Fields{
+						"Type":    Equal("RunTemplateReady"),
+						"Status":  Equal(metav1.ConditionFalse),
+						"Reason":  Equal("RunTimedOut"),
+						"Message": Equal("run 'my-stamped-resource-abcde' did not complete within 1h0m0s"),
+					}),
+				)
+			})
+
+			It("keeps the previous outputs", func() {
+				_, outputs, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(outputs["myout"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"a previous output"`)}))
+			})
+		})
+
+		Context("and the latest run completed after the timeout", func() {
+			BeforeEach(func() {
+				run.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-2 * time.Hour)))
+				Expect(unstructured.SetNestedSlice(run.Object, []interface{}{
+					map[string]interface{}{"type": "Succeeded", "status": "True"},
+				}, "status", "conditions")).To(Succeed())
+			})
+
+			It("returns a happy condition", func() {
+				condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(condition.Reason).To(Equal("Ready"))
+			})
+		})
+
+		Context("and the latest run is still within the timeout", func() {
+			BeforeEach(func() {
+				run.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-time.Minute)))
+			})
+
+			It("does not time out", func() {
+				condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+				Expect(condition.Reason).NotTo(Equal("RunTimedOut"))
+			})
 		})
 	})
 
@@ -243,7 +1129,7 @@ var _ = Describe("Realizer", func() {
 
 			createdUnstructured = &unstructured.Unstructured{}
 
-			repository.EnsureObjectExistsOnClusterStub = func(obj *unstructured.Unstructured, allowUpdate bool) error {
+			repository.EnsureObjectExistsOnClusterStub = func(obj *unstructured.Unstructured, allowUpdate bool, _ ...repo.ApplyOption) error {
 				createdUnstructured.Object = obj.Object
 				return nil
 			}
@@ -343,5 +1229,54 @@ var _ = Describe("Realizer", func() {
 				}),
 			)
 		})
+
+		Context("when the RunTemplate is selected by labels", func() {
+			BeforeEach(func() {
+				pipeline.Spec.RunTemplateRef.Name = ""
+				pipeline.Spec.RunTemplateRef.Selector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app.tanzu.vmware.com/template": "tekton"},
+				}
+			})
+
+			It("describes the selector in the condition", func() {
+				condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+				Expect(*condition).To(
+					MatchFields(IgnoreExtras, Fields{
+						"Reason":  Equal("RunTemplateNotFound"),
+						"Message": Equal("could not get RunTemplate matching app.tanzu.vmware.com/template=tekton: Errol mcErrorFace"),
+					}),
+				)
+			})
+		})
+	})
+
+	Context("the RunTemplate reference sets both a name and a selector", func() {
+		BeforeEach(func() {
+			pipeline = &v1alpha1.Pipeline{
+				Spec: v1alpha1.PipelineSpec{
+					RunTemplateRef: v1alpha1.TemplateReference{
+						Name: "my-template",
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"app.tanzu.vmware.com/template": "tekton"},
+						},
+					},
+				},
+			}
+		})
+
+		It("does not look up the RunTemplate", func() {
+			condition, _, _ := rlzr.Realize(context.TODO(), pipeline, logger, repository)
+
+			Expect(repository.GetRunTemplateCallCount()).To(Equal(0))
+			Expect(*condition).To(
+				MatchFields(IgnoreExtras, Fields{
+					"Type":    Equal("RunTemplateReady"),
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal("RunTemplateNotFound"),
+					"Message": Equal("invalid RunTemplate reference: exactly one of name or selector must be set"),
+				}),
+			)
+		})
 	})
 })
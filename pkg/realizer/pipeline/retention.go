@@ -0,0 +1,151 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+)
+
+var dependentKinds = []string{"Pod", "PersistentVolumeClaim"}
+
+// pruneFailedRuns deletes the oldest failed runs beyond the retention
+// policy's MaxFailedRuns. The latest run is always kept, as it is the one the
+// pipeline stamps again and reads its outputs from. It returns the runs it
+// did not delete.
+func pruneFailedRuns(pipeline *v1alpha1.Pipeline, runs []*unstructured.Unstructured, repository repository.Repository, logger logr.Logger) []*unstructured.Unstructured {
+	policy := pipeline.Spec.RetentionPolicy
+	if policy == nil {
+		return runs
+	}
+
+	var failedRuns []*unstructured.Unstructured
+	for _, run := range runs {
+		if isStampedBy(run, pipeline) && succeededStatus(run) == metav1.ConditionFalse {
+			failedRuns = append(failedRuns, run)
+		}
+	}
+
+	excess := int64(len(failedRuns)) - policy.MaxFailedRuns
+	if excess <= 0 {
+		return runs
+	}
+
+	sort.SliceStable(failedRuns, func(i, j int) bool {
+		iCreated, jCreated := failedRuns[i].GetCreationTimestamp(), failedRuns[j].GetCreationTimestamp()
+		return iCreated.Before(&jCreated)
+	})
+
+	latest := latestRun(runs)
+	deleted := map[*unstructured.Unstructured]bool{}
+	for _, run := range failedRuns[:excess] {
+		if run == latest {
+			continue
+		}
+
+		if policy.PruneDependents {
+			if err := pruneDependents(run, repository); err != nil {
+				logger.Error(err, "could not prune dependents of failed run", "run", run.GetName())
+				continue
+			}
+		}
+
+		if err := repository.Delete(run); err != nil {
+			logger.Error(err, "could not prune failed run", "run", run.GetName())
+			continue
+		}
+		deleted[run] = true
+	}
+
+	var remaining []*unstructured.Unstructured
+	for _, run := range runs {
+		if !deleted[run] {
+			remaining = append(remaining, run)
+		}
+	}
+	return remaining
+}
+
+// expireFinishedRuns deletes the runs whose TTLSecondsAfterFinished has
+// elapsed since they completed, other than the latest run. It returns the
+// runs it did not delete.
+func expireFinishedRuns(pipeline *v1alpha1.Pipeline, runs []*unstructured.Unstructured, now time.Time, repository repository.Repository, logger logr.Logger) []*unstructured.Unstructured {
+	if pipeline.Spec.TTLSecondsAfterFinished == nil {
+		return runs
+	}
+	ttl := time.Duration(*pipeline.Spec.TTLSecondsAfterFinished) * time.Second
+
+	var remaining []*unstructured.Unstructured
+	latest := latestRun(runs)
+	for _, run := range runs {
+		if run == latest || !isStampedBy(run, pipeline) || !isExpired(run, ttl, now) {
+			remaining = append(remaining, run)
+			continue
+		}
+
+		if policy := pipeline.Spec.RetentionPolicy; policy != nil && policy.PruneDependents {
+			if err := pruneDependents(run, repository); err != nil {
+				logger.Error(err, "could not prune dependents of expired run", "run", run.GetName())
+				remaining = append(remaining, run)
+				continue
+			}
+		}
+
+		if err := repository.Delete(run); err != nil {
+			logger.Error(err, "could not delete expired run", "run", run.GetName())
+			remaining = append(remaining, run)
+		}
+	}
+
+	return remaining
+}
+
+func isExpired(run *unstructured.Unstructured, ttl time.Duration, now time.Time) bool {
+	switch succeededStatus(run) {
+	case metav1.ConditionTrue, metav1.ConditionFalse:
+		return !now.Before(completedAt(run).Add(ttl))
+	default:
+		return false
+	}
+}
+
+// pruneDependents deletes the Pods and PersistentVolumeClaims the run owns,
+// directly or through the objects it creates, such as the Pods of the
+// TaskRuns of a Tekton PipelineRun.
+func pruneDependents(run *unstructured.Unstructured, repository repository.Repository) error {
+	for _, kind := range dependentKinds {
+		dependents, err := repository.ListDependents(run, schema.GroupVersionKind{Version: "v1", Kind: kind})
+		if err != nil {
+			return fmt.Errorf("list %s: %w", kind, err)
+		}
+
+		for _, dependent := range dependents {
+			if err := repository.Delete(dependent); err != nil {
+				return fmt.Errorf("delete %s '%s': %w", kind, dependent.GetName(), err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+const (
+	generationLabel = "carto.run/pipeline-generation"
+	attemptLabel    = "carto.run/run-attempt"
+)
+
+// nextAttempt returns the attempt that should be stamped for the current
+// generation of the pipeline, and the earliest time it may be stamped. A
+// zero time means the attempt can be stamped straight away.
+func nextAttempt(pipeline *v1alpha1.Pipeline, runs []*unstructured.Unstructured) (int64, time.Time) {
+	policy := pipeline.Spec.RetryPolicy
+
+	var generationRuns []*unstructured.Unstructured
+	for _, run := range runs {
+		if isStampedBy(run, pipeline) && run.GetLabels()[generationLabel] == strconv.FormatInt(pipeline.Generation, 10) {
+			generationRuns = append(generationRuns, run)
+		}
+	}
+
+	run := latestRun(generationRuns)
+	if run == nil {
+		return 0, time.Time{}
+	}
+
+	attempt, _ := strconv.ParseInt(run.GetLabels()[attemptLabel], 10, 64)
+	if succeededStatus(run) != metav1.ConditionFalse || attempt >= policy.Limit {
+		return attempt, time.Time{}
+	}
+
+	retryAt := completedAt(run)
+	if policy.Backoff != nil {
+		retryAt = retryAt.Add(policy.Backoff.Duration)
+	}
+
+	return attempt + 1, retryAt
+}
+
+// RetryAt is when the failed run the pipeline is backing off from, the
+// latest in its run history, is retried, as nextAttempt computed it. It is
+// false when the latest run has not failed or the pipeline does not back off.
+func RetryAt(pipeline *v1alpha1.Pipeline) (time.Time, bool) {
+	policy := pipeline.Spec.RetryPolicy
+	if policy == nil || policy.Backoff == nil || len(pipeline.Status.RunHistory) == 0 {
+		return time.Time{}, false
+	}
+
+	latest := pipeline.Status.RunHistory[0]
+	if latest.Result != v1alpha1.FailedRunResult || latest.CompletionTime == nil {
+		return time.Time{}, false
+	}
+	return latest.CompletionTime.Add(policy.Backoff.Duration), true
+}
+
+// attemptLabels identify the generation and attempt of a stamped run, so
+// that a retry is stamped as a new object rather than matching the run that
+// failed.
+func attemptLabels(pipeline *v1alpha1.Pipeline, attempt int64) map[string]string {
+	return map[string]string{
+		generationLabel: strconv.FormatInt(pipeline.Generation, 10),
+		attemptLabel:    strconv.FormatInt(attempt, 10),
+	}
+}
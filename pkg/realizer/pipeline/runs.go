@@ -0,0 +1,114 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// isStampedBy guards against deleting anything the pipeline did not create,
+// even if it happens to be returned by the label query.
+func isStampedBy(run *unstructured.Unstructured, pipeline *v1alpha1.Pipeline) bool {
+	labels := run.GetLabels()
+	return labels["carto.run/pipeline-name"] == pipeline.Name &&
+		labels["carto.run/pipeline-namespace"] == pipeline.Namespace
+}
+
+// succeededStatus returns the status of the run's Succeeded condition, or
+// an empty string when the run has not reported one yet.
+func succeededStatus(run *unstructured.Unstructured) metav1.ConditionStatus {
+	status, _ := succeededCondition(run)["status"].(string)
+	return metav1.ConditionStatus(status)
+}
+
+// completedAt returns when the run's Succeeded condition last changed,
+// falling back to its creation when the condition carries no timestamp.
+func completedAt(run *unstructured.Unstructured) time.Time {
+	transitioned, _ := succeededCondition(run)["lastTransitionTime"].(string)
+	if completed, err := time.Parse(time.RFC3339, transitioned); err == nil {
+		return completed
+	}
+	return run.GetCreationTimestamp().Time
+}
+
+func succeededCondition(run *unstructured.Unstructured) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(run.UnstructuredContent(), "status", "conditions")
+	for _, condition := range conditions {
+		typedCondition, ok := condition.(map[string]interface{})
+		if ok && typedCondition["type"] == "Succeeded" {
+			return typedCondition
+		}
+	}
+	return nil
+}
+
+func latestRun(runs []*unstructured.Unstructured) *unstructured.Unstructured {
+	var latest *unstructured.Unstructured
+	for _, run := range runs {
+		if latest == nil {
+			latest = run
+			continue
+		}
+		runCreated, latestCreated := run.GetCreationTimestamp(), latest.GetCreationTimestamp()
+		if latestCreated.Before(&runCreated) {
+			latest = run
+		}
+	}
+	return latest
+}
+
+// TimeoutAt is when the latest run in the pipeline's run history times out.
+// It is false when the pipeline has no timeout or the run is not running.
+func TimeoutAt(pipeline *v1alpha1.Pipeline) (time.Time, bool) {
+	if pipeline.Spec.Timeout == nil || len(pipeline.Status.RunHistory) == 0 {
+		return time.Time{}, false
+	}
+
+	latest := pipeline.Status.RunHistory[0]
+	if latest.Result != v1alpha1.RunningRunResult {
+		return time.Time{}, false
+	}
+	return latest.StartTime.Add(pipeline.Spec.Timeout.Duration), true
+}
+
+// timedOutRun returns the latest run when it is still running after the
+// pipeline's timeout has elapsed since it was created.
+func timedOutRun(pipeline *v1alpha1.Pipeline, runs []*unstructured.Unstructured, now time.Time) *unstructured.Unstructured {
+	if pipeline.Spec.Timeout == nil {
+		return nil
+	}
+
+	run := latestRun(runs)
+	if run == nil {
+		return nil
+	}
+
+	switch succeededStatus(run) {
+	case metav1.ConditionTrue, metav1.ConditionFalse:
+		return nil
+	}
+
+	deadline := run.GetCreationTimestamp().Add(pipeline.Spec.Timeout.Duration)
+	if now.Before(deadline) {
+		return nil
+	}
+
+	return run
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"encoding/json"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// Artifacts returns the latest source, image and config provided by the
+// components of the supply chain, and the Knative Service serving the app:
+// each is that of the last component in realization order to provide one.
+// A component that is not realized this time keeps providing what it
+// provided before, as recorded in previous.
+func Artifacts(supplyChain *v1alpha1.ClusterSupplyChain, statuses []v1alpha1.ComponentStatus, previous *v1alpha1.WorkloadArtifacts) *v1alpha1.WorkloadArtifacts {
+	order, err := supplyChain.Spec.RealizationOrder()
+	if err != nil {
+		return previous
+	}
+	if previous == nil {
+		previous = &v1alpha1.WorkloadArtifacts{}
+	}
+
+	byName := map[string]*v1alpha1.ComponentStatus{}
+	for i := range statuses {
+		byName[statuses[i].Name] = &statuses[i]
+	}
+
+	artifacts := &v1alpha1.WorkloadArtifacts{}
+	for _, i := range order {
+		name := supplyChain.Spec.Components[i].Name
+		status, ok := byName[name]
+		if !ok || status.State != v1alpha1.RealizedComponentState {
+			if previous.Source != nil && previous.Source.Component == name {
+				artifacts.Source = previous.Source
+			}
+			if previous.Image != nil && previous.Image.Component == name {
+				artifacts.Image = previous.Image
+			}
+			if previous.Config != nil && previous.Config.Component == name {
+				artifacts.Config = previous.Config
+			}
+			if previous.Service != nil && previous.Service.Component == name {
+				artifacts.Service = previous.Service
+			}
+			continue
+		}
+
+		outputs := map[string]v1alpha1.ComponentOutput{}
+		for _, output := range status.Outputs {
+			outputs[output.Name] = output
+		}
+		if url, ok := outputs["url"]; ok {
+			artifacts.Source = &v1alpha1.SourceArtifact{
+				Component: name,
+				URL:       previewValue(url),
+				Revision:  previewValue(outputs["revision"]),
+			}
+		}
+		if image, ok := outputs["image"]; ok {
+			artifacts.Image = &v1alpha1.ImageArtifact{Component: name, Image: previewValue(image)}
+		}
+		if config, ok := outputs["config"]; ok {
+			artifacts.Config = &v1alpha1.ConfigArtifact{Component: name, StampedRef: status.StampedRef, Digest: config.Digest}
+		}
+		if status.Service != nil {
+			artifacts.Service = &v1alpha1.ServiceArtifact{Component: name, URL: status.Service.URL, LatestReadyRevision: status.Service.LatestReadyRevision}
+		}
+	}
+
+	if artifacts.Source == nil && artifacts.Image == nil && artifacts.Config == nil && artifacts.Service == nil {
+		return nil
+	}
+	return artifacts
+}
+
+// previewValue is the value of an output when it is a string, or its
+// preview as JSON otherwise.
+func previewValue(output v1alpha1.ComponentOutput) string {
+	var value string
+	if err := json.Unmarshal([]byte(output.Preview), &value); err != nil {
+		return output.Preview
+	}
+	return value
+}
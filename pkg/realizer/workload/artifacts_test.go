@@ -0,0 +1,115 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/workload"
+)
+
+var _ = Describe("Artifacts", func() {
+	var (
+		supplyChain *v1alpha1.ClusterSupplyChain
+		statuses    []v1alpha1.ComponentStatus
+		configRef   *corev1.ObjectReference
+	)
+
+	BeforeEach(func() {
+		supplyChain = &v1alpha1.ClusterSupplyChain{
+			Spec: v1alpha1.SupplyChainSpec{
+				Components: []v1alpha1.SupplyChainComponent{
+					{Name: "source-provider"},
+					{Name: "source-tester", Sources: []v1alpha1.ComponentReference{{Name: "source", Component: "source-provider"}}},
+					{Name: "image-builder", Sources: []v1alpha1.ComponentReference{{Name: "source", Component: "source-tester"}}},
+					{Name: "config", Images: []v1alpha1.ComponentReference{{Name: "image", Component: "image-builder"}}},
+				},
+			},
+		}
+		configRef = &corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "petclinic-config"}
+
+		statuses = []v1alpha1.ComponentStatus{
+			{
+				Name:    "source-provider",
+				State:   v1alpha1.RealizedComponentState,
+				Outputs: []v1alpha1.ComponentOutput{{Name: "url", Preview: `"https://example.com/source.tar.gz"`}, {Name: "revision", Preview: `"abc123"`}},
+			},
+			{
+				Name:    "source-tester",
+				State:   v1alpha1.RealizedComponentState,
+				Outputs: []v1alpha1.ComponentOutput{{Name: "url", Preview: `"https://example.com/tested.tar.gz"`}, {Name: "revision", Preview: `"abc123"`}},
+			},
+			{
+				Name:    "image-builder",
+				State:   v1alpha1.RealizedComponentState,
+				Outputs: []v1alpha1.ComponentOutput{{Name: "image", Preview: `"registry.example.com/petclinic@sha256:def456"`}},
+			},
+			{
+				Name:       "config",
+				State:      v1alpha1.RealizedComponentState,
+				StampedRef: configRef,
+				Outputs:    []v1alpha1.ComponentOutput{{Name: "config", Preview: `{"some":"config"}`, Digest: "sha256:0123"}},
+			},
+		}
+	})
+
+	It("takes each artifact from the last component to provide one", func() {
+		Expect(realizer.Artifacts(supplyChain, statuses, nil)).To(Equal(&v1alpha1.WorkloadArtifacts{
+			Source: &v1alpha1.SourceArtifact{Component: "source-tester", URL: "https://example.com/tested.tar.gz", Revision: "abc123"},
+			Image:  &v1alpha1.ImageArtifact{Component: "image-builder", Image: "registry.example.com/petclinic@sha256:def456"},
+			Config: &v1alpha1.ConfigArtifact{Component: "config", StampedRef: configRef, Digest: "sha256:0123"},
+		}))
+	})
+
+	It("keeps what a component provided before while it is not realized", func() {
+		previous := &v1alpha1.WorkloadArtifacts{
+			Source: &v1alpha1.SourceArtifact{Component: "source-tester", URL: "https://example.com/old.tar.gz", Revision: "old"},
+		}
+		statuses[1] = v1alpha1.ComponentStatus{Name: "source-tester", State: v1alpha1.WaitingComponentState}
+
+		artifacts := realizer.Artifacts(supplyChain, statuses, previous)
+		Expect(artifacts.Source).To(Equal(previous.Source))
+	})
+
+	It("falls back to an earlier component when a later one has never provided the artifact", func() {
+		statuses[1] = v1alpha1.ComponentStatus{Name: "source-tester", State: v1alpha1.WaitingComponentState}
+
+		artifacts := realizer.Artifacts(supplyChain, statuses, nil)
+		Expect(artifacts.Source).To(Equal(&v1alpha1.SourceArtifact{Component: "source-provider", URL: "https://example.com/source.tar.gz", Revision: "abc123"}))
+	})
+
+	It("takes the service from the last component to stamp a Knative Service", func() {
+		statuses = append(statuses, v1alpha1.ComponentStatus{
+			Name:    "deployer",
+			State:   v1alpha1.RealizedComponentState,
+			Service: &v1alpha1.ServiceStatus{URL: "https://petclinic.dev.example.com", LatestReadyRevision: "petclinic-00002"},
+		})
+		supplyChain.Spec.Components = append(supplyChain.Spec.Components, v1alpha1.SupplyChainComponent{Name: "deployer"})
+
+		artifacts := realizer.Artifacts(supplyChain, statuses, nil)
+		Expect(artifacts.Service).To(Equal(&v1alpha1.ServiceArtifact{
+			Component:           "deployer",
+			URL:                 "https://petclinic.dev.example.com",
+			LatestReadyRevision: "petclinic-00002",
+		}))
+	})
+
+	It("is nil when no component provides an artifact", func() {
+		Expect(realizer.Artifacts(supplyChain, nil, nil)).To(BeNil())
+	})
+})
@@ -16,40 +16,183 @@ package workload
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/artifact"
+	"github.com/vmware-tanzu/cartographer/pkg/gitops"
+	"github.com/vmware-tanzu/cartographer/pkg/identity"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/logging"
+	"github.com/vmware-tanzu/cartographer/pkg/oci"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/tracing"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 
 //counterfeiter:generate . ComponentRealizer
 type ComponentRealizer interface {
-	Do(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs Outputs) (*templates.Output, error)
+	Do(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs Outputs) (*StampedObject, *templates.Output, error)
+}
+
+// StampedObject is the object submitted for a component, with the template
+// it was stamped from and that template's health rule. Object is nil until
+// the object is submitted, and for a component resolving an artifact.
+type StampedObject struct {
+	Object      *unstructured.Unstructured
+	TemplateRef *corev1.ObjectReference
+	HealthRule  *v1alpha1.HealthRule
 }
 
+const defaultArtifactPollInterval = time.Minute
+
 type componentRealizer struct {
-	workload *v1alpha1.Workload
-	repo     repository.Repository
+	workload          *v1alpha1.Workload
+	secretParams      map[string]apiextensionsv1.JSON
+	repo              repository.Repository
+	interceptor       interceptor.Interceptor
+	resolver          artifact.Resolver
+	gitWriter         gitops.Writer
+	publisher         oci.Publisher
+	templateNamespace string
+	ownerReferences   v1alpha1.OwnerReferencePolicy
+	applyOptions      []repository.ApplyOption
+	clusterContext    templates.ClusterContext
+	targetNamespaces  []string
+	impersonated      bool
+	accessMapper      meta.RESTMapper
+}
+
+// ComponentRealizerOptions configure how a ComponentRealizer stamps and
+// writes the objects of a supply chain's components.
+type ComponentRealizerOptions struct {
+	// SecretParams are the values read for the workload's params from
+	// secrets; they reach templates as params only.
+	SecretParams map[string]apiextensionsv1.JSON
+	Interceptor  interceptor.Interceptor
+	Resolver     artifact.Resolver
+	// GitWriter commits the objects of components that write to git.
+	GitWriter gitops.Writer
+	// Publisher pushes the objects of components published as OCI
+	// artifacts.
+	Publisher oci.Publisher
+	// TemplateNamespace is where the templates of a namespaced SupplyChain
+	// are looked up first; for a ClusterSupplyChain it is empty.
+	TemplateNamespace string
+	// OwnerReferences is the policy of the supply chain for how stamped
+	// objects refer to the workload.
+	OwnerReferences v1alpha1.OwnerReferencePolicy
+	// ServerSideApply are the supply chain's settings for applying stamped
+	// objects, if any.
+	ServerSideApply *v1alpha1.ServerSideApplySettings
+	// ClusterContext is stamped into every template.
+	ClusterContext templates.ClusterContext
+	// ApplyOptions choose how stamped objects are applied.
+	ApplyOptions []repository.ApplyOption
+	// AllowedTargetNamespaces are the namespaces other than the workload's
+	// that objects may be stamped into.
+	AllowedTargetNamespaces []string
+	// Impersonated tells whether the ApplyOptions write objects as a
+	// service account rather than as the controller. Objects are only
+	// stamped into another namespace than the workload's as a service
+	// account, whose access to it is reviewed with the AccessMapper before
+	// they are written.
+	Impersonated bool
+	AccessMapper meta.RESTMapper
 }
 
-func NewComponentRealizer(workload *v1alpha1.Workload, repo repository.Repository) ComponentRealizer {
+// NewComponentRealizer realizes the components of a supply chain for the
+// workload, as the opts configure.
+func NewComponentRealizer(workload *v1alpha1.Workload, repo repository.Repository, opts ComponentRealizerOptions) ComponentRealizer {
 	return &componentRealizer{
-		workload: workload,
-		repo:     repo,
+		workload:          workload,
+		secretParams:      opts.SecretParams,
+		repo:              repo,
+		interceptor:       opts.Interceptor,
+		resolver:          opts.Resolver,
+		gitWriter:         opts.GitWriter,
+		publisher:         opts.Publisher,
+		templateNamespace: opts.TemplateNamespace,
+		ownerReferences:   opts.OwnerReferences,
+		applyOptions:      append(applyOptions(opts.ServerSideApply), opts.ApplyOptions...),
+		clusterContext:    opts.ClusterContext,
+		targetNamespaces:  opts.AllowedTargetNamespaces,
+		impersonated:      opts.Impersonated,
+		accessMapper:      opts.AccessMapper,
 	}
 }
 
-func (r *componentRealizer) Do(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs Outputs) (*templates.Output, error) {
-	template, err := r.repo.GetClusterTemplate(component.TemplateRef)
+func applyOptions(settings *v1alpha1.ServerSideApplySettings) []repository.ApplyOption {
+	if settings == nil {
+		return nil
+	}
+
+	opts := []repository.ApplyOption{repository.WithFieldManager(settings.FieldManager)}
+	if settings.ForceConflicts != nil {
+		opts = append(opts, repository.WithForceConflicts(*settings.ForceConflicts))
+	}
+	return opts
+}
+
+func (r *componentRealizer) getTemplate(ref v1alpha1.ClusterTemplateReference) (templates.Template, error) {
+	if r.templateNamespace == "" {
+		return r.repo.GetClusterTemplate(ref)
+	}
+	return r.repo.GetTemplate(ref, r.templateNamespace)
+}
+
+func (r *componentRealizer) Do(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs Outputs) (stamped *StampedObject, output *templates.Output, err error) {
+	defer observe(r.workload.Namespace, supplyChainName, component.Name, time.Now(), &err)
+	ctx, span := tracing.Start(ctx, "Realize component", tracing.String("supply_chain", supplyChainName), tracing.String("component", component.Name))
+	defer func() { span.End(err) }()
+
+	logger := logr.FromContextOrDiscard(ctx).WithName(logging.Realizer).WithValues("component", component.Name)
+	ctx = logr.NewContext(ctx, logger)
+	logger.V(1).Info("realizing component", "supplyChain", supplyChainName)
+	defer func() {
+		if err != nil {
+			logger.V(1).Info("component not realized", "reason", err.Error())
+		} else {
+			logger.V(1).Info("component realized")
+		}
+	}()
+
+	return r.realize(ctx, component, supplyChainName, outputs)
+}
+
+func (r *componentRealizer) realize(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs Outputs) (*StampedObject, *templates.Output, error) {
+	templateRef, err := SelectTemplateRef(r.workload, component)
 	if err != nil {
-		return nil, GetClusterTemplateError{
+		return nil, nil, err
+	}
+
+	_, span := tracing.Start(ctx, "Get template", tracing.String("kind", templateRef.Kind), tracing.String("name", templateRef.Name))
+	template, err := r.getTemplate(templateRef)
+	span.End(err)
+	if err != nil {
+		return nil, nil, GetClusterTemplateError{
 			Err:         err,
-			TemplateRef: component.TemplateRef,
+			TemplateRef: templateRef,
 		}
 	}
 
+	stamped := &StampedObject{
+		TemplateRef: r.templateRef(template),
+		HealthRule:  template.GetResourceTemplate().HealthRule,
+	}
+
 	labels := map[string]string{
 		"carto.run/workload-name":             r.workload.Name,
 		"carto.run/workload-namespace":        r.workload.Namespace,
@@ -59,13 +202,25 @@ func (r *componentRealizer) Do(ctx context.Context, component *v1alpha1.SupplyCh
 		"carto.run/cluster-template-name":     template.GetName(),
 	}
 
+	params, err := templates.ParamsBuilder(template.GetDefaultParams(), component.Params, r.workloadParams())
+	if err != nil {
+		return stamped, nil, ParamsError{
+			Err:              err,
+			Component:        component,
+			TemplateMetadata: template.GetResourceTemplate().Metadata,
+		}
+	}
+
 	inputs := outputs.GenerateInputs(component)
 	workloadTemplatingContext := map[string]interface{}{
 		"workload": r.workload,
-		"params":   templates.ParamsBuilder(template.GetDefaultParams(), component.Params),
+		"params":   params,
 		"sources":  inputs.Sources,
 		"images":   inputs.Images,
 		"configs":  inputs.Configs,
+		"inputs":   inputs.Collections,
+
+		"clusterContext": r.clusterContext,
 	}
 	if inputs.OnlyConfig() != nil {
 		workloadTemplatingContext["config"] = inputs.OnlyConfig()
@@ -77,30 +232,443 @@ func (r *componentRealizer) Do(ctx context.Context, component *v1alpha1.SupplyCh
 		workloadTemplatingContext["source"] = inputs.OnlySource()
 	}
 
+	if component.Hooks != nil {
+		if err := r.runPreHooks(ctx, component, workloadTemplatingContext, labels); err != nil {
+			return stamped, nil, err
+		}
+	}
+
 	stampContext := templates.StamperBuilder(r.workload, workloadTemplatingContext, labels)
-	stampedObject, err := stampContext.Stamp(ctx, template.GetResourceTemplate())
+	stampContext.OwnerReferences = r.ownerReferences
+	stampedObject, output, err := r.submit(ctx, component, supplyChainName, template, stampContext)
+	stamped.Object = stampedObject
 	if err != nil {
-		return nil, StampError{
+		return stamped, nil, err
+	}
+
+	if component.Hooks != nil {
+		if err := r.runPostHooks(ctx, component, workloadTemplatingContext, labels, output); err != nil {
+			return stamped, nil, err
+		}
+	}
+
+	return stamped, output, nil
+}
+
+// templateRef refers to the template, which is in the template namespace
+// unless it is cluster-scoped.
+func (r *componentRealizer) templateRef(template templates.Template) *corev1.ObjectReference {
+	ref := &corev1.ObjectReference{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       template.GetKind(),
+		Name:       template.GetName(),
+	}
+	if !strings.HasPrefix(template.GetKind(), "Cluster") {
+		ref.Namespace = r.templateNamespace
+	}
+	return ref
+}
+
+// submit stamps and submits the component's object, or resolves its
+// artifact, and returns the object submitted, if any, and the component's
+// outputs.
+func (r *componentRealizer) submit(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, template templates.Template, stampContext templates.Stamper) (*unstructured.Unstructured, *templates.Output, error) {
+	if artifactTemplate, ok := template.(templates.ArtifactTemplate); ok && artifactTemplate.GetArtifactSource() != nil {
+		output, err := r.resolveArtifact(ctx, component, stampContext, artifactTemplate.GetArtifactSource(), template.GetResourceTemplate().Metadata)
+		return nil, output, err
+	}
+
+	stampCtx, span := tracing.Start(ctx, "Stamp", tracing.String("template", template.GetName()))
+	stampedObject, err := stampContext.Stamp(stampCtx, template.GetResourceTemplate())
+	span.End(err)
+	if err != nil {
+		return nil, nil, StampError{
+			Err:              err,
+			Component:        component,
+			TemplateMetadata: template.GetResourceTemplate().Metadata,
+		}
+	}
+
+	if component.Namespace != "" {
+		namespace, err := stampContext.Interpolate(component.Namespace)
+		if err != nil {
+			return nil, nil, StampError{
+				Err:              fmt.Errorf("namespace: %w", err),
+				Component:        component,
+				TemplateMetadata: template.GetResourceTemplate().Metadata,
+			}
+		}
+		stampedObject.SetNamespace(namespace)
+	}
+	if stampedObject.GetNamespace() != r.workload.Namespace {
+		// owner references cannot cross namespaces; the garbage collector
+		// would delete the object as if its owner were gone.
+		stampedObject.SetOwnerReferences(nil)
+	}
+
+	if err := r.checkNamespace(component, stampedObject); err != nil {
+		return nil, nil, KindNotAllowedError{
+			Err:              err,
+			Component:        component,
+			TemplateMetadata: template.GetResourceTemplate().Metadata,
+		}
+	}
+
+	policies, err := r.repo.ListStampPolicies()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := v1alpha1.CheckStampPolicies(policies, r.policyNamespace(stampedObject), stampedObject.GroupVersionKind().GroupKind()); err != nil {
+		return nil, nil, KindNotAllowedError{
+			Err:              err,
+			Component:        component,
+			TemplateMetadata: template.GetResourceTemplate().Metadata,
+		}
+	}
+
+	templateHash, err := identity.TemplateHash(template.GetResourceTemplate())
+	if err != nil {
+		return nil, nil, StampError{
+			Err:              err,
+			Component:        component,
+			TemplateMetadata: template.GetResourceTemplate().Metadata,
+		}
+	}
+	identity.Apply(stampedObject, identity.Identity{
+		OwnerUID:       r.workload.UID,
+		ResourceName:   component.Name,
+		TemplateHash:   templateHash,
+		OwnerKind:      "Workload",
+		OwnerName:      r.workload.Name,
+		OwnerNamespace: r.workload.Namespace,
+		SupplyChain:    supplyChainName,
+		TemplateKind:   template.GetKind(),
+		TemplateName:   template.GetName(),
+	})
+	logging.LogStampedObject(logr.FromContextOrDiscard(ctx), stampedObject)
+
+	submission := &interceptor.Submission{Owner: r.workload, Object: stampedObject}
+	err = r.interceptor.BeforeSubmit(ctx, submission)
+	if err != nil {
+		return nil, nil, InterceptError{
 			Err:       err,
 			Component: component,
 		}
 	}
 
-	err = r.repo.EnsureObjectExistsOnCluster(stampedObject, true)
+	if component.GitOps != nil {
+		return r.writeToGit(ctx, component, template, stampContext, submission)
+	}
+	if component.OCIArtifact != nil {
+		return r.publishArtifact(ctx, component, template, stampContext, submission)
+	}
+
+	applyOptions := r.applyOptions
+	if stampedObject.GetNamespace() != r.workload.Namespace {
+		applyOptions = append(append([]repository.ApplyOption{}, r.applyOptions...), repository.WithAccessReview(r.accessMapper))
+	}
+	_, span = tracing.Start(ctx, "Apply", tracing.String("kind", stampedObject.GetKind()), tracing.String("name", stampedObject.GetName()))
+	if component.Adopt {
+		err = r.repo.AdoptObjectOnCluster(stampedObject, applyOptions...)
+	} else {
+		err = r.repo.EnsureObjectExistsOnCluster(stampedObject, true, applyOptions...)
+	}
+	span.End(err)
 	if err != nil {
-		return nil, ApplyStampedObjectError{
-			Err:           err,
-			StampedObject: stampedObject,
+		return nil, nil, ApplyStampedObjectError{
+			Err:              err,
+			StampedObject:    stampedObject,
+			TemplateMetadata: template.GetResourceTemplate().Metadata,
 		}
 	}
 
+	_, span = tracing.Start(ctx, "Read outputs")
 	output, err := template.GetOutput(stampedObject)
+	span.End(err)
+	if err != nil {
+		return stampedObject, nil, RetrieveOutputError{
+			Err:       err,
+			component: component,
+		}
+	}
+
+	submission.Outputs = output
+	err = r.interceptor.AfterSubmit(ctx, submission)
+	if err != nil {
+		return stampedObject, nil, InterceptError{
+			Err:       err,
+			Component: component,
+		}
+	}
+
+	return stampedObject, output, nil
+}
+
+// writeToGit commits the submitted object to the component's Git
+// repository, instead of applying it, and reads the component's outputs from
+// the object as stamped. The object is not returned, as there is nothing of
+// it on the cluster to track.
+func (r *componentRealizer) writeToGit(ctx context.Context, component *v1alpha1.SupplyChainComponent, template templates.Template, stampContext templates.Stamper, submission *interceptor.Submission) (*unstructured.Unstructured, *templates.Output, error) {
+	metadata := template.GetResourceTemplate().Metadata
+	target := component.GitOps
+
+	branch := target.Branch
+	if branch == "" {
+		branch = v1alpha1.DefaultGitOpsBranch
+	}
+	branch, err := stampContext.Interpolate(branch)
+	if err != nil {
+		return nil, nil, StampError{Err: fmt.Errorf("gitOps branch: %w", err), Component: component, TemplateMetadata: metadata}
+	}
+	path := fmt.Sprintf("%s/%s/%s.yaml", r.workload.Namespace, r.workload.Name, component.Name)
+	if target.Path != "" {
+		if path, err = stampContext.Interpolate(target.Path); err != nil {
+			return nil, nil, StampError{Err: fmt.Errorf("gitOps path: %w", err), Component: component, TemplateMetadata: metadata}
+		}
+	}
+
+	if r.gitWriter == nil {
+		return nil, nil, GitWriteError{Err: errors.New("the controller cannot write to git"), Component: component, TemplateMetadata: metadata}
+	}
+	repository := gitops.Repository{URL: target.URL, Branch: branch}
+	if target.SecretRef != nil {
+		data, err := r.repo.GetSecretData(target.SecretRef.Name, r.workload.Namespace)
+		if err != nil {
+			return nil, nil, GitWriteError{Err: fmt.Errorf("read credentials: %w", err), Component: component, TemplateMetadata: metadata}
+		}
+		repository.Username, repository.Password = string(data[corev1.BasicAuthUsernameKey]), string(data[corev1.BasicAuthPasswordKey])
+	}
+
+	content, err := manifestOf(submission.Object)
+	if err != nil {
+		return nil, nil, GitWriteError{Err: fmt.Errorf("marshal manifest: %w", err), Component: component, TemplateMetadata: metadata}
+	}
+
+	_, span := tracing.Start(ctx, "Write to git", tracing.String("url", target.URL), tracing.String("path", path))
+	err = r.gitWriter.Write(ctx, repository, path, content, fmt.Sprintf("Update %s of workload %s/%s", component.Name, r.workload.Namespace, r.workload.Name))
+	span.End(err)
+	if err != nil {
+		return nil, nil, GitWriteError{Err: err, Component: component, TemplateMetadata: metadata}
+	}
+
+	output, err := template.GetOutput(submission.Object)
+	if err != nil {
+		return nil, nil, RetrieveOutputError{
+			Err:       err,
+			component: component,
+		}
+	}
+
+	submission.Outputs = output
+	if err := r.interceptor.AfterSubmit(ctx, submission); err != nil {
+		return nil, nil, InterceptError{
+			Err:       err,
+			Component: component,
+		}
+	}
+
+	return nil, output, nil
+}
+
+// publishArtifact pushes the submitted object as the component's OCI
+// artifact, instead of applying it, and reads the component's outputs from
+// the object as stamped, with the artifact as its source. The object is not
+// returned, as there is nothing of it on the cluster to track.
+func (r *componentRealizer) publishArtifact(ctx context.Context, component *v1alpha1.SupplyChainComponent, template templates.Template, stampContext templates.Stamper, submission *interceptor.Submission) (*unstructured.Unstructured, *templates.Output, error) {
+	metadata := template.GetResourceTemplate().Metadata
+	target := component.OCIArtifact
+
+	image, err := stampContext.Interpolate(target.Image)
+	if err != nil {
+		return nil, nil, StampError{Err: fmt.Errorf("ociArtifact image: %w", err), Component: component, TemplateMetadata: metadata}
+	}
+	ref, err := oci.ParseReference(image)
+	if err != nil {
+		return nil, nil, StampError{Err: fmt.Errorf("ociArtifact image: %w", err), Component: component, TemplateMetadata: metadata}
+	}
+
+	if r.publisher == nil {
+		return nil, nil, PublishArtifactError{Err: errors.New("the controller cannot publish OCI artifacts"), Component: component, TemplateMetadata: metadata}
+	}
+	var credentials oci.Credentials
+	if target.SecretRef != nil {
+		data, err := r.repo.GetSecretData(target.SecretRef.Name, r.workload.Namespace)
+		if err != nil {
+			return nil, nil, PublishArtifactError{Err: fmt.Errorf("read credentials: %w", err), Component: component, TemplateMetadata: metadata}
+		}
+		credentials, err = oci.DockerConfigCredentials(data[corev1.DockerConfigJsonKey], ref.Registry)
+		if err != nil {
+			return nil, nil, PublishArtifactError{Err: fmt.Errorf("read credentials: %w", err), Component: component, TemplateMetadata: metadata}
+		}
+	}
+
+	content, err := manifestOf(submission.Object)
 	if err != nil {
-		return nil, RetrieveOutputError{
+		return nil, nil, PublishArtifactError{Err: fmt.Errorf("marshal manifest: %w", err), Component: component, TemplateMetadata: metadata}
+	}
+
+	_, span := tracing.Start(ctx, "Publish artifact", tracing.String("image", image))
+	digest, err := r.publisher.Publish(ctx, image, map[string][]byte{component.Name + ".yaml": content}, credentials)
+	span.End(err)
+	if err != nil {
+		return nil, nil, PublishArtifactError{Err: err, Component: component, TemplateMetadata: metadata}
+	}
+
+	output, err := template.GetOutput(submission.Object)
+	if err != nil {
+		return nil, nil, RetrieveOutputError{
 			Err:       err,
 			component: component,
 		}
 	}
+	if output == nil {
+		output = &templates.Output{}
+	}
+	output.Source = &templates.Source{URL: ref.Name() + "@" + digest, Revision: digest}
+
+	submission.Outputs = output
+	if err := r.interceptor.AfterSubmit(ctx, submission); err != nil {
+		return nil, nil, InterceptError{
+			Err:       err,
+			Component: component,
+		}
+	}
+
+	return nil, output, nil
+}
+
+// manifestOf is the manifest of the object as written outside the cluster.
+// The owner references refer to the workload on this cluster, which the
+// cluster the manifest is applied to does not have.
+func manifestOf(obj *unstructured.Unstructured) ([]byte, error) {
+	manifest := obj.DeepCopy()
+	manifest.SetOwnerReferences(nil)
+	return yaml.Marshal(manifest.Object)
+}
+
+// workloadParams are the workload's params, with the values of those from
+// secrets filled in. A param from a secret that was not read is left out.
+func (r *componentRealizer) workloadParams() []v1alpha1.WorkloadParam {
+	params := make([]v1alpha1.WorkloadParam, 0, len(r.workload.Spec.Params))
+	for _, param := range r.workload.Spec.Params {
+		if param.ValueFrom != nil {
+			value, ok := r.secretParams[param.Name]
+			if !ok {
+				continue
+			}
+			param = v1alpha1.WorkloadParam{Name: param.Name, Value: value}
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// checkNamespace rejects an object a namespaced SupplyChain would write to
+// the cluster outside its own namespace: into another namespace, or in the
+// cluster scope. Those who may write a SupplyChain in a namespace may not
+// write anywhere else through it. The objects of a ClusterSupplyChain are
+// checked by checkTargetNamespace.
+func (r *componentRealizer) checkNamespace(component *v1alpha1.SupplyChainComponent, obj *unstructured.Unstructured) error {
+	if component.GitOps != nil || component.OCIArtifact != nil {
+		return nil
+	}
+	if r.templateNamespace == "" {
+		return r.checkTargetNamespace(obj)
+	}
+
+	namespaced, err := r.repo.IsNamespaced(obj.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return fmt.Errorf("kind %s is cluster-scoped, but supply chains in namespace '%s' may only stamp objects into it", obj.GetKind(), r.templateNamespace)
+	}
+	if obj.GetNamespace() != r.templateNamespace {
+		return fmt.Errorf("namespace '%s' is not that of the supply chain, '%s'", obj.GetNamespace(), r.templateNamespace)
+	}
+	return nil
+}
+
+// checkTargetNamespace rejects an object a ClusterSupplyChain would write
+// into another namespace than the workload's, which a param of the workload
+// may choose, unless the supply chain allows the namespace and the object
+// is written as a service account, whose access is reviewed before it is.
+func (r *componentRealizer) checkTargetNamespace(obj *unstructured.Unstructured) error {
+	namespace := obj.GetNamespace()
+	if namespace == r.workload.Namespace {
+		return nil
+	}
+	if !containsString(r.targetNamespaces, namespace) {
+		return fmt.Errorf("namespace '%s' is not one of the allowedTargetNamespaces of the supply chain", namespace)
+	}
+	if !r.impersonated || r.accessMapper == nil {
+		return fmt.Errorf("objects are only stamped into namespace '%s' as a service account, but the workload is realized as the controller", namespace)
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// policyNamespace is the namespace whose ClusterStampPolicies apply to the
+// object: its own, or the workload's for a cluster-scoped object.
+func (r *componentRealizer) policyNamespace(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() != "" {
+		return obj.GetNamespace()
+	}
+	return r.workload.Namespace
+}
+
+func (r *componentRealizer) resolveArtifact(ctx context.Context, component *v1alpha1.SupplyChainComponent, stampContext templates.Stamper, source *v1alpha1.ArtifactSource, metadata *v1alpha1.TemplateMetadata) (*templates.Output, error) {
+	name, err := stampContext.Interpolate(source.Name)
+	if err != nil {
+		return nil, StampError{
+			Err:              err,
+			Component:        component,
+			TemplateMetadata: metadata,
+		}
+	}
+
+	version, err := stampContext.Interpolate(source.Version)
+	if err != nil {
+		return nil, StampError{
+			Err:              err,
+			Component:        component,
+			TemplateMetadata: metadata,
+		}
+	}
+
+	pollInterval := defaultArtifactPollInterval
+	if source.PollInterval != nil {
+		pollInterval = source.PollInterval.Duration
+	}
+
+	resolveCtx, span := tracing.Start(ctx, "Resolve artifact", tracing.String("name", name), tracing.String("version", version))
+	resolved, err := r.resolver.Resolve(resolveCtx, artifact.Coordinate{
+		Type:     source.Type,
+		Registry: source.Registry,
+		Name:     name,
+		Version:  version,
+	}, pollInterval)
+	span.End(err)
+	if err != nil {
+		return nil, ResolveArtifactError{
+			Err:              err,
+			Component:        component,
+			TemplateMetadata: metadata,
+		}
+	}
 
-	return output, nil
+	return &templates.Output{
+		Source: &templates.Source{
+			URL:      resolved.URL,
+			Revision: resolved.Version,
+		},
+	}, nil
 }
@@ -19,18 +19,36 @@ import (
 	"encoding/json"
 	"errors"
 	"reflect"
+	"time"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/artifact"
+	"github.com/vmware-tanzu/cartographer/pkg/artifact/artifactfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/eval"
+	"github.com/vmware-tanzu/cartographer/pkg/gitops"
+	"github.com/vmware-tanzu/cartographer/pkg/gitops/gitopsfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor/interceptorfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/oci"
+	"github.com/vmware-tanzu/cartographer/pkg/oci/ocifakes"
 	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/workload"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/tracing"
 )
 
 var _ = Describe("Component", func() {
@@ -41,6 +59,8 @@ var _ = Describe("Component", func() {
 		outputs         realizer.Outputs
 		supplyChainName string
 		fakeRepo        repositoryfakes.FakeRepository
+		fakeInterceptor *interceptorfakes.FakeInterceptor
+		fakeResolver    *artifactfakes.FakeResolver
 		r               realizer.ComponentRealizer
 	)
 
@@ -58,8 +78,10 @@ var _ = Describe("Component", func() {
 		outputs = realizer.NewOutputs()
 
 		fakeRepo = repositoryfakes.FakeRepository{}
-		workload = v1alpha1.Workload{}
-		r = realizer.NewComponentRealizer(&workload, &fakeRepo)
+		workload = v1alpha1.Workload{ObjectMeta: metav1.ObjectMeta{Namespace: "some-namespace"}}
+		fakeInterceptor = &interceptorfakes.FakeInterceptor{}
+		fakeResolver = &artifactfakes.FakeResolver{}
+		r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{Interceptor: fakeInterceptor, Resolver: fakeResolver, ClusterContext: templates.ClusterContext{Name: "prod-eu", IngressDomain: "apps.example.com"}})
 	})
 
 	Describe("Do", func() {
@@ -89,6 +111,7 @@ var _ = Describe("Component", func() {
 					Data: map[string]string{
 						"player_current_lives": `$(source.url)$`,
 						"some_other_info":      `$(sources.source-provider.revision)$`,
+						"host":                 `petclinic.$(clusterContext.ingressDomain)$`,
 					},
 				}
 
@@ -106,7 +129,8 @@ var _ = Describe("Component", func() {
 					},
 					Spec: v1alpha1.ImageTemplateSpec{
 						TemplateSpec: v1alpha1.TemplateSpec{
-							Template: &runtime.RawExtension{Raw: dbytes},
+							Template:   &runtime.RawExtension{Raw: dbytes},
+							HealthRule: &v1alpha1.HealthRule{SingleConditionType: "Ready"},
 						},
 						ImagePath: "data.some_other_info",
 					},
@@ -118,11 +142,19 @@ var _ = Describe("Component", func() {
 			})
 
 			It("creates a stamped object and returns the outputs", func() {
-				out, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				workload.Namespace = "some-namespace"
+				stamped, out, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
 				Expect(err).ToNot(HaveOccurred())
 
-				stampedObject, allowUpdate := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(0)
+				stampedObject, allowUpdate, _ := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(0)
 				Expect(allowUpdate).To(BeTrue())
+				Expect(stamped.Object).To(BeIdenticalTo(stampedObject))
+				Expect(stamped.HealthRule).To(Equal(&v1alpha1.HealthRule{SingleConditionType: "Ready"}))
+				Expect(stamped.TemplateRef).To(Equal(&corev1.ObjectReference{
+					APIVersion: "carto.run/v1alpha1",
+					Kind:       "ClusterImageTemplate",
+					Name:       "image-template-1",
+				}))
 				metadata := stampedObject.Object["metadata"]
 				metadataValues, ok := metadata.(map[string]interface{})
 				Expect(ok).To(BeTrue())
@@ -138,18 +170,498 @@ var _ = Describe("Component", func() {
 						"blockOwnerDeletion": true,
 					},
 				}))
-				Expect(metadataValues["labels"]).To(Equal(map[string]interface{}{
-					"carto.run/cluster-supply-chain-name": "supply-chain-name",
-					"carto.run/component-name":            "component-1",
-					"carto.run/cluster-template-name":     "image-template-1",
-					"carto.run/workload-name":             "",
-					"carto.run/workload-namespace":        "",
-					"carto.run/template-kind":             "ClusterImageTemplate",
+				Expect(metadataValues["labels"]).To(MatchAllKeys(Keys{
+					"carto.run/cluster-supply-chain-name": Equal("supply-chain-name"),
+					"carto.run/component-name":            Equal("component-1"),
+					"carto.run/cluster-template-name":     Equal("image-template-1"),
+					"carto.run/workload-name":             Equal(""),
+					"carto.run/workload-namespace":        Equal("some-namespace"),
+					"carto.run/template-kind":             Equal("ClusterImageTemplate"),
+					"carto.run/owner-uid":                 Equal(""),
+					"carto.run/resource-name":             Equal("component-1"),
+					"carto.run/template-hash":             MatchRegexp(`^[0-9a-f]{16}$`),
+					"carto.run/owner-kind":                Equal("Workload"),
+					"carto.run/owner-namespace":           Equal("some-namespace"),
+					"carto.run/supply-chain-name":         Equal("supply-chain-name"),
+					"carto.run/template-name":             Equal("image-template-1"),
 				}))
-				Expect(stampedObject.Object["data"]).To(Equal(map[string]interface{}{"player_current_lives": "some-url", "some_other_info": "some-revision"}))
+				Expect(stampedObject.Object["data"]).To(Equal(map[string]interface{}{"player_current_lives": "some-url", "some_other_info": "some-revision", "host": "petclinic.apps.example.com"}))
 
 				Expect(out.Image).To(Equal("some-revision"))
 			})
+
+			Context("when the component writes to git", func() {
+				var gitWriter *gitopsfakes.FakeWriter
+
+				BeforeEach(func() {
+					workload.Name = "petclinic"
+					workload.Namespace = "some-namespace"
+					component.GitOps = &v1alpha1.GitOpsTarget{URL: "https://github.com/acme/config.git"}
+					gitWriter = &gitopsfakes.FakeWriter{}
+					r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{Interceptor: fakeInterceptor, Resolver: fakeResolver, GitWriter: gitWriter})
+				})
+
+				It("commits the manifest instead of applying it, and returns the outputs", func() {
+					stamped, out, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+					Expect(stamped.Object).To(BeNil())
+					Expect(out.Image).To(Equal("some-revision"))
+
+					Expect(gitWriter.WriteCallCount()).To(Equal(1))
+					_, repository, path, content, message := gitWriter.WriteArgsForCall(0)
+					Expect(repository).To(Equal(gitops.Repository{URL: "https://github.com/acme/config.git", Branch: "main"}))
+					Expect(path).To(Equal("some-namespace/petclinic/component-1.yaml"))
+					Expect(message).To(Equal("Update component-1 of workload some-namespace/petclinic"))
+
+					manifest := &unstructured.Unstructured{}
+					Expect(yaml.Unmarshal(content, &manifest.Object)).To(Succeed())
+					Expect(manifest.GetName()).To(Equal("example-config-map"))
+					Expect(manifest.GetOwnerReferences()).To(BeEmpty())
+					Expect(manifest.Object["data"]).To(HaveKeyWithValue("some_other_info", "some-revision"))
+				})
+
+				It("interpolates the branch and path", func() {
+					component.GitOps.Branch = "env/$(workload.metadata.namespace)$"
+					component.GitOps.Path = "apps/$(workload.metadata.name)$.yaml"
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, repository, path, _, _ := gitWriter.WriteArgsForCall(0)
+					Expect(repository.Branch).To(Equal("env/some-namespace"))
+					Expect(path).To(Equal("apps/petclinic.yaml"))
+				})
+
+				It("writes with the credentials of the secret in the workload's namespace", func() {
+					component.GitOps.SecretRef = &corev1.LocalObjectReference{Name: "git-credentials"}
+					fakeRepo.GetSecretDataReturns(map[string][]byte{"username": []byte("bot"), "password": []byte("s3cr3t")}, nil)
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).ToNot(HaveOccurred())
+
+					name, namespace := fakeRepo.GetSecretDataArgsForCall(0)
+					Expect(name).To(Equal("git-credentials"))
+					Expect(namespace).To(Equal("some-namespace"))
+					_, repository, _, _, _ := gitWriter.WriteArgsForCall(0)
+					Expect(repository.Username).To(Equal("bot"))
+					Expect(repository.Password).To(Equal("s3cr3t"))
+				})
+
+				It("returns GitWriteError when the manifest cannot be committed", func() {
+					gitWriter.WriteReturns(errors.New("git push: rejected"))
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(MatchError("unable to write object of component 'component-1' to git: git push: rejected"))
+					Expect(reflect.TypeOf(err).String()).To(Equal("workload.GitWriteError"))
+				})
+			})
+
+			Context("when the component is published as an OCI artifact", func() {
+				var publisher *ocifakes.FakePublisher
+
+				BeforeEach(func() {
+					workload.Name = "petclinic"
+					workload.Namespace = "some-namespace"
+					component.OCIArtifact = &v1alpha1.OCIArtifactTarget{Image: "registry.example.com/team/$(workload.metadata.name)$-config"}
+					publisher = &ocifakes.FakePublisher{}
+					publisher.PublishReturns("sha256:abc123", nil)
+					r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{Interceptor: fakeInterceptor, Resolver: fakeResolver, Publisher: publisher})
+				})
+
+				It("publishes the manifest instead of applying it, and outputs the artifact as its source", func() {
+					stamped, out, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+					Expect(stamped.Object).To(BeNil())
+					Expect(out.Image).To(Equal("some-revision"))
+					Expect(out.Source).To(Equal(&templates.Source{
+						URL:      "registry.example.com/team/petclinic-config@sha256:abc123",
+						Revision: "sha256:abc123",
+					}))
+
+					Expect(publisher.PublishCallCount()).To(Equal(1))
+					_, image, files, credentials := publisher.PublishArgsForCall(0)
+					Expect(image).To(Equal("registry.example.com/team/petclinic-config"))
+					Expect(credentials).To(Equal(oci.Credentials{}))
+					Expect(files).To(HaveKey("component-1.yaml"))
+
+					manifest := &unstructured.Unstructured{}
+					Expect(yaml.Unmarshal(files["component-1.yaml"], &manifest.Object)).To(Succeed())
+					Expect(manifest.GetName()).To(Equal("example-config-map"))
+					Expect(manifest.GetOwnerReferences()).To(BeEmpty())
+				})
+
+				It("publishes with the credentials of the secret in the workload's namespace", func() {
+					component.OCIArtifact.SecretRef = &corev1.LocalObjectReference{Name: "registry-credentials"}
+					fakeRepo.GetSecretDataReturns(map[string][]byte{
+						".dockerconfigjson": []byte(`{"auths": {"registry.example.com": {"username": "bot", "password": "s3cr3t"}}}`),
+					}, nil)
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).ToNot(HaveOccurred())
+
+					name, namespace := fakeRepo.GetSecretDataArgsForCall(0)
+					Expect(name).To(Equal("registry-credentials"))
+					Expect(namespace).To(Equal("some-namespace"))
+					_, _, _, credentials := publisher.PublishArgsForCall(0)
+					Expect(credentials).To(Equal(oci.Credentials{Username: "bot", Password: "s3cr3t"}))
+				})
+
+				It("returns PublishArtifactError when the artifact cannot be pushed", func() {
+					publisher.PublishReturns("", errors.New("push manifest: 403 Forbidden"))
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(MatchError("unable to publish object of component 'component-1' as an OCI artifact: push manifest: 403 Forbidden"))
+					Expect(reflect.TypeOf(err).String()).To(Equal("workload.PublishArtifactError"))
+				})
+			})
+
+			It("observes how long the component took to realize", func() {
+				workload.Namespace = "some-namespace"
+				realizer.ComponentRealizationDurationSeconds.Reset()
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(testutil.CollectAndCount(realizer.ComponentRealizationDurationSeconds)).To(Equal(1))
+				Expect(realizer.ComponentRealizationDurationSeconds.DeleteLabelValues("some-namespace", "supply-chain-name", "component-1", "Success")).To(BeTrue())
+			})
+
+			It("traces the steps of realizing the component", func() {
+				spans := &spanRecorder{}
+				provider := tracing.NewProvider(spans, logr.Discard())
+				tracing.SetProvider(provider)
+				defer tracing.SetProvider(nil)
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).ToNot(HaveOccurred())
+
+				provider.Flush(context.TODO())
+				var names []string
+				for _, span := range spans.spans {
+					names = append(names, span.Name)
+				}
+				Expect(names).To(Equal([]string{"Get template", "Stamp", "Apply", "Read outputs", "Realize component"}))
+				parent := spans.spans[4]
+				Expect(parent.Attributes).To(ContainElement(tracing.String("component", "component-1")))
+				for _, span := range spans.spans[:4] {
+					Expect(span.ParentID).To(Equal(parent.SpanID))
+				}
+			})
+
+			It("stamps the object with the owner references the supply chain asks for", func() {
+				r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{Interceptor: fakeInterceptor, Resolver: fakeResolver, OwnerReferences: v1alpha1.NoneOwnerReferencePolicy})
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).ToNot(HaveOccurred())
+
+				stampedObject, _, _ := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(0)
+				Expect(stampedObject.GetOwnerReferences()).To(BeEmpty())
+				Expect(stampedObject.GetLabels()).To(HaveKeyWithValue("carto.run/component-name", "component-1"))
+			})
+
+			Context("when the component asks for another namespace", func() {
+				BeforeEach(func() {
+					component.Namespace = "$(workload.metadata.namespace)$-builds"
+					r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{
+						Interceptor:             fakeInterceptor,
+						Resolver:                fakeResolver,
+						AllowedTargetNamespaces: []string{"some-namespace-builds"},
+						Impersonated:            true,
+						AccessMapper:            meta.NewDefaultRESTMapper(nil),
+					})
+				})
+
+				It("stamps the object into the namespace, without owner references, reviewing access to it first", func() {
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).ToNot(HaveOccurred())
+
+					stampedObject, _, opts := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(0)
+					Expect(stampedObject.GetNamespace()).To(Equal("some-namespace-builds"))
+					Expect(stampedObject.GetOwnerReferences()).To(BeEmpty())
+					Expect(stampedObject.GetLabels()).To(HaveKeyWithValue("carto.run/owner-uid", ""))
+					Expect(opts).To(HaveLen(1))
+				})
+
+				It("returns KindNotAllowedError without submitting the object when the supply chain does not allow the namespace", func() {
+					component.Namespace = "kube-system"
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(BeAssignableToTypeOf(realizer.KindNotAllowedError{}))
+					Expect(err.Error()).To(ContainSubstring("namespace 'kube-system' is not one of the allowedTargetNamespaces of the supply chain"))
+					Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+				})
+
+				It("returns KindNotAllowedError without submitting the object when it would be written as the controller", func() {
+					r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{
+						Interceptor:             fakeInterceptor,
+						Resolver:                fakeResolver,
+						AllowedTargetNamespaces: []string{"some-namespace-builds"},
+						AccessMapper:            meta.NewDefaultRESTMapper(nil),
+					})
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(BeAssignableToTypeOf(realizer.KindNotAllowedError{}))
+					Expect(err.Error()).To(ContainSubstring("objects are only stamped into namespace 'some-namespace-builds' as a service account, but the workload is realized as the controller"))
+					Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+				})
+			})
+
+			It("returns StampError when the namespace cannot be interpolated", func() {
+				component.Namespace = "$(params.missing)$"
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).To(BeAssignableToTypeOf(realizer.StampError{}))
+				Expect(err.Error()).To(ContainSubstring("unable to stamp object for component 'component-1': namespace:"))
+				Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+			})
+
+			It("returns KindNotAllowedError without submitting an object the stamp policies do not allow", func() {
+				fakeRepo.ListStampPoliciesReturns([]v1alpha1.ClusterStampPolicy{{
+					ObjectMeta: metav1.ObjectMeta{Name: "tenants"},
+					Spec: v1alpha1.StampPolicySpec{
+						AllowedKinds: []v1alpha1.AllowedKind{{Group: "apps", Kind: "Deployment"}},
+					},
+				}}, nil)
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).To(BeAssignableToTypeOf(realizer.KindNotAllowedError{}))
+				Expect(err.Error()).To(ContainSubstring("kind ConfigMap may not be stamped in namespace 'some-namespace': not allowed by ClusterStampPolicy 'tenants'"))
+				Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+			})
+
+			Context("when the supply chain is namespaced", func() {
+				BeforeEach(func() {
+					workload.Namespace = "some-namespace"
+					template, err := fakeRepo.GetClusterTemplate(component.TemplateRef)
+					Expect(err).ToNot(HaveOccurred())
+					fakeRepo.GetTemplateReturns(template, nil)
+					fakeRepo.IsNamespacedReturns(true, nil)
+					r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{Interceptor: fakeInterceptor, Resolver: fakeResolver, TemplateNamespace: "some-namespace"})
+				})
+
+				It("applies an object in the namespace of the supply chain", func() {
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(1))
+				})
+
+				It("returns KindNotAllowedError without submitting an object into another namespace", func() {
+					component.Namespace = "kube-system"
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(BeAssignableToTypeOf(realizer.KindNotAllowedError{}))
+					Expect(err.Error()).To(ContainSubstring("namespace 'kube-system' is not that of the supply chain, 'some-namespace'"))
+					Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+				})
+
+				It("returns KindNotAllowedError without submitting a cluster-scoped object", func() {
+					fakeRepo.IsNamespacedReturns(false, nil)
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(BeAssignableToTypeOf(realizer.KindNotAllowedError{}))
+					Expect(err.Error()).To(ContainSubstring("kind ConfigMap is cluster-scoped, but supply chains in namespace 'some-namespace' may only stamp objects into it"))
+					Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+					gvk := fakeRepo.IsNamespacedArgsForCall(0)
+					Expect(gvk.Kind).To(Equal("ConfigMap"))
+				})
+			})
+
+			It("applies the object with the server-side apply settings of the supply chain", func() {
+				force := false
+				r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{Interceptor: fakeInterceptor, Resolver: fakeResolver, ServerSideApply: &v1alpha1.ServerSideApplySettings{FieldManager: "team-a", ForceConflicts: &force}})
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, opts := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(0)
+				Expect(opts).To(HaveLen(2))
+			})
+
+			It("applies the object with the options it is given", func() {
+				r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{Interceptor: fakeInterceptor, Resolver: fakeResolver, ApplyOptions: []repository.ApplyOption{repository.WithWriter(&repositoryfakes.FakeClient{})}})
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, opts := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(0)
+				Expect(opts).To(HaveLen(1))
+			})
+
+			It("adopts an existing object when the component asks to", func() {
+				component.Adopt = true
+
+				stamped, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+				Expect(fakeRepo.AdoptObjectOnClusterCallCount()).To(Equal(1))
+				adopted, _ := fakeRepo.AdoptObjectOnClusterArgsForCall(0)
+				Expect(adopted).To(BeIdenticalTo(stamped.Object))
+			})
+
+			It("submits the object as mutated by the interceptor", func() {
+				fakeInterceptor.BeforeSubmitStub = func(_ context.Context, submission *interceptor.Submission) error {
+					Expect(submission.Owner).To(Equal(&workload))
+					submission.Object.SetAnnotations(map[string]string{"org.example/team": "core"})
+					return nil
+				}
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeInterceptor.BeforeSubmitCallCount()).To(Equal(1))
+				stampedObject, _, _ := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(0)
+				Expect(stampedObject.GetAnnotations()).To(Equal(map[string]string{"org.example/team": "core"}))
+			})
+
+			It("returns the outputs as mutated by the interceptor", func() {
+				fakeInterceptor.AfterSubmitStub = func(_ context.Context, submission *interceptor.Submission) error {
+					output, ok := submission.Outputs.(*templates.Output)
+					Expect(ok).To(BeTrue())
+					Expect(output.Image).To(Equal("some-revision"))
+					output.Image = "another-revision"
+					return nil
+				}
+
+				_, out, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeInterceptor.AfterSubmitCallCount()).To(Equal(1))
+				Expect(out.Image).To(Equal("another-revision"))
+			})
+
+			When("the interceptor fails before submission", func() {
+				BeforeEach(func() {
+					fakeInterceptor.BeforeSubmitReturns(errors.New("denied"))
+				})
+
+				It("returns InterceptError without submitting the object", func() {
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(HaveOccurred())
+
+					Expect(err.Error()).To(ContainSubstring("denied"))
+					Expect(reflect.TypeOf(err).String()).To(Equal("workload.InterceptError"))
+					Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+				})
+			})
+
+			When("the interceptor fails after submission", func() {
+				BeforeEach(func() {
+					fakeInterceptor.AfterSubmitReturns(errors.New("denied"))
+				})
+
+				It("returns InterceptError", func() {
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(HaveOccurred())
+
+					Expect(reflect.TypeOf(err).String()).To(Equal("workload.InterceptError"))
+				})
+			})
+
+			When("the component has hooks", func() {
+				var preHookResult string
+
+				nestedString := func(obj *unstructured.Unstructured, fields ...string) string {
+					value, _, _ := unstructured.NestedString(obj.Object, fields...)
+					return value
+				}
+
+				BeforeEach(func() {
+					workload.Name = "my-workload"
+					preHookResult = v1alpha1.SucceededRunResult
+					component.Hooks = &v1alpha1.ComponentHooks{
+						Pre: []v1alpha1.ComponentHook{{
+							Name:           "migrate",
+							RunTemplateRef: v1alpha1.TemplateReference{Name: "migration"},
+							Inputs: map[string]apiextensionsv1.JSON{
+								"revision": {Raw: []byte(`"$(source.revision)$"`)},
+							},
+						}},
+						Post: []v1alpha1.ComponentHook{{
+							Name:           "notify",
+							RunTemplateRef: v1alpha1.TemplateReference{Name: "notification"},
+							Inputs: map[string]apiextensionsv1.JSON{
+								"image": {Raw: []byte(`"$(output.image)$"`)},
+							},
+						}},
+					}
+
+					fakeRepo.EnsureObjectExistsOnClusterStub = func(obj *unstructured.Unstructured, _ bool, _ ...repository.ApplyOption) error {
+						if obj.GetKind() == "Pipeline" && obj.GetLabels()[realizer.HookNameLabel] == "migrate" && preHookResult != "" {
+							Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{
+								map[string]interface{}{"name": "run-1", "result": preHookResult},
+							}, "status", "runHistory")).To(Succeed())
+						}
+						return nil
+					}
+				})
+
+				It("stamps a pipeline for each hook around the component's object", func() {
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(3))
+
+					preHook, _, _ := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(0)
+					Expect(preHook.GetKind()).To(Equal("Pipeline"))
+					Expect(preHook.GetName()).To(Equal("my-workload-component-1-migrate"))
+					Expect(preHook.GetNamespace()).To(Equal("some-namespace"))
+					Expect(preHook.GetLabels()).To(HaveKeyWithValue(realizer.HookNameLabel, "migrate"))
+					Expect(preHook.GetLabels()).To(HaveKeyWithValue("carto.run/resource-name", "component-1"))
+					Expect(nestedString(preHook, "spec", "runTemplateRef", "name")).To(Equal("migration"))
+					Expect(nestedString(preHook, "spec", "inputs", "revision")).To(Equal("some-revision"))
+
+					mainObject, _, _ := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(1)
+					Expect(mainObject.GetKind()).To(Equal("ConfigMap"))
+
+					postHook, _, _ := fakeRepo.EnsureObjectExistsOnClusterArgsForCall(2)
+					Expect(postHook.GetName()).To(Equal("my-workload-component-1-notify"))
+					Expect(nestedString(postHook, "spec", "inputs", "image")).To(Equal("some-revision"))
+				})
+
+				When("the latest run of a pre hook has not finished", func() {
+					BeforeEach(func() {
+						preHookResult = ""
+					})
+
+					It("returns a PendingHookError without submitting the component's object", func() {
+						_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+						Expect(err).To(MatchError("component 'component-1' is waiting on pre hook 'migrate' to succeed"))
+						Expect(reflect.TypeOf(err).String()).To(Equal("workload.PendingHookError"))
+						Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(1))
+					})
+				})
+
+				When("the latest run of a pre hook failed", func() {
+					BeforeEach(func() {
+						preHookResult = v1alpha1.FailedRunResult
+					})
+
+					It("returns a HookError without submitting the component's object", func() {
+						_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+						Expect(err).To(MatchError("hook 'migrate' of component 'component-1' failed: latest run of pipeline 'my-workload-component-1-migrate' failed"))
+						Expect(reflect.TypeOf(err).String()).To(Equal("workload.HookError"))
+						Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(1))
+					})
+				})
+
+				When("a hook pipeline cannot be applied", func() {
+					BeforeEach(func() {
+						fakeRepo.EnsureObjectExistsOnClusterStub = nil
+						fakeRepo.EnsureObjectExistsOnClusterReturns(errors.New("forbidden"))
+					})
+
+					It("returns a HookError", func() {
+						_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+						Expect(err).To(MatchError(ContainSubstring("hook 'migrate' of component 'component-1' failed: apply pipeline 'my-workload-component-1-migrate': forbidden")))
+						Expect(reflect.TypeOf(err).String()).To(Equal("workload.HookError"))
+					})
+				})
+			})
 		})
 
 		When("unable to get the template ref from repo", func() {
@@ -158,7 +670,7 @@ var _ = Describe("Component", func() {
 			})
 
 			It("returns GetClusterTemplateError", func() {
-				_, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
 				Expect(err).To(HaveOccurred())
 
 				Expect(err.Error()).To(ContainSubstring("unable to get template 'image-template-1'"))
@@ -167,6 +679,25 @@ var _ = Describe("Component", func() {
 			})
 		})
 
+		When("the supply chain is namespaced", func() {
+			BeforeEach(func() {
+				r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{Interceptor: fakeInterceptor, Resolver: fakeResolver, TemplateNamespace: "team-ns"})
+				fakeRepo.GetTemplateReturns(nil, errors.New("bad template"))
+			})
+
+			It("gets the template from the namespace of the supply chain", func() {
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).To(HaveOccurred())
+				Expect(reflect.TypeOf(err).String()).To(Equal("workload.GetClusterTemplateError"))
+
+				Expect(fakeRepo.GetClusterTemplateCallCount()).To(Equal(0))
+				Expect(fakeRepo.GetTemplateCallCount()).To(Equal(1))
+				ref, namespace := fakeRepo.GetTemplateArgsForCall(0)
+				Expect(ref).To(Equal(component.TemplateRef))
+				Expect(namespace).To(Equal("team-ns"))
+			})
+		})
+
 		When("unable to Stamp a new template", func() {
 			BeforeEach(func() {
 				templateAPI := &v1alpha1.ClusterImageTemplate{
@@ -190,7 +721,7 @@ var _ = Describe("Component", func() {
 			})
 
 			It("returns StampError", func() {
-				_, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("unable to stamp object for component 'component-1'"))
 				Expect(reflect.TypeOf(err).String()).To(Equal("workload.StampError"))
@@ -240,7 +771,7 @@ var _ = Describe("Component", func() {
 			})
 
 			It("returns RetrieveOutputError", func() {
-				_, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("find results: does-not-exist is not found"))
 				Expect(reflect.TypeOf(err).String()).To(Equal("workload.RetrieveOutputError"))
@@ -301,12 +832,235 @@ var _ = Describe("Component", func() {
 				fakeRepo.EnsureObjectExistsOnClusterReturns(errors.New("bad object"))
 			})
 			It("returns ApplyStampedObjectError", func() {
-				_, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
 				Expect(err).To(HaveOccurred())
 
 				Expect(err.Error()).To(ContainSubstring("bad object"))
 				Expect(reflect.TypeOf(err).String()).To(Equal("workload.ApplyStampedObjectError"))
 			})
 		})
+
+		When("passed a source template in artifact mode", func() {
+			BeforeEach(func() {
+				workload.Name = "petclinic"
+				component.Params = []v1alpha1.SupplyChainParam{
+					{Name: "group", Value: apiextensionsv1.JSON{Raw: []byte(`"org.springframework"`)}},
+				}
+
+				templateAPI := &v1alpha1.ClusterSourceTemplate{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "ClusterSourceTemplate",
+						APIVersion: "carto.run/v1alpha1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "maven-source",
+					},
+					Spec: v1alpha1.SourceTemplateSpec{
+						TemplateSpec: v1alpha1.TemplateSpec{
+							Params: v1alpha1.DefaultParams{
+								{Name: "group", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"org.example"`)}},
+							},
+						},
+						Artifact: &v1alpha1.ArtifactSource{
+							Type:         "maven",
+							Registry:     "https://repo1.maven.org/maven2",
+							Name:         "$(params.group)$:spring-$(workload.metadata.name)$",
+							PollInterval: &metav1.Duration{Duration: 5 * time.Minute},
+						},
+					},
+				}
+
+				template := templates.NewClusterSourceTemplateModel(templateAPI, eval.EvaluatorBuilder())
+				fakeRepo.GetClusterTemplateReturns(template, nil)
+				fakeResolver.ResolveReturns(&artifact.Artifact{
+					URL:     "https://repo1.maven.org/maven2/org/springframework/spring-petclinic/2.5.0/spring-petclinic-2.5.0.jar",
+					Version: "2.5.0",
+				}, nil)
+			})
+
+			It("resolves the interpolated coordinate", func() {
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeResolver.ResolveCallCount()).To(Equal(1))
+				_, coordinate, pollInterval := fakeResolver.ResolveArgsForCall(0)
+				Expect(coordinate).To(Equal(artifact.Coordinate{
+					Type:     "maven",
+					Registry: "https://repo1.maven.org/maven2",
+					Name:     "org.springframework:spring-petclinic",
+				}))
+				Expect(pollInterval).To(Equal(5 * time.Minute))
+			})
+
+			It("outputs the artifact url and version as the source", func() {
+				_, out, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(out.Source).To(Equal(&templates.Source{
+					URL:      "https://repo1.maven.org/maven2/org/springframework/spring-petclinic/2.5.0/spring-petclinic-2.5.0.jar",
+					Revision: "2.5.0",
+				}))
+			})
+
+			It("does not stamp an object", func() {
+				_, _, _ = r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(fakeRepo.EnsureObjectExistsOnClusterCallCount()).To(Equal(0))
+			})
+
+			Context("when the workload sets a param", func() {
+				BeforeEach(func() {
+					workload.Spec.Params = []v1alpha1.WorkloadParam{
+						{Name: "group", Value: apiextensionsv1.JSON{Raw: []byte(`"io.acme"`)}},
+					}
+				})
+
+				It("interpolates the workload's value", func() {
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, coordinate, _ := fakeResolver.ResolveArgsForCall(0)
+					Expect(coordinate.Name).To(Equal("io.acme:spring-petclinic"))
+				})
+
+				It("returns ParamsError when the supply chain marks the param immutable", func() {
+					component.Params[0].Immutable = true
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(MatchError("unable to merge params for component 'component-1': param 'group' is immutable in the supply chain and may not be set by the workload"))
+					Expect(reflect.TypeOf(err).String()).To(Equal("workload.ParamsError"))
+					Expect(fakeResolver.ResolveCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the workload's param comes from a secret", func() {
+				BeforeEach(func() {
+					workload.Spec.Params = []v1alpha1.WorkloadParam{{
+						Name: "group",
+						ValueFrom: &v1alpha1.WorkloadParamSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "maven"},
+								Key:                  "group",
+							},
+						},
+					}}
+				})
+
+				It("interpolates the value read from the secret", func() {
+					r = realizer.NewComponentRealizer(&workload, &fakeRepo, realizer.ComponentRealizerOptions{SecretParams: map[string]apiextensionsv1.JSON{"group": {Raw: []byte(`"com.acme"`)}}, Interceptor: fakeInterceptor, Resolver: fakeResolver})
+
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, coordinate, _ := fakeResolver.ResolveArgsForCall(0)
+					Expect(coordinate.Name).To(Equal("com.acme:spring-petclinic"))
+				})
+
+				It("falls back to the default when the value was not read", func() {
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, coordinate, _ := fakeResolver.ResolveArgsForCall(0)
+					Expect(coordinate.Name).To(Equal("org.springframework:spring-petclinic"))
+				})
+			})
+
+			Context("when the artifact cannot be resolved", func() {
+				BeforeEach(func() {
+					fakeResolver.ResolveReturns(nil, errors.New("registry responded 404"))
+				})
+
+				It("returns ResolveArtifactError", func() {
+					_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+					Expect(err).To(MatchError("unable to resolve artifact for component 'component-1': registry responded 404"))
+					Expect(reflect.TypeOf(err).String()).To(Equal("workload.ResolveArtifactError"))
+				})
+			})
+		})
+
+		When("the template ref lists options", func() {
+			BeforeEach(func() {
+				dockerfile := v1alpha1.FieldSelectorRequirement{Key: `spec.params[?(@.name=="dockerfile")].value`}
+				component.TemplateRef = v1alpha1.ClusterTemplateReference{
+					Kind: "ClusterImageTemplate",
+					Options: []v1alpha1.TemplateOption{
+						{
+							Name: "kpack-template",
+							Selector: v1alpha1.OptionSelector{
+								MatchFields: []v1alpha1.FieldSelectorRequirement{
+									{Key: dockerfile.Key, Operator: v1alpha1.FieldSelectorOpDoesNotExist},
+								},
+							},
+						},
+						{
+							Name: "kaniko-template",
+							Selector: v1alpha1.OptionSelector{
+								MatchFields: []v1alpha1.FieldSelectorRequirement{
+									{Key: dockerfile.Key, Operator: v1alpha1.FieldSelectorOpExists},
+								},
+							},
+						},
+					},
+				}
+				fakeRepo.GetClusterTemplateReturns(nil, errors.New("bad template"))
+			})
+
+			It("gets the template of the option matching the workload", func() {
+				_, _, _ = r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(fakeRepo.GetClusterTemplateArgsForCall(0)).To(Equal(v1alpha1.ClusterTemplateReference{
+					Kind: "ClusterImageTemplate",
+					Name: "kpack-template",
+				}))
+
+				workload.Spec.Params = []v1alpha1.WorkloadParam{
+					{Name: "dockerfile", Value: apiextensionsv1.JSON{Raw: []byte(`"./Dockerfile"`)}},
+				}
+				_, _, _ = r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(fakeRepo.GetClusterTemplateArgsForCall(1)).To(Equal(v1alpha1.ClusterTemplateReference{
+					Kind: "ClusterImageTemplate",
+					Name: "kaniko-template",
+				}))
+			})
+
+			It("matches options on the workload's labels", func() {
+				workload.Labels = map[string]string{"apps.tanzu.vmware.com/language": "java"}
+				component.TemplateRef.Options[0].Selector.MatchLabels = map[string]string{"apps.tanzu.vmware.com/language": "go"}
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).To(MatchError("no template option of component 'component-1' matches the workload"))
+				Expect(reflect.TypeOf(err).String()).To(Equal("workload.TemplateOptionError"))
+				Expect(fakeRepo.GetClusterTemplateCallCount()).To(Equal(0))
+			})
+
+			It("matches options on the values of the workload's fields", func() {
+				workload.Spec.Params = []v1alpha1.WorkloadParam{
+					{Name: "dockerfile", Value: apiextensionsv1.JSON{Raw: []byte(`"./Dockerfile"`)}},
+				}
+				component.TemplateRef.Options[1].Selector.MatchFields[0].Operator = v1alpha1.FieldSelectorOpIn
+				component.TemplateRef.Options[1].Selector.MatchFields[0].Values = []string{"Dockerfile.prod"}
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).To(MatchError("no template option of component 'component-1' matches the workload"))
+			})
+
+			It("returns an ambiguous TemplateOptionError when several options match", func() {
+				component.TemplateRef.Options[1].Selector = v1alpha1.OptionSelector{}
+
+				_, _, err := r.Do(context.TODO(), &component, supplyChainName, outputs)
+				Expect(err).To(MatchError("template options 'kpack-template', 'kaniko-template' of component 'component-1' all match the workload"))
+
+				var optionErr realizer.TemplateOptionError
+				Expect(errors.As(err, &optionErr)).To(BeTrue())
+				Expect(optionErr.Ambiguous()).To(BeTrue())
+			})
+		})
 	})
 })
+
+type spanRecorder struct {
+	spans []tracing.SpanData
+}
+
+func (r *spanRecorder) Export(_ context.Context, spans []tracing.SpanData) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
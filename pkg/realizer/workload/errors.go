@@ -16,12 +16,30 @@ package workload
 
 import (
 	"fmt"
+	"strings"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 )
 
+// TemplateError is implemented by the errors a component's template
+// caused, which name whom to contact about the template.
+type TemplateError interface {
+	error
+	TemplateContact() string
+}
+
+// withContact appends how to reach the maintainers of the template that
+// caused an error to its message.
+func withContact(message string, metadata *v1alpha1.TemplateMetadata) string {
+	if contact := metadata.Contact(); contact != "" {
+		return fmt.Sprintf("%s (%s)", message, contact)
+	}
+	return message
+}
+
 type GetClusterTemplateError struct {
 	Err         error
 	TemplateRef v1alpha1.ClusterTemplateReference
@@ -32,21 +50,117 @@ func (e GetClusterTemplateError) Error() string {
 }
 
 type ApplyStampedObjectError struct {
-	Err           error
-	StampedObject *unstructured.Unstructured
+	Err              error
+	StampedObject    *unstructured.Unstructured
+	TemplateMetadata *v1alpha1.TemplateMetadata
 }
 
 func (e ApplyStampedObjectError) Error() string {
-	return fmt.Errorf("unable to apply object '%s/%s': %w", e.StampedObject.GetNamespace(), e.StampedObject.GetName(), e.Err).Error()
+	return withContact(fmt.Errorf("unable to apply object '%s/%s': %w", e.StampedObject.GetNamespace(), e.StampedObject.GetName(), e.Err).Error(), e.TemplateMetadata)
+}
+
+func (e ApplyStampedObjectError) TemplateContact() string {
+	return e.TemplateMetadata.Contact()
 }
 
 type StampError struct {
+	Err              error
+	Component        *v1alpha1.SupplyChainComponent
+	TemplateMetadata *v1alpha1.TemplateMetadata
+}
+
+func (e StampError) Error() string {
+	return withContact(fmt.Errorf("unable to stamp object for component '%s': %w", e.Component.Name, e.Err).Error(), e.TemplateMetadata)
+}
+
+func (e StampError) TemplateContact() string {
+	return e.TemplateMetadata.Contact()
+}
+
+// KindNotAllowedError reports an object of a kind the ClusterStampPolicies
+// that apply to its namespace do not allow to be stamped, or that a
+// namespaced SupplyChain would stamp outside its namespace.
+type KindNotAllowedError struct {
+	Err              error
+	Component        *v1alpha1.SupplyChainComponent
+	TemplateMetadata *v1alpha1.TemplateMetadata
+}
+
+func (e KindNotAllowedError) Error() string {
+	return withContact(fmt.Errorf("unable to stamp object for component '%s': %w", e.Component.Name, e.Err).Error(), e.TemplateMetadata)
+}
+
+func (e KindNotAllowedError) TemplateContact() string {
+	return e.TemplateMetadata.Contact()
+}
+
+type ParamsError struct {
+	Err              error
+	Component        *v1alpha1.SupplyChainComponent
+	TemplateMetadata *v1alpha1.TemplateMetadata
+}
+
+func (e ParamsError) Error() string {
+	return withContact(fmt.Errorf("unable to merge params for component '%s': %w", e.Component.Name, e.Err).Error(), e.TemplateMetadata)
+}
+
+func (e ParamsError) TemplateContact() string {
+	return e.TemplateMetadata.Contact()
+}
+
+type InterceptError struct {
 	Err       error
 	Component *v1alpha1.SupplyChainComponent
 }
 
-func (e StampError) Error() string {
-	return fmt.Errorf("unable to stamp object for component '%s': %w", e.Component.Name, e.Err).Error()
+func (e InterceptError) Error() string {
+	return fmt.Errorf("interceptor failed for component '%s': %w", e.Component.Name, e.Err).Error()
+}
+
+type ResolveArtifactError struct {
+	Err              error
+	Component        *v1alpha1.SupplyChainComponent
+	TemplateMetadata *v1alpha1.TemplateMetadata
+}
+
+func (e ResolveArtifactError) Error() string {
+	return withContact(fmt.Errorf("unable to resolve artifact for component '%s': %w", e.Component.Name, e.Err).Error(), e.TemplateMetadata)
+}
+
+func (e ResolveArtifactError) TemplateContact() string {
+	return e.TemplateMetadata.Contact()
+}
+
+// GitWriteError reports a stamped object that could not be committed to the
+// Git repository of its component.
+type GitWriteError struct {
+	Err              error
+	Component        *v1alpha1.SupplyChainComponent
+	TemplateMetadata *v1alpha1.TemplateMetadata
+}
+
+func (e GitWriteError) Error() string {
+	return withContact(fmt.Errorf("unable to write object of component '%s' to git: %w", e.Component.Name, e.Err).Error(), e.TemplateMetadata)
+}
+
+func (e GitWriteError) TemplateContact() string {
+	return e.TemplateMetadata.Contact()
+}
+
+// PublishArtifactError reports a stamped object that could not be pushed as
+// the OCI artifact of its component.
+type PublishArtifactError struct {
+	Err              error
+	Component        *v1alpha1.SupplyChainComponent
+	TemplateMetadata *v1alpha1.TemplateMetadata
+}
+
+func (e PublishArtifactError) Error() string {
+	return withContact(fmt.Errorf("unable to publish object of component '%s' as an OCI artifact: %w", e.Component.Name, e.Err).Error(), e.TemplateMetadata)
+}
+
+func (e PublishArtifactError) TemplateContact() string {
+	return e.TemplateMetadata.Contact()
 }
 
 func NewRetrieveOutputError(component *v1alpha1.SupplyChainComponent, err error) RetrieveOutputError {
@@ -80,3 +194,60 @@ func (e RetrieveOutputError) JsonPathExpression() string {
 	}
 	return "<no jsonpath context>"
 }
+
+type TemplateOptionError struct {
+	Err       error
+	Component *v1alpha1.SupplyChainComponent
+	Matched   []string
+}
+
+func (e TemplateOptionError) Error() string {
+	if e.Err != nil {
+		return fmt.Errorf("unable to select a template option for component '%s': %w", e.Component.Name, e.Err).Error()
+	}
+	if len(e.Matched) == 0 {
+		return fmt.Sprintf("no template option of component '%s' matches the workload", e.Component.Name)
+	}
+	return fmt.Sprintf("template options '%s' of component '%s' all match the workload", strings.Join(e.Matched, "', '"), e.Component.Name)
+}
+
+// Ambiguous reports whether more than one option matched the workload.
+func (e TemplateOptionError) Ambiguous() bool {
+	return len(e.Matched) > 1
+}
+
+type HookError struct {
+	Err       error
+	Component *v1alpha1.SupplyChainComponent
+	Hook      string
+}
+
+func (e HookError) Error() string {
+	return fmt.Errorf("hook '%s' of component '%s' failed: %w", e.Hook, e.Component.Name, e.Err).Error()
+}
+
+// PendingHookError is returned while the latest run of a pre hook has not
+// yet succeeded, which holds back the component's object.
+type PendingHookError struct {
+	Component *v1alpha1.SupplyChainComponent
+	Hook      string
+}
+
+func (e PendingHookError) Error() string {
+	return fmt.Sprintf("component '%s' is waiting on pre hook '%s' to succeed", e.Component.Name, e.Hook)
+}
+
+// ReadinessGateError is returned while the object of a component with a
+// readiness gate is not healthy, which holds back its outputs.
+type ReadinessGateError struct {
+	Component *v1alpha1.SupplyChainComponent
+	Health    metav1.Condition
+}
+
+func (e ReadinessGateError) Error() string {
+	message := fmt.Sprintf("component '%s' is waiting on its object to become healthy", e.Component.Name)
+	if e.Health.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, e.Health.Message)
+	}
+	return message
+}
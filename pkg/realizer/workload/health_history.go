@@ -0,0 +1,137 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+const (
+	// flapWindow is how far back the health transitions of a component
+	// are counted to detect flapping.
+	flapWindow = 10 * time.Minute
+	// flapThreshold is how many times the health of a component must have
+	// changed within the flap window for it to be flapping.
+	flapThreshold = 4
+	// maxHealthTransitions bounds how many transitions are kept for a
+	// component.
+	maxHealthTransitions = 10
+)
+
+// healthTransitions adds the health of the stamped object of a component to
+// the transitions recorded for it when it became healthy or unhealthy.
+// Unknown health is not a transition, so that an object that is briefly
+// reconciling does not count as flapping. Transitions older than the flap
+// window are dropped, but for the last of them, which tells since when the
+// object has had its health.
+func healthTransitions(previous []v1alpha1.HealthTransition, health metav1.Condition, now time.Time) []v1alpha1.HealthTransition {
+	transitions := append([]v1alpha1.HealthTransition{}, previous...)
+	if health.Status == metav1.ConditionTrue || health.Status == metav1.ConditionFalse {
+		if len(transitions) == 0 || transitions[len(transitions)-1].Status != health.Status {
+			transitions = append(transitions, v1alpha1.HealthTransition{Status: health.Status, Time: metav1.NewTime(now)})
+		}
+	}
+
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	first := 0
+	for first < len(transitions)-1 && transitions[first+1].Time.Time.Before(now.Add(-flapWindow)) {
+		first++
+	}
+	if len(transitions)-first > maxHealthTransitions {
+		first = len(transitions) - maxHealthTransitions
+	}
+	return transitions[first:]
+}
+
+// healthChanges counts the transitions within the flap window that changed
+// the health of the object, leaving out the first health it was known to
+// have.
+func healthChanges(transitions []v1alpha1.HealthTransition, now time.Time) int {
+	var changes int
+	for i, transition := range transitions {
+		if i > 0 && !transition.Time.Time.Before(now.Add(-flapWindow)) {
+			changes++
+		}
+	}
+	return changes
+}
+
+// flappingCondition reports that the object of a component is flapping, or
+// is nil when its health has changed less often than the flap threshold
+// within the flap window.
+func flappingCondition(transitions []v1alpha1.HealthTransition, now time.Time) *metav1.Condition {
+	changes := healthChanges(transitions, now)
+	if changes < flapThreshold {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    v1alpha1.ComponentHealthFlapping,
+		Status:  metav1.ConditionTrue,
+		Reason:  v1alpha1.FrequentChangesHealthFlappingReason,
+		Message: fmt.Sprintf("health changed %d times in the last %s", changes, flapWindow),
+	}
+}
+
+// stabilizedHealth deems a healthy object of unknown health until it has
+// been healthy for the minHealthySeconds of its component.
+func stabilizedHealth(health metav1.Condition, transitions []v1alpha1.HealthTransition, minHealthySeconds int64, now time.Time) metav1.Condition {
+	if minHealthySeconds <= 0 || health.Status != metav1.ConditionTrue || len(transitions) == 0 {
+		return health
+	}
+
+	required := time.Duration(minHealthySeconds) * time.Second
+	healthyFor := now.Sub(transitions[len(transitions)-1].Time.Time)
+	if healthyFor >= required {
+		return health
+	}
+	return metav1.Condition{
+		Type:    v1alpha1.ComponentHealthy,
+		Status:  metav1.ConditionUnknown,
+		Reason:  v1alpha1.StabilizingHealthyReason,
+		Message: fmt.Sprintf("healthy for %s of the %s required", healthyFor.Truncate(time.Second), required),
+	}
+}
+
+// componentHealth judges the health of the object stamped for a component,
+// as its template's health rule and its minHealthySeconds tell, and reports
+// whether it is flapping, given the transitions previously recorded for it.
+// Only the health of a stamped object is recorded.
+func componentHealth(component *v1alpha1.SupplyChainComponent, stamped *StampedObject, outputsAvailable bool, previous *v1alpha1.ComponentStatus, now time.Time) (metav1.Condition, []metav1.Condition, []v1alpha1.HealthTransition) {
+	var prior []v1alpha1.HealthTransition
+	if previous != nil {
+		prior = previous.HealthTransitions
+	}
+
+	health := healthCondition(stamped, outputsAvailable)
+	transitions := prior
+	if stamped != nil && stamped.Object != nil {
+		transitions = healthTransitions(prior, health, now)
+	}
+	health = stabilizedHealth(health, transitions, component.MinHealthySeconds, now)
+
+	conditions := []metav1.Condition{health}
+	if flapping := flappingCondition(transitions, now); flapping != nil {
+		conditions = append(conditions, *flapping)
+	}
+	return health, conditions, transitions
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/identity"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+// HookNameLabel holds the name of the hook a Pipeline was stamped for,
+// telling it apart from the component's own object.
+const HookNameLabel = "carto.run/hook-name"
+
+// runPreHooks stamps the pipeline of every pre hook, and holds the component
+// back until the latest run of each has succeeded.
+func (r *componentRealizer) runPreHooks(ctx context.Context, component *v1alpha1.SupplyChainComponent, templatingContext map[string]interface{}, labels map[string]string) error {
+	for i := range component.Hooks.Pre {
+		hook := &component.Hooks.Pre[i]
+		pipeline, err := r.stampHook(ctx, component, hook, templatingContext, labels)
+		if err != nil {
+			return err
+		}
+
+		switch latestRunResult(pipeline) {
+		case v1alpha1.SucceededRunResult:
+			continue
+		case v1alpha1.FailedRunResult:
+			return HookError{
+				Err:       fmt.Errorf("latest run of pipeline '%s' failed", pipeline.GetName()),
+				Component: component,
+				Hook:      hook.Name,
+			}
+		default:
+			return PendingHookError{Component: component, Hook: hook.Name}
+		}
+	}
+	return nil
+}
+
+// runPostHooks stamps the pipeline of every post hook, with the outputs of
+// the component. The component does not wait on their runs.
+func (r *componentRealizer) runPostHooks(ctx context.Context, component *v1alpha1.SupplyChainComponent, templatingContext map[string]interface{}, labels map[string]string, output *templates.Output) error {
+	postContext := make(map[string]interface{}, len(templatingContext)+1)
+	for key, value := range templatingContext {
+		postContext[key] = value
+	}
+	postContext["output"] = map[string]interface{}{
+		"source": output.Source,
+		"image":  output.Image,
+		"config": output.Config,
+	}
+
+	for i := range component.Hooks.Post {
+		if _, err := r.stampHook(ctx, component, &component.Hooks.Post[i], postContext, labels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stampHook stamps and submits the Pipeline of a hook, returning it as it
+// is on the cluster.
+func (r *componentRealizer) stampHook(ctx context.Context, component *v1alpha1.SupplyChainComponent, hook *v1alpha1.ComponentHook, templatingContext map[string]interface{}, labels map[string]string) (*unstructured.Unstructured, error) {
+	raw, err := json.Marshal(map[string]interface{}{
+		"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+		"kind":       "Pipeline",
+		"metadata": map[string]interface{}{
+			"name": fmt.Sprintf("%s-%s-%s", r.workload.Name, component.Name, hook.Name),
+		},
+		"spec": map[string]interface{}{
+			"runTemplateRef": hook.RunTemplateRef,
+			"inputs":         hook.Inputs,
+		},
+	})
+	if err != nil {
+		return nil, HookError{Err: fmt.Errorf("marshal pipeline: %w", err), Component: component, Hook: hook.Name}
+	}
+	hookTemplate := v1alpha1.TemplateSpec{Template: &runtime.RawExtension{Raw: raw}}
+
+	hookLabels := map[string]string{HookNameLabel: hook.Name}
+	for key, value := range labels {
+		hookLabels[key] = value
+	}
+
+	stampContext := templates.StamperBuilder(r.workload, templatingContext, hookLabels)
+	pipeline, err := stampContext.Stamp(ctx, hookTemplate)
+	if err != nil {
+		return nil, HookError{Err: fmt.Errorf("stamp pipeline: %w", err), Component: component, Hook: hook.Name}
+	}
+
+	templateHash, err := identity.TemplateHash(hookTemplate)
+	if err != nil {
+		return nil, HookError{Err: err, Component: component, Hook: hook.Name}
+	}
+	identity.Apply(pipeline, identity.Identity{
+		OwnerUID:       r.workload.UID,
+		ResourceName:   component.Name,
+		TemplateHash:   templateHash,
+		OwnerKind:      "Workload",
+		OwnerName:      r.workload.Name,
+		OwnerNamespace: r.workload.Namespace,
+		SupplyChain:    labels["carto.run/cluster-supply-chain-name"],
+	})
+
+	if err := r.interceptor.BeforeSubmit(ctx, &interceptor.Submission{Owner: r.workload, Object: pipeline}); err != nil {
+		return nil, InterceptError{Err: err, Component: component}
+	}
+
+	if err := r.repo.EnsureObjectExistsOnCluster(pipeline, true); err != nil {
+		return nil, HookError{Err: fmt.Errorf("apply pipeline '%s': %w", pipeline.GetName(), err), Component: component, Hook: hook.Name}
+	}
+
+	return pipeline, nil
+}
+
+// latestRunResult is the result of the newest run of a pipeline, empty
+// before it has stamped any.
+func latestRunResult(pipeline *unstructured.Unstructured) string {
+	history, _, _ := unstructured.NestedSlice(pipeline.Object, "status", "runHistory")
+	if len(history) == 0 {
+		return ""
+	}
+	latest, ok := history[0].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	result, _, _ := unstructured.NestedString(latest, "result")
+	return result
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The outcomes of realizing a component. A component waiting on a pre hook
+// or a readiness gate is pending rather than failed.
+const (
+	SuccessOutcome = "Success"
+	PendingOutcome = "Pending"
+	FailureOutcome = "Failure"
+)
+
+// ComponentRealizationDurationSeconds observes how long each component of
+// a supply chain took to realize for a workload, by its outcome, so that
+// the slow templates and stamped kinds of a supply chain can be found.
+var ComponentRealizationDurationSeconds = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "cartographer_component_realization_duration_seconds",
+		Help:    "Time taken to realize a component of a supply chain for a workload by outcome",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"namespace", "supply_chain", "component", "outcome"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(ComponentRealizationDurationSeconds)
+}
+
+// observe records the realization of a component that started at start and
+// failed with *err, if not nil. It is meant to be deferred, with err
+// pointing to the named error result of the realization.
+func observe(namespace, supplyChain, component string, start time.Time, err *error) {
+	ComponentRealizationDurationSeconds.
+		WithLabelValues(namespace, supplyChain, component, outcome(*err)).
+		Observe(time.Since(start).Seconds())
+}
+
+func outcome(err error) string {
+	switch err.(type) {
+	case nil:
+		return SuccessOutcome
+	case PendingHookError, ReadinessGateError:
+		return PendingOutcome
+	default:
+		return FailureOutcome
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
+)
+
+// SelectTemplateRef resolves a reference listing options to the template of
+// the one option whose selector matches the workload.
+func SelectTemplateRef(workload *v1alpha1.Workload, component *v1alpha1.SupplyChainComponent) (v1alpha1.ClusterTemplateReference, error) {
+	ref := component.TemplateRef
+	if len(ref.Options) == 0 {
+		return ref, nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(workload)
+	if err != nil {
+		return ref, TemplateOptionError{
+			Err:       fmt.Errorf("convert workload: %w", err),
+			Component: component,
+		}
+	}
+
+	var matched []string
+	for _, option := range ref.Options {
+		matches, err := optionMatches(option.Selector, workload.Labels, content)
+		if err != nil {
+			return ref, TemplateOptionError{
+				Err:       fmt.Errorf("evaluate selector of option '%s': %w", option.Name, err),
+				Component: component,
+			}
+		}
+		if matches {
+			matched = append(matched, option.Name)
+		}
+	}
+
+	if len(matched) != 1 {
+		return ref, TemplateOptionError{
+			Component: component,
+			Matched:   matched,
+		}
+	}
+
+	return v1alpha1.ClusterTemplateReference{Kind: ref.Kind, Name: matched[0]}, nil
+}
+
+func optionMatches(optionSelector v1alpha1.OptionSelector, workloadLabels map[string]string, content map[string]interface{}) (bool, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(&optionSelector.LabelSelector)
+	if err != nil {
+		return false, fmt.Errorf("label selector: %w", err)
+	}
+	if !labelSelector.Matches(labels.Set(workloadLabels)) {
+		return false, nil
+	}
+
+	return selector.MatchFields(optionSelector.MatchFields, content)
+}
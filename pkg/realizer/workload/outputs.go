@@ -29,6 +29,14 @@ func (o Outputs) AddOutput(name string, output *templates.Output) {
 	o[name] = output
 }
 
+func (o Outputs) copy() Outputs {
+	result := make(Outputs, len(o))
+	for name, output := range o {
+		result[name] = output
+	}
+	return result
+}
+
 func (o Outputs) getComponentSource(componentName string) *templates.Source {
 	output := o[componentName]
 	if output == nil {
@@ -59,36 +67,47 @@ func (o Outputs) GenerateInputs(component *v1alpha1.SupplyChainComponent) *templ
 		Sources: map[string]templates.SourceInput{},
 		Images:  map[string]templates.ImageInput{},
 		Configs: map[string]templates.ConfigInput{},
+		Collections: templates.InputCollections{
+			Sources: []templates.SourceInput{},
+			Images:  []templates.ImageInput{},
+			Configs: []templates.ConfigInput{},
+		},
 	}
 
 	for _, referenceSource := range component.Sources {
 		source := o.getComponentSource(referenceSource.Component)
 		if source != nil {
-			inputs.Sources[referenceSource.Name] = templates.SourceInput{
+			input := templates.SourceInput{
 				URL:      source.URL,
 				Revision: source.Revision,
 				Name:     referenceSource.Name,
 			}
+			inputs.Sources[referenceSource.Name] = input
+			inputs.Collections.Sources = append(inputs.Collections.Sources, input)
 		}
 	}
 
 	for _, referenceImage := range component.Images {
 		image := o.getComponentImage(referenceImage.Component)
 		if image != nil {
-			inputs.Images[referenceImage.Name] = templates.ImageInput{
+			input := templates.ImageInput{
 				Image: image,
 				Name:  referenceImage.Name,
 			}
+			inputs.Images[referenceImage.Name] = input
+			inputs.Collections.Images = append(inputs.Collections.Images, input)
 		}
 	}
 
 	for _, referenceConfig := range component.Configs {
 		config := o.getComponentConfig(referenceConfig.Component)
 		if config != nil {
-			inputs.Configs[referenceConfig.Name] = templates.ConfigInput{
+			input := templates.ConfigInput{
 				Config: config,
 				Name:   referenceConfig.Name,
 			}
+			inputs.Configs[referenceConfig.Name] = input
+			inputs.Collections.Configs = append(inputs.Collections.Configs, input)
 		}
 	}
 
@@ -143,6 +143,30 @@ var _ = Describe("Outputs", func() {
 				})
 			})
 
+			Context("And several configs are consumed", func() {
+				It("Adds them to the collection in the order they are listed", func() {
+					outs.AddOutput("other-config-output", &templates.Output{Config: "config67890"})
+					component := &v1alpha1.SupplyChainComponent{
+						Configs: []v1alpha1.ComponentReference{
+							{
+								Name:      "other-config-ref",
+								Component: "other-config-output",
+							},
+							{
+								Name:      "config-ref",
+								Component: "config-output",
+							},
+						},
+					}
+					inputs := outs.GenerateInputs(component)
+					Expect(inputs.Configs).To(HaveLen(2))
+					Expect(inputs.Collections.Configs).To(Equal([]templates.ConfigInput{
+						{Name: "other-config-ref", Config: "config67890"},
+						{Name: "config-ref", Config: "config12345"},
+					}))
+				})
+			})
+
 			Context("And the configs do not have a match with the outputs", func() {
 				It("Does not add configs to inputs", func() {
 					component := &v1alpha1.SupplyChainComponent{
@@ -18,32 +18,282 @@ package workload
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/realizer/healthcheck"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
 
 //counterfeiter:generate . Realizer
 type Realizer interface {
-	Realize(ctx context.Context, componentRealizer ComponentRealizer, supplyChain *v1alpha1.ClusterSupplyChain) error
+	Realize(ctx context.Context, componentRealizer ComponentRealizer, supplyChain *v1alpha1.ClusterSupplyChain, previous []v1alpha1.ComponentStatus) ([]v1alpha1.ComponentStatus, error)
 }
 
-type realizer struct{}
+// defaultConcurrency bounds how many components of a supply chain are
+// realized at the same time.
+const defaultConcurrency = 4
+
+type realizer struct {
+	concurrency int
+}
 
 func NewRealizer() Realizer {
-	return &realizer{}
+	return &realizer{concurrency: defaultConcurrency}
+}
+
+type componentResult struct {
+	index   int
+	stamped *StampedObject
+	output  *templates.Output
+	err     error
 }
 
-func (r *realizer) Realize(ctx context.Context, componentRealizer ComponentRealizer, supplyChain *v1alpha1.ClusterSupplyChain) error {
+// Realize realizes the components of the supply chain, concurrently where
+// they do not depend on each other. A component is started once every
+// component it depends on is realized. After a component fails, the
+// components already started are completed and the rest are blocked. The
+// health of each component is judged along with the transitions recorded
+// for it in the previous statuses.
+func (r *realizer) Realize(ctx context.Context, componentRealizer ComponentRealizer, supplyChain *v1alpha1.ClusterSupplyChain, previous []v1alpha1.ComponentStatus) ([]v1alpha1.ComponentStatus, error) {
+	order, err := supplyChain.Spec.RealizationOrder()
+	if err != nil {
+		return nil, fmt.Errorf("realization order: %w", err)
+	}
+	dependencies := supplyChain.Spec.DependencyIndexes()
+	components := supplyChain.Spec.Components
+	prior := map[string]*v1alpha1.ComponentStatus{}
+	for i := range previous {
+		prior[previous[i].Name] = &previous[i]
+	}
+	now := time.Now()
+
 	outs := NewOutputs()
+	statuses := make([]v1alpha1.ComponentStatus, len(components))
+	started := make([]bool, len(components))
+	realized := make([]bool, len(components))
+	results := make(chan componentResult)
+
+	var (
+		running int
+		failed  *componentResult
+	)
+	for {
+		for _, i := range order {
+			if failed != nil || running >= r.concurrency {
+				break
+			}
+			if started[i] || !allRealized(dependencies[i], realized) {
+				continue
+			}
+
+			started[i] = true
+			running++
+			go func(i int, inputs Outputs) {
+				component := components[i]
+				stamped, out, err := componentRealizer.Do(ctx, &component, supplyChain.Name, inputs)
+				results <- componentResult{index: i, stamped: stamped, output: out, err: err}
+			}(i, outs.copy())
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+		component := components[result.index]
+		health, conditions, transitions := componentHealth(&component, result.stamped, result.err == nil, prior[component.Name], now)
+		if result.err == nil && component.ReadinessGate && health.Status != metav1.ConditionTrue {
+			result.err = ReadinessGateError{Component: &component, Health: health}
+		}
+		if result.err != nil {
+			statuses[result.index] = v1alpha1.ComponentStatus{
+				Name:        component.Name,
+				State:       v1alpha1.FailedComponentState,
+				Message:     result.err.Error(),
+				StampedRef:  stampedRef(result.stamped),
+				TemplateRef: templateRef(result.stamped),
+				Conditions:  conditions,
+				Service:     serviceStatus(result.stamped),
+
+				HealthTransitions: transitions,
+			}
+			switch result.err.(type) {
+			case RetrieveOutputError, PendingHookError, ReadinessGateError:
+				statuses[result.index].State = v1alpha1.WaitingComponentState
+			}
+			if failed == nil || position(order, result.index) < position(order, failed.index) {
+				failed = &result
+			}
+			continue
+		}
+
+		outs.AddOutput(component.Name, result.output)
+		realized[result.index] = true
+		statuses[result.index] = v1alpha1.ComponentStatus{
+			Name:        component.Name,
+			State:       v1alpha1.RealizedComponentState,
+			StampedRef:  stampedRef(result.stamped),
+			TemplateRef: templateRef(result.stamped),
+			Conditions:  conditions,
+			Outputs:     componentOutputs(result.output),
+			Service:     serviceStatus(result.stamped),
+
+			HealthTransitions: transitions,
+		}
+	}
+
+	if failed == nil {
+		return statuses, nil
+	}
+
+	for i, component := range components {
+		if !started[i] {
+			statuses[i] = v1alpha1.ComponentStatus{
+				Name:    component.Name,
+				State:   v1alpha1.BlockedComponentState,
+				Message: fmt.Sprintf("blocked by component '%s'", components[failed.index].Name),
+			}
+			if was, ok := prior[component.Name]; ok {
+				statuses[i].HealthTransitions = was.HealthTransitions
+			}
+		}
+	}
+	return statuses, failed.err
+}
+
+// stampedRef refers to the object stamped for a component, or is nil when
+// none was, e.g. for a component resolving an artifact.
+func stampedRef(stamped *StampedObject) *corev1.ObjectReference {
+	if stamped == nil || stamped.Object == nil {
+		return nil
+	}
+
+	stampedObject := stamped.Object
+	apiVersion, kind := stampedObject.GroupVersionKind().ToAPIVersionAndKind()
+	return &corev1.ObjectReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  stampedObject.GetNamespace(),
+		Name:       stampedObject.GetName(),
+	}
+}
+
+// serviceStatus is where the Knative Service stamped for a component serves
+// the app, or nil when the object stamped is not one, or has no url yet.
+func serviceStatus(stamped *StampedObject) *v1alpha1.ServiceStatus {
+	if stamped == nil || !healthcheck.IsKnativeService(stamped.Object) {
+		return nil
+	}
 
-	for i := range supplyChain.Spec.Components {
-		component := supplyChain.Spec.Components[i]
-		out, err := componentRealizer.Do(ctx, &component, supplyChain.Name, outs)
+	content := stamped.Object.UnstructuredContent()
+	url, _, _ := unstructured.NestedString(content, "status", "url")
+	if url == "" {
+		return nil
+	}
+	revision, _, _ := unstructured.NestedString(content, "status", "latestReadyRevisionName")
+	return &v1alpha1.ServiceStatus{URL: url, LatestReadyRevision: revision}
+}
+
+// healthCondition judges the health of the object stamped for a component
+// by the health rule of its template.
+func healthCondition(stamped *StampedObject, outputsAvailable bool) metav1.Condition {
+	if stamped == nil || stamped.Object == nil {
+		return healthcheck.DetermineHealthCondition(nil, nil, outputsAvailable)
+	}
+	return healthcheck.DetermineHealthCondition(stamped.HealthRule, stamped.Object, outputsAvailable)
+}
+
+// templateRef refers to the template of a component, or is nil when it
+// could not be retrieved.
+func templateRef(stamped *StampedObject) *corev1.ObjectReference {
+	if stamped == nil {
+		return nil
+	}
+	return stamped.TemplateRef
+}
+
+// maxOutputPreview is how much of an output's JSON its preview holds.
+const maxOutputPreview = 1024
+
+// componentOutputs summarizes the values a component provides, in the
+// order url, revision, image, config.
+func componentOutputs(output *templates.Output) []v1alpha1.ComponentOutput {
+	if output == nil {
+		return nil
+	}
+
+	var values []namedValue
+	if output.Source != nil {
+		values = append(values, namedValue{"url", output.Source.URL}, namedValue{"revision", output.Source.Revision})
+	}
+	values = append(values, namedValue{"image", output.Image}, namedValue{"config", output.Config})
+
+	var outputs []v1alpha1.ComponentOutput
+	for _, value := range values {
+		if value.value == nil {
+			continue
+		}
+		encoded, err := json.Marshal(value.value)
 		if err != nil {
-			return err
+			encoded = []byte(fmt.Sprintf("%q", fmt.Sprint(value.value)))
 		}
-		outs.AddOutput(component.Name, out)
+
+		preview := string(encoded)
+		if len(preview) > maxOutputPreview {
+			preview = preview[:maxOutputPreview] + "..."
+		}
+		outputs = append(outputs, v1alpha1.ComponentOutput{
+			Name:    value.name,
+			Preview: preview,
+			Digest:  fmt.Sprintf("sha256:%x", sha256.Sum256(encoded)),
+		})
+	}
+	return outputs
+}
+
+type namedValue struct {
+	name  string
+	value interface{}
+}
+
+func allRealized(dependencies []int, realized []bool) bool {
+	for _, dependency := range dependencies {
+		if !realized[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
+func position(order []int, index int) int {
+	for p, i := range order {
+		if i == index {
+			return p
+		}
+	}
+	return len(order)
+}
+
+// Progress returns the percentage of the components that are realized.
+func Progress(statuses []v1alpha1.ComponentStatus) int32 {
+	if len(statuses) == 0 {
+		return 0
 	}
 
-	return nil
+	realized := 0
+	for _, status := range statuses {
+		if status.State == v1alpha1.RealizedComponentState {
+			realized++
+		}
+	}
+	return int32(realized * 100 / len(statuses))
 }
@@ -17,10 +17,14 @@ package workload_test
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
 	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/workload"
@@ -28,6 +32,15 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
 
+var (
+	healthy = []metav1.Condition{
+		{Type: "Healthy", Status: metav1.ConditionTrue, Reason: "OutputsAvailable"},
+	}
+	healthUnknown = []metav1.Condition{
+		{Type: "Healthy", Status: metav1.ConditionUnknown, Reason: "OutputsNotAvailable"},
+	}
+)
+
 var _ = Describe("Realize", func() {
 	var (
 		componentRealizer *workloadfakes.FakeComponentRealizer
@@ -54,17 +67,20 @@ var _ = Describe("Realize", func() {
 		}
 	})
 
-	It("realizes each component in supply chain order, accumulating output for each subsequent component", func() {
+	It("realizes each component after those it consumes, accumulating their outputs", func() {
+		supplyChain.Spec.Components[1].Images = []v1alpha1.ComponentReference{
+			{Name: "image", Component: "component1"},
+		}
 		outputFromFirstComponent := &templates.Output{Image: "whatever"}
 
 		var executedComponentOrder []string
 
-		componentRealizer.DoCalls(func(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs realizer.Outputs) (*templates.Output, error) {
+		componentRealizer.DoCalls(func(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs realizer.Outputs) (*realizer.StampedObject, *templates.Output, error) {
 			executedComponentOrder = append(executedComponentOrder, component.Name)
 			Expect(supplyChainName).To(Equal("greatest-supply-chain"))
 			if component.Name == "component1" {
 				Expect(outputs).To(Equal(realizer.NewOutputs()))
-				return outputFromFirstComponent, nil
+				return nil, outputFromFirstComponent, nil
 			}
 
 			if component.Name == "component2" {
@@ -73,16 +89,434 @@ var _ = Describe("Realize", func() {
 				Expect(outputs).To(Equal(expectedSecondComponentOutputs))
 			}
 
-			return &templates.Output{}, nil
+			return nil, &templates.Output{}, nil
 		})
 
-		Expect(rlzr.Realize(context.TODO(), componentRealizer, supplyChain)).To(Succeed())
+		_, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+		Expect(err).NotTo(HaveOccurred())
 
 		Expect(executedComponentOrder).To(Equal([]string{"component1", "component2"}))
 	})
 
+	It("reports every component as realized", func() {
+		componentRealizer.DoReturns(nil, &templates.Output{}, nil)
+
+		statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(statuses).To(Equal([]v1alpha1.ComponentStatus{
+			{Name: "component1", State: "Realized", Conditions: healthy},
+			{Name: "component2", State: "Realized", Conditions: healthy},
+		}))
+		Expect(realizer.Progress(statuses)).To(Equal(int32(100)))
+	})
+
+	It("refers to the object stamped for each component", func() {
+		stamped := &unstructured.Unstructured{}
+		stamped.SetAPIVersion("kpack.io/v1alpha1")
+		stamped.SetKind("Image")
+		stamped.SetNamespace("dev")
+		stamped.SetName("petclinic")
+		componentRealizer.DoReturnsOnCall(0, &realizer.StampedObject{Object: stamped}, &templates.Output{}, nil)
+		componentRealizer.DoReturnsOnCall(1, &realizer.StampedObject{Object: stamped}, nil, errors.New("interceptor is down"))
+
+		statuses, _ := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+
+		ref := &corev1.ObjectReference{APIVersion: "kpack.io/v1alpha1", Kind: "Image", Namespace: "dev", Name: "petclinic"}
+		Expect(statuses[0].StampedRef).To(Equal(ref))
+		Expect(statuses[1].StampedRef).To(Equal(ref))
+	})
+
+	It("refers to the template of each component and lists the outputs it provides", func() {
+		templateRef := &corev1.ObjectReference{APIVersion: "carto.run/v1alpha1", Kind: "ClusterSourceTemplate", Name: "git"}
+		componentRealizer.DoCalls(func(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs realizer.Outputs) (*realizer.StampedObject, *templates.Output, error) {
+			if component.Name == "component1" {
+				return &realizer.StampedObject{TemplateRef: templateRef}, &templates.Output{
+					Source: &templates.Source{URL: "https://example.com/source.tar.gz", Revision: "abc123"},
+				}, nil
+			}
+			return &realizer.StampedObject{TemplateRef: templateRef}, nil, errors.New("interceptor is down")
+		})
+
+		statuses, _ := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+
+		Expect(statuses[0].TemplateRef).To(Equal(templateRef))
+		Expect(statuses[0].StampedRef).To(BeNil())
+		Expect(statuses[0].Outputs).To(Equal([]v1alpha1.ComponentOutput{
+			{
+				Name:    "url",
+				Preview: `"https://example.com/source.tar.gz"`,
+				Digest:  "sha256:41b7f9d09e83e191130e5c7b68afa315e570038df687b5d6528c515af9a67df9",
+			},
+			{
+				Name:    "revision",
+				Preview: `"abc123"`,
+				Digest:  "sha256:3f59069122f3a32d3c09ce5ef4882e49feb7777b539cdc4be0d214fa3332e11e",
+			},
+		}))
+		Expect(statuses[1].TemplateRef).To(Equal(templateRef))
+		Expect(statuses[1].Outputs).To(BeNil())
+	})
+
+	It("truncates the preview of a long output", func() {
+		componentRealizer.DoReturns(nil, &templates.Output{Config: strings.Repeat("a", 2000)}, nil)
+
+		statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(statuses[0].Outputs).To(HaveLen(1))
+		Expect(statuses[0].Outputs[0].Name).To(Equal("config"))
+		Expect(statuses[0].Outputs[0].Preview).To(HaveLen(1027))
+		Expect(statuses[0].Outputs[0].Preview).To(HaveSuffix("..."))
+	})
+
+	It("reports where a stamped Knative Service serves the app", func() {
+		service := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1",
+			"kind":       "Service",
+			"status": map[string]interface{}{
+				"url":                     "https://petclinic.dev.example.com",
+				"latestReadyRevisionName": "petclinic-00002",
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "True"},
+				},
+			},
+		}}
+		componentRealizer.DoCalls(func(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs realizer.Outputs) (*realizer.StampedObject, *templates.Output, error) {
+			if component.Name == "component1" {
+				return &realizer.StampedObject{Object: service}, &templates.Output{}, nil
+			}
+			return &realizer.StampedObject{Object: &unstructured.Unstructured{}}, &templates.Output{}, nil
+		})
+
+		statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(statuses[0].Service).To(Equal(&v1alpha1.ServiceStatus{URL: "https://petclinic.dev.example.com", LatestReadyRevision: "petclinic-00002"}))
+		Expect(statuses[0].Conditions[0].Reason).To(Equal("KnativeService"))
+		Expect(statuses[1].Service).To(BeNil())
+	})
+
+	It("judges the health of each stamped object by its template's health rule", func() {
+		stamped := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "False", "message": "build failed"},
+				},
+			},
+		}}
+		componentRealizer.DoCalls(func(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs realizer.Outputs) (*realizer.StampedObject, *templates.Output, error) {
+			if component.Name == "component1" {
+				return &realizer.StampedObject{
+					Object:     stamped,
+					HealthRule: &v1alpha1.HealthRule{SingleConditionType: "Ready"},
+				}, &templates.Output{}, nil
+			}
+			return &realizer.StampedObject{Object: stamped}, &templates.Output{}, nil
+		})
+
+		statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(statuses[0].Conditions).To(Equal([]metav1.Condition{{
+			Type:    "Healthy",
+			Status:  metav1.ConditionFalse,
+			Reason:  "SingleConditionType",
+			Message: "condition status: False, message: build failed",
+		}}))
+		Expect(statuses[1].Conditions).To(Equal(healthy))
+	})
+
+	Context("when a component has a readiness gate", func() {
+		var health *v1alpha1.HealthRule
+
+		BeforeEach(func() {
+			supplyChain.Spec.Components[0].ReadinessGate = true
+			supplyChain.Spec.Components[1].DependsOn = []string{"component1"}
+			health = &v1alpha1.HealthRule{SingleConditionType: "Succeeded"}
+		})
+
+		stampedWithStatus := func(status string) *realizer.StampedObject {
+			return &realizer.StampedObject{
+				Object: &unstructured.Unstructured{Object: map[string]interface{}{
+					"status": map[string]interface{}{
+						"conditions": []interface{}{
+							map[string]interface{}{"type": "Succeeded", "status": status},
+						},
+					},
+				}},
+				HealthRule: health,
+			}
+		}
+
+		It("holds back the components after it until its object is healthy", func() {
+			componentRealizer.DoReturns(stampedWithStatus("Unknown"), &templates.Output{}, nil)
+
+			statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+			Expect(err).To(BeAssignableToTypeOf(realizer.ReadinessGateError{}))
+			Expect(err).To(MatchError("component 'component1' is waiting on its object to become healthy: condition status: Unknown"))
+
+			Expect(componentRealizer.DoCallCount()).To(Equal(1))
+			Expect(statuses[0].State).To(Equal(v1alpha1.WaitingComponentState))
+			Expect(statuses[0].Conditions[0].Status).To(Equal(metav1.ConditionUnknown))
+			Expect(statuses[1]).To(Equal(v1alpha1.ComponentStatus{
+				Name: "component2", State: "Blocked", Message: "blocked by component 'component1'",
+			}))
+		})
+
+		It("realizes the components after it once its object is healthy", func() {
+			componentRealizer.DoReturns(stampedWithStatus("True"), &templates.Output{}, nil)
+
+			statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(componentRealizer.DoCallCount()).To(Equal(2))
+			Expect(statuses[0].State).To(Equal(v1alpha1.RealizedComponentState))
+			Expect(statuses[1].State).To(Equal(v1alpha1.RealizedComponentState))
+		})
+	})
+
+	Context("when the health of a stamped object changes", func() {
+		var (
+			stamped  *realizer.StampedObject
+			previous []v1alpha1.ComponentStatus
+		)
+
+		transition := func(status metav1.ConditionStatus, ago time.Duration) v1alpha1.HealthTransition {
+			return v1alpha1.HealthTransition{Status: status, Time: metav1.NewTime(time.Now().Add(-ago))}
+		}
+
+		BeforeEach(func() {
+			stamped = &realizer.StampedObject{
+				Object: &unstructured.Unstructured{Object: map[string]interface{}{
+					"status": map[string]interface{}{
+						"conditions": []interface{}{
+							map[string]interface{}{"type": "Ready", "status": "True"},
+						},
+					},
+				}},
+				HealthRule: &v1alpha1.HealthRule{SingleConditionType: "Ready"},
+			}
+			componentRealizer.DoReturns(stamped, &templates.Output{}, nil)
+			previous = []v1alpha1.ComponentStatus{{
+				Name:              "component1",
+				HealthTransitions: []v1alpha1.HealthTransition{transition(metav1.ConditionFalse, time.Minute)},
+			}}
+		})
+
+		It("records when the object became healthy or unhealthy", func() {
+			statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, previous)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(statuses[0].HealthTransitions).To(HaveLen(2))
+			Expect(statuses[0].HealthTransitions[1].Status).To(Equal(metav1.ConditionTrue))
+			Expect(statuses[0].HealthTransitions[1].Time.Time).To(BeTemporally("~", time.Now(), time.Second))
+			Expect(statuses[0].Conditions).To(HaveLen(1))
+		})
+
+		It("keeps the transitions when the health did not change, dropping those out of the flap window but the last", func() {
+			previous[0].HealthTransitions = []v1alpha1.HealthTransition{
+				transition(metav1.ConditionFalse, time.Hour),
+				transition(metav1.ConditionTrue, 30*time.Minute),
+			}
+
+			statuses, _ := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, previous)
+			Expect(statuses[0].HealthTransitions).To(Equal(previous[0].HealthTransitions[1:]))
+		})
+
+		It("reports the object flapping when its health changed often within the flap window", func() {
+			previous[0].HealthTransitions = []v1alpha1.HealthTransition{
+				transition(metav1.ConditionTrue, 20*time.Minute),
+				transition(metav1.ConditionFalse, 8*time.Minute),
+				transition(metav1.ConditionTrue, 6*time.Minute),
+				transition(metav1.ConditionFalse, 4*time.Minute),
+			}
+
+			statuses, _ := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, previous)
+			Expect(statuses[0].Conditions).To(ContainElement(metav1.Condition{
+				Type:    "HealthFlapping",
+				Status:  metav1.ConditionTrue,
+				Reason:  "FrequentChanges",
+				Message: "health changed 4 times in the last 10m0s",
+			}))
+		})
+
+		It("does not report the object flapping for changes out of the flap window", func() {
+			previous[0].HealthTransitions = []v1alpha1.HealthTransition{
+				transition(metav1.ConditionTrue, 40*time.Minute),
+				transition(metav1.ConditionFalse, 30*time.Minute),
+				transition(metav1.ConditionTrue, 20*time.Minute),
+				transition(metav1.ConditionFalse, 4*time.Minute),
+			}
+
+			statuses, _ := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, previous)
+			Expect(statuses[0].Conditions).To(HaveLen(1))
+		})
+
+		Context("and its component has minHealthySeconds", func() {
+			BeforeEach(func() {
+				supplyChain.Spec.Components[0].MinHealthySeconds = 120
+				supplyChain.Spec.Components[0].ReadinessGate = true
+				supplyChain.Spec.Components[1].DependsOn = []string{"component1"}
+			})
+
+			It("deems the object of unknown health, holding back the components after it, until it has been healthy long enough", func() {
+				previous[0].HealthTransitions = append(previous[0].HealthTransitions, transition(metav1.ConditionTrue, 30*time.Second))
+
+				statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, previous)
+				Expect(err).To(BeAssignableToTypeOf(realizer.ReadinessGateError{}))
+
+				Expect(componentRealizer.DoCallCount()).To(Equal(1))
+				Expect(statuses[0].Conditions[0].Status).To(Equal(metav1.ConditionUnknown))
+				Expect(statuses[0].Conditions[0].Reason).To(Equal("Stabilizing"))
+				Expect(statuses[0].Conditions[0].Message).To(Equal("healthy for 30s of the 2m0s required"))
+				Expect(statuses[1].State).To(Equal(v1alpha1.BlockedComponentState))
+			})
+
+			It("deems the object healthy once it has been healthy long enough", func() {
+				previous[0].HealthTransitions = append(previous[0].HealthTransitions, transition(metav1.ConditionTrue, 3*time.Minute))
+
+				statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, previous)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(statuses[0].Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+				Expect(statuses[1].State).To(Equal(v1alpha1.RealizedComponentState))
+			})
+		})
+	})
+
 	It("returns any error encountered realizing a component", func() {
-		componentRealizer.DoReturns(nil, errors.New("realizing is hard"))
-		Expect(rlzr.Realize(context.TODO(), componentRealizer, supplyChain)).To(MatchError("realizing is hard"))
+		componentRealizer.DoReturns(nil, nil, errors.New("realizing is hard"))
+		_, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+		Expect(err).To(MatchError("realizing is hard"))
+	})
+
+	It("reports a failed component and blocks the components after it", func() {
+		supplyChain.Spec.Components[1].DependsOn = []string{"component1"}
+		componentRealizer.DoReturns(nil, nil, errors.New("realizing is hard"))
+
+		statuses, _ := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+
+		Expect(statuses).To(Equal([]v1alpha1.ComponentStatus{
+			{Name: "component1", State: "Failed", Message: "realizing is hard", Conditions: healthUnknown},
+			{Name: "component2", State: "Blocked", Message: "blocked by component 'component1'"},
+		}))
+		Expect(realizer.Progress(statuses)).To(Equal(int32(0)))
+	})
+
+	It("reports a component waiting on its outputs", func() {
+		supplyChain.Spec.Components[1].DependsOn = []string{"component1"}
+		waiting := realizer.NewRetrieveOutputError(&component2, errors.New("no value at path"))
+		componentRealizer.DoReturnsOnCall(0, nil, &templates.Output{}, nil)
+		componentRealizer.DoReturnsOnCall(1, nil, nil, waiting)
+
+		statuses, _ := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+
+		Expect(statuses).To(Equal([]v1alpha1.ComponentStatus{
+			{Name: "component1", State: "Realized", Conditions: healthy},
+			{Name: "component2", State: "Waiting", Message: waiting.Error(), Conditions: healthUnknown},
+		}))
+		Expect(realizer.Progress(statuses)).To(Equal(int32(50)))
+	})
+
+	It("reports a component waiting on a pre hook", func() {
+		supplyChain.Spec.Components[1].DependsOn = []string{"component1"}
+		waiting := realizer.PendingHookError{Component: &component2, Hook: "migrate"}
+		componentRealizer.DoReturnsOnCall(0, nil, &templates.Output{}, nil)
+		componentRealizer.DoReturnsOnCall(1, nil, nil, waiting)
+
+		statuses, _ := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+
+		Expect(statuses).To(Equal([]v1alpha1.ComponentStatus{
+			{Name: "component1", State: "Realized", Conditions: healthy},
+			{Name: "component2", State: "Waiting", Message: waiting.Error(), Conditions: healthUnknown},
+		}))
+	})
+
+	Context("when a component depends on a later component", func() {
+		BeforeEach(func() {
+			supplyChain.Spec.Components[0].DependsOn = []string{"component2"}
+		})
+
+		It("realizes the dependency first", func() {
+			var executedComponentOrder []string
+			componentRealizer.DoCalls(func(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs realizer.Outputs) (*realizer.StampedObject, *templates.Output, error) {
+				executedComponentOrder = append(executedComponentOrder, component.Name)
+				return nil, &templates.Output{}, nil
+			})
+
+			statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(executedComponentOrder).To(Equal([]string{"component2", "component1"}))
+			Expect(statuses[0].Name).To(Equal("component1"))
+		})
+
+		It("blocks the dependent component when its dependency fails", func() {
+			componentRealizer.DoReturns(nil, nil, errors.New("realizing is hard"))
+
+			statuses, _ := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+
+			Expect(statuses).To(Equal([]v1alpha1.ComponentStatus{
+				{Name: "component1", State: "Blocked", Message: "blocked by component 'component2'"},
+				{Name: "component2", State: "Failed", Message: "realizing is hard", Conditions: healthUnknown},
+			}))
+		})
+	})
+
+	Context("when components do not depend on each other", func() {
+		It("realizes them concurrently", func() {
+			started := make(chan string, 2)
+			release := make(chan struct{})
+			componentRealizer.DoCalls(func(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs realizer.Outputs) (*realizer.StampedObject, *templates.Output, error) {
+				started <- component.Name
+				<-release
+				return nil, &templates.Output{}, nil
+			})
+
+			done := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				_, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+				Expect(err).NotTo(HaveOccurred())
+			}()
+
+			Eventually(started).Should(Receive())
+			Eventually(started).Should(Receive())
+			close(release)
+			Eventually(done).Should(BeClosed())
+		})
+
+		It("completes the components already started when one fails, and blocks the rest", func() {
+			component3 := v1alpha1.SupplyChainComponent{Name: "component3", DependsOn: []string{"component1"}}
+			supplyChain.Spec.Components = append(supplyChain.Spec.Components, component3)
+
+			componentRealizer.DoCalls(func(ctx context.Context, component *v1alpha1.SupplyChainComponent, supplyChainName string, outputs realizer.Outputs) (*realizer.StampedObject, *templates.Output, error) {
+				if component.Name == "component1" {
+					return nil, nil, errors.New("realizing is hard")
+				}
+				return nil, &templates.Output{}, nil
+			})
+
+			statuses, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+			Expect(err).To(MatchError("realizing is hard"))
+
+			Expect(statuses).To(Equal([]v1alpha1.ComponentStatus{
+				{Name: "component1", State: "Failed", Message: "realizing is hard", Conditions: healthUnknown},
+				{Name: "component2", State: "Realized", Conditions: healthy},
+				{Name: "component3", State: "Blocked", Message: "blocked by component 'component1'"},
+			}))
+		})
+	})
+
+	It("returns an error when the components cannot be ordered", func() {
+		supplyChain.Spec.Components[0].DependsOn = []string{"component2"}
+		supplyChain.Spec.Components[1].DependsOn = []string{"component1"}
+
+		_, err := rlzr.Realize(context.TODO(), componentRealizer, supplyChain, nil)
+
+		Expect(err).To(MatchError("realization order: cannot order components 'component1', 'component2': dependencies form a cycle"))
+		Expect(componentRealizer.DoCallCount()).To(Equal(0))
 	})
 })
@@ -11,7 +11,7 @@ import (
 )
 
 type FakeComponentRealizer struct {
-	DoStub        func(context.Context, *v1alpha1.SupplyChainComponent, string, workload.Outputs) (*templates.Output, error)
+	DoStub        func(context.Context, *v1alpha1.SupplyChainComponent, string, workload.Outputs) (*workload.StampedObject, *templates.Output, error)
 	doMutex       sync.RWMutex
 	doArgsForCall []struct {
 		arg1 context.Context
@@ -20,18 +20,20 @@ type FakeComponentRealizer struct {
 		arg4 workload.Outputs
 	}
 	doReturns struct {
-		result1 *templates.Output
-		result2 error
+		result1 *workload.StampedObject
+		result2 *templates.Output
+		result3 error
 	}
 	doReturnsOnCall map[int]struct {
-		result1 *templates.Output
-		result2 error
+		result1 *workload.StampedObject
+		result2 *templates.Output
+		result3 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeComponentRealizer) Do(arg1 context.Context, arg2 *v1alpha1.SupplyChainComponent, arg3 string, arg4 workload.Outputs) (*templates.Output, error) {
+func (fake *FakeComponentRealizer) Do(arg1 context.Context, arg2 *v1alpha1.SupplyChainComponent, arg3 string, arg4 workload.Outputs) (*workload.StampedObject, *templates.Output, error) {
 	fake.doMutex.Lock()
 	ret, specificReturn := fake.doReturnsOnCall[len(fake.doArgsForCall)]
 	fake.doArgsForCall = append(fake.doArgsForCall, struct {
@@ -48,9 +50,9 @@ func (fake *FakeComponentRealizer) Do(arg1 context.Context, arg2 *v1alpha1.Suppl
 		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *FakeComponentRealizer) DoCallCount() int {
@@ -59,7 +61,7 @@ func (fake *FakeComponentRealizer) DoCallCount() int {
 	return len(fake.doArgsForCall)
 }
 
-func (fake *FakeComponentRealizer) DoCalls(stub func(context.Context, *v1alpha1.SupplyChainComponent, string, workload.Outputs) (*templates.Output, error)) {
+func (fake *FakeComponentRealizer) DoCalls(stub func(context.Context, *v1alpha1.SupplyChainComponent, string, workload.Outputs) (*workload.StampedObject, *templates.Output, error)) {
 	fake.doMutex.Lock()
 	defer fake.doMutex.Unlock()
 	fake.DoStub = stub
@@ -72,30 +74,33 @@ func (fake *FakeComponentRealizer) DoArgsForCall(i int) (context.Context, *v1alp
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeComponentRealizer) DoReturns(result1 *templates.Output, result2 error) {
+func (fake *FakeComponentRealizer) DoReturns(result1 *workload.StampedObject, result2 *templates.Output, result3 error) {
 	fake.doMutex.Lock()
 	defer fake.doMutex.Unlock()
 	fake.DoStub = nil
 	fake.doReturns = struct {
-		result1 *templates.Output
-		result2 error
-	}{result1, result2}
+		result1 *workload.StampedObject
+		result2 *templates.Output
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakeComponentRealizer) DoReturnsOnCall(i int, result1 *templates.Output, result2 error) {
+func (fake *FakeComponentRealizer) DoReturnsOnCall(i int, result1 *workload.StampedObject, result2 *templates.Output, result3 error) {
 	fake.doMutex.Lock()
 	defer fake.doMutex.Unlock()
 	fake.DoStub = nil
 	if fake.doReturnsOnCall == nil {
 		fake.doReturnsOnCall = make(map[int]struct {
-			result1 *templates.Output
-			result2 error
+			result1 *workload.StampedObject
+			result2 *templates.Output
+			result3 error
 		})
 	}
 	fake.doReturnsOnCall[i] = struct {
-		result1 *templates.Output
-		result2 error
-	}{result1, result2}
+		result1 *workload.StampedObject
+		result2 *templates.Output
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *FakeComponentRealizer) Invocations() map[string][][]interface{} {
@@ -10,42 +10,51 @@ import (
 )
 
 type FakeRealizer struct {
-	RealizeStub        func(context.Context, workload.ComponentRealizer, *v1alpha1.ClusterSupplyChain) error
+	RealizeStub        func(context.Context, workload.ComponentRealizer, *v1alpha1.ClusterSupplyChain, []v1alpha1.ComponentStatus) ([]v1alpha1.ComponentStatus, error)
 	realizeMutex       sync.RWMutex
 	realizeArgsForCall []struct {
 		arg1 context.Context
 		arg2 workload.ComponentRealizer
 		arg3 *v1alpha1.ClusterSupplyChain
+		arg4 []v1alpha1.ComponentStatus
 	}
 	realizeReturns struct {
-		result1 error
+		result1 []v1alpha1.ComponentStatus
+		result2 error
 	}
 	realizeReturnsOnCall map[int]struct {
-		result1 error
+		result1 []v1alpha1.ComponentStatus
+		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeRealizer) Realize(arg1 context.Context, arg2 workload.ComponentRealizer, arg3 *v1alpha1.ClusterSupplyChain) error {
+func (fake *FakeRealizer) Realize(arg1 context.Context, arg2 workload.ComponentRealizer, arg3 *v1alpha1.ClusterSupplyChain, arg4 []v1alpha1.ComponentStatus) ([]v1alpha1.ComponentStatus, error) {
+	var arg4Copy []v1alpha1.ComponentStatus
+	if arg4 != nil {
+		arg4Copy = make([]v1alpha1.ComponentStatus, len(arg4))
+		copy(arg4Copy, arg4)
+	}
 	fake.realizeMutex.Lock()
 	ret, specificReturn := fake.realizeReturnsOnCall[len(fake.realizeArgsForCall)]
 	fake.realizeArgsForCall = append(fake.realizeArgsForCall, struct {
 		arg1 context.Context
 		arg2 workload.ComponentRealizer
 		arg3 *v1alpha1.ClusterSupplyChain
-	}{arg1, arg2, arg3})
+		arg4 []v1alpha1.ComponentStatus
+	}{arg1, arg2, arg3, arg4Copy})
 	stub := fake.RealizeStub
 	fakeReturns := fake.realizeReturns
-	fake.recordInvocation("Realize", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("Realize", []interface{}{arg1, arg2, arg3, arg4Copy})
 	fake.realizeMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeRealizer) RealizeCallCount() int {
@@ -54,40 +63,43 @@ func (fake *FakeRealizer) RealizeCallCount() int {
 	return len(fake.realizeArgsForCall)
 }
 
-func (fake *FakeRealizer) RealizeCalls(stub func(context.Context, workload.ComponentRealizer, *v1alpha1.ClusterSupplyChain) error) {
+func (fake *FakeRealizer) RealizeCalls(stub func(context.Context, workload.ComponentRealizer, *v1alpha1.ClusterSupplyChain, []v1alpha1.ComponentStatus) ([]v1alpha1.ComponentStatus, error)) {
 	fake.realizeMutex.Lock()
 	defer fake.realizeMutex.Unlock()
 	fake.RealizeStub = stub
 }
 
-func (fake *FakeRealizer) RealizeArgsForCall(i int) (context.Context, workload.ComponentRealizer, *v1alpha1.ClusterSupplyChain) {
+func (fake *FakeRealizer) RealizeArgsForCall(i int) (context.Context, workload.ComponentRealizer, *v1alpha1.ClusterSupplyChain, []v1alpha1.ComponentStatus) {
 	fake.realizeMutex.RLock()
 	defer fake.realizeMutex.RUnlock()
 	argsForCall := fake.realizeArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeRealizer) RealizeReturns(result1 error) {
+func (fake *FakeRealizer) RealizeReturns(result1 []v1alpha1.ComponentStatus, result2 error) {
 	fake.realizeMutex.Lock()
 	defer fake.realizeMutex.Unlock()
 	fake.RealizeStub = nil
 	fake.realizeReturns = struct {
-		result1 error
-	}{result1}
+		result1 []v1alpha1.ComponentStatus
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeRealizer) RealizeReturnsOnCall(i int, result1 error) {
+func (fake *FakeRealizer) RealizeReturnsOnCall(i int, result1 []v1alpha1.ComponentStatus, result2 error) {
 	fake.realizeMutex.Lock()
 	defer fake.realizeMutex.Unlock()
 	fake.RealizeStub = nil
 	if fake.realizeReturnsOnCall == nil {
 		fake.realizeReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 []v1alpha1.ComponentStatus
+			result2 error
 		})
 	}
 	fake.realizeReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 []v1alpha1.ComponentStatus
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeRealizer) Invocations() map[string][][]interface{} {
@@ -0,0 +1,182 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registrar
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+// NewClientFunc creates the manager's client, which reads stamped objects
+// of watchedKinds, or of every kind when watchedKinds is empty, from the
+// manager's shared informers, as it already reads cartographer's own objects.
+// An informer is started for a kind the first time an object of that kind is
+// read. Each call gives up after requestTimeout, unless it is zero.
+func NewClientFunc(watchedKinds []schema.GroupKind, requestTimeout time.Duration) cluster.NewClientFunc {
+	return func(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+		c, err := cluster.DefaultNewClient(cache, config, options, uncachedObjects...)
+		if err != nil {
+			return nil, err
+		}
+
+		c = NewInformerClient(c, cache, watchedKinds)
+		if requestTimeout > 0 {
+			c = NewTimeoutClient(c, requestTimeout)
+		}
+		return c, nil
+	}
+}
+
+// NewInformerClient reads unstructured objects of kinds, or of every kind
+// when kinds is empty, from informers, and every other object through c.
+func NewInformerClient(c client.Client, informers client.Reader, kinds []schema.GroupKind) client.Client {
+	return &informerClient{
+		Client:    c,
+		informers: informers,
+		kinds:     kinds,
+	}
+}
+
+type informerClient struct {
+	client.Client
+	informers client.Reader
+	kinds     []schema.GroupKind
+}
+
+func (c *informerClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if u, ok := obj.(*unstructured.Unstructured); ok && c.handles(u.GroupVersionKind().GroupKind()) {
+		return c.informers.Get(ctx, key, obj)
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *informerClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if u, ok := list.(*unstructured.UnstructuredList); ok {
+		gvk := u.GroupVersionKind()
+		if c.handles(schema.GroupKind{Group: gvk.Group, Kind: strings.TrimSuffix(gvk.Kind, "List")}) {
+			return c.informers.List(ctx, list, unpaged(opts))
+		}
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+// unpaged are the options without a page size or continue token: an
+// informer stops at the page size without returning a continue token, so
+// its objects are listed in full.
+func unpaged(opts []client.ListOption) *client.ListOptions {
+	listOptions := &client.ListOptions{}
+	listOptions.ApplyOptions(opts)
+	listOptions.Limit = 0
+	listOptions.Continue = ""
+	return listOptions
+}
+
+func (c *informerClient) handles(kind schema.GroupKind) bool {
+	if len(c.kinds) == 0 {
+		return true
+	}
+
+	for _, handled := range c.kinds {
+		if handled == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTimeoutClient gives up on each call through c after timeout, rather
+// than letting an unresponsive API server hold up a reconcile.
+func NewTimeoutClient(c client.Client, timeout time.Duration) client.Client {
+	return &timeoutClient{
+		Client:  c,
+		timeout: timeout,
+	}
+}
+
+type timeoutClient struct {
+	client.Client
+	timeout time.Duration
+}
+
+func (c *timeoutClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *timeoutClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *timeoutClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *timeoutClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *timeoutClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *timeoutClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *timeoutClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *timeoutClient) Status() client.StatusWriter {
+	return &timeoutStatusWriter{StatusWriter: c.Client.Status(), timeout: c.timeout}
+}
+
+type timeoutStatusWriter struct {
+	client.StatusWriter
+	timeout time.Duration
+}
+
+func (w *timeoutStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func (w *timeoutStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	return w.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,162 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registrar_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/cartographer/pkg/registrar"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+)
+
+var _ = Describe("NewInformerClient", func() {
+	var (
+		apiServer client.Client
+		informers client.Client
+		kinds     []schema.GroupKind
+		c         client.Client
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		apiServer = fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap("from-api-server"), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "from-api-server", Namespace: "dev"},
+		}).Build()
+		informers = fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap("from-informers")).Build()
+		kinds = nil
+	})
+
+	JustBeforeEach(func() {
+		c = registrar.NewInformerClient(apiServer, informers, kinds)
+	})
+
+	unstructuredList := func(kind string) *unstructured.UnstructuredList {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: kind + "List"})
+		return list
+	}
+
+	names := func(list *unstructured.UnstructuredList) []string {
+		var names []string
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		return names
+	}
+
+	It("reads unstructured objects of every kind from the informers", func() {
+		list := unstructuredList("ConfigMap")
+		Expect(c.List(context.TODO(), list)).To(Succeed())
+		Expect(names(list)).To(ConsistOf("from-informers"))
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})
+		Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "dev", Name: "from-informers"}, obj)).To(Succeed())
+	})
+
+	It("lists every object from the informers, whatever the page size", func() {
+		pagedInformers := &repositoryfakes.FakeClient{}
+		c = registrar.NewInformerClient(apiServer, pagedInformers, kinds)
+
+		Expect(c.List(context.TODO(), unstructuredList("ConfigMap"), client.InNamespace("dev"), client.Limit(500), client.Continue("page-2"))).To(Succeed())
+
+		_, _, opts := pagedInformers.ListArgsForCall(0)
+		listOptions := &client.ListOptions{}
+		listOptions.ApplyOptions(opts)
+		Expect(listOptions.Namespace).To(Equal("dev"))
+		Expect(listOptions.Limit).To(BeZero())
+		Expect(listOptions.Continue).To(BeEmpty())
+	})
+
+	It("reads typed objects through the client", func() {
+		list := &corev1.ConfigMapList{}
+		Expect(c.List(context.TODO(), list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].Name).To(Equal("from-api-server"))
+	})
+
+	Context("when only some kinds are watched", func() {
+		BeforeEach(func() {
+			kinds = []schema.GroupKind{{Kind: "ConfigMap"}}
+		})
+
+		It("reads unstructured objects of the watched kinds from the informers", func() {
+			list := unstructuredList("ConfigMap")
+			Expect(c.List(context.TODO(), list)).To(Succeed())
+			Expect(names(list)).To(ConsistOf("from-informers"))
+		})
+
+		It("reads unstructured objects of other kinds from the API server", func() {
+			list := unstructuredList("Secret")
+			Expect(c.List(context.TODO(), list)).To(Succeed())
+			Expect(names(list)).To(ConsistOf("from-api-server"))
+
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "Secret"})
+			Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "dev", Name: "from-api-server"}, obj)).To(Succeed())
+		})
+	})
+})
+
+var _ = Describe("NewTimeoutClient", func() {
+	var deadlines []time.Time
+
+	BeforeEach(func() {
+		deadlines = nil
+	})
+
+	recordDeadline := func(ctx context.Context) {
+		deadline, ok := ctx.Deadline()
+		Expect(ok).To(BeTrue())
+		deadlines = append(deadlines, deadline)
+	}
+
+	It("bounds every call with the timeout", func() {
+		fakeClient := &repositoryfakes.FakeClient{}
+		fakeClient.GetStub = func(ctx context.Context, _ client.ObjectKey, _ client.Object) error {
+			recordDeadline(ctx)
+			return nil
+		}
+		fakeClient.PatchStub = func(ctx context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+			recordDeadline(ctx)
+			return nil
+		}
+
+		c := registrar.NewTimeoutClient(fakeClient, time.Minute)
+		Expect(c.Get(context.TODO(), client.ObjectKey{}, configMap("some-name"))).To(Succeed())
+		Expect(c.Patch(context.TODO(), configMap("some-name"), client.Merge)).To(Succeed())
+
+		Expect(deadlines).To(HaveLen(2))
+		for _, deadline := range deadlines {
+			Expect(time.Until(deadline)).To(BeNumerically("~", time.Minute, time.Second))
+		}
+	})
+})
+
+func configMap(name string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "dev"}}
+}
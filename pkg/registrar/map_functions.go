@@ -18,11 +18,14 @@ import (
 	"context"
 	"fmt"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
 )
 
 //counterfeiter:generate . Logger
@@ -44,11 +47,17 @@ func (mapper *Mapper) ClusterSupplyChainToWorkloadRequests(object client.Object)
 		return nil
 	}
 
+	labelSelector, err := supplyChain.Spec.LabelSelector()
+	if err != nil {
+		mapper.Logger.Error(fmt.Errorf("label selector: %w", err), "cluster supply chain to workload requests: label selector")
+		return nil
+	}
+
 	list := &v1alpha1.WorkloadList{}
 
 	err = mapper.Client.List(context.TODO(), list,
 		client.InNamespace(supplyChain.Namespace),
-		client.MatchingLabels(supplyChain.Spec.Selector))
+		client.MatchingLabelsSelector{Selector: labelSelector})
 	if err != nil {
 		mapper.Logger.Error(fmt.Errorf("client list: %w", err), "cluster supply chain to workload requests: client list")
 		return nil
@@ -56,6 +65,9 @@ func (mapper *Mapper) ClusterSupplyChainToWorkloadRequests(object client.Object)
 
 	var requests []reconcile.Request
 	for _, workload := range list.Items {
+		if matches, err := selector.SupplyChainMatchesWorkload(&supplyChain.Spec, &workload); err != nil || !matches {
+			continue
+		}
 		requests = append(requests, reconcile.Request{
 			NamespacedName: types.NamespacedName{
 				Name:      workload.Name,
@@ -68,6 +80,18 @@ func (mapper *Mapper) ClusterSupplyChainToWorkloadRequests(object client.Object)
 
 }
 
+// SupplyChainToWorkloadRequests maps a namespaced SupplyChain to the
+// workloads it selects, which are in its own namespace.
+func (mapper *Mapper) SupplyChainToWorkloadRequests(object client.Object) []reconcile.Request {
+	supplyChain, ok := object.(*v1alpha1.SupplyChain)
+	if !ok {
+		mapper.Logger.Error(nil, "supply chain to workload requests: cast to SupplyChain failed")
+		return nil
+	}
+
+	return mapper.ClusterSupplyChainToWorkloadRequests(supplyChain.AsClusterSupplyChain())
+}
+
 func (mapper *Mapper) RunTemplateToPipelineRequests(object client.Object) []reconcile.Request {
 	var err error
 
@@ -100,8 +124,45 @@ func (mapper *Mapper) RunTemplateToPipelineRequests(object client.Object) []reco
 	return requests
 }
 
+func (mapper *Mapper) WorkloadToWorkloadPreviewRequests(object client.Object) []reconcile.Request {
+	var err error
+
+	workload, ok := object.(*v1alpha1.Workload)
+	if !ok {
+		mapper.Logger.Error(nil, "workload to workload preview requests: cast to Workload failed")
+		return nil
+	}
+
+	list := &v1alpha1.WorkloadPreviewList{}
+
+	err = mapper.Client.List(context.TODO(), list, client.InNamespace(workload.Namespace))
+	if err != nil {
+		mapper.Logger.Error(fmt.Errorf("client list: %w", err), "workload to workload preview requests: client list")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, preview := range list.Items {
+		if preview.Spec.Workload == workload.Name {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      preview.Name,
+					Namespace: preview.Namespace,
+				},
+			})
+		}
+	}
+
+	return requests
+}
+
 func runTemplateRefMatch(ref v1alpha1.TemplateReference, pipelineNamespace string, runTemplate *v1alpha1.RunTemplate) bool {
-	if ref.Name != runTemplate.Name {
+	if ref.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+		if err != nil || !selector.Matches(labels.Set(runTemplate.Labels)) {
+			return false
+		}
+	} else if ref.Name != runTemplate.Name {
 		return false
 	}
 
@@ -128,7 +128,7 @@ var _ = Describe("MapFunctions", func() {
 					It("returns a list of requests that includes the workload", func() {
 						expected := []reconcile.Request{
 							{
-								types.NamespacedName{
+								NamespacedName: types.NamespacedName{
 									Namespace: "first-namespace",
 									Name:      "first-workload",
 								},
@@ -138,6 +138,54 @@ var _ = Describe("MapFunctions", func() {
 						Expect(result).To(Equal(expected))
 					})
 				})
+				Context("supply chain with match expressions the workload meets", func() {
+					BeforeEach(func() {
+						workload.Labels = map[string]string{
+							"myLabel": "myLabelsValue",
+							"tier":    "web",
+						}
+						clusterSupplyChain.(*v1alpha1.ClusterSupplyChain).Spec.SelectorMatchExpressions = []metav1.LabelSelectorRequirement{
+							{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"web"}},
+						}
+						clientObjects = []client.Object{workload}
+					})
+
+					It("returns a list of requests that includes the workload", func() {
+						Expect(result).To(Equal([]reconcile.Request{
+							{NamespacedName: types.NamespacedName{Namespace: "first-namespace", Name: "first-workload"}},
+						}))
+					})
+				})
+				Context("supply chain with match expressions the workload does not meet", func() {
+					BeforeEach(func() {
+						workload.Labels = map[string]string{
+							"myLabel": "myLabelsValue",
+						}
+						clusterSupplyChain.(*v1alpha1.ClusterSupplyChain).Spec.SelectorMatchExpressions = []metav1.LabelSelectorRequirement{
+							{Key: "tier", Operator: metav1.LabelSelectorOpExists},
+						}
+						clientObjects = []client.Object{workload}
+					})
+
+					It("returns an empty list of requests", func() {
+						Expect(result).To(BeEmpty())
+					})
+				})
+				Context("supply chain with field requirements the workload does not meet", func() {
+					BeforeEach(func() {
+						workload.Labels = map[string]string{
+							"myLabel": "myLabelsValue",
+						}
+						clusterSupplyChain.(*v1alpha1.ClusterSupplyChain).Spec.SelectorMatchFields = []v1alpha1.FieldSelectorRequirement{
+							{Key: "spec.image", Operator: v1alpha1.FieldSelectorOpExists},
+						}
+						clientObjects = []client.Object{workload}
+					})
+
+					It("returns an empty list of requests", func() {
+						Expect(result).To(BeEmpty())
+					})
+				})
 				Context("supply chain without matching workload", func() {
 					BeforeEach(func() {
 						workload.Labels = map[string]string{
@@ -167,6 +215,82 @@ var _ = Describe("MapFunctions", func() {
 		})
 	})
 
+	Describe("SupplyChainToWorkloadRequests", func() {
+		var (
+			clientObjects []client.Object
+			mapper        *registrar.Mapper
+			fakeLogger    *registrarfakes.FakeLogger
+			supplyChain   client.Object
+			result        []reconcile.Request
+		)
+
+		BeforeEach(func() {
+			fakeLogger = &registrarfakes.FakeLogger{}
+
+			supplyChain = &v1alpha1.SupplyChain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "team-supply-chain",
+					Namespace: "team-ns",
+				},
+				Spec: v1alpha1.SupplyChainSpec{
+					Selector: map[string]string{
+						"myLabel": "myLabelsValue",
+					},
+				},
+			}
+
+			clientObjects = []client.Object{
+				&v1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "team-workload",
+						Namespace: "team-ns",
+						Labels:    map[string]string{"myLabel": "myLabelsValue"},
+					},
+				},
+				&v1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "other-workload",
+						Namespace: "other-ns",
+						Labels:    map[string]string{"myLabel": "myLabelsValue"},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+			mapper = &registrar.Mapper{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(clientObjects...).Build(),
+				Logger: fakeLogger,
+			}
+
+			result = mapper.SupplyChainToWorkloadRequests(supplyChain)
+		})
+
+		It("returns requests for the matching workloads in its namespace only", func() {
+			Expect(result).To(Equal([]reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "team-workload", Namespace: "team-ns"}},
+			}))
+		})
+
+		Context("when function is passed an object that is not a namespaced supply chain", func() {
+			BeforeEach(func() {
+				supplyChain = &v1alpha1.ClusterSupplyChain{}
+			})
+
+			It("logs a helpful error", func() {
+				Expect(result).To(BeEmpty())
+
+				Expect(fakeLogger.ErrorCallCount()).To(Equal(1))
+				firstArg, secondArg, _ := fakeLogger.ErrorArgsForCall(0)
+				Expect(firstArg).To(BeNil())
+				Expect(secondArg).To(Equal("supply chain to workload requests: cast to SupplyChain failed"))
+			})
+		})
+	})
+
 	Describe("RunTemplateToPipelineRequests", func() {
 		var (
 			clientObjects     []client.Object
@@ -187,6 +311,7 @@ var _ = Describe("MapFunctions", func() {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "match",
 					Namespace: "match",
+					Labels:    map[string]string{"app.tanzu.vmware.com/template": "match"},
 				},
 			}
 		})
@@ -254,7 +379,7 @@ var _ = Describe("MapFunctions", func() {
 						It("returns a list of requests with the pipeline present", func() {
 							expected := []reconcile.Request{
 								{
-									types.NamespacedName{
+									NamespacedName: types.NamespacedName{
 										Namespace: "my-namespace",
 										Name:      "my-pipeline",
 									},
@@ -277,7 +402,7 @@ var _ = Describe("MapFunctions", func() {
 						It("returns a list of requests with the pipeline present", func() {
 							expected := []reconcile.Request{
 								{
-									types.NamespacedName{
+									NamespacedName: types.NamespacedName{
 										Namespace: "match",
 										Name:      "my-pipeline",
 									},
@@ -287,8 +412,49 @@ var _ = Describe("MapFunctions", func() {
 							Expect(result).To(Equal(expected))
 						})
 					})
+
+					Context("with a templateRef that specifies a matching selector", func() {
+						BeforeEach(func() {
+							pipeline.Spec.RunTemplateRef = v1alpha1.TemplateReference{
+								Namespace: "match",
+								Selector: &metav1.LabelSelector{
+									MatchLabels: map[string]string{"app.tanzu.vmware.com/template": "match"},
+								},
+							}
+							clientObjects = []client.Object{pipeline}
+						})
+
+						It("returns a list of requests with the pipeline present", func() {
+							expected := []reconcile.Request{
+								{
+									NamespacedName: types.NamespacedName{
+										Namespace: "my-namespace",
+										Name:      "my-pipeline",
+									},
+								},
+							}
+
+							Expect(result).To(Equal(expected))
+						})
+					})
 				})
 				Context("no pipeline matches the runTemplate", func() {
+					Context("because the selector in the templateRef does not match the labels", func() {
+						BeforeEach(func() {
+							pipeline.Spec.RunTemplateRef = v1alpha1.TemplateReference{
+								Namespace: "match",
+								Selector: &metav1.LabelSelector{
+									MatchLabels: map[string]string{"app.tanzu.vmware.com/template": "other"},
+								},
+							}
+							clientObjects = []client.Object{pipeline}
+						})
+
+						It("returns an empty list of requests", func() {
+							Expect(result).To(BeEmpty())
+						})
+					})
+
 					Context("because the name in the templateRef is different", func() {
 						BeforeEach(func() {
 							pipeline.Spec.RunTemplateRef = v1alpha1.TemplateReference{
@@ -361,4 +527,90 @@ var _ = Describe("MapFunctions", func() {
 			})
 		})
 	})
+
+	Describe("WorkloadToWorkloadPreviewRequests", func() {
+		var (
+			clientObjects []client.Object
+			scheme        *runtime.Scheme
+			fakeLogger    *registrarfakes.FakeLogger
+			workload      client.Object
+			result        []reconcile.Request
+		)
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			fakeLogger = &registrarfakes.FakeLogger{}
+			clientObjects = nil
+
+			workload = &v1alpha1.Workload{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "petclinic",
+					Namespace: "dev",
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			mapper := &registrar.Mapper{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(clientObjects...).Build(),
+				Logger: fakeLogger,
+			}
+
+			result = mapper.WorkloadToWorkloadPreviewRequests(workload)
+		})
+
+		Context("client.List returns an error", func() {
+			It("logs an error to the client", func() {
+				Expect(result).To(BeEmpty())
+
+				Expect(fakeLogger.ErrorCallCount()).To(Equal(1))
+				firstArg, secondArg, _ := fakeLogger.ErrorArgsForCall(0)
+				Expect(firstArg).NotTo(BeNil())
+				Expect(secondArg).To(Equal("workload to workload preview requests: client list"))
+			})
+		})
+
+		Context("client does not return errors", func() {
+			BeforeEach(func() {
+				err := v1alpha1.AddToScheme(scheme)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("and there are previews", func() {
+				BeforeEach(func() {
+					preview := func(name, namespace, workload string) *v1alpha1.WorkloadPreview {
+						return &v1alpha1.WorkloadPreview{
+							ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+							Spec:       v1alpha1.WorkloadPreviewSpec{Workload: workload, PullRequest: 1},
+						}
+					}
+					clientObjects = []client.Object{
+						preview("petclinic-pr-1", "dev", "petclinic"),
+						preview("other-pr-1", "dev", "other"),
+						preview("petclinic-pr-2", "prod", "petclinic"),
+					}
+				})
+
+				It("returns requests for the previews of the workload in its namespace", func() {
+					Expect(result).To(Equal([]reconcile.Request{
+						{NamespacedName: types.NamespacedName{Namespace: "dev", Name: "petclinic-pr-1"}},
+					}))
+				})
+			})
+
+			Context("when function is passed an object that is not a workload", func() {
+				BeforeEach(func() {
+					workload = &v1alpha1.Pipeline{}
+				})
+				It("logs a helpful error", func() {
+					Expect(result).To(BeEmpty())
+
+					Expect(fakeLogger.ErrorCallCount()).To(Equal(1))
+					firstArg, secondArg, _ := fakeLogger.ErrorArgsForCall(0)
+					Expect(firstArg).To(BeNil())
+					Expect(secondArg).To(Equal("workload to workload preview requests: cast to Workload failed"))
+				})
+			})
+		})
+	})
 })
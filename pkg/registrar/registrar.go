@@ -19,9 +19,13 @@ package registrar
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/cache"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,15 +35,26 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha2"
+	"github.com/vmware-tanzu/cartographer/pkg/artifact"
 	"github.com/vmware-tanzu/cartographer/pkg/conditions"
 	"github.com/vmware-tanzu/cartographer/pkg/controller/pipeline"
+	"github.com/vmware-tanzu/cartographer/pkg/controller/realizationreport"
 	"github.com/vmware-tanzu/cartographer/pkg/controller/supplychain"
+	"github.com/vmware-tanzu/cartographer/pkg/controller/templateplayground"
 	"github.com/vmware-tanzu/cartographer/pkg/controller/workload"
+	"github.com/vmware-tanzu/cartographer/pkg/controller/workloadpreview"
+	"github.com/vmware-tanzu/cartographer/pkg/gitops"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/oci"
 	realizerpipeline "github.com/vmware-tanzu/cartographer/pkg/realizer/pipeline"
 	realizerworkload "github.com/vmware-tanzu/cartographer/pkg/realizer/workload"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
 
+const artifactRegistryTimeout = 10 * time.Second
+
 type Timer struct{}
 
 func (t Timer) Now() metav1.Time {
@@ -50,36 +65,113 @@ func AddToScheme(scheme *runtime.Scheme) error {
 	if err := v1alpha1.AddToScheme(scheme); err != nil {
 		return fmt.Errorf("cartographer v1alpha1 add to scheme: %w", err)
 	}
+	if err := v1alpha2.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("cartographer v1alpha2 add to scheme: %w", err)
+	}
+	if err := authorizationv1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("authorization v1 add to scheme: %w", err)
+	}
 
 	return nil
 }
 
-func RegisterControllers(mgr manager.Manager) error {
-	if err := registerWorkloadController(mgr); err != nil {
+// ResyncIntervals are how often the controllers reconcile an object again
+// when nothing about it changed. A zero interval keeps the controller's
+// default: workload.DefaultResyncInterval, supplychain.DefaultResyncInterval,
+// and no resync of pipelines.
+type ResyncIntervals struct {
+	Workload    time.Duration
+	SupplyChain time.Duration
+	Pipeline    time.Duration
+}
+
+// MaxConcurrentReconciles are how many objects each controller reconciles
+// at once. Zero keeps the default of one at a time.
+type MaxConcurrentReconciles struct {
+	Workload    int
+	SupplyChain int
+	Pipeline    int
+}
+
+// Options configure the controllers RegisterControllers registers.
+type Options struct {
+	Interceptor interceptor.Interceptor
+	// WatchedKinds are the kinds of stamped objects the workload and
+	// pipeline controllers watch; empty watches every kind.
+	WatchedKinds []schema.GroupKind
+	// CoalesceWindow is how long the reconciles caused by updates to an
+	// owner's stamped objects are coalesced for.
+	CoalesceWindow          time.Duration
+	ResyncIntervals         ResyncIntervals
+	MaxConcurrentReconciles MaxConcurrentReconciles
+	ClusterContext          templates.ClusterContext
+	// ImpersonateWorkloads writes stamped objects as the service account
+	// of their workload.
+	ImpersonateWorkloads bool
+	// ApplyOptions choose how stamped objects are applied.
+	ApplyOptions []repository.ApplyOption
+}
+
+// RegisterControllers registers cartographer's controllers with the
+// manager, as the opts configure.
+func RegisterControllers(mgr manager.Manager, opts Options) error {
+	if err := registerWorkloadController(mgr, opts); err != nil {
 		return fmt.Errorf("register workload controller: %w", err)
 	}
 
-	if err := registerSupplyChainController(mgr); err != nil {
+	if err := registerSupplyChainController(mgr, opts.ResyncIntervals.SupplyChain, opts.MaxConcurrentReconciles.SupplyChain); err != nil {
 		return fmt.Errorf("register supply-chain controller: %w", err)
 	}
 
-	if err := registerPipelineServiceController(mgr); err != nil {
+	if err := registerPipelineServiceController(mgr, opts); err != nil {
 		return fmt.Errorf("register pipeline-service controller: %w", err)
 	}
 
+	if err := registerWorkloadPreviewController(mgr); err != nil {
+		return fmt.Errorf("register workload-preview controller: %w", err)
+	}
+
+	if err := registerRealizationReportController(mgr); err != nil {
+		return fmt.Errorf("register realization-report controller: %w", err)
+	}
+
+	if err := registerTemplatePlaygroundController(mgr, opts.ClusterContext); err != nil {
+		return fmt.Errorf("register template-playground controller: %w", err)
+	}
+
 	return nil
 }
 
-func registerWorkloadController(mgr manager.Manager) error {
-	repo := repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring()))
-
+func registerWorkloadController(mgr manager.Manager, opts Options) error {
+	repo := repository.NewCachedRepository(mgr.GetClient(), mgr.GetAPIReader(), repository.NewCache(cache.NewExpiring()), opts.ApplyOptions...)
+
+	reconciler := workload.NewReconciler(repo, conditions.NewConditionManager, realizerworkload.NewRealizer(), opts.Interceptor, artifact.NewResolver(&http.Client{Timeout: artifactRegistryTimeout}), mgr.GetEventRecorderFor("workload"), opts.ClusterContext)
+	reconciler.SetImpersonator(repository.NewImpersonator(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()}))
+	reconciler.SetAccessMapper(mgr.GetRESTMapper())
+	reconciler.SetImpersonateWorkloadServiceAccounts(opts.ImpersonateWorkloads)
+	reconciler.SetGitWriter(gitops.NewWriter())
+	reconciler.SetPublisher(oci.NewPublisher(&http.Client{Timeout: artifactRegistryTimeout}))
+	if opts.ResyncIntervals.Workload > 0 {
+		reconciler.SetResyncInterval(opts.ResyncIntervals.Workload)
+	}
 	ctrl, err := pkgcontroller.New("workload", mgr, pkgcontroller.Options{
-		Reconciler: workload.NewReconciler(repo, conditions.NewConditionManager, realizerworkload.NewRealizer()),
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: opts.MaxConcurrentReconciles.Workload,
 	})
 	if err != nil {
 		return fmt.Errorf("controller new: %w", err)
 	}
 
+	reconciler.AddTracking(&pipeline.KindFilteredTracker{
+		Tracker: &pipeline.CoalescingTracker{
+			Tracker: &external.ObjectTracker{
+				Controller: ctrl,
+			},
+			Window: opts.CoalesceWindow,
+		},
+		Kinds: opts.WatchedKinds,
+	})
+
 	if err := ctrl.Watch(
 		&source.Kind{Type: &v1alpha1.Workload{}},
 		&handler.EnqueueRequestForObject{},
@@ -99,14 +191,26 @@ func registerWorkloadController(mgr manager.Manager) error {
 		return fmt.Errorf("watch: %w", err)
 	}
 
+	if err := ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.SupplyChain{}},
+		handler.EnqueueRequestsFromMapFunc(mapper.SupplyChainToWorkloadRequests),
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
 	return nil
 }
 
-func registerSupplyChainController(mgr manager.Manager) error {
+func registerSupplyChainController(mgr manager.Manager, resyncInterval time.Duration, maxConcurrentReconciles int) error {
 	repo := repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring()))
 
+	reconciler := supplychain.NewReconciler(repo, conditions.NewConditionManager)
+	if resyncInterval > 0 {
+		reconciler.SetResyncInterval(resyncInterval)
+	}
 	ctrl, err := pkgcontroller.New("supply-chain", mgr, pkgcontroller.Options{
-		Reconciler: supplychain.NewReconciler(repo, conditions.NewConditionManager),
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
 	})
 	if err != nil {
 		return fmt.Errorf("controller new: %w", err)
@@ -119,22 +223,37 @@ func registerSupplyChainController(mgr manager.Manager) error {
 		return fmt.Errorf("watch: %w", err)
 	}
 
+	if err := ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.SupplyChain{}},
+		&handler.EnqueueRequestForObject{},
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
 	return nil
 }
 
-func registerPipelineServiceController(mgr manager.Manager) error {
-	repo := repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring()))
+func registerPipelineServiceController(mgr manager.Manager, opts Options) error {
+	repo := repository.NewCachedRepository(mgr.GetClient(), mgr.GetAPIReader(), repository.NewCache(cache.NewExpiring()), opts.ApplyOptions...)
 
-	reconciler := pipeline.NewReconciler(repo, realizerpipeline.NewRealizer())
+	reconciler := pipeline.NewReconciler(repo, realizerpipeline.NewRealizer(opts.Interceptor, opts.ClusterContext), mgr.GetEventRecorderFor("pipeline"))
+	reconciler.SetResyncInterval(opts.ResyncIntervals.Pipeline)
 	ctrl, err := pkgcontroller.New("pipeline-service", mgr, pkgcontroller.Options{
-		Reconciler: reconciler,
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: opts.MaxConcurrentReconciles.Pipeline,
 	})
 	if err != nil {
 		return fmt.Errorf("controller new pipeline-service: %w", err)
 	}
 
-	reconciler.AddTracking(&external.ObjectTracker{
-		Controller: ctrl,
+	reconciler.AddTracking(&pipeline.KindFilteredTracker{
+		Tracker: &pipeline.CoalescingTracker{
+			Tracker: &external.ObjectTracker{
+				Controller: ctrl,
+			},
+			Window: opts.CoalesceWindow,
+		},
+		Kinds: opts.WatchedKinds,
 	})
 
 	if err := ctrl.Watch(
@@ -159,6 +278,85 @@ func registerPipelineServiceController(mgr manager.Manager) error {
 	return nil
 }
 
+func registerWorkloadPreviewController(mgr manager.Manager) error {
+	repo := repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring()))
+
+	ctrl, err := pkgcontroller.New("workload-preview", mgr, pkgcontroller.Options{
+		Reconciler: workloadpreview.NewReconciler(repo, conditions.NewConditionManager),
+	})
+	if err != nil {
+		return fmt.Errorf("controller new: %w", err)
+	}
+
+	if err := ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.WorkloadPreview{}},
+		&handler.EnqueueRequestForObject{},
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	if err := ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.Workload{}},
+		&handler.EnqueueRequestForOwner{OwnerType: &v1alpha1.WorkloadPreview{}, IsController: true},
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	mapper := Mapper{
+		Client: mgr.GetClient(),
+		Logger: mgr.GetLogger().WithName("workload-preview"),
+	}
+
+	if err := ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.Workload{}},
+		handler.EnqueueRequestsFromMapFunc(mapper.WorkloadToWorkloadPreviewRequests),
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	return nil
+}
+
+func registerRealizationReportController(mgr manager.Manager) error {
+	repo := repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring()))
+
+	ctrl, err := pkgcontroller.New("realization-report", mgr, pkgcontroller.Options{
+		Reconciler: realizationreport.NewReconciler(repo, Timer{}),
+	})
+	if err != nil {
+		return fmt.Errorf("controller new: %w", err)
+	}
+
+	if err := ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.RealizationReport{}},
+		&handler.EnqueueRequestForObject{},
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	return nil
+}
+
+func registerTemplatePlaygroundController(mgr manager.Manager, clusterContext templates.ClusterContext) error {
+	repo := repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring()))
+
+	ctrl, err := pkgcontroller.New("template-playground", mgr, pkgcontroller.Options{
+		Reconciler: templateplayground.NewReconciler(repo, conditions.NewConditionManager, clusterContext),
+	})
+	if err != nil {
+		return fmt.Errorf("controller new: %w", err)
+	}
+
+	if err := ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.TemplatePlayground{}},
+		&handler.EnqueueRequestForObject{},
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	return nil
+}
+
 func IndexResources(mgr manager.Manager, ctx context.Context) error {
 	fieldIndexer := mgr.GetFieldIndexer()
 
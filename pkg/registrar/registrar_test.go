@@ -53,12 +53,16 @@ var _ = Describe("Registrar", func() {
 				Expect(scheme.IsGroupRegistered("carto.run")).To(BeTrue())
 			})
 
+			It("registers the authorization group for access reviews", func() {
+				Expect(scheme.IsGroupRegistered("authorization.k8s.io")).To(BeTrue())
+			})
+
 			It("creates a scheme with expected length", func() {
 				gv := schema.GroupVersion{
 					Group:   "carto.run",
 					Version: "v1alpha1",
 				}
-				Expect(len(scheme.KnownTypes(gv))).To(Equal(23))
+				Expect(len(scheme.KnownTypes(gv))).To(Equal(41))
 				// If this test fails, it may indicate that new types should be added to the test below
 			})
 
@@ -72,11 +76,20 @@ var _ = Describe("Registrar", func() {
 					"ClusterConfigTemplate",
 					"ClusterImageTemplate",
 					"ClusterSourceTemplate",
+					"ClusterStampPolicy",
 					"ClusterSupplyChain",
 					"ClusterTemplate",
+					"ConfigTemplate",
+					"ImageTemplate",
 					"Pipeline",
+					"RealizationReport",
 					"RunTemplate",
+					"SourceTemplate",
+					"SupplyChain",
+					"Template",
+					"TemplatePlayground",
 					"Workload",
+					"WorkloadPreview",
 				}
 
 				for _, kind := range kinds {
@@ -84,6 +97,13 @@ var _ = Describe("Registrar", func() {
 					Expect(scheme.Recognizes(baseGVK)).To(BeTrue(), fmt.Sprintf("scheme should have kind: %s", kind))
 				}
 			})
+
+			It("adds the v1alpha2 objects to the scheme", func() {
+				for _, kind := range []string{"Pipeline", "Workload"} {
+					gvk := schema.GroupVersionKind{Group: "carto.run", Version: "v1alpha2", Kind: kind}
+					Expect(scheme.Recognizes(gvk)).To(BeTrue(), fmt.Sprintf("scheme should have kind: %s", kind))
+				}
+			})
 		})
 	})
 })
@@ -0,0 +1,70 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// reviewAccess asks the API server, with a SelfSubjectAccessReview made by
+// the client the object is written with, whether its identity may verb the
+// object, so that a missing permission is reported as a denied ObjectError
+// naming the verb and resource rather than as the rejection of a write.
+func (r *repository) reviewAccess(verb string, obj *unstructured.Unstructured, policy applyPolicy) error {
+	if policy.accessMapper == nil {
+		return nil
+	}
+
+	gvk := obj.GroupVersionKind()
+	mapping, err := policy.accessMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return newObjectError(verb, obj, err)
+	}
+
+	attributes := &authorizationv1.ResourceAttributes{
+		Namespace: obj.GetNamespace(),
+		Verb:      verb,
+		Group:     mapping.Resource.Group,
+		Version:   mapping.Resource.Version,
+		Resource:  mapping.Resource.Resource,
+	}
+	if verb != CreateVerb {
+		// the name of an object being created is not authorized: a
+		// permission limited to resourceNames never allows a create.
+		attributes.Name = obj.GetName()
+	}
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attributes},
+	}
+	if err := r.writer(policy).Create(context.TODO(), review); err != nil {
+		return newObjectError(verb, obj, fmt.Errorf("review access: %w", err))
+	}
+	if review.Status.Allowed {
+		return nil
+	}
+
+	denial := fmt.Sprintf("permission denied: cannot %s %s", verb, mapping.Resource.GroupResource())
+	if review.Status.Reason != "" {
+		denial = fmt.Sprintf("%s: %s", denial, review.Status.Reason)
+	}
+	objectError := newObjectError(verb, obj, errors.New(denial))
+	objectError.Denied = true
+	return objectError
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/cartographer/pkg/audit"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi"
+	"github.com/vmware-tanzu/cartographer/pkg/signature"
+)
+
+// FieldManager is the default field manager of the objects Cartographer
+// stamps.
+const FieldManager = "cartographer"
+
+// LastAppliedAnnotation records the configuration an object was last
+// stamped with, when objects are updated with a three-way merge.
+const LastAppliedAnnotation = "carto.run/last-applied-configuration"
+
+type applyPolicy struct {
+	fieldManager   string
+	forceConflicts bool
+	threeWayMerge  bool
+	dryRun         bool
+	validator      openapi.Validator
+	auditor        audit.Auditor
+	writer         client.Writer
+	accessMapper   meta.RESTMapper
+	verifier       signature.Verifier
+}
+
+// ApplyOption changes how stamped objects are applied: by default, with
+// server-side apply under the FieldManager, taking over the fields other
+// managers own.
+type ApplyOption func(*applyPolicy)
+
+// WithFieldManager applies objects under another field manager, so that
+// instances of Cartographer, or Cartographer and other controllers, can
+// tell their fields apart.
+func WithFieldManager(fieldManager string) ApplyOption {
+	return func(p *applyPolicy) {
+		if fieldManager != "" {
+			p.fieldManager = fieldManager
+		}
+	}
+}
+
+// WithForceConflicts chooses whether to take over the fields other field
+// managers own. When false, applying an object that sets such a field fails
+// with a conflict.
+func WithForceConflicts(force bool) ApplyOption {
+	return func(p *applyPolicy) {
+		p.forceConflicts = force
+	}
+}
+
+// WithThreeWayMerge updates objects with a three-way merge of the
+// configuration they were last stamped with, the one they are stamped with
+// now, and the object on the cluster, for clusters where server-side apply
+// is not desired. Fields the last configuration did not set, such as those
+// added by mutating webhooks or other operators, are kept. The last
+// configuration is recorded in the LastAppliedAnnotation.
+func WithThreeWayMerge() ApplyOption {
+	return func(p *applyPolicy) {
+		p.threeWayMerge = true
+	}
+}
+
+// WithDryRun submits each object with dryRun=All before writing it, so that
+// an object the API server rejects fails with an ObjectError that reports
+// DryRun, and nothing of it is written.
+func WithDryRun() ApplyOption {
+	return func(p *applyPolicy) {
+		p.dryRun = true
+	}
+}
+
+// WithValidator validates each object against the schema of its kind before
+// submitting it, so that an object that does not conform fails with an
+// ObjectError of the ValidateVerb, and nothing of it is written. An object
+// whose schema cannot be fetched is left for the API server to validate.
+func WithValidator(validator openapi.Validator) ApplyOption {
+	return func(p *applyPolicy) {
+		p.validator = validator
+	}
+}
+
+// WithAuditor tells the auditor of every object that is written, and of the
+// object it replaced, if any. Dry runs, and objects unchanged since they
+// were last written, are not audited.
+func WithAuditor(auditor audit.Auditor) ApplyOption {
+	return func(p *applyPolicy) {
+		p.auditor = auditor
+	}
+}
+
+// WithWriter writes stamped objects with the writer, such as a client
+// impersonating a service account, rather than the repository's own client.
+// The objects are still read with the repository's client.
+func WithWriter(writer client.Writer) ApplyOption {
+	return func(p *applyPolicy) {
+		p.writer = writer
+	}
+}
+
+// WithAccessReview asks the API server, with a SelfSubjectAccessReview,
+// whether the identity objects are written as may create or patch each
+// object before submitting it, so that a missing permission fails with an
+// ObjectError that reports Denied, and nothing of the object is written. The
+// mapper resolves the resource of each kind.
+func WithAccessReview(mapper meta.RESTMapper) ApplyOption {
+	return func(p *applyPolicy) {
+		p.accessMapper = mapper
+	}
+}
+
+// WithTemplateVerifier verifies the signature of every template before it
+// is used, so that a template the verifier does not trust fails to be
+// fetched, and nothing is stamped from it.
+func WithTemplateVerifier(verifier signature.Verifier) ApplyOption {
+	return func(p *applyPolicy) {
+		p.verifier = verifier
+	}
+}
+
+func (p applyPolicy) with(opts []ApplyOption) applyPolicy {
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+func (p applyPolicy) validate(obj *unstructured.Unstructured) error {
+	if p.validator == nil {
+		return nil
+	}
+
+	var invalid openapi.ValidationError
+	if err := p.validator.Validate(obj); errors.As(err, &invalid) {
+		return newObjectError(ValidateVerb, obj, err)
+	}
+	return nil
+}
+
+func (p applyPolicy) verifyTemplate(template client.Object) error {
+	if p.verifier == nil {
+		return nil
+	}
+	if err := p.verifier.Verify(template); err != nil {
+		return fmt.Errorf("verify signature: %w", err)
+	}
+	return nil
+}
+
+func (p applyPolicy) audit(before, after *unstructured.Unstructured) {
+	if p.auditor != nil {
+		p.auditor.Audit(before, after)
+	}
+}
+
+func (p applyPolicy) patchOptions() []client.PatchOption {
+	opts := []client.PatchOption{client.FieldOwner(p.fieldManager)}
+	if p.forceConflicts {
+		opts = append(opts, client.ForceOwnership)
+	}
+	return opts
+}
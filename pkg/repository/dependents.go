@@ -0,0 +1,124 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/cartographer/pkg/identity"
+)
+
+// maxOwnerDepth bounds how many owners are walked up from a dependent to
+// the object it depends on, such as Pod, TaskRun, PipelineRun.
+const maxOwnerDepth = 4
+
+// ListDependents lists the objects of a kind in the namespace of owner that
+// carry its identity labels, as Tekton propagates the labels of a run to
+// the objects it creates, and are owned by it directly or through other
+// owners. They are listed through the API server when the repository has a
+// reader of it, so that no informer is started for kinds such as Pods. An
+// owner without identity labels has no dependents.
+func (r *repository) ListDependents(owner *unstructured.Unstructured, gvk schema.GroupVersionKind) (list []*unstructured.Unstructured, err error) {
+	defer observe(ListOperation, gvk, time.Now(), &err)
+
+	ownerLabels := owner.GetLabels()
+	uid, resourceName := ownerLabels[identity.OwnerUIDLabel], ownerLabels[identity.ResourceNameLabel]
+	if uid == "" || resourceName == "" {
+		return nil, nil
+	}
+
+	var reader client.Reader = r.cl
+	if r.apiReader != nil {
+		reader = r.apiReader
+	}
+
+	query := &unstructured.Unstructured{}
+	query.SetGroupVersionKind(gvk)
+	query.SetNamespace(owner.GetNamespace())
+	query.SetLabels(identity.OwnedBy(types.UID(uid), resourceName))
+	candidates, err := r.listUnstructured(reader, query)
+	if err != nil {
+		return nil, err
+	}
+
+	walker := ownerWalker{reader: reader, owner: owner.GetUID(), owned: map[types.UID]bool{}}
+	for _, candidate := range candidates {
+		owned, err := walker.isOwned(candidate, maxOwnerDepth)
+		if err != nil {
+			return nil, err
+		}
+		if owned {
+			list = append(list, candidate)
+		}
+	}
+	return list, nil
+}
+
+// ownerWalker tells whether objects are owned by the owner through their
+// owner references, remembering the owners it has read.
+type ownerWalker struct {
+	reader client.Reader
+	owner  types.UID
+	owned  map[types.UID]bool
+}
+
+func (w ownerWalker) isOwned(obj metav1.Object, depth int) (bool, error) {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == w.owner {
+			return true, nil
+		}
+		if depth <= 1 {
+			continue
+		}
+
+		owned, seen := w.owned[ref.UID]
+		if !seen {
+			intermediate := &unstructured.Unstructured{}
+			intermediate.SetAPIVersion(ref.APIVersion)
+			intermediate.SetKind(ref.Kind)
+			err := w.reader.Get(context.TODO(), client.ObjectKey{Namespace: obj.GetNamespace(), Name: ref.Name}, intermediate)
+			if api_errors.IsNotFound(err) {
+				w.owned[ref.UID] = false
+				continue
+			}
+			if err != nil {
+				return false, fmt.Errorf("get owner %s '%s': %w", ref.Kind, ref.Name, err)
+			}
+			if intermediate.GetUID() != ref.UID {
+				w.owned[ref.UID] = false
+				continue
+			}
+
+			owned, err = w.isOwned(intermediate, depth-1)
+			if err != nil {
+				return false, err
+			}
+			w.owned[ref.UID] = owned
+		}
+		if owned {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	ListVerb   = "list"
+	GetVerb    = "get"
+	CreateVerb = "create"
+	PatchVerb  = "patch"
+	// ValidateVerb is the verb of an object that does not conform to the
+	// schema of its kind, and so was not submitted.
+	ValidateVerb = "validate"
+)
+
+// ObjectError reports which verb the API server refused for a stamped
+// object, and against which kind and namespace, as the remedy for a missing
+// permission to list, create or patch differs. DryRun reports that the verb
+// was refused for a dry run, and Denied that an access review denied the
+// verb before it was attempted, so that nothing was written.
+type ObjectError struct {
+	Verb      string
+	GVK       schema.GroupVersionKind
+	Namespace string
+	Name      string
+	DryRun    bool
+	Denied    bool
+	Err       error
+}
+
+func newObjectError(verb string, obj *unstructured.Unstructured, err error) ObjectError {
+	objectError := ObjectError{
+		Verb:      verb,
+		GVK:       obj.GroupVersionKind(),
+		Namespace: obj.GetNamespace(),
+		Err:       err,
+	}
+	if verb != ListVerb {
+		objectError.Name = obj.GetName()
+	}
+	return objectError
+}
+
+func newDryRunError(verb string, obj *unstructured.Unstructured, err error) ObjectError {
+	objectError := newObjectError(verb, obj, err)
+	objectError.DryRun = true
+	return objectError
+}
+
+func (e ObjectError) Error() string {
+	target := e.GVK.GroupKind().String()
+	if e.Name != "" {
+		target = fmt.Sprintf("%s '%s'", target, e.Name)
+	}
+	if e.Namespace != "" {
+		target = fmt.Sprintf("%s in namespace '%s'", target, e.Namespace)
+	}
+	verb := e.Verb
+	if e.DryRun {
+		verb = "dry-run " + verb
+	}
+	return fmt.Sprintf("%s %s: %s", verb, target, e.Err.Error())
+}
+
+func (e ObjectError) Unwrap() error {
+	return e.Err
+}
+
+// Forbidden reports whether the verb was refused for lack of permission.
+func (e ObjectError) Forbidden() bool {
+	return api_errors.IsForbidden(e.Err)
+}
+
+// IsMissingAPI reports whether err is caused by a kind the API server does
+// not serve, e.g. a Tekton kind before Tekton is installed. Such an error
+// clears up once the CRD of the kind is installed.
+func IsMissingAPI(err error) bool {
+	var noKindMatch *meta.NoKindMatchError
+	var noResourceMatch *meta.NoResourceMatchError
+	return errors.As(err, &noKindMatch) || errors.As(err, &noResourceMatch)
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//counterfeiter:generate . Impersonator
+
+// Impersonator builds clients that act as a service account, so that the
+// objects stamped for a tenant are written with the tenant's permissions
+// rather than those of the controller.
+type Impersonator interface {
+	ServiceAccountClient(namespace, name string) (client.Client, error)
+}
+
+type impersonator struct {
+	config  *rest.Config
+	options client.Options
+
+	mu      sync.Mutex
+	clients map[types.NamespacedName]client.Client
+}
+
+// NewImpersonator builds clients from the config, impersonating a service
+// account, with the options. A client is built once per service account.
+func NewImpersonator(config *rest.Config, options client.Options) Impersonator {
+	return &impersonator{
+		config:  config,
+		options: options,
+		clients: map[types.NamespacedName]client.Client{},
+	}
+}
+
+func (i *impersonator) ServiceAccountClient(namespace, name string) (client.Client, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if c, ok := i.clients[key]; ok {
+		return c, nil
+	}
+
+	config := rest.CopyConfig(i.config)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: serviceaccount.MakeUsername(namespace, name),
+		Groups:   append(serviceaccount.MakeGroupNames(namespace), user.AllAuthenticated),
+	}
+	c, err := client.New(config, i.options)
+	if err != nil {
+		return nil, fmt.Errorf("new client for service account '%s/%s': %w", namespace, name, err)
+	}
+
+	i.clients[key] = c
+	return c, nil
+}
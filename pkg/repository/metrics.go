@@ -0,0 +1,95 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The operations of the repository that are instrumented.
+const (
+	GetTemplateOperation = "get_template"
+	EnsureOperation      = "ensure"
+	AdoptOperation       = "adopt"
+	ListOperation        = "list"
+)
+
+// SuccessOutcome is the outcome of an operation that did not fail. A failed
+// operation has the reason of the API server's error, such as NotFound or
+// Forbidden, as its outcome, or Error when there is none.
+const (
+	SuccessOutcome = "Success"
+	ErrorOutcome   = "Error"
+)
+
+var operationLabels = []string{"operation", "group", "version", "kind", "outcome"}
+
+// OperationsTotal counts the operations of the repository, by the kind of
+// object they were for and their outcome, so that the stamped kinds that
+// fail can be told apart.
+var OperationsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "cartographer_repository_operations_total",
+		Help: "Number of repository operations by kind of object and outcome",
+	},
+	operationLabels,
+)
+
+// OperationDurationSeconds observes how long the operations of the
+// repository took, by the kind of object they were for and their outcome,
+// so that the stamped kinds that are slow can be told apart.
+var OperationDurationSeconds = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "cartographer_repository_operation_duration_seconds",
+		Help:    "Time taken by repository operations by kind of object and outcome",
+		Buckets: prometheus.DefBuckets,
+	},
+	operationLabels,
+)
+
+func init() {
+	metrics.Registry.MustRegister(OperationsTotal, OperationDurationSeconds)
+}
+
+// observe records an operation that started at start and failed with *err,
+// if not nil. It is meant to be deferred, with err pointing to the named
+// error result of the operation.
+func observe(operation string, gvk schema.GroupVersionKind, start time.Time, err *error) {
+	labels := prometheus.Labels{
+		"operation": operation,
+		"group":     gvk.Group,
+		"version":   gvk.Version,
+		"kind":      gvk.Kind,
+		"outcome":   outcome(*err),
+	}
+	OperationsTotal.With(labels).Inc()
+	OperationDurationSeconds.With(labels).Observe(time.Since(start).Seconds())
+}
+
+func outcome(err error) string {
+	if err == nil {
+		return SuccessOutcome
+	}
+	if reason := api_errors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+	return ErrorOutcome
+}
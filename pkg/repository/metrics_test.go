@@ -0,0 +1,106 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+)
+
+var _ = Describe("repository metrics", func() {
+	var (
+		cl   *repositoryfakes.FakeClient
+		repo repository.Repository
+		obj  *unstructured.Unstructured
+	)
+
+	labels := func(operation, group, version, kind, outcome string) prometheus.Labels {
+		return prometheus.Labels{"operation": operation, "group": group, "version": version, "kind": kind, "outcome": outcome}
+	}
+
+	count := func(labels prometheus.Labels) float64 {
+		return testutil.ToFloat64(repository.OperationsTotal.With(labels))
+	}
+
+	observations := func(labels prometheus.Labels) uint64 {
+		metric := &dto.Metric{}
+		Expect(repository.OperationDurationSeconds.With(labels).(prometheus.Histogram).Write(metric)).To(Succeed())
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	BeforeEach(func() {
+		cl = &repositoryfakes.FakeClient{}
+		repo = repository.NewRepository(cl, &repositoryfakes.FakeRepoCache{})
+
+		obj = &unstructured.Unstructured{}
+		obj.SetAPIVersion("batch/v1")
+		obj.SetKind("Job")
+		obj.SetGenerateName("hello-")
+		obj.SetNamespace("default")
+	})
+
+	It("counts the objects ensured, by kind and outcome", func() {
+		succeeded := labels(repository.EnsureOperation, "batch", "v1", "Job", repository.SuccessOutcome)
+		before := count(succeeded)
+
+		Expect(repo.EnsureObjectExistsOnCluster(obj, false)).To(Succeed())
+
+		Expect(count(succeeded)).To(Equal(before + 1))
+	})
+
+	It("labels a failure with the reason of the API server's error", func() {
+		forbidden := labels(repository.ListOperation, "batch", "v1", "Job", "Forbidden")
+		before := count(forbidden)
+
+		cl.ListReturns(api_errors.NewForbidden(schema.GroupResource{Group: "batch", Resource: "jobs"}, "", errors.New("no rbac")))
+		_, err := repo.ListUnstructured(obj)
+		Expect(err).To(HaveOccurred())
+
+		Expect(count(forbidden)).To(Equal(before + 1))
+	})
+
+	It("labels a failure without a reason as an error", func() {
+		failed := labels(repository.EnsureOperation, "batch", "v1", "Job", repository.ErrorOutcome)
+		before := count(failed)
+
+		cl.CreateReturns(errors.New("some-error"))
+		Expect(repo.EnsureObjectExistsOnCluster(obj, false)).NotTo(Succeed())
+
+		Expect(count(failed)).To(Equal(before + 1))
+	})
+
+	It("observes how long getting a template took", func() {
+		notFound := labels(repository.GetTemplateOperation, "carto.run", "v1alpha1", "ClusterTemplate", "NotFound")
+		before := observations(notFound)
+
+		cl.GetReturns(api_errors.NewNotFound(schema.GroupResource{Group: "carto.run", Resource: "clustertemplates"}, "some-template"))
+		_, err := repo.GetClusterTemplate(v1alpha1.ClusterTemplateReference{Kind: "ClusterTemplate", Name: "some-template"})
+		Expect(err).To(HaveOccurred())
+
+		Expect(observations(notFound)).To(Equal(before + 1))
+	})
+})
@@ -16,14 +16,29 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"time"
 
 	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/audit"
+	"github.com/vmware-tanzu/cartographer/pkg/identity"
+	"github.com/vmware-tanzu/cartographer/pkg/logging"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 )
 
@@ -33,53 +48,254 @@ import (
 
 //counterfeiter:generate . Repository
 type Repository interface {
-	EnsureObjectExistsOnCluster(obj *unstructured.Unstructured, allowUpdate bool) error
+	EnsureObjectExistsOnCluster(obj *unstructured.Unstructured, allowUpdate bool, opts ...ApplyOption) error
+	AdoptObjectOnCluster(obj *unstructured.Unstructured, opts ...ApplyOption) error
 	GetClusterTemplate(reference v1alpha1.ClusterTemplateReference) (templates.Template, error)
+	GetTemplate(reference v1alpha1.ClusterTemplateReference, namespace string) (templates.Template, error)
 	GetRunTemplate(reference v1alpha1.TemplateReference) (templates.RunTemplate, error)
 	GetSupplyChainsForWorkload(workload *v1alpha1.Workload) ([]v1alpha1.ClusterSupplyChain, error)
+	GetDefaultSupplyChainsForWorkload(workload *v1alpha1.Workload) ([]v1alpha1.ClusterSupplyChain, error)
 	GetWorkload(name string, namespace string) (*v1alpha1.Workload, error)
+	GetWorkloadPreview(name string, namespace string) (*v1alpha1.WorkloadPreview, error)
+	GetTemplatePlayground(name string, namespace string) (*v1alpha1.TemplatePlayground, error)
+	GetRealizationReport(name string) (*v1alpha1.RealizationReport, error)
+	ListWorkloads() ([]v1alpha1.Workload, error)
+	ListSupplyChains() ([]v1alpha1.ClusterSupplyChain, error)
+	ListNamespacedSupplyChains() ([]v1alpha1.SupplyChain, error)
+	ListPipelines() ([]v1alpha1.Pipeline, error)
+	ListStampPolicies() ([]v1alpha1.ClusterStampPolicy, error)
 	GetSupplyChain(name string) (*v1alpha1.ClusterSupplyChain, error)
+	GetNamespacedSupplyChain(name string, namespace string) (*v1alpha1.SupplyChain, error)
 	StatusUpdate(object client.Object) error
+	Update(object client.Object) error
 	GetScheme() *runtime.Scheme
 	GetPipeline(name string, namespace string) (*v1alpha1.Pipeline, error)
+	GetSecretData(name string, namespace string) (map[string][]byte, error)
 	ListUnstructured(obj *unstructured.Unstructured) ([]*unstructured.Unstructured, error)
+	ListStampedObjects(gvk schema.GroupVersionKind, namespace string, owner types.UID, resourceName string) ([]*unstructured.Unstructured, error)
+	ListDependents(owner *unstructured.Unstructured, gvk schema.GroupVersionKind) ([]*unstructured.Unstructured, error)
+	Delete(obj *unstructured.Unstructured) error
+	IsNamespaced(gvk schema.GroupVersionKind) (bool, error)
 }
 
+var log = ctrllog.Log.WithName(logging.Repository)
+
 type repository struct {
-	rc RepoCache
-	cl client.Client
+	rc          RepoCache
+	cl          client.Client
+	apiReader   client.Reader
+	applyPolicy applyPolicy
+}
+
+// NewRepository applies stamped objects as the opts choose, unless the
+// opts of a single call choose otherwise.
+func NewRepository(client client.Client, repoCache RepoCache, opts ...ApplyOption) Repository {
+	return &repository{
+		rc:          repoCache,
+		cl:          client,
+		applyPolicy: applyPolicy{fieldManager: FieldManager, forceConflicts: true}.with(opts),
+	}
 }
 
-func NewRepository(client client.Client, repoCache RepoCache) Repository {
+// ListPageSize is how many objects are listed at a time.
+const ListPageSize = 500
+
+// conflictRetry bounds how often a write that conflicted with another write
+// to a stamped object is retried.
+var conflictRetry = retry.DefaultRetry
+
+// NewCachedRepository is NewRepository for a client that reads stamped
+// objects from informers. An informer may not have seen the object created
+// by the previous reconcile yet, so before creating an object with a
+// generated name the repository lists again through apiReader, rather than
+// creating a duplicate.
+func NewCachedRepository(client client.Client, apiReader client.Reader, repoCache RepoCache, opts ...ApplyOption) Repository {
 	return &repository{
-		rc: repoCache,
-		cl: client,
+		rc:          repoCache,
+		cl:          client,
+		apiReader:   apiReader,
+		applyPolicy: applyPolicy{fieldManager: FieldManager, forceConflicts: true}.with(opts),
 	}
 }
 
-func (r *repository) EnsureObjectExistsOnCluster(obj *unstructured.Unstructured, allowUpdate bool) error {
-	unstructuredList, err := r.ListUnstructured(obj)
+func (r *repository) EnsureObjectExistsOnCluster(obj *unstructured.Unstructured, allowUpdate bool, opts ...ApplyOption) (err error) {
+	defer observe(EnsureOperation, obj.GroupVersionKind(), time.Now(), &err)
+	return r.ensureObjectExistsOnCluster(obj, allowUpdate, opts)
+}
+
+// ensureObjectExistsOnCluster retries a write that conflicted with another
+// write to the object, such as a resourceVersion race, reading the live
+// object again and submitting the stamped object anew each time, up to the
+// bound of conflictRetry. A conflict over fields another field manager owns
+// is not retried, as it will not resolve itself.
+func (r *repository) ensureObjectExistsOnCluster(obj *unstructured.Unstructured, allowUpdate bool, opts []ApplyOption) error {
+	stamped := obj.DeepCopy()
+	reader := client.Reader(r.cl)
+	return retry.OnError(conflictRetry, isTransientConflict, func() error {
+		*obj = *stamped.DeepCopy()
+		err := r.ensureObjectExistsOnClusterOnce(obj, allowUpdate, opts, reader)
+		if r.apiReader != nil {
+			reader = r.apiReader
+		}
+		return err
+	})
+}
+
+// lookupQuery selects the objects the stamped object may already exist as.
+// The labels that change with its template are left out, so that an object
+// stamped from an earlier version of the template is updated rather than
+// created again.
+func lookupQuery(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	query := &unstructured.Unstructured{}
+	query.SetGroupVersionKind(obj.GroupVersionKind())
+	query.SetNamespace(obj.GetNamespace())
+	query.SetName(obj.GetName())
+	query.SetLabels(identity.LookupLabels(obj.GetLabels()))
+	return query
+}
+
+func isTransientConflict(err error) bool {
+	if !api_errors.IsConflict(err) {
+		return false
+	}
+
+	var status api_errors.APIStatus
+	if errors.As(err, &status) && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Type == metav1.CauseTypeFieldManagerConflict {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r *repository) ensureObjectExistsOnClusterOnce(obj *unstructured.Unstructured, allowUpdate bool, opts []ApplyOption, reader client.Reader) error {
+	query := lookupQuery(obj)
+	unstructuredList, err := r.listUnstructured(reader, query)
 	if err != nil {
 		return err
 	}
 
 	cacheHit := r.rc.UnchangedSinceCached(obj, unstructuredList)
+	if cacheHit == nil && obj.GetName() == "" && r.apiReader != nil {
+		unstructuredList, err = r.listUnstructured(r.apiReader, query)
+		if err != nil {
+			return err
+		}
+		cacheHit = r.rc.UnchangedSinceCached(obj, unstructuredList)
+	}
 	if cacheHit != nil {
+		log.V(2).Info("object unchanged since it was last submitted", objectValues(obj)...)
 		r.rc.Refresh(obj.DeepCopy())
 		*obj = *cacheHit
 		return nil
 	}
 
-	var outdatedObject *unstructured.Unstructured
-	if allowUpdate {
-		outdatedObject = getOutdatedUnstructuredByName(obj, unstructuredList)
+	policy := r.applyPolicy.with(opts)
+	if err := policy.validate(obj); err != nil {
+		return err
+	}
+	if !allowUpdate || obj.GetName() == "" {
+		return r.createUnstructured(obj, policy)
 	}
 
+	outdatedObject := getOutdatedUnstructuredByName(obj, unstructuredList)
 	if outdatedObject != nil {
-		return r.patchUnstructured(outdatedObject, obj)
-	} else {
-		return r.createUnstructured(obj)
+		logDrift(outdatedObject, obj)
+	}
+	if policy.threeWayMerge {
+		if outdatedObject == nil {
+			return r.createUnstructured(obj, policy)
+		}
+		return r.mergeUnstructured(outdatedObject, obj, policy)
+	}
+
+	if outdatedObject == nil {
+		if err := r.ensureNameIsFree(obj); err != nil {
+			return err
+		}
+	}
+	return r.applyUnstructured(outdatedObject, obj, policy)
+}
+
+// ensureNameIsFree fails as a create would when an object of the same name,
+// which was not stamped as this one, already exists: server-side apply would
+// silently take it over.
+func (r *repository) ensureNameIsFree(obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := r.cl.Get(context.TODO(), client.ObjectKeyFromObject(obj), existing)
+	if api_errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return newObjectError(GetVerb, obj, err)
+	}
+
+	return newObjectError(CreateVerb, obj, r.alreadyExists(obj))
+}
+
+// alreadyExists is the error a create of the object would fail with, naming
+// its resource when the client's REST mapper resolves it.
+func (r *repository) alreadyExists(obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	if mapper := r.cl.RESTMapper(); mapper != nil {
+		if mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			return api_errors.NewAlreadyExists(mapping.Resource.GroupResource(), obj.GetName())
+		}
+	}
+
+	err := api_errors.NewAlreadyExists(schema.GroupResource{}, obj.GetName())
+	err.ErrStatus.Message = fmt.Sprintf("%q already exists", obj.GetName())
+	return err
+}
+
+// AdoptObjectOnCluster ensures the object exists like
+// EnsureObjectExistsOnCluster, and takes over an existing object of the same
+// name that was not stamped, rather than failing to create the object. An
+// object controlled by another owner, or labelled as stamped for another
+// owner, is not adopted.
+func (r *repository) AdoptObjectOnCluster(obj *unstructured.Unstructured, opts ...ApplyOption) (err error) {
+	defer observe(AdoptOperation, obj.GroupVersionKind(), time.Now(), &err)
+	return r.adoptObjectOnCluster(obj, opts)
+}
+
+func (r *repository) adoptObjectOnCluster(obj *unstructured.Unstructured, opts []ApplyOption) error {
+	err := r.ensureObjectExistsOnCluster(obj, true, opts)
+	if !api_errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := r.cl.Get(context.TODO(), client.ObjectKeyFromObject(obj), existing); err != nil {
+		return newObjectError(GetVerb, obj, err)
+	}
+
+	if err := adoptable(existing, obj); err != nil {
+		return fmt.Errorf("adopt %s '%s': %w", existing.GetKind(), existing.GetName(), err)
+	}
+
+	policy := r.applyPolicy.with(opts)
+	if policy.threeWayMerge {
+		return r.mergeUnstructured(existing, obj, policy)
 	}
+	return r.applyUnstructured(existing, obj, policy)
+}
+
+func adoptable(existing *unstructured.Unstructured, obj *unstructured.Unstructured) error {
+	owner := metav1.GetControllerOf(obj)
+	if controller := metav1.GetControllerOf(existing); controller != nil && (owner == nil || controller.UID != owner.UID) {
+		return fmt.Errorf("controlled by %s '%s'", controller.Kind, controller.Name)
+	}
+
+	stampedFor := existing.GetLabels()[identity.OwnerUIDLabel]
+	if stampedFor != "" && stampedFor != obj.GetLabels()[identity.OwnerUIDLabel] {
+		return fmt.Errorf("stamped for owner uid '%s'", stampedFor)
+	}
+
+	return nil
 }
 
 func getOutdatedUnstructuredByName(target *unstructured.Unstructured, candidates []*unstructured.Unstructured) *unstructured.Unstructured {
@@ -91,28 +307,92 @@ func getOutdatedUnstructuredByName(target *unstructured.Unstructured, candidates
 	return nil
 }
 
-func (r *repository) ListUnstructured(obj *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
-	unstructuredList := &unstructured.UnstructuredList{}
-	unstructuredList.SetGroupVersionKind(obj.GroupVersionKind())
+func (r *repository) ListUnstructured(obj *unstructured.Unstructured) (list []*unstructured.Unstructured, err error) {
+	defer observe(ListOperation, obj.GroupVersionKind(), time.Now(), &err)
+	return r.listUnstructured(r.cl, obj)
+}
+
+// ListStampedObjects lists the objects of a kind stamped into a namespace
+// for the owner with the uid, for any of its resources when resourceName is
+// empty, by their identity labels.
+func (r *repository) ListStampedObjects(gvk schema.GroupVersionKind, namespace string, owner types.UID, resourceName string) (list []*unstructured.Unstructured, err error) {
+	defer observe(ListOperation, gvk, time.Now(), &err)
+
+	query := &unstructured.Unstructured{}
+	query.SetGroupVersionKind(gvk)
+	query.SetNamespace(namespace)
+	query.SetLabels(identity.OwnedBy(owner, resourceName))
+	return r.listUnstructured(r.cl, query)
+}
+
+// listUnstructured lists the objects with the labels of obj a page of
+// ListPageSize objects at a time, so that a namespace with many stamped
+// objects of the kind, such as the runs of a busy pipeline, is not read from
+// the API server in one response. A reader backed by informers must leave
+// the page size out, as an informer stops at it without returning a continue
+// token; the manager's client does (see registrar.NewInformerClient).
+func (r *repository) listUnstructured(reader client.Reader, obj *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	var pointersToUnstructureds []*unstructured.Unstructured
+
+	continueToken := ""
+	for {
+		unstructuredList := &unstructured.UnstructuredList{}
+		unstructuredList.SetGroupVersionKind(obj.GroupVersionKind())
+
+		opts := []client.ListOption{
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingLabels(obj.GetLabels()),
+			client.Limit(ListPageSize),
+		}
+		if continueToken != "" {
+			opts = append(opts, client.Continue(continueToken))
+		}
+		err := reader.List(context.TODO(), unstructuredList, opts...)
+		if err != nil {
+			return nil, newObjectError(ListVerb, obj, err)
+		}
 
-	opts := []client.ListOption{
-		client.InNamespace(obj.GetNamespace()),
-		client.MatchingLabels(obj.GetLabels()),
+		for i := range unstructuredList.Items {
+			pointersToUnstructureds = append(pointersToUnstructureds, &unstructuredList.Items[i])
+		}
+
+		continueToken = unstructuredList.GetContinue()
+		if continueToken == "" {
+			return pointersToUnstructureds, nil
+		}
 	}
-	err := r.cl.List(context.TODO(), unstructuredList, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("list: %w", err)
+}
+
+func (r *repository) Delete(obj *unstructured.Unstructured) error {
+	err := r.cl.Delete(context.TODO(), obj)
+	if err != nil && !api_errors.IsNotFound(err) {
+		return fmt.Errorf("delete: %w", err)
 	}
 
-	pointersToUnstructureds := make([]*unstructured.Unstructured, len(unstructuredList.Items))
+	log.V(1).Info("deleted object", objectValues(obj)...)
+	return nil
+}
 
-	for i, item := range unstructuredList.Items {
-		pointersToUnstructureds[i] = item.DeepCopy()
+// IsNamespaced tells whether objects of the kind live in a namespace,
+// rather than in the cluster scope.
+func (r *repository) IsNamespaced(gvk schema.GroupVersionKind) (bool, error) {
+	mapper := r.cl.RESTMapper()
+	if mapper == nil {
+		return false, fmt.Errorf("no REST mapper to resolve the scope of %s", gvk.Kind)
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, fmt.Errorf("rest mapping of %s: %w", gvk.Kind, err)
 	}
-	return pointersToUnstructureds, nil
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+func (r *repository) GetClusterTemplate(ref v1alpha1.ClusterTemplateReference) (template templates.Template, err error) {
+	defer observe(GetTemplateOperation, v1alpha1.SchemeGroupVersion.WithKind(ref.Kind), time.Now(), &err)
+	return r.getClusterTemplate(ref)
 }
 
-func (r *repository) GetClusterTemplate(ref v1alpha1.ClusterTemplateReference) (templates.Template, error) {
+func (r *repository) getClusterTemplate(ref v1alpha1.ClusterTemplateReference) (templates.Template, error) {
 	apiTemplate, err := v1alpha1.GetAPITemplate(ref.Kind)
 	if err != nil {
 		return nil, fmt.Errorf("get api template: %w", err)
@@ -124,6 +404,9 @@ func (r *repository) GetClusterTemplate(ref v1alpha1.ClusterTemplateReference) (
 	if err != nil {
 		return nil, fmt.Errorf("get: %w", err)
 	}
+	if err := r.applyPolicy.verifyTemplate(apiTemplate); err != nil {
+		return nil, err
+	}
 
 	template, err := templates.NewModelFromAPI(apiTemplate)
 	if err != nil {
@@ -133,7 +416,51 @@ func (r *repository) GetClusterTemplate(ref v1alpha1.ClusterTemplateReference) (
 	return template, nil
 }
 
-func (r *repository) GetRunTemplate(ref v1alpha1.TemplateReference) (templates.RunTemplate, error) {
+// GetTemplate gets the namespaced counterpart of the referenced template in
+// the namespace, falling back to the cluster-scoped template when there is
+// none, or when the namespaced kind is not installed.
+func (r *repository) GetTemplate(ref v1alpha1.ClusterTemplateReference, namespace string) (template templates.Template, err error) {
+	defer observe(GetTemplateOperation, v1alpha1.SchemeGroupVersion.WithKind(ref.Kind), time.Now(), &err)
+	return r.getTemplate(ref, namespace)
+}
+
+func (r *repository) getTemplate(ref v1alpha1.ClusterTemplateReference, namespace string) (templates.Template, error) {
+	apiTemplate, err := v1alpha1.GetNamespacedAPITemplate(ref.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("get api template: %w", err)
+	}
+
+	err = r.cl.Get(context.TODO(), client.ObjectKey{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}, apiTemplate)
+	if api_errors.IsNotFound(err) || IsMissingAPI(err) {
+		return r.getClusterTemplate(ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get: %w", err)
+	}
+	if err := r.applyPolicy.verifyTemplate(apiTemplate); err != nil {
+		return nil, err
+	}
+
+	template, err := templates.NewModelFromAPI(apiTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("new model from api: %w", err)
+	}
+
+	return template, nil
+}
+
+func (r *repository) GetRunTemplate(ref v1alpha1.TemplateReference) (template templates.RunTemplate, err error) {
+	defer observe(GetTemplateOperation, v1alpha1.SchemeGroupVersion.WithKind("RunTemplate"), time.Now(), &err)
+	return r.getRunTemplate(ref)
+}
+
+func (r *repository) getRunTemplate(ref v1alpha1.TemplateReference) (templates.RunTemplate, error) {
+	if ref.Selector != nil {
+		return r.selectRunTemplate(ref)
+	}
 
 	runTemplate := &v1alpha1.RunTemplate{}
 
@@ -144,45 +471,225 @@ func (r *repository) GetRunTemplate(ref v1alpha1.TemplateReference) (templates.R
 	if err != nil {
 		return nil, fmt.Errorf("get: %w", err)
 	}
+	if err := r.applyPolicy.verifyTemplate(runTemplate); err != nil {
+		return nil, err
+	}
+
+	return templates.NewRunTemplateModel(runTemplate), nil
+}
 
-	template := templates.NewRunTemplateModel(runTemplate)
+func (r *repository) selectRunTemplate(ref v1alpha1.TemplateReference) (templates.RunTemplate, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
 	if err != nil {
-		return nil, fmt.Errorf("new model from api: %w", err)
+		return nil, fmt.Errorf("selector: %w", err)
 	}
 
-	return template, nil
+	list := &v1alpha1.RunTemplateList{}
+	err = r.cl.List(context.TODO(), list,
+		client.InNamespace(ref.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list: %w", err)
+	}
+
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("select: %w", api_errors.NewNotFound(v1alpha1.SchemeGroupVersion.WithResource("runtemplates").GroupResource(), selector.String()))
+	}
+
+	var (
+		selected        *v1alpha1.RunTemplate
+		selectedVersion *version.Version
+		ambiguous       bool
+	)
+	for i := range list.Items {
+		candidate := &list.Items[i]
+		candidateVersion, _ := version.ParseGeneric(candidate.Annotations[v1alpha1.TemplateVersionAnnotation])
+
+		switch {
+		case selected == nil:
+			selected, selectedVersion = candidate, candidateVersion
+		case candidateVersion == nil && selectedVersion == nil:
+			ambiguous = true
+		case selectedVersion == nil || (candidateVersion != nil && selectedVersion.LessThan(candidateVersion)):
+			selected, selectedVersion, ambiguous = candidate, candidateVersion, false
+		case candidateVersion != nil && !candidateVersion.LessThan(selectedVersion):
+			ambiguous = true
+		}
+	}
+
+	if ambiguous {
+		return nil, fmt.Errorf("select: more than one RunTemplate matches '%s' without a higher '%s'", selector.String(), v1alpha1.TemplateVersionAnnotation)
+	}
+
+	if err := r.applyPolicy.verifyTemplate(selected); err != nil {
+		return nil, err
+	}
+	return templates.NewRunTemplateModel(selected), nil
 }
 
-func (r *repository) createUnstructured(obj *unstructured.Unstructured) error {
+func (r *repository) createUnstructured(obj *unstructured.Unstructured, policy applyPolicy) error {
 	submitted := obj.DeepCopy()
-	if err := r.cl.Create(context.TODO(), obj); err != nil {
-		return fmt.Errorf("create: %w", err)
+	if policy.threeWayMerge {
+		if _, err := setLastApplied(obj); err != nil {
+			return newObjectError(CreateVerb, submitted, err)
+		}
+	}
+	if err := r.reviewAccess(CreateVerb, submitted, policy); err != nil {
+		return err
+	}
+	if policy.dryRun {
+		if err := r.writer(policy).Create(context.TODO(), obj.DeepCopy(), client.FieldOwner(policy.fieldManager), client.DryRunAll); err != nil {
+			return newDryRunError(CreateVerb, submitted, err)
+		}
+	}
+	if err := r.writer(policy).Create(context.TODO(), obj, client.FieldOwner(policy.fieldManager)); err != nil {
+		return newObjectError(CreateVerb, submitted, err)
 	}
 
 	r.rc.Set(submitted, obj.DeepCopy())
+	policy.audit(nil, obj)
+	log.V(1).Info("created object", objectValues(obj)...)
 	return nil
 }
 
-func (r *repository) patchUnstructured(existingObj *unstructured.Unstructured, obj *unstructured.Unstructured) error {
+// mergeUnstructured updates the existing object with a three-way merge of
+// the configuration it was last stamped with, the object as it is stamped
+// now, and the existing object, so that the fields others added to it are
+// kept. An object that was never stamped with a three-way merge is merged
+// as if it had been stamped with nothing, leaving every field it has that
+// the template does not set.
+func (r *repository) mergeUnstructured(existingObj *unstructured.Unstructured, obj *unstructured.Unstructured, policy applyPolicy) error {
 	submitted := obj.DeepCopy()
-	obj.SetResourceVersion(existingObj.GetResourceVersion())
-	if err := r.cl.Patch(context.TODO(), obj, client.MergeFrom(existingObj)); err != nil {
-		return fmt.Errorf("patch: %w", err)
+
+	original := []byte(existingObj.GetAnnotations()[LastAppliedAnnotation])
+	if len(original) == 0 {
+		original = []byte("{}")
+	}
+	modified, err := setLastApplied(obj)
+	if err != nil {
+		return newObjectError(PatchVerb, submitted, err)
+	}
+	current, err := existingObj.MarshalJSON()
+	if err != nil {
+		return newObjectError(PatchVerb, submitted, fmt.Errorf("marshal existing object: %w", err))
+	}
+
+	patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, current)
+	if err != nil {
+		return newObjectError(PatchVerb, submitted, fmt.Errorf("three-way merge: %w", err))
+	}
+	if err := r.reviewAccess(PatchVerb, submitted, policy); err != nil {
+		return err
+	}
+
+	if policy.dryRun {
+		if err := r.writer(policy).Patch(context.TODO(), obj.DeepCopy(), client.RawPatch(types.MergePatchType, patch), client.FieldOwner(policy.fieldManager), client.DryRunAll); err != nil {
+			return newDryRunError(PatchVerb, submitted, err)
+		}
+	}
+	if err := r.writer(policy).Patch(context.TODO(), obj, client.RawPatch(types.MergePatchType, patch), client.FieldOwner(policy.fieldManager)); err != nil {
+		return newObjectError(PatchVerb, submitted, err)
 	}
 
 	r.rc.Set(submitted, obj.DeepCopy())
+	policy.audit(existingObj, obj)
+	log.V(1).Info("merged object", objectValues(obj)...)
 	return nil
 }
 
+// setLastApplied records the configuration of the object in its
+// LastAppliedAnnotation, and returns the object as JSON.
+func setLastApplied(obj *unstructured.Unstructured) ([]byte, error) {
+	configuration := obj.DeepCopy()
+	annotations := configuration.GetAnnotations()
+	delete(annotations, LastAppliedAnnotation)
+	configuration.SetAnnotations(annotations)
+
+	lastApplied, err := configuration.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("marshal last applied configuration: %w", err)
+	}
+
+	annotations = obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastAppliedAnnotation] = string(lastApplied)
+	obj.SetAnnotations(annotations)
+
+	return obj.MarshalJSON()
+}
+
+// applyUnstructured creates or updates the object with server-side apply,
+// so that only the fields Cartographer stamps are managed by it, and fields
+// set by other controllers, such as the replicas set by an autoscaler, are
+// left as they are. existing is the object on the cluster, or nil when there
+// is none.
+func (r *repository) applyUnstructured(existing *unstructured.Unstructured, obj *unstructured.Unstructured, policy applyPolicy) error {
+	submitted := obj.DeepCopy()
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	if existing == nil {
+		// applying an object that does not exist creates it.
+		if err := r.reviewAccess(CreateVerb, submitted, policy); err != nil {
+			return err
+		}
+	}
+	if err := r.reviewAccess(PatchVerb, submitted, policy); err != nil {
+		return err
+	}
+	if policy.dryRun {
+		if err := r.writer(policy).Patch(context.TODO(), obj.DeepCopy(), client.Apply, append(policy.patchOptions(), client.DryRunAll)...); err != nil {
+			return newDryRunError(PatchVerb, submitted, err)
+		}
+	}
+	if err := r.writer(policy).Patch(context.TODO(), obj, client.Apply, policy.patchOptions()...); err != nil {
+		return newObjectError(PatchVerb, submitted, err)
+	}
+
+	r.rc.Set(submitted, obj.DeepCopy())
+	policy.audit(existing, obj)
+	log.V(1).Info("applied object", objectValues(obj)...)
+	return nil
+}
+
+// writer is the client stamped objects are written with: that of the
+// policy, if any, or the repository's own.
+func (r *repository) writer(policy applyPolicy) client.Writer {
+	if policy.writer != nil {
+		return policy.writer
+	}
+	return r.cl
+}
+
+// logDrift logs the fields of the stamped object that differ on the live
+// object it is about to update, to explain updates that keep happening, e.g.
+// because the API server defaults a field the template sets otherwise.
+func logDrift(live, stamped *unstructured.Unstructured) {
+	logger := log.V(2)
+	if !logger.Enabled() {
+		return
+	}
+	logger.Info("stamped object differs from live object", append(objectValues(stamped), "drift", audit.Drift(live, stamped))...)
+}
+
+func objectValues(obj *unstructured.Unstructured) []interface{} {
+	return []interface{}{"kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName()}
+}
+
 func (r *repository) GetSupplyChainsForWorkload(workload *v1alpha1.Workload) ([]v1alpha1.ClusterSupplyChain, error) {
-	list := &v1alpha1.ClusterSupplyChainList{}
-	if err := r.cl.List(context.TODO(), list); err != nil {
-		return nil, fmt.Errorf("list supply chains: %w", err)
+	supplyChains, err := r.listSupplyChainsForNamespace(workload.Namespace)
+	if err != nil {
+		return nil, err
 	}
 
 	var clusterSupplyChains []v1alpha1.ClusterSupplyChain
-	for _, supplyChain := range list.Items {
-		if supplyChainSelectorMatchesWorkloadLabels(supplyChain.Spec.Selector, workload.Labels) {
+	for _, supplyChain := range supplyChains {
+		if supplyChain.Spec.Default && supplyChain.Spec.SelectorSpecificity() == 0 {
+			continue
+		}
+		if matches, err := selector.SupplyChainMatchesWorkload(&supplyChain.Spec, workload); err == nil && matches {
 			clusterSupplyChains = append(clusterSupplyChains, supplyChain)
 		}
 	}
@@ -190,6 +697,51 @@ func (r *repository) GetSupplyChainsForWorkload(workload *v1alpha1.Workload) ([]
 	return clusterSupplyChains, nil
 }
 
+// GetDefaultSupplyChainsForWorkload returns the default supply chains of the
+// workload's namespace or, when there are none, the default
+// ClusterSupplyChains.
+func (r *repository) GetDefaultSupplyChainsForWorkload(workload *v1alpha1.Workload) ([]v1alpha1.ClusterSupplyChain, error) {
+	supplyChains, err := r.listSupplyChainsForNamespace(workload.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusterDefaults, namespacedDefaults []v1alpha1.ClusterSupplyChain
+	for _, supplyChain := range supplyChains {
+		switch {
+		case !supplyChain.Spec.Default:
+		case supplyChain.Namespace != "":
+			namespacedDefaults = append(namespacedDefaults, supplyChain)
+		default:
+			clusterDefaults = append(clusterDefaults, supplyChain)
+		}
+	}
+
+	if len(namespacedDefaults) > 0 {
+		return namespacedDefaults, nil
+	}
+	return clusterDefaults, nil
+}
+
+// listSupplyChainsForNamespace lists the ClusterSupplyChains, followed by
+// the SupplyChains of the namespace.
+func (r *repository) listSupplyChainsForNamespace(namespace string) ([]v1alpha1.ClusterSupplyChain, error) {
+	list := &v1alpha1.ClusterSupplyChainList{}
+	if err := r.cl.List(context.TODO(), list); err != nil {
+		return nil, fmt.Errorf("list supply chains: %w", err)
+	}
+
+	namespacedList := &v1alpha1.SupplyChainList{}
+	if err := r.cl.List(context.TODO(), namespacedList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("list namespaced supply chains: %w", err)
+	}
+	for i := range namespacedList.Items {
+		list.Items = append(list.Items, *namespacedList.Items[i].AsClusterSupplyChain())
+	}
+
+	return list.Items, nil
+}
+
 func (r *repository) GetWorkload(name string, namespace string) (*v1alpha1.Workload, error) {
 	workload := v1alpha1.Workload{}
 
@@ -207,6 +759,104 @@ func (r *repository) GetWorkload(name string, namespace string) (*v1alpha1.Workl
 	return &workload, nil
 }
 
+func (r *repository) GetWorkloadPreview(name string, namespace string) (*v1alpha1.WorkloadPreview, error) {
+	preview := &v1alpha1.WorkloadPreview{}
+
+	err := r.cl.Get(context.TODO(),
+		client.ObjectKey{
+			Name:      name,
+			Namespace: namespace,
+		},
+		preview,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get-workload-preview: %w", err)
+	}
+
+	return preview, nil
+}
+
+func (r *repository) GetTemplatePlayground(name string, namespace string) (*v1alpha1.TemplatePlayground, error) {
+	playground := &v1alpha1.TemplatePlayground{}
+
+	err := r.cl.Get(context.TODO(),
+		client.ObjectKey{
+			Name:      name,
+			Namespace: namespace,
+		},
+		playground,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get-template-playground: %w", err)
+	}
+
+	return playground, nil
+}
+
+func (r *repository) GetRealizationReport(name string) (*v1alpha1.RealizationReport, error) {
+	report := &v1alpha1.RealizationReport{}
+
+	err := r.cl.Get(context.TODO(),
+		client.ObjectKey{
+			Name: name,
+		},
+		report,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get-realization-report: %w", err)
+	}
+
+	return report, nil
+}
+
+func (r *repository) ListWorkloads() ([]v1alpha1.Workload, error) {
+	list := &v1alpha1.WorkloadList{}
+	if err := r.cl.List(context.TODO(), list); err != nil {
+		return nil, fmt.Errorf("list workloads: %w", err)
+	}
+
+	return list.Items, nil
+}
+
+func (r *repository) ListSupplyChains() ([]v1alpha1.ClusterSupplyChain, error) {
+	list := &v1alpha1.ClusterSupplyChainList{}
+	if err := r.cl.List(context.TODO(), list); err != nil {
+		return nil, fmt.Errorf("list supply chains: %w", err)
+	}
+
+	return list.Items, nil
+}
+
+// ListNamespacedSupplyChains lists the supply chains of every namespace.
+func (r *repository) ListNamespacedSupplyChains() ([]v1alpha1.SupplyChain, error) {
+	list := &v1alpha1.SupplyChainList{}
+	if err := r.cl.List(context.TODO(), list); err != nil {
+		return nil, fmt.Errorf("list namespaced supply chains: %w", err)
+	}
+
+	return list.Items, nil
+}
+
+func (r *repository) ListPipelines() ([]v1alpha1.Pipeline, error) {
+	list := &v1alpha1.PipelineList{}
+	if err := r.cl.List(context.TODO(), list); err != nil {
+		return nil, fmt.Errorf("list pipelines: %w", err)
+	}
+
+	return list.Items, nil
+}
+
+// ListStampPolicies lists the policies restricting the kinds templates may
+// stamp.
+func (r *repository) ListStampPolicies() ([]v1alpha1.ClusterStampPolicy, error) {
+	list := &v1alpha1.ClusterStampPolicyList{}
+	if err := r.cl.List(context.TODO(), list); err != nil {
+		return nil, fmt.Errorf("list stamp policies: %w", err)
+	}
+
+	return list.Items, nil
+}
+
 func (r *repository) GetPipeline(name string, namespace string) (*v1alpha1.Pipeline, error) {
 	pipeline := &v1alpha1.Pipeline{}
 
@@ -225,14 +875,35 @@ func (r *repository) GetPipeline(name string, namespace string) (*v1alpha1.Pipel
 	return pipeline, nil
 }
 
-func supplyChainSelectorMatchesWorkloadLabels(selector map[string]string, labels map[string]string) bool {
-	for key, value := range selector {
-		if labels[key] != value {
-			return false
-		}
+// GetSecretData reads the data of a Secret, through the API server when the
+// repository has a reader of it, so that secrets are not cached by informers.
+func (r *repository) GetSecretData(name string, namespace string) (map[string][]byte, error) {
+	var reader client.Reader = r.cl
+	if r.apiReader != nil {
+		reader = r.apiReader
 	}
 
-	return true
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	err := reader.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: namespace}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("get-secret: %w", err)
+	}
+
+	encoded, _, err := unstructured.NestedStringMap(secret.Object, "data")
+	if err != nil {
+		return nil, fmt.Errorf("get-secret: %w", err)
+	}
+	data := make(map[string][]byte, len(encoded))
+	for key, value := range encoded {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("get-secret: decode key '%s': %w", key, err)
+		}
+		data[key] = decoded
+	}
+	return data, nil
 }
 
 func (r *repository) GetSupplyChain(name string) (*v1alpha1.ClusterSupplyChain, error) {
@@ -255,10 +926,35 @@ func (r *repository) GetSupplyChain(name string) (*v1alpha1.ClusterSupplyChain,
 	return &supplyChain, nil
 }
 
+func (r *repository) GetNamespacedSupplyChain(name string, namespace string) (*v1alpha1.SupplyChain, error) {
+	supplyChain := v1alpha1.SupplyChain{}
+
+	err := r.cl.Get(context.TODO(),
+		client.ObjectKey{
+			Name:      name,
+			Namespace: namespace,
+		},
+		&supplyChain,
+	)
+	if err != nil && !api_errors.IsNotFound(err) {
+		return nil, fmt.Errorf("get: %w", err)
+	}
+
+	if api_errors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	return &supplyChain, nil
+}
+
 func (r *repository) StatusUpdate(object client.Object) error {
 	return r.cl.Status().Update(context.TODO(), object)
 }
 
+func (r *repository) Update(object client.Object) error {
+	return r.cl.Update(context.TODO(), object)
+}
+
 func (r *repository) GetScheme() *runtime.Scheme {
 	return r.cl.Scheme()
 }
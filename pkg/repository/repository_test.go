@@ -21,17 +21,25 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	v1 "k8s.io/api/core/v1"
+	api_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi/openapifakes"
 	"github.com/vmware-tanzu/cartographer/pkg/repository"
 	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/signature/signaturefakes"
 	"github.com/vmware-tanzu/cartographer/pkg/utils"
 )
 
@@ -76,6 +84,8 @@ spec:
 				dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
 				_, _, err := dec.Decode([]byte(stampedObjManifest), nil, stampedObj)
 				Expect(err).NotTo(HaveOccurred())
+
+				cl.GetReturns(api_errors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, "hello"))
 			})
 
 			It("attempts to get the object from the apiServer", func() {
@@ -86,6 +96,7 @@ spec:
 				listOptions := []client.ListOption{
 					client.InNamespace(stampedObj.GetNamespace()),
 					client.MatchingLabels(stampedObj.GetLabels()),
+					client.Limit(repository.ListPageSize),
 				}
 
 				_, objectList, options := cl.ListArgsForCall(0)
@@ -103,7 +114,18 @@ spec:
 
 				It("returns a helpful error", func() {
 					err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
-					Expect(err).To(MatchError(ContainSubstring("list: some-error")))
+					Expect(err).To(MatchError("list Job.batch in namespace 'default': some-error"))
+				})
+
+				It("reports the verb, kind and namespace that failed", func() {
+					err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
+
+					var objectErr repository.ObjectError
+					Expect(errors.As(err, &objectErr)).To(BeTrue())
+					Expect(objectErr.Verb).To(Equal(repository.ListVerb))
+					Expect(objectErr.GVK).To(Equal(stampedObj.GroupVersionKind()))
+					Expect(objectErr.Namespace).To(Equal("default"))
+					Expect(objectErr.Forbidden()).To(BeFalse())
 				})
 
 				It("does not create or patch any objects", func() {
@@ -122,22 +144,91 @@ spec:
 				BeforeEach(func() {
 					// default behavior is empty list - no need to stub
 				})
-				It("attempts to create the object", func() {
+				It("makes sure the name is not taken", func() {
 					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
 
-					Expect(cl.CreateCallCount()).To(Equal(1))
-					_, createCallObj, _ := cl.CreateArgsForCall(0)
-					Expect(createCallObj).To(Equal(stampedObj))
+					Expect(cl.GetCallCount()).To(Equal(1))
+					_, key, _ := cl.GetArgsForCall(0)
+					Expect(key).To(Equal(client.ObjectKey{Namespace: "default", Name: "hello"}))
+				})
+
+				It("creates the object with server-side apply", func() {
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+
+					Expect(cl.CreateCallCount()).To(Equal(0))
+					Expect(cl.PatchCallCount()).To(Equal(1))
+					_, patchCallObj, patch, opts := cl.PatchArgsForCall(0)
+					Expect(patchCallObj.GetName()).To(Equal("hello"))
+					Expect(patch).To(Equal(client.Apply))
+					Expect(opts).To(ConsistOf(client.FieldOwner("cartographer"), client.ForceOwnership))
+				})
+
+				It("applies the object as the options of the repository choose", func() {
+					repo = repository.NewRepository(cl, cache, repository.WithFieldManager("team-a"), repository.WithForceConflicts(false))
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+
+					_, _, _, opts := cl.PatchArgsForCall(0)
+					Expect(opts).To(ConsistOf(client.FieldOwner("team-a")))
+				})
+
+				It("applies the object as the options of the call choose, over those of the repository", func() {
+					repo = repository.NewRepository(cl, cache, repository.WithFieldManager("team-a"))
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true, repository.WithFieldManager("team-b"))).To(Succeed())
+
+					_, _, _, opts := cl.PatchArgsForCall(0)
+					Expect(opts).To(ConsistOf(client.FieldOwner("team-b"), client.ForceOwnership))
+				})
+
+				It("keeps the field manager when an option leaves it empty", func() {
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true, repository.WithFieldManager(""))).To(Succeed())
+
+					_, _, _, opts := cl.PatchArgsForCall(0)
+					Expect(opts).To(ConsistOf(client.FieldOwner("cartographer"), client.ForceOwnership))
+				})
+
+				Context("and an object of the same name that was not stamped exists", func() {
+					BeforeEach(func() {
+						cl.GetReturns(nil)
+					})
+
+					It("returns an error, as a create would", func() {
+						mapper := meta.NewDefaultRESTMapper(nil)
+						mapper.Add(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, meta.RESTScopeNamespace)
+						cl.RESTMapperReturns(mapper)
+
+						err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
+						Expect(err).To(MatchError(`create Job.batch 'hello' in namespace 'default': jobs.batch "hello" already exists`))
+						Expect(api_errors.IsAlreadyExists(err)).To(BeTrue())
+						Expect(cl.PatchCallCount()).To(Equal(0))
+					})
+
+					It("leaves the resource out of the error when it cannot be resolved", func() {
+						err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
+						Expect(err).To(MatchError(`create Job.batch 'hello' in namespace 'default': "hello" already exists`))
+						Expect(api_errors.IsAlreadyExists(err)).To(BeTrue())
+					})
+				})
+
+				Context("and the apiServer errors when looking up the name", func() {
+					BeforeEach(func() {
+						cl.GetReturns(errors.New("some-error"))
+					})
+
+					It("returns a helpful error", func() {
+						err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
+						Expect(err).To(MatchError("get Job.batch 'hello' in namespace 'default': some-error"))
+						Expect(cl.PatchCallCount()).To(Equal(0))
+					})
 				})
 
-				Context("and the apiServer errors when creating the object", func() {
+				Context("and the apiServer errors when applying the object", func() {
 					BeforeEach(func() {
-						cl.CreateReturns(errors.New("some-error"))
+						cl.PatchReturns(errors.New("some-error"))
 					})
 
 					It("returns a helpful error", func() {
 						err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
-						Expect(err).To(MatchError(ContainSubstring("create: some-error")))
+						Expect(err).To(MatchError("patch Job.batch 'hello' in namespace 'default': some-error"))
 					})
 
 					It("does not write to the submitted or persisted cache", func() {
@@ -151,7 +242,7 @@ spec:
 					BeforeEach(func() {
 						returnedCreatedObj = stampedObj.DeepCopy()
 						Expect(utils.AlterFieldOfNestedStringMaps(returnedCreatedObj.Object, "spec.template.spec.restartPolicy", "Never")).To(Succeed())
-						cl.CreateStub = func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+						cl.PatchStub = func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
 							objVal := reflect.ValueOf(obj)
 							returnVal := reflect.ValueOf(returnedCreatedObj)
 
@@ -250,9 +341,13 @@ spec:
 
 					Context("and allowUpdate is true", func() {
 						Context("list has exactly one object", func() {
-							It("patches the object", func() {
+							It("applies the object, without looking up its name", func() {
 								Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+								Expect(cl.GetCallCount()).To(Equal(0))
 								Expect(cl.PatchCallCount()).To(Equal(1))
+								_, _, patch, opts := cl.PatchArgsForCall(0)
+								Expect(patch).To(Equal(client.Apply))
+								Expect(opts).To(ConsistOf(client.FieldOwner("cartographer"), client.ForceOwnership))
 							})
 
 							Context("and the patch succeeds", func() {
@@ -291,7 +386,7 @@ spec:
 								})
 								It("returns a helpful error", func() {
 									err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
-									Expect(err).To(MatchError(ContainSubstring("patch: some-error")))
+									Expect(err).To(MatchError("patch Job.batch 'hello' in namespace 'default': some-error"))
 								})
 
 								It("does not write to the submitted or persisted cache", func() {
@@ -329,7 +424,8 @@ spec:
 								})
 								It("it creates", func() {
 									Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
-									Expect(cl.CreateCallCount()).To(Equal(1))
+									Expect(cl.GetCallCount()).To(Equal(1))
+									Expect(cl.PatchCallCount()).To(Equal(1))
 								})
 							})
 						})
@@ -340,6 +436,8 @@ spec:
 							Expect(repo.EnsureObjectExistsOnCluster(stampedObj, false)).To(Succeed())
 							Expect(cl.PatchCallCount()).To(Equal(0))
 							Expect(cl.CreateCallCount()).To(Equal(1))
+							_, _, opts := cl.CreateArgsForCall(0)
+							Expect(opts).To(ConsistOf(client.FieldOwner("cartographer")))
 						})
 
 						Context("and the create succeeds", func() {
@@ -378,7 +476,7 @@ spec:
 							})
 							It("returns a helpful error", func() {
 								err := repo.EnsureObjectExistsOnCluster(stampedObj, false)
-								Expect(err).To(MatchError(ContainSubstring("create: some-error")))
+								Expect(err).To(MatchError("create Job.batch 'hello' in namespace 'default': some-error"))
 							})
 
 							It("does not write to the submitted or persisted cache", func() {
@@ -403,6 +501,61 @@ spec:
 			})
 		})
 
+		Context("ListWorkloads", func() {
+			BeforeEach(func() {
+				cl.ListReturns(errors.New("some list error"))
+			})
+
+			It("attempts to list the workloads from the apiServer", func() {
+				_, err := repo.ListWorkloads()
+				Expect(err).To(MatchError("list workloads: some list error"))
+			})
+		})
+
+		Context("ListSupplyChains", func() {
+			BeforeEach(func() {
+				cl.ListReturns(errors.New("some list error"))
+			})
+
+			It("attempts to list the supply chains from the apiServer", func() {
+				_, err := repo.ListSupplyChains()
+				Expect(err).To(MatchError("list supply chains: some list error"))
+			})
+		})
+
+		Context("ListNamespacedSupplyChains", func() {
+			BeforeEach(func() {
+				cl.ListReturns(errors.New("some list error"))
+			})
+
+			It("attempts to list the namespaced supply chains from the apiServer", func() {
+				_, err := repo.ListNamespacedSupplyChains()
+				Expect(err).To(MatchError("list namespaced supply chains: some list error"))
+			})
+		})
+
+		Context("ListPipelines", func() {
+			BeforeEach(func() {
+				cl.ListReturns(errors.New("some list error"))
+			})
+
+			It("attempts to list the pipelines from the apiServer", func() {
+				_, err := repo.ListPipelines()
+				Expect(err).To(MatchError("list pipelines: some list error"))
+			})
+		})
+
+		Context("ListStampPolicies", func() {
+			BeforeEach(func() {
+				cl.ListReturns(errors.New("some list error"))
+			})
+
+			It("attempts to list the stamp policies from the apiServer", func() {
+				_, err := repo.ListStampPolicies()
+				Expect(err).To(MatchError("list stamp policies: some list error"))
+			})
+		})
+
 		Context("GetSupplyChain", func() {
 			BeforeEach(func() {
 				cl.GetReturns(errors.New("some get error"))
@@ -415,6 +568,47 @@ spec:
 			})
 		})
 
+		Context("Delete", func() {
+			var obj *unstructured.Unstructured
+
+			BeforeEach(func() {
+				obj = &unstructured.Unstructured{}
+				obj.SetAPIVersion("v1")
+				obj.SetKind("Pod")
+				obj.SetName("some-pod")
+				obj.SetNamespace("some-ns")
+			})
+
+			It("deletes the object from the apiServer", func() {
+				Expect(repo.Delete(obj)).To(Succeed())
+
+				Expect(cl.DeleteCallCount()).To(Equal(1))
+				_, deletedObj, _ := cl.DeleteArgsForCall(0)
+				Expect(deletedObj).To(Equal(obj))
+			})
+
+			Context("when the object no longer exists", func() {
+				BeforeEach(func() {
+					cl.DeleteReturns(api_errors.NewNotFound(schema.GroupResource{Resource: "pods"}, "some-pod"))
+				})
+
+				It("does not return an error", func() {
+					Expect(repo.Delete(obj)).To(Succeed())
+				})
+			})
+
+			Context("when the apiServer errors", func() {
+				BeforeEach(func() {
+					cl.DeleteReturns(errors.New("some delete error"))
+				})
+
+				It("returns a helpful error", func() {
+					err := repo.Delete(obj)
+					Expect(err).To(MatchError(ContainSubstring("delete: some delete error")))
+				})
+			})
+		})
+
 		Context("GetClusterTemplate", func() {
 			Context("when the template reference kind is not in our gvk", func() {
 				It("returns a helpful error", func() {
@@ -442,143 +636,1270 @@ spec:
 				})
 			})
 		})
-	})
 
-	Describe("tests using apiMachinery fake client", func() {
-		var (
-			scheme            *runtime.Scheme
-			fakeClientBuilder *fake.ClientBuilder
-			clientObjects     []client.Object
-			cl                client.Client
-		)
+		Context("EnsureObjectExistsOnCluster with a cached repository", func() {
+			var (
+				apiReader  *repositoryfakes.FakeClient
+				stampedObj *unstructured.Unstructured
+				created    *unstructured.Unstructured
+			)
 
-		BeforeEach(func() {
-			scheme = runtime.NewScheme()
-			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
-			Expect(v1.AddToScheme(scheme)).To(Succeed())
-		})
+			BeforeEach(func() {
+				apiReader = &repositoryfakes.FakeClient{}
+				repo = repository.NewCachedRepository(cl, apiReader, cache)
 
-		JustBeforeEach(func() {
-			fakeClientBuilder = fake.NewClientBuilder().WithScheme(scheme).WithObjects(clientObjects...)
-			cl = fakeClientBuilder.Build()
+				stampedObj = &unstructured.Unstructured{}
+				stampedObj.SetAPIVersion("batch/v1")
+				stampedObj.SetKind("Job")
+				stampedObj.SetGenerateName("hello-")
+				stampedObj.SetNamespace("default")
 
-			repo = repository.NewRepository(cl, cache)
-		})
+				created = stampedObj.DeepCopy()
+				created.SetName("hello-abcde")
 
-		Context("GetClusterTemplate", func() {
-			BeforeEach(func() {
-				template := &v1alpha1.ClusterSourceTemplate{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "some-name",
-					},
+				cache.UnchangedSinceCachedStub = func(_ *unstructured.Unstructured, existing []*unstructured.Unstructured) *unstructured.Unstructured {
+					if len(existing) > 0 {
+						return existing[0]
+					}
+					return nil
 				}
-				clientObjects = []client.Object{template}
 			})
 
-			It("gets the template successfully", func() {
-				templateRef := v1alpha1.ClusterTemplateReference{
-					Kind: "ClusterSourceTemplate",
-					Name: "some-name",
-				}
-				template, err := repo.GetClusterTemplate(templateRef)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(template.GetName()).To(Equal("some-name"))
-			})
-		})
+			Context("when the informers have not seen the object created before", func() {
+				BeforeEach(func() {
+					apiReader.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+						list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{*created}
+						return nil
+					}
+				})
 
-		Context("GetRunTemplate", func() {
-			BeforeEach(func() {
-				clientObjects = []client.Object{
-					&v1alpha1.RunTemplate{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      "first-template",
-							Namespace: "ns1",
-						},
-					},
-					&v1alpha1.RunTemplate{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      "second-template",
-							Namespace: "ns2",
-						},
-					}}
-			})
+				It("finds the object through the API server rather than creating another", func() {
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, false)).To(Succeed())
 
-			It("gets the template successfully", func() {
-				templateRef := v1alpha1.TemplateReference{
-					Kind:      "RunTemplate",
-					Name:      "second-template",
-					Namespace: "ns2",
-				}
-				template, err := repo.GetRunTemplate(templateRef)
-				Expect(err).ToNot(HaveOccurred())
-				Expect(template.GetName()).To(Equal("second-template"))
+					Expect(cl.ListCallCount()).To(Equal(1))
+					Expect(apiReader.ListCallCount()).To(Equal(1))
+					Expect(cl.CreateCallCount()).To(Equal(0))
+					Expect(stampedObj.GetName()).To(Equal("hello-abcde"))
+				})
 			})
 
-			It("finds nothing with a mismatched namespace", func() {
-				templateRef := v1alpha1.TemplateReference{
-					Kind:      "RunTemplate",
-					Name:      "second-template",
-					Namespace: "ns1",
-				}
-				_, err := repo.GetRunTemplate(templateRef)
-				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("not found"))
+			Context("when the API server has not seen the object either", func() {
+				It("creates the object", func() {
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, false)).To(Succeed())
+
+					Expect(apiReader.ListCallCount()).To(Equal(1))
+					Expect(cl.CreateCallCount()).To(Equal(1))
+				})
 			})
-		})
 
-		Context("GetWorkload", func() {
-			BeforeEach(func() {
-				workload := &v1alpha1.Workload{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "workload-name",
-						Namespace: "workload-namespace",
-					},
-				}
-				clientObjects = []client.Object{workload}
+			Context("when listing through the API server fails", func() {
+				BeforeEach(func() {
+					apiReader.ListReturns(errors.New("some-error"))
+				})
+
+				It("does not create the object", func() {
+					err := repo.EnsureObjectExistsOnCluster(stampedObj, false)
+					Expect(err).To(MatchError("list Job.batch in namespace 'default': some-error"))
+					Expect(cl.CreateCallCount()).To(Equal(0))
+				})
 			})
 
-			It("gets the workload successfully", func() {
-				workload, err := repo.GetWorkload("workload-name", "workload-namespace")
-				Expect(err).ToNot(HaveOccurred())
-				Expect(workload.GetName()).To(Equal("workload-name"))
+			Context("when the informers have seen the object", func() {
+				BeforeEach(func() {
+					cl.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+						list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{*created}
+						return nil
+					}
+				})
+
+				It("does not list through the API server", func() {
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, false)).To(Succeed())
+
+					Expect(apiReader.ListCallCount()).To(Equal(0))
+					Expect(cl.CreateCallCount()).To(Equal(0))
+				})
 			})
 
-			Context("workload doesnt exist", func() {
-				It("returns an error", func() {
-					_, err := repo.GetWorkload("workload-that-does-not-exist-name", "workload-namespace")
-					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(ContainSubstring("get:"))
+			Context("when the object is named", func() {
+				BeforeEach(func() {
+					stampedObj.SetName("hello")
+					cl.GetReturns(api_errors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, "hello"))
+				})
+
+				It("reads only from the informers", func() {
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+
+					Expect(apiReader.ListCallCount()).To(Equal(0))
+					Expect(cl.PatchCallCount()).To(Equal(1))
+				})
+
+				It("reads the live object through the API server before retrying a conflicted write", func() {
+					cl.PatchReturnsOnCall(0, api_errors.NewConflict(schema.GroupResource{Group: "batch", Resource: "jobs"}, "hello", errors.New("the object has been modified")))
+
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+
+					Expect(cl.ListCallCount()).To(Equal(1))
+					Expect(apiReader.ListCallCount()).To(Equal(1))
+					Expect(cl.PatchCallCount()).To(Equal(2))
 				})
 			})
+
+			It("lists dependents through the API server, by the identity labels of their owner", func() {
+				run := &unstructured.Unstructured{}
+				run.SetNamespace("default")
+				run.SetLabels(map[string]string{"carto.run/owner-uid": "pipeline-uid", "carto.run/resource-name": "run"})
+
+				_, err := repo.ListDependents(run, schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(cl.ListCallCount()).To(Equal(0))
+				Expect(apiReader.ListCallCount()).To(Equal(1))
+				_, _, opts := apiReader.ListArgsForCall(0)
+				Expect(opts).To(ContainElements(
+					client.InNamespace("default"),
+					client.MatchingLabels{"carto.run/owner-uid": "pipeline-uid", "carto.run/resource-name": "run"},
+				))
+			})
 		})
 
-		Context("GetPipeline", func() {
+		Context("EnsureObjectExistsOnCluster with a dry run first", func() {
+			var stampedObj *unstructured.Unstructured
+
 			BeforeEach(func() {
-				pipeline := &v1alpha1.Pipeline{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "pipeline-name",
-						Namespace: "pipeline-namespace",
-					},
-				}
-				clientObjects = []client.Object{pipeline}
+				repo = repository.NewRepository(cl, cache, repository.WithDryRun())
+
+				stampedObj = &unstructured.Unstructured{}
+				stampedObj.SetAPIVersion("batch/v1")
+				stampedObj.SetKind("Job")
+				stampedObj.SetName("hello")
+				stampedObj.SetNamespace("default")
+
+				cl.GetReturns(api_errors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, "hello"))
 			})
 
-			It("gets the pipeline successfully", func() {
-				pipeline, err := repo.GetPipeline("pipeline-name", "pipeline-namespace")
-				Expect(err).ToNot(HaveOccurred())
-				Expect(pipeline.GetName()).To(Equal("pipeline-name"))
+			It("applies the object with a dry run, then for real", func() {
+				Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+
+				Expect(cl.PatchCallCount()).To(Equal(2))
+				_, _, _, dryRunOptions := cl.PatchArgsForCall(0)
+				Expect(dryRunOptions).To(ContainElement(client.DryRunAll))
+				_, _, _, options := cl.PatchArgsForCall(1)
+				Expect(options).NotTo(ContainElement(client.DryRunAll))
 			})
 
-			Context("pipeline doesnt exist", func() {
-				It("returns an error", func() {
-					_, err := repo.GetPipeline("pipeline-that-does-not-exist-name", "pipeline-namespace")
-					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(ContainSubstring("get-pipeline:"))
-				})
+			It("creates an object with a generated name with a dry run, then for real", func() {
+				stampedObj.SetName("")
+				stampedObj.SetGenerateName("hello-")
+
+				Expect(repo.EnsureObjectExistsOnCluster(stampedObj, false)).To(Succeed())
+
+				Expect(cl.CreateCallCount()).To(Equal(2))
+				_, _, dryRunOptions := cl.CreateArgsForCall(0)
+				Expect(dryRunOptions).To(ContainElement(client.DryRunAll))
 			})
-		})
 
-		Context("GetSupplyChain", func() {
+			Context("when the dry run is rejected", func() {
+				BeforeEach(func() {
+					cl.PatchReturnsOnCall(0, api_errors.NewBadRequest("spec.foo: Invalid value"))
+				})
+
+				It("does not write the object", func() {
+					_ = repo.EnsureObjectExistsOnCluster(stampedObj, true)
+					Expect(cl.PatchCallCount()).To(Equal(1))
+					Expect(cache.SetCallCount()).To(Equal(0))
+				})
+
+				It("reports the rejection of the dry run", func() {
+					err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
+					Expect(err).To(MatchError("dry-run patch Job.batch 'hello' in namespace 'default': spec.foo: Invalid value"))
+
+					var objectErr repository.ObjectError
+					Expect(errors.As(err, &objectErr)).To(BeTrue())
+					Expect(objectErr.DryRun).To(BeTrue())
+				})
+			})
+		})
+
+		Context("EnsureObjectExistsOnCluster with an access review first", func() {
+			var (
+				stampedObj *unstructured.Unstructured
+				reviews    []*authorizationv1.SelfSubjectAccessReview
+				allowed    bool
+			)
+
+			BeforeEach(func() {
+				mapper := meta.NewDefaultRESTMapper(nil)
+				mapper.Add(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, meta.RESTScopeNamespace)
+				repo = repository.NewRepository(cl, cache, repository.WithAccessReview(mapper))
+
+				stampedObj = &unstructured.Unstructured{}
+				stampedObj.SetAPIVersion("batch/v1")
+				stampedObj.SetKind("Job")
+				stampedObj.SetName("hello")
+				stampedObj.SetNamespace("default")
+
+				cl.GetReturns(api_errors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, "hello"))
+
+				reviews = nil
+				allowed = true
+				cl.CreateStub = func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+					if review, ok := obj.(*authorizationv1.SelfSubjectAccessReview); ok {
+						review.Status.Allowed = allowed
+						reviews = append(reviews, review)
+					}
+					return nil
+				}
+			})
+
+			It("reviews the verbs of the write before applying the object", func() {
+				Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+
+				Expect(reviews).To(HaveLen(2))
+				Expect(*reviews[0].Spec.ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{
+					Namespace: "default",
+					Verb:      "create",
+					Group:     "batch",
+					Version:   "v1",
+					Resource:  "jobs",
+				}))
+				Expect(reviews[1].Spec.ResourceAttributes.Verb).To(Equal("patch"))
+				Expect(reviews[1].Spec.ResourceAttributes.Name).To(Equal("hello"))
+				Expect(cl.PatchCallCount()).To(Equal(1))
+			})
+
+			Context("when the access review denies the write", func() {
+				BeforeEach(func() {
+					allowed = false
+				})
+
+				It("does not write the object", func() {
+					_ = repo.EnsureObjectExistsOnCluster(stampedObj, true)
+					Expect(cl.PatchCallCount()).To(Equal(0))
+					Expect(cache.SetCallCount()).To(Equal(0))
+				})
+
+				It("reports the verb and resource denied", func() {
+					err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
+					Expect(err).To(MatchError("create Job.batch 'hello' in namespace 'default': permission denied: cannot create jobs.batch"))
+
+					var objectErr repository.ObjectError
+					Expect(errors.As(err, &objectErr)).To(BeTrue())
+					Expect(objectErr.Denied).To(BeTrue())
+				})
+			})
+		})
+
+		Context("ListUnstructured", func() {
+			var query *unstructured.Unstructured
+
+			BeforeEach(func() {
+				query = &unstructured.Unstructured{}
+				query.SetAPIVersion("tekton.dev/v1beta1")
+				query.SetKind("TaskRun")
+				query.SetNamespace("default")
+				query.SetLabels(map[string]string{"carto.run/pipeline-name": "my-pipeline"})
+
+				cl.ListStub = func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					listOptions := &client.ListOptions{}
+					listOptions.ApplyOptions(opts)
+
+					page := list.(*unstructured.UnstructuredList)
+					run := unstructured.Unstructured{}
+					switch listOptions.Continue {
+					case "":
+						run.SetName("run-1")
+						page.SetContinue("page-2")
+					case "page-2":
+						run.SetName("run-2")
+					}
+					page.Items = []unstructured.Unstructured{run}
+					return nil
+				}
+			})
+
+			It("lists the objects with the labels of the query a page at a time", func() {
+				objects, err := repo.ListUnstructured(query)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(objects).To(HaveLen(2))
+				Expect(objects[0].GetName()).To(Equal("run-1"))
+				Expect(objects[1].GetName()).To(Equal("run-2"))
+
+				Expect(cl.ListCallCount()).To(Equal(2))
+				_, _, firstPage := cl.ListArgsForCall(0)
+				Expect(firstPage).To(ConsistOf(
+					client.InNamespace("default"),
+					client.MatchingLabels{"carto.run/pipeline-name": "my-pipeline"},
+					client.Limit(repository.ListPageSize),
+				))
+				_, _, secondPage := cl.ListArgsForCall(1)
+				Expect(secondPage).To(ContainElement(client.Continue("page-2")))
+			})
+
+			It("returns an error when a page cannot be listed", func() {
+				firstPage := cl.ListStub
+				cl.ListStub = func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+					listOptions := &client.ListOptions{}
+					listOptions.ApplyOptions(opts)
+					if listOptions.Continue != "" {
+						return errors.New("some-error")
+					}
+					return firstPage(ctx, list, opts...)
+				}
+
+				_, err := repo.ListUnstructured(query)
+				Expect(err).To(MatchError("list TaskRun.tekton.dev in namespace 'default': some-error"))
+			})
+
+			It("lists the objects stamped for an owner by their identity labels", func() {
+				gvk := schema.GroupVersionKind{Group: "tekton.dev", Version: "v1beta1", Kind: "TaskRun"}
+				objects, err := repo.ListStampedObjects(gvk, "default", types.UID("owner-uid"), "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(objects).To(HaveLen(2))
+
+				_, list, firstPage := cl.ListArgsForCall(0)
+				Expect(list.GetObjectKind().GroupVersionKind()).To(Equal(gvk))
+				Expect(firstPage).To(ContainElements(
+					client.InNamespace("default"),
+					client.MatchingLabels{"carto.run/owner-uid": "owner-uid"},
+				))
+			})
+
+			It("lists the objects stamped for one resource of an owner", func() {
+				gvk := schema.GroupVersionKind{Group: "tekton.dev", Version: "v1beta1", Kind: "TaskRun"}
+				_, err := repo.ListStampedObjects(gvk, "default", types.UID("owner-uid"), "run")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, firstPage := cl.ListArgsForCall(0)
+				Expect(firstPage).To(ContainElement(
+					client.MatchingLabels{"carto.run/owner-uid": "owner-uid", "carto.run/resource-name": "run"},
+				))
+			})
+		})
+
+		Context("EnsureObjectExistsOnCluster when the write conflicts", func() {
+			var (
+				stampedObj *unstructured.Unstructured
+				conflict   error
+			)
+
+			BeforeEach(func() {
+				stampedObj = &unstructured.Unstructured{}
+				stampedObj.SetAPIVersion("batch/v1")
+				stampedObj.SetKind("Job")
+				stampedObj.SetName("hello")
+				stampedObj.SetNamespace("default")
+
+				cl.GetReturns(api_errors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, "hello"))
+				conflict = api_errors.NewConflict(schema.GroupResource{Group: "batch", Resource: "jobs"}, "hello", errors.New("the object has been modified"))
+			})
+
+			It("reads the object again and retries", func() {
+				cl.PatchReturnsOnCall(0, conflict)
+
+				Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+
+				Expect(cl.ListCallCount()).To(Equal(2))
+				Expect(cl.PatchCallCount()).To(Equal(2))
+				_, first, _, _ := cl.PatchArgsForCall(0)
+				_, second, _, _ := cl.PatchArgsForCall(1)
+				Expect(second).To(Equal(first))
+			})
+
+			It("gives up after a bounded number of attempts", func() {
+				cl.PatchReturns(conflict)
+
+				err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
+				Expect(api_errors.IsConflict(err)).To(BeTrue())
+				Expect(cl.PatchCallCount()).To(Equal(5))
+			})
+
+			It("does not retry a conflict over the fields of another field manager", func() {
+				fieldManagerConflict := &api_errors.StatusError{ErrStatus: metav1.Status{
+					Status: metav1.StatusFailure,
+					Code:   409,
+					Reason: metav1.StatusReasonConflict,
+					Details: &metav1.StatusDetails{
+						Causes: []metav1.StatusCause{{Type: metav1.CauseTypeFieldManagerConflict, Field: ".spec.parallelism"}},
+					},
+				}}
+				cl.PatchReturns(fieldManagerConflict)
+
+				Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).NotTo(Succeed())
+				Expect(cl.PatchCallCount()).To(Equal(1))
+			})
+
+			It("does not retry other errors", func() {
+				cl.PatchReturns(errors.New("some-error"))
+
+				Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).NotTo(Succeed())
+				Expect(cl.PatchCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("EnsureObjectExistsOnCluster with a validator", func() {
+			var (
+				validator  *openapifakes.FakeValidator
+				stampedObj *unstructured.Unstructured
+			)
+
+			BeforeEach(func() {
+				validator = &openapifakes.FakeValidator{}
+				repo = repository.NewRepository(cl, cache, repository.WithValidator(validator))
+
+				stampedObj = &unstructured.Unstructured{}
+				stampedObj.SetAPIVersion("batch/v1")
+				stampedObj.SetKind("Job")
+				stampedObj.SetName("hello")
+				stampedObj.SetNamespace("default")
+
+				cl.GetReturns(api_errors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, "hello"))
+			})
+
+			It("validates the object before applying it", func() {
+				Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+
+				Expect(validator.ValidateCallCount()).To(Equal(1))
+				Expect(validator.ValidateArgsForCall(0)).To(Equal(stampedObj))
+				Expect(cl.PatchCallCount()).To(Equal(1))
+			})
+
+			Context("when the object does not conform to its schema", func() {
+				BeforeEach(func() {
+					validator.ValidateReturns(openapi.ValidationError{
+						GVK:  stampedObj.GroupVersionKind(),
+						Errs: []error{errors.New(`unknown field "colour"`)},
+					})
+				})
+
+				It("does not submit the object", func() {
+					err := repo.EnsureObjectExistsOnCluster(stampedObj, true)
+					Expect(err).To(MatchError(`validate Job.batch 'hello' in namespace 'default': invalid Job: unknown field "colour"`))
+
+					var objectErr repository.ObjectError
+					Expect(errors.As(err, &objectErr)).To(BeTrue())
+					Expect(objectErr.Verb).To(Equal(repository.ValidateVerb))
+
+					Expect(cl.PatchCallCount()).To(Equal(0))
+					Expect(cl.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the schema of the object cannot be fetched", func() {
+				BeforeEach(func() {
+					validator.ValidateReturns(errors.New("openapi schema: some-error"))
+				})
+
+				It("leaves the object for the API server to validate", func() {
+					Expect(repo.EnsureObjectExistsOnCluster(stampedObj, true)).To(Succeed())
+					Expect(cl.PatchCallCount()).To(Equal(1))
+				})
+			})
+		})
+	})
+
+	Describe("tests using apiMachinery fake client", func() {
+		var (
+			scheme            *runtime.Scheme
+			fakeClientBuilder *fake.ClientBuilder
+			clientObjects     []client.Object
+			cl                client.Client
+		)
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(v1.AddToScheme(scheme)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			fakeClientBuilder = fake.NewClientBuilder().WithScheme(scheme).WithObjects(clientObjects...)
+			cl = fakeClientBuilder.Build()
+
+			repo = repository.NewRepository(cl, cache)
+		})
+
+		Context("AdoptObjectOnCluster", func() {
+			var (
+				existing *v1.ConfigMap
+				stamped  *unstructured.Unstructured
+			)
+
+			BeforeEach(func() {
+				existing = &v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "app-config",
+						Namespace: "dev",
+						Labels:    map[string]string{"created-by": "hand"},
+					},
+					Data: map[string]string{"color": "red"},
+				}
+				clientObjects = []client.Object{existing}
+
+				stamped = &unstructured.Unstructured{}
+				stamped.SetAPIVersion("v1")
+				stamped.SetKind("ConfigMap")
+				stamped.SetName("app-config")
+				stamped.SetNamespace("dev")
+				stamped.SetLabels(map[string]string{
+					"carto.run/owner-uid":     "workload-uid",
+					"carto.run/resource-name": "config",
+				})
+				isController := true
+				stamped.SetOwnerReferences([]metav1.OwnerReference{{
+					APIVersion: "carto.run/v1alpha1",
+					Kind:       "Workload",
+					Name:       "petclinic",
+					UID:        "workload-uid",
+					Controller: &isController,
+				}})
+				Expect(unstructured.SetNestedStringMap(stamped.Object, map[string]string{"color": "blue"}, "data")).To(Succeed())
+			})
+
+			JustBeforeEach(func() {
+				repo = repository.NewRepository(applyAsMerge{cl}, cache)
+			})
+
+			adopted := func() *v1.ConfigMap {
+				configMap := &v1.ConfigMap{}
+				Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "dev", Name: "app-config"}, configMap)).To(Succeed())
+				return configMap
+			}
+
+			It("takes ownership of an object that was not stamped and applies the template", func() {
+				Expect(repo.AdoptObjectOnCluster(stamped)).To(Succeed())
+
+				configMap := adopted()
+				Expect(configMap.Data).To(Equal(map[string]string{"color": "blue"}))
+				Expect(configMap.Labels).To(HaveKeyWithValue("carto.run/owner-uid", "workload-uid"))
+				Expect(metav1.GetControllerOf(configMap).UID).To(Equal(types.UID("workload-uid")))
+			})
+
+			Context("when there is no object of the same name", func() {
+				BeforeEach(func() {
+					clientObjects = nil
+				})
+
+				It("creates the object", func() {
+					Expect(repo.AdoptObjectOnCluster(stamped)).To(Succeed())
+					Expect(adopted().Data).To(Equal(map[string]string{"color": "blue"}))
+				})
+			})
+
+			Context("when the object is controlled by another owner", func() {
+				BeforeEach(func() {
+					isController := true
+					existing.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "other", UID: "other-uid", Controller: &isController}}
+				})
+
+				It("does not adopt it", func() {
+					err := repo.AdoptObjectOnCluster(stamped)
+					Expect(err).To(MatchError("adopt ConfigMap 'app-config': controlled by Deployment 'other'"))
+					Expect(adopted().Data).To(Equal(map[string]string{"color": "red"}))
+				})
+			})
+
+			Context("when the object was stamped for another owner", func() {
+				BeforeEach(func() {
+					existing.Labels = map[string]string{"carto.run/owner-uid": "other-uid"}
+				})
+
+				It("does not adopt it", func() {
+					err := repo.AdoptObjectOnCluster(stamped)
+					Expect(err).To(MatchError("adopt ConfigMap 'app-config': stamped for owner uid 'other-uid'"))
+					Expect(adopted().Data).To(Equal(map[string]string{"color": "red"}))
+				})
+			})
+		})
+
+		Context("EnsureObjectExistsOnCluster with a three-way merge", func() {
+			var stamped func(data map[string]string) *unstructured.Unstructured
+
+			BeforeEach(func() {
+				clientObjects = nil
+
+				stamped = func(data map[string]string) *unstructured.Unstructured {
+					obj := &unstructured.Unstructured{}
+					obj.SetAPIVersion("v1")
+					obj.SetKind("ConfigMap")
+					obj.SetName("app-config")
+					obj.SetNamespace("dev")
+					obj.SetLabels(map[string]string{"carto.run/owner-uid": "workload-uid"})
+					Expect(unstructured.SetNestedStringMap(obj.Object, data, "data")).To(Succeed())
+					return obj
+				}
+			})
+
+			JustBeforeEach(func() {
+				repo = repository.NewRepository(cl, cache, repository.WithThreeWayMerge())
+			})
+
+			onCluster := func() *v1.ConfigMap {
+				configMap := &v1.ConfigMap{}
+				Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "dev", Name: "app-config"}, configMap)).To(Succeed())
+				return configMap
+			}
+
+			It("records the configuration the object was created with", func() {
+				Expect(repo.EnsureObjectExistsOnCluster(stamped(map[string]string{"color": "blue"}), true)).To(Succeed())
+
+				Expect(onCluster().Annotations[repository.LastAppliedAnnotation]).To(MatchJSON(`{
+					"apiVersion": "v1",
+					"kind": "ConfigMap",
+					"metadata": {"name": "app-config", "namespace": "dev", "labels": {"carto.run/owner-uid": "workload-uid"}},
+					"data": {"color": "blue"}
+				}`))
+			})
+
+			It("keeps the fields added by others, and removes those the template no longer sets", func() {
+				Expect(repo.EnsureObjectExistsOnCluster(stamped(map[string]string{"color": "blue", "size": "large"}), true)).To(Succeed())
+
+				configMap := onCluster()
+				configMap.Data["injected"] = "by-a-webhook"
+				configMap.Annotations["team"] = "a"
+				Expect(cl.Update(context.TODO(), configMap)).To(Succeed())
+
+				Expect(repo.EnsureObjectExistsOnCluster(stamped(map[string]string{"color": "red"}), true)).To(Succeed())
+
+				configMap = onCluster()
+				Expect(configMap.Data).To(Equal(map[string]string{"color": "red", "injected": "by-a-webhook"}))
+				Expect(configMap.Annotations).To(HaveKeyWithValue("team", "a"))
+			})
+
+			Context("when the object was not stamped with a three-way merge before", func() {
+				BeforeEach(func() {
+					clientObjects = []client.Object{&v1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "dev", Labels: map[string]string{"carto.run/owner-uid": "workload-uid"}},
+						Data:       map[string]string{"color": "blue", "size": "large"},
+					}}
+				})
+
+				It("updates the fields the template sets and leaves the others", func() {
+					Expect(repo.EnsureObjectExistsOnCluster(stamped(map[string]string{"color": "red"}), true)).To(Succeed())
+
+					configMap := onCluster()
+					Expect(configMap.Data).To(Equal(map[string]string{"color": "red", "size": "large"}))
+					Expect(configMap.Annotations).To(HaveKey(repository.LastAppliedAnnotation))
+				})
+			})
+		})
+
+		Context("EnsureObjectExistsOnCluster after the template is edited", func() {
+			var stamped func(templateHash string, color string) *unstructured.Unstructured
+
+			BeforeEach(func() {
+				clientObjects = nil
+
+				stamped = func(templateHash string, color string) *unstructured.Unstructured {
+					obj := &unstructured.Unstructured{}
+					obj.SetAPIVersion("v1")
+					obj.SetKind("ConfigMap")
+					obj.SetName("app-config")
+					obj.SetNamespace("dev")
+					obj.SetLabels(map[string]string{
+						"carto.run/owner-uid":         "workload-uid",
+						"carto.run/resource-name":     "config",
+						"carto.run/template-hash":     templateHash,
+						"carto.run/template-name":     "config-template",
+						"carto.run/supply-chain-name": "my-supply-chain",
+					})
+					Expect(unstructured.SetNestedStringMap(obj.Object, map[string]string{"color": color}, "data")).To(Succeed())
+					return obj
+				}
+			})
+
+			onCluster := func() *v1.ConfigMap {
+				configMap := &v1.ConfigMap{}
+				Expect(cl.Get(context.TODO(), client.ObjectKey{Namespace: "dev", Name: "app-config"}, configMap)).To(Succeed())
+				return configMap
+			}
+
+			It("updates the object stamped from the earlier template", func() {
+				repo = repository.NewRepository(applyAsMerge{cl}, cache)
+
+				Expect(repo.EnsureObjectExistsOnCluster(stamped("0123456789abcdef", "blue"), true)).To(Succeed())
+				Expect(repo.EnsureObjectExistsOnCluster(stamped("fedcba9876543210", "red"), true)).To(Succeed())
+
+				configMap := onCluster()
+				Expect(configMap.Data).To(Equal(map[string]string{"color": "red"}))
+				Expect(configMap.Labels).To(HaveKeyWithValue("carto.run/template-hash", "fedcba9876543210"))
+			})
+
+			It("updates the object stamped from the earlier template with a three-way merge", func() {
+				repo = repository.NewRepository(cl, cache, repository.WithThreeWayMerge())
+
+				Expect(repo.EnsureObjectExistsOnCluster(stamped("0123456789abcdef", "blue"), true)).To(Succeed())
+				Expect(repo.EnsureObjectExistsOnCluster(stamped("fedcba9876543210", "red"), true)).To(Succeed())
+
+				configMap := onCluster()
+				Expect(configMap.Data).To(Equal(map[string]string{"color": "red"}))
+				Expect(configMap.Labels).To(HaveKeyWithValue("carto.run/template-hash", "fedcba9876543210"))
+			})
+		})
+
+		Context("EnsureObjectExistsOnCluster with an auditor", func() {
+			var (
+				auditor *recordingAuditor
+				stamped func(color string) *unstructured.Unstructured
+			)
+
+			BeforeEach(func() {
+				clientObjects = nil
+				auditor = &recordingAuditor{}
+
+				stamped = func(color string) *unstructured.Unstructured {
+					obj := &unstructured.Unstructured{}
+					obj.SetAPIVersion("v1")
+					obj.SetKind("ConfigMap")
+					obj.SetName("app-config")
+					obj.SetNamespace("dev")
+					obj.SetLabels(map[string]string{"carto.run/owner-uid": "workload-uid"})
+					Expect(unstructured.SetNestedStringMap(obj.Object, map[string]string{"color": color}, "data")).To(Succeed())
+					return obj
+				}
+			})
+
+			JustBeforeEach(func() {
+				repo = repository.NewRepository(applyAsMerge{cl}, cache, repository.WithAuditor(auditor))
+			})
+
+			It("audits the object it creates, and the object it replaces when it updates it", func() {
+				Expect(repo.EnsureObjectExistsOnCluster(stamped("blue"), true)).To(Succeed())
+				Expect(repo.EnsureObjectExistsOnCluster(stamped("red"), true)).To(Succeed())
+
+				Expect(auditor.befores).To(HaveLen(2))
+				Expect(auditor.befores[0]).To(BeNil())
+				Expect(auditor.afters[0].Object["data"]).To(Equal(map[string]interface{}{"color": "blue"}))
+				Expect(auditor.befores[1].Object["data"]).To(Equal(map[string]interface{}{"color": "blue"}))
+				Expect(auditor.afters[1].Object["data"]).To(Equal(map[string]interface{}{"color": "red"}))
+			})
+
+			Context("when the object is unchanged since it was last written", func() {
+				BeforeEach(func() {
+					cache.UnchangedSinceCachedReturns(stamped("blue"))
+				})
+
+				It("does not audit it", func() {
+					Expect(repo.EnsureObjectExistsOnCluster(stamped("blue"), true)).To(Succeed())
+					Expect(auditor.afters).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("EnsureObjectExistsOnCluster with a writer", func() {
+			var writer *repositoryfakes.FakeClient
+
+			BeforeEach(func() {
+				clientObjects = nil
+				writer = &repositoryfakes.FakeClient{}
+			})
+
+			JustBeforeEach(func() {
+				repo = repository.NewRepository(cl, cache, repository.WithWriter(writer))
+			})
+
+			It("writes the object with the writer rather than the repository's client", func() {
+				obj := &unstructured.Unstructured{}
+				obj.SetAPIVersion("v1")
+				obj.SetKind("ConfigMap")
+				obj.SetName("app-config")
+				obj.SetNamespace("dev")
+
+				Expect(repo.EnsureObjectExistsOnCluster(obj, false)).To(Succeed())
+
+				Expect(writer.CreateCallCount()).To(Equal(1))
+				_, created, _ := writer.CreateArgsForCall(0)
+				Expect(created.GetName()).To(Equal("app-config"))
+
+				list := &unstructured.UnstructuredList{}
+				list.SetAPIVersion("v1")
+				list.SetKind("ConfigMapList")
+				Expect(cl.List(context.TODO(), list)).To(Succeed())
+				Expect(list.Items).To(BeEmpty())
+			})
+		})
+
+		Context("ListDependents", func() {
+			var run *unstructured.Unstructured
+
+			pod := func(name string, labels map[string]string, owner metav1.OwnerReference) *v1.Pod {
+				return &v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            name,
+						Namespace:       "dev",
+						Labels:          labels,
+						OwnerReferences: []metav1.OwnerReference{owner},
+					},
+				}
+			}
+
+			BeforeEach(func() {
+				runLabels := map[string]string{
+					"carto.run/owner-uid":     "pipeline-uid",
+					"carto.run/resource-name": "run",
+				}
+				run = &unstructured.Unstructured{}
+				run.SetAPIVersion("tekton.dev/v1beta1")
+				run.SetKind("PipelineRun")
+				run.SetName("build-1")
+				run.SetNamespace("dev")
+				run.SetUID("run-uid")
+				run.SetLabels(runLabels)
+
+				intermediate := &v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "build-1-task",
+						Namespace:       "dev",
+						UID:             "task-uid",
+						OwnerReferences: []metav1.OwnerReference{{APIVersion: "tekton.dev/v1beta1", Kind: "PipelineRun", Name: "build-1", UID: "run-uid"}},
+					},
+				}
+				clientObjects = []client.Object{
+					intermediate,
+					pod("owned", runLabels, metav1.OwnerReference{APIVersion: "tekton.dev/v1beta1", Kind: "PipelineRun", Name: "build-1", UID: "run-uid"}),
+					pod("owned-through-task", runLabels, metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "build-1-task", UID: "task-uid"}),
+					pod("of-another-run", runLabels, metav1.OwnerReference{APIVersion: "tekton.dev/v1beta1", Kind: "PipelineRun", Name: "build-2", UID: "other-run-uid"}),
+					pod("unlabelled", nil, metav1.OwnerReference{APIVersion: "tekton.dev/v1beta1", Kind: "PipelineRun", Name: "build-1", UID: "run-uid"}),
+				}
+			})
+
+			It("lists the objects with the run's identity labels that it owns, directly or through other owners", func() {
+				dependents, err := repo.ListDependents(run, schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+				Expect(err).NotTo(HaveOccurred())
+
+				var names []string
+				for _, dependent := range dependents {
+					names = append(names, dependent.GetName())
+				}
+				Expect(names).To(ConsistOf("owned", "owned-through-task"))
+			})
+
+			It("lists nothing for a run without identity labels", func() {
+				run.SetLabels(nil)
+
+				dependents, err := repo.ListDependents(run, schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependents).To(BeEmpty())
+			})
+		})
+
+		Context("GetClusterTemplate", func() {
+			BeforeEach(func() {
+				template := &v1alpha1.ClusterSourceTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "some-name",
+					},
+				}
+				clientObjects = []client.Object{template}
+			})
+
+			It("gets the template successfully", func() {
+				templateRef := v1alpha1.ClusterTemplateReference{
+					Kind: "ClusterSourceTemplate",
+					Name: "some-name",
+				}
+				template, err := repo.GetClusterTemplate(templateRef)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(template.GetName()).To(Equal("some-name"))
+			})
+
+			Context("when templates are verified", func() {
+				var (
+					verifier    *signaturefakes.FakeVerifier
+					templateRef v1alpha1.ClusterTemplateReference
+				)
+
+				JustBeforeEach(func() {
+					verifier = &signaturefakes.FakeVerifier{}
+					repo = repository.NewRepository(cl, cache, repository.WithTemplateVerifier(verifier))
+					templateRef = v1alpha1.ClusterTemplateReference{Kind: "ClusterSourceTemplate", Name: "some-name"}
+				})
+
+				It("verifies the template it gets", func() {
+					_, err := repo.GetClusterTemplate(templateRef)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(verifier.VerifyCallCount()).To(Equal(1))
+					Expect(verifier.VerifyArgsForCall(0).GetName()).To(Equal("some-name"))
+				})
+
+				It("does not return a template the verifier does not trust", func() {
+					verifier.VerifyReturns(errors.New("signature does not match any trusted key"))
+
+					template, err := repo.GetClusterTemplate(templateRef)
+					Expect(err).To(MatchError("verify signature: signature does not match any trusted key"))
+					Expect(template).To(BeNil())
+				})
+			})
+		})
+
+		Context("GetTemplate", func() {
+			var templateRef v1alpha1.ClusterTemplateReference
+
+			BeforeEach(func() {
+				templateRef = v1alpha1.ClusterTemplateReference{
+					Kind: "ClusterSourceTemplate",
+					Name: "some-name",
+				}
+				clientObjects = []client.Object{
+					&v1alpha1.ClusterSourceTemplate{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "some-name",
+						},
+						Spec: v1alpha1.SourceTemplateSpec{TemplateSpec: v1alpha1.TemplateSpec{Ytt: "cluster"}},
+					},
+					&v1alpha1.SourceTemplate{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "some-name",
+							Namespace: "team-ns",
+						},
+						Spec: v1alpha1.SourceTemplateSpec{TemplateSpec: v1alpha1.TemplateSpec{Ytt: "namespaced"}},
+					},
+				}
+			})
+
+			It("gets the template in the namespace", func() {
+				template, err := repo.GetTemplate(templateRef, "team-ns")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(template.GetName()).To(Equal("some-name"))
+				Expect(template.GetResourceTemplate().Ytt).To(Equal("namespaced"))
+			})
+
+			It("falls back to the cluster template when the namespace has none", func() {
+				template, err := repo.GetTemplate(templateRef, "other-ns")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(template.GetResourceTemplate().Ytt).To(Equal("cluster"))
+			})
+
+			It("returns a not found error when neither template exists", func() {
+				templateRef.Name = "missing"
+				_, err := repo.GetTemplate(templateRef, "team-ns")
+				Expect(err).To(HaveOccurred())
+				Expect(api_errors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		Context("GetRunTemplate", func() {
+			BeforeEach(func() {
+				clientObjects = []client.Object{
+					&v1alpha1.RunTemplate{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "first-template",
+							Namespace: "ns1",
+						},
+					},
+					&v1alpha1.RunTemplate{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "second-template",
+							Namespace: "ns2",
+						},
+					}}
+			})
+
+			It("gets the template successfully", func() {
+				templateRef := v1alpha1.TemplateReference{
+					Kind:      "RunTemplate",
+					Name:      "second-template",
+					Namespace: "ns2",
+				}
+				template, err := repo.GetRunTemplate(templateRef)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(template.GetName()).To(Equal("second-template"))
+			})
+
+			It("finds nothing with a mismatched namespace", func() {
+				templateRef := v1alpha1.TemplateReference{
+					Kind:      "RunTemplate",
+					Name:      "second-template",
+					Namespace: "ns1",
+				}
+				_, err := repo.GetRunTemplate(templateRef)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("not found"))
+			})
+
+			Context("with a selector", func() {
+				var templateRef v1alpha1.TemplateReference
+
+				runTemplate := func(name, version string) *v1alpha1.RunTemplate {
+					template := &v1alpha1.RunTemplate{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      name,
+							Namespace: "ns3",
+							Labels:    map[string]string{"app.tanzu.vmware.com/template": "tekton"},
+						},
+					}
+					if version != "" {
+						template.Annotations = map[string]string{v1alpha1.TemplateVersionAnnotation: version}
+					}
+					return template
+				}
+
+				BeforeEach(func() {
+					templateRef = v1alpha1.TemplateReference{
+						Kind:      "RunTemplate",
+						Namespace: "ns3",
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"app.tanzu.vmware.com/template": "tekton"},
+						},
+					}
+				})
+
+				Context("when one template matches", func() {
+					BeforeEach(func() {
+						clientObjects = append(clientObjects, runTemplate("only-template", ""))
+					})
+
+					It("gets the matching template", func() {
+						template, err := repo.GetRunTemplate(templateRef)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(template.GetName()).To(Equal("only-template"))
+					})
+				})
+
+				Context("when several templates match", func() {
+					BeforeEach(func() {
+						clientObjects = append(clientObjects,
+							runTemplate("template-v1-10", "1.10.0"),
+							runTemplate("template-v1-9", "1.9.2"),
+							runTemplate("template-unversioned", ""),
+						)
+					})
+
+					It("gets the matching template with the highest version", func() {
+						template, err := repo.GetRunTemplate(templateRef)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(template.GetName()).To(Equal("template-v1-10"))
+					})
+				})
+
+				It("returns a not found error when no template matches", func() {
+					_, err := repo.GetRunTemplate(templateRef)
+					Expect(err).To(HaveOccurred())
+					Expect(api_errors.IsNotFound(err)).To(BeTrue())
+				})
+
+				Context("when the highest version is shared by several templates", func() {
+					BeforeEach(func() {
+						clientObjects = append(clientObjects,
+							runTemplate("template-a", "2.0.0"),
+							runTemplate("template-b", "2.0.0"),
+						)
+					})
+
+					It("returns an error", func() {
+						_, err := repo.GetRunTemplate(templateRef)
+						Expect(err).To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring("more than one RunTemplate matches"))
+					})
+				})
+			})
+		})
+
+		Context("GetWorkload", func() {
+			BeforeEach(func() {
+				workload := &v1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "workload-name",
+						Namespace: "workload-namespace",
+					},
+				}
+				clientObjects = []client.Object{workload}
+			})
+
+			It("gets the workload successfully", func() {
+				workload, err := repo.GetWorkload("workload-name", "workload-namespace")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(workload.GetName()).To(Equal("workload-name"))
+			})
+
+			Context("workload doesnt exist", func() {
+				It("returns an error", func() {
+					_, err := repo.GetWorkload("workload-that-does-not-exist-name", "workload-namespace")
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("get:"))
+				})
+			})
+		})
+
+		Context("GetWorkloadPreview", func() {
+			BeforeEach(func() {
+				preview := &v1alpha1.WorkloadPreview{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "preview-name",
+						Namespace: "preview-namespace",
+					},
+				}
+				clientObjects = []client.Object{preview}
+			})
+
+			It("gets the preview successfully", func() {
+				preview, err := repo.GetWorkloadPreview("preview-name", "preview-namespace")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(preview.GetName()).To(Equal("preview-name"))
+			})
+
+			Context("preview doesnt exist", func() {
+				It("returns a not found error", func() {
+					_, err := repo.GetWorkloadPreview("preview-that-does-not-exist", "preview-namespace")
+					Expect(err).To(MatchError(ContainSubstring("get-workload-preview:")))
+					Expect(api_errors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+
+		Context("GetTemplatePlayground", func() {
+			BeforeEach(func() {
+				playground := &v1alpha1.TemplatePlayground{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "playground-name",
+						Namespace: "playground-namespace",
+					},
+				}
+				clientObjects = []client.Object{playground}
+			})
+
+			It("gets the playground successfully", func() {
+				playground, err := repo.GetTemplatePlayground("playground-name", "playground-namespace")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(playground.GetName()).To(Equal("playground-name"))
+			})
+
+			Context("playground doesnt exist", func() {
+				It("returns a not found error", func() {
+					_, err := repo.GetTemplatePlayground("playground-that-does-not-exist", "playground-namespace")
+					Expect(err).To(MatchError(ContainSubstring("get-template-playground:")))
+					Expect(api_errors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+
+		Context("GetRealizationReport", func() {
+			BeforeEach(func() {
+				report := &v1alpha1.RealizationReport{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "fleet",
+					},
+				}
+				clientObjects = []client.Object{report}
+			})
+
+			It("gets the report successfully", func() {
+				report, err := repo.GetRealizationReport("fleet")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.GetName()).To(Equal("fleet"))
+			})
+
+			Context("report doesnt exist", func() {
+				It("returns a not found error", func() {
+					_, err := repo.GetRealizationReport("report-that-does-not-exist")
+					Expect(err).To(MatchError(ContainSubstring("get-realization-report:")))
+					Expect(api_errors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+
+		Context("ListWorkloads", func() {
+			BeforeEach(func() {
+				clientObjects = []client.Object{
+					&v1alpha1.Workload{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "dev"}},
+					&v1alpha1.Workload{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "prod"}},
+				}
+			})
+
+			It("lists the workloads of every namespace", func() {
+				workloads, err := repo.ListWorkloads()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(workloads).To(HaveLen(2))
+			})
+		})
+
+		Context("ListSupplyChains", func() {
+			BeforeEach(func() {
+				clientObjects = []client.Object{
+					&v1alpha1.ClusterSupplyChain{ObjectMeta: metav1.ObjectMeta{Name: "supplychain-name"}},
+				}
+			})
+
+			It("lists the supply chains", func() {
+				supplyChains, err := repo.ListSupplyChains()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(supplyChains).To(HaveLen(1))
+				Expect(supplyChains[0].Name).To(Equal("supplychain-name"))
+			})
+		})
+
+		Context("ListNamespacedSupplyChains", func() {
+			BeforeEach(func() {
+				clientObjects = []client.Object{
+					&v1alpha1.SupplyChain{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "dev"}},
+					&v1alpha1.SupplyChain{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "prod"}},
+				}
+			})
+
+			It("lists the supply chains of every namespace", func() {
+				supplyChains, err := repo.ListNamespacedSupplyChains()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(supplyChains).To(HaveLen(2))
+			})
+		})
+
+		Context("ListPipelines", func() {
+			BeforeEach(func() {
+				clientObjects = []client.Object{
+					&v1alpha1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "dev"}},
+					&v1alpha1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "prod"}},
+				}
+			})
+
+			It("lists the pipelines of every namespace", func() {
+				pipelines, err := repo.ListPipelines()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pipelines).To(HaveLen(2))
+			})
+		})
+
+		Context("ListStampPolicies", func() {
+			BeforeEach(func() {
+				clientObjects = []client.Object{
+					&v1alpha1.ClusterStampPolicy{ObjectMeta: metav1.ObjectMeta{Name: "tenants"}},
+				}
+			})
+
+			It("lists the stamp policies", func() {
+				policies, err := repo.ListStampPolicies()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(policies).To(HaveLen(1))
+			})
+		})
+
+		Context("GetPipeline", func() {
+			BeforeEach(func() {
+				pipeline := &v1alpha1.Pipeline{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pipeline-name",
+						Namespace: "pipeline-namespace",
+					},
+				}
+				clientObjects = []client.Object{pipeline}
+			})
+
+			It("gets the pipeline successfully", func() {
+				pipeline, err := repo.GetPipeline("pipeline-name", "pipeline-namespace")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(pipeline.GetName()).To(Equal("pipeline-name"))
+			})
+
+			Context("pipeline doesnt exist", func() {
+				It("returns an error", func() {
+					_, err := repo.GetPipeline("pipeline-that-does-not-exist-name", "pipeline-namespace")
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("get-pipeline:"))
+				})
+			})
+		})
+
+		Context("GetSupplyChain", func() {
 			BeforeEach(func() {
 				supplyChain := &v1alpha1.ClusterSupplyChain{
 					ObjectMeta: metav1.ObjectMeta{
@@ -689,6 +2010,292 @@ spec:
 					Expect(len(supplyChains)).To(Equal(0))
 				})
 			})
+
+			Context("Supply chain with selector match expressions", func() {
+				BeforeEach(func() {
+					supplyChain := &v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "supplychain-name",
+						},
+						Spec: v1alpha1.SupplyChainSpec{
+							Selector: map[string]string{"foo": "bar"},
+							SelectorMatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"web", "worker"}},
+								{Key: "legacy", Operator: metav1.LabelSelectorOpDoesNotExist},
+							},
+						},
+					}
+					clientObjects = []client.Object{supplyChain}
+				})
+
+				It("returns supply chains whose expressions the workload labels meet", func() {
+					workload := &v1alpha1.Workload{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "workload-name",
+							Labels: map[string]string{"foo": "bar", "tier": "worker"},
+						},
+					}
+					supplyChains, err := repo.GetSupplyChainsForWorkload(workload)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(len(supplyChains)).To(Equal(1))
+					Expect(supplyChains[0].Name).To(Equal("supplychain-name"))
+				})
+
+				Context("and field requirements", func() {
+					BeforeEach(func() {
+						clientObjects[0].(*v1alpha1.ClusterSupplyChain).Spec.SelectorMatchFields = []v1alpha1.FieldSelectorRequirement{
+							{Key: "spec.source.git", Operator: v1alpha1.FieldSelectorOpExists},
+						}
+					})
+
+					It("returns no supply chains when a field requirement is not met", func() {
+						workload := &v1alpha1.Workload{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:   "workload-name",
+								Labels: map[string]string{"foo": "bar", "tier": "web"},
+							},
+						}
+						supplyChains, err := repo.GetSupplyChainsForWorkload(workload)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(len(supplyChains)).To(Equal(0))
+					})
+
+					It("returns supply chains whose field requirements the workload meets", func() {
+						url := "https://github.com/spring-projects/spring-petclinic.git"
+						workload := &v1alpha1.Workload{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:   "workload-name",
+								Labels: map[string]string{"foo": "bar", "tier": "web"},
+							},
+							Spec: v1alpha1.WorkloadSpec{
+								Source: &v1alpha1.WorkloadSource{Git: &v1alpha1.WorkloadGit{URL: &url}},
+							},
+						}
+						supplyChains, err := repo.GetSupplyChainsForWorkload(workload)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(len(supplyChains)).To(Equal(1))
+					})
+				})
+
+				It("returns no supply chains when an expression is not met", func() {
+					workload := &v1alpha1.Workload{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "workload-name",
+							Labels: map[string]string{"foo": "bar", "tier": "web", "legacy": "true"},
+						},
+					}
+					supplyChains, err := repo.GetSupplyChainsForWorkload(workload)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(len(supplyChains)).To(Equal(0))
+				})
+			})
+
+			Context("Namespaced supply chains", func() {
+				BeforeEach(func() {
+					clientObjects = []client.Object{
+						&v1alpha1.ClusterSupplyChain{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "cluster-supplychain",
+							},
+							Spec: v1alpha1.SupplyChainSpec{
+								Selector: map[string]string{"foo": "bar"},
+							},
+						},
+						&v1alpha1.SupplyChain{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "team-supplychain",
+								Namespace: "team-ns",
+							},
+							Spec: v1alpha1.SupplyChainSpec{
+								Selector: map[string]string{"foo": "bar"},
+							},
+						},
+						&v1alpha1.SupplyChain{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "other-supplychain",
+								Namespace: "other-ns",
+							},
+							Spec: v1alpha1.SupplyChainSpec{
+								Selector: map[string]string{"foo": "bar"},
+							},
+						},
+					}
+				})
+
+				It("returns the supply chains of the workload namespace alongside the cluster supply chains", func() {
+					workload := &v1alpha1.Workload{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "workload-name",
+							Namespace: "team-ns",
+							Labels:    map[string]string{"foo": "bar"},
+						},
+					}
+					supplyChains, err := repo.GetSupplyChainsForWorkload(workload)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(len(supplyChains)).To(Equal(2))
+					Expect(supplyChains[0].Name).To(Equal("cluster-supplychain"))
+					Expect(supplyChains[0].Namespace).To(BeEmpty())
+					Expect(supplyChains[1].Name).To(Equal("team-supplychain"))
+					Expect(supplyChains[1].Namespace).To(Equal("team-ns"))
+				})
+			})
+
+			Context("Default supply chain without a selector", func() {
+				BeforeEach(func() {
+					clientObjects = []client.Object{
+						&v1alpha1.ClusterSupplyChain{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "default-supplychain",
+							},
+							Spec: v1alpha1.SupplyChainSpec{
+								Default: true,
+							},
+						},
+					}
+				})
+
+				It("does not select the workload", func() {
+					workload := &v1alpha1.Workload{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "workload-name",
+							Labels: map[string]string{"foo": "bar"},
+						},
+					}
+					supplyChains, err := repo.GetSupplyChainsForWorkload(workload)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(supplyChains).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("GetDefaultSupplyChainsForWorkload", func() {
+			var workload *v1alpha1.Workload
+
+			BeforeEach(func() {
+				workload = &v1alpha1.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "workload-name",
+						Namespace: "team-ns",
+					},
+				}
+				clientObjects = []client.Object{
+					&v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster-default",
+						},
+						Spec: v1alpha1.SupplyChainSpec{
+							Default: true,
+						},
+					},
+					&v1alpha1.ClusterSupplyChain{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster-supplychain",
+						},
+						Spec: v1alpha1.SupplyChainSpec{
+							Selector: map[string]string{"foo": "bar"},
+						},
+					},
+				}
+			})
+
+			It("returns the default ClusterSupplyChains", func() {
+				supplyChains, err := repo.GetDefaultSupplyChainsForWorkload(workload)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(supplyChains)).To(Equal(1))
+				Expect(supplyChains[0].Name).To(Equal("cluster-default"))
+			})
+
+			Context("when the workload namespace has a default supply chain", func() {
+				BeforeEach(func() {
+					clientObjects = append(clientObjects,
+						&v1alpha1.SupplyChain{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "team-default",
+								Namespace: "team-ns",
+							},
+							Spec: v1alpha1.SupplyChainSpec{
+								Default: true,
+							},
+						},
+						&v1alpha1.SupplyChain{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "other-default",
+								Namespace: "other-ns",
+							},
+							Spec: v1alpha1.SupplyChainSpec{
+								Default: true,
+							},
+						},
+					)
+				})
+
+				It("prefers it to the default ClusterSupplyChains", func() {
+					supplyChains, err := repo.GetDefaultSupplyChainsForWorkload(workload)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(len(supplyChains)).To(Equal(1))
+					Expect(supplyChains[0].Name).To(Equal("team-default"))
+					Expect(supplyChains[0].Namespace).To(Equal("team-ns"))
+				})
+			})
+		})
+
+		Context("GetNamespacedSupplyChain", func() {
+			BeforeEach(func() {
+				clientObjects = []client.Object{
+					&v1alpha1.SupplyChain{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "sc-name",
+							Namespace: "team-ns",
+						},
+					},
+				}
+			})
+
+			It("gets the supply chain successfully", func() {
+				sc, err := repo.GetNamespacedSupplyChain("sc-name", "team-ns")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sc.GetName()).To(Equal("sc-name"))
+			})
+
+			Context("supply chain doesnt exist in the namespace", func() {
+				It("returns no error", func() {
+					sc, err := repo.GetNamespacedSupplyChain("sc-name", "other-ns")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(sc).To(BeNil())
+				})
+			})
 		})
 	})
 })
+
+// applyAsMerge stands in for server-side apply, which the fake client does
+// not support, with a create or a merge patch of the applied object.
+type recordingAuditor struct {
+	befores []*unstructured.Unstructured
+	afters  []*unstructured.Unstructured
+}
+
+func (a *recordingAuditor) Audit(before, after *unstructured.Unstructured) {
+	a.befores = append(a.befores, before)
+	a.afters = append(a.afters, after.DeepCopy())
+}
+
+type applyAsMerge struct {
+	client.Client
+}
+
+func (c applyAsMerge) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	err = c.Client.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+	if api_errors.IsNotFound(err) {
+		return c.Client.Create(ctx, obj)
+	}
+	return err
+}
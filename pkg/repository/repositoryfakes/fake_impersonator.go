@@ -0,0 +1,119 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package repositoryfakes
+
+import (
+	"sync"
+
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type FakeImpersonator struct {
+	ServiceAccountClientStub        func(string, string) (client.Client, error)
+	serviceAccountClientMutex       sync.RWMutex
+	serviceAccountClientArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	serviceAccountClientReturns struct {
+		result1 client.Client
+		result2 error
+	}
+	serviceAccountClientReturnsOnCall map[int]struct {
+		result1 client.Client
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeImpersonator) ServiceAccountClient(arg1 string, arg2 string) (client.Client, error) {
+	fake.serviceAccountClientMutex.Lock()
+	ret, specificReturn := fake.serviceAccountClientReturnsOnCall[len(fake.serviceAccountClientArgsForCall)]
+	fake.serviceAccountClientArgsForCall = append(fake.serviceAccountClientArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ServiceAccountClientStub
+	fakeReturns := fake.serviceAccountClientReturns
+	fake.recordInvocation("ServiceAccountClient", []interface{}{arg1, arg2})
+	fake.serviceAccountClientMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeImpersonator) ServiceAccountClientCallCount() int {
+	fake.serviceAccountClientMutex.RLock()
+	defer fake.serviceAccountClientMutex.RUnlock()
+	return len(fake.serviceAccountClientArgsForCall)
+}
+
+func (fake *FakeImpersonator) ServiceAccountClientCalls(stub func(string, string) (client.Client, error)) {
+	fake.serviceAccountClientMutex.Lock()
+	defer fake.serviceAccountClientMutex.Unlock()
+	fake.ServiceAccountClientStub = stub
+}
+
+func (fake *FakeImpersonator) ServiceAccountClientArgsForCall(i int) (string, string) {
+	fake.serviceAccountClientMutex.RLock()
+	defer fake.serviceAccountClientMutex.RUnlock()
+	argsForCall := fake.serviceAccountClientArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeImpersonator) ServiceAccountClientReturns(result1 client.Client, result2 error) {
+	fake.serviceAccountClientMutex.Lock()
+	defer fake.serviceAccountClientMutex.Unlock()
+	fake.ServiceAccountClientStub = nil
+	fake.serviceAccountClientReturns = struct {
+		result1 client.Client
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeImpersonator) ServiceAccountClientReturnsOnCall(i int, result1 client.Client, result2 error) {
+	fake.serviceAccountClientMutex.Lock()
+	defer fake.serviceAccountClientMutex.Unlock()
+	fake.ServiceAccountClientStub = nil
+	if fake.serviceAccountClientReturnsOnCall == nil {
+		fake.serviceAccountClientReturnsOnCall = make(map[int]struct {
+			result1 client.Client
+			result2 error
+		})
+	}
+	fake.serviceAccountClientReturnsOnCall[i] = struct {
+		result1 client.Client
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeImpersonator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.serviceAccountClientMutex.RLock()
+	defer fake.serviceAccountClientMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeImpersonator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ repository.Impersonator = new(FakeImpersonator)
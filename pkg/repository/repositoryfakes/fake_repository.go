@@ -9,15 +9,41 @@ import (
 	"github.com/vmware-tanzu/cartographer/pkg/templates"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type FakeRepository struct {
-	EnsureObjectExistsOnClusterStub        func(*unstructured.Unstructured, bool) error
+	AdoptObjectOnClusterStub        func(*unstructured.Unstructured, ...repository.ApplyOption) error
+	adoptObjectOnClusterMutex       sync.RWMutex
+	adoptObjectOnClusterArgsForCall []struct {
+		arg1 *unstructured.Unstructured
+		arg2 []repository.ApplyOption
+	}
+	adoptObjectOnClusterReturns struct {
+		result1 error
+	}
+	adoptObjectOnClusterReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteStub        func(*unstructured.Unstructured) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 *unstructured.Unstructured
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	EnsureObjectExistsOnClusterStub        func(*unstructured.Unstructured, bool, ...repository.ApplyOption) error
 	ensureObjectExistsOnClusterMutex       sync.RWMutex
 	ensureObjectExistsOnClusterArgsForCall []struct {
 		arg1 *unstructured.Unstructured
 		arg2 bool
+		arg3 []repository.ApplyOption
 	}
 	ensureObjectExistsOnClusterReturns struct {
 		result1 error
@@ -38,6 +64,33 @@ type FakeRepository struct {
 		result1 templates.Template
 		result2 error
 	}
+	GetDefaultSupplyChainsForWorkloadStub        func(*v1alpha1.Workload) ([]v1alpha1.ClusterSupplyChain, error)
+	getDefaultSupplyChainsForWorkloadMutex       sync.RWMutex
+	getDefaultSupplyChainsForWorkloadArgsForCall []struct {
+		arg1 *v1alpha1.Workload
+	}
+	getDefaultSupplyChainsForWorkloadReturns struct {
+		result1 []v1alpha1.ClusterSupplyChain
+		result2 error
+	}
+	getDefaultSupplyChainsForWorkloadReturnsOnCall map[int]struct {
+		result1 []v1alpha1.ClusterSupplyChain
+		result2 error
+	}
+	GetNamespacedSupplyChainStub        func(string, string) (*v1alpha1.SupplyChain, error)
+	getNamespacedSupplyChainMutex       sync.RWMutex
+	getNamespacedSupplyChainArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getNamespacedSupplyChainReturns struct {
+		result1 *v1alpha1.SupplyChain
+		result2 error
+	}
+	getNamespacedSupplyChainReturnsOnCall map[int]struct {
+		result1 *v1alpha1.SupplyChain
+		result2 error
+	}
 	GetPipelineStub        func(string, string) (*v1alpha1.Pipeline, error)
 	getPipelineMutex       sync.RWMutex
 	getPipelineArgsForCall []struct {
@@ -52,6 +105,19 @@ type FakeRepository struct {
 		result1 *v1alpha1.Pipeline
 		result2 error
 	}
+	GetRealizationReportStub        func(string) (*v1alpha1.RealizationReport, error)
+	getRealizationReportMutex       sync.RWMutex
+	getRealizationReportArgsForCall []struct {
+		arg1 string
+	}
+	getRealizationReportReturns struct {
+		result1 *v1alpha1.RealizationReport
+		result2 error
+	}
+	getRealizationReportReturnsOnCall map[int]struct {
+		result1 *v1alpha1.RealizationReport
+		result2 error
+	}
 	GetRunTemplateStub        func(v1alpha1.TemplateReference) (templates.RunTemplate, error)
 	getRunTemplateMutex       sync.RWMutex
 	getRunTemplateArgsForCall []struct {
@@ -75,6 +141,20 @@ type FakeRepository struct {
 	getSchemeReturnsOnCall map[int]struct {
 		result1 *runtime.Scheme
 	}
+	GetSecretDataStub        func(string, string) (map[string][]byte, error)
+	getSecretDataMutex       sync.RWMutex
+	getSecretDataArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getSecretDataReturns struct {
+		result1 map[string][]byte
+		result2 error
+	}
+	getSecretDataReturnsOnCall map[int]struct {
+		result1 map[string][]byte
+		result2 error
+	}
 	GetSupplyChainStub        func(string) (*v1alpha1.ClusterSupplyChain, error)
 	getSupplyChainMutex       sync.RWMutex
 	getSupplyChainArgsForCall []struct {
@@ -101,6 +181,34 @@ type FakeRepository struct {
 		result1 []v1alpha1.ClusterSupplyChain
 		result2 error
 	}
+	GetTemplateStub        func(v1alpha1.ClusterTemplateReference, string) (templates.Template, error)
+	getTemplateMutex       sync.RWMutex
+	getTemplateArgsForCall []struct {
+		arg1 v1alpha1.ClusterTemplateReference
+		arg2 string
+	}
+	getTemplateReturns struct {
+		result1 templates.Template
+		result2 error
+	}
+	getTemplateReturnsOnCall map[int]struct {
+		result1 templates.Template
+		result2 error
+	}
+	GetTemplatePlaygroundStub        func(string, string) (*v1alpha1.TemplatePlayground, error)
+	getTemplatePlaygroundMutex       sync.RWMutex
+	getTemplatePlaygroundArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getTemplatePlaygroundReturns struct {
+		result1 *v1alpha1.TemplatePlayground
+		result2 error
+	}
+	getTemplatePlaygroundReturnsOnCall map[int]struct {
+		result1 *v1alpha1.TemplatePlayground
+		result2 error
+	}
 	GetWorkloadStub        func(string, string) (*v1alpha1.Workload, error)
 	getWorkloadMutex       sync.RWMutex
 	getWorkloadArgsForCall []struct {
@@ -115,6 +223,111 @@ type FakeRepository struct {
 		result1 *v1alpha1.Workload
 		result2 error
 	}
+	GetWorkloadPreviewStub        func(string, string) (*v1alpha1.WorkloadPreview, error)
+	getWorkloadPreviewMutex       sync.RWMutex
+	getWorkloadPreviewArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getWorkloadPreviewReturns struct {
+		result1 *v1alpha1.WorkloadPreview
+		result2 error
+	}
+	getWorkloadPreviewReturnsOnCall map[int]struct {
+		result1 *v1alpha1.WorkloadPreview
+		result2 error
+	}
+	IsNamespacedStub        func(schema.GroupVersionKind) (bool, error)
+	isNamespacedMutex       sync.RWMutex
+	isNamespacedArgsForCall []struct {
+		arg1 schema.GroupVersionKind
+	}
+	isNamespacedReturns struct {
+		result1 bool
+		result2 error
+	}
+	isNamespacedReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	ListDependentsStub        func(*unstructured.Unstructured, schema.GroupVersionKind) ([]*unstructured.Unstructured, error)
+	listDependentsMutex       sync.RWMutex
+	listDependentsArgsForCall []struct {
+		arg1 *unstructured.Unstructured
+		arg2 schema.GroupVersionKind
+	}
+	listDependentsReturns struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}
+	listDependentsReturnsOnCall map[int]struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}
+	ListNamespacedSupplyChainsStub        func() ([]v1alpha1.SupplyChain, error)
+	listNamespacedSupplyChainsMutex       sync.RWMutex
+	listNamespacedSupplyChainsArgsForCall []struct {
+	}
+	listNamespacedSupplyChainsReturns struct {
+		result1 []v1alpha1.SupplyChain
+		result2 error
+	}
+	listNamespacedSupplyChainsReturnsOnCall map[int]struct {
+		result1 []v1alpha1.SupplyChain
+		result2 error
+	}
+	ListPipelinesStub        func() ([]v1alpha1.Pipeline, error)
+	listPipelinesMutex       sync.RWMutex
+	listPipelinesArgsForCall []struct {
+	}
+	listPipelinesReturns struct {
+		result1 []v1alpha1.Pipeline
+		result2 error
+	}
+	listPipelinesReturnsOnCall map[int]struct {
+		result1 []v1alpha1.Pipeline
+		result2 error
+	}
+	ListStampPoliciesStub        func() ([]v1alpha1.ClusterStampPolicy, error)
+	listStampPoliciesMutex       sync.RWMutex
+	listStampPoliciesArgsForCall []struct {
+	}
+	listStampPoliciesReturns struct {
+		result1 []v1alpha1.ClusterStampPolicy
+		result2 error
+	}
+	listStampPoliciesReturnsOnCall map[int]struct {
+		result1 []v1alpha1.ClusterStampPolicy
+		result2 error
+	}
+	ListStampedObjectsStub        func(schema.GroupVersionKind, string, types.UID, string) ([]*unstructured.Unstructured, error)
+	listStampedObjectsMutex       sync.RWMutex
+	listStampedObjectsArgsForCall []struct {
+		arg1 schema.GroupVersionKind
+		arg2 string
+		arg3 types.UID
+		arg4 string
+	}
+	listStampedObjectsReturns struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}
+	listStampedObjectsReturnsOnCall map[int]struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}
+	ListSupplyChainsStub        func() ([]v1alpha1.ClusterSupplyChain, error)
+	listSupplyChainsMutex       sync.RWMutex
+	listSupplyChainsArgsForCall []struct {
+	}
+	listSupplyChainsReturns struct {
+		result1 []v1alpha1.ClusterSupplyChain
+		result2 error
+	}
+	listSupplyChainsReturnsOnCall map[int]struct {
+		result1 []v1alpha1.ClusterSupplyChain
+		result2 error
+	}
 	ListUnstructuredStub        func(*unstructured.Unstructured) ([]*unstructured.Unstructured, error)
 	listUnstructuredMutex       sync.RWMutex
 	listUnstructuredArgsForCall []struct {
@@ -128,6 +341,18 @@ type FakeRepository struct {
 		result1 []*unstructured.Unstructured
 		result2 error
 	}
+	ListWorkloadsStub        func() ([]v1alpha1.Workload, error)
+	listWorkloadsMutex       sync.RWMutex
+	listWorkloadsArgsForCall []struct {
+	}
+	listWorkloadsReturns struct {
+		result1 []v1alpha1.Workload
+		result2 error
+	}
+	listWorkloadsReturnsOnCall map[int]struct {
+		result1 []v1alpha1.Workload
+		result2 error
+	}
 	StatusUpdateStub        func(client.Object) error
 	statusUpdateMutex       sync.RWMutex
 	statusUpdateArgsForCall []struct {
@@ -139,23 +364,158 @@ type FakeRepository struct {
 	statusUpdateReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateStub        func(client.Object) error
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		arg1 client.Object
+	}
+	updateReturns struct {
+		result1 error
+	}
+	updateReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeRepository) EnsureObjectExistsOnCluster(arg1 *unstructured.Unstructured, arg2 bool) error {
+func (fake *FakeRepository) AdoptObjectOnCluster(arg1 *unstructured.Unstructured, arg2 ...repository.ApplyOption) error {
+	fake.adoptObjectOnClusterMutex.Lock()
+	ret, specificReturn := fake.adoptObjectOnClusterReturnsOnCall[len(fake.adoptObjectOnClusterArgsForCall)]
+	fake.adoptObjectOnClusterArgsForCall = append(fake.adoptObjectOnClusterArgsForCall, struct {
+		arg1 *unstructured.Unstructured
+		arg2 []repository.ApplyOption
+	}{arg1, arg2})
+	stub := fake.AdoptObjectOnClusterStub
+	fakeReturns := fake.adoptObjectOnClusterReturns
+	fake.recordInvocation("AdoptObjectOnCluster", []interface{}{arg1, arg2})
+	fake.adoptObjectOnClusterMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) AdoptObjectOnClusterCallCount() int {
+	fake.adoptObjectOnClusterMutex.RLock()
+	defer fake.adoptObjectOnClusterMutex.RUnlock()
+	return len(fake.adoptObjectOnClusterArgsForCall)
+}
+
+func (fake *FakeRepository) AdoptObjectOnClusterCalls(stub func(*unstructured.Unstructured, ...repository.ApplyOption) error) {
+	fake.adoptObjectOnClusterMutex.Lock()
+	defer fake.adoptObjectOnClusterMutex.Unlock()
+	fake.AdoptObjectOnClusterStub = stub
+}
+
+func (fake *FakeRepository) AdoptObjectOnClusterArgsForCall(i int) (*unstructured.Unstructured, []repository.ApplyOption) {
+	fake.adoptObjectOnClusterMutex.RLock()
+	defer fake.adoptObjectOnClusterMutex.RUnlock()
+	argsForCall := fake.adoptObjectOnClusterArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) AdoptObjectOnClusterReturns(result1 error) {
+	fake.adoptObjectOnClusterMutex.Lock()
+	defer fake.adoptObjectOnClusterMutex.Unlock()
+	fake.AdoptObjectOnClusterStub = nil
+	fake.adoptObjectOnClusterReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) AdoptObjectOnClusterReturnsOnCall(i int, result1 error) {
+	fake.adoptObjectOnClusterMutex.Lock()
+	defer fake.adoptObjectOnClusterMutex.Unlock()
+	fake.AdoptObjectOnClusterStub = nil
+	if fake.adoptObjectOnClusterReturnsOnCall == nil {
+		fake.adoptObjectOnClusterReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.adoptObjectOnClusterReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) Delete(arg1 *unstructured.Unstructured) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 *unstructured.Unstructured
+	}{arg1})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeRepository) DeleteCalls(stub func(*unstructured.Unstructured) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeRepository) DeleteArgsForCall(i int) *unstructured.Unstructured {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) EnsureObjectExistsOnCluster(arg1 *unstructured.Unstructured, arg2 bool, arg3 ...repository.ApplyOption) error {
 	fake.ensureObjectExistsOnClusterMutex.Lock()
 	ret, specificReturn := fake.ensureObjectExistsOnClusterReturnsOnCall[len(fake.ensureObjectExistsOnClusterArgsForCall)]
 	fake.ensureObjectExistsOnClusterArgsForCall = append(fake.ensureObjectExistsOnClusterArgsForCall, struct {
 		arg1 *unstructured.Unstructured
 		arg2 bool
-	}{arg1, arg2})
+		arg3 []repository.ApplyOption
+	}{arg1, arg2, arg3})
 	stub := fake.EnsureObjectExistsOnClusterStub
 	fakeReturns := fake.ensureObjectExistsOnClusterReturns
-	fake.recordInvocation("EnsureObjectExistsOnCluster", []interface{}{arg1, arg2})
+	fake.recordInvocation("EnsureObjectExistsOnCluster", []interface{}{arg1, arg2, arg3})
 	fake.ensureObjectExistsOnClusterMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3...)
 	}
 	if specificReturn {
 		return ret.result1
@@ -169,17 +529,17 @@ func (fake *FakeRepository) EnsureObjectExistsOnClusterCallCount() int {
 	return len(fake.ensureObjectExistsOnClusterArgsForCall)
 }
 
-func (fake *FakeRepository) EnsureObjectExistsOnClusterCalls(stub func(*unstructured.Unstructured, bool) error) {
+func (fake *FakeRepository) EnsureObjectExistsOnClusterCalls(stub func(*unstructured.Unstructured, bool, ...repository.ApplyOption) error) {
 	fake.ensureObjectExistsOnClusterMutex.Lock()
 	defer fake.ensureObjectExistsOnClusterMutex.Unlock()
 	fake.EnsureObjectExistsOnClusterStub = stub
 }
 
-func (fake *FakeRepository) EnsureObjectExistsOnClusterArgsForCall(i int) (*unstructured.Unstructured, bool) {
+func (fake *FakeRepository) EnsureObjectExistsOnClusterArgsForCall(i int) (*unstructured.Unstructured, bool, []repository.ApplyOption) {
 	fake.ensureObjectExistsOnClusterMutex.RLock()
 	defer fake.ensureObjectExistsOnClusterMutex.RUnlock()
 	argsForCall := fake.ensureObjectExistsOnClusterArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeRepository) EnsureObjectExistsOnClusterReturns(result1 error) {
@@ -269,6 +629,135 @@ func (fake *FakeRepository) GetClusterTemplateReturnsOnCall(i int, result1 templ
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) GetDefaultSupplyChainsForWorkload(arg1 *v1alpha1.Workload) ([]v1alpha1.ClusterSupplyChain, error) {
+	fake.getDefaultSupplyChainsForWorkloadMutex.Lock()
+	ret, specificReturn := fake.getDefaultSupplyChainsForWorkloadReturnsOnCall[len(fake.getDefaultSupplyChainsForWorkloadArgsForCall)]
+	fake.getDefaultSupplyChainsForWorkloadArgsForCall = append(fake.getDefaultSupplyChainsForWorkloadArgsForCall, struct {
+		arg1 *v1alpha1.Workload
+	}{arg1})
+	stub := fake.GetDefaultSupplyChainsForWorkloadStub
+	fakeReturns := fake.getDefaultSupplyChainsForWorkloadReturns
+	fake.recordInvocation("GetDefaultSupplyChainsForWorkload", []interface{}{arg1})
+	fake.getDefaultSupplyChainsForWorkloadMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetDefaultSupplyChainsForWorkloadCallCount() int {
+	fake.getDefaultSupplyChainsForWorkloadMutex.RLock()
+	defer fake.getDefaultSupplyChainsForWorkloadMutex.RUnlock()
+	return len(fake.getDefaultSupplyChainsForWorkloadArgsForCall)
+}
+
+func (fake *FakeRepository) GetDefaultSupplyChainsForWorkloadCalls(stub func(*v1alpha1.Workload) ([]v1alpha1.ClusterSupplyChain, error)) {
+	fake.getDefaultSupplyChainsForWorkloadMutex.Lock()
+	defer fake.getDefaultSupplyChainsForWorkloadMutex.Unlock()
+	fake.GetDefaultSupplyChainsForWorkloadStub = stub
+}
+
+func (fake *FakeRepository) GetDefaultSupplyChainsForWorkloadArgsForCall(i int) *v1alpha1.Workload {
+	fake.getDefaultSupplyChainsForWorkloadMutex.RLock()
+	defer fake.getDefaultSupplyChainsForWorkloadMutex.RUnlock()
+	argsForCall := fake.getDefaultSupplyChainsForWorkloadArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) GetDefaultSupplyChainsForWorkloadReturns(result1 []v1alpha1.ClusterSupplyChain, result2 error) {
+	fake.getDefaultSupplyChainsForWorkloadMutex.Lock()
+	defer fake.getDefaultSupplyChainsForWorkloadMutex.Unlock()
+	fake.GetDefaultSupplyChainsForWorkloadStub = nil
+	fake.getDefaultSupplyChainsForWorkloadReturns = struct {
+		result1 []v1alpha1.ClusterSupplyChain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetDefaultSupplyChainsForWorkloadReturnsOnCall(i int, result1 []v1alpha1.ClusterSupplyChain, result2 error) {
+	fake.getDefaultSupplyChainsForWorkloadMutex.Lock()
+	defer fake.getDefaultSupplyChainsForWorkloadMutex.Unlock()
+	fake.GetDefaultSupplyChainsForWorkloadStub = nil
+	if fake.getDefaultSupplyChainsForWorkloadReturnsOnCall == nil {
+		fake.getDefaultSupplyChainsForWorkloadReturnsOnCall = make(map[int]struct {
+			result1 []v1alpha1.ClusterSupplyChain
+			result2 error
+		})
+	}
+	fake.getDefaultSupplyChainsForWorkloadReturnsOnCall[i] = struct {
+		result1 []v1alpha1.ClusterSupplyChain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetNamespacedSupplyChain(arg1 string, arg2 string) (*v1alpha1.SupplyChain, error) {
+	fake.getNamespacedSupplyChainMutex.Lock()
+	ret, specificReturn := fake.getNamespacedSupplyChainReturnsOnCall[len(fake.getNamespacedSupplyChainArgsForCall)]
+	fake.getNamespacedSupplyChainArgsForCall = append(fake.getNamespacedSupplyChainArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetNamespacedSupplyChainStub
+	fakeReturns := fake.getNamespacedSupplyChainReturns
+	fake.recordInvocation("GetNamespacedSupplyChain", []interface{}{arg1, arg2})
+	fake.getNamespacedSupplyChainMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetNamespacedSupplyChainCallCount() int {
+	fake.getNamespacedSupplyChainMutex.RLock()
+	defer fake.getNamespacedSupplyChainMutex.RUnlock()
+	return len(fake.getNamespacedSupplyChainArgsForCall)
+}
+
+func (fake *FakeRepository) GetNamespacedSupplyChainCalls(stub func(string, string) (*v1alpha1.SupplyChain, error)) {
+	fake.getNamespacedSupplyChainMutex.Lock()
+	defer fake.getNamespacedSupplyChainMutex.Unlock()
+	fake.GetNamespacedSupplyChainStub = stub
+}
+
+func (fake *FakeRepository) GetNamespacedSupplyChainArgsForCall(i int) (string, string) {
+	fake.getNamespacedSupplyChainMutex.RLock()
+	defer fake.getNamespacedSupplyChainMutex.RUnlock()
+	argsForCall := fake.getNamespacedSupplyChainArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) GetNamespacedSupplyChainReturns(result1 *v1alpha1.SupplyChain, result2 error) {
+	fake.getNamespacedSupplyChainMutex.Lock()
+	defer fake.getNamespacedSupplyChainMutex.Unlock()
+	fake.GetNamespacedSupplyChainStub = nil
+	fake.getNamespacedSupplyChainReturns = struct {
+		result1 *v1alpha1.SupplyChain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetNamespacedSupplyChainReturnsOnCall(i int, result1 *v1alpha1.SupplyChain, result2 error) {
+	fake.getNamespacedSupplyChainMutex.Lock()
+	defer fake.getNamespacedSupplyChainMutex.Unlock()
+	fake.GetNamespacedSupplyChainStub = nil
+	if fake.getNamespacedSupplyChainReturnsOnCall == nil {
+		fake.getNamespacedSupplyChainReturnsOnCall = make(map[int]struct {
+			result1 *v1alpha1.SupplyChain
+			result2 error
+		})
+	}
+	fake.getNamespacedSupplyChainReturnsOnCall[i] = struct {
+		result1 *v1alpha1.SupplyChain
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) GetPipeline(arg1 string, arg2 string) (*v1alpha1.Pipeline, error) {
 	fake.getPipelineMutex.Lock()
 	ret, specificReturn := fake.getPipelineReturnsOnCall[len(fake.getPipelineArgsForCall)]
@@ -334,16 +823,16 @@ func (fake *FakeRepository) GetPipelineReturnsOnCall(i int, result1 *v1alpha1.Pi
 	}{result1, result2}
 }
 
-func (fake *FakeRepository) GetRunTemplate(arg1 v1alpha1.TemplateReference) (templates.RunTemplate, error) {
-	fake.getRunTemplateMutex.Lock()
-	ret, specificReturn := fake.getRunTemplateReturnsOnCall[len(fake.getRunTemplateArgsForCall)]
-	fake.getRunTemplateArgsForCall = append(fake.getRunTemplateArgsForCall, struct {
-		arg1 v1alpha1.TemplateReference
+func (fake *FakeRepository) GetRealizationReport(arg1 string) (*v1alpha1.RealizationReport, error) {
+	fake.getRealizationReportMutex.Lock()
+	ret, specificReturn := fake.getRealizationReportReturnsOnCall[len(fake.getRealizationReportArgsForCall)]
+	fake.getRealizationReportArgsForCall = append(fake.getRealizationReportArgsForCall, struct {
+		arg1 string
 	}{arg1})
-	stub := fake.GetRunTemplateStub
-	fakeReturns := fake.getRunTemplateReturns
-	fake.recordInvocation("GetRunTemplate", []interface{}{arg1})
-	fake.getRunTemplateMutex.Unlock()
+	stub := fake.GetRealizationReportStub
+	fakeReturns := fake.getRealizationReportReturns
+	fake.recordInvocation("GetRealizationReport", []interface{}{arg1})
+	fake.getRealizationReportMutex.Unlock()
 	if stub != nil {
 		return stub(arg1)
 	}
@@ -353,15 +842,79 @@ func (fake *FakeRepository) GetRunTemplate(arg1 v1alpha1.TemplateReference) (tem
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeRepository) GetRunTemplateCallCount() int {
-	fake.getRunTemplateMutex.RLock()
-	defer fake.getRunTemplateMutex.RUnlock()
-	return len(fake.getRunTemplateArgsForCall)
+func (fake *FakeRepository) GetRealizationReportCallCount() int {
+	fake.getRealizationReportMutex.RLock()
+	defer fake.getRealizationReportMutex.RUnlock()
+	return len(fake.getRealizationReportArgsForCall)
 }
 
-func (fake *FakeRepository) GetRunTemplateCalls(stub func(v1alpha1.TemplateReference) (templates.RunTemplate, error)) {
-	fake.getRunTemplateMutex.Lock()
-	defer fake.getRunTemplateMutex.Unlock()
+func (fake *FakeRepository) GetRealizationReportCalls(stub func(string) (*v1alpha1.RealizationReport, error)) {
+	fake.getRealizationReportMutex.Lock()
+	defer fake.getRealizationReportMutex.Unlock()
+	fake.GetRealizationReportStub = stub
+}
+
+func (fake *FakeRepository) GetRealizationReportArgsForCall(i int) string {
+	fake.getRealizationReportMutex.RLock()
+	defer fake.getRealizationReportMutex.RUnlock()
+	argsForCall := fake.getRealizationReportArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) GetRealizationReportReturns(result1 *v1alpha1.RealizationReport, result2 error) {
+	fake.getRealizationReportMutex.Lock()
+	defer fake.getRealizationReportMutex.Unlock()
+	fake.GetRealizationReportStub = nil
+	fake.getRealizationReportReturns = struct {
+		result1 *v1alpha1.RealizationReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetRealizationReportReturnsOnCall(i int, result1 *v1alpha1.RealizationReport, result2 error) {
+	fake.getRealizationReportMutex.Lock()
+	defer fake.getRealizationReportMutex.Unlock()
+	fake.GetRealizationReportStub = nil
+	if fake.getRealizationReportReturnsOnCall == nil {
+		fake.getRealizationReportReturnsOnCall = make(map[int]struct {
+			result1 *v1alpha1.RealizationReport
+			result2 error
+		})
+	}
+	fake.getRealizationReportReturnsOnCall[i] = struct {
+		result1 *v1alpha1.RealizationReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetRunTemplate(arg1 v1alpha1.TemplateReference) (templates.RunTemplate, error) {
+	fake.getRunTemplateMutex.Lock()
+	ret, specificReturn := fake.getRunTemplateReturnsOnCall[len(fake.getRunTemplateArgsForCall)]
+	fake.getRunTemplateArgsForCall = append(fake.getRunTemplateArgsForCall, struct {
+		arg1 v1alpha1.TemplateReference
+	}{arg1})
+	stub := fake.GetRunTemplateStub
+	fakeReturns := fake.getRunTemplateReturns
+	fake.recordInvocation("GetRunTemplate", []interface{}{arg1})
+	fake.getRunTemplateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetRunTemplateCallCount() int {
+	fake.getRunTemplateMutex.RLock()
+	defer fake.getRunTemplateMutex.RUnlock()
+	return len(fake.getRunTemplateArgsForCall)
+}
+
+func (fake *FakeRepository) GetRunTemplateCalls(stub func(v1alpha1.TemplateReference) (templates.RunTemplate, error)) {
+	fake.getRunTemplateMutex.Lock()
+	defer fake.getRunTemplateMutex.Unlock()
 	fake.GetRunTemplateStub = stub
 }
 
@@ -451,6 +1004,71 @@ func (fake *FakeRepository) GetSchemeReturnsOnCall(i int, result1 *runtime.Schem
 	}{result1}
 }
 
+func (fake *FakeRepository) GetSecretData(arg1 string, arg2 string) (map[string][]byte, error) {
+	fake.getSecretDataMutex.Lock()
+	ret, specificReturn := fake.getSecretDataReturnsOnCall[len(fake.getSecretDataArgsForCall)]
+	fake.getSecretDataArgsForCall = append(fake.getSecretDataArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetSecretDataStub
+	fakeReturns := fake.getSecretDataReturns
+	fake.recordInvocation("GetSecretData", []interface{}{arg1, arg2})
+	fake.getSecretDataMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetSecretDataCallCount() int {
+	fake.getSecretDataMutex.RLock()
+	defer fake.getSecretDataMutex.RUnlock()
+	return len(fake.getSecretDataArgsForCall)
+}
+
+func (fake *FakeRepository) GetSecretDataCalls(stub func(string, string) (map[string][]byte, error)) {
+	fake.getSecretDataMutex.Lock()
+	defer fake.getSecretDataMutex.Unlock()
+	fake.GetSecretDataStub = stub
+}
+
+func (fake *FakeRepository) GetSecretDataArgsForCall(i int) (string, string) {
+	fake.getSecretDataMutex.RLock()
+	defer fake.getSecretDataMutex.RUnlock()
+	argsForCall := fake.getSecretDataArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) GetSecretDataReturns(result1 map[string][]byte, result2 error) {
+	fake.getSecretDataMutex.Lock()
+	defer fake.getSecretDataMutex.Unlock()
+	fake.GetSecretDataStub = nil
+	fake.getSecretDataReturns = struct {
+		result1 map[string][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetSecretDataReturnsOnCall(i int, result1 map[string][]byte, result2 error) {
+	fake.getSecretDataMutex.Lock()
+	defer fake.getSecretDataMutex.Unlock()
+	fake.GetSecretDataStub = nil
+	if fake.getSecretDataReturnsOnCall == nil {
+		fake.getSecretDataReturnsOnCall = make(map[int]struct {
+			result1 map[string][]byte
+			result2 error
+		})
+	}
+	fake.getSecretDataReturnsOnCall[i] = struct {
+		result1 map[string][]byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) GetSupplyChain(arg1 string) (*v1alpha1.ClusterSupplyChain, error) {
 	fake.getSupplyChainMutex.Lock()
 	ret, specificReturn := fake.getSupplyChainReturnsOnCall[len(fake.getSupplyChainArgsForCall)]
@@ -579,6 +1197,136 @@ func (fake *FakeRepository) GetSupplyChainsForWorkloadReturnsOnCall(i int, resul
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) GetTemplate(arg1 v1alpha1.ClusterTemplateReference, arg2 string) (templates.Template, error) {
+	fake.getTemplateMutex.Lock()
+	ret, specificReturn := fake.getTemplateReturnsOnCall[len(fake.getTemplateArgsForCall)]
+	fake.getTemplateArgsForCall = append(fake.getTemplateArgsForCall, struct {
+		arg1 v1alpha1.ClusterTemplateReference
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetTemplateStub
+	fakeReturns := fake.getTemplateReturns
+	fake.recordInvocation("GetTemplate", []interface{}{arg1, arg2})
+	fake.getTemplateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetTemplateCallCount() int {
+	fake.getTemplateMutex.RLock()
+	defer fake.getTemplateMutex.RUnlock()
+	return len(fake.getTemplateArgsForCall)
+}
+
+func (fake *FakeRepository) GetTemplateCalls(stub func(v1alpha1.ClusterTemplateReference, string) (templates.Template, error)) {
+	fake.getTemplateMutex.Lock()
+	defer fake.getTemplateMutex.Unlock()
+	fake.GetTemplateStub = stub
+}
+
+func (fake *FakeRepository) GetTemplateArgsForCall(i int) (v1alpha1.ClusterTemplateReference, string) {
+	fake.getTemplateMutex.RLock()
+	defer fake.getTemplateMutex.RUnlock()
+	argsForCall := fake.getTemplateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) GetTemplateReturns(result1 templates.Template, result2 error) {
+	fake.getTemplateMutex.Lock()
+	defer fake.getTemplateMutex.Unlock()
+	fake.GetTemplateStub = nil
+	fake.getTemplateReturns = struct {
+		result1 templates.Template
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetTemplateReturnsOnCall(i int, result1 templates.Template, result2 error) {
+	fake.getTemplateMutex.Lock()
+	defer fake.getTemplateMutex.Unlock()
+	fake.GetTemplateStub = nil
+	if fake.getTemplateReturnsOnCall == nil {
+		fake.getTemplateReturnsOnCall = make(map[int]struct {
+			result1 templates.Template
+			result2 error
+		})
+	}
+	fake.getTemplateReturnsOnCall[i] = struct {
+		result1 templates.Template
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetTemplatePlayground(arg1 string, arg2 string) (*v1alpha1.TemplatePlayground, error) {
+	fake.getTemplatePlaygroundMutex.Lock()
+	ret, specificReturn := fake.getTemplatePlaygroundReturnsOnCall[len(fake.getTemplatePlaygroundArgsForCall)]
+	fake.getTemplatePlaygroundArgsForCall = append(fake.getTemplatePlaygroundArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetTemplatePlaygroundStub
+	fakeReturns := fake.getTemplatePlaygroundReturns
+	fake.recordInvocation("GetTemplatePlayground", []interface{}{arg1, arg2})
+	fake.getTemplatePlaygroundMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetTemplatePlaygroundCallCount() int {
+	fake.getTemplatePlaygroundMutex.RLock()
+	defer fake.getTemplatePlaygroundMutex.RUnlock()
+	return len(fake.getTemplatePlaygroundArgsForCall)
+}
+
+func (fake *FakeRepository) GetTemplatePlaygroundCalls(stub func(string, string) (*v1alpha1.TemplatePlayground, error)) {
+	fake.getTemplatePlaygroundMutex.Lock()
+	defer fake.getTemplatePlaygroundMutex.Unlock()
+	fake.GetTemplatePlaygroundStub = stub
+}
+
+func (fake *FakeRepository) GetTemplatePlaygroundArgsForCall(i int) (string, string) {
+	fake.getTemplatePlaygroundMutex.RLock()
+	defer fake.getTemplatePlaygroundMutex.RUnlock()
+	argsForCall := fake.getTemplatePlaygroundArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) GetTemplatePlaygroundReturns(result1 *v1alpha1.TemplatePlayground, result2 error) {
+	fake.getTemplatePlaygroundMutex.Lock()
+	defer fake.getTemplatePlaygroundMutex.Unlock()
+	fake.GetTemplatePlaygroundStub = nil
+	fake.getTemplatePlaygroundReturns = struct {
+		result1 *v1alpha1.TemplatePlayground
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetTemplatePlaygroundReturnsOnCall(i int, result1 *v1alpha1.TemplatePlayground, result2 error) {
+	fake.getTemplatePlaygroundMutex.Lock()
+	defer fake.getTemplatePlaygroundMutex.Unlock()
+	fake.GetTemplatePlaygroundStub = nil
+	if fake.getTemplatePlaygroundReturnsOnCall == nil {
+		fake.getTemplatePlaygroundReturnsOnCall = make(map[int]struct {
+			result1 *v1alpha1.TemplatePlayground
+			result2 error
+		})
+	}
+	fake.getTemplatePlaygroundReturnsOnCall[i] = struct {
+		result1 *v1alpha1.TemplatePlayground
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) GetWorkload(arg1 string, arg2 string) (*v1alpha1.Workload, error) {
 	fake.getWorkloadMutex.Lock()
 	ret, specificReturn := fake.getWorkloadReturnsOnCall[len(fake.getWorkloadArgsForCall)]
@@ -644,6 +1392,491 @@ func (fake *FakeRepository) GetWorkloadReturnsOnCall(i int, result1 *v1alpha1.Wo
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) GetWorkloadPreview(arg1 string, arg2 string) (*v1alpha1.WorkloadPreview, error) {
+	fake.getWorkloadPreviewMutex.Lock()
+	ret, specificReturn := fake.getWorkloadPreviewReturnsOnCall[len(fake.getWorkloadPreviewArgsForCall)]
+	fake.getWorkloadPreviewArgsForCall = append(fake.getWorkloadPreviewArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetWorkloadPreviewStub
+	fakeReturns := fake.getWorkloadPreviewReturns
+	fake.recordInvocation("GetWorkloadPreview", []interface{}{arg1, arg2})
+	fake.getWorkloadPreviewMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) GetWorkloadPreviewCallCount() int {
+	fake.getWorkloadPreviewMutex.RLock()
+	defer fake.getWorkloadPreviewMutex.RUnlock()
+	return len(fake.getWorkloadPreviewArgsForCall)
+}
+
+func (fake *FakeRepository) GetWorkloadPreviewCalls(stub func(string, string) (*v1alpha1.WorkloadPreview, error)) {
+	fake.getWorkloadPreviewMutex.Lock()
+	defer fake.getWorkloadPreviewMutex.Unlock()
+	fake.GetWorkloadPreviewStub = stub
+}
+
+func (fake *FakeRepository) GetWorkloadPreviewArgsForCall(i int) (string, string) {
+	fake.getWorkloadPreviewMutex.RLock()
+	defer fake.getWorkloadPreviewMutex.RUnlock()
+	argsForCall := fake.getWorkloadPreviewArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) GetWorkloadPreviewReturns(result1 *v1alpha1.WorkloadPreview, result2 error) {
+	fake.getWorkloadPreviewMutex.Lock()
+	defer fake.getWorkloadPreviewMutex.Unlock()
+	fake.GetWorkloadPreviewStub = nil
+	fake.getWorkloadPreviewReturns = struct {
+		result1 *v1alpha1.WorkloadPreview
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) GetWorkloadPreviewReturnsOnCall(i int, result1 *v1alpha1.WorkloadPreview, result2 error) {
+	fake.getWorkloadPreviewMutex.Lock()
+	defer fake.getWorkloadPreviewMutex.Unlock()
+	fake.GetWorkloadPreviewStub = nil
+	if fake.getWorkloadPreviewReturnsOnCall == nil {
+		fake.getWorkloadPreviewReturnsOnCall = make(map[int]struct {
+			result1 *v1alpha1.WorkloadPreview
+			result2 error
+		})
+	}
+	fake.getWorkloadPreviewReturnsOnCall[i] = struct {
+		result1 *v1alpha1.WorkloadPreview
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) IsNamespaced(arg1 schema.GroupVersionKind) (bool, error) {
+	fake.isNamespacedMutex.Lock()
+	ret, specificReturn := fake.isNamespacedReturnsOnCall[len(fake.isNamespacedArgsForCall)]
+	fake.isNamespacedArgsForCall = append(fake.isNamespacedArgsForCall, struct {
+		arg1 schema.GroupVersionKind
+	}{arg1})
+	stub := fake.IsNamespacedStub
+	fakeReturns := fake.isNamespacedReturns
+	fake.recordInvocation("IsNamespaced", []interface{}{arg1})
+	fake.isNamespacedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) IsNamespacedCallCount() int {
+	fake.isNamespacedMutex.RLock()
+	defer fake.isNamespacedMutex.RUnlock()
+	return len(fake.isNamespacedArgsForCall)
+}
+
+func (fake *FakeRepository) IsNamespacedCalls(stub func(schema.GroupVersionKind) (bool, error)) {
+	fake.isNamespacedMutex.Lock()
+	defer fake.isNamespacedMutex.Unlock()
+	fake.IsNamespacedStub = stub
+}
+
+func (fake *FakeRepository) IsNamespacedArgsForCall(i int) schema.GroupVersionKind {
+	fake.isNamespacedMutex.RLock()
+	defer fake.isNamespacedMutex.RUnlock()
+	argsForCall := fake.isNamespacedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) IsNamespacedReturns(result1 bool, result2 error) {
+	fake.isNamespacedMutex.Lock()
+	defer fake.isNamespacedMutex.Unlock()
+	fake.IsNamespacedStub = nil
+	fake.isNamespacedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) IsNamespacedReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.isNamespacedMutex.Lock()
+	defer fake.isNamespacedMutex.Unlock()
+	fake.IsNamespacedStub = nil
+	if fake.isNamespacedReturnsOnCall == nil {
+		fake.isNamespacedReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.isNamespacedReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListDependents(arg1 *unstructured.Unstructured, arg2 schema.GroupVersionKind) ([]*unstructured.Unstructured, error) {
+	fake.listDependentsMutex.Lock()
+	ret, specificReturn := fake.listDependentsReturnsOnCall[len(fake.listDependentsArgsForCall)]
+	fake.listDependentsArgsForCall = append(fake.listDependentsArgsForCall, struct {
+		arg1 *unstructured.Unstructured
+		arg2 schema.GroupVersionKind
+	}{arg1, arg2})
+	stub := fake.ListDependentsStub
+	fakeReturns := fake.listDependentsReturns
+	fake.recordInvocation("ListDependents", []interface{}{arg1, arg2})
+	fake.listDependentsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ListDependentsCallCount() int {
+	fake.listDependentsMutex.RLock()
+	defer fake.listDependentsMutex.RUnlock()
+	return len(fake.listDependentsArgsForCall)
+}
+
+func (fake *FakeRepository) ListDependentsCalls(stub func(*unstructured.Unstructured, schema.GroupVersionKind) ([]*unstructured.Unstructured, error)) {
+	fake.listDependentsMutex.Lock()
+	defer fake.listDependentsMutex.Unlock()
+	fake.ListDependentsStub = stub
+}
+
+func (fake *FakeRepository) ListDependentsArgsForCall(i int) (*unstructured.Unstructured, schema.GroupVersionKind) {
+	fake.listDependentsMutex.RLock()
+	defer fake.listDependentsMutex.RUnlock()
+	argsForCall := fake.listDependentsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRepository) ListDependentsReturns(result1 []*unstructured.Unstructured, result2 error) {
+	fake.listDependentsMutex.Lock()
+	defer fake.listDependentsMutex.Unlock()
+	fake.ListDependentsStub = nil
+	fake.listDependentsReturns = struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListDependentsReturnsOnCall(i int, result1 []*unstructured.Unstructured, result2 error) {
+	fake.listDependentsMutex.Lock()
+	defer fake.listDependentsMutex.Unlock()
+	fake.ListDependentsStub = nil
+	if fake.listDependentsReturnsOnCall == nil {
+		fake.listDependentsReturnsOnCall = make(map[int]struct {
+			result1 []*unstructured.Unstructured
+			result2 error
+		})
+	}
+	fake.listDependentsReturnsOnCall[i] = struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListNamespacedSupplyChains() ([]v1alpha1.SupplyChain, error) {
+	fake.listNamespacedSupplyChainsMutex.Lock()
+	ret, specificReturn := fake.listNamespacedSupplyChainsReturnsOnCall[len(fake.listNamespacedSupplyChainsArgsForCall)]
+	fake.listNamespacedSupplyChainsArgsForCall = append(fake.listNamespacedSupplyChainsArgsForCall, struct {
+	}{})
+	stub := fake.ListNamespacedSupplyChainsStub
+	fakeReturns := fake.listNamespacedSupplyChainsReturns
+	fake.recordInvocation("ListNamespacedSupplyChains", []interface{}{})
+	fake.listNamespacedSupplyChainsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ListNamespacedSupplyChainsCallCount() int {
+	fake.listNamespacedSupplyChainsMutex.RLock()
+	defer fake.listNamespacedSupplyChainsMutex.RUnlock()
+	return len(fake.listNamespacedSupplyChainsArgsForCall)
+}
+
+func (fake *FakeRepository) ListNamespacedSupplyChainsCalls(stub func() ([]v1alpha1.SupplyChain, error)) {
+	fake.listNamespacedSupplyChainsMutex.Lock()
+	defer fake.listNamespacedSupplyChainsMutex.Unlock()
+	fake.ListNamespacedSupplyChainsStub = stub
+}
+
+func (fake *FakeRepository) ListNamespacedSupplyChainsReturns(result1 []v1alpha1.SupplyChain, result2 error) {
+	fake.listNamespacedSupplyChainsMutex.Lock()
+	defer fake.listNamespacedSupplyChainsMutex.Unlock()
+	fake.ListNamespacedSupplyChainsStub = nil
+	fake.listNamespacedSupplyChainsReturns = struct {
+		result1 []v1alpha1.SupplyChain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListNamespacedSupplyChainsReturnsOnCall(i int, result1 []v1alpha1.SupplyChain, result2 error) {
+	fake.listNamespacedSupplyChainsMutex.Lock()
+	defer fake.listNamespacedSupplyChainsMutex.Unlock()
+	fake.ListNamespacedSupplyChainsStub = nil
+	if fake.listNamespacedSupplyChainsReturnsOnCall == nil {
+		fake.listNamespacedSupplyChainsReturnsOnCall = make(map[int]struct {
+			result1 []v1alpha1.SupplyChain
+			result2 error
+		})
+	}
+	fake.listNamespacedSupplyChainsReturnsOnCall[i] = struct {
+		result1 []v1alpha1.SupplyChain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListPipelines() ([]v1alpha1.Pipeline, error) {
+	fake.listPipelinesMutex.Lock()
+	ret, specificReturn := fake.listPipelinesReturnsOnCall[len(fake.listPipelinesArgsForCall)]
+	fake.listPipelinesArgsForCall = append(fake.listPipelinesArgsForCall, struct {
+	}{})
+	stub := fake.ListPipelinesStub
+	fakeReturns := fake.listPipelinesReturns
+	fake.recordInvocation("ListPipelines", []interface{}{})
+	fake.listPipelinesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ListPipelinesCallCount() int {
+	fake.listPipelinesMutex.RLock()
+	defer fake.listPipelinesMutex.RUnlock()
+	return len(fake.listPipelinesArgsForCall)
+}
+
+func (fake *FakeRepository) ListPipelinesCalls(stub func() ([]v1alpha1.Pipeline, error)) {
+	fake.listPipelinesMutex.Lock()
+	defer fake.listPipelinesMutex.Unlock()
+	fake.ListPipelinesStub = stub
+}
+
+func (fake *FakeRepository) ListPipelinesReturns(result1 []v1alpha1.Pipeline, result2 error) {
+	fake.listPipelinesMutex.Lock()
+	defer fake.listPipelinesMutex.Unlock()
+	fake.ListPipelinesStub = nil
+	fake.listPipelinesReturns = struct {
+		result1 []v1alpha1.Pipeline
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListPipelinesReturnsOnCall(i int, result1 []v1alpha1.Pipeline, result2 error) {
+	fake.listPipelinesMutex.Lock()
+	defer fake.listPipelinesMutex.Unlock()
+	fake.ListPipelinesStub = nil
+	if fake.listPipelinesReturnsOnCall == nil {
+		fake.listPipelinesReturnsOnCall = make(map[int]struct {
+			result1 []v1alpha1.Pipeline
+			result2 error
+		})
+	}
+	fake.listPipelinesReturnsOnCall[i] = struct {
+		result1 []v1alpha1.Pipeline
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListStampPolicies() ([]v1alpha1.ClusterStampPolicy, error) {
+	fake.listStampPoliciesMutex.Lock()
+	ret, specificReturn := fake.listStampPoliciesReturnsOnCall[len(fake.listStampPoliciesArgsForCall)]
+	fake.listStampPoliciesArgsForCall = append(fake.listStampPoliciesArgsForCall, struct {
+	}{})
+	stub := fake.ListStampPoliciesStub
+	fakeReturns := fake.listStampPoliciesReturns
+	fake.recordInvocation("ListStampPolicies", []interface{}{})
+	fake.listStampPoliciesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ListStampPoliciesCallCount() int {
+	fake.listStampPoliciesMutex.RLock()
+	defer fake.listStampPoliciesMutex.RUnlock()
+	return len(fake.listStampPoliciesArgsForCall)
+}
+
+func (fake *FakeRepository) ListStampPoliciesCalls(stub func() ([]v1alpha1.ClusterStampPolicy, error)) {
+	fake.listStampPoliciesMutex.Lock()
+	defer fake.listStampPoliciesMutex.Unlock()
+	fake.ListStampPoliciesStub = stub
+}
+
+func (fake *FakeRepository) ListStampPoliciesReturns(result1 []v1alpha1.ClusterStampPolicy, result2 error) {
+	fake.listStampPoliciesMutex.Lock()
+	defer fake.listStampPoliciesMutex.Unlock()
+	fake.ListStampPoliciesStub = nil
+	fake.listStampPoliciesReturns = struct {
+		result1 []v1alpha1.ClusterStampPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListStampPoliciesReturnsOnCall(i int, result1 []v1alpha1.ClusterStampPolicy, result2 error) {
+	fake.listStampPoliciesMutex.Lock()
+	defer fake.listStampPoliciesMutex.Unlock()
+	fake.ListStampPoliciesStub = nil
+	if fake.listStampPoliciesReturnsOnCall == nil {
+		fake.listStampPoliciesReturnsOnCall = make(map[int]struct {
+			result1 []v1alpha1.ClusterStampPolicy
+			result2 error
+		})
+	}
+	fake.listStampPoliciesReturnsOnCall[i] = struct {
+		result1 []v1alpha1.ClusterStampPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListStampedObjects(arg1 schema.GroupVersionKind, arg2 string, arg3 types.UID, arg4 string) ([]*unstructured.Unstructured, error) {
+	fake.listStampedObjectsMutex.Lock()
+	ret, specificReturn := fake.listStampedObjectsReturnsOnCall[len(fake.listStampedObjectsArgsForCall)]
+	fake.listStampedObjectsArgsForCall = append(fake.listStampedObjectsArgsForCall, struct {
+		arg1 schema.GroupVersionKind
+		arg2 string
+		arg3 types.UID
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ListStampedObjectsStub
+	fakeReturns := fake.listStampedObjectsReturns
+	fake.recordInvocation("ListStampedObjects", []interface{}{arg1, arg2, arg3, arg4})
+	fake.listStampedObjectsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ListStampedObjectsCallCount() int {
+	fake.listStampedObjectsMutex.RLock()
+	defer fake.listStampedObjectsMutex.RUnlock()
+	return len(fake.listStampedObjectsArgsForCall)
+}
+
+func (fake *FakeRepository) ListStampedObjectsCalls(stub func(schema.GroupVersionKind, string, types.UID, string) ([]*unstructured.Unstructured, error)) {
+	fake.listStampedObjectsMutex.Lock()
+	defer fake.listStampedObjectsMutex.Unlock()
+	fake.ListStampedObjectsStub = stub
+}
+
+func (fake *FakeRepository) ListStampedObjectsArgsForCall(i int) (schema.GroupVersionKind, string, types.UID, string) {
+	fake.listStampedObjectsMutex.RLock()
+	defer fake.listStampedObjectsMutex.RUnlock()
+	argsForCall := fake.listStampedObjectsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeRepository) ListStampedObjectsReturns(result1 []*unstructured.Unstructured, result2 error) {
+	fake.listStampedObjectsMutex.Lock()
+	defer fake.listStampedObjectsMutex.Unlock()
+	fake.ListStampedObjectsStub = nil
+	fake.listStampedObjectsReturns = struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListStampedObjectsReturnsOnCall(i int, result1 []*unstructured.Unstructured, result2 error) {
+	fake.listStampedObjectsMutex.Lock()
+	defer fake.listStampedObjectsMutex.Unlock()
+	fake.ListStampedObjectsStub = nil
+	if fake.listStampedObjectsReturnsOnCall == nil {
+		fake.listStampedObjectsReturnsOnCall = make(map[int]struct {
+			result1 []*unstructured.Unstructured
+			result2 error
+		})
+	}
+	fake.listStampedObjectsReturnsOnCall[i] = struct {
+		result1 []*unstructured.Unstructured
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListSupplyChains() ([]v1alpha1.ClusterSupplyChain, error) {
+	fake.listSupplyChainsMutex.Lock()
+	ret, specificReturn := fake.listSupplyChainsReturnsOnCall[len(fake.listSupplyChainsArgsForCall)]
+	fake.listSupplyChainsArgsForCall = append(fake.listSupplyChainsArgsForCall, struct {
+	}{})
+	stub := fake.ListSupplyChainsStub
+	fakeReturns := fake.listSupplyChainsReturns
+	fake.recordInvocation("ListSupplyChains", []interface{}{})
+	fake.listSupplyChainsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ListSupplyChainsCallCount() int {
+	fake.listSupplyChainsMutex.RLock()
+	defer fake.listSupplyChainsMutex.RUnlock()
+	return len(fake.listSupplyChainsArgsForCall)
+}
+
+func (fake *FakeRepository) ListSupplyChainsCalls(stub func() ([]v1alpha1.ClusterSupplyChain, error)) {
+	fake.listSupplyChainsMutex.Lock()
+	defer fake.listSupplyChainsMutex.Unlock()
+	fake.ListSupplyChainsStub = stub
+}
+
+func (fake *FakeRepository) ListSupplyChainsReturns(result1 []v1alpha1.ClusterSupplyChain, result2 error) {
+	fake.listSupplyChainsMutex.Lock()
+	defer fake.listSupplyChainsMutex.Unlock()
+	fake.ListSupplyChainsStub = nil
+	fake.listSupplyChainsReturns = struct {
+		result1 []v1alpha1.ClusterSupplyChain
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListSupplyChainsReturnsOnCall(i int, result1 []v1alpha1.ClusterSupplyChain, result2 error) {
+	fake.listSupplyChainsMutex.Lock()
+	defer fake.listSupplyChainsMutex.Unlock()
+	fake.ListSupplyChainsStub = nil
+	if fake.listSupplyChainsReturnsOnCall == nil {
+		fake.listSupplyChainsReturnsOnCall = make(map[int]struct {
+			result1 []v1alpha1.ClusterSupplyChain
+			result2 error
+		})
+	}
+	fake.listSupplyChainsReturnsOnCall[i] = struct {
+		result1 []v1alpha1.ClusterSupplyChain
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) ListUnstructured(arg1 *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
 	fake.listUnstructuredMutex.Lock()
 	ret, specificReturn := fake.listUnstructuredReturnsOnCall[len(fake.listUnstructuredArgsForCall)]
@@ -708,6 +1941,62 @@ func (fake *FakeRepository) ListUnstructuredReturnsOnCall(i int, result1 []*unst
 	}{result1, result2}
 }
 
+func (fake *FakeRepository) ListWorkloads() ([]v1alpha1.Workload, error) {
+	fake.listWorkloadsMutex.Lock()
+	ret, specificReturn := fake.listWorkloadsReturnsOnCall[len(fake.listWorkloadsArgsForCall)]
+	fake.listWorkloadsArgsForCall = append(fake.listWorkloadsArgsForCall, struct {
+	}{})
+	stub := fake.ListWorkloadsStub
+	fakeReturns := fake.listWorkloadsReturns
+	fake.recordInvocation("ListWorkloads", []interface{}{})
+	fake.listWorkloadsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRepository) ListWorkloadsCallCount() int {
+	fake.listWorkloadsMutex.RLock()
+	defer fake.listWorkloadsMutex.RUnlock()
+	return len(fake.listWorkloadsArgsForCall)
+}
+
+func (fake *FakeRepository) ListWorkloadsCalls(stub func() ([]v1alpha1.Workload, error)) {
+	fake.listWorkloadsMutex.Lock()
+	defer fake.listWorkloadsMutex.Unlock()
+	fake.ListWorkloadsStub = stub
+}
+
+func (fake *FakeRepository) ListWorkloadsReturns(result1 []v1alpha1.Workload, result2 error) {
+	fake.listWorkloadsMutex.Lock()
+	defer fake.listWorkloadsMutex.Unlock()
+	fake.ListWorkloadsStub = nil
+	fake.listWorkloadsReturns = struct {
+		result1 []v1alpha1.Workload
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRepository) ListWorkloadsReturnsOnCall(i int, result1 []v1alpha1.Workload, result2 error) {
+	fake.listWorkloadsMutex.Lock()
+	defer fake.listWorkloadsMutex.Unlock()
+	fake.ListWorkloadsStub = nil
+	if fake.listWorkloadsReturnsOnCall == nil {
+		fake.listWorkloadsReturnsOnCall = make(map[int]struct {
+			result1 []v1alpha1.Workload
+			result2 error
+		})
+	}
+	fake.listWorkloadsReturnsOnCall[i] = struct {
+		result1 []v1alpha1.Workload
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRepository) StatusUpdate(arg1 client.Object) error {
 	fake.statusUpdateMutex.Lock()
 	ret, specificReturn := fake.statusUpdateReturnsOnCall[len(fake.statusUpdateArgsForCall)]
@@ -769,29 +2058,126 @@ func (fake *FakeRepository) StatusUpdateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRepository) Update(arg1 client.Object) error {
+	fake.updateMutex.Lock()
+	ret, specificReturn := fake.updateReturnsOnCall[len(fake.updateArgsForCall)]
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		arg1 client.Object
+	}{arg1})
+	stub := fake.UpdateStub
+	fakeReturns := fake.updateReturns
+	fake.recordInvocation("Update", []interface{}{arg1})
+	fake.updateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRepository) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *FakeRepository) UpdateCalls(stub func(client.Object) error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = stub
+}
+
+func (fake *FakeRepository) UpdateArgsForCall(i int) client.Object {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	argsForCall := fake.updateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRepository) UpdateReturns(result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRepository) UpdateReturnsOnCall(i int, result1 error) {
+	fake.updateMutex.Lock()
+	defer fake.updateMutex.Unlock()
+	fake.UpdateStub = nil
+	if fake.updateReturnsOnCall == nil {
+		fake.updateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.adoptObjectOnClusterMutex.RLock()
+	defer fake.adoptObjectOnClusterMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
 	fake.ensureObjectExistsOnClusterMutex.RLock()
 	defer fake.ensureObjectExistsOnClusterMutex.RUnlock()
 	fake.getClusterTemplateMutex.RLock()
 	defer fake.getClusterTemplateMutex.RUnlock()
+	fake.getDefaultSupplyChainsForWorkloadMutex.RLock()
+	defer fake.getDefaultSupplyChainsForWorkloadMutex.RUnlock()
+	fake.getNamespacedSupplyChainMutex.RLock()
+	defer fake.getNamespacedSupplyChainMutex.RUnlock()
 	fake.getPipelineMutex.RLock()
 	defer fake.getPipelineMutex.RUnlock()
+	fake.getRealizationReportMutex.RLock()
+	defer fake.getRealizationReportMutex.RUnlock()
 	fake.getRunTemplateMutex.RLock()
 	defer fake.getRunTemplateMutex.RUnlock()
 	fake.getSchemeMutex.RLock()
 	defer fake.getSchemeMutex.RUnlock()
+	fake.getSecretDataMutex.RLock()
+	defer fake.getSecretDataMutex.RUnlock()
 	fake.getSupplyChainMutex.RLock()
 	defer fake.getSupplyChainMutex.RUnlock()
 	fake.getSupplyChainsForWorkloadMutex.RLock()
 	defer fake.getSupplyChainsForWorkloadMutex.RUnlock()
+	fake.getTemplateMutex.RLock()
+	defer fake.getTemplateMutex.RUnlock()
+	fake.getTemplatePlaygroundMutex.RLock()
+	defer fake.getTemplatePlaygroundMutex.RUnlock()
 	fake.getWorkloadMutex.RLock()
 	defer fake.getWorkloadMutex.RUnlock()
+	fake.getWorkloadPreviewMutex.RLock()
+	defer fake.getWorkloadPreviewMutex.RUnlock()
+	fake.isNamespacedMutex.RLock()
+	defer fake.isNamespacedMutex.RUnlock()
+	fake.listDependentsMutex.RLock()
+	defer fake.listDependentsMutex.RUnlock()
+	fake.listNamespacedSupplyChainsMutex.RLock()
+	defer fake.listNamespacedSupplyChainsMutex.RUnlock()
+	fake.listPipelinesMutex.RLock()
+	defer fake.listPipelinesMutex.RUnlock()
+	fake.listStampPoliciesMutex.RLock()
+	defer fake.listStampPoliciesMutex.RUnlock()
+	fake.listStampedObjectsMutex.RLock()
+	defer fake.listStampedObjectsMutex.RUnlock()
+	fake.listSupplyChainsMutex.RLock()
+	defer fake.listSupplyChainsMutex.RUnlock()
 	fake.listUnstructuredMutex.RLock()
 	defer fake.listUnstructuredMutex.RUnlock()
+	fake.listWorkloadsMutex.RLock()
+	defer fake.listWorkloadsMutex.RUnlock()
 	fake.statusUpdateMutex.RLock()
 	defer fake.statusUpdateMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,63 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package root
+
+import (
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+)
+
+// ClientSettings tune how the controller talks to the API server, for
+// installations large enough to be throttled by the client's defaults.
+type ClientSettings struct {
+	// QPS is the sustained rate of requests per second to the API server.
+	// When zero, the client's default is kept.
+	QPS float32 `json:"qps,omitempty"`
+	// Burst is the number of requests that may exceed QPS for a moment.
+	// When zero, the client's default is kept.
+	Burst int `json:"burst,omitempty"`
+	// RequestTimeout bounds each request of the controllers to the API
+	// server. When zero, requests are not bounded.
+	RequestTimeout metav1.Duration `json:"requestTimeout,omitempty"`
+}
+
+// ReadClientSettings reads the settings from a YAML or JSON file.
+func ReadClientSettings(path string) (ClientSettings, error) {
+	var settings ClientSettings
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return settings, fmt.Errorf("read: %w", err)
+	}
+
+	if err := yaml.UnmarshalStrict(content, &settings); err != nil {
+		return settings, fmt.Errorf("unmarshal '%s': %w", path, err)
+	}
+
+	return settings, nil
+}
+
+func (s ClientSettings) configure(cfg *rest.Config) {
+	if s.QPS > 0 {
+		cfg.QPS = s.QPS
+	}
+	if s.Burst > 0 {
+		cfg.Burst = s.Burst
+	}
+}
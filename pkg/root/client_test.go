@@ -0,0 +1,66 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package root_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/cartographer/pkg/root"
+)
+
+var _ = Describe("ReadClientSettings", func() {
+	var (
+		dir  string
+		path string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "client-settings-")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "config.yaml")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("reads the settings", func() {
+		Expect(os.WriteFile(path, []byte("qps: 50\nburst: 100\nrequestTimeout: 30s\n"), 0600)).To(Succeed())
+
+		settings, err := root.ReadClientSettings(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings.QPS).To(Equal(float32(50)))
+		Expect(settings.Burst).To(Equal(100))
+		Expect(settings.RequestTimeout.Duration).To(Equal(30 * time.Second))
+	})
+
+	It("rejects unknown fields", func() {
+		Expect(os.WriteFile(path, []byte("qpz: 50\n"), 0600)).To(Succeed())
+
+		_, err := root.ReadClientSettings(path)
+		Expect(err).To(MatchError(ContainSubstring(`unknown field "qpz"`)))
+	})
+
+	It("returns an error when the file cannot be read", func() {
+		_, err := root.ReadClientSettings(filepath.Join(path, "missing"))
+		Expect(err).To(MatchError(ContainSubstring("read:")))
+	})
+})
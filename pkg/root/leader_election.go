@@ -0,0 +1,78 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package root
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// LeaderElectionID is the name of the lease the replicas of the controller
+// elect their leader with.
+const LeaderElectionID = "cartographer-controller-leader"
+
+// LeaderElectionSettings choose whether the replicas of the controller elect
+// a leader, so that only one of them reconciles at a time, and how quickly a
+// new leader takes over.
+type LeaderElectionSettings struct {
+	// Enabled elects a leader. A single replica, e.g. on a dev cluster, can
+	// do without.
+	Enabled bool
+	// Namespace, when set, is where the lease is kept. Otherwise it is the
+	// namespace the controller runs in.
+	Namespace string
+	// LeaseDuration is how long the other replicas wait before taking over
+	// from a leader that stopped renewing its lease. When zero, the
+	// manager's default is kept.
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the leader tries to renew its lease before
+	// giving up leadership. When zero, the manager's default is kept.
+	RenewDeadline time.Duration
+}
+
+// inClusterNamespaceFile is where a pod finds the namespace it runs in,
+// which the lease is kept in unless Namespace is set.
+const inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// Validate rejects a leader election outside of a pod, such as that of
+// `make run`, without a Namespace to keep the lease in.
+func (s LeaderElectionSettings) Validate() error {
+	if !s.Enabled || s.Namespace != "" {
+		return nil
+	}
+	if _, err := os.Stat(inClusterNamespaceFile); err == nil {
+		return nil
+	}
+	return errors.New("--leader-elect-namespace is required when the controller runs outside of a pod, unless --leader-elect=false")
+}
+
+func (s LeaderElectionSettings) configure(options *manager.Options) {
+	if !s.Enabled {
+		return
+	}
+
+	options.LeaderElection = true
+	options.LeaderElectionID = LeaderElectionID
+	options.LeaderElectionNamespace = s.Namespace
+	if s.LeaseDuration > 0 {
+		options.LeaseDuration = &s.LeaseDuration
+	}
+	if s.RenewDeadline > 0 {
+		options.RenewDeadline = &s.RenewDeadline
+	}
+}
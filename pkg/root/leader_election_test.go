@@ -0,0 +1,46 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package root_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/cartographer/pkg/root"
+)
+
+var _ = Describe("LeaderElectionSettings", func() {
+	Describe("Validate", func() {
+		It("requires a namespace for the lease outside of a pod", func() {
+			if _, err := os.Stat("/var/run/secrets/kubernetes.io/serviceaccount/namespace"); err == nil {
+				Skip("running in a pod")
+			}
+
+			settings := root.LeaderElectionSettings{Enabled: true}
+			Expect(settings.Validate()).To(MatchError("--leader-elect-namespace is required when the controller runs outside of a pod, unless --leader-elect=false"))
+		})
+
+		It("accepts a namespace for the lease", func() {
+			settings := root.LeaderElectionSettings{Enabled: true, Namespace: "cartographer-system"}
+			Expect(settings.Validate()).To(Succeed())
+		})
+
+		It("accepts no leader election", func() {
+			Expect(root.LeaderElectionSettings{}.Validate()).To(Succeed())
+		})
+	})
+})
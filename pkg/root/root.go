@@ -17,16 +17,35 @@ package root
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/discovery"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/audit"
+	"github.com/vmware-tanzu/cartographer/pkg/health"
+	"github.com/vmware-tanzu/cartographer/pkg/interceptor"
+	"github.com/vmware-tanzu/cartographer/pkg/logging"
+	"github.com/vmware-tanzu/cartographer/pkg/openapi"
 	"github.com/vmware-tanzu/cartographer/pkg/registrar"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/signature"
+	"github.com/vmware-tanzu/cartographer/pkg/supportbundle"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/tracing"
+	"github.com/vmware-tanzu/cartographer/pkg/webhook"
 )
 
 type Command struct {
@@ -34,34 +53,189 @@ type Command struct {
 	CertDir string
 	Context context.Context
 	Logger  logr.Logger
+	// Logging filters the logs written to Logger by the verbosity of each
+	// subsystem, which Logger must enable up to Logging.MaxLevel().
+	Logging logging.Settings
+	// Interceptors are invoked around the submission of every stamped
+	// object, letting embedders customize them without forking the realizers.
+	Interceptors []interceptor.Interceptor
+	// InterceptorURL, when set, adds a webhook interceptor after Interceptors.
+	InterceptorURL string
+	// WatchedKinds are the kinds of stamped objects this instance watches,
+	// as Kind.group (e.g. TaskRun.tekton.dev, or Pod for the core group).
+	// Instances watching disjoint kinds share the informer memory of large
+	// installs. Stamped objects of these kinds are read from the informers
+	// rather than the API server. When empty, every stamped kind is watched.
+	WatchedKinds []string
+	// CoalesceWindow delays the reconcile of a pipeline after an update to
+	// one of its stamped objects, so that the updates within the window
+	// cause a single reconcile. When zero, every update is reconciled.
+	CoalesceWindow time.Duration
+	// ResyncIntervals are how often each controller reconciles an object
+	// again when nothing about it changed, trading freshness for load on the
+	// API server. Zero keeps a controller's default.
+	ResyncIntervals registrar.ResyncIntervals
+	// MaxConcurrentReconciles are how many objects each controller
+	// reconciles at once. Zero keeps a controller reconciling one at a time.
+	MaxConcurrentReconciles registrar.MaxConcurrentReconciles
+	// ClusterContext describes the cluster to every template, as
+	// $(clusterContext.<field>)$.
+	ClusterContext templates.ClusterContext
+	// SupportBundleAddress, when set, is the address the support bundle
+	// endpoint listens on.
+	SupportBundleAddress string
+	// MetricsAddress, when set, is the address the metrics endpoint listens
+	// on.
+	MetricsAddress string
+	// HealthProbeAddress, when set, is the address the /healthz and /readyz
+	// probes are served on. The controller is ready once its informers have
+	// synced and, with a CertDir, its webhook server is up.
+	HealthProbeAddress string
+	// ProfilingAddress, when set, is the address the pprof profiles are
+	// served on, under /debug/pprof/.
+	ProfilingAddress string
+	// TracesEndpoint, when set, is the OTLP/HTTP endpoint of the
+	// OpenTelemetry collector a trace of every reconcile is exported to.
+	TracesEndpoint string
+	// FieldManager, when set, is the field manager stamped objects are
+	// applied under, in place of repository.FieldManager.
+	FieldManager string
+	// LeaveConflicts fails to apply a stamped object that sets a field
+	// another field manager owns, instead of taking the field over.
+	LeaveConflicts bool
+	// ThreeWayMerge updates stamped objects with a three-way merge of their
+	// last applied configuration instead of server-side apply.
+	ThreeWayMerge bool
+	// DryRunFirst submits each stamped object with a dry run before writing
+	// it, so that an object the API server rejects is not written at all.
+	DryRunFirst bool
+	// ValidateSchemas validates each stamped object against the OpenAPI
+	// schema the API server publishes for its kind before submitting it.
+	ValidateSchemas bool
+	// ReviewAccessFirst asks the API server, with a SelfSubjectAccessReview,
+	// whether each stamped object may be written before writing it, so that
+	// a missing permission is reported as such.
+	ReviewAccessFirst bool
+	// TemplateSigningKeys, when set, are the files of the PEM encoded public
+	// keys templates must be signed with before they are stamped.
+	TemplateSigningKeys []string
+	// AuditLog, when set, is the file every create and update of a stamped
+	// object is appended to, with the fields an update changed, or "-" for
+	// stdout.
+	AuditLog string
+	// RejectMissingTemplates rejects supply chains and pipelines referring
+	// to templates that do not exist, instead of admitting them with a
+	// warning.
+	RejectMissingTemplates bool
+	// WorkloadDefaults are set on the workloads that omit them when they are
+	// admitted.
+	WorkloadDefaults WorkloadDefaults
+	// ImpersonateWorkloadServiceAccounts writes the objects stamped for a
+	// workload as its spec.serviceAccountName, or the default service
+	// account of its namespace, so that RBAC in the workload's namespace is
+	// what authorizes them.
+	ImpersonateWorkloadServiceAccounts bool
+	// Client tunes the rate and timeout of requests to the API server.
+	Client ClientSettings
+	// LeaderElection, when enabled, lets only one of several replicas of the
+	// controller reconcile at a time.
+	LeaderElection LeaderElectionSettings
 }
 
+// supportBundleLogLines is how many of the latest log lines are kept for
+// support bundles.
+const supportBundleLogLines = 2000
+
 func (cmd *Command) Execute() error {
-	log.SetLogger(cmd.Logger)
+	logs := supportbundle.NewLogRecorder(supportBundleLogLines)
+	log.SetLogger(logging.NewLogger(logs.Logger(cmd.Logger), cmd.Logging))
 	l := log.Log.WithName("cartographer")
 
 	cfg, err := config.GetConfig()
 	if err != nil {
 		return fmt.Errorf("get config: %w", err)
 	}
+	cmd.Client.configure(cfg)
 
 	scheme := runtime.NewScheme()
 	if err := registrar.AddToScheme(scheme); err != nil {
 		return fmt.Errorf("add to scheme: %w", err)
 	}
 
-	mgr, err := manager.New(cfg, manager.Options{
-		Port:               cmd.Port,
-		CertDir:            cmd.CertDir,
-		Scheme:             scheme,
-		MetricsBindAddress: "0",
-	})
+	var watchedKinds []schema.GroupKind
+	for _, kind := range cmd.WatchedKinds {
+		watchedKinds = append(watchedKinds, schema.ParseGroupKind(kind))
+	}
+
+	metricsAddress := cmd.MetricsAddress
+	if metricsAddress == "" {
+		metricsAddress = "0"
+	}
 
+	options := manager.Options{
+		Port:                   cmd.Port,
+		CertDir:                cmd.CertDir,
+		Scheme:                 scheme,
+		MetricsBindAddress:     metricsAddress,
+		HealthProbeBindAddress: cmd.HealthProbeAddress,
+		NewClient:              registrar.NewClientFunc(watchedKinds, cmd.Client.RequestTimeout.Duration),
+	}
+	cmd.LeaderElection.configure(&options)
+
+	mgr, err := manager.New(cfg, options)
 	if err != nil {
 		return fmt.Errorf("manager new: %w", err)
 	}
 
-	if err := registrar.RegisterControllers(mgr); err != nil {
+	interceptors := interceptor.Chain(cmd.Interceptors)
+	if cmd.InterceptorURL != "" {
+		interceptors = append(interceptors, interceptor.NewWebhook(cmd.InterceptorURL, &http.Client{Timeout: 10 * time.Second}))
+	}
+
+	applyOptions := []repository.ApplyOption{
+		repository.WithFieldManager(cmd.FieldManager),
+		repository.WithForceConflicts(!cmd.LeaveConflicts),
+	}
+	if cmd.ThreeWayMerge {
+		applyOptions = append(applyOptions, repository.WithThreeWayMerge())
+	}
+	if cmd.DryRunFirst {
+		applyOptions = append(applyOptions, repository.WithDryRun())
+	}
+	if cmd.ValidateSchemas {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("discovery client: %w", err)
+		}
+		applyOptions = append(applyOptions, repository.WithValidator(openapi.NewValidator(discoveryClient, openapi.DefaultRefreshInterval)))
+	}
+	if cmd.ReviewAccessFirst {
+		applyOptions = append(applyOptions, repository.WithAccessReview(mgr.GetRESTMapper()))
+	}
+	if len(cmd.TemplateSigningKeys) > 0 {
+		verifier, err := signature.LoadVerifier(cmd.TemplateSigningKeys...)
+		if err != nil {
+			return fmt.Errorf("template signing keys: %w", err)
+		}
+		applyOptions = append(applyOptions, repository.WithTemplateVerifier(verifier))
+	}
+	if cmd.AuditLog != "" {
+		auditLog, err := audit.Open(cmd.AuditLog)
+		if err != nil {
+			return err
+		}
+		applyOptions = append(applyOptions, repository.WithAuditor(auditLog))
+	}
+	if err := registrar.RegisterControllers(mgr, registrar.Options{
+		Interceptor:             interceptors,
+		WatchedKinds:            watchedKinds,
+		CoalesceWindow:          cmd.CoalesceWindow,
+		ResyncIntervals:         cmd.ResyncIntervals,
+		MaxConcurrentReconciles: cmd.MaxConcurrentReconciles,
+		ClusterContext:          cmd.ClusterContext,
+		ImpersonateWorkloads:    cmd.ImpersonateWorkloadServiceAccounts,
+		ApplyOptions:            applyOptions,
+	}); err != nil {
 		return fmt.Errorf("register controllers: %w", err)
 	}
 
@@ -69,34 +243,127 @@ func (cmd *Command) Execute() error {
 		return fmt.Errorf("index resources: %w", err)
 	}
 
+	if cmd.SupportBundleAddress != "" {
+		if err := mgr.Add(&supportbundle.Server{
+			Addr:    cmd.SupportBundleAddress,
+			Handler: supportbundle.NewHandler(mgr.GetAPIReader(), logs),
+		}); err != nil {
+			return fmt.Errorf("add support bundle server: %w", err)
+		}
+	}
+
+	if cmd.ProfilingAddress != "" {
+		if err := mgr.Add(&health.ProfilingServer{Addr: cmd.ProfilingAddress}); err != nil {
+			return fmt.Errorf("add profiling server: %w", err)
+		}
+	}
+
+	if cmd.TracesEndpoint != "" {
+		provider := tracing.NewProvider(tracing.NewOTLPExporter(cmd.TracesEndpoint, &http.Client{Timeout: 10 * time.Second}), l.WithName("tracing"))
+		if err := mgr.Add(provider); err != nil {
+			return fmt.Errorf("add tracing provider: %w", err)
+		}
+		tracing.SetProvider(provider)
+	}
+
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		return fmt.Errorf("add health check: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("informers", health.CacheSynced(mgr.GetCache())); err != nil {
+		return fmt.Errorf("add informers readiness check: %w", err)
+	}
+
 	if cmd.CertDir == "" {
 		l.Info("Not registering the webhook server. Must pass a directory containing tls.crt and tls.key to --cert-dir")
 	} else {
-		if err := controllerruntime.NewWebhookManagedBy(mgr).
-			For(&v1alpha1.ClusterSupplyChain{}).
-			Complete(); err != nil {
-			return fmt.Errorf("clustersupplychain webhook: %w", err)
+		templateValidator := &webhook.TemplateValidator{
+			ClusterContext: cmd.ClusterContext,
+			Repository:     repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring())),
+		}
+		supplyChainValidator := &webhook.SupplyChainValidator{
+			Repository:             repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring())),
+			RejectMissingTemplates: cmd.RejectMissingTemplates,
 		}
+		mgr.GetWebhookServer().Register("/validate-carto-run-v1alpha1-clustersupplychain",
+			webhook.WithWarnings(admission.WithCustomValidator(&v1alpha1.ClusterSupplyChain{}, supplyChainValidator)))
 		if err := controllerruntime.NewWebhookManagedBy(mgr).
 			For(&v1alpha1.ClusterConfigTemplate{}).
+			WithValidator(templateValidator).
 			Complete(); err != nil {
 			return fmt.Errorf("clusterconfigtemplate webhook: %w", err)
 		}
 		if err := controllerruntime.NewWebhookManagedBy(mgr).
 			For(&v1alpha1.ClusterImageTemplate{}).
+			WithValidator(templateValidator).
 			Complete(); err != nil {
 			return fmt.Errorf("clusterimagetemplate webhook: %w", err)
 		}
 		if err := controllerruntime.NewWebhookManagedBy(mgr).
 			For(&v1alpha1.ClusterSourceTemplate{}).
+			WithValidator(templateValidator).
 			Complete(); err != nil {
 			return fmt.Errorf("clustersourcetemplate webhook: %w", err)
 		}
 		if err := controllerruntime.NewWebhookManagedBy(mgr).
 			For(&v1alpha1.ClusterTemplate{}).
+			WithValidator(templateValidator).
 			Complete(); err != nil {
 			return fmt.Errorf("clustertemplate webhook: %w", err)
 		}
+		mgr.GetWebhookServer().Register("/validate-carto-run-v1alpha1-supplychain",
+			webhook.WithWarnings(admission.WithCustomValidator(&v1alpha1.SupplyChain{}, supplyChainValidator)))
+		if err := controllerruntime.NewWebhookManagedBy(mgr).
+			For(&v1alpha1.ConfigTemplate{}).
+			WithValidator(templateValidator).
+			Complete(); err != nil {
+			return fmt.Errorf("configtemplate webhook: %w", err)
+		}
+		if err := controllerruntime.NewWebhookManagedBy(mgr).
+			For(&v1alpha1.ImageTemplate{}).
+			WithValidator(templateValidator).
+			Complete(); err != nil {
+			return fmt.Errorf("imagetemplate webhook: %w", err)
+		}
+		if err := controllerruntime.NewWebhookManagedBy(mgr).
+			For(&v1alpha1.SourceTemplate{}).
+			WithValidator(templateValidator).
+			Complete(); err != nil {
+			return fmt.Errorf("sourcetemplate webhook: %w", err)
+		}
+		if err := controllerruntime.NewWebhookManagedBy(mgr).
+			For(&v1alpha1.Template{}).
+			WithValidator(templateValidator).
+			Complete(); err != nil {
+			return fmt.Errorf("template webhook: %w", err)
+		}
+		if err := controllerruntime.NewWebhookManagedBy(mgr).
+			For(&v1alpha1.RunTemplate{}).
+			WithValidator(templateValidator).
+			Complete(); err != nil {
+			return fmt.Errorf("runtemplate webhook: %w", err)
+		}
+		mgr.GetWebhookServer().Register("/validate-carto-run-v1alpha1-pipeline",
+			webhook.WithWarnings(admission.WithCustomValidator(&v1alpha1.Pipeline{}, &webhook.PipelineValidator{
+				Repository:             repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring())),
+				RejectMissingTemplates: cmd.RejectMissingTemplates,
+			})))
+		mgr.GetWebhookServer().Register("/convert", &conversion.Webhook{})
+		if err := controllerruntime.NewWebhookManagedBy(mgr).
+			For(&v1alpha1.Workload{}).
+			WithDefaulter(&webhook.WorkloadDefaulter{
+				Repository:         repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring())),
+				ServiceAccountName: cmd.WorkloadDefaults.ServiceAccountName,
+				Labels:             cmd.WorkloadDefaults.Labels,
+			}).
+			WithValidator(&webhook.WorkloadValidator{
+				Repository: repository.NewRepository(mgr.GetClient(), repository.NewCache(cache.NewExpiring())),
+			}).
+			Complete(); err != nil {
+			return fmt.Errorf("workload webhook: %w", err)
+		}
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			return fmt.Errorf("add webhook readiness check: %w", err)
+		}
 	}
 
 	if err := mgr.Start(cmd.Context); err != nil {
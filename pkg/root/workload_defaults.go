@@ -0,0 +1,27 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package root
+
+// WorkloadDefaults are what the workloads that omit them are given when they
+// are admitted, so that minimal workloads still follow the conventions of an
+// organization.
+type WorkloadDefaults struct {
+	// ServiceAccountName, when set, is the service account name of the
+	// workloads that do not set one.
+	ServiceAccountName string
+	// Labels are added to the workloads that do not set them, before the
+	// supply chain declaring their param defaults is chosen.
+	Labels map[string]string
+}
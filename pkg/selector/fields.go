@@ -0,0 +1,88 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"bytes"
+	"fmt"
+
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// MatchFields reports whether the values at the jsonpaths of the
+// requirements into content, an object converted to unstructured content,
+// meet every requirement.
+func MatchFields(requirements []v1alpha1.FieldSelectorRequirement, content map[string]interface{}) (bool, error) {
+	for _, requirement := range requirements {
+		value, found, err := fieldValue(requirement.Key, content)
+		if err != nil {
+			return false, fmt.Errorf("field '%s': %w", requirement.Key, err)
+		}
+
+		var satisfied bool
+		switch requirement.Operator {
+		case v1alpha1.FieldSelectorOpExists:
+			satisfied = found
+		case v1alpha1.FieldSelectorOpDoesNotExist:
+			satisfied = !found
+		case v1alpha1.FieldSelectorOpIn:
+			satisfied = found && contains(requirement.Values, value)
+		case v1alpha1.FieldSelectorOpNotIn:
+			satisfied = !found || !contains(requirement.Values, value)
+		default:
+			return false, fmt.Errorf("field '%s': unknown operator '%s'", requirement.Key, requirement.Operator)
+		}
+		if !satisfied {
+			log.V(2).Info("field requirement not met", "key", requirement.Key, "operator", requirement.Operator, "value", value, "found", found)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// fieldValue evaluates a jsonpath against the content, reporting whether
+// the path led to a value.
+func fieldValue(path string, content map[string]interface{}) (string, bool, error) {
+	parser := jsonpath.New("").AllowMissingKeys(true)
+	if err := parser.Parse(fmt.Sprintf("{.%s}", path)); err != nil {
+		return "", false, fmt.Errorf("parse: %w", err)
+	}
+
+	results, err := parser.FindResults(content)
+	if err != nil {
+		return "", false, fmt.Errorf("find results: %w", err)
+	}
+	if len(results) == 0 || len(results[0]) == 0 {
+		return "", false, nil
+	}
+
+	var buffer bytes.Buffer
+	if err := parser.PrintResults(&buffer, results[0]); err != nil {
+		return "", false, fmt.Errorf("print results: %w", err)
+	}
+	return buffer.String(), true, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
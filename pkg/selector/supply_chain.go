@@ -0,0 +1,99 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/logging"
+)
+
+var log = ctrllog.Log.WithName(logging.Selectors)
+
+// SupplyChainMatchesWorkload reports whether the workload is selected by
+// the supply chain, by its labels and by its fields.
+func SupplyChainMatchesWorkload(spec *v1alpha1.SupplyChainSpec, workload *v1alpha1.Workload) (bool, error) {
+	labelSelector, err := spec.LabelSelector()
+	if err != nil {
+		return false, fmt.Errorf("label selector: %w", err)
+	}
+	if !labelSelector.Matches(labels.Set(workload.Labels)) {
+		log.V(2).Info("labels of workload do not match", "workload", workload.Name, "namespace", workload.Namespace, "selector", labelSelector.String())
+		return false, nil
+	}
+
+	if len(spec.SelectorMatchFields) == 0 {
+		return true, nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(workload)
+	if err != nil {
+		return false, fmt.Errorf("convert workload: %w", err)
+	}
+
+	return MatchFields(spec.SelectorMatchFields, content)
+}
+
+// ChooseSupplyChain chooses among the supply chains that select a workload
+// the one of highest priority, then the one with the most specific
+// selector, explaining the choice. It returns nil when no single supply
+// chain comes first.
+func ChooseSupplyChain(supplyChains []v1alpha1.ClusterSupplyChain) (*v1alpha1.ClusterSupplyChain, string) {
+	if len(supplyChains) == 0 {
+		return nil, ""
+	}
+
+	candidates := make([]v1alpha1.ClusterSupplyChain, len(supplyChains))
+	copy(candidates, supplyChains)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Spec.Priority != candidates[j].Spec.Priority {
+			return candidates[i].Spec.Priority > candidates[j].Spec.Priority
+		}
+		return candidates[i].Spec.SelectorSpecificity() > candidates[j].Spec.SelectorSpecificity()
+	})
+
+	chosen := &candidates[0]
+	if len(candidates) == 1 {
+		log.V(1).Info("chose the only matching supply chain", "supplyChain", chosen.Name)
+		return chosen, ""
+	}
+
+	next := candidates[1]
+	var reason string
+	switch {
+	case chosen.Spec.Priority > next.Spec.Priority:
+		reason = fmt.Sprintf("its higher priority (%d)", chosen.Spec.Priority)
+	case chosen.Spec.SelectorSpecificity() > next.Spec.SelectorSpecificity():
+		reason = "its more specific selector"
+	default:
+		log.V(1).Info("no supply chain comes first", "supplyChains", len(candidates), "priority", chosen.Spec.Priority)
+		return nil, ""
+	}
+
+	var others []string
+	for _, other := range candidates[1:] {
+		others = append(others, fmt.Sprintf("'%s'", other.Name))
+	}
+	explanation := fmt.Sprintf("supply chain '%s' chosen over %s for %s", chosen.Name, strings.Join(others, ", "), reason)
+	log.V(1).Info(explanation)
+	return chosen, explanation
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
+)
+
+var _ = Describe("SupplyChainMatchesWorkload", func() {
+	var (
+		spec     *v1alpha1.SupplyChainSpec
+		workload *v1alpha1.Workload
+	)
+
+	BeforeEach(func() {
+		url := "https://github.com/spring-projects/spring-petclinic.git"
+		spec = &v1alpha1.SupplyChainSpec{
+			Selector: map[string]string{"app.tanzu.vmware.com/workload-type": "web"},
+		}
+		workload = &v1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"app.tanzu.vmware.com/workload-type": "web"},
+			},
+			Spec: v1alpha1.WorkloadSpec{
+				Source: &v1alpha1.WorkloadSource{Git: &v1alpha1.WorkloadGit{URL: &url}},
+				Params: []v1alpha1.WorkloadParam{{Name: "dockerfile", Value: apiextensionsv1.JSON{Raw: []byte(`"Dockerfile"`)}}},
+			},
+		}
+	})
+
+	It("matches a workload by its labels", func() {
+		Expect(selector.SupplyChainMatchesWorkload(spec, workload)).To(BeTrue())
+	})
+
+	It("does not match a workload without the labels", func() {
+		workload.Labels = nil
+		Expect(selector.SupplyChainMatchesWorkload(spec, workload)).To(BeFalse())
+	})
+
+	DescribeTable("matching fields",
+		func(requirement v1alpha1.FieldSelectorRequirement, expected bool) {
+			spec.SelectorMatchFields = []v1alpha1.FieldSelectorRequirement{requirement}
+			Expect(selector.SupplyChainMatchesWorkload(spec, workload)).To(Equal(expected))
+		},
+		Entry("a field that exists", v1alpha1.FieldSelectorRequirement{Key: "spec.source.git", Operator: "Exists"}, true),
+		Entry("a field that does not exist", v1alpha1.FieldSelectorRequirement{Key: "spec.image", Operator: "Exists"}, false),
+		Entry("a field required not to exist", v1alpha1.FieldSelectorRequirement{Key: "spec.image", Operator: "DoesNotExist"}, true),
+		Entry("a value in the values", v1alpha1.FieldSelectorRequirement{Key: `spec.params[?(@.name=="dockerfile")].value`, Operator: "In", Values: []string{"Dockerfile"}}, true),
+		Entry("a value not in the values", v1alpha1.FieldSelectorRequirement{Key: `spec.params[?(@.name=="dockerfile")].value`, Operator: "NotIn", Values: []string{"Dockerfile"}}, false),
+		Entry("a missing value required not to be in the values", v1alpha1.FieldSelectorRequirement{Key: "spec.image", Operator: "NotIn", Values: []string{"some-image"}}, true),
+	)
+
+	It("does not consult the fields of a workload without the labels", func() {
+		workload.Labels = nil
+		spec.SelectorMatchFields = []v1alpha1.FieldSelectorRequirement{{Key: "spec.source.git", Operator: "Exists"}}
+		Expect(selector.SupplyChainMatchesWorkload(spec, workload)).To(BeFalse())
+	})
+
+	It("returns an error for an unknown operator", func() {
+		spec.SelectorMatchFields = []v1alpha1.FieldSelectorRequirement{{Key: "spec.image", Operator: "Matches"}}
+		_, err := selector.SupplyChainMatchesWorkload(spec, workload)
+		Expect(err).To(MatchError("field 'spec.image': unknown operator 'Matches'"))
+	})
+
+	It("returns an error for an invalid jsonpath", func() {
+		spec.SelectorMatchFields = []v1alpha1.FieldSelectorRequirement{{Key: "spec.params[", Operator: "Exists"}}
+		_, err := selector.SupplyChainMatchesWorkload(spec, workload)
+		Expect(err).To(MatchError(HavePrefix("field 'spec.params[': parse: ")))
+	})
+})
+
+var _ = Describe("ChooseSupplyChain", func() {
+	supplyChain := func(name string, priority int32, selector map[string]string) v1alpha1.ClusterSupplyChain {
+		return v1alpha1.ClusterSupplyChain{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha1.SupplyChainSpec{Selector: selector, Priority: priority},
+		}
+	}
+
+	web := map[string]string{"app.tanzu.vmware.com/workload-type": "web"}
+	webJava := map[string]string{"app.tanzu.vmware.com/workload-type": "web", "apps.tanzu.vmware.com/language": "java"}
+
+	It("chooses the only supply chain without explanation", func() {
+		chosen, reason := selector.ChooseSupplyChain([]v1alpha1.ClusterSupplyChain{supplyChain("web", 0, web)})
+		Expect(chosen.Name).To(Equal("web"))
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("chooses the supply chain of highest priority", func() {
+		chosen, reason := selector.ChooseSupplyChain([]v1alpha1.ClusterSupplyChain{
+			supplyChain("web", 0, web),
+			supplyChain("web-java", 0, webJava),
+			supplyChain("golden", 10, web),
+		})
+		Expect(chosen.Name).To(Equal("golden"))
+		Expect(reason).To(Equal("supply chain 'golden' chosen over 'web-java', 'web' for its higher priority (10)"))
+	})
+
+	It("chooses the supply chain with the most specific selector among those of equal priority", func() {
+		chosen, reason := selector.ChooseSupplyChain([]v1alpha1.ClusterSupplyChain{
+			supplyChain("web", 0, web),
+			supplyChain("web-java", 0, webJava),
+		})
+		Expect(chosen.Name).To(Equal("web-java"))
+		Expect(reason).To(Equal("supply chain 'web-java' chosen over 'web' for its more specific selector"))
+	})
+
+	It("counts match expressions and fields towards the specificity of a selector", func() {
+		withFields := supplyChain("web-dockerfile", 0, web)
+		withFields.Spec.SelectorMatchFields = []v1alpha1.FieldSelectorRequirement{{Key: "spec.source.git", Operator: "Exists"}}
+		withFields.Spec.SelectorMatchExpressions = []metav1.LabelSelectorRequirement{{Key: "tier", Operator: metav1.LabelSelectorOpExists}}
+
+		chosen, _ := selector.ChooseSupplyChain([]v1alpha1.ClusterSupplyChain{supplyChain("web-java", 0, webJava), withFields})
+		Expect(chosen.Name).To(Equal("web-dockerfile"))
+	})
+
+	It("chooses none when the first supply chains tie", func() {
+		chosen, reason := selector.ChooseSupplyChain([]v1alpha1.ClusterSupplyChain{
+			supplyChain("web", 0, web),
+			supplyChain("other-web", 0, web),
+		})
+		Expect(chosen).To(BeNil())
+		Expect(reason).To(BeEmpty())
+	})
+})
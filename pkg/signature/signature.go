@@ -0,0 +1,149 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signature verifies that templates were signed by a trusted key, so
+// that only templates vetted by the platform team drive stamping. A template
+// is signed over its payload, the JSON of its spec, as with
+//
+//	kubectl get clustertemplate my-template -o json | jq -cjS .spec > payload.json
+//	cosign sign-blob --key cosign.key payload.json
+//
+// and the signature is set in its Annotation.
+package signature
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotation holds the base64 signature of a template's payload.
+const Annotation = "carto.run/signature"
+
+//counterfeiter:generate . Verifier
+type Verifier interface {
+	Verify(template client.Object) error
+}
+
+// NewVerifier verifies templates against the PEM encoded public keys: ECDSA,
+// as cosign generates, Ed25519 or RSA. A template is trusted when its
+// signature matches any of them.
+func NewVerifier(keys ...[]byte) (Verifier, error) {
+	v := &verifier{}
+	for i, data := range keys {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("key %d: no PEM block", i)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		switch key.(type) {
+		case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		default:
+			return nil, fmt.Errorf("key %d: unsupported key type %T", i, key)
+		}
+		v.keys = append(v.keys, key)
+	}
+	if len(v.keys) == 0 {
+		return nil, errors.New("no keys")
+	}
+	return v, nil
+}
+
+// LoadVerifier verifies templates against the public keys in the files.
+func LoadVerifier(paths ...string) (Verifier, error) {
+	var keys [][]byte
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read key: %w", err)
+		}
+		keys = append(keys, data)
+	}
+	return NewVerifier(keys...)
+}
+
+type verifier struct {
+	keys []crypto.PublicKey
+}
+
+func (v *verifier) Verify(template client.Object) error {
+	encoded, ok := template.GetAnnotations()[Annotation]
+	if !ok {
+		return fmt.Errorf("template is not signed: no %s annotation", Annotation)
+	}
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("decode signature: %w", err)
+	}
+	payload, err := Payload(template)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(payload)
+	for _, key := range v.keys {
+		if verify(key, payload, digest[:], sig) {
+			return nil
+		}
+	}
+	return errors.New("signature does not match any trusted key")
+}
+
+func verify(key crypto.PublicKey, payload, digest, sig []byte) bool {
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest, sig)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, payload, sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) == nil
+	}
+	return false
+}
+
+// Payload is what a template is signed over: its spec as compact JSON, with
+// the keys of objects sorted and no characters escaped, so that a change to
+// its metadata, such as setting the signature, does not invalidate it.
+func Payload(template client.Object) ([]byte, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(template)
+	if err != nil {
+		return nil, fmt.Errorf("convert template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(obj["spec"]); err != nil {
+		return nil, fmt.Errorf("encode spec: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
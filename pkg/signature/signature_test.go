@@ -0,0 +1,138 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/signature"
+)
+
+var _ = Describe("Verifier", func() {
+	var (
+		key      *ecdsa.PrivateKey
+		verifier signature.Verifier
+		template *v1alpha1.ClusterTemplate
+	)
+
+	publicPEM := func(public interface{}) []byte {
+		der, err := x509.MarshalPKIXPublicKey(public)
+		Expect(err).NotTo(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+
+	sign := func(template *v1alpha1.ClusterTemplate) {
+		payload, err := signature.Payload(template)
+		Expect(err).NotTo(HaveOccurred())
+		digest := sha256.Sum256(payload)
+		sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		Expect(err).NotTo(HaveOccurred())
+		template.Annotations = map[string]string{signature.Annotation: base64.StdEncoding.EncodeToString(sig)}
+	}
+
+	BeforeEach(func() {
+		var err error
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		verifier, err = signature.NewVerifier(publicPEM(&key.PublicKey))
+		Expect(err).NotTo(HaveOccurred())
+
+		template = &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+			Spec:       v1alpha1.TemplateSpec{Ytt: "some: template"},
+		}
+	})
+
+	It("trusts a template signed with a trusted key", func() {
+		sign(template)
+
+		Expect(verifier.Verify(template)).To(Succeed())
+	})
+
+	It("trusts a signed template whose metadata changed", func() {
+		sign(template)
+		template.Labels = map[string]string{"team": "platform"}
+
+		Expect(verifier.Verify(template)).To(Succeed())
+	})
+
+	It("rejects a signed template whose spec changed", func() {
+		sign(template)
+		template.Spec.Ytt = "other: template"
+
+		Expect(verifier.Verify(template)).To(MatchError("signature does not match any trusted key"))
+	})
+
+	It("rejects a template signed with another key", func() {
+		sign(template)
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		verifier, err = signature.NewVerifier(publicPEM(&other.PublicKey))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(verifier.Verify(template)).To(MatchError("signature does not match any trusted key"))
+	})
+
+	It("rejects a template that is not signed", func() {
+		Expect(verifier.Verify(template)).To(MatchError("template is not signed: no carto.run/signature annotation"))
+	})
+
+	It("trusts a template signed with any of the keys", func() {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		verifier, err = signature.NewVerifier(publicPEM(&key.PublicKey), publicPEM(public))
+		Expect(err).NotTo(HaveOccurred())
+
+		payload, err := signature.Payload(template)
+		Expect(err).NotTo(HaveOccurred())
+		template.Annotations = map[string]string{signature.Annotation: base64.StdEncoding.EncodeToString(ed25519.Sign(private, payload))}
+
+		Expect(verifier.Verify(template)).To(Succeed())
+	})
+
+	It("rejects a key that is not PEM encoded", func() {
+		_, err := signature.NewVerifier([]byte("not a key"))
+		Expect(err).To(MatchError("key 0: no PEM block"))
+	})
+})
+
+var _ = Describe("Payload", func() {
+	It("is the spec as compact JSON with sorted keys and nothing escaped", func() {
+		template := &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+			Spec: v1alpha1.TemplateSpec{
+				Ytt:    "a: <b>",
+				Params: v1alpha1.DefaultParams{{Name: "port"}},
+			},
+		}
+
+		payload, err := signature.Payload(template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(payload)).To(Equal(`{"params":[{"default":null,"name":"port"}],"ytt":"a: <b>"}`))
+	})
+})
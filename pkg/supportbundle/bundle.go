@@ -0,0 +1,251 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package supportbundle collects what is needed to debug a workload into a
+// single sanitized document, for attaching to support tickets.
+package supportbundle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	realizer "github.com/vmware-tanzu/cartographer/pkg/realizer/workload"
+)
+
+// maxEvents is how many of the most recent events a bundle holds.
+const maxEvents = 50
+
+const redacted = "<redacted>"
+
+// Bundle is everything collected to debug a workload. Whatever could not be
+// collected is listed in Errors, so that a partial bundle is still of use.
+type Bundle struct {
+	GeneratedAt    metav1.Time                  `json:"generatedAt"`
+	Workload       *unstructured.Unstructured   `json:"workload"`
+	SupplyChain    *unstructured.Unstructured   `json:"supplyChain,omitempty"`
+	Templates      []*unstructured.Unstructured `json:"templates,omitempty"`
+	StampedObjects []*unstructured.Unstructured `json:"stampedObjects,omitempty"`
+	Events         []corev1.Event               `json:"events,omitempty"`
+	Logs           []LogEntry                   `json:"logs,omitempty"`
+	Errors         []string                     `json:"errors,omitempty"`
+}
+
+// Generate collects the workload with its conditions, the supply chain it
+// was matched to, the templates and objects stamped for its components, the
+// recent events of the workload and its objects, and the recent log lines
+// about it. Every object is sanitized: the values of secrets and of literal
+// environment variables are redacted. logs may be nil.
+func Generate(ctx context.Context, c client.Reader, logs *LogRecorder, namespace, name string) (*Bundle, error) {
+	workloadObject, err := get(ctx, c, v1alpha1.SchemeGroupVersion.WithKind("Workload"), namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("get workload: %w", err)
+	}
+
+	workload := &v1alpha1.Workload{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(workloadObject.Object, workload); err != nil {
+		return nil, fmt.Errorf("convert workload: %w", err)
+	}
+
+	bundle := &Bundle{
+		GeneratedAt: metav1.Now(),
+		Workload:    sanitize(workloadObject),
+	}
+	uids := map[types.UID]bool{workload.UID: true}
+
+	bundle.collectSupplyChain(ctx, c, workload)
+	bundle.collectStampedObjects(ctx, c, workload, uids)
+	bundle.collectEvents(ctx, c, namespace, uids)
+	if logs != nil {
+		bundle.Logs = logs.Entries(func(entry LogEntry) bool {
+			return entry.Values["namespace"] == namespace && entry.Values["name"] == name
+		})
+	}
+
+	return bundle, nil
+}
+
+func (b *Bundle) collectSupplyChain(ctx context.Context, c client.Reader, workload *v1alpha1.Workload) {
+	ref := workload.Status.SupplyChainRef
+	if ref.Name == "" {
+		return
+	}
+
+	supplyChainObject, err := get(ctx, c, v1alpha1.SchemeGroupVersion.WithKind(ref.Kind), ref.Namespace, ref.Name)
+	if err != nil {
+		b.addError(fmt.Errorf("get %s '%s': %w", ref.Kind, ref.Name, err))
+		return
+	}
+	b.SupplyChain = sanitize(supplyChainObject)
+
+	spec := &v1alpha1.SupplyChainSpec{}
+	specFields, _, _ := unstructured.NestedMap(supplyChainObject.Object, "spec")
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(specFields, spec); err != nil {
+		b.addError(fmt.Errorf("convert %s '%s': %w", ref.Kind, ref.Name, err))
+		return
+	}
+
+	for i := range spec.Components {
+		component := &spec.Components[i]
+		templateRef, err := realizer.SelectTemplateRef(workload, component)
+		if err != nil {
+			b.addError(fmt.Errorf("select template of component '%s': %w", component.Name, err))
+			continue
+		}
+
+		template, err := getTemplate(ctx, c, templateRef, ref.Namespace)
+		if err != nil {
+			b.addError(fmt.Errorf("get %s '%s' of component '%s': %w", templateRef.Kind, templateRef.Name, component.Name, err))
+			continue
+		}
+		b.Templates = append(b.Templates, sanitize(template))
+	}
+}
+
+// getTemplate gets the template a component refers to, preferring its
+// namespace-scoped counterpart in the namespace of a SupplyChain, as the
+// realizer does.
+func getTemplate(ctx context.Context, c client.Reader, ref v1alpha1.ClusterTemplateReference, namespace string) (*unstructured.Unstructured, error) {
+	if namespace != "" {
+		namespacedKind := strings.TrimPrefix(ref.Kind, "Cluster")
+		template, err := get(ctx, c, v1alpha1.SchemeGroupVersion.WithKind(namespacedKind), namespace, ref.Name)
+		if !kerrors.IsNotFound(err) {
+			return template, err
+		}
+	}
+
+	return get(ctx, c, v1alpha1.SchemeGroupVersion.WithKind(ref.Kind), "", ref.Name)
+}
+
+func (b *Bundle) collectStampedObjects(ctx context.Context, c client.Reader, workload *v1alpha1.Workload, uids map[types.UID]bool) {
+	for _, component := range workload.Status.Components {
+		ref := component.StampedRef
+		if ref == nil {
+			continue
+		}
+
+		stamped, err := get(ctx, c, schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind), ref.Namespace, ref.Name)
+		if err != nil {
+			b.addError(fmt.Errorf("get stamped object of component '%s': %w", component.Name, err))
+			continue
+		}
+		uids[stamped.GetUID()] = true
+		b.StampedObjects = append(b.StampedObjects, sanitize(stamped))
+	}
+}
+
+func (b *Bundle) collectEvents(ctx context.Context, c client.Reader, namespace string, uids map[types.UID]bool) {
+	events := &corev1.EventList{}
+	if err := c.List(ctx, events, client.InNamespace(namespace)); err != nil {
+		b.addError(fmt.Errorf("list events: %w", err))
+		return
+	}
+
+	for _, event := range events.Items {
+		if uids[event.InvolvedObject.UID] {
+			event.ManagedFields = nil
+			b.Events = append(b.Events, event)
+		}
+	}
+
+	sort.SliceStable(b.Events, func(i, j int) bool {
+		return eventTime(b.Events[i]).Before(eventTime(b.Events[j]))
+	})
+	if len(b.Events) > maxEvents {
+		b.Events = b.Events[len(b.Events)-maxEvents:]
+	}
+}
+
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+func (b *Bundle) addError(err error) {
+	b.Errors = append(b.Errors, err.Error())
+}
+
+func get(ctx context.Context, c client.Reader, gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// sanitize drops the bookkeeping fields of obj, and redacts the values of
+// a secret and of the literal environment variables of any container.
+func sanitize(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "annotations", corev1.LastAppliedConfigAnnotation)
+
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			values, found, _ := unstructured.NestedMap(obj.Object, field)
+			if !found {
+				continue
+			}
+			for key := range values {
+				values[key] = redacted
+			}
+			_ = unstructured.SetNestedMap(obj.Object, values, field)
+		}
+	}
+
+	redactEnv(obj.Object)
+	return obj
+}
+
+// redactEnv redacts the value of every environment variable listed under an
+// env field, leaving references to secrets and config maps as they are.
+func redactEnv(value interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, field := range typed {
+			if env, ok := field.([]interface{}); ok && key == "env" {
+				for _, variable := range env {
+					if variable, ok := variable.(map[string]interface{}); ok {
+						if _, ok := variable["value"]; ok {
+							variable["value"] = redacted
+						}
+					}
+				}
+				continue
+			}
+			redactEnv(field)
+		}
+	case []interface{}:
+		for _, item := range typed {
+			redactEnv(item)
+		}
+	}
+}
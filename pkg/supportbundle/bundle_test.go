@@ -0,0 +1,204 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/supportbundle"
+)
+
+var _ = Describe("Generate", func() {
+	var (
+		ctx      context.Context
+		objects  []client.Object
+		workload *v1alpha1.Workload
+		logs     *supportbundle.LogRecorder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		logs = supportbundle.NewLogRecorder(10)
+
+		workload = &v1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{Name: "petclinic", Namespace: "dev", UID: "workload-uid"},
+			Spec: v1alpha1.WorkloadSpec{
+				Env: []corev1.EnvVar{{Name: "DB_PASSWORD", Value: "hunter2"}},
+			},
+			Status: v1alpha1.WorkloadStatus{
+				SupplyChainRef: v1alpha1.WorkloadSupplyChainReference{Kind: "ClusterSupplyChain", Name: "web"},
+				Conditions: []metav1.Condition{
+					{Type: "Ready", Status: metav1.ConditionFalse, Reason: "TemplateStampFailure", LastTransitionTime: metav1.Now()},
+				},
+				Components: []v1alpha1.ComponentStatus{{
+					Name:       "config",
+					State:      v1alpha1.RealizedComponentState,
+					StampedRef: &corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "dev", Name: "petclinic-config"},
+				}},
+			},
+		}
+
+		objects = []client.Object{
+			workload,
+			&v1alpha1.ClusterSupplyChain{
+				ObjectMeta: metav1.ObjectMeta{Name: "web"},
+				Spec: v1alpha1.SupplyChainSpec{
+					Components: []v1alpha1.SupplyChainComponent{{
+						Name:        "config",
+						TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterTemplate", Name: "app-config"},
+					}},
+				},
+			},
+			&v1alpha1.ClusterTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+				Spec:       v1alpha1.TemplateSpec{Ytt: "some: template"},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "petclinic-config",
+					Namespace: "dev",
+					UID:       "secret-uid",
+					Annotations: map[string]string{
+						corev1.LastAppliedConfigAnnotation: `{"data": {"password": "aHVudGVyMg=="}}`,
+						"owner":                            "team-a",
+					},
+				},
+				Data: map[string][]byte{"password": []byte("hunter2")},
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "petclinic.2", Namespace: "dev"},
+				InvolvedObject: corev1.ObjectReference{UID: "secret-uid"},
+				Reason:         "Updated",
+				LastTimestamp:  metav1.NewTime(time.Unix(200, 0)),
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "petclinic.1", Namespace: "dev"},
+				InvolvedObject: corev1.ObjectReference{UID: "workload-uid"},
+				Reason:         "TemplateStampFailure",
+				LastTimestamp:  metav1.NewTime(time.Unix(100, 0)),
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "other", Namespace: "dev"},
+				InvolvedObject: corev1.ObjectReference{UID: "other-uid"},
+				Reason:         "Unrelated",
+			},
+		}
+	})
+
+	generate := func() (*supportbundle.Bundle, error) {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+		return supportbundle.Generate(ctx, c, logs, "dev", "petclinic")
+	}
+
+	It("collects the workload with its conditions", func() {
+		bundle, err := generate()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bundle.Workload.GetName()).To(Equal("petclinic"))
+		conditions, _, _ := unstructured.NestedSlice(bundle.Workload.Object, "status", "conditions")
+		Expect(conditions).To(HaveLen(1))
+		Expect(bundle.Errors).To(BeEmpty())
+	})
+
+	It("collects the supply chain and the templates of its components", func() {
+		bundle, err := generate()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bundle.SupplyChain.GetName()).To(Equal("web"))
+		Expect(bundle.Templates).To(HaveLen(1))
+		Expect(bundle.Templates[0].GetKind()).To(Equal("ClusterTemplate"))
+		Expect(bundle.Templates[0].GetName()).To(Equal("app-config"))
+	})
+
+	It("collects the stamped objects, redacting secrets", func() {
+		bundle, err := generate()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bundle.StampedObjects).To(HaveLen(1))
+		secret := bundle.StampedObjects[0]
+		Expect(secret.GetName()).To(Equal("petclinic-config"))
+		Expect(secret.Object["data"]).To(Equal(map[string]interface{}{"password": "<redacted>"}))
+		Expect(secret.GetAnnotations()).To(Equal(map[string]string{"owner": "team-a"}))
+	})
+
+	It("redacts the values of environment variables", func() {
+		bundle, err := generate()
+		Expect(err).NotTo(HaveOccurred())
+
+		env, _, _ := unstructured.NestedSlice(bundle.Workload.Object, "spec", "env")
+		Expect(env).To(Equal([]interface{}{
+			map[string]interface{}{"name": "DB_PASSWORD", "value": "<redacted>"},
+		}))
+	})
+
+	It("collects the events of the workload and its stamped objects, oldest first", func() {
+		bundle, err := generate()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bundle.Events).To(HaveLen(2))
+		Expect(bundle.Events[0].Reason).To(Equal("TemplateStampFailure"))
+		Expect(bundle.Events[1].Reason).To(Equal("Updated"))
+	})
+
+	It("collects the log lines about the workload", func() {
+		logger := logs.Logger(zap.New(zap.WriteTo(GinkgoWriter)))
+		logger.WithValues("name", "petclinic", "namespace", "dev").Info("started")
+		logger.WithValues("name", "petclinic", "namespace", "prod").Info("started")
+		logger.WithValues("name", "petclinic", "namespace", "dev").Error(errors.New("stamp failed"), "failed")
+
+		bundle, err := generate()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bundle.Logs).To(HaveLen(2))
+		Expect(bundle.Logs[0].Message).To(Equal("started"))
+		Expect(bundle.Logs[1].Error).To(Equal("stamp failed"))
+	})
+
+	It("lists what it could not collect", func() {
+		objects = objects[:2]
+
+		bundle, err := generate()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bundle.Errors).To(ConsistOf(
+			ContainSubstring("get ClusterTemplate 'app-config' of component 'config'"),
+			ContainSubstring("get stamped object of component 'config'"),
+		))
+	})
+
+	It("returns an error when the workload does not exist", func() {
+		objects = nil
+
+		_, err := generate()
+		Expect(err).To(MatchError(ContainSubstring("get workload")))
+	})
+})
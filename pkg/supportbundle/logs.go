@@ -0,0 +1,143 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LogEntry is a line the controllers logged. Its values are formatted as
+// strings, so that the objects logged are not kept alive.
+type LogEntry struct {
+	Time    metav1.Time       `json:"time"`
+	Logger  string            `json:"logger,omitempty"`
+	Message string            `json:"message"`
+	Error   string            `json:"error,omitempty"`
+	Values  map[string]string `json:"values,omitempty"`
+}
+
+// LogRecorder keeps the latest lines logged through its Logger.
+type LogRecorder struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int
+	full    bool
+}
+
+// NewLogRecorder keeps the latest size lines.
+func NewLogRecorder(size int) *LogRecorder {
+	return &LogRecorder{entries: make([]LogEntry, size)}
+}
+
+// Logger returns a logger that logs to delegate, recording the lines that
+// delegate has enabled.
+func (r *LogRecorder) Logger(delegate logr.Logger) logr.Logger {
+	return &recordingLogger{delegate: delegate, recorder: r}
+}
+
+// Entries returns the recorded lines that match, oldest first.
+func (r *LogRecorder) Entries(match func(LogEntry) bool) []LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ordered := r.entries[:r.next]
+	if r.full {
+		ordered = append(append([]LogEntry{}, r.entries[r.next:]...), r.entries[:r.next]...)
+	}
+
+	var result []LogEntry
+	for _, entry := range ordered {
+		if match(entry) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+func (r *LogRecorder) record(entry LogEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entries) == 0 {
+		return
+	}
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+type recordingLogger struct {
+	delegate logr.Logger
+	recorder *LogRecorder
+	name     string
+	values   []interface{}
+}
+
+func (l *recordingLogger) Enabled() bool {
+	return l.delegate.Enabled()
+}
+
+func (l *recordingLogger) Info(msg string, keysAndValues ...interface{}) {
+	if l.delegate.Enabled() {
+		l.recorder.record(l.entry(msg, nil, keysAndValues))
+	}
+	l.delegate.Info(msg, keysAndValues...)
+}
+
+func (l *recordingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.recorder.record(l.entry(msg, err, keysAndValues))
+	l.delegate.Error(err, msg, keysAndValues...)
+}
+
+func (l *recordingLogger) V(level int) logr.Logger {
+	return &recordingLogger{delegate: l.delegate.V(level), recorder: l.recorder, name: l.name, values: l.values}
+}
+
+func (l *recordingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	values := append(append([]interface{}{}, l.values...), keysAndValues...)
+	return &recordingLogger{delegate: l.delegate.WithValues(keysAndValues...), recorder: l.recorder, name: l.name, values: values}
+}
+
+func (l *recordingLogger) WithName(name string) logr.Logger {
+	fullName := name
+	if l.name != "" {
+		fullName = l.name + "." + name
+	}
+	return &recordingLogger{delegate: l.delegate.WithName(name), recorder: l.recorder, name: fullName, values: l.values}
+}
+
+func (l *recordingLogger) entry(msg string, err error, keysAndValues []interface{}) LogEntry {
+	entry := LogEntry{
+		Time:    metav1.Now(),
+		Logger:  l.name,
+		Message: msg,
+		Values:  map[string]string{},
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	all := append(append([]interface{}{}, l.values...), keysAndValues...)
+	for i := 0; i+1 < len(all); i += 2 {
+		entry.Values[fmt.Sprint(all[i])] = fmt.Sprint(all[i+1])
+	}
+	return entry
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/vmware-tanzu/cartographer/pkg/supportbundle"
+)
+
+var _ = Describe("LogRecorder", func() {
+	all := func(supportbundle.LogEntry) bool { return true }
+
+	It("keeps the latest lines, oldest first", func() {
+		logs := supportbundle.NewLogRecorder(2)
+		logger := logs.Logger(zap.New(zap.WriteTo(GinkgoWriter)))
+
+		logger.Info("first")
+		logger.Info("second")
+		logger.Info("third")
+
+		entries := logs.Entries(all)
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].Message).To(Equal("second"))
+		Expect(entries[1].Message).To(Equal("third"))
+	})
+
+	It("records the name and values of the logger", func() {
+		logs := supportbundle.NewLogRecorder(2)
+		logger := logs.Logger(zap.New(zap.WriteTo(GinkgoWriter))).
+			WithName("controller").WithName("workload").
+			WithValues("name", "petclinic")
+
+		logger.Info("started", "attempt", 2)
+
+		entries := logs.Entries(all)
+		Expect(entries[0].Logger).To(Equal("controller.workload"))
+		Expect(entries[0].Values).To(Equal(map[string]string{"name": "petclinic", "attempt": "2"}))
+	})
+
+	It("does not record the lines its delegate does not log", func() {
+		logs := supportbundle.NewLogRecorder(2)
+		logger := logs.Logger(zap.New(zap.WriteTo(GinkgoWriter)))
+
+		logger.V(5).Info("verbose")
+
+		Expect(logs.Entries(all)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,105 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Path is where the handler serves the bundle of a workload, as
+// Path/<namespace>/<name>.
+const Path = "/support-bundle/workloads"
+
+// NewHandler serves the bundle of a workload as a JSON attachment.
+func NewHandler(c client.Reader, logs *LogRecorder) http.Handler {
+	return &handler{client: c, logs: logs}
+}
+
+type handler struct {
+	client client.Reader
+	logs   *LogRecorder
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, Path+"/"), "/")
+	if !strings.HasPrefix(req.URL.Path, Path+"/") || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, fmt.Sprintf("expected %s/<namespace>/<name>", Path), http.StatusNotFound)
+		return
+	}
+	namespace, name := parts[0], parts[1]
+
+	bundle, err := Generate(req.Context(), h.client, h.logs, namespace, name)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if kerrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s-support-bundle.json"`, namespace, name))
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(bundle)
+}
+
+// Server serves a handler on an address for as long as the manager it is
+// added to runs.
+type Server struct {
+	Addr    string
+	Handler http.Handler
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path+"/", s.Handler)
+	server := &http.Server{Handler: mux}
+
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("serve: %w", err)
+	}
+	<-done
+	return nil
+}
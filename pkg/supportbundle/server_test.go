@@ -0,0 +1,76 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/supportbundle"
+)
+
+var _ = Describe("Handler", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&v1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{Name: "petclinic", Namespace: "dev"},
+		}).Build()
+
+		handler = supportbundle.NewHandler(c, nil)
+	})
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder
+	}
+
+	It("serves the bundle of a workload as an attachment", func() {
+		response := serve(http.MethodGet, "/support-bundle/workloads/dev/petclinic")
+
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.Header().Get("Content-Disposition")).To(Equal(`attachment; filename="dev-petclinic-support-bundle.json"`))
+
+		bundle := map[string]interface{}{}
+		Expect(json.Unmarshal(response.Body.Bytes(), &bundle)).To(Succeed())
+		Expect(bundle["workload"]).To(HaveKeyWithValue("metadata", HaveKeyWithValue("name", "petclinic")))
+	})
+
+	It("responds not found for a workload that does not exist", func() {
+		response := serve(http.MethodGet, "/support-bundle/workloads/dev/other")
+		Expect(response.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("responds not found for a path that does not name a workload", func() {
+		response := serve(http.MethodGet, "/support-bundle/workloads/dev")
+		Expect(response.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("only serves GET requests", func() {
+		response := serve(http.MethodPost, "/support-bundle/workloads/dev/petclinic")
+		Expect(response.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
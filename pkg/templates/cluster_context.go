@@ -0,0 +1,26 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+// ClusterContext describes the cluster the controller runs in. It is
+// available to every template as $(clusterContext.<field>)$, so that the
+// same supply chains and templates can be installed on many clusters
+// without repeating per-cluster params.
+type ClusterContext struct {
+	Name          string `json:"name"`
+	Region        string `json:"region"`
+	IngressDomain string `json:"ingressDomain"`
+	Registry      string `json:"registry"`
+}
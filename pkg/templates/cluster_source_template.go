@@ -67,6 +67,10 @@ func (t clusterSourceTemplate) GetResourceTemplate() v1alpha1.TemplateSpec {
 	return t.template.Spec.TemplateSpec
 }
 
+func (t clusterSourceTemplate) GetArtifactSource() *v1alpha1.ArtifactSource {
+	return t.template.Spec.Artifact
+}
+
 func (t clusterSourceTemplate) GetDefaultParams() v1alpha1.DefaultParams {
 	return t.template.Spec.Params
 }
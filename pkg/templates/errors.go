@@ -36,4 +36,11 @@ func (e JsonPathError) Error() string {
 
 func (e JsonPathError) JsonPathExpression() string {
 	return e.expression
-}
\ No newline at end of file
+}
+type ImmutableParamError struct {
+	Name string
+}
+
+func (e ImmutableParamError) Error() string {
+	return fmt.Sprintf("param '%s' is immutable in the supply chain and may not be set by the workload", e.Name)
+}
@@ -34,6 +34,15 @@ type Inputs struct {
 	Sources map[string]SourceInput
 	Images  map[string]ImageInput
 	Configs map[string]ConfigInput
+	// Collections holds the same inputs in the order the component lists
+	// them, for templates that merge several inputs of a kind.
+	Collections InputCollections
+}
+
+type InputCollections struct {
+	Sources []SourceInput `json:"sources"`
+	Images  []ImageInput  `json:"images"`
+	Configs []ConfigInput `json:"configs"`
 }
 
 func (i Inputs) OnlySource() *SourceInput {
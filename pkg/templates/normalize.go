@@ -0,0 +1,96 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// quantityListKeys name the maps, found under a "resources" key, whose values
+// are resource quantities.
+var quantityListKeys = map[string]bool{
+	"limits":   true,
+	"requests": true,
+}
+
+// normalize rewrites stamped content so that renders which the API server
+// would treat identically compare equal. Null fields are pruned, and resource
+// quantities are rewritten in their canonical form. Empty maps and lists are
+// kept, as they are meaningful in places such as `emptyDir: {}` or an
+// `args: []` that clears the arguments of an image.
+func normalize(content map[string]interface{}) {
+	normalizeMap(content, "")
+}
+
+func normalizeMap(m map[string]interface{}, parentKey string) {
+	for key, value := range m {
+		if parentKey == "resources" && quantityListKeys[key] {
+			if quantities, ok := value.(map[string]interface{}); ok {
+				normalizeQuantities(quantities)
+			}
+		}
+
+		normalized, keep := normalizeValue(value, key)
+		if !keep {
+			delete(m, key)
+			continue
+		}
+		m[key] = normalized
+	}
+}
+
+func normalizeValue(value interface{}, key string) (interface{}, bool) {
+	switch typed := value.(type) {
+	case nil:
+		return nil, false
+	case map[string]interface{}:
+		normalizeMap(typed, key)
+		return typed, true
+	case []interface{}:
+		kept := []interface{}{}
+		for _, element := range typed {
+			if normalized, keep := normalizeValue(element, ""); keep {
+				kept = append(kept, normalized)
+			}
+		}
+		return kept, true
+	default:
+		return typed, true
+	}
+}
+
+func normalizeQuantities(quantities map[string]interface{}) {
+	for name, value := range quantities {
+		var raw string
+		switch typed := value.(type) {
+		case string:
+			raw = typed
+		case float64:
+			raw = strconv.FormatFloat(typed, 'f', -1, 64)
+		case int64:
+			raw = strconv.FormatInt(typed, 10)
+		default:
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(raw)
+		if err != nil {
+			continue
+		}
+		quantities[name] = quantity.String()
+	}
+}
@@ -22,18 +22,36 @@ import (
 
 type Params map[string]apiextensionsv1.JSON
 
-func ParamsBuilder(defaultParams v1alpha1.DefaultParams, componentParams []v1alpha1.SupplyChainParam) Params {
+// ParamsBuilder merges the values of the params a template declares. A
+// template's default is overridden by the value of the supply chain
+// component, which is in turn overridden by the value of the workload,
+// unless the component marks the param immutable. Values for params the
+// template does not declare are ignored.
+func ParamsBuilder(defaultParams v1alpha1.DefaultParams, componentParams []v1alpha1.SupplyChainParam, workloadParams []v1alpha1.WorkloadParam) (Params, error) {
 	newParams := Params{}
 	for _, param := range defaultParams {
 		newParams[param.Name] = param.DefaultValue
 	}
 
+	immutable := map[string]bool{}
 	for key := range newParams {
 		for _, override := range componentParams {
 			if key == override.Name {
 				newParams[key] = override.Value
+				immutable[key] = override.Immutable
 			}
 		}
 	}
-	return newParams
+
+	for key := range newParams {
+		for _, override := range workloadParams {
+			if key == override.Name {
+				if immutable[key] {
+					return nil, ImmutableParamError{Name: key}
+				}
+				newParams[key] = override.Value
+			}
+		}
+	}
+	return newParams, nil
 }
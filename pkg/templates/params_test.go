@@ -48,11 +48,61 @@ var _ = Describe("OverrideDefaultParams", func() {
 					},
 				},
 			}
-			params := templates.ParamsBuilder(defaultParams, componentParams)
+			params, err := templates.ParamsBuilder(defaultParams, componentParams, nil)
+			Expect(err).NotTo(HaveOccurred())
 
 			Expect(params).To(HaveLen(2))
 			Expect(params["foo"].Raw).To(Equal([]byte("bar")))
 			Expect(params["fizz"].Raw).To(Equal([]byte("buzz")))
 		})
+
+		Context("when the workload sets params", func() {
+			var (
+				defaultParams   v1alpha1.DefaultParams
+				componentParams []v1alpha1.SupplyChainParam
+				workloadParams  []v1alpha1.WorkloadParam
+			)
+
+			BeforeEach(func() {
+				defaultParams = v1alpha1.DefaultParams{
+					{Name: "foo", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"bar"`)}},
+					{Name: "fizz", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"baz"`)}},
+				}
+				componentParams = []v1alpha1.SupplyChainParam{
+					{Name: "fizz", Value: apiextensionsv1.JSON{Raw: []byte(`"buzz"`)}},
+				}
+				workloadParams = []v1alpha1.WorkloadParam{
+					{Name: "foo", Value: apiextensionsv1.JSON{Raw: []byte(`"workload-foo"`)}},
+					{Name: "fizz", Value: apiextensionsv1.JSON{Raw: []byte(`"workload-fizz"`)}},
+					{Name: "undeclared", Value: apiextensionsv1.JSON{Raw: []byte(`"ignored"`)}},
+				}
+			})
+
+			It("overrides the defaults and the supply chain values with the workload values", func() {
+				params, err := templates.ParamsBuilder(defaultParams, componentParams, workloadParams)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(params).To(HaveLen(2))
+				Expect(params["foo"].Raw).To(Equal([]byte(`"workload-foo"`)))
+				Expect(params["fizz"].Raw).To(Equal([]byte(`"workload-fizz"`)))
+			})
+
+			Context("and the supply chain marks a param immutable", func() {
+				BeforeEach(func() {
+					componentParams[0].Immutable = true
+				})
+
+				It("forbids the workload from overriding it", func() {
+					_, err := templates.ParamsBuilder(defaultParams, componentParams, workloadParams)
+					Expect(err).To(MatchError("param 'fizz' is immutable in the supply chain and may not be set by the workload"))
+				})
+
+				It("uses the supply chain value when the workload does not set it", func() {
+					params, err := templates.ParamsBuilder(defaultParams, componentParams, workloadParams[:1])
+					Expect(err).NotTo(HaveOccurred())
+					Expect(params["fizz"].Raw).To(Equal([]byte(`"buzz"`)))
+				})
+			})
+		})
 	})
 })
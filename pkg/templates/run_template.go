@@ -32,12 +32,17 @@ type RunTemplate interface {
 	GetName() string
 	GetResourceTemplate() v1alpha1.TemplateSpec
 	GetOutput(stampedObjects []*unstructured.Unstructured) (Outputs, error)
+	ValidateInputs(inputs map[string]apiextensionsv1.JSON) error
 }
 
 type runTemplate struct {
 	template *v1alpha1.RunTemplate
 }
 
+func (t runTemplate) ValidateInputs(inputs map[string]apiextensionsv1.JSON) error {
+	return t.template.Spec.ValidateInputs(inputs)
+}
+
 func (t runTemplate) GetOutput(stampedObjects []*unstructured.Unstructured) (Outputs, error) {
 	var (
 		updateError                        error
@@ -127,6 +132,21 @@ func (t runTemplate) getOutputsOfSingleObject(evaluator eval.Evaluator, stampedO
 		ext := apiextensionsv1.JSON{Raw: result}
 		provisionalOutputs[key] = ext
 	}
+	for key, name := range t.template.Spec.TektonResults {
+		output, err := tektonResult(stampedObject, name)
+		if err != nil {
+			objectErr = fmt.Errorf("get output: %w", err)
+			continue
+		}
+
+		result, err := json.Marshal(output)
+		if err != nil {
+			objectErr = fmt.Errorf("get output could not marshal Tekton result: %w", err)
+			continue
+		}
+
+		provisionalOutputs[key] = apiextensionsv1.JSON{Raw: result}
+	}
 	return objectErr, provisionalOutputs
 }
 
@@ -142,6 +142,36 @@ var _ = Describe("RunTemplate", func() {
 				})
 			})
 
+			Context("with Tekton results", func() {
+				BeforeEach(func() {
+					Expect(unstructured.SetNestedSlice(firstStampedObject.Object, []interface{}{
+						map[string]interface{}{"name": "image-url", "value": "registry.example.com/app@sha256:abc"},
+					}, "status", "pipelineResults")).To(Succeed())
+					Expect(unstructured.SetNestedSlice(firstStampedObject.Object, []interface{}{
+						map[string]interface{}{"name": "commit", "value": "a1b2c3"},
+					}, "status", "results")).To(Succeed())
+					apiTemplate.Spec.TektonResults = map[string]string{
+						"url":      "image-url",
+						"revision": "commit",
+					}
+				})
+
+				It("returns the values of the results by name", func() {
+					template := templates.NewRunTemplateModel(apiTemplate)
+					outputs, err := template.GetOutput(stampedObjects)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(outputs["url"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"registry.example.com/app@sha256:abc"`)}))
+					Expect(outputs["revision"]).To(Equal(apiextensionsv1.JSON{Raw: []byte(`"a1b2c3"`)}))
+				})
+
+				It("returns an error for a result the run does not have", func() {
+					apiTemplate.Spec.TektonResults["digest"] = "image-digest"
+					template := templates.NewRunTemplateModel(apiTemplate)
+					_, err := template.GetOutput(stampedObjects)
+					Expect(err).To(MatchError("get output: Tekton result 'image-digest' not found"))
+				})
+			})
+
 			Context("with invalid output paths defined", func() {
 				BeforeEach(func() {
 					apiTemplate.Spec.Outputs = map[string]string{
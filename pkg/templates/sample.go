@@ -0,0 +1,83 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// StampSample stamps the template for its sample workload, as a component
+// of a supply chain would.
+func StampSample(ctx context.Context, template v1alpha1.TemplateSpec, clusterContext ClusterContext) (*unstructured.Unstructured, error) {
+	if template.Sample == nil {
+		return nil, errors.New("template has no sample")
+	}
+
+	workload := &v1alpha1.Workload{}
+	if err := json.Unmarshal(template.Sample.Workload.Raw, workload); err != nil {
+		return nil, fmt.Errorf("unmarshal workload: %w", err)
+	}
+
+	templatingContext := map[string]interface{}{}
+	if template.Sample.Context != nil && len(template.Sample.Context.Raw) > 0 {
+		if err := json.Unmarshal(template.Sample.Context.Raw, &templatingContext); err != nil {
+			return nil, fmt.Errorf("unmarshal context: %w", err)
+		}
+	}
+	templatingContext["workload"] = workload
+
+	if _, ok := templatingContext["params"]; !ok {
+		params, err := ParamsBuilder(template.Params, nil, workload.Spec.Params)
+		if err != nil {
+			return nil, fmt.Errorf("params: %w", err)
+		}
+		templatingContext["params"] = params
+	}
+
+	if _, ok := templatingContext["clusterContext"]; !ok {
+		templatingContext["clusterContext"] = clusterContext
+	}
+
+	stampContext := StamperBuilder(workload, templatingContext, nil)
+	return stampContext.Stamp(ctx, template)
+}
+
+// StampRunTemplateSample stamps the RunTemplate for its sample pipeline, as
+// the pipeline would.
+func StampRunTemplateSample(ctx context.Context, template v1alpha1.RunTemplateSpec, clusterContext ClusterContext) (*unstructured.Unstructured, error) {
+	if template.Sample == nil {
+		return nil, errors.New("template has no sample")
+	}
+
+	pipeline := &v1alpha1.Pipeline{}
+	if err := json.Unmarshal(template.Sample.Pipeline.Raw, pipeline); err != nil {
+		return nil, fmt.Errorf("unmarshal pipeline: %w", err)
+	}
+
+	templatingContext := map[string]interface{}{
+		"pipeline":       pipeline,
+		"clusterContext": clusterContext,
+	}
+
+	stampContext := StamperBuilder(pipeline, templatingContext, nil)
+	return stampContext.Stamp(ctx, v1alpha1.TemplateSpec{Template: &template.Template})
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+var _ = Describe("StampSample", func() {
+	var template v1alpha1.TemplateSpec
+
+	BeforeEach(func() {
+		template = v1alpha1.TemplateSpec{
+			Template: &runtime.RawExtension{Raw: []byte(`{
+				"apiVersion": "v1",
+				"kind": "ConfigMap",
+				"metadata": {"name": "$(workload.metadata.name)$-config"},
+				"data": {"port": "$(params.port)$", "region": "$(clusterContext.region)$", "url": "$(source.url)$"}
+			}`)},
+			Params: v1alpha1.DefaultParams{{Name: "port", DefaultValue: apiextensionsv1.JSON{Raw: []byte(`"8080"`)}}},
+			Sample: &v1alpha1.TemplateSample{
+				Workload: runtime.RawExtension{Raw: []byte(`{
+					"metadata": {"name": "petclinic", "namespace": "dev"},
+					"spec": {"params": [{"name": "port", "value": "9090"}]}
+				}`)},
+				Context: &runtime.RawExtension{Raw: []byte(`{"source": {"url": "https://example.com/source.tar.gz"}}`)},
+			},
+		}
+	})
+
+	It("stamps the template for its sample workload", func() {
+		stamped, err := templates.StampSample(context.TODO(), template, templates.ClusterContext{Region: "eu-west-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(stamped.GetName()).To(Equal("petclinic-config"))
+		Expect(stamped.GetNamespace()).To(Equal("dev"))
+		Expect(stamped.Object["data"]).To(Equal(map[string]interface{}{
+			"port":   "9090",
+			"region": "eu-west-1",
+			"url":    "https://example.com/source.tar.gz",
+		}))
+	})
+
+	It("returns an error when the template refers to what the sample does not provide", func() {
+		template.Sample.Context = nil
+
+		_, err := templates.StampSample(context.TODO(), template, templates.ClusterContext{})
+		Expect(err).To(MatchError(ContainSubstring("source.url")))
+	})
+
+	It("returns an error when the sample workload is not a workload", func() {
+		template.Sample.Workload = runtime.RawExtension{Raw: []byte(`"petclinic"`)}
+
+		_, err := templates.StampSample(context.TODO(), template, templates.ClusterContext{})
+		Expect(err).To(MatchError(ContainSubstring("unmarshal workload")))
+	})
+})
+
+var _ = Describe("StampRunTemplateSample", func() {
+	It("stamps the template for its sample pipeline", func() {
+		template := v1alpha1.RunTemplateSpec{
+			Template: runtime.RawExtension{Raw: []byte(`{
+				"apiVersion": "tekton.dev/v1beta1",
+				"kind": "TaskRun",
+				"metadata": {"generateName": "$(pipeline.metadata.name)$-"},
+				"spec": {"params": [{"name": "url", "value": "$(pipeline.spec.inputs.url)$"}]}
+			}`)},
+			Sample: &v1alpha1.RunTemplateSample{
+				Pipeline: runtime.RawExtension{Raw: []byte(`{
+					"metadata": {"name": "tests", "namespace": "dev"},
+					"spec": {"inputs": {"url": "https://example.com"}}
+				}`)},
+			},
+		}
+
+		stamped, err := templates.StampRunTemplateSample(context.TODO(), template, templates.ClusterContext{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stamped.GetGenerateName()).To(Equal("tests-"))
+		Expect(stamped.GetNamespace()).To(Equal("dev"))
+	})
+})
@@ -47,6 +47,9 @@ type Stamper struct {
 	TemplatingContext JsonPathContext
 	Owner             client.Object
 	Labels            Labels
+	// OwnerReferences chooses how stamped objects refer to the Owner.
+	// Defaults to a controller reference.
+	OwnerReferences v1alpha1.OwnerReferencePolicy
 }
 
 func StamperBuilder(owner client.Object, templatingContext JsonPathContext, labels Labels) Stamper {
@@ -148,25 +151,53 @@ func (s *Stamper) Stamp(ctx context.Context, resourceTemplate v1alpha1.TemplateS
 		return nil, err
 	}
 
+	normalize(stampedObject.Object)
+
 	if stampedObject.GetNamespace() == "" {
 		stampedObject.SetNamespace(s.Owner.GetNamespace())
 	}
 
+	stampedObject.SetOwnerReferences(s.ownerReferences())
+
+	s.mergeLabels(stampedObject)
+
+	return stampedObject, nil
+}
+
+func (s *Stamper) ownerReferences() []metav1.OwnerReference {
+	if s.OwnerReferences == v1alpha1.NoneOwnerReferencePolicy {
+		return nil
+	}
+
 	apiVersion, kind := s.Owner.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
-	stampedObject.SetOwnerReferences([]metav1.OwnerReference{
+	return []metav1.OwnerReference{
 		{
 			APIVersion:         apiVersion,
 			Kind:               kind,
 			UID:                s.Owner.GetUID(),
 			Name:               s.Owner.GetName(),
 			BlockOwnerDeletion: pointer.BoolPtr(true),
-			Controller:         pointer.BoolPtr(true),
+			Controller:         pointer.BoolPtr(s.OwnerReferences != v1alpha1.NonControllerOwnerReferencePolicy),
 		},
-	})
+	}
+}
 
-	s.mergeLabels(stampedObject)
+// Interpolate evaluates the tags in a single value, as they would be in a
+// template. The result must be a string or a number.
+func (s *Stamper) Interpolate(value string) (string, error) {
+	interpolated, err := s.recursivelyEvaluateTemplates(value, loopDetector{})
+	if err != nil {
+		return "", err
+	}
 
-	return stampedObject, nil
+	switch typed := interpolated.(type) {
+	case string:
+		return typed, nil
+	case nil, map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("'%s' does not interpolate to a string: %+v", value, interpolated)
+	default:
+		return fmt.Sprint(typed), nil
+	}
 }
 
 func (s *Stamper) applyTemplate(resourceTemplate []byte) (*unstructured.Unstructured, error) {
@@ -81,6 +81,34 @@ var _ = Describe("Stamper", func() {
 				}))
 			})
 
+			It("sets an owner reference that is not a controller's when asked to", func() {
+				stamper.OwnerReferences = v1alpha1.NonControllerOwnerReferencePolicy
+				template := v1alpha1.TemplateSpec{
+					Template: &runtime.RawExtension{
+						Raw: []byte(`{ "kind": "Silly", "apiVersion": "silly.io/v1"}`),
+					},
+				}
+				stamped, err := stamper.Stamp(context.TODO(), template)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stamped.GetOwnerReferences()).To(HaveLen(1))
+				Expect(stamped.GetOwnerReferences()[0].UID).To(Equal(types.UID("1234567890abcdef")))
+				Expect(stamped.GetOwnerReferences()[0].Controller).To(Equal(pointer.BoolPtr(false)))
+			})
+
+			It("sets no owner reference when asked not to", func() {
+				stamper.OwnerReferences = v1alpha1.NoneOwnerReferencePolicy
+				template := v1alpha1.TemplateSpec{
+					Template: &runtime.RawExtension{
+						Raw: []byte(`{ "kind": "Silly", "apiVersion": "silly.io/v1", "metadata": { "ownerReferences": [{ "name": "other" }] }}`),
+					},
+				}
+				stamped, err := stamper.Stamp(context.TODO(), template)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stamped.GetOwnerReferences()).To(BeEmpty())
+			})
+
 			Context("template does not specify a namespace", func() {
 				var template v1alpha1.TemplateSpec
 				BeforeEach(func() {
@@ -122,6 +150,68 @@ var _ = Describe("Stamper", func() {
 			})
 		})
 
+		Describe("normalization of the stamped object", func() {
+			var stamper templates.Stamper
+
+			BeforeEach(func() {
+				owner := &v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-config-map",
+						Namespace: "owner-ns",
+					},
+				}
+
+				stamper = templates.StamperBuilder(owner, struct{}{}, templates.Labels{})
+			})
+
+			stamp := func(raw string) map[string]interface{} {
+				stamped, err := stamper.Stamp(context.TODO(), v1alpha1.TemplateSpec{
+					Template: &runtime.RawExtension{Raw: []byte(raw)},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				return stamped.Object
+			}
+
+			It("prunes null fields", func() {
+				object := stamp(`{"kind": "Silly", "apiVersion": "silly.io/v1", "spec": {"a": null, "b": "c", "d": [null, "e"]}}`)
+
+				Expect(object["spec"]).To(Equal(map[string]interface{}{
+					"b": "c",
+					"d": []interface{}{"e"},
+				}))
+			})
+
+			It("keeps empty lists and empty maps", func() {
+				object := stamp(`{"kind": "Silly", "apiVersion": "silly.io/v1", "spec": {"args": [], "emptyDir": {}}}`)
+
+				Expect(object["spec"]).To(Equal(map[string]interface{}{
+					"args":     []interface{}{},
+					"emptyDir": map[string]interface{}{},
+				}))
+			})
+
+			It("renders semantically identical templates identically", func() {
+				first := stamp(`{"kind": "Silly", "apiVersion": "silly.io/v1", "spec": {"resources": {"limits": {"cpu": 1, "memory": "1024Mi"}, "requests": {"cpu": "500m"}}}}`)
+				second := stamp(`{"kind": "Silly", "apiVersion": "silly.io/v1", "spec": {"env": null, "resources": {"limits": {"cpu": "1000m", "memory": "1Gi"}, "requests": {"cpu": "0.5"}}}}`)
+
+				Expect(first).To(Equal(second))
+				Expect(first["spec"]).To(Equal(map[string]interface{}{
+					"resources": map[string]interface{}{
+						"limits":   map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+						"requests": map[string]interface{}{"cpu": "500m"},
+					},
+				}))
+			})
+
+			It("leaves quantity-like values outside of resources untouched", func() {
+				object := stamp(`{"kind": "Silly", "apiVersion": "silly.io/v1", "spec": {"limits": {"cpu": "1000m"}}}`)
+
+				Expect(object["spec"]).To(Equal(map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": "1000m"},
+				}))
+			})
+		})
+
 		DescribeTable("tag evaluation of template",
 			func(tmpl string, subJSON string, expected interface{}, expectedErr string) {
 				template := v1alpha1.TemplateSpec{
@@ -0,0 +1,44 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// tektonResultFields are the fields of the status of a Tekton run holding
+// its results: results in v1, pipelineResults of a v1beta1 PipelineRun and
+// taskResults of a v1beta1 TaskRun.
+var tektonResultFields = []string{"results", "pipelineResults", "taskResults"}
+
+// tektonResult is the value of the result of the PipelineRun or TaskRun with
+// the name. A value is a string, or an array or object of strings.
+func tektonResult(run unstructured.Unstructured, name string) (interface{}, error) {
+	for _, field := range tektonResultFields {
+		results, _, _ := unstructured.NestedSlice(run.UnstructuredContent(), "status", field)
+		for _, result := range results {
+			fields, ok := result.(map[string]interface{})
+			if !ok || fields["name"] != name {
+				continue
+			}
+			if value, ok := fields["value"]; ok {
+				return value, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Tekton result '%s' not found", name)
+}
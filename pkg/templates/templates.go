@@ -32,6 +32,12 @@ type Template interface {
 	GetKind() string
 }
 
+// ArtifactTemplate is implemented by templates that may provide their output
+// from a package registry rather than from a stamped object.
+type ArtifactTemplate interface {
+	GetArtifactSource() *v1alpha1.ArtifactSource
+}
+
 func NewModelFromAPI(template client.Object) (Template, error) {
 	switch v := template.(type) {
 
@@ -43,6 +49,17 @@ func NewModelFromAPI(template client.Object) (Template, error) {
 		return NewClusterConfigTemplateModel(v, eval.EvaluatorBuilder()), nil
 	case *v1alpha1.ClusterTemplate:
 		return NewClusterTemplateModel(v), nil
+
+	// Namespaced templates share the specs of the cluster-scoped ones, and so
+	// their models.
+	case *v1alpha1.SourceTemplate:
+		return NewClusterSourceTemplateModel(&v1alpha1.ClusterSourceTemplate{TypeMeta: v.TypeMeta, ObjectMeta: v.ObjectMeta, Spec: v.Spec}, eval.EvaluatorBuilder()), nil
+	case *v1alpha1.ImageTemplate:
+		return NewClusterImageTemplateModel(&v1alpha1.ClusterImageTemplate{TypeMeta: v.TypeMeta, ObjectMeta: v.ObjectMeta, Spec: v.Spec}, eval.EvaluatorBuilder()), nil
+	case *v1alpha1.ConfigTemplate:
+		return NewClusterConfigTemplateModel(&v1alpha1.ClusterConfigTemplate{TypeMeta: v.TypeMeta, ObjectMeta: v.ObjectMeta, Spec: v.Spec}, eval.EvaluatorBuilder()), nil
+	case *v1alpha1.Template:
+		return NewClusterTemplateModel(&v1alpha1.ClusterTemplate{TypeMeta: v.TypeMeta, ObjectMeta: v.ObjectMeta, Spec: v.Spec}), nil
 	}
 	return nil, fmt.Errorf("component does not match a known template")
 }
@@ -17,6 +17,7 @@ package templates_test
 import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
@@ -94,6 +95,54 @@ var _ = Describe("Templates", func() {
 			})
 		})
 
+		Context("when passed a namespaced SourceTemplate", func() {
+			BeforeEach(func() {
+				apiTemplate = &v1alpha1.SourceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "team-template", Namespace: "team-ns"}}
+			})
+
+			ItDoesNotReturnAnError()
+
+			It("returns a template", func() {
+				Expect(templateModel.GetName()).To(Equal("team-template"))
+			})
+		})
+
+		Context("when passed a namespaced ImageTemplate", func() {
+			BeforeEach(func() {
+				apiTemplate = &v1alpha1.ImageTemplate{ObjectMeta: metav1.ObjectMeta{Name: "team-template", Namespace: "team-ns"}}
+			})
+
+			ItDoesNotReturnAnError()
+
+			It("returns a template", func() {
+				Expect(templateModel.GetName()).To(Equal("team-template"))
+			})
+		})
+
+		Context("when passed a namespaced ConfigTemplate", func() {
+			BeforeEach(func() {
+				apiTemplate = &v1alpha1.ConfigTemplate{ObjectMeta: metav1.ObjectMeta{Name: "team-template", Namespace: "team-ns"}}
+			})
+
+			ItDoesNotReturnAnError()
+
+			It("returns a template", func() {
+				Expect(templateModel.GetName()).To(Equal("team-template"))
+			})
+		})
+
+		Context("when passed a namespaced Template", func() {
+			BeforeEach(func() {
+				apiTemplate = &v1alpha1.Template{ObjectMeta: metav1.ObjectMeta{Name: "team-template", Namespace: "team-ns"}}
+			})
+
+			ItDoesNotReturnAnError()
+
+			It("returns a template", func() {
+				Expect(templateModel.GetName()).To(Equal("team-template"))
+			})
+		})
+
 		Context("when passed an unsupported object", func() {
 			BeforeEach(func() {
 				apiTemplate = &v1alpha1.Workload{}
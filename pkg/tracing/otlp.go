@@ -0,0 +1,178 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TracesPath is where an OpenTelemetry collector receives traces over
+// OTLP/HTTP.
+const TracesPath = "/v1/traces"
+
+// ServiceName is the service.name of the spans.
+const ServiceName = "cartographer"
+
+const scopeName = "github.com/vmware-tanzu/cartographer"
+
+// NewOTLPExporter exports spans to the OTLP/HTTP endpoint of a collector,
+// e.g. http://otel-collector:4318, in the JSON encoding of OTLP.
+func NewOTLPExporter(endpoint string, client *http.Client) Exporter {
+	return &otlpExporter{
+		url:    strings.TrimSuffix(endpoint, "/") + TracesPath,
+		client: client,
+	}
+}
+
+type otlpExporter struct {
+	url    string
+	client *http.Client
+}
+
+func (e *otlpExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(otlpRequest(spans))
+	if err != nil {
+		return fmt.Errorf("marshal spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post spans: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("post spans: collector responded %s", resp.Status)
+	}
+	return nil
+}
+
+// The types below are the subset of the OTLP trace request Cartographer
+// sends, as its JSON encoding names them.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+func otlpRequest(spans []SpanData) exportRequest {
+	scoped := scopeSpans{Scope: scope{Name: scopeName}}
+	for _, data := range spans {
+		s := span{
+			TraceID:           hex.EncodeToString(data.TraceID[:]),
+			SpanID:            hex.EncodeToString(data.SpanID[:]),
+			Name:              data.Name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(data.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(data.End.UnixNano(), 10),
+			Attributes:        keyValues(data.Attributes),
+		}
+		if data.ParentID != (SpanID{}) {
+			s.ParentSpanID = hex.EncodeToString(data.ParentID[:])
+		}
+		if data.Error != "" {
+			s.Status = status{Code: statusCodeError, Message: data.Error}
+		}
+		scoped.Spans = append(scoped.Spans, s)
+	}
+
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource:   resource{Attributes: keyValues([]Attribute{String("service.name", ServiceName)})},
+			ScopeSpans: []scopeSpans{scoped},
+		}},
+	}
+}
+
+func keyValues(attributes []Attribute) []keyValue {
+	var kvs []keyValue
+	for _, attribute := range attributes {
+		var value anyValue
+		switch v := attribute.Value.(type) {
+		case int64:
+			s := strconv.FormatInt(v, 10)
+			value.IntValue = &s
+		default:
+			s := fmt.Sprint(v)
+			value.StringValue = &s
+		}
+		kvs = append(kvs, keyValue{Key: attribute.Key, Value: value})
+	}
+	return kvs
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// DefaultExportInterval is how often the spans that ended are exported.
+	DefaultExportInterval = 5 * time.Second
+	// maxQueuedSpans bounds the spans waiting to be exported; more are
+	// dropped rather than growing without limit while the collector is down.
+	maxQueuedSpans = 4096
+)
+
+// Exporter sends the spans that ended to where they are collected.
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+// Provider exports the spans that ended every Interval, for as long as the
+// manager it is added to runs, whether or not it is the leader.
+type Provider struct {
+	Exporter Exporter
+	Interval time.Duration
+	Logger   logr.Logger
+
+	mu     sync.Mutex
+	queued []SpanData
+}
+
+func NewProvider(exporter Exporter, logger logr.Logger) *Provider {
+	return &Provider{
+		Exporter: exporter,
+		Interval: DefaultExportInterval,
+		Logger:   logger,
+	}
+}
+
+func (p *Provider) record(span SpanData) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.queued) < maxQueuedSpans {
+		p.queued = append(p.queued, span)
+	}
+}
+
+func (p *Provider) NeedLeaderElection() bool {
+	return false
+}
+
+func (p *Provider) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.Flush(ctx)
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			p.Flush(flushCtx)
+			return nil
+		}
+	}
+}
+
+// Flush exports the spans that ended since the last export. Spans that fail
+// to export are dropped.
+func (p *Provider) Flush(ctx context.Context) {
+	p.mu.Lock()
+	spans := p.queued
+	p.queued = nil
+	p.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+	if err := p.Exporter.Export(ctx, spans); err != nil {
+		p.Logger.Error(err, "failed to export spans", "spans", len(spans))
+	}
+}
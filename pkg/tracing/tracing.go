@@ -0,0 +1,121 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing records a trace of each reconcile, with a span for each
+// step of realizing it, and exports them to an OpenTelemetry collector.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"sync/atomic"
+	"time"
+)
+
+// TraceID identifies a trace, and SpanID a span within it.
+type (
+	TraceID [16]byte
+	SpanID  [8]byte
+)
+
+// Attribute describes a span, with a string or an int64 Value.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// SpanData is a span that ended.
+type SpanData struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	ParentID   SpanID
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Attributes []Attribute
+	// Error is the message of the error the span ended with, if any.
+	Error string
+}
+
+// Span is a step of a trace that has not ended yet. A nil Span, which is
+// what Start returns while tracing is disabled, records nothing.
+type Span struct {
+	provider *Provider
+	data     SpanData
+}
+
+type spanKey struct{}
+
+var global atomic.Value
+
+// SetProvider makes Start record spans in the provider. Until it is called,
+// tracing is disabled.
+func SetProvider(provider *Provider) {
+	global.Store(provider)
+}
+
+// Start starts a span, which is the child of the span of ctx, if any, and
+// returns a context for its own children.
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	provider, _ := global.Load().(*Provider)
+	if provider == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		provider: provider,
+		data: SpanData{
+			Name:       name,
+			Start:      time.Now(),
+			Attributes: attributes,
+		},
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		span.data.TraceID = parent.data.TraceID
+		span.data.ParentID = parent.data.SpanID
+	} else {
+		_, _ = rand.Read(span.data.TraceID[:])
+	}
+	_, _ = rand.Read(span.data.SpanID[:])
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SetAttributes adds attributes learned after the span started.
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.data.Attributes = append(s.data.Attributes, attributes...)
+}
+
+// End ends the span, as failed when err is not nil.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.data.End = time.Now()
+	if err != nil {
+		s.data.Error = err.Error()
+	}
+	s.provider.record(s.data)
+}
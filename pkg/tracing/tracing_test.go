@@ -0,0 +1,170 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-tanzu/cartographer/pkg/tracing"
+)
+
+type recordingExporter struct {
+	spans []tracing.SpanData
+}
+
+func (e *recordingExporter) Export(_ context.Context, spans []tracing.SpanData) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+var _ = Describe("Start", func() {
+	var exporter *recordingExporter
+
+	BeforeEach(func() {
+		exporter = &recordingExporter{}
+	})
+
+	AfterEach(func() {
+		tracing.SetProvider(nil)
+	})
+
+	It("records nothing while tracing is disabled", func() {
+		ctx := context.Background()
+		spanCtx, span := tracing.Start(ctx, "Reconcile")
+		Expect(span).To(BeNil())
+		Expect(spanCtx).To(Equal(ctx))
+
+		span.SetAttributes(tracing.String("name", "petclinic"))
+		span.End(errors.New("ignored"))
+	})
+
+	It("records the spans of a trace as they end", func() {
+		provider := tracing.NewProvider(exporter, logr.Discard())
+		tracing.SetProvider(provider)
+
+		ctx, parent := tracing.Start(context.Background(), "Reconcile", tracing.String("name", "petclinic"))
+		_, child := tracing.Start(ctx, "Stamp")
+		child.SetAttributes(tracing.Int("attempt", 2))
+		child.End(errors.New("stamp failed"))
+		parent.End(nil)
+
+		provider.Flush(context.Background())
+
+		Expect(exporter.spans).To(HaveLen(2))
+		stamp, reconcile := exporter.spans[0], exporter.spans[1]
+		Expect(stamp.Name).To(Equal("Stamp"))
+		Expect(stamp.TraceID).To(Equal(reconcile.TraceID))
+		Expect(stamp.ParentID).To(Equal(reconcile.SpanID))
+		Expect(stamp.Attributes).To(Equal([]tracing.Attribute{tracing.Int("attempt", 2)}))
+		Expect(stamp.Error).To(Equal("stamp failed"))
+
+		Expect(reconcile.ParentID).To(Equal(tracing.SpanID{}))
+		Expect(reconcile.Attributes).To(Equal([]tracing.Attribute{tracing.String("name", "petclinic")}))
+		Expect(reconcile.Error).To(BeEmpty())
+		Expect(reconcile.End).NotTo(BeTemporally("<", reconcile.Start))
+
+		provider.Flush(context.Background())
+		Expect(exporter.spans).To(HaveLen(2))
+	})
+
+	It("starts a new trace for a span without a parent", func() {
+		provider := tracing.NewProvider(exporter, logr.Discard())
+		tracing.SetProvider(provider)
+
+		_, first := tracing.Start(context.Background(), "Reconcile")
+		_, second := tracing.Start(context.Background(), "Reconcile")
+		first.End(nil)
+		second.End(nil)
+
+		provider.Flush(context.Background())
+
+		Expect(exporter.spans).To(HaveLen(2))
+		Expect(exporter.spans[0].TraceID).NotTo(Equal(exporter.spans[1].TraceID))
+	})
+})
+
+var _ = Describe("OTLPExporter", func() {
+	var (
+		server   *httptest.Server
+		status   int
+		path     string
+		received map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		status = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			path = req.URL.Path
+			body, _ := io.ReadAll(req.Body)
+			received = nil
+			_ = json.Unmarshal(body, &received)
+			w.WriteHeader(status)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("posts the spans to the collector in the JSON encoding of OTLP", func() {
+		exporter := tracing.NewOTLPExporter(server.URL+"/", server.Client())
+
+		err := exporter.Export(context.Background(), []tracing.SpanData{{
+			TraceID:    tracing.TraceID{1},
+			SpanID:     tracing.SpanID{2},
+			ParentID:   tracing.SpanID{3},
+			Name:       "Apply",
+			Attributes: []tracing.Attribute{tracing.String("component", "image"), tracing.Int("attempt", 2)},
+			Error:      "forbidden",
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(path).To(Equal(tracing.TracesPath))
+		resourceSpans := received["resourceSpans"].([]interface{})[0].(map[string]interface{})
+		Expect(resourceSpans["resource"]).To(Equal(map[string]interface{}{
+			"attributes": []interface{}{
+				map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "cartographer"}},
+			},
+		}))
+
+		span := resourceSpans["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})[0].(map[string]interface{})
+		Expect(span).To(HaveKeyWithValue("traceId", "01000000000000000000000000000000"))
+		Expect(span).To(HaveKeyWithValue("spanId", "0200000000000000"))
+		Expect(span).To(HaveKeyWithValue("parentSpanId", "0300000000000000"))
+		Expect(span).To(HaveKeyWithValue("name", "Apply"))
+		Expect(span).To(HaveKeyWithValue("attributes", []interface{}{
+			map[string]interface{}{"key": "component", "value": map[string]interface{}{"stringValue": "image"}},
+			map[string]interface{}{"key": "attempt", "value": map[string]interface{}{"intValue": "2"}},
+		}))
+		Expect(span).To(HaveKeyWithValue("status", map[string]interface{}{"code": float64(2), "message": "forbidden"}))
+	})
+
+	It("returns an error when the collector does not accept the spans", func() {
+		status = http.StatusServiceUnavailable
+		exporter := tracing.NewOTLPExporter(server.URL, server.Client())
+
+		err := exporter.Export(context.Background(), []tracing.SpanData{{Name: "Apply"}})
+		Expect(err).To(MatchError(ContainSubstring("503")))
+	})
+})
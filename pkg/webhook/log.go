@@ -0,0 +1,41 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/vmware-tanzu/cartographer/pkg/logging"
+)
+
+var log = ctrllog.Log.WithName(logging.Webhook)
+
+// logValidation logs whether the object was admitted, and returns err, the
+// outcome of validating it.
+func logValidation(operation string, obj runtime.Object, err error) error {
+	values := []interface{}{"operation", operation, "kind", obj.GetObjectKind().GroupVersionKind().Kind}
+	if accessor, accessorErr := meta.Accessor(obj); accessorErr == nil {
+		values = append(values, "namespace", accessor.GetNamespace(), "name", accessor.GetName())
+	}
+
+	if err != nil {
+		log.V(1).Info("rejected", append(values, "reason", err.Error())...)
+	} else {
+		log.V(2).Info("admitted", values...)
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+)
+
+// PipelineValidator rejects pipelines whose inputs do not satisfy those
+// declared by their RunTemplate. A pipeline referring to a RunTemplate that
+// does not exist yet is admitted with a warning, and reported on by the
+// realizer instead, unless RejectMissingTemplates is set.
+type PipelineValidator struct {
+	Repository             repository.Repository
+	RejectMissingTemplates bool
+}
+
+func (v *PipelineValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return logValidation("create", obj, v.validate(ctx, obj))
+}
+
+func (v *PipelineValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return logValidation("update", newObj, v.validate(ctx, newObj))
+}
+
+func (v *PipelineValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *PipelineValidator) validate(ctx context.Context, obj runtime.Object) error {
+	pipeline, ok := obj.(*v1alpha1.Pipeline)
+	if !ok {
+		return fmt.Errorf("expected a pipeline but got a %T", obj)
+	}
+
+	ref := pipeline.Spec.RunTemplateRef
+	if err := ref.Validate(); err != nil {
+		return fmt.Errorf("invalid run template ref: %w", err)
+	}
+
+	ref.Kind = "RunTemplate"
+	if ref.Namespace == "" {
+		ref.Namespace = pipeline.Namespace
+	}
+
+	template, err := v.Repository.GetRunTemplate(ref)
+	if kerrors.IsNotFound(err) {
+		missing := fmt.Sprintf("RunTemplate '%s'", ref.Name)
+		if ref.Selector != nil {
+			missing = fmt.Sprintf("RunTemplate matching '%s'", metav1.FormatLabelSelector(ref.Selector))
+		}
+		message := fmt.Sprintf("referenced templates do not exist: %s", missing)
+		if v.RejectMissingTemplates {
+			return errors.New(message)
+		}
+		Warn(ctx, message)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get run template '%s': %w", ref.Name, err)
+	}
+
+	if err := template.ValidateInputs(pipeline.Spec.Inputs); err != nil {
+		return fmt.Errorf("invalid inputs for RunTemplate '%s': %w", template.GetName(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/webhook"
+)
+
+var _ = Describe("PipelineValidator", func() {
+	var (
+		repository *repositoryfakes.FakeRepository
+		validator  *webhook.PipelineValidator
+		pipeline   *v1alpha1.Pipeline
+	)
+
+	BeforeEach(func() {
+		repository = &repositoryfakes.FakeRepository{}
+		validator = &webhook.PipelineValidator{Repository: repository}
+
+		repository.GetRunTemplateReturns(templates.NewRunTemplateModel(&v1alpha1.RunTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-template"},
+			Spec: v1alpha1.RunTemplateSpec{
+				Inputs: []v1alpha1.RunTemplateInput{
+					{Name: "url", Type: "string", Required: true},
+				},
+			},
+		}), nil)
+
+		pipeline = &v1alpha1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns"},
+			Spec: v1alpha1.PipelineSpec{
+				RunTemplateRef: v1alpha1.TemplateReference{Name: "my-template"},
+				Inputs: map[string]apiextensionsv1.JSON{
+					"url": {Raw: []byte(`"https://example.com"`)},
+				},
+			},
+		}
+	})
+
+	It("looks up the run template in the pipeline's namespace", func() {
+		Expect(validator.ValidateCreate(context.TODO(), pipeline)).To(Succeed())
+
+		Expect(repository.GetRunTemplateCallCount()).To(Equal(1))
+		Expect(repository.GetRunTemplateArgsForCall(0)).To(Equal(v1alpha1.TemplateReference{
+			Kind:      "RunTemplate",
+			Name:      "my-template",
+			Namespace: "my-ns",
+		}))
+	})
+
+	It("does not default the pipeline it was given", func() {
+		Expect(validator.ValidateCreate(context.TODO(), pipeline)).To(Succeed())
+		Expect(pipeline.Spec.RunTemplateRef.Namespace).To(BeEmpty())
+	})
+
+	It("rejects inputs that do not satisfy the run template", func() {
+		pipeline.Spec.Inputs = map[string]apiextensionsv1.JSON{
+			"uri": {Raw: []byte(`"https://example.com"`)},
+		}
+
+		Expect(validator.ValidateUpdate(context.TODO(), nil, pipeline)).To(
+			MatchError("invalid inputs for RunTemplate 'my-template': input 'url' is required"),
+		)
+	})
+
+	It("rejects a run template ref with both a name and a selector", func() {
+		pipeline.Spec.RunTemplateRef.Selector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app.tanzu.vmware.com/template": "tekton"},
+		}
+
+		Expect(validator.ValidateCreate(context.TODO(), pipeline)).To(
+			MatchError("invalid run template ref: exactly one of name or selector must be set"),
+		)
+		Expect(repository.GetRunTemplateCallCount()).To(Equal(0))
+	})
+
+	It("rejects a run template ref with neither a name nor a selector", func() {
+		pipeline.Spec.RunTemplateRef.Name = ""
+
+		Expect(validator.ValidateCreate(context.TODO(), pipeline)).To(
+			MatchError("invalid run template ref: exactly one of name or selector must be set"),
+		)
+	})
+
+	It("admits pipelines whose run template does not exist yet", func() {
+		notFound := kerrors.NewNotFound(schema.GroupResource{Group: "carto.run", Resource: "runtemplates"}, "my-template")
+		repository.GetRunTemplateReturns(nil, fmt.Errorf("get: %w", notFound))
+
+		Expect(validator.ValidateCreate(context.TODO(), pipeline)).To(Succeed())
+	})
+
+	It("rejects pipelines whose run template does not exist when missing templates are rejected", func() {
+		validator.RejectMissingTemplates = true
+		notFound := kerrors.NewNotFound(schema.GroupResource{Group: "carto.run", Resource: "runtemplates"}, "my-template")
+		repository.GetRunTemplateReturns(nil, fmt.Errorf("get: %w", notFound))
+
+		Expect(validator.ValidateCreate(context.TODO(), pipeline)).To(
+			MatchError("referenced templates do not exist: RunTemplate 'my-template'"),
+		)
+	})
+
+	It("returns an error when the run template cannot be read", func() {
+		repository.GetRunTemplateReturns(nil, errors.New("some error"))
+
+		Expect(validator.ValidateCreate(context.TODO(), pipeline)).To(
+			MatchError("get run template 'my-template': some error"),
+		)
+	})
+
+	It("does not validate deletes", func() {
+		Expect(validator.ValidateDelete(context.TODO(), pipeline)).To(Succeed())
+		Expect(repository.GetRunTemplateCallCount()).To(Equal(0))
+	})
+})
@@ -0,0 +1,136 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+// SupplyChainValidator validates ClusterSupplyChains and SupplyChains as
+// they validate themselves, then looks up the templates their components
+// refer to. Missing templates are reported with a warning, or rejected when
+// RejectMissingTemplates is set. A SupplyChain whose templates stamp a
+// cluster-scoped kind is rejected, as it may only write to its namespace.
+type SupplyChainValidator struct {
+	Repository             repository.Repository
+	RejectMissingTemplates bool
+}
+
+func (v *SupplyChainValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return logValidation("create", obj, v.validate(ctx, obj))
+}
+
+func (v *SupplyChainValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return logValidation("update", newObj, v.validate(ctx, newObj))
+}
+
+func (v *SupplyChainValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *SupplyChainValidator) validate(ctx context.Context, obj runtime.Object) error {
+	var supplyChain *v1alpha1.ClusterSupplyChain
+	switch typed := obj.(type) {
+	case *v1alpha1.ClusterSupplyChain:
+		if err := typed.ValidateCreate(); err != nil {
+			return err
+		}
+		supplyChain = typed
+	case *v1alpha1.SupplyChain:
+		if err := typed.ValidateCreate(); err != nil {
+			return err
+		}
+		supplyChain = typed.AsClusterSupplyChain()
+	default:
+		return fmt.Errorf("expected a supply chain but got a %T", obj)
+	}
+
+	if ref := supplyChain.Spec.ServiceAccountRef; supplyChain.Namespace == "" && ref != nil && ref.Namespace != "" {
+		Warn(ctx, fmt.Sprintf("spec.serviceAccountRef.namespace '%s' is ignored: workloads are realized as service account '%s' of their own namespace", ref.Namespace, ref.Name))
+	}
+
+	missing, err := v.checkTemplates(supplyChain)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("referenced templates do not exist: %s", strings.Join(missing, ", "))
+	if v.RejectMissingTemplates {
+		return errors.New(message)
+	}
+	Warn(ctx, message)
+	return nil
+}
+
+// checkTemplates lists the templates the components of the supply chain
+// refer to that do not exist, as "Kind 'name'", and rejects those of a
+// namespaced supply chain that stamp a cluster-scoped kind.
+func (v *SupplyChainValidator) checkTemplates(supplyChain *v1alpha1.ClusterSupplyChain) ([]string, error) {
+	var missing []string
+	for _, component := range supplyChain.Spec.Components {
+		for _, candidate := range component.TemplateRef.Candidates() {
+			var (
+				template templates.Template
+				err      error
+			)
+			if supplyChain.Namespace == "" {
+				template, err = v.Repository.GetClusterTemplate(candidate)
+			} else {
+				template, err = v.Repository.GetTemplate(candidate, supplyChain.Namespace)
+			}
+			if kerrors.IsNotFound(err) {
+				missing = append(missing, fmt.Sprintf("%s '%s'", candidate.Kind, candidate.Name))
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("get %s '%s' of component '%s': %w", candidate.Kind, candidate.Name, component.Name, err)
+			}
+			if err := v.checkScope(supplyChain, component, template); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return missing, nil
+}
+
+// checkScope rejects a template of a namespaced supply chain that stamps a
+// cluster-scoped kind into the cluster. Kinds the template only stamps
+// through ytt, or that are not known to the cluster, are left to the
+// realizer.
+func (v *SupplyChainValidator) checkScope(supplyChain *v1alpha1.ClusterSupplyChain, component v1alpha1.SupplyChainComponent, template templates.Template) error {
+	if supplyChain.Namespace == "" || template == nil || component.GitOps != nil || component.OCIArtifact != nil {
+		return nil
+	}
+	for _, kind := range stampedKinds(template.GetResourceTemplate().Template, nil) {
+		namespaced, err := v.Repository.IsNamespaced(kind.WithVersion(""))
+		if err == nil && !namespaced {
+			return fmt.Errorf("component '%s' may not stamp cluster-scoped kind %s outside the namespace of the supply chain, '%s'", component.Name, kind.Kind, supplyChain.Namespace)
+		}
+	}
+	return nil
+}
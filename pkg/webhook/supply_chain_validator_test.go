@@ -0,0 +1,228 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/webhook"
+)
+
+var _ = Describe("SupplyChainValidator", func() {
+	var (
+		repository  *repositoryfakes.FakeRepository
+		validator   *webhook.SupplyChainValidator
+		supplyChain *v1alpha1.ClusterSupplyChain
+		notFound    error
+	)
+
+	BeforeEach(func() {
+		repository = &repositoryfakes.FakeRepository{}
+		validator = &webhook.SupplyChainValidator{Repository: repository}
+		notFound = fmt.Errorf("get: %w", kerrors.NewNotFound(schema.GroupResource{Group: "carto.run", Resource: "clustertemplates"}, "missing"))
+
+		supplyChain = &v1alpha1.ClusterSupplyChain{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "carto.run/v1alpha1", Kind: "ClusterSupplyChain"},
+			ObjectMeta: metav1.ObjectMeta{Name: "my-supply-chain"},
+			Spec: v1alpha1.SupplyChainSpec{
+				Selector: map[string]string{"app": "web"},
+				Components: []v1alpha1.SupplyChainComponent{
+					{
+						Name:        "source",
+						TemplateRef: v1alpha1.ClusterTemplateReference{Kind: "ClusterSourceTemplate", Name: "git"},
+					},
+					{
+						Name: "config",
+						TemplateRef: v1alpha1.ClusterTemplateReference{
+							Kind: "ClusterTemplate",
+							Options: []v1alpha1.TemplateOption{
+								{Name: "config-a", Selector: v1alpha1.OptionSelector{MatchFields: []v1alpha1.FieldSelectorRequirement{{Key: "spec.source.git", Operator: "Exists"}}}},
+								{Name: "config-b", Selector: v1alpha1.OptionSelector{MatchFields: []v1alpha1.FieldSelectorRequirement{{Key: "spec.source.git", Operator: "DoesNotExist"}}}},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("looks up every template the components refer to", func() {
+		Expect(validator.ValidateCreate(context.TODO(), supplyChain)).To(Succeed())
+
+		Expect(repository.GetClusterTemplateCallCount()).To(Equal(3))
+		Expect(repository.GetClusterTemplateArgsForCall(0)).To(Equal(v1alpha1.ClusterTemplateReference{Kind: "ClusterSourceTemplate", Name: "git"}))
+		Expect(repository.GetClusterTemplateArgsForCall(1)).To(Equal(v1alpha1.ClusterTemplateReference{Kind: "ClusterTemplate", Name: "config-a"}))
+		Expect(repository.GetClusterTemplateArgsForCall(2)).To(Equal(v1alpha1.ClusterTemplateReference{Kind: "ClusterTemplate", Name: "config-b"}))
+	})
+
+	It("looks up the templates of a namespaced supply chain in its namespace", func() {
+		namespaced := &v1alpha1.SupplyChain{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-supply-chain", Namespace: "my-ns"},
+			Spec:       supplyChain.Spec,
+		}
+
+		Expect(validator.ValidateUpdate(context.TODO(), nil, namespaced)).To(Succeed())
+
+		Expect(repository.GetClusterTemplateCallCount()).To(Equal(0))
+		Expect(repository.GetTemplateCallCount()).To(Equal(3))
+		_, namespace := repository.GetTemplateArgsForCall(0)
+		Expect(namespace).To(Equal("my-ns"))
+	})
+
+	Describe("a namespaced supply chain", func() {
+		var namespaced *v1alpha1.SupplyChain
+
+		BeforeEach(func() {
+			namespaced = &v1alpha1.SupplyChain{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-supply-chain", Namespace: "my-ns"},
+				Spec:       supplyChain.Spec,
+			}
+			clusterRole, err := json.Marshal(map[string]interface{}{"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "ClusterRole"})
+			Expect(err).NotTo(HaveOccurred())
+			repository.GetTemplateReturns(templates.NewClusterTemplateModel(&v1alpha1.ClusterTemplate{
+				Spec: v1alpha1.TemplateSpec{Template: &runtime.RawExtension{Raw: clusterRole}},
+			}), nil)
+		})
+
+		It("is rejected when its templates stamp a cluster-scoped kind", func() {
+			Expect(validator.ValidateCreate(context.TODO(), namespaced)).To(
+				MatchError("component 'source' may not stamp cluster-scoped kind ClusterRole outside the namespace of the supply chain, 'my-ns'"),
+			)
+			Expect(repository.IsNamespacedArgsForCall(0)).To(Equal(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}))
+		})
+
+		It("is admitted when its templates stamp namespaced kinds", func() {
+			repository.IsNamespacedReturns(true, nil)
+
+			Expect(validator.ValidateCreate(context.TODO(), namespaced)).To(Succeed())
+		})
+
+		It("is rejected when a component stamps its object into another namespace", func() {
+			repository.IsNamespacedReturns(true, nil)
+			namespaced.Spec.Components[0].Namespace = "other-ns"
+
+			Expect(validator.ValidateCreate(context.TODO(), namespaced)).To(
+				MatchError("component 'source' may not stamp its object outside the namespace of the supply chain, 'my-ns'"),
+			)
+		})
+	})
+
+	It("rejects supply chains that are invalid in themselves", func() {
+		supplyChain.Spec.Components[1].Name = "source"
+
+		Expect(validator.ValidateCreate(context.TODO(), supplyChain)).To(
+			MatchError("duplicate component name 'source' found in clustersupplychain 'my-supply-chain'"),
+		)
+		Expect(repository.GetClusterTemplateCallCount()).To(Equal(0))
+	})
+
+	It("admits supply chains whose templates do not exist yet", func() {
+		repository.GetClusterTemplateReturns(nil, notFound)
+
+		Expect(validator.ValidateCreate(context.TODO(), supplyChain)).To(Succeed())
+	})
+
+	It("rejects supply chains whose templates do not exist when missing templates are rejected", func() {
+		validator.RejectMissingTemplates = true
+		repository.GetClusterTemplateReturnsOnCall(0, nil, notFound)
+		repository.GetClusterTemplateReturnsOnCall(2, nil, notFound)
+
+		Expect(validator.ValidateCreate(context.TODO(), supplyChain)).To(
+			MatchError("referenced templates do not exist: ClusterSourceTemplate 'git', ClusterTemplate 'config-b'"),
+		)
+	})
+
+	It("returns an error when a template cannot be read", func() {
+		repository.GetClusterTemplateReturns(nil, errors.New("some error"))
+
+		Expect(validator.ValidateCreate(context.TODO(), supplyChain)).To(
+			MatchError("get ClusterSourceTemplate 'git' of component 'source': some error"),
+		)
+	})
+
+	It("does not validate deletes", func() {
+		Expect(validator.ValidateDelete(context.TODO(), supplyChain)).To(Succeed())
+		Expect(repository.GetClusterTemplateCallCount()).To(Equal(0))
+	})
+
+	Describe("served with warnings", func() {
+		var handler *admission.Webhook
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+			handler = webhook.WithWarnings(admission.WithCustomValidator(&v1alpha1.ClusterSupplyChain{}, validator))
+			Expect(handler.InjectScheme(scheme)).To(Succeed())
+			Expect(handler.InjectFunc(func(interface{}) error { return nil })).To(Succeed())
+		})
+
+		handle := func() admission.Response {
+			raw, err := json.Marshal(supplyChain)
+			Expect(err).NotTo(HaveOccurred())
+
+			return handler.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+		}
+
+		It("warns about the templates that do not exist", func() {
+			repository.GetClusterTemplateReturnsOnCall(1, nil, notFound)
+
+			response := handle()
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(Equal([]string{"referenced templates do not exist: ClusterTemplate 'config-a'"}))
+		})
+
+		It("warns that the namespace of the service account is ignored", func() {
+			supplyChain.Spec.ServiceAccountRef = &v1alpha1.ServiceAccountRef{Name: "stamper", Namespace: "cartographer-system"}
+
+			response := handle()
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(Equal([]string{"spec.serviceAccountRef.namespace 'cartographer-system' is ignored: workloads are realized as service account 'stamper' of their own namespace"}))
+		})
+
+		It("does not warn when every template exists", func() {
+			response := handle()
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(BeEmpty())
+		})
+
+		It("does not warn about a rejected supply chain", func() {
+			validator.RejectMissingTemplates = true
+			repository.GetClusterTemplateReturns(nil, notFound)
+
+			response := handle()
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Warnings).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,223 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+)
+
+// referrers lists the supply chains and pipelines, other than those being
+// deleted, that refer to the template, as "Kind 'name'" or, when
+// namespaced, "Kind 'namespace/name'".
+func (v *TemplateValidator) referrers(obj runtime.Object) ([]string, error) {
+	var (
+		referrers []string
+		err       error
+	)
+	switch template := obj.(type) {
+	case *v1alpha1.RunTemplate:
+		referrers, err = v.runTemplateReferrers(template)
+	case *v1alpha1.ClusterSourceTemplate:
+		referrers, err = v.templateReferrers("ClusterSourceTemplate", template.Name, "")
+	case *v1alpha1.ClusterImageTemplate:
+		referrers, err = v.templateReferrers("ClusterImageTemplate", template.Name, "")
+	case *v1alpha1.ClusterConfigTemplate:
+		referrers, err = v.templateReferrers("ClusterConfigTemplate", template.Name, "")
+	case *v1alpha1.ClusterTemplate:
+		referrers, err = v.templateReferrers("ClusterTemplate", template.Name, "")
+	case *v1alpha1.SourceTemplate:
+		referrers, err = v.templateReferrers("ClusterSourceTemplate", template.Name, template.Namespace)
+	case *v1alpha1.ImageTemplate:
+		referrers, err = v.templateReferrers("ClusterImageTemplate", template.Name, template.Namespace)
+	case *v1alpha1.ConfigTemplate:
+		referrers, err = v.templateReferrers("ClusterConfigTemplate", template.Name, template.Namespace)
+	case *v1alpha1.Template:
+		referrers, err = v.templateReferrers("ClusterTemplate", template.Name, template.Namespace)
+	default:
+		return nil, fmt.Errorf("expected a template but got a %T", obj)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(referrers)
+	return dedupe(referrers), nil
+}
+
+// templateReferrers lists the supply chains with a component referring to
+// the template by the kind components refer to it as, kind being that of
+// the cluster template even when the template is the namespaced
+// counterpart. A namespaced template is only referred to by the supply
+// chains of its namespace, a cluster template by those of any namespace.
+func (v *TemplateValidator) templateReferrers(kind, name, namespace string) ([]string, error) {
+	var referrers []string
+
+	if namespace == "" {
+		supplyChains, err := v.Repository.ListSupplyChains()
+		if err != nil {
+			return nil, err
+		}
+		for _, supplyChain := range supplyChains {
+			if supplyChain.DeletionTimestamp == nil && refersToTemplate(supplyChain.Spec, kind, name) {
+				referrers = append(referrers, fmt.Sprintf("ClusterSupplyChain '%s'", supplyChain.Name))
+			}
+		}
+	}
+
+	supplyChains, err := v.Repository.ListNamespacedSupplyChains()
+	if err != nil {
+		return nil, err
+	}
+	for _, supplyChain := range supplyChains {
+		if namespace != "" && supplyChain.Namespace != namespace {
+			continue
+		}
+		if supplyChain.DeletionTimestamp == nil && refersToTemplate(supplyChain.AsClusterSupplyChain().Spec, kind, name) {
+			referrers = append(referrers, fmt.Sprintf("SupplyChain '%s/%s'", supplyChain.Namespace, supplyChain.Name))
+		}
+	}
+
+	return referrers, nil
+}
+
+func refersToTemplate(spec v1alpha1.SupplyChainSpec, kind, name string) bool {
+	for _, component := range spec.Components {
+		if component.TemplateRef.Kind != kind {
+			continue
+		}
+		for _, candidate := range component.TemplateRef.Candidates() {
+			if candidate.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runTemplateReferrers lists the pipelines, and the supply chains with a
+// hook, that refer to the RunTemplate. A reference by a selector only
+// counts when the RunTemplate is the one it selects.
+func (v *TemplateValidator) runTemplateReferrers(template *v1alpha1.RunTemplate) ([]string, error) {
+	var referrers []string
+
+	pipelines, err := v.Repository.ListPipelines()
+	if err != nil {
+		return nil, err
+	}
+	for _, pipeline := range pipelines {
+		if pipeline.DeletionTimestamp != nil {
+			continue
+		}
+		refers, err := v.refersToRunTemplate(pipeline.Spec.RunTemplateRef, pipeline.Namespace, template)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline '%s/%s': %w", pipeline.Namespace, pipeline.Name, err)
+		}
+		if refers {
+			referrers = append(referrers, fmt.Sprintf("Pipeline '%s/%s'", pipeline.Namespace, pipeline.Name))
+		}
+	}
+
+	clusterSupplyChains, err := v.Repository.ListSupplyChains()
+	if err != nil {
+		return nil, err
+	}
+	for _, supplyChain := range clusterSupplyChains {
+		refers, err := v.hooksReferToRunTemplate(supplyChain.Spec, "", template)
+		if err != nil {
+			return nil, fmt.Errorf("clustersupplychain '%s': %w", supplyChain.Name, err)
+		}
+		if supplyChain.DeletionTimestamp == nil && refers {
+			referrers = append(referrers, fmt.Sprintf("ClusterSupplyChain '%s'", supplyChain.Name))
+		}
+	}
+
+	supplyChains, err := v.Repository.ListNamespacedSupplyChains()
+	if err != nil {
+		return nil, err
+	}
+	for _, supplyChain := range supplyChains {
+		refers, err := v.hooksReferToRunTemplate(supplyChain.AsClusterSupplyChain().Spec, supplyChain.Namespace, template)
+		if err != nil {
+			return nil, fmt.Errorf("supplychain '%s/%s': %w", supplyChain.Namespace, supplyChain.Name, err)
+		}
+		if supplyChain.DeletionTimestamp == nil && refers {
+			referrers = append(referrers, fmt.Sprintf("SupplyChain '%s/%s'", supplyChain.Namespace, supplyChain.Name))
+		}
+	}
+
+	return referrers, nil
+}
+
+// hooksReferToRunTemplate tells whether a hook of the supply chain refers to
+// the RunTemplate. The hooks of a ClusterSupplyChain that do not name a
+// namespace run in that of each workload, so are not known to refer to it.
+func (v *TemplateValidator) hooksReferToRunTemplate(spec v1alpha1.SupplyChainSpec, namespace string, template *v1alpha1.RunTemplate) (bool, error) {
+	for _, component := range spec.Components {
+		if component.Hooks == nil {
+			continue
+		}
+		for _, hook := range append(append([]v1alpha1.ComponentHook{}, component.Hooks.Pre...), component.Hooks.Post...) {
+			refers, err := v.refersToRunTemplate(hook.RunTemplateRef, namespace, template)
+			if err != nil || refers {
+				return refers, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// refersToRunTemplate tells whether the reference, in the namespace it
+// defaults to, resolves to the RunTemplate.
+func (v *TemplateValidator) refersToRunTemplate(ref v1alpha1.TemplateReference, namespace string, template *v1alpha1.RunTemplate) (bool, error) {
+	if ref.Kind != "" && ref.Kind != "RunTemplate" {
+		return false, nil
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = namespace
+	}
+	if ref.Namespace != template.Namespace {
+		return false, nil
+	}
+	if ref.Selector == nil {
+		return ref.Name == template.Name, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+	if err != nil || !selector.Matches(labels.Set(template.Labels)) {
+		return false, nil
+	}
+	selected, err := v.Repository.GetRunTemplate(ref)
+	if err != nil {
+		return false, fmt.Errorf("get RunTemplate matching '%s': %w", metav1.FormatLabelSelector(ref.Selector), err)
+	}
+	return selected.GetName() == template.Name, nil
+}
+
+func dedupe(sorted []string) []string {
+	var unique []string
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
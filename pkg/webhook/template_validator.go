@@ -0,0 +1,166 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+)
+
+// TemplateValidator validates templates as they validate themselves, then
+// stamps those with a sample for it, rejecting the templates that fail to
+// stamp before they break the workloads and pipelines going through them,
+// and those stamping a kind the ClusterStampPolicies do not allow. It also
+// rejects the deletion of templates that supply chains or pipelines still
+// refer to, listing them.
+type TemplateValidator struct {
+	ClusterContext templates.ClusterContext
+	Repository     repository.Repository
+}
+
+func (v *TemplateValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return logValidation("create", obj, v.validate(ctx, obj))
+}
+
+func (v *TemplateValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return logValidation("update", newObj, v.validate(ctx, newObj))
+}
+
+func (v *TemplateValidator) ValidateDelete(_ context.Context, obj runtime.Object) error {
+	return logValidation("delete", obj, v.validateDelete(obj))
+}
+
+func (v *TemplateValidator) validateDelete(obj runtime.Object) error {
+	referrers, err := v.referrers(obj)
+	if err != nil {
+		return fmt.Errorf("list referrers: %w", err)
+	}
+	if len(referrers) > 0 {
+		return fmt.Errorf("template is still referenced by %s", strings.Join(referrers, ", "))
+	}
+	return nil
+}
+
+func (v *TemplateValidator) validate(ctx context.Context, obj runtime.Object) error {
+	validator, ok := obj.(webhook.Validator)
+	if !ok {
+		return fmt.Errorf("expected a template but got a %T", obj)
+	}
+	if err := validator.ValidateCreate(); err != nil {
+		return err
+	}
+
+	var (
+		raw     *runtime.RawExtension
+		stamped *unstructured.Unstructured
+		err     error
+	)
+	switch template := obj.(type) {
+	case *v1alpha1.RunTemplate:
+		raw = &template.Spec.Template
+		if template.Spec.Sample != nil {
+			stamped, err = templates.StampRunTemplateSample(ctx, template.Spec, v.ClusterContext)
+		}
+	default:
+		spec, ok := templateSpec(obj)
+		if !ok {
+			return fmt.Errorf("expected a template but got a %T", obj)
+		}
+		raw = spec.Template
+		if spec.Sample != nil {
+			stamped, err = templates.StampSample(ctx, spec, v.ClusterContext)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid template: failed to stamp its sample: %w", err)
+	}
+
+	return v.validateKinds(obj, stampedKinds(raw, stamped))
+}
+
+// validateKinds rejects a template stamping a kind the ClusterStampPolicies
+// do not allow where it is stamped: in the namespace of the template, or in
+// any namespace for a cluster template.
+func (v *TemplateValidator) validateKinds(obj runtime.Object, kinds []schema.GroupKind) error {
+	if v.Repository == nil || len(kinds) == 0 {
+		return nil
+	}
+
+	policies, err := v.Repository.ListStampPolicies()
+	if err != nil {
+		return err
+	}
+
+	var namespace string
+	if template, ok := obj.(metav1.Object); ok {
+		namespace = template.GetNamespace()
+	}
+	for _, kind := range kinds {
+		if err := v1alpha1.CheckStampPolicies(policies, namespace, kind); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	return nil
+}
+
+// stampedKinds are the kinds a template is known to stamp: that of its
+// template, unless it is ytt, and that of the object stamped for its sample.
+func stampedKinds(raw *runtime.RawExtension, stamped *unstructured.Unstructured) []schema.GroupKind {
+	var kinds []schema.GroupKind
+	if raw != nil && len(raw.Raw) > 0 {
+		var typeMeta metav1.TypeMeta
+		if err := json.Unmarshal(raw.Raw, &typeMeta); err == nil && typeMeta.Kind != "" {
+			kinds = append(kinds, typeMeta.GroupVersionKind().GroupKind())
+		}
+	}
+	if stamped != nil && stamped.GetKind() != "" {
+		kinds = append(kinds, stamped.GroupVersionKind().GroupKind())
+	}
+	return kinds
+}
+
+func templateSpec(obj runtime.Object) (v1alpha1.TemplateSpec, bool) {
+	switch template := obj.(type) {
+	case *v1alpha1.ClusterSourceTemplate:
+		return template.Spec.TemplateSpec, true
+	case *v1alpha1.ClusterImageTemplate:
+		return template.Spec.TemplateSpec, true
+	case *v1alpha1.ClusterConfigTemplate:
+		return template.Spec.TemplateSpec, true
+	case *v1alpha1.ClusterTemplate:
+		return template.Spec, true
+	case *v1alpha1.SourceTemplate:
+		return template.Spec.TemplateSpec, true
+	case *v1alpha1.ImageTemplate:
+		return template.Spec.TemplateSpec, true
+	case *v1alpha1.ConfigTemplate:
+		return template.Spec.TemplateSpec, true
+	case *v1alpha1.Template:
+		return template.Spec, true
+	}
+	return v1alpha1.TemplateSpec{}, false
+}
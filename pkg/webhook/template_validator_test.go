@@ -0,0 +1,279 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/templates"
+	"github.com/vmware-tanzu/cartographer/pkg/webhook"
+)
+
+var _ = Describe("TemplateValidator", func() {
+	var (
+		validator  *webhook.TemplateValidator
+		repository *repositoryfakes.FakeRepository
+		template   *v1alpha1.ClusterConfigTemplate
+	)
+
+	BeforeEach(func() {
+		repository = &repositoryfakes.FakeRepository{}
+		validator = &webhook.TemplateValidator{Repository: repository}
+
+		template = &v1alpha1.ClusterConfigTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-config"},
+			Spec: v1alpha1.ConfigTemplateSpec{
+				TemplateSpec: v1alpha1.TemplateSpec{
+					Template: &runtime.RawExtension{Raw: []byte(`{
+						"apiVersion": "v1",
+						"kind": "ConfigMap",
+						"metadata": {"name": "$(workload.metadata.name)$"},
+						"data": {"image": "$(image)$"}
+					}`)},
+					Sample: &v1alpha1.TemplateSample{
+						Workload: runtime.RawExtension{Raw: []byte(`{"metadata": {"name": "petclinic", "namespace": "dev"}}`)},
+						Context:  &runtime.RawExtension{Raw: []byte(`{"image": "registry.example.com/petclinic"}`)},
+					},
+				},
+				ConfigPath: ".data",
+			},
+		}
+	})
+
+	It("admits templates that stamp for their sample", func() {
+		Expect(validator.ValidateCreate(context.TODO(), template)).To(Succeed())
+	})
+
+	It("rejects templates that fail to stamp for their sample", func() {
+		template.Spec.Sample.Context = nil
+
+		Expect(validator.ValidateUpdate(context.TODO(), nil, template)).To(
+			MatchError(ContainSubstring("invalid template: failed to stamp its sample")),
+		)
+	})
+
+	It("admits templates without a sample", func() {
+		template.Spec.Sample = nil
+		template.Spec.Template = &runtime.RawExtension{Raw: []byte(`{"data": {"image": "$(image)$"}}`)}
+
+		Expect(validator.ValidateCreate(context.TODO(), template)).To(Succeed())
+	})
+
+	It("rejects templates that are invalid in themselves", func() {
+		template.Spec.Ytt = "some: ytt"
+
+		Expect(validator.ValidateCreate(context.TODO(), template)).To(
+			MatchError("invalid template: must specify one of template or ytt, found both"),
+		)
+	})
+
+	It("stamps run templates for their sample pipeline", func() {
+		runTemplate := &v1alpha1.RunTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "tekton-taskrun"},
+			Spec: v1alpha1.RunTemplateSpec{
+				Template: runtime.RawExtension{Raw: []byte(`{
+					"apiVersion": "tekton.dev/v1beta1",
+					"kind": "TaskRun",
+					"metadata": {"generateName": "$(pipeline.metadata.name)$-"},
+					"spec": {"params": [{"name": "url", "value": "$(pipeline.spec.inputs.url)$"}]}
+				}`)},
+				Sample: &v1alpha1.RunTemplateSample{
+					Pipeline: runtime.RawExtension{Raw: []byte(`{"metadata": {"name": "tests"}, "spec": {"inputs": {"revision": "main"}}}`)},
+				},
+			},
+		}
+
+		Expect(validator.ValidateCreate(context.TODO(), runTemplate)).To(
+			MatchError(ContainSubstring("invalid template: failed to stamp its sample")),
+		)
+	})
+
+	Describe("stamp policies", func() {
+		policy := func(name string, namespaces []string, kinds ...v1alpha1.AllowedKind) v1alpha1.ClusterStampPolicy {
+			return v1alpha1.ClusterStampPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       v1alpha1.StampPolicySpec{Namespaces: namespaces, AllowedKinds: kinds},
+			}
+		}
+
+		It("admits templates stamping a kind a policy allows", func() {
+			repository.ListStampPoliciesReturns([]v1alpha1.ClusterStampPolicy{
+				policy("apps", nil, v1alpha1.AllowedKind{Kind: "ConfigMap"}),
+			}, nil)
+
+			Expect(validator.ValidateCreate(context.TODO(), template)).To(Succeed())
+		})
+
+		It("rejects templates stamping a kind no policy allows", func() {
+			repository.ListStampPoliciesReturns([]v1alpha1.ClusterStampPolicy{
+				policy("apps", nil, v1alpha1.AllowedKind{Group: "apps", Kind: v1alpha1.AnyKind}),
+			}, nil)
+
+			Expect(validator.ValidateCreate(context.TODO(), template)).To(
+				MatchError("invalid template: kind ConfigMap may not be stamped in any namespace: not allowed by ClusterStampPolicy 'apps'"),
+			)
+		})
+
+		It("checks namespaced templates against the policies of their namespace", func() {
+			repository.ListStampPoliciesReturns([]v1alpha1.ClusterStampPolicy{
+				policy("prod", []string{"prod"}, v1alpha1.AllowedKind{Group: "apps", Kind: "Deployment"}),
+			}, nil)
+			namespaced := &v1alpha1.ConfigTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "dev"},
+				Spec:       template.Spec,
+			}
+
+			Expect(validator.ValidateCreate(context.TODO(), namespaced)).To(Succeed())
+
+			namespaced.Namespace = "prod"
+			Expect(validator.ValidateCreate(context.TODO(), namespaced)).To(
+				MatchError(ContainSubstring("kind ConfigMap may not be stamped in namespace 'prod'")),
+			)
+		})
+	})
+
+	It("does not stamp templates being deleted", func() {
+		template.Spec.Sample.Context = nil
+
+		Expect(validator.ValidateDelete(context.TODO(), template)).To(Succeed())
+	})
+
+	Describe("deleting templates", func() {
+		supplyChain := func(kind, templateName string) v1alpha1.SupplyChainSpec {
+			return v1alpha1.SupplyChainSpec{
+				Components: []v1alpha1.SupplyChainComponent{{
+					Name:        "config",
+					TemplateRef: v1alpha1.ClusterTemplateReference{Kind: kind, Name: templateName},
+				}},
+			}
+		}
+
+		It("rejects deleting a template that supply chains refer to, listing them", func() {
+			repository.ListSupplyChainsReturns([]v1alpha1.ClusterSupplyChain{
+				{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Spec: supplyChain("ClusterConfigTemplate", "app-config")},
+				{ObjectMeta: metav1.ObjectMeta{Name: "other"}, Spec: supplyChain("ClusterConfigTemplate", "other-config")},
+				{ObjectMeta: metav1.ObjectMeta{Name: "image"}, Spec: supplyChain("ClusterImageTemplate", "app-config")},
+			}, nil)
+			repository.ListNamespacedSupplyChainsReturns([]v1alpha1.SupplyChain{
+				{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "dev"}, Spec: supplyChain("ClusterConfigTemplate", "app-config")},
+			}, nil)
+
+			Expect(validator.ValidateDelete(context.TODO(), template)).To(
+				MatchError("template is still referenced by ClusterSupplyChain 'web', SupplyChain 'dev/api'"),
+			)
+		})
+
+		It("admits deleting a template whose referrers are being deleted", func() {
+			now := metav1.Now()
+			repository.ListSupplyChainsReturns([]v1alpha1.ClusterSupplyChain{
+				{ObjectMeta: metav1.ObjectMeta{Name: "web", DeletionTimestamp: &now}, Spec: supplyChain("ClusterConfigTemplate", "app-config")},
+			}, nil)
+
+			Expect(validator.ValidateDelete(context.TODO(), template)).To(Succeed())
+		})
+
+		It("only counts the supply chains of the namespace of a namespaced template", func() {
+			repository.ListNamespacedSupplyChainsReturns([]v1alpha1.SupplyChain{
+				{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "dev"}, Spec: supplyChain("ClusterConfigTemplate", "app-config")},
+				{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod"}, Spec: supplyChain("ClusterConfigTemplate", "app-config")},
+			}, nil)
+
+			Expect(validator.ValidateDelete(context.TODO(), &v1alpha1.ConfigTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "prod"},
+			})).To(MatchError("template is still referenced by SupplyChain 'prod/api'"))
+			Expect(repository.ListSupplyChainsCallCount()).To(Equal(0))
+		})
+
+		It("fails when the referrers cannot be listed", func() {
+			repository.ListSupplyChainsReturns(nil, errors.New("some list error"))
+
+			Expect(validator.ValidateDelete(context.TODO(), template)).To(
+				MatchError("list referrers: some list error"),
+			)
+		})
+
+		Context("a RunTemplate", func() {
+			var runTemplate *v1alpha1.RunTemplate
+
+			BeforeEach(func() {
+				runTemplate = &v1alpha1.RunTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: "tekton", Namespace: "dev", Labels: map[string]string{"app": "tekton"}},
+				}
+			})
+
+			It("rejects deleting a RunTemplate that pipelines or hooks refer to", func() {
+				repository.ListPipelinesReturns([]v1alpha1.Pipeline{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "tests", Namespace: "dev"},
+						Spec:       v1alpha1.PipelineSpec{RunTemplateRef: v1alpha1.TemplateReference{Kind: "RunTemplate", Name: "tekton"}},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "tests", Namespace: "prod"},
+						Spec:       v1alpha1.PipelineSpec{RunTemplateRef: v1alpha1.TemplateReference{Name: "tekton"}},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "lint", Namespace: "ci"},
+						Spec:       v1alpha1.PipelineSpec{RunTemplateRef: v1alpha1.TemplateReference{Name: "tekton", Namespace: "dev"}},
+					},
+				}, nil)
+				repository.ListNamespacedSupplyChainsReturns([]v1alpha1.SupplyChain{{
+					ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "dev"},
+					Spec: v1alpha1.SupplyChainSpec{
+						Components: []v1alpha1.SupplyChainComponent{{
+							Name: "deploy",
+							Hooks: &v1alpha1.ComponentHooks{
+								Post: []v1alpha1.ComponentHook{{Name: "notify", RunTemplateRef: v1alpha1.TemplateReference{Name: "tekton"}}},
+							},
+						}},
+					},
+				}}, nil)
+
+				Expect(validator.ValidateDelete(context.TODO(), runTemplate)).To(
+					MatchError("template is still referenced by Pipeline 'ci/lint', Pipeline 'dev/tests', SupplyChain 'dev/api'"),
+				)
+			})
+
+			It("only counts the pipelines whose selector selects the RunTemplate", func() {
+				selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "tekton"}}
+				repository.ListPipelinesReturns([]v1alpha1.Pipeline{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "tests", Namespace: "dev"},
+						Spec:       v1alpha1.PipelineSpec{RunTemplateRef: v1alpha1.TemplateReference{Selector: selector}},
+					},
+				}, nil)
+				repository.GetRunTemplateReturns(templates.NewRunTemplateModel(&v1alpha1.RunTemplate{
+					ObjectMeta: metav1.ObjectMeta{Name: "tekton-v2", Namespace: "dev"},
+				}), nil)
+
+				Expect(validator.ValidateDelete(context.TODO(), runTemplate)).To(Succeed())
+
+				repository.GetRunTemplateReturns(templates.NewRunTemplateModel(runTemplate), nil)
+
+				Expect(validator.ValidateDelete(context.TODO(), runTemplate)).To(
+					MatchError("template is still referenced by Pipeline 'dev/tests'"),
+				)
+				Expect(repository.GetRunTemplateArgsForCall(0).Namespace).To(Equal("dev"))
+			})
+		})
+	})
+})
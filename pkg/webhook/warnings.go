@@ -0,0 +1,71 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type warningsKey struct{}
+
+type warnings struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// Warn adds a warning to the response of the admission request being
+// validated with ctx. It is dropped when the webhook was not wrapped with
+// WithWarnings, or when the request is rejected.
+func Warn(ctx context.Context, message string) {
+	w, ok := ctx.Value(warningsKey{}).(*warnings)
+	if !ok {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, message)
+}
+
+// WithWarnings returns the webhook, with the warnings its validator adds
+// with Warn returned to the client when the request is admitted.
+func WithWarnings(wh *admission.Webhook) *admission.Webhook {
+	wh.Handler = &warningHandler{Handler: wh.Handler}
+	return wh
+}
+
+type warningHandler struct {
+	admission.Handler
+}
+
+func (h *warningHandler) InjectDecoder(d *admission.Decoder) error {
+	_, err := admission.InjectDecoderInto(d, h.Handler)
+	return err
+}
+
+func (h *warningHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	w := &warnings{}
+	response := h.Handler.Handle(context.WithValue(ctx, warningsKey{}, w), req)
+	if !response.Allowed {
+		return response
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return response.WithWarnings(w.messages...)
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+)
+
+// WorkloadDefaulter fills in what a workload omits: the labels and the
+// service account name of the Cartographer installation, then the param
+// defaults declared by the supply chain it would go through, chosen with
+// those labels.
+type WorkloadDefaulter struct {
+	Repository repository.Repository
+	// ServiceAccountName, when set, is the service account name of the
+	// workloads that do not set one.
+	ServiceAccountName string
+	// Labels are added to the workloads that do not set them.
+	Labels map[string]string
+}
+
+func (d *WorkloadDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	workload, ok := obj.(*v1alpha1.Workload)
+	if !ok {
+		return fmt.Errorf("expected a workload but got a %T", obj)
+	}
+
+	// A workload being deleted is only updated to remove its finalizers.
+	if workload.DeletionTimestamp != nil {
+		return nil
+	}
+
+	for key, value := range d.Labels {
+		if _, ok := workload.Labels[key]; ok {
+			continue
+		}
+		if workload.Labels == nil {
+			workload.Labels = map[string]string{}
+		}
+		workload.Labels[key] = value
+	}
+
+	if workload.Spec.ServiceAccountName == "" {
+		workload.Spec.ServiceAccountName = d.ServiceAccountName
+	}
+
+	supplyChain, err := resolvedSupplyChainFor(d.Repository, workload)
+	if err != nil {
+		return err
+	}
+	if supplyChain != nil {
+		workload.Spec.Params = supplyChain.Spec.DefaultWorkloadParams(workload.Spec.Params)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/webhook"
+)
+
+var _ = Describe("WorkloadDefaulter", func() {
+	var (
+		repository *repositoryfakes.FakeRepository
+		defaulter  *webhook.WorkloadDefaulter
+		workload   *v1alpha1.Workload
+	)
+
+	BeforeEach(func() {
+		repository = &repositoryfakes.FakeRepository{}
+		defaulter = &webhook.WorkloadDefaulter{
+			Repository:         repository,
+			ServiceAccountName: "workload-runner",
+			Labels:             map[string]string{"app.tanzu.vmware.com/workload-type": "web", "team": "platform"},
+		}
+
+		repository.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{{
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec: v1alpha1.SupplyChainSpec{
+				WorkloadParams: []v1alpha1.WorkloadParamDeclaration{
+					{Name: "port", Type: "integer", Default: &apiextensionsv1.JSON{Raw: []byte(`8080`)}},
+					{Name: "debug", Type: "boolean", Default: &apiextensionsv1.JSON{Raw: []byte(`false`)}},
+				},
+			},
+		}}, nil)
+
+		workload = &v1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Labels: map[string]string{"team": "payments"}},
+			Spec: v1alpha1.WorkloadSpec{
+				Params: []v1alpha1.WorkloadParam{
+					{Name: "debug", Value: apiextensionsv1.JSON{Raw: []byte(`true`)}},
+				},
+			},
+		}
+	})
+
+	It("adds the labels the workload does not set", func() {
+		Expect(defaulter.Default(context.TODO(), workload)).To(Succeed())
+		Expect(workload.Labels).To(Equal(map[string]string{"app.tanzu.vmware.com/workload-type": "web", "team": "payments"}))
+	})
+
+	It("chooses the supply chain with the defaulted labels", func() {
+		Expect(defaulter.Default(context.TODO(), workload)).To(Succeed())
+		Expect(repository.GetSupplyChainsForWorkloadArgsForCall(0).Labels).To(HaveKeyWithValue("app.tanzu.vmware.com/workload-type", "web"))
+	})
+
+	It("sets the service account name when the workload does not set one", func() {
+		Expect(defaulter.Default(context.TODO(), workload)).To(Succeed())
+		Expect(workload.Spec.ServiceAccountName).To(Equal("workload-runner"))
+
+		workload.Spec.ServiceAccountName = "my-runner"
+		Expect(defaulter.Default(context.TODO(), workload)).To(Succeed())
+		Expect(workload.Spec.ServiceAccountName).To(Equal("my-runner"))
+	})
+
+	It("adds the param defaults of the supply chain", func() {
+		Expect(defaulter.Default(context.TODO(), workload)).To(Succeed())
+		Expect(workload.Spec.Params).To(Equal([]v1alpha1.WorkloadParam{
+			{Name: "debug", Value: apiextensionsv1.JSON{Raw: []byte(`true`)}},
+			{Name: "port", Value: apiextensionsv1.JSON{Raw: []byte(`8080`)}},
+		}))
+	})
+
+	It("adds no params when no supply chain would realize the workload", func() {
+		repository.GetSupplyChainsForWorkloadReturns(nil, nil)
+
+		Expect(defaulter.Default(context.TODO(), workload)).To(Succeed())
+		Expect(workload.Spec.Params).To(HaveLen(1))
+	})
+
+	It("does not default workloads being deleted", func() {
+		workload.DeletionTimestamp = &metav1.Time{}
+
+		Expect(defaulter.Default(context.TODO(), workload)).To(Succeed())
+		Expect(workload.Labels).To(HaveLen(1))
+		Expect(repository.GetSupplyChainsForWorkloadCallCount()).To(Equal(0))
+	})
+
+	It("returns an error when the supply chains cannot be listed", func() {
+		repository.GetSupplyChainsForWorkloadReturns(nil, errors.New("some error"))
+
+		Expect(defaulter.Default(context.TODO(), workload)).To(MatchError("get supply chains: some error"))
+	})
+})
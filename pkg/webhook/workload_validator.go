@@ -0,0 +1,98 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository"
+	"github.com/vmware-tanzu/cartographer/pkg/selector"
+)
+
+// WorkloadValidator rejects workloads whose params do not satisfy those
+// declared by the supply chain they would go through. A workload that no
+// single supply chain would realize is admitted, and reported on by the
+// reconciler instead.
+type WorkloadValidator struct {
+	Repository repository.Repository
+}
+
+func (v *WorkloadValidator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	return logValidation("create", obj, v.validate(obj))
+}
+
+func (v *WorkloadValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) error {
+	return logValidation("update", newObj, v.validate(newObj))
+}
+
+func (v *WorkloadValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *WorkloadValidator) validate(obj runtime.Object) error {
+	workload, ok := obj.(*v1alpha1.Workload)
+	if !ok {
+		return fmt.Errorf("expected a workload but got a %T", obj)
+	}
+
+	// A workload being deleted is only updated to remove its finalizers.
+	if workload.DeletionTimestamp != nil {
+		return nil
+	}
+
+	supplyChain, err := resolvedSupplyChainFor(v.Repository, workload)
+	if err != nil {
+		return err
+	}
+	if supplyChain == nil {
+		return nil
+	}
+
+	if err := supplyChain.Spec.ValidateWorkloadParams(workload.Spec.Params); err != nil {
+		return fmt.Errorf("invalid params for supply chain '%s': %w", supplyChain.Name, err)
+	}
+
+	return nil
+}
+
+// resolvedSupplyChainFor returns the supply chain the workload would go
+// through, resolved, or nil when no single supply chain would realize it.
+func resolvedSupplyChainFor(repo repository.Repository, workload *v1alpha1.Workload) (*v1alpha1.ClusterSupplyChain, error) {
+	supplyChains, err := repo.GetSupplyChainsForWorkload(workload)
+	if err != nil {
+		return nil, fmt.Errorf("get supply chains: %w", err)
+	}
+	if len(supplyChains) == 0 {
+		supplyChains, err = repo.GetDefaultSupplyChainsForWorkload(workload)
+		if err != nil {
+			return nil, fmt.Errorf("get default supply chains: %w", err)
+		}
+	}
+
+	supplyChain, _ := selector.ChooseSupplyChain(supplyChains)
+	if supplyChain == nil {
+		return nil, nil
+	}
+
+	resolved, err := supplyChain.Resolve(repo.GetSupplyChain)
+	if err != nil {
+		return nil, nil
+	}
+	return resolved, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 VMware
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/cartographer/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/cartographer/pkg/repository/repositoryfakes"
+	"github.com/vmware-tanzu/cartographer/pkg/webhook"
+)
+
+var _ = Describe("WorkloadValidator", func() {
+	var (
+		repository  *repositoryfakes.FakeRepository
+		validator   *webhook.WorkloadValidator
+		supplyChain v1alpha1.ClusterSupplyChain
+		workload    *v1alpha1.Workload
+	)
+
+	BeforeEach(func() {
+		repository = &repositoryfakes.FakeRepository{}
+		validator = &webhook.WorkloadValidator{Repository: repository}
+
+		supplyChain = v1alpha1.ClusterSupplyChain{
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec: v1alpha1.SupplyChainSpec{
+				WorkloadParams: []v1alpha1.WorkloadParamDeclaration{
+					{Name: "port", Type: "integer", Required: true},
+				},
+			},
+		}
+		repository.GetSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+
+		workload = &v1alpha1.Workload{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Labels: map[string]string{"app": "web"}},
+			Spec: v1alpha1.WorkloadSpec{
+				Params: []v1alpha1.WorkloadParam{
+					{Name: "port", Value: apiextensionsv1.JSON{Raw: []byte(`8080`)}},
+				},
+			},
+		}
+	})
+
+	It("admits params that satisfy the supply chain", func() {
+		Expect(validator.ValidateCreate(context.TODO(), workload)).To(Succeed())
+		Expect(repository.GetSupplyChainsForWorkloadArgsForCall(0)).To(Equal(workload))
+	})
+
+	It("rejects params that do not satisfy the supply chain, naming each problem", func() {
+		workload.Spec.Params = []v1alpha1.WorkloadParam{
+			{Name: "prot", Value: apiextensionsv1.JSON{Raw: []byte(`8080`)}},
+		}
+
+		Expect(validator.ValidateUpdate(context.TODO(), nil, workload)).To(MatchError(
+			"invalid params for supply chain 'web': param 'port' is required, param 'prot' is not declared by the supply chain",
+		))
+	})
+
+	It("validates against the default supply chain when no supply chain selects the workload", func() {
+		repository.GetSupplyChainsForWorkloadReturns(nil, nil)
+		supplyChain.Spec.Default = true
+		repository.GetDefaultSupplyChainsForWorkloadReturns([]v1alpha1.ClusterSupplyChain{supplyChain}, nil)
+		workload.Spec.Params = nil
+
+		Expect(validator.ValidateCreate(context.TODO(), workload)).To(
+			MatchError("invalid params for supply chain 'web': param 'port' is required"),
+		)
+	})
+
+	It("admits workloads that no supply chain would realize", func() {
+		repository.GetSupplyChainsForWorkloadReturns(nil, nil)
+		workload.Spec.Params = nil
+
+		Expect(validator.ValidateCreate(context.TODO(), workload)).To(Succeed())
+	})
+
+	It("admits workloads being deleted", func() {
+		now := metav1.Now()
+		workload.DeletionTimestamp = &now
+		workload.Spec.Params = nil
+
+		Expect(validator.ValidateUpdate(context.TODO(), nil, workload)).To(Succeed())
+		Expect(repository.GetSupplyChainsForWorkloadCallCount()).To(Equal(0))
+	})
+
+	It("returns an error when the supply chains cannot be listed", func() {
+		repository.GetSupplyChainsForWorkloadReturns(nil, errors.New("some error"))
+
+		Expect(validator.ValidateCreate(context.TODO(), workload)).To(
+			MatchError("get supply chains: some error"),
+		)
+	})
+
+	It("does not validate deletes", func() {
+		Expect(validator.ValidateDelete(context.TODO(), workload)).To(Succeed())
+		Expect(repository.GetSupplyChainsForWorkloadCallCount()).To(Equal(0))
+	})
+})